@@ -3,6 +3,10 @@ package agents
 import (
 	"context"
 
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
 )
 
@@ -14,3 +18,65 @@ type Agent interface {
 	GetInputKeys() []string
 	GetOutputKeys() []string
 }
+
+// PromptSizer is implemented by agents able to measure the prompt of their next
+// plan, so that the executor can prune it to fit the context window of the llm.
+type PromptSizer interface {
+	// PromptTokens returns the number of tokens of the prompt planning with
+	// the steps and inputs.
+	PromptTokens(intermediateSteps []schema.AgentStep, inputs map[string]string) (int, error)
+	// ContextSize returns the size of the context window of the llm, or 0 if
+	// unknown.
+	ContextSize() int
+}
+
+// newLLMChain creates the llm chain of an agent, reporting to the callbacks
+// handler if any.
+func newLLMChain(llm llms.LanguageModel, prompt prompts.PromptTemplate, handler callbacks.Handler) *chains.LLMChain {
+	chain := chains.NewLLMChain(llm, prompt)
+	chain.CallbacksHandler = handler
+	return chain
+}
+
+// planOptions returns the options of the chain calls made to plan. With a
+// callbacks handler streaming is enabled, the llm chain passing the streamed
+// chunks to the handler.
+func planOptions(handler callbacks.Handler) []chains.ChainCallOption {
+	options := []chains.ChainCallOption{
+		chains.WithStopWords([]string{"\nObservation:", "\n\tObservation:"}),
+	}
+	if handler != nil {
+		options = append(options, chains.WithStreamingFunc(func(context.Context, []byte) error {
+			return nil
+		}))
+	}
+	return options
+}
+
+// chainPromptTokens returns the number of tokens of the prompt of the chain
+// formatted with the inputs. Only llm chains are supported, 0 is returned for
+// other chains.
+func chainPromptTokens(chain chains.Chain, inputs map[string]any) (int, error) {
+	llmChain, ok := chain.(*chains.LLMChain)
+	if !ok {
+		return 0, nil
+	}
+	prompt, err := llmChain.Prompt.FormatPrompt(inputs)
+	if err != nil {
+		return 0, err
+	}
+	return llmChain.LLM.GetNumTokens(prompt.String()), nil
+}
+
+// chainContextSize returns the size of the context window of the llm of the
+// chain, or 0 if unknown.
+func chainContextSize(chain chains.Chain) int {
+	llmChain, ok := chain.(*chains.LLMChain)
+	if !ok {
+		return 0
+	}
+	if sizer, ok := llmChain.LLM.(llms.ContextSizer); ok {
+		return sizer.GetContextSize()
+	}
+	return 0
+}
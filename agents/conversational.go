@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/schema"
@@ -31,9 +32,14 @@ type ConversationalAgent struct {
 	Tools []tools.Tool
 	// Output key is the key where the final output is placed.
 	OutputKey string
+	// CallbacksHandler is given the chunks streamed by the llm while planning.
+	CallbacksHandler callbacks.Handler
 }
 
-var _ Agent = (*ConversationalAgent)(nil)
+var (
+	_ Agent       = (*ConversationalAgent)(nil)
+	_ PromptSizer = (*ConversationalAgent)(nil)
+)
 
 func NewConversationalAgent(llm llms.LanguageModel, tools []tools.Tool, opts ...CreationOption) *ConversationalAgent {
 	options := conversationalDefaultOptions()
@@ -42,9 +48,10 @@ func NewConversationalAgent(llm llms.LanguageModel, tools []tools.Tool, opts ...
 	}
 
 	return &ConversationalAgent{
-		Chain:     chains.NewLLMChain(llm, options.getConversationalPrompt(tools)),
-		Tools:     tools,
-		OutputKey: options.outputKey,
+		Chain:            newLLMChain(llm, options.getConversationalPrompt(tools), options.callbacksHandler),
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
 	}
 }
 
@@ -54,18 +61,11 @@ func (a *ConversationalAgent) Plan(
 	intermediateSteps []schema.AgentStep,
 	inputs map[string]string,
 ) ([]schema.AgentAction, *schema.AgentFinish, error) {
-	fullInputs := make(map[string]any, len(inputs))
-	for key, value := range inputs {
-		fullInputs[key] = value
-	}
-
-	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
-
 	output, err := chains.Predict(
 		ctx,
 		a.Chain,
-		fullInputs,
-		chains.WithStopWords([]string{"\nObservation:", "\n\tObservation:"}),
+		a.planInputs(intermediateSteps, inputs),
+		planOptions(a.CallbacksHandler)...,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -74,6 +74,29 @@ func (a *ConversationalAgent) Plan(
 	return a.parseOutput(output)
 }
 
+// PromptTokens returns the number of tokens of the prompt planning with the
+// steps and inputs.
+func (a *ConversationalAgent) PromptTokens(intermediateSteps []schema.AgentStep, inputs map[string]string) (int, error) { //nolint:lll
+	return chainPromptTokens(a.Chain, a.planInputs(intermediateSteps, inputs))
+}
+
+// ContextSize returns the size of the context window of the llm, or 0 if
+// unknown.
+func (a *ConversationalAgent) ContextSize() int {
+	return chainContextSize(a.Chain)
+}
+
+// planInputs returns the inputs of the chain for the steps and inputs.
+func (a *ConversationalAgent) planInputs(intermediateSteps []schema.AgentStep, inputs map[string]string) map[string]any { //nolint:lll
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+
+	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
+	return fullInputs
+}
+
 func (a *ConversationalAgent) GetInputKeys() []string {
 	chainInputs := a.Chain.GetInputKeys()
 
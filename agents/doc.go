@@ -19,4 +19,11 @@
 // calling the tool that the action references with the corresponding input,
 // getting the output of the tool, and then passing all that information back
 // into the Agent to get the next action it should take.
+//
+// For tasks needing many steps, PlanAndExecute first asks the model for a plan,
+// runs each step of the plan with an Executor and composes the final answer
+// from the results of the steps.
+//
+// VoiceAgent wraps an agent for voice bots: it transcribes the audio it is given,
+// runs the agent with the transcript and synthesizes speech for the answer.
 package agents
@@ -10,6 +10,9 @@ var (
 	// ErrNotFinished is returned if the agent does not give a finish before  the number of iterations
 	// is larger then max iterations.
 	ErrNotFinished = errors.New("agent not finished before max iterations")
+	// ErrBudgetExceeded is returned if the llm calls of the agent use more tokens or cost more than
+	// the budget set with WithBudget.
+	ErrBudgetExceeded = errors.New("agent budget exceeded")
 	// ErrUnknownAgentType is returned if the type given to the initializer is invalid.
 	ErrUnknownAgentType = errors.New("unknown agent type")
 	// ErrInvalidOptions is returned if the options given to the initializer is invalid.
@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/safety"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/tools"
 )
 
-const _intermediateStepsOutputKey = "intermediateSteps"
+// IntermediateStepsOutputKey is the output key of the intermediate steps
+// returned with WithReturnIntermediateSteps.
+const IntermediateStepsOutputKey = "intermediateSteps"
 
 // Executor is the chain responsible for running agents.
 type Executor struct {
@@ -20,9 +25,32 @@ type Executor struct {
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+	CallbacksHandler        callbacks.Handler
+
+	// PruneContext makes the executor prune the prompt of each plan to fit the
+	// context window of the llm, see WithContextPruning.
+	PruneContext bool
+	// ResponseReserve is the number of tokens kept free for the response when
+	// pruning.
+	ResponseReserve int
+	// SafetyCheckers check the final answer before it is returned, see
+	// WithSafetyCheckers.
+	SafetyCheckers []safety.Checker
+	// ApprovalFunc is consulted before each tool call, see WithApprovalFunc.
+	ApprovalFunc ApprovalFunc
+	// MaxTotalTokens and MaxCostUSD are the budget of a run, see WithBudget.
+	MaxTotalTokens int
+	MaxCostUSD     float64
 }
 
-var _ chains.Chain = Executor{}
+// ApprovalFunc decides whether the tool call of the action can be made. An
+// error stops the executor.
+type ApprovalFunc func(ctx context.Context, action schema.AgentAction) (bool, error)
+
+var (
+	_ chains.Chain           = Executor{}
+	_ callbacks.HandlerHaver = Executor{}
+)
 
 // NewExecutor creates a new agent executor with a agent and the tools the agent can use.
 func NewExecutor(agent Agent, tools []tools.Tool, opts ...CreationOption) Executor {
@@ -37,6 +65,13 @@ func NewExecutor(agent Agent, tools []tools.Tool, opts ...CreationOption) Execut
 		Memory:                  options.memory,
 		MaxIterations:           options.maxIterations,
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
+		CallbacksHandler:        options.callbacksHandler,
+		PruneContext:            options.pruneContext,
+		ResponseReserve:         options.responseReserve,
+		SafetyCheckers:          options.safetyCheckers,
+		ApprovalFunc:            options.approvalFunc,
+		MaxTotalTokens:          options.maxTotalTokens,
+		MaxCostUSD:              options.maxCostUSD,
 	}
 }
 
@@ -46,37 +81,51 @@ func (e Executor) Call(ctx context.Context, inputValues map[string]any, _ ...cha
 		return nil, err
 	}
 	nameToTool := getNameToTool(e.Tools)
+	var usage *llms.UsageTracker
+	if e.MaxTotalTokens > 0 || e.MaxCostUSD > 0 {
+		usage = &llms.UsageTracker{}
+		ctx = llms.WithUsageTracker(ctx, usage)
+	}
 
 	steps := make([]schema.AgentStep, 0)
 	for i := 0; i < e.MaxIterations; i++ {
-		actions, finish, err := e.Agent.Plan(ctx, steps, inputs)
+		planSteps, planInputs, err := e.prune(steps, inputs)
+		if err != nil {
+			return nil, err
+		}
+		actions, finish, err := e.Agent.Plan(ctx, planSteps, planInputs)
 		if err != nil {
 			return nil, err
 		}
+		if err := e.checkBudget(usage); err != nil {
+			return nil, err
+		}
 
 		if len(actions) == 0 && finish == nil {
 			return nil, ErrAgentNoReturn
 		}
 
 		if finish != nil {
+			if err := e.checkSafety(ctx, finish, steps); err != nil {
+				return nil, err
+			}
+			if e.CallbacksHandler != nil {
+				e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
+			}
 			return e.getReturn(finish, steps), nil
 		}
 
 		for _, action := range actions {
-			tool, ok := nameToTool[strings.ToUpper(action.Tool)]
-			if !ok {
-				steps = append(steps, schema.AgentStep{
-					Action:      action,
-					Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
-				})
-				continue
+			if e.CallbacksHandler != nil {
+				e.CallbacksHandler.HandleAgentAction(ctx, action)
 			}
-
-			observation, err := tool.Call(ctx, action.ToolInput)
+			observation, err := e.doAction(ctx, nameToTool, action)
 			if err != nil {
 				return nil, err
 			}
-
+			if err := e.checkBudget(usage); err != nil {
+				return nil, err
+			}
 			steps = append(steps, schema.AgentStep{
 				Action:      action,
 				Observation: observation,
@@ -89,12 +138,186 @@ func (e Executor) Call(ctx context.Context, inputValues map[string]any, _ ...cha
 
 func (e Executor) getReturn(finish *schema.AgentFinish, steps []schema.AgentStep) map[string]any {
 	if e.ReturnIntermediateSteps {
-		finish.ReturnValues[_intermediateStepsOutputKey] = steps
+		finish.ReturnValues[IntermediateStepsOutputKey] = steps
 	}
 
 	return finish.ReturnValues
 }
 
+// checkBudget returns ErrBudgetExceeded if the usage tracked during the run
+// is over the budget of the executor.
+func (e Executor) checkBudget(usage *llms.UsageTracker) error {
+	if usage == nil {
+		return nil
+	}
+	u := usage.Usage()
+	if (e.MaxTotalTokens > 0 && u.TotalTokens > e.MaxTotalTokens) || (e.MaxCostUSD > 0 && u.CostUSD > e.MaxCostUSD) {
+		return fmt.Errorf("%w: used %d tokens and $%.4f, the budget is %d tokens and $%.4f",
+			ErrBudgetExceeded, u.TotalTokens, u.CostUSD, e.MaxTotalTokens, e.MaxCostUSD)
+	}
+	return nil
+}
+
+// checkSafety checks the string return values of the finish with the safety
+// checkers, giving them the observations of the steps.
+func (e Executor) checkSafety(ctx context.Context, finish *schema.AgentFinish, steps []schema.AgentStep) error {
+	if len(e.SafetyCheckers) == 0 {
+		return nil
+	}
+	observations := make([]string, 0, len(steps))
+	for _, step := range steps {
+		observations = append(observations, step.Observation)
+	}
+
+	var annotations []string
+	for key, value := range finish.ReturnValues {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		result, err := safety.Check(ctx, safety.Input{Text: text, Observations: observations}, e.SafetyCheckers...)
+		if err != nil {
+			return err
+		}
+		finish.ReturnValues[key] = result.Text
+		annotations = append(annotations, result.Annotations...)
+	}
+	if len(annotations) > 0 {
+		finish.ReturnValues[chains.SafetyAnnotationsKey] = annotations
+	}
+	return nil
+}
+
+// prune returns the steps and inputs to plan with, dropping the oldest steps
+// and then the oldest lines of the memory until the prompt fits the context
+// window with the response reserve. The latest step is always kept.
+func (e Executor) prune(steps []schema.AgentStep, inputs map[string]string) ([]schema.AgentStep, map[string]string, error) { //nolint:lll
+	sizer, ok := e.Agent.(PromptSizer)
+	if !e.PruneContext || !ok || sizer.ContextSize() == 0 {
+		return steps, inputs, nil
+	}
+	contextSize := sizer.ContextSize()
+
+	var memoryKeys []string
+	if e.Memory != nil {
+		memoryKeys = e.Memory.MemoryVariables()
+	}
+	pruned := make(map[string]string, len(inputs))
+	for key, value := range inputs {
+		pruned[key] = value
+	}
+
+	for {
+		tokens, err := sizer.PromptTokens(steps, pruned)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tokens+e.ResponseReserve <= contextSize {
+			return steps, pruned, nil
+		}
+		if len(steps) > 1 {
+			steps = steps[1:]
+			continue
+		}
+		if !dropOldestLine(pruned, memoryKeys) {
+			return nil, nil, &llms.ContextTooLongError{
+				ContextSize:  contextSize,
+				PromptTokens: tokens,
+				MaxTokens:    e.ResponseReserve,
+			}
+		}
+	}
+}
+
+// dropOldestLine removes the first line of the first non-empty memory input,
+// reporting whether a line was removed.
+func dropOldestLine(inputs map[string]string, memoryKeys []string) bool {
+	for _, key := range memoryKeys {
+		value := inputs[key]
+		if value == "" {
+			continue
+		}
+		if i := strings.IndexByte(value, '\n'); i >= 0 {
+			inputs[key] = value[i+1:]
+		} else {
+			inputs[key] = ""
+		}
+		return true
+	}
+	return false
+}
+
+// doAction calls the tool of the action and returns the observation. Unknown
+// tools, invalid inputs and calls not approved are reported in the
+// observation to let the agent try something else.
+func (e Executor) doAction(ctx context.Context, nameToTool map[string]tools.Tool, action schema.AgentAction) (string, error) { //nolint:lll
+	tool, ok := nameToTool[strings.ToUpper(action.Tool)]
+	if !ok {
+		return fmt.Sprintf("%s is not a valid tool, try another one", action.Tool), nil
+	}
+	if st, ok := tool.(tools.StructuredTool); ok {
+		if _, err := tools.ValidateInput(st, action.ToolInput); err != nil {
+			return fmt.Sprintf("%s, fix the input and try again", err), nil
+		}
+	}
+	if e.ApprovalFunc != nil {
+		approved, err := e.ApprovalFunc(ctx, action)
+		if err != nil {
+			return "", err
+		}
+		if !approved {
+			return fmt.Sprintf("the call to %s was not approved, try something else", action.Tool), nil
+		}
+	}
+	return e.callTool(ctx, tool, action.ToolInput)
+}
+
+// callTool calls the tool, reporting the call to the callbacks handler.
+func (e Executor) callTool(ctx context.Context, tool tools.Tool, input string) (string, error) {
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleToolStart(ctx, tool.Name(), input)
+	}
+	observation, err := callInterruptible(ctx, tool, input)
+	if e.CallbacksHandler != nil {
+		if err != nil {
+			e.CallbacksHandler.HandleToolError(ctx, tool.Name(), err)
+		} else {
+			e.CallbacksHandler.HandleToolEnd(ctx, tool.Name(), observation)
+		}
+	}
+	return observation, err
+}
+
+// callInterruptible calls the tool, interrupting it if it implements
+// tools.Interrupter and the context is canceled during the call.
+func callInterruptible(ctx context.Context, tool tools.Tool, input string) (string, error) {
+	interrupter, ok := tool.(tools.Interrupter)
+	if !ok {
+		return tool.Call(ctx, input)
+	}
+
+	done := make(chan struct{})
+	interrupted := make(chan struct{})
+	go func() {
+		defer close(interrupted)
+		select {
+		case <-ctx.Done():
+			// The context is canceled, the interruption must not be.
+			_ = interrupter.Interrupt(context.Background())
+		case <-done:
+		}
+	}()
+	observation, err := tool.Call(ctx, input)
+	close(done)
+	<-interrupted
+	return observation, err
+}
+
+// GetCallbackHandler returns the callbacks handler of the executor.
+func (e Executor) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return e.CallbacksHandler
+}
+
 // GetInputKeys gets the input keys the agent of the executor expects.
 // Often "input".
 func (e Executor) GetInputKeys() []string {
@@ -103,7 +326,11 @@ func (e Executor) GetInputKeys() []string {
 
 // GetOutputKeys gets the output keys the agent of the executor returns.
 func (e Executor) GetOutputKeys() []string {
-	return e.Agent.GetOutputKeys()
+	outputKeys := e.Agent.GetOutputKeys()
+	if e.ReturnIntermediateSteps {
+		outputKeys = append(outputKeys, IntermediateStepsOutputKey)
+	}
+	return outputKeys
 }
 
 func (e Executor) GetMemory() schema.Memory { //nolint:ireturn
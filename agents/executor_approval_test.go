@@ -0,0 +1,43 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestExecutorApprovalFunc(t *testing.T) {
+	t.Parallel()
+
+	var approvals []schema.AgentAction
+	deny := func(_ context.Context, action schema.AgentAction) (bool, error) {
+		approvals = append(approvals, action)
+		return false, nil
+	}
+	executor := agents.NewExecutor(oneActionAgent{}, []tools.Tool{staticJobTool{output: "started"}},
+		agents.WithApprovalFunc(deny))
+	outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.NoError(t, err)
+	require.Equal(t, "the call to job was not approved, try something else", outputs["output"])
+	require.Equal(t, []schema.AgentAction{{Tool: "job", ToolInput: "start"}}, approvals)
+
+	approve := func(context.Context, schema.AgentAction) (bool, error) { return true, nil }
+	executor = agents.NewExecutor(oneActionAgent{}, []tools.Tool{staticJobTool{output: "started"}},
+		agents.WithApprovalFunc(approve))
+	outputs, err = chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.NoError(t, err)
+	require.Equal(t, "started", outputs["output"])
+
+	errPolicy := errors.New("policy unavailable")
+	fail := func(context.Context, schema.AgentAction) (bool, error) { return false, errPolicy }
+	executor = agents.NewExecutor(oneActionAgent{}, []tools.Tool{staticJobTool{output: "started"}},
+		agents.WithApprovalFunc(fail))
+	_, err = chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.ErrorIs(t, err, errPolicy)
+}
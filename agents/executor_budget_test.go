@@ -0,0 +1,48 @@
+package agents_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// loopingLLM always calls the "job" tool and reports 40 tokens of usage for
+// each call.
+type loopingLLM struct{}
+
+func (loopingLLM) GeneratePrompt(context.Context, []schema.PromptValue, ...llms.CallOption) (llms.LLMResult, error) {
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{
+		Text:           "Action: job\nAction Input: start",
+		GenerationInfo: map[string]any{"PromptTokens": 30, "CompletionTokens": 10, "Model": "gpt-4"},
+	}}}}, nil
+}
+
+func (loopingLLM) GetNumTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestExecutorBudget(t *testing.T) {
+	t.Parallel()
+
+	jobs := []tools.Tool{staticJobTool{output: "started"}}
+	executor := agents.NewExecutor(agents.NewOneShotAgent(loopingLLM{}, jobs), jobs, agents.WithBudget(100, 0))
+	_, err := chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.ErrorIs(t, err, agents.ErrBudgetExceeded)
+	require.ErrorContains(t, err, "used 120 tokens")
+
+	executor = agents.NewExecutor(agents.NewOneShotAgent(loopingLLM{}, jobs), jobs, agents.WithBudget(0, 0.002))
+	_, err = chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.ErrorIs(t, err, agents.ErrBudgetExceeded)
+	require.ErrorContains(t, err, "used 80 tokens")
+
+	executor = agents.NewExecutor(agents.NewOneShotAgent(loopingLLM{}, jobs), jobs)
+	_, err = chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+}
@@ -0,0 +1,71 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// oneActionAgent calls the "job" tool once.
+type oneActionAgent struct{}
+
+func (oneActionAgent) Plan(_ context.Context, steps []schema.AgentStep, _ map[string]string) ([]schema.AgentAction, *schema.AgentFinish, error) { //nolint:lll
+	if len(steps) > 0 {
+		return nil, &schema.AgentFinish{ReturnValues: map[string]any{"output": steps[0].Observation}}, nil
+	}
+	return []schema.AgentAction{{Tool: "job", ToolInput: "start"}}, nil, nil
+}
+
+func (oneActionAgent) GetInputKeys() []string  { return []string{"input"} }
+func (oneActionAgent) GetOutputKeys() []string { return []string{"output"} }
+
+// jobTool blocks until interrupted.
+type jobTool struct {
+	started     chan struct{}
+	interrupted chan struct{}
+}
+
+var (
+	_ tools.Tool        = jobTool{}
+	_ tools.Interrupter = jobTool{}
+)
+
+func (jobTool) Name() string        { return "job" }
+func (jobTool) Description() string { return "starts a job" }
+
+func (j jobTool) Call(context.Context, string) (string, error) {
+	close(j.started)
+	<-j.interrupted
+	return "interrupted", nil
+}
+
+func (j jobTool) Interrupt(context.Context) error {
+	close(j.interrupted)
+	return nil
+}
+
+func TestExecutorInterruptsTool(t *testing.T) {
+	t.Parallel()
+
+	tool := jobTool{started: make(chan struct{}), interrupted: make(chan struct{})}
+	executor := agents.NewExecutor(oneActionAgent{}, []tools.Tool{tool})
+	m := chains.NewRunManager()
+
+	done := make(chan error)
+	go func() {
+		_, err := m.Call(context.Background(), "run", executor, map[string]any{"input": "go"})
+		done <- err
+	}()
+
+	<-tool.started
+	require.NoError(t, m.Stop("run"))
+	require.ErrorIs(t, <-done, chains.ErrRunStopped)
+	run, _ := m.Get("run")
+	assert.Equal(t, chains.RunStateStopped, run.State)
+}
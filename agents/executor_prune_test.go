@@ -0,0 +1,102 @@
+package agents_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// wordsLLM counts tokens as words and records the prompts it is given.
+type wordsLLM struct {
+	contextSize int
+	responses   []string
+	prompts     []string
+}
+
+var (
+	_ llms.LanguageModel = (*wordsLLM)(nil)
+	_ llms.ContextSizer  = (*wordsLLM)(nil)
+)
+
+func (l *wordsLLM) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	l.prompts = append(l.prompts, promptValues[0].String())
+	response := l.responses[0]
+	l.responses = l.responses[1:]
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: response}}}}, nil
+}
+
+func (l *wordsLLM) GetNumTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func (l *wordsLLM) GetContextSize() int {
+	return l.contextSize
+}
+
+func newPruningExecutor(t *testing.T, llm *wordsLLM, turns int) agents.Executor {
+	t.Helper()
+
+	history := memory.NewChatMessageHistory()
+	for i := 0; i < turns; i++ {
+		require.NoError(t, history.AddUserMessage(fmt.Sprintf("question %d", i)))
+	}
+	mem := memory.NewConversationBuffer(memory.WithChatHistory(history))
+	prompt := prompts.NewPromptTemplate("{{.history}}\n{{.input}}\n{{.agent_scratchpad}}",
+		[]string{"history", "input", "agent_scratchpad"})
+	agent := agents.NewConversationalAgent(llm, []tools.Tool{tools.Calculator{}}, agents.WithPrompt(prompt))
+	return agents.NewExecutor(agent, agent.Tools, agents.WithMemory(mem), agents.WithContextPruning(5))
+}
+
+func TestExecutorPrunesMemory(t *testing.T) {
+	t.Parallel()
+
+	llm := &wordsLLM{contextSize: 20, responses: []string{"AI: done"}}
+	executor := newPruningExecutor(t, llm, 10)
+
+	_, err := chains.Run(context.Background(), executor, "hi")
+	require.NoError(t, err)
+	require.Len(t, llm.prompts, 1)
+	assert.Equal(t, "Human: question 6\nHuman: question 7\nHuman: question 8\nHuman: question 9\nhi\n",
+		llm.prompts[0])
+}
+
+func TestExecutorPrunesSteps(t *testing.T) {
+	t.Parallel()
+
+	llm := &wordsLLM{contextSize: 20, responses: []string{
+		"Action: calculator\nAction Input: 1+1",
+		"Action: calculator\nAction Input: 2+2",
+		"Action: calculator\nAction Input: 3+3",
+		"AI: done",
+	}}
+	executor := newPruningExecutor(t, llm, 0)
+
+	_, err := chains.Run(context.Background(), executor, "hi")
+	require.NoError(t, err)
+	require.Len(t, llm.prompts, 4)
+	last := llm.prompts[3]
+	assert.NotContains(t, last, "1+1")
+	assert.Contains(t, last, "3+3")
+	assert.LessOrEqual(t, llm.GetNumTokens(last)+5, 20)
+}
+
+func TestExecutorPruningFails(t *testing.T) {
+	t.Parallel()
+
+	llm := &wordsLLM{contextSize: 4, responses: []string{"AI: done"}}
+	executor := newPruningExecutor(t, llm, 0)
+
+	_, err := chains.Run(context.Background(), executor, "hi")
+	require.ErrorIs(t, err, llms.ErrContextTooLong)
+}
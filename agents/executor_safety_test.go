@@ -0,0 +1,44 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/safety"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// staticJobTool is a "job" tool returning its output.
+type staticJobTool struct {
+	output string
+}
+
+func (staticJobTool) Name() string        { return "job" }
+func (staticJobTool) Description() string { return "starts a job" }
+
+func (t staticJobTool) Call(context.Context, string) (string, error) {
+	return t.output, nil
+}
+
+func TestExecutorSafetyCheckers(t *testing.T) {
+	t.Parallel()
+
+	redact, err := safety.NewDenylist(safety.Redact, `sk-[a-z0-9]+`)
+	require.NoError(t, err)
+	block, err := safety.NewDenylist(safety.Block, `rm -rf`)
+	require.NoError(t, err)
+
+	executor := agents.NewExecutor(oneActionAgent{}, []tools.Tool{staticJobTool{output: "key is sk-abc123"}},
+		agents.WithSafetyCheckers(redact, block))
+	outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.NoError(t, err)
+	require.Equal(t, "key is [REDACTED]", outputs["output"])
+
+	executor = agents.NewExecutor(oneActionAgent{}, []tools.Tool{staticJobTool{output: "run rm -rf /"}},
+		agents.WithSafetyCheckers(redact, block))
+	_, err = chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.ErrorIs(t, err, safety.ErrBlocked)
+}
@@ -0,0 +1,41 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestExecutorReturnIntermediateSteps(t *testing.T) {
+	t.Parallel()
+
+	mem := memory.NewConversationBuffer(memory.WithOutputKey("output"))
+	executor := agents.NewExecutor(oneActionAgent{}, []tools.Tool{staticJobTool{output: "started"}},
+		agents.WithReturnIntermediateSteps(), agents.WithMemory(mem))
+	require.Equal(t, []string{"output", agents.IntermediateStepsOutputKey}, executor.GetOutputKeys())
+
+	outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.NoError(t, err)
+	require.Equal(t, "started", outputs["output"])
+	require.Equal(t, []schema.AgentStep{{
+		Action:      schema.AgentAction{Tool: "job", ToolInput: "start"},
+		Observation: "started",
+	}}, outputs[agents.IntermediateStepsOutputKey])
+
+	messages, err := mem.ChatHistory.Messages()
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	require.Equal(t, "started", messages[1].GetContent())
+
+	executor = agents.NewExecutor(oneActionAgent{}, []tools.Tool{staticJobTool{output: "started"}})
+	require.Equal(t, []string{"output"}, executor.GetOutputKeys())
+	outputs, err = chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.NoError(t, err)
+	require.NotContains(t, outputs, agents.IntermediateStepsOutputKey)
+}
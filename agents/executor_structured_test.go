@@ -0,0 +1,58 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// retryAgent calls the "lookup" tool with an invalid input, then with a valid
+// one, and answers with the observations.
+type retryAgent struct{}
+
+func (retryAgent) Plan(_ context.Context, steps []schema.AgentStep, _ map[string]string) ([]schema.AgentAction, *schema.AgentFinish, error) { //nolint:lll
+	switch len(steps) {
+	case 0:
+		return []schema.AgentAction{{Tool: "lookup", ToolInput: `{"id": "seven"}`}}, nil, nil
+	case 1:
+		return []schema.AgentAction{{Tool: "lookup", ToolInput: `{"id": 7}`}}, nil, nil
+	}
+	return nil, &schema.AgentFinish{ReturnValues: map[string]any{
+		"output": steps[0].Observation + " | " + steps[1].Observation,
+	}}, nil
+}
+
+func (retryAgent) GetInputKeys() []string  { return []string{"input"} }
+func (retryAgent) GetOutputKeys() []string { return []string{"output"} }
+
+type lookupTool struct{}
+
+var _ tools.StructuredTool = lookupTool{}
+
+func (lookupTool) Name() string        { return "lookup" }
+func (lookupTool) Description() string { return "looks up a record" }
+
+func (lookupTool) Call(_ context.Context, input string) (string, error) {
+	return "found " + input, nil
+}
+
+func (lookupTool) Parameters() []tools.Parameter {
+	return []tools.Parameter{{Name: "id", Type: tools.ParameterTypeInteger, Required: true}}
+}
+
+func TestExecutorValidatesStructuredInput(t *testing.T) {
+	t.Parallel()
+
+	executor := agents.NewExecutor(retryAgent{}, []tools.Tool{lookupTool{}})
+	outputs, err := chains.Call(context.Background(), executor, map[string]any{"input": "go"})
+	require.NoError(t, err)
+	require.Equal(t,
+		`invalid tool input: parameter "id" must be of type integer, fix the input and try again | found {"id": 7}`,
+		outputs["output"],
+	)
+}
@@ -1,10 +1,12 @@
 package agents
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
 )
 
 func TestMRKLOutputParser(t *testing.T) {
@@ -36,3 +38,21 @@ func TestMRKLOutputParser(t *testing.T) {
 		require.Equal(t, tc.expectedFinish, finish)
 	}
 }
+
+func TestToolDescriptionsStructured(t *testing.T) {
+	t.Parallel()
+
+	descriptions := toolDescriptions([]tools.Tool{tools.Calculator{}, structuredTool{}})
+	require.Contains(t, descriptions, "- lookup: looks up a record\n"+
+		`  Input: a JSON object with the schema {"properties":{"id":{"type":"integer"}},"required":["id"],"type":"object"}`+"\n")
+}
+
+type structuredTool struct{}
+
+func (structuredTool) Name() string                                 { return "lookup" }
+func (structuredTool) Description() string                          { return "looks up a record" }
+func (structuredTool) Call(context.Context, string) (string, error) { return "", nil }
+
+func (structuredTool) Parameters() []tools.Parameter {
+	return []tools.Parameter{{Name: "id", Type: tools.ParameterTypeInteger, Required: true}}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/schema"
@@ -31,9 +32,14 @@ type OneShotZeroAgent struct {
 	Tools []tools.Tool
 	// Output key is the key where the final output is placed.
 	OutputKey string
+	// CallbacksHandler is given the chunks streamed by the llm while planning.
+	CallbacksHandler callbacks.Handler
 }
 
-var _ Agent = (*OneShotZeroAgent)(nil)
+var (
+	_ Agent       = (*OneShotZeroAgent)(nil)
+	_ PromptSizer = (*OneShotZeroAgent)(nil)
+)
 
 // NewOneShotAgent creates a new OneShotZeroAgent with the given LLM model, tools,
 // and options. It returns a pointer to the created agent. The opts parameter
@@ -45,9 +51,10 @@ func NewOneShotAgent(llm llms.LanguageModel, tools []tools.Tool, opts ...Creatio
 	}
 
 	return &OneShotZeroAgent{
-		Chain:     chains.NewLLMChain(llm, options.getMrklPrompt(tools)),
-		Tools:     tools,
-		OutputKey: options.outputKey,
+		Chain:            newLLMChain(llm, options.getMrklPrompt(tools), options.callbacksHandler),
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
 	}
 }
 
@@ -57,19 +64,11 @@ func (a *OneShotZeroAgent) Plan(
 	intermediateSteps []schema.AgentStep,
 	inputs map[string]string,
 ) ([]schema.AgentAction, *schema.AgentFinish, error) {
-	fullInputs := make(map[string]any, len(inputs))
-	for key, value := range inputs {
-		fullInputs[key] = value
-	}
-
-	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
-	fullInputs["today"] = time.Now().Format("January 02, 2006")
-
 	output, err := chains.Predict(
 		ctx,
 		a.Chain,
-		fullInputs,
-		chains.WithStopWords([]string{"\nObservation:", "\n\tObservation:"}),
+		a.planInputs(intermediateSteps, inputs),
+		planOptions(a.CallbacksHandler)...,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -78,6 +77,30 @@ func (a *OneShotZeroAgent) Plan(
 	return a.parseOutput(output)
 }
 
+// PromptTokens returns the number of tokens of the prompt planning with the
+// steps and inputs.
+func (a *OneShotZeroAgent) PromptTokens(intermediateSteps []schema.AgentStep, inputs map[string]string) (int, error) {
+	return chainPromptTokens(a.Chain, a.planInputs(intermediateSteps, inputs))
+}
+
+// ContextSize returns the size of the context window of the llm, or 0 if
+// unknown.
+func (a *OneShotZeroAgent) ContextSize() int {
+	return chainContextSize(a.Chain)
+}
+
+// planInputs returns the inputs of the chain for the steps and inputs.
+func (a *OneShotZeroAgent) planInputs(intermediateSteps []schema.AgentStep, inputs map[string]string) map[string]any {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+
+	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
+	fullInputs["today"] = time.Now().Format("January 02, 2006")
+	return fullInputs
+}
+
 func (a *OneShotZeroAgent) GetInputKeys() []string {
 	chainInputs := a.Chain.GetInputKeys()
 
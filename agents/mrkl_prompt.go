@@ -1,6 +1,7 @@
 package agents
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -57,11 +58,18 @@ func toolNames(tools []tools.Tool) string {
 	return tn.String()
 }
 
-func toolDescriptions(tools []tools.Tool) string {
-	var ts strings.Builder
-	for _, tool := range tools {
-		ts.WriteString(fmt.Sprintf("- %s: %s\n", tool.Name(), tool.Description()))
+func toolDescriptions(ts []tools.Tool) string {
+	var sb strings.Builder
+	for _, tool := range ts {
+		sb.WriteString(fmt.Sprintf("- %s: %s", tool.Name(), tool.Description()))
+		if st, ok := tool.(tools.StructuredTool); ok {
+			schema, err := json.Marshal(tools.InputJSONSchema(st))
+			if err == nil {
+				sb.WriteString(fmt.Sprintf("\n  Input: a JSON object with the schema %s", schema))
+			}
+		}
+		sb.WriteString("\n")
 	}
 
-	return ts.String()
+	return sb.String()
 }
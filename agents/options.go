@@ -1,8 +1,10 @@
 package agents
 
 import (
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/safety"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/tools"
 )
@@ -16,6 +18,13 @@ type CreationOptions struct {
 	promptPrefix            string
 	formatInstructions      string
 	promptSuffix            string
+	callbacksHandler        callbacks.Handler
+	pruneContext            bool
+	responseReserve         int
+	safetyCheckers          []safety.Checker
+	approvalFunc            ApprovalFunc
+	maxTotalTokens          int
+	maxCostUSD              float64
 }
 
 // CreationOption is a function type that can be used to modify the creation of the agents
@@ -119,7 +128,9 @@ func WithPrompt(prompt prompts.PromptTemplate) CreationOption {
 }
 
 // WithReturnIntermediateSteps is an option for making the executor return the intermediate steps
-// taken.
+// taken. The steps, a []schema.AgentStep with each action and its observation, are
+// returned under IntermediateStepsOutputKey. A conversation memory of the executor then
+// needs an output key, as in memory.WithOutputKey("output"), to know which output to save.
 func WithReturnIntermediateSteps() CreationOption {
 	return func(co *CreationOptions) {
 		co.returnIntermediateSteps = true
@@ -131,3 +142,55 @@ func WithMemory(m schema.Memory) CreationOption {
 		co.memory = m
 	}
 }
+
+// WithCallbacksHandler is an option for setting the handler called with the
+// events of the run: the llm calls and streamed chunks of agents, and the
+// actions, tool calls and final answer of executors.
+func WithCallbacksHandler(handler callbacks.Handler) CreationOption {
+	return func(co *CreationOptions) {
+		co.callbacksHandler = handler
+	}
+}
+
+// WithContextPruning is an option for making the executor prune the prompt of
+// each plan to fit the context window of the llm of the agent, keeping room for
+// a response of responseReserve tokens. The oldest intermediate steps are
+// dropped first, then the oldest lines of the memory. The agent must implement
+// PromptSizer and its llm llms.ContextSizer.
+func WithContextPruning(responseReserve int) CreationOption {
+	return func(co *CreationOptions) {
+		co.pruneContext = true
+		co.responseReserve = responseReserve
+	}
+}
+
+// WithSafetyCheckers is an option for making the executor check the final
+// answer of the agent, with the observations of the tools, before returning
+// it. Blocked answers make the executor fail with safety.ErrBlocked, and the
+// annotations are returned under the chains.SafetyAnnotationsKey output key.
+func WithSafetyCheckers(checkers ...safety.Checker) CreationOption {
+	return func(co *CreationOptions) {
+		co.safetyCheckers = append(co.safetyCheckers, checkers...)
+	}
+}
+
+// WithApprovalFunc is an option for making the executor consult the function
+// before each tool call, for example to ask a human for confirmation or to
+// enforce a policy. Calls not approved are not made, and the agent is told so
+// in the observation. An error from the function stops the executor.
+func WithApprovalFunc(approve ApprovalFunc) CreationOption {
+	return func(co *CreationOptions) {
+		co.approvalFunc = approve
+	}
+}
+
+// WithBudget is an option for stopping the executor with ErrBudgetExceeded once the llm calls
+// made during a run used more than maxTotalTokens tokens or cost more than maxCostUSD US
+// dollars. The usage is read from the generation info of the llm results, and the cost is
+// estimated with llms.ModelPrice. A zero limit is not checked.
+func WithBudget(maxTotalTokens int, maxCostUSD float64) CreationOption {
+	return func(co *CreationOptions) {
+		co.maxTotalTokens = maxTotalTokens
+		co.maxCostUSD = maxCostUSD
+	}
+}
@@ -0,0 +1,206 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	_defaultPlannerTemplate = `Let's first understand the problem and devise a plan to solve it.
+Output the plan as a numbered list of steps, one step per line, and nothing else.
+The steps must be simple and together enough to answer the question correctly.
+The last step should be answering the question.
+
+Question: {{.input}}
+
+Plan:`
+
+	_defaultStepTemplate = `Objective: {{.objective}}
+
+Previous steps and their results:
+{{.previous_steps}}
+
+Current step: {{.step}}`
+
+	_defaultSynthesizerTemplate = `Answer the question using the results of the steps taken to answer it.
+
+Question: {{.input}}
+
+Steps and their results:
+{{.steps}}
+
+Answer:`
+
+	_defaultMaxPlanSteps = 10
+)
+
+var _planStepRegexp = regexp.MustCompile(`^\s*\d+[.)]\s*(.+)$`)
+
+// PlanStep is a step of the plan of a PlanAndExecute and its result.
+type PlanStep struct {
+	Step     string
+	Response string
+}
+
+// PlanAndExecute is a chain answering with a plan: a planner llm call lists
+// the steps to take, an executor runs each step with the tools, and a
+// synthesizer llm call composes the answer from the results of the steps.
+type PlanAndExecute struct {
+	// Planner is given the "input" and returns the plan as a numbered list.
+	Planner chains.Chain
+	// StepExecutor is given each step as "input", with the objective and the
+	// previous steps, and returns its result as its only output.
+	StepExecutor chains.Chain
+	// Synthesizer is given the "input" and the "steps" with their results and
+	// returns the answer.
+	Synthesizer chains.Chain
+	Memory      schema.Memory
+
+	// MaxSteps is the maximum number of steps of a plan, the next ones are
+	// dropped.
+	MaxSteps                int
+	OutputKey               string
+	ReturnIntermediateSteps bool
+	CallbacksHandler        callbacks.Handler
+}
+
+var (
+	_ chains.Chain           = PlanAndExecute{}
+	_ callbacks.HandlerHaver = PlanAndExecute{}
+)
+
+// NewPlanAndExecute creates a plan and execute chain using the llm to plan,
+// to run the steps with a zero shot agent using the tools, and to compose the
+// answer. The options apply to the step executor, except for the memory and
+// the return of intermediate steps, which apply to the chain. The intermediate
+// steps of the chain are its PlanStep values.
+func NewPlanAndExecute(llm llms.LanguageModel, tools []tools.Tool, opts ...CreationOption) PlanAndExecute {
+	options := executorDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	stepExecutor := NewExecutor(NewOneShotAgent(llm, tools, opts...), tools, opts...)
+	stepExecutor.Memory = memory.NewSimple()
+	stepExecutor.ReturnIntermediateSteps = false
+
+	return PlanAndExecute{
+		Planner: newLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultPlannerTemplate, []string{"input"},
+		), options.callbacksHandler),
+		StepExecutor: stepExecutor,
+		Synthesizer: newLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultSynthesizerTemplate, []string{"input", "steps"},
+		), options.callbacksHandler),
+		Memory:                  options.memory,
+		MaxSteps:                _defaultMaxPlanSteps,
+		OutputKey:               options.outputKey,
+		ReturnIntermediateSteps: options.returnIntermediateSteps,
+		CallbacksHandler:        options.callbacksHandler,
+	}
+}
+
+// Call plans, runs the steps of the plan in order and composes the answer.
+func (p PlanAndExecute) Call(ctx context.Context, inputValues map[string]any, options ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	inputs, err := inputsToString(inputValues)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := chains.Predict(ctx, p.Planner, map[string]any{"input": inputs["input"]}, options...)
+	if err != nil {
+		return nil, err
+	}
+	steps := parsePlan(plan, p.MaxSteps)
+
+	stepPrompt := prompts.NewPromptTemplate(_defaultStepTemplate, []string{"objective", "previous_steps", "step"})
+	results := make([]PlanStep, 0, len(steps))
+	for _, step := range steps {
+		stepInput, err := stepPrompt.Format(map[string]any{
+			"objective":      inputs["input"],
+			"previous_steps": formatPlanSteps(results),
+			"step":           step,
+		})
+		if err != nil {
+			return nil, err
+		}
+		response, err := chains.Predict(ctx, p.StepExecutor, map[string]any{"input": stepInput}, options...)
+		if err != nil {
+			return nil, fmt.Errorf("step %q: %w", step, err)
+		}
+		results = append(results, PlanStep{Step: step, Response: strings.TrimSpace(response)})
+	}
+
+	answer, err := chains.Predict(ctx, p.Synthesizer, map[string]any{
+		"input": inputs["input"],
+		"steps": formatPlanSteps(results),
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := map[string]any{p.OutputKey: strings.TrimSpace(answer)}
+	if p.ReturnIntermediateSteps {
+		outputs[IntermediateStepsOutputKey] = results
+	}
+	return outputs, nil
+}
+
+// GetMemory returns the memory of the chain.
+func (p PlanAndExecute) GetMemory() schema.Memory { //nolint:ireturn
+	return p.Memory
+}
+
+// GetInputKeys returns the input key of the chain, "input".
+func (p PlanAndExecute) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+// GetOutputKeys returns the output key of the chain.
+func (p PlanAndExecute) GetOutputKeys() []string {
+	return []string{p.OutputKey}
+}
+
+// GetCallbackHandler returns the callbacks handler of the chain.
+func (p PlanAndExecute) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return p.CallbacksHandler
+}
+
+// parsePlan returns the steps of the numbered list of the plan, or the whole
+// plan as a single step if it is not a numbered list.
+func parsePlan(plan string, maxSteps int) []string {
+	steps := make([]string, 0)
+	for _, line := range strings.Split(plan, "\n") {
+		if m := _planStepRegexp.FindStringSubmatch(line); m != nil {
+			steps = append(steps, strings.TrimSpace(m[1]))
+		}
+	}
+	if len(steps) == 0 && strings.TrimSpace(plan) != "" {
+		steps = append(steps, strings.TrimSpace(plan))
+	}
+	if maxSteps > 0 && len(steps) > maxSteps {
+		steps = steps[:maxSteps]
+	}
+	return steps
+}
+
+func formatPlanSteps(steps []PlanStep) string {
+	if len(steps) == 0 {
+		return "None"
+	}
+	var sb strings.Builder
+	for i, s := range steps {
+		fmt.Fprintf(&sb, "%d. %s\nResult: %s\n", i+1, s.Step, s.Response)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,55 @@
+package agents_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// planningLLM plans two steps, runs each with the "lookup" tool and answers
+// with the results of the steps.
+type planningLLM struct{}
+
+func (planningLLM) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	prompt := promptValues[0].String()
+	var text string
+	switch {
+	case strings.Contains(prompt, "devise a plan"):
+		text = "1. Look up the otter.\n2) Look up the owl.\n"
+	case strings.HasSuffix(prompt, "Answer:"):
+		text = " " + strconv.Itoa(strings.Count(prompt, "Result: done found"))
+	case strings.Contains(prompt, "Observation: found"):
+		observation := prompt[strings.LastIndex(prompt, "Observation: ")+len("Observation: "):]
+		text = "Final Answer: done " + strings.SplitN(observation, "\n", 2)[0]
+	case strings.Contains(prompt, "Current step: Look up the otter."):
+		text = "Action: lookup\nAction Input: {\"id\": 1}"
+	case strings.Contains(prompt, "Current step: Look up the owl."):
+		text = "Action: lookup\nAction Input: {\"id\": 2}"
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: text}}}}, nil
+}
+
+func (planningLLM) GetNumTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestPlanAndExecute(t *testing.T) {
+	t.Parallel()
+
+	p := agents.NewPlanAndExecute(planningLLM{}, []tools.Tool{lookupTool{}}, agents.WithReturnIntermediateSteps())
+	outputs, err := chains.Call(context.Background(), p, map[string]any{"input": "what are otters and owls?"})
+	require.NoError(t, err)
+	require.Equal(t, "2", outputs["output"])
+	require.Equal(t, []agents.PlanStep{
+		{Step: "Look up the otter.", Response: `done found {"id": 1}`},
+		{Step: "Look up the owl.", Response: `done found {"id": 2}`},
+	}, outputs["intermediateSteps"])
+}
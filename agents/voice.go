@@ -0,0 +1,142 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// VoiceAudioKey is the input key of the audio of a VoiceAgent, and the
+	// output key of the synthesized answer.
+	VoiceAudioKey = "audio"
+	// VoiceTranscriptKey is the output key of the transcript of the audio.
+	VoiceTranscriptKey = "transcript"
+	// VoiceLatencyKey is the output key of the VoiceLatency of a call.
+	VoiceLatencyKey = "latency"
+)
+
+// ErrVoiceInputNotAudio is returned if the audio given to a VoiceAgent is not
+// a []byte.
+var ErrVoiceInputNotAudio = errors.New("voice agent input not audio bytes")
+
+// Transcriber transcribes speech to text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio []byte) (string, error)
+}
+
+// SpeechSynthesizer synthesizes speech for a text.
+type SpeechSynthesizer interface {
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// VoiceLatency is the time taken by each stage of a call of a VoiceAgent.
+type VoiceLatency struct {
+	Transcription time.Duration
+	Agent         time.Duration
+	Synthesis     time.Duration
+}
+
+// VoiceAgent is a chain transcribing audio, running an agent with the
+// transcript and synthesizing speech for the answer.
+type VoiceAgent struct {
+	Transcriber Transcriber
+	// Agent is run with the transcript as its only input and must return a
+	// single output, as an Executor does by default.
+	Agent chains.Chain
+	// Synthesizer synthesizes the answer if it is not nil.
+	Synthesizer SpeechSynthesizer
+	Memory      schema.Memory
+	OutputKey   string
+}
+
+var (
+	_ chains.Chain           = VoiceAgent{}
+	_ callbacks.HandlerHaver = VoiceAgent{}
+)
+
+// NewVoiceAgent creates a voice agent transcribing audio with the transcriber
+// and running the agent with the transcript. The answer is synthesized with
+// the synthesizer, which can be nil to only return the text of the answer.
+func NewVoiceAgent(transcriber Transcriber, agent chains.Chain, synthesizer SpeechSynthesizer) VoiceAgent {
+	return VoiceAgent{
+		Transcriber: transcriber,
+		Agent:       agent,
+		Synthesizer: synthesizer,
+		Memory:      memory.NewSimple(),
+		OutputKey:   _defaultOutputKey,
+	}
+}
+
+// Call transcribes the audio, runs the agent and synthesizes its answer. It
+// returns the transcript, the answer, the synthesized audio if any, and the
+// latency of each stage.
+func (v VoiceAgent) Call(ctx context.Context, inputValues map[string]any, options ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	audio, ok := inputValues[VoiceAudioKey].([]byte)
+	if !ok {
+		return nil, ErrVoiceInputNotAudio
+	}
+
+	var latency VoiceLatency
+	start := time.Now()
+	transcript, err := v.Transcriber.Transcribe(ctx, audio)
+	if err != nil {
+		return nil, err
+	}
+	latency.Transcription = time.Since(start)
+
+	start = time.Now()
+	answer, err := chains.Run(ctx, v.Agent, transcript, options...)
+	if err != nil {
+		return nil, err
+	}
+	latency.Agent = time.Since(start)
+
+	outputs := map[string]any{
+		VoiceTranscriptKey: transcript,
+		v.OutputKey:        answer,
+	}
+	if v.Synthesizer != nil {
+		start = time.Now()
+		speech, err := v.Synthesizer.Synthesize(ctx, answer)
+		if err != nil {
+			return nil, err
+		}
+		latency.Synthesis = time.Since(start)
+		outputs[VoiceAudioKey] = speech
+	}
+	outputs[VoiceLatencyKey] = latency
+	return outputs, nil
+}
+
+// GetMemory returns the memory of the chain.
+func (v VoiceAgent) GetMemory() schema.Memory { //nolint:ireturn
+	return v.Memory
+}
+
+// GetInputKeys returns the input key of the chain, "audio".
+func (v VoiceAgent) GetInputKeys() []string {
+	return []string{VoiceAudioKey}
+}
+
+// GetOutputKeys returns the output keys of the chain.
+func (v VoiceAgent) GetOutputKeys() []string {
+	outputKeys := []string{VoiceTranscriptKey, v.OutputKey, VoiceLatencyKey}
+	if v.Synthesizer != nil {
+		outputKeys = append(outputKeys, VoiceAudioKey)
+	}
+	return outputKeys
+}
+
+// GetCallbackHandler returns the callbacks handler of the agent, if any.
+func (v VoiceAgent) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	if hh, ok := v.Agent.(callbacks.HandlerHaver); ok {
+		return hh.GetCallbackHandler()
+	}
+	return nil
+}
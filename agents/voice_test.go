@@ -0,0 +1,51 @@
+package agents_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/tools"
+)
+
+var (
+	_ agents.Transcriber       = (*openai.Chat)(nil)
+	_ agents.SpeechSynthesizer = (*openai.Chat)(nil)
+)
+
+// textAudio "transcribes" audio holding text and "synthesizes" text as upper
+// case audio.
+type textAudio struct{}
+
+func (textAudio) Transcribe(_ context.Context, audio []byte) (string, error) {
+	return string(audio), nil
+}
+
+func (textAudio) Synthesize(_ context.Context, text string) ([]byte, error) {
+	return []byte(strings.ToUpper(text)), nil
+}
+
+func TestVoiceAgent(t *testing.T) {
+	t.Parallel()
+
+	executor := agents.NewExecutor(oneActionAgent{}, []tools.Tool{staticJobTool{output: "started"}})
+	voice := agents.NewVoiceAgent(textAudio{}, executor, textAudio{})
+	outputs, err := chains.Call(context.Background(), voice, map[string]any{"audio": []byte("start the job")})
+	require.NoError(t, err)
+	require.Equal(t, "start the job", outputs[agents.VoiceTranscriptKey])
+	require.Equal(t, "started", outputs["output"])
+	require.Equal(t, []byte("STARTED"), outputs[agents.VoiceAudioKey])
+	require.IsType(t, agents.VoiceLatency{}, outputs[agents.VoiceLatencyKey])
+
+	voice = agents.NewVoiceAgent(textAudio{}, executor, nil)
+	outputs, err = chains.Call(context.Background(), voice, map[string]any{"audio": []byte("start the job")})
+	require.NoError(t, err)
+	require.NotContains(t, outputs, agents.VoiceAudioKey)
+
+	_, err = chains.Call(context.Background(), voice, map[string]any{"audio": "start the job"})
+	require.ErrorIs(t, err, agents.ErrVoiceInputNotAudio)
+}
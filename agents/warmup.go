@@ -0,0 +1,52 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/chains"
+)
+
+var (
+	_ chains.Warmer = (*OneShotZeroAgent)(nil)
+	_ chains.Warmer = (*ConversationalAgent)(nil)
+	_ chains.Warmer = Executor{}
+	_ chains.Warmer = PlanAndExecute{}
+	_ chains.Warmer = VoiceAgent{}
+)
+
+// Warmup prepares the chain of the agent for its first plan, see
+// chains.Warmup.
+func (a *OneShotZeroAgent) Warmup(ctx context.Context, options ...chains.WarmupOption) error {
+	return chains.Warmup(ctx, a.Chain, options...)
+}
+
+// Warmup prepares the chain of the agent for its first plan, see
+// chains.Warmup.
+func (a *ConversationalAgent) Warmup(ctx context.Context, options ...chains.WarmupOption) error {
+	return chains.Warmup(ctx, a.Chain, options...)
+}
+
+// Warmup prepares the agent for its first run if it implements chains.Warmer,
+// as the agents of this package do.
+func (e Executor) Warmup(ctx context.Context, options ...chains.WarmupOption) error {
+	if w, ok := e.Agent.(chains.Warmer); ok {
+		return w.Warmup(ctx, options...)
+	}
+	return nil
+}
+
+// Warmup prepares the planner, the step executor and the synthesizer for
+// their first call.
+func (p PlanAndExecute) Warmup(ctx context.Context, options ...chains.WarmupOption) error {
+	for _, c := range []chains.Chain{p.Planner, p.StepExecutor, p.Synthesizer} {
+		if err := chains.Warmup(ctx, c, options...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Warmup prepares the agent for its first run.
+func (v VoiceAgent) Warmup(ctx context.Context, options ...chains.WarmupOption) error {
+	return chains.Warmup(ctx, v.Agent, options...)
+}
@@ -0,0 +1,51 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+type warmupLLM struct {
+	warmedUp int
+	prompts  []string
+}
+
+func (l *warmupLLM) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	for _, p := range promptValues {
+		l.prompts = append(l.prompts, p.String())
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: "ok"}}}}, nil
+}
+
+func (l *warmupLLM) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func (l *warmupLLM) Warmup(context.Context) error {
+	l.warmedUp++
+	return nil
+}
+
+func TestExecutorWarmup(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &warmupLLM{}
+	agentTools := []tools.Tool{tools.Calculator{}}
+	executor := NewExecutor(NewOneShotAgent(llm, agentTools), agentTools)
+	require.NoError(t, chains.Warmup(ctx, executor, chains.WithPromptCachePriming(true)))
+	require.Equal(t, 1, llm.warmedUp)
+
+	// The default prompt starts with the date.
+	require.Equal(t, []string{"Today is "}, llm.prompts)
+
+	plan := NewPlanAndExecute(llm, agentTools)
+	require.NoError(t, chains.Warmup(ctx, plan))
+	require.Equal(t, 4, llm.warmedUp)
+}
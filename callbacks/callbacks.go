@@ -0,0 +1,31 @@
+package callbacks
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Handler is the interface that allows for hooking into specific parts of an
+// LLM application. Handlers must not block: they are called synchronously from
+// the run they observe.
+type Handler interface {
+	HandleLLMStart(ctx context.Context, prompts []string)
+	HandleLLMEnd(ctx context.Context, output llms.LLMResult)
+	HandleLLMError(ctx context.Context, err error)
+	HandleStreamingFunc(ctx context.Context, chunk []byte)
+	HandleChainStart(ctx context.Context, inputs map[string]any)
+	HandleChainEnd(ctx context.Context, outputs map[string]any)
+	HandleChainError(ctx context.Context, err error)
+	HandleToolStart(ctx context.Context, tool, input string)
+	HandleToolEnd(ctx context.Context, tool, output string)
+	HandleToolError(ctx context.Context, tool string, err error)
+	HandleAgentAction(ctx context.Context, action schema.AgentAction)
+	HandleAgentFinish(ctx context.Context, finish schema.AgentFinish)
+}
+
+// HandlerHaver is an interface used to get callbacks handler.
+type HandlerHaver interface {
+	GetCallbackHandler() Handler
+}
@@ -0,0 +1,87 @@
+package callbacks
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// CombiningHandler is a Handler calling each of its handlers in turn.
+type CombiningHandler struct {
+	Callbacks []Handler
+}
+
+var _ Handler = CombiningHandler{}
+
+func (l CombiningHandler) HandleLLMStart(ctx context.Context, prompts []string) {
+	for _, h := range l.Callbacks {
+		h.HandleLLMStart(ctx, prompts)
+	}
+}
+
+func (l CombiningHandler) HandleLLMEnd(ctx context.Context, output llms.LLMResult) {
+	for _, h := range l.Callbacks {
+		h.HandleLLMEnd(ctx, output)
+	}
+}
+
+func (l CombiningHandler) HandleLLMError(ctx context.Context, err error) {
+	for _, h := range l.Callbacks {
+		h.HandleLLMError(ctx, err)
+	}
+}
+
+func (l CombiningHandler) HandleStreamingFunc(ctx context.Context, chunk []byte) {
+	for _, h := range l.Callbacks {
+		h.HandleStreamingFunc(ctx, chunk)
+	}
+}
+
+func (l CombiningHandler) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	for _, h := range l.Callbacks {
+		h.HandleChainStart(ctx, inputs)
+	}
+}
+
+func (l CombiningHandler) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	for _, h := range l.Callbacks {
+		h.HandleChainEnd(ctx, outputs)
+	}
+}
+
+func (l CombiningHandler) HandleChainError(ctx context.Context, err error) {
+	for _, h := range l.Callbacks {
+		h.HandleChainError(ctx, err)
+	}
+}
+
+func (l CombiningHandler) HandleToolStart(ctx context.Context, tool, input string) {
+	for _, h := range l.Callbacks {
+		h.HandleToolStart(ctx, tool, input)
+	}
+}
+
+func (l CombiningHandler) HandleToolEnd(ctx context.Context, tool, output string) {
+	for _, h := range l.Callbacks {
+		h.HandleToolEnd(ctx, tool, output)
+	}
+}
+
+func (l CombiningHandler) HandleToolError(ctx context.Context, tool string, err error) {
+	for _, h := range l.Callbacks {
+		h.HandleToolError(ctx, tool, err)
+	}
+}
+
+func (l CombiningHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	for _, h := range l.Callbacks {
+		h.HandleAgentAction(ctx, action)
+	}
+}
+
+func (l CombiningHandler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	for _, h := range l.Callbacks {
+		h.HandleAgentFinish(ctx, finish)
+	}
+}
@@ -0,0 +1,7 @@
+// Package callbacks contains the Handler interface used to observe the runs of
+// chains, agents, tools and LLMs, and helpers to build handlers.
+//
+// RedactingHandler masks secrets and personal information in the events
+// passed to another handler, such as a tracer exporting runs, and LogHandler
+// logs events with their payloads masked the same way.
+package callbacks
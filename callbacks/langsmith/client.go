@@ -0,0 +1,132 @@
+package langsmith
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// The environment variables configuring the tracing, read under their
+// LANGSMITH_ name, or else their LANGCHAIN_ name, as by the Python SDK.
+const (
+	apiKeyEnvVarName   = "API_KEY"
+	endpointEnvVarName = "ENDPOINT"
+	projectEnvVarName  = "PROJECT"
+	tracingEnvVarName  = "TRACING"
+	defaultEndpoint    = "https://api.smith.langchain.com"
+)
+
+var (
+	// ErrMissingAPIKey is returned by NewClient when no API key is given.
+	ErrMissingAPIKey = errors.New("missing the LangSmith API key, set it in the LANGSMITH_API_KEY environment variable")
+	// ErrTracingDisabled is returned by NewTracerFromEnv when tracing is not
+	// enabled by the environment.
+	ErrTracingDisabled = errors.New("LangSmith tracing is disabled, set LANGSMITH_TRACING to true to enable it")
+	// ErrUnexpectedStatus is returned by Client.Export when the API rejects
+	// a run.
+	ErrUnexpectedStatus = errors.New("unexpected status code")
+)
+
+// Client is an Exporter posting the trees of runs to the LangSmith API.
+type Client struct {
+	apiKey     string
+	endpoint   string
+	httpClient llms.Doer
+}
+
+var _ Exporter = (*Client)(nil)
+
+// ClientOption is a function that configures a Client.
+type ClientOption func(c *Client)
+
+// WithAPIKey sets the API key of the client. If not set, the key is read from
+// the LANGSMITH_API_KEY, or LANGCHAIN_API_KEY, environment variable.
+func WithAPIKey(apiKey string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithEndpoint sets the URL of the LangSmith API, such as the URL of a self
+// hosted instance. If not set, the URL is read from the LANGSMITH_ENDPOINT, or
+// LANGCHAIN_ENDPOINT, environment variable, and defaults to
+// https://api.smith.langchain.com.
+func WithEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient sets the client sending the requests, http.DefaultClient by
+// default.
+func WithHTTPClient(client llms.Doer) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// NewClient creates a client of the LangSmith API.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		apiKey:     getenv(apiKeyEnvVarName),
+		endpoint:   getenv(endpointEnvVarName),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.apiKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+	if c.endpoint == "" {
+		c.endpoint = defaultEndpoint
+	}
+	c.endpoint = strings.TrimSuffix(c.endpoint, "/")
+	return c, nil
+}
+
+// Export posts the run with its child runs.
+func (c *Client) Export(ctx context.Context, run *Run) error {
+	body, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("marshal run: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/runs", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("export run %s: %w", run.ID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("export run %s: %w %d: %s", run.ID, ErrUnexpectedStatus, resp.StatusCode, msg)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// getenv returns the value of the LANGSMITH_ environment variable of the name,
+// or else of its LANGCHAIN_ equivalent.
+func getenv(name string) string {
+	if v := os.Getenv("LANGSMITH_" + name); v != "" {
+		return v
+	}
+	if name == tracingEnvVarName {
+		name = "TRACING_V2"
+	}
+	return os.Getenv("LANGCHAIN_" + name)
+}
@@ -0,0 +1,34 @@
+/*
+Package langsmith exports the runs of chains, agents, tools and LLMs to
+LangSmith, so that Go services are traced in the same projects as Python
+services.
+
+A Tracer is a callbacks.Handler building the tree of the runs it observes:
+each chain, LLM and tool run is a child of the run in progress when it
+started. When a top level run ends, its tree is exported with an Exporter,
+such as a Client posting it to the LangSmith API:
+
+	client, err := langsmith.NewClient() // Reads LANGSMITH_API_KEY.
+	if err != nil {
+		return err
+	}
+	tracer := langsmith.NewTracer(client, langsmith.WithProjectName("my-project"))
+	executor := agents.NewExecutor(agent, tools, agents.WithCallbacksHandler(tracer))
+	_, err = chains.Run(ctx, executor, input)
+	...
+	err = tracer.Flush(ctx)
+
+As with the Python SDK, tracing can be configured by the environment instead:
+NewTracerFromEnv creates a tracer when LANGSMITH_TRACING is true, with the API
+key, endpoint and project of LANGSMITH_API_KEY, LANGSMITH_ENDPOINT and
+LANGSMITH_PROJECT. Their LANGCHAIN_ equivalents, such as LANGCHAIN_TRACING_V2,
+are read as well.
+
+Runs are exported in the background, as handlers must not block. Flush waits
+for the exports in progress, and should be called before the process exits,
+for example by giving the tracer to chains.RunManager.Shutdown.
+
+Concurrent runs sharing a tracer are told apart by the run id of their
+context, see callbacks.WithRunID. Runs without an id must not be concurrent.
+*/
+package langsmith
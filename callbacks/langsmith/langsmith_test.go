@@ -0,0 +1,170 @@
+package langsmith
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type exporterFunc func(ctx context.Context, run *Run) error
+
+func (f exporterFunc) Export(ctx context.Context, run *Run) error { return f(ctx, run) }
+
+func TestTracer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		runs []*Run
+	)
+	tracer := NewTracer(exporterFunc(func(_ context.Context, run *Run) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs = append(runs, run)
+		return nil
+	}), WithProjectName("project"), WithTags("go"))
+
+	ctx := callbacks.WithRunID(context.Background(), "run-1")
+	tracer.HandleChainStart(ctx, map[string]any{"input": "question"})
+	tracer.HandleLLMStart(ctx, []string{"prompt"})
+	tracer.HandleLLMEnd(ctx, llms.LLMResult{Generations: [][]*llms.Generation{{{Text: "action"}}}})
+	tracer.HandleAgentAction(ctx, schema.AgentAction{Tool: "search", ToolInput: "query"})
+	tracer.HandleToolStart(ctx, "search", "query")
+	tracer.HandleToolError(ctx, "search", errors.New("offline"))
+	tracer.HandleChainEnd(ctx, map[string]any{"output": "answer"})
+	require.NoError(t, tracer.Flush(context.Background()))
+
+	require.Len(t, runs, 1)
+	root := runs[0]
+	require.Equal(t, RunTypeChain, root.RunType)
+	require.Equal(t, "project", root.SessionName)
+	require.Equal(t, []string{"go"}, root.Tags)
+	require.Equal(t, root.ID, root.TraceID)
+	require.Equal(t, map[string]any{"output": "answer"}, root.Outputs)
+	require.Equal(t, "agent_action", root.Events[0].Name)
+	require.False(t, root.EndTime.Before(root.StartTime))
+
+	require.Len(t, root.ChildRuns, 2)
+	llm, tool := root.ChildRuns[0], root.ChildRuns[1]
+	require.Equal(t, RunTypeLLM, llm.RunType)
+	require.Equal(t, map[string]any{"prompts": []string{"prompt"}}, llm.Inputs)
+	require.Equal(t, "search", tool.Name)
+	require.Equal(t, "offline", tool.Error)
+	for _, child := range root.ChildRuns {
+		require.Equal(t, root.ID, child.ParentRunID)
+		require.Equal(t, root.ID, child.TraceID)
+		require.True(t, strings.HasPrefix(child.DottedOrder, root.DottedOrder+"."))
+		require.True(t, strings.HasSuffix(child.DottedOrder, child.ID))
+	}
+	require.Less(t, llm.DottedOrder, tool.DottedOrder)
+}
+
+func TestTracerConcurrentRuns(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		runs = map[string]*Run{}
+	)
+	tracer := NewTracer(exporterFunc(func(_ context.Context, run *Run) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs[run.Inputs["input"].(string)] = run //nolint:forcetypeassert
+		return nil
+	}))
+
+	a := callbacks.WithRunID(context.Background(), "a")
+	b := callbacks.WithRunID(context.Background(), "b")
+	tracer.HandleChainStart(a, map[string]any{"input": "a"})
+	tracer.HandleChainStart(b, map[string]any{"input": "b"})
+	tracer.HandleToolStart(a, "tool", "a")
+	tracer.HandleChainEnd(b, nil)
+	// The tool run left open is closed with its parent.
+	tracer.HandleChainEnd(a, nil)
+	require.NoError(t, tracer.Flush(context.Background()))
+
+	require.Len(t, runs, 2)
+	require.Len(t, runs["a"].ChildRuns, 1)
+	require.Empty(t, runs["b"].ChildRuns)
+	require.Equal(t, runs["a"].EndTime, runs["a"].ChildRuns[0].EndTime)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/runs", r.URL.Path)
+		require.Equal(t, "key", r.Header.Get("x-api-key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got["name"] == "rejected" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithAPIKey("key"), WithEndpoint(server.URL+"/"))
+	require.NoError(t, err)
+	tracer := NewTracer(client)
+
+	tracer.HandleChainStart(context.Background(), map[string]any{"input": "question"})
+	tracer.HandleLLMStart(context.Background(), []string{"prompt"})
+	tracer.HandleLLMEnd(context.Background(), llms.LLMResult{})
+	tracer.HandleChainEnd(context.Background(), map[string]any{"output": "answer"})
+	require.NoError(t, tracer.Flush(context.Background()))
+
+	require.Equal(t, "chain", got["run_type"])
+	require.Equal(t, map[string]any{"input": "question"}, got["inputs"])
+	children, ok := got["child_runs"].([]any)
+	require.True(t, ok)
+	require.Len(t, children, 1)
+	require.Equal(t, "llm", children[0].(map[string]any)["run_type"]) //nolint:forcetypeassert
+
+	err = client.Export(context.Background(), &Run{Name: "rejected"})
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+}
+
+func TestNewTracerFromEnv(t *testing.T) {
+	var got *Run
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "env-key", r.Header.Get("x-api-key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer server.Close()
+
+	for _, prefix := range []string{"LANGSMITH_", "LANGCHAIN_"} {
+		t.Setenv(prefix+"TRACING", "")
+		t.Setenv(prefix+"TRACING_V2", "")
+		t.Setenv(prefix+"API_KEY", "")
+		t.Setenv(prefix+"ENDPOINT", "")
+		t.Setenv(prefix+"PROJECT", "")
+	}
+	_, err := NewTracerFromEnv()
+	require.ErrorIs(t, err, ErrTracingDisabled)
+
+	t.Setenv("LANGCHAIN_TRACING_V2", "true")
+	_, err = NewTracerFromEnv()
+	require.ErrorIs(t, err, ErrMissingAPIKey)
+
+	t.Setenv("LANGCHAIN_API_KEY", "env-key")
+	t.Setenv("LANGSMITH_ENDPOINT", server.URL)
+	t.Setenv("LANGSMITH_PROJECT", "env-project")
+	tracer, err := NewTracerFromEnv()
+	require.NoError(t, err)
+
+	tracer.HandleToolStart(context.Background(), "search", "query")
+	tracer.HandleToolEnd(context.Background(), "search", "result")
+	require.NoError(t, tracer.Flush(context.Background()))
+	require.Equal(t, "env-project", got.SessionName)
+	require.Equal(t, RunTypeTool, got.RunType)
+}
@@ -0,0 +1,51 @@
+package langsmith
+
+import (
+	"strings"
+	"time"
+)
+
+// RunType is the type of a run.
+type RunType string
+
+const (
+	RunTypeChain RunType = "chain"
+	RunTypeLLM   RunType = "llm"
+	RunTypeTool  RunType = "tool"
+)
+
+// Run is a run in the format of the LangSmith API, with its child runs.
+type Run struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	RunType     RunType        `json:"run_type"`
+	StartTime   time.Time      `json:"start_time"`
+	EndTime     time.Time      `json:"end_time"`
+	Inputs      map[string]any `json:"inputs"`
+	Outputs     map[string]any `json:"outputs,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	ParentRunID string         `json:"parent_run_id,omitempty"`
+	// TraceID is the id of the top level run of the tree.
+	TraceID string `json:"trace_id"`
+	// DottedOrder orders the run in its tree: it is the dotted order of its
+	// parent, if any, followed by its start time and id.
+	DottedOrder string         `json:"dotted_order"`
+	SessionName string         `json:"session_name,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Extra       map[string]any `json:"extra,omitempty"`
+	Events      []Event        `json:"events,omitempty"`
+	ChildRuns   []*Run         `json:"child_runs,omitempty"`
+}
+
+// Event is an event of a run, such as an action of an agent.
+type Event struct {
+	Name   string         `json:"name"`
+	Time   time.Time      `json:"time"`
+	Kwargs map[string]any `json:"kwargs,omitempty"`
+}
+
+// dottedOrder returns the part of the dotted order of the run started at the
+// time with the id, the time in UTC with microseconds followed by the id.
+func dottedOrder(start time.Time, id string) string {
+	return strings.Replace(start.UTC().Format("20060102T150405.000000Z"), ".", "", 1) + id
+}
@@ -0,0 +1,243 @@
+package langsmith
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Exporter exports the trees of runs.
+type Exporter interface {
+	Export(ctx context.Context, run *Run) error
+}
+
+// Tracer is a callbacks.Handler building the trees of the runs it observes
+// and exporting them when their top level run ends. It is safe for concurrent
+// use.
+type Tracer struct {
+	exporter Exporter
+	project  string
+	tags     []string
+
+	mu      sync.Mutex
+	open    map[string][]*Run // Open runs by the run id of their context.
+	errs    []error
+	pending sync.WaitGroup
+}
+
+var _ callbacks.Handler = (*Tracer)(nil)
+
+// TracerOption is a function that configures a Tracer.
+type TracerOption func(t *Tracer)
+
+// WithProjectName sets the LangSmith project the runs are exported to. If not
+// set, the project is read from the LANGSMITH_PROJECT, or LANGCHAIN_PROJECT,
+// environment variable, and defaults to the default project of the API key.
+func WithProjectName(project string) TracerOption {
+	return func(t *Tracer) {
+		t.project = project
+	}
+}
+
+// WithTags sets the tags of the top level runs.
+func WithTags(tags ...string) TracerOption {
+	return func(t *Tracer) {
+		t.tags = tags
+	}
+}
+
+// NewTracer creates a tracer exporting the runs with the exporter.
+func NewTracer(exporter Exporter, opts ...TracerOption) *Tracer {
+	t := &Tracer{exporter: exporter, project: getenv(projectEnvVarName), open: make(map[string][]*Run)}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// NewTracerFromEnv creates a tracer exporting the runs to the LangSmith API
+// with a client configured by the environment, as the Python SDK is: tracing
+// is enabled by setting LANGSMITH_TRACING, or LANGCHAIN_TRACING_V2, to true,
+// and the API key, endpoint and project are read from LANGSMITH_API_KEY,
+// LANGSMITH_ENDPOINT and LANGSMITH_PROJECT, or their LANGCHAIN_ equivalents.
+// It returns ErrTracingDisabled if tracing is not enabled.
+func NewTracerFromEnv(opts ...TracerOption) (*Tracer, error) {
+	if enabled, _ := strconv.ParseBool(getenv(tracingEnvVarName)); !enabled {
+		return nil, ErrTracingDisabled
+	}
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewTracer(client, opts...), nil
+}
+
+// Flush waits for the exports in progress, or for the context to be done, and
+// returns the errors of the exports since the last flush.
+func (t *Tracer) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	err := errors.Join(t.errs...)
+	t.errs = nil
+	return err
+}
+
+func (t *Tracer) HandleLLMStart(ctx context.Context, prompts []string) {
+	t.start(ctx, RunTypeLLM, "LLM", map[string]any{"prompts": prompts})
+}
+
+func (t *Tracer) HandleLLMEnd(ctx context.Context, output llms.LLMResult) {
+	generations := make([][]map[string]any, len(output.Generations))
+	for i, gens := range output.Generations {
+		for _, g := range gens {
+			generations[i] = append(generations[i], map[string]any{
+				"text":            g.Text,
+				"generation_info": g.GenerationInfo,
+			})
+		}
+	}
+	t.end(ctx, RunTypeLLM, map[string]any{"generations": generations, "llm_output": output.LLMOutput}, nil)
+}
+
+func (t *Tracer) HandleLLMError(ctx context.Context, err error) {
+	t.end(ctx, RunTypeLLM, nil, err)
+}
+
+func (t *Tracer) HandleStreamingFunc(context.Context, []byte) {}
+
+func (t *Tracer) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	t.start(ctx, RunTypeChain, "Chain", inputs)
+}
+
+func (t *Tracer) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	t.end(ctx, RunTypeChain, outputs, nil)
+}
+
+func (t *Tracer) HandleChainError(ctx context.Context, err error) {
+	t.end(ctx, RunTypeChain, nil, err)
+}
+
+func (t *Tracer) HandleToolStart(ctx context.Context, tool, input string) {
+	t.start(ctx, RunTypeTool, tool, map[string]any{"input": input})
+}
+
+func (t *Tracer) HandleToolEnd(ctx context.Context, _, output string) {
+	t.end(ctx, RunTypeTool, map[string]any{"output": output}, nil)
+}
+
+func (t *Tracer) HandleToolError(ctx context.Context, _ string, err error) {
+	t.end(ctx, RunTypeTool, nil, err)
+}
+
+func (t *Tracer) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	t.event(ctx, "agent_action", map[string]any{"tool": action.Tool, "tool_input": action.ToolInput, "log": action.Log})
+}
+
+func (t *Tracer) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	t.event(ctx, "agent_finish", map[string]any{"return_values": finish.ReturnValues, "log": finish.Log})
+}
+
+// start opens a run, child of the innermost open run of the context, if any.
+func (t *Tracer) start(ctx context.Context, runType RunType, name string, inputs map[string]any) {
+	key := callbacks.RunIDFromContext(ctx)
+	run := &Run{
+		ID:        uuid.NewString(),
+		Name:      name,
+		RunType:   runType,
+		StartTime: time.Now(),
+		Inputs:    inputs,
+	}
+	order := dottedOrder(run.StartTime, run.ID)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stack := t.open[key]
+	if len(stack) == 0 {
+		run.TraceID = run.ID
+		run.DottedOrder = order
+		run.SessionName = t.project
+		run.Tags = t.tags
+		if key != "" {
+			run.Extra = map[string]any{"metadata": map[string]any{"run_id": key}}
+		}
+	} else {
+		parent := stack[len(stack)-1]
+		run.ParentRunID = parent.ID
+		run.TraceID = parent.TraceID
+		run.DottedOrder = parent.DottedOrder + "." + order
+		parent.ChildRuns = append(parent.ChildRuns, run)
+	}
+	t.open[key] = append(stack, run)
+}
+
+// end closes the innermost open run of the type, and the runs opened in it
+// which were not closed. The tree is exported once its top level run ends.
+func (t *Tracer) end(ctx context.Context, runType RunType, outputs map[string]any, err error) {
+	key := callbacks.RunIDFromContext(ctx)
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stack := t.open[key]
+	i := len(stack) - 1
+	for i >= 0 && stack[i].RunType != runType {
+		i--
+	}
+	if i < 0 {
+		return
+	}
+	for _, run := range stack[i:] {
+		run.EndTime = now
+	}
+	run := stack[i]
+	run.Outputs = outputs
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	if i > 0 {
+		t.open[key] = stack[:i]
+		return
+	}
+	delete(t.open, key)
+	t.pending.Add(1)
+	go func() {
+		defer t.pending.Done()
+		if err := t.exporter.Export(context.Background(), run); err != nil {
+			t.mu.Lock()
+			t.errs = append(t.errs, err)
+			t.mu.Unlock()
+		}
+	}()
+}
+
+// event adds an event to the innermost open run of the context.
+func (t *Tracer) event(ctx context.Context, name string, kwargs map[string]any) {
+	key := callbacks.RunIDFromContext(ctx)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stack := t.open[key]
+	if len(stack) == 0 {
+		return
+	}
+	run := stack[len(stack)-1]
+	run.Events = append(run.Events, Event{Name: name, Time: time.Now(), Kwargs: kwargs})
+}
@@ -0,0 +1,108 @@
+package callbacks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// LogHandler is a Handler logging the events of runs, with their run id if
+// any, after scrubbing the logged payloads with its scrubbers. Streamed
+// chunks are not logged.
+type LogHandler struct {
+	// Logger is the logger written to, log.Default() if nil.
+	Logger *log.Logger
+	// Scrubbers mask sensitive data in the logged payloads, see
+	// DefaultScrubbers. Payloads are logged as is if there are none.
+	Scrubbers []Scrubber
+}
+
+var _ Handler = LogHandler{}
+
+// NewLogHandler creates a handler logging to the logger, with payloads
+// scrubbed with DefaultScrubbers.
+func NewLogHandler(logger *log.Logger) LogHandler {
+	return LogHandler{Logger: logger, Scrubbers: DefaultScrubbers()}
+}
+
+func (l LogHandler) HandleLLMStart(ctx context.Context, prompts []string) {
+	l.log(ctx, "llm start", "prompts", prompts)
+}
+
+func (l LogHandler) HandleLLMEnd(ctx context.Context, output llms.LLMResult) {
+	var texts []string
+	for _, generations := range output.Generations {
+		for _, g := range generations {
+			if g != nil {
+				texts = append(texts, g.Text)
+			}
+		}
+	}
+	l.log(ctx, "llm end", "generations", texts)
+}
+
+func (l LogHandler) HandleLLMError(ctx context.Context, err error) {
+	l.log(ctx, "llm error", "error", err.Error())
+}
+
+func (l LogHandler) HandleStreamingFunc(context.Context, []byte) {}
+
+func (l LogHandler) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	l.log(ctx, "chain start", "inputs", inputs)
+}
+
+func (l LogHandler) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	l.log(ctx, "chain end", "outputs", outputs)
+}
+
+func (l LogHandler) HandleChainError(ctx context.Context, err error) {
+	l.log(ctx, "chain error", "error", err.Error())
+}
+
+func (l LogHandler) HandleToolStart(ctx context.Context, tool, input string) {
+	l.log(ctx, "tool start "+tool, "input", input)
+}
+
+func (l LogHandler) HandleToolEnd(ctx context.Context, tool, output string) {
+	l.log(ctx, "tool end "+tool, "output", output)
+}
+
+func (l LogHandler) HandleToolError(ctx context.Context, tool string, err error) {
+	l.log(ctx, "tool error "+tool, "error", err.Error())
+}
+
+func (l LogHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	l.log(ctx, "agent action "+action.Tool, "input", action.ToolInput)
+}
+
+func (l LogHandler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	l.log(ctx, "agent finish", "outputs", finish.ReturnValues)
+}
+
+// log logs the event with its payload, scrubbed once formatted so that the
+// values of any type are scrubbed.
+func (l LogHandler) log(ctx context.Context, event, name string, payload any) {
+	text := fmt.Sprintf("%v", payload)
+	if s, ok := payload.(string); ok {
+		text = s
+	}
+	for _, s := range l.Scrubbers {
+		text = s(text)
+	}
+
+	var sb strings.Builder
+	if runID := RunIDFromContext(ctx); runID != "" {
+		sb.WriteString("[" + runID + "] ")
+	}
+	fmt.Fprintf(&sb, "%s: %s=%q", event, name, text)
+
+	logger := l.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	logger.Print(sb.String())
+}
@@ -0,0 +1,80 @@
+// Package otel traces the runs of chains, agents, tools, LLMs and retrievers
+// as OpenTelemetry spans, so that langchaingo shows up in existing distributed
+// traces. LLM spans carry the model, token usage and latency of the calls.
+//
+// The Handler creates its spans with a Tracer, which keeps this package free
+// of the OpenTelemetry dependencies. The adapter of a trace.Tracer of
+// go.opentelemetry.io/otel, with this package imported as lotel, is:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attrs ...lotel.Attribute) (context.Context, lotel.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name, trace.WithAttributes(keyValues(attrs)...))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttributes(attrs ...lotel.Attribute) { s.Span.SetAttributes(keyValues(attrs)...) }
+//
+//	func (s otelSpan) AddEvent(name string, attrs ...lotel.Attribute) {
+//		s.Span.AddEvent(name, trace.WithAttributes(keyValues(attrs)...))
+//	}
+//
+//	func (s otelSpan) RecordError(err error) {
+//		s.Span.RecordError(err)
+//		s.Span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s otelSpan) End() { s.Span.End() }
+//
+//	func keyValues(attrs []lotel.Attribute) []attribute.KeyValue {
+//		kvs := make([]attribute.KeyValue, 0, len(attrs))
+//		for _, a := range attrs {
+//			switch v := a.Value.(type) {
+//			case string:
+//				kvs = append(kvs, attribute.String(a.Key, v))
+//			case bool:
+//				kvs = append(kvs, attribute.Bool(a.Key, v))
+//			case int:
+//				kvs = append(kvs, attribute.Int(a.Key, v))
+//			case int64:
+//				kvs = append(kvs, attribute.Int64(a.Key, v))
+//			case float64:
+//				kvs = append(kvs, attribute.Float64(a.Key, v))
+//			case []string:
+//				kvs = append(kvs, attribute.StringSlice(a.Key, v))
+//			}
+//		}
+//		return kvs
+//	}
+//
+// and the propagator of its spans, set with WithPropagator, is:
+//
+//	type otelPropagator struct{}
+//
+//	func (otelPropagator) Inject(ctx context.Context, header http.Header) {
+//		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+//	}
+//
+// # Context propagation
+//
+// Callbacks can not change the context of the calls they observe, so the
+// spans of the handler are not in the contexts given to the providers and
+// tools. The parent of the spans is threaded through as follows:
+//
+//   - The first span of a run is started with the context of the callback,
+//     and is a child of the span of the context, if any: start the request
+//     span of the application before calling the chain or agent with its
+//     context.
+//   - The spans of a run opened later are children of its innermost open
+//     span. Runs are told apart by the run ids of their contexts, see
+//     callbacks.WithRunID; concurrent runs must have distinct run ids.
+//   - The HTTP requests of the providers carry the trace context of the
+//     innermost open span of their run, the span of the LLM call, when the
+//     transport hook of the handler is set on the calls with
+//     llms.WithTransportHook. The context given to the propagator is the one
+//     returned by the Tracer when starting the span. The default propagator,
+//     TraceContext, sets the W3C traceparent header for the spans
+//     implementing SpanContexter.
+package otel
@@ -0,0 +1,305 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Attribute keys set on the spans.
+const (
+	AttributeRunID            = "langchaingo.run_id"
+	AttributeSpanKind         = "langchaingo.span.kind"
+	AttributeInputKeys        = "langchaingo.chain.input_keys"
+	AttributeOutputKeys       = "langchaingo.chain.output_keys"
+	AttributeInputs           = "langchaingo.inputs"
+	AttributeOutputs          = "langchaingo.outputs"
+	AttributeToolName         = "langchaingo.tool.name"
+	AttributeModel            = "gen_ai.response.model"
+	AttributeFinishReasons    = "gen_ai.response.finish_reasons"
+	AttributePromptTokens     = "gen_ai.usage.prompt_tokens"
+	AttributeCompletionTokens = "gen_ai.usage.completion_tokens"
+	AttributeTotalTokens      = "gen_ai.usage.total_tokens"
+	AttributeCostUSD          = "langchaingo.llm.cost_usd"
+	AttributeLatencyMs        = "langchaingo.llm.latency_ms"
+	AttributeDocuments        = "langchaingo.retriever.documents"
+)
+
+// Span kinds, the values of the AttributeSpanKind attribute.
+const (
+	KindChain     = "chain"
+	KindLLM       = "llm"
+	KindTool      = "tool"
+	KindRetriever = "retriever"
+)
+
+// Attribute is an attribute of a span or event. Values are strings, bools,
+// ints, int64s, float64s or slices of strings.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Tracer starts spans, such as an adapter of an OpenTelemetry trace.Tracer.
+type Tracer interface {
+	// Start starts a span, child of the span of the context if any, and
+	// returns a context carrying it.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	AddEvent(name string, attrs ...Attribute)
+	// RecordError records the error and marks the span as failed.
+	RecordError(err error)
+	End()
+}
+
+// Handler is a callbacks.Handler tracing the runs it observes as spans. It is
+// safe for concurrent use.
+type Handler struct {
+	tracer         Tracer
+	recordPayloads bool
+	propagator     Propagator
+
+	mu   sync.Mutex
+	open map[string][]openSpan // Open spans by the run id of their context.
+}
+
+type openSpan struct {
+	kind string
+	ctx  context.Context //nolint:containedctx
+	span Span
+}
+
+var _ callbacks.Handler = (*Handler)(nil)
+
+// Option is a function that configures a Handler.
+type Option func(h *Handler)
+
+// WithRecordPayloads records the prompts, generations, inputs and outputs of
+// the runs as span attributes. They are not recorded by default, as they may
+// hold personal data; see callbacks.RedactingHandler to mask it.
+func WithRecordPayloads(record bool) Option {
+	return func(h *Handler) {
+		h.recordPayloads = record
+	}
+}
+
+// WithPropagator sets the propagator of the trace context of the spans to the
+// requests sent with the transport hook of the handler. Defaults to
+// TraceContext.
+func WithPropagator(propagator Propagator) Option {
+	return func(h *Handler) {
+		h.propagator = propagator
+	}
+}
+
+// NewHandler creates a handler tracing runs with the tracer.
+func NewHandler(tracer Tracer, opts ...Option) *Handler {
+	h := &Handler{tracer: tracer, propagator: TraceContext{}, open: make(map[string][]openSpan)}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Handler) HandleLLMStart(ctx context.Context, prompts []string) {
+	var attrs []Attribute
+	if h.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeInputs, Value: prompts})
+	}
+	h.start(ctx, KindLLM, "llm", attrs...)
+}
+
+func (h *Handler) HandleLLMEnd(ctx context.Context, output llms.LLMResult) {
+	var (
+		usage         llms.Usage
+		model         string
+		finishReasons []string
+		texts         []string
+		latencyMs     float64
+	)
+	for _, generations := range output.Generations {
+		for _, g := range generations {
+			if g == nil {
+				continue
+			}
+			m := g.ResponseMetadata()
+			usage.Add(m.Usage)
+			if model == "" {
+				model = m.Model
+			}
+			if m.FinishReason != "" {
+				finishReasons = append(finishReasons, m.FinishReason)
+			}
+			latencyMs += float64(m.Latency.Microseconds()) / 1000
+			texts = append(texts, g.Text)
+		}
+	}
+
+	attrs := []Attribute{
+		{Key: AttributePromptTokens, Value: usage.PromptTokens},
+		{Key: AttributeCompletionTokens, Value: usage.CompletionTokens},
+		{Key: AttributeTotalTokens, Value: usage.TotalTokens},
+	}
+	if model != "" {
+		attrs = append(attrs, Attribute{Key: AttributeModel, Value: model})
+	}
+	if finishReasons != nil {
+		attrs = append(attrs, Attribute{Key: AttributeFinishReasons, Value: finishReasons})
+	}
+	if usage.CostUSD != 0 {
+		attrs = append(attrs, Attribute{Key: AttributeCostUSD, Value: usage.CostUSD})
+	}
+	if latencyMs != 0 {
+		attrs = append(attrs, Attribute{Key: AttributeLatencyMs, Value: latencyMs})
+	}
+	if h.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeOutputs, Value: texts})
+	}
+	h.end(ctx, KindLLM, nil, attrs...)
+}
+
+func (h *Handler) HandleLLMError(ctx context.Context, err error) {
+	h.end(ctx, KindLLM, err)
+}
+
+func (h *Handler) HandleStreamingFunc(context.Context, []byte) {}
+
+func (h *Handler) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	attrs := []Attribute{{Key: AttributeInputKeys, Value: sortedKeys(inputs)}}
+	if h.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeInputs, Value: fmt.Sprint(inputs)})
+	}
+	h.start(ctx, KindChain, "chain", attrs...)
+}
+
+func (h *Handler) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	attrs := []Attribute{{Key: AttributeOutputKeys, Value: sortedKeys(outputs)}}
+	if h.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeOutputs, Value: fmt.Sprint(outputs)})
+	}
+	h.end(ctx, KindChain, nil, attrs...)
+}
+
+func (h *Handler) HandleChainError(ctx context.Context, err error) {
+	h.end(ctx, KindChain, err)
+}
+
+func (h *Handler) HandleToolStart(ctx context.Context, tool, input string) {
+	attrs := []Attribute{{Key: AttributeToolName, Value: tool}}
+	if h.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeInputs, Value: input})
+	}
+	h.start(ctx, KindTool, "tool "+tool, attrs...)
+}
+
+func (h *Handler) HandleToolEnd(ctx context.Context, _, output string) {
+	var attrs []Attribute
+	if h.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeOutputs, Value: output})
+	}
+	h.end(ctx, KindTool, nil, attrs...)
+}
+
+func (h *Handler) HandleToolError(ctx context.Context, _ string, err error) {
+	h.end(ctx, KindTool, err)
+}
+
+func (h *Handler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	attrs := []Attribute{{Key: AttributeToolName, Value: action.Tool}}
+	if h.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeInputs, Value: action.ToolInput})
+	}
+	h.event(ctx, "agent_action", attrs...)
+}
+
+func (h *Handler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	attrs := []Attribute{{Key: AttributeOutputKeys, Value: sortedKeys(finish.ReturnValues)}}
+	if h.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeOutputs, Value: fmt.Sprint(finish.ReturnValues)})
+	}
+	h.event(ctx, "agent_finish", attrs...)
+}
+
+// start starts a span, child of the innermost open span of the run of the
+// context, or of the span of the context if the run has none.
+func (h *Handler) start(ctx context.Context, kind, name string, attrs ...Attribute) {
+	key := callbacks.RunIDFromContext(ctx)
+	attrs = append(attrs, Attribute{Key: AttributeSpanKind, Value: kind})
+	if key != "" {
+		attrs = append(attrs, Attribute{Key: AttributeRunID, Value: key})
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stack := h.open[key]
+	parent := ctx
+	if len(stack) > 0 {
+		parent = stack[len(stack)-1].ctx
+	}
+	spanCtx, span := h.tracer.Start(parent, name, attrs...)
+	h.open[key] = append(stack, openSpan{kind: kind, ctx: contextWithSpan(spanCtx, span), span: span})
+}
+
+// end ends the innermost open span of the kind, and the spans started in it
+// which were not ended.
+func (h *Handler) end(ctx context.Context, kind string, err error, attrs ...Attribute) {
+	key := callbacks.RunIDFromContext(ctx)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stack := h.open[key]
+	i := len(stack) - 1
+	for i >= 0 && stack[i].kind != kind {
+		i--
+	}
+	if i < 0 {
+		return
+	}
+	for j := len(stack) - 1; j > i; j-- {
+		stack[j].span.End()
+	}
+	span := stack[i].span
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+
+	if i > 0 {
+		h.open[key] = stack[:i]
+		return
+	}
+	delete(h.open, key)
+}
+
+// event adds an event to the innermost open span of the run of the context.
+func (h *Handler) event(ctx context.Context, name string, attrs ...Attribute) {
+	key := callbacks.RunIDFromContext(ctx)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stack := h.open[key]
+	if len(stack) == 0 {
+		return
+	}
+	stack[len(stack)-1].span.AddEvent(name, attrs...)
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
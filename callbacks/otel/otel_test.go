@@ -0,0 +1,246 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type testSpanKey struct{}
+
+// testSpan records what is done to it.
+type testSpan struct {
+	name   string
+	parent *testSpan
+	attrs  map[string]any
+	events []string
+	err    error
+	ended  bool
+	id     byte
+}
+
+func (s *testSpan) SpanContext() SpanContext {
+	return SpanContext{TraceID: [16]byte{15: 1}, SpanID: [8]byte{7: s.id}, Sampled: true}
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *testSpan) AddEvent(name string, _ ...Attribute) { s.events = append(s.events, name) }
+func (s *testSpan) RecordError(err error)                { s.err = err }
+func (s *testSpan) End()                                 { s.ended = true }
+
+// testTracer records the spans it starts, children of the span of their
+// context.
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{name: name, parent: parent, attrs: map[string]any{}, id: byte(len(t.spans) + 1)}
+	span.SetAttributes(attrs...)
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	tracer := &testTracer{}
+	h := NewHandler(tracer)
+	app, appSpan := tracer.Start(context.Background(), "http request")
+	ctx := callbacks.WithRunID(app, "run-1")
+
+	h.HandleChainStart(ctx, map[string]any{"input": "hi", "history": ""})
+	h.HandleAgentAction(ctx, schema.AgentAction{Tool: "search", ToolInput: "hi"})
+	h.HandleToolStart(ctx, "search", "hi")
+	h.HandleToolError(ctx, "search", errors.New("no results"))
+	h.HandleLLMStart(ctx, []string{"hi"})
+	h.HandleLLMEnd(ctx, llms.LLMResult{Generations: [][]*llms.Generation{{{
+		Text: "hello",
+		Metadata: llms.NewResponseMetadata("gpt-4-0613", "stop", llms.Usage{PromptTokens: 1000, CompletionTokens: 500},
+			1500*time.Millisecond, nil),
+	}}}})
+	h.HandleChainEnd(ctx, map[string]any{"output": "hello"})
+
+	spans := tracer.spans
+	require.Len(t, spans, 4)
+	chain, tool, llm := spans[1], spans[2], spans[3]
+	assert.Same(t, appSpan, chain.parent, "first span of a run is a child of the span of the context")
+	assert.Same(t, chain, tool.parent)
+	assert.Same(t, chain, llm.parent)
+	for _, s := range spans[1:] {
+		assert.True(t, s.ended, s.name)
+		assert.Equal(t, "run-1", s.attrs[AttributeRunID])
+	}
+
+	assert.Equal(t, []string{"history", "input"}, chain.attrs[AttributeInputKeys])
+	assert.Equal(t, []string{"output"}, chain.attrs[AttributeOutputKeys])
+	assert.Equal(t, []string{"agent_action"}, chain.events)
+	assert.NotContains(t, chain.attrs, AttributeInputs, "payloads are not recorded by default")
+
+	assert.Equal(t, "tool search", tool.name)
+	assert.EqualError(t, tool.err, "no results")
+
+	assert.Equal(t, KindLLM, llm.attrs[AttributeSpanKind])
+	assert.Equal(t, "gpt-4-0613", llm.attrs[AttributeModel])
+	assert.Equal(t, 1000, llm.attrs[AttributePromptTokens])
+	assert.Equal(t, 1500, llm.attrs[AttributeTotalTokens])
+	assert.Equal(t, []string{"stop"}, llm.attrs[AttributeFinishReasons])
+	assert.Equal(t, 1500.0, llm.attrs[AttributeLatencyMs])
+	assert.InDelta(t, 0.06, llm.attrs[AttributeCostUSD], 1e-9)
+}
+
+func TestHandlerEndsUnclosedSpans(t *testing.T) {
+	t.Parallel()
+
+	tracer := &testTracer{}
+	h := NewHandler(tracer, WithRecordPayloads(true))
+	ctx := context.Background()
+
+	h.HandleChainStart(ctx, map[string]any{"input": "hi"})
+	h.HandleLLMStart(ctx, []string{"hi"})
+	h.HandleChainError(ctx, errors.New("boom"))
+
+	require.Len(t, tracer.spans, 2)
+	assert.True(t, tracer.spans[1].ended)
+	assert.EqualError(t, tracer.spans[0].err, "boom")
+	assert.Equal(t, []string{"hi"}, tracer.spans[1].attrs[AttributeInputs])
+
+	// Ends of runs that were not started are ignored.
+	h.HandleToolEnd(ctx, "search", "")
+	require.Len(t, tracer.spans, 2)
+}
+
+func TestHandlerTransportHook(t *testing.T) {
+	t.Parallel()
+
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+	}))
+	defer server.Close()
+
+	tracer := &testTracer{}
+	h := NewHandler(tracer)
+	// Providers send their requests through a HookDoer, with the context of
+	// the call carrying the hook, and the run id of the callbacks.
+	opts := llms.CallOptions{}
+	llms.WithTransportHook(h.TransportHook())(&opts)
+	doer := llms.HookDoer(server.Client())
+	send := func(ctx context.Context) {
+		req, err := http.NewRequestWithContext(llms.ApplyTransportHook(ctx, opts), http.MethodPost, server.URL, nil)
+		require.NoError(t, err)
+		res, err := doer.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+	}
+
+	ctx := callbacks.WithRunID(context.Background(), "run-1")
+	h.HandleChainStart(ctx, map[string]any{})
+	h.HandleLLMStart(ctx, []string{"hi"})
+	send(ctx)
+	h.HandleLLMEnd(ctx, llms.LLMResult{})
+	send(ctx)
+	h.HandleChainEnd(ctx, map[string]any{})
+	send(ctx)
+
+	require.Len(t, tracer.spans, 2)
+	assert.Equal(t, []string{
+		"00-00000000000000000000000000000001-0000000000000002-01",
+		"00-00000000000000000000000000000001-0000000000000001-01",
+		"",
+	}, traceparents, "the innermost open span of the run is propagated")
+}
+
+type propagatorFunc func(ctx context.Context, header http.Header)
+
+func (f propagatorFunc) Inject(ctx context.Context, header http.Header) { f(ctx, header) }
+
+func TestHandlerPropagator(t *testing.T) {
+	t.Parallel()
+
+	tracer := &testTracer{}
+	var injected []Span
+	h := NewHandler(tracer, WithPropagator(propagatorFunc(func(ctx context.Context, header http.Header) {
+		// The context is the one returned by the tracer, such as a context
+		// carrying an OpenTelemetry span.
+		require.NotNil(t, ctx.Value(testSpanKey{}))
+		injected = append(injected, SpanFromContext(ctx))
+		header.Set("X-Span", "set")
+	})))
+
+	hook := h.TransportHook()
+	next := func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: req.Header}, nil
+	}
+	ctx := context.Background()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	require.NoError(t, err)
+
+	res, err := hook(req, next)
+	require.NoError(t, err)
+	assert.Empty(t, res.Header.Get("X-Span"), "requests outside of runs are not changed")
+
+	h.HandleToolStart(ctx, "search", "hi")
+	res, err = hook(req, next)
+	require.NoError(t, err)
+	assert.Equal(t, "set", res.Header.Get("X-Span"))
+	assert.Empty(t, req.Header.Get("X-Span"), "the request is cloned")
+	require.Len(t, injected, 1)
+	assert.Same(t, tracer.spans[0], injected[0])
+}
+
+func TestTraceContext(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	TraceContext{}.Inject(context.Background(), header)
+	assert.Empty(t, header)
+
+	TraceContext{}.Inject(contextWithSpan(context.Background(), &testSpan{}), header)
+	assert.Empty(t, header, "invalid span contexts are not propagated")
+
+	TraceContext{}.Inject(contextWithSpan(context.Background(), &testSpan{id: 0xab}), header)
+	assert.Equal(t, "00-00000000000000000000000000000001-00000000000000ab-01", header.Get("traceparent"))
+}
+
+type testRetriever struct{}
+
+func (testRetriever) GetRelevantDocuments(context.Context, string) ([]schema.Document, error) {
+	return []schema.Document{{PageContent: "a"}, {PageContent: "b"}}, nil
+}
+
+func TestRetriever(t *testing.T) {
+	t.Parallel()
+
+	tracer := &testTracer{}
+	docs, err := NewRetriever(tracer, testRetriever{}, WithRecordPayloads(true)).
+		GetRelevantDocuments(context.Background(), "query")
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(t, map[string]any{
+		AttributeSpanKind:  KindRetriever,
+		AttributeInputs:    "query",
+		AttributeDocuments: 2,
+	}, tracer.spans[0].attrs)
+	assert.True(t, tracer.spans[0].ended)
+}
@@ -0,0 +1,100 @@
+package otel
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Propagator injects the trace context of the span of a context into the
+// headers of a request, such as the TextMapPropagator of OpenTelemetry with a
+// propagation.HeaderCarrier.
+type Propagator interface {
+	Inject(ctx context.Context, header http.Header)
+}
+
+// SpanContext identifies a span in a distributed trace.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid reports whether the trace and span ids are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// SpanContexter is implemented by the spans knowing their SpanContext, which
+// TraceContext propagates.
+type SpanContexter interface {
+	SpanContext() SpanContext
+}
+
+// TraceContext is a Propagator setting the traceparent header of the W3C
+// trace context to the SpanContext of the span of the context, if it
+// implements SpanContexter.
+type TraceContext struct{}
+
+var _ Propagator = TraceContext{}
+
+func (TraceContext) Inject(ctx context.Context, header http.Header) {
+	sc, ok := SpanFromContext(ctx).(SpanContexter)
+	if !ok || !sc.SpanContext().IsValid() {
+		return
+	}
+	c := sc.SpanContext()
+	flags := "00"
+	if c.Sampled {
+		flags = "01"
+	}
+	header.Set("traceparent", "00-"+hex.EncodeToString(c.TraceID[:])+"-"+hex.EncodeToString(c.SpanID[:])+"-"+flags)
+}
+
+type spanKey struct{}
+
+// contextWithSpan returns a context carrying the span, for SpanFromContext.
+func contextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span of the contexts given to propagators, nil
+// for other contexts.
+func SpanFromContext(ctx context.Context) Span { //nolint:ireturn
+	span, _ := ctx.Value(spanKey{}).(Span)
+	return span
+}
+
+// TransportHook returns a transport hook propagating the innermost open span
+// of the run of the context of each request, such as the span of the LLM call
+// sending it, to the server with the propagator of the handler. Requests
+// outside of the runs traced by the handler are sent unchanged. Set it on the
+// calls with llms.WithTransportHook, so that the spans of the providers are
+// the parents of the spans of their servers.
+func (h *Handler) TransportHook() llms.TransportHook {
+	return func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+		ctx, ok := h.innermostContext(req.Context())
+		if !ok {
+			return next(req)
+		}
+		req = req.Clone(req.Context())
+		h.propagator.Inject(ctx, req.Header)
+		return next(req)
+	}
+}
+
+// innermostContext returns the context of the innermost open span of the run
+// of the context.
+func (h *Handler) innermostContext(ctx context.Context) (context.Context, bool) {
+	key := callbacks.RunIDFromContext(ctx)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stack := h.open[key]
+	if len(stack) == 0 {
+		return nil, false
+	}
+	return stack[len(stack)-1].ctx, true
+}
@@ -0,0 +1,46 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Retriever is a retriever tracing the queries of the retriever it wraps as
+// spans.
+type Retriever struct {
+	Retriever      schema.Retriever
+	tracer         Tracer
+	recordPayloads bool
+}
+
+var _ schema.Retriever = Retriever{}
+
+// NewRetriever creates a retriever tracing the queries of the retriever with
+// the tracer. WithRecordPayloads records the queries.
+func NewRetriever(tracer Tracer, retriever schema.Retriever, opts ...Option) Retriever {
+	h := Handler{}
+	for _, opt := range opts {
+		opt(&h)
+	}
+	return Retriever{Retriever: retriever, tracer: tracer, recordPayloads: h.recordPayloads}
+}
+
+// GetRelevantDocuments returns the documents of the retriever for the query,
+// in a span child of the span of the context.
+func (r Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	attrs := []Attribute{{Key: AttributeSpanKind, Value: KindRetriever}}
+	if r.recordPayloads {
+		attrs = append(attrs, Attribute{Key: AttributeInputs, Value: query})
+	}
+	ctx, span := r.tracer.Start(ctx, "retriever", attrs...)
+	defer span.End()
+
+	docs, err := r.Retriever.GetRelevantDocuments(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes(Attribute{Key: AttributeDocuments, Value: len(docs)})
+	return docs, nil
+}
@@ -0,0 +1,22 @@
+// Package prometheus exports metrics of the LLM calls, tool calls and agent
+// runs observed by a callbacks handler in the Prometheus text format, so that
+// services embedding langchaingo can alert on cost and latency:
+//
+//	metrics := prometheus.NewHandler()
+//	http.Handle("/metrics/langchaingo", metrics)
+//	executor, err := agents.Initialize(llm, tools, agents.ZeroShotReactDescription,
+//		agents.WithCallbacksHandler(metrics))
+//
+// The metrics, prefixed with the namespace, "langchaingo" by default, are:
+//
+//   - llm_requests_total, the LLM calls by model and status, success or error;
+//   - llm_tokens_total, the tokens used by model and type, prompt or
+//     completion;
+//   - llm_cost_usd_total, the estimated cost of the calls by model;
+//   - llm_request_duration_seconds, a histogram of the durations of the calls
+//     by model;
+//   - tool_calls_total, the tool calls by tool and status;
+//   - agent_iterations, a histogram of the number of actions of agent runs.
+//
+// The model of failed calls is unknown, as it is read from the responses.
+package prometheus
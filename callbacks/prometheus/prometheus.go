@@ -0,0 +1,217 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_defaultNamespace = "langchaingo"
+	_unknownModel     = "unknown"
+	_statusSuccess    = "success"
+	_statusError      = "error"
+)
+
+// nolint:gochecknoglobals
+var (
+	_defaultDurationBuckets  = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+	_defaultIterationBuckets = []float64{1, 2, 3, 5, 8, 13, 21}
+)
+
+// Handler is a callbacks.Handler counting the LLM calls, tool calls and agent
+// runs it observes, and an http.Handler serving the metrics in the Prometheus
+// text format. It is safe for concurrent use.
+type Handler struct {
+	callbacks.SimpleHandler
+
+	namespace        string
+	durationBuckets  []float64
+	iterationBuckets []float64
+
+	mu              sync.Mutex
+	llmRequests     *counterVec
+	llmTokens       *counterVec
+	llmCost         *counterVec
+	llmDuration     *histogramVec
+	toolCalls       *counterVec
+	agentIterations *histogramVec
+	llmStarts       map[string][]time.Time // Start times of open LLM calls by run id.
+	agentActions    map[string]int         // Actions of open agent runs by run id.
+}
+
+var (
+	_ callbacks.Handler = (*Handler)(nil)
+	_ http.Handler      = (*Handler)(nil)
+)
+
+// Option is a function that configures a Handler.
+type Option func(h *Handler)
+
+// WithNamespace sets the prefix of the names of the metrics. Defaults to
+// "langchaingo".
+func WithNamespace(namespace string) Option {
+	return func(h *Handler) {
+		h.namespace = namespace
+	}
+}
+
+// WithDurationBuckets sets the upper bounds, in seconds, of the buckets of
+// the histogram of the durations of LLM calls.
+func WithDurationBuckets(buckets ...float64) Option {
+	return func(h *Handler) {
+		h.durationBuckets = buckets
+	}
+}
+
+// WithIterationBuckets sets the upper bounds of the buckets of the histogram
+// of the number of actions of agent runs.
+func WithIterationBuckets(buckets ...float64) Option {
+	return func(h *Handler) {
+		h.iterationBuckets = buckets
+	}
+}
+
+// NewHandler creates a new handler with empty metrics.
+func NewHandler(opts ...Option) *Handler {
+	h := &Handler{
+		namespace:        _defaultNamespace,
+		durationBuckets:  _defaultDurationBuckets,
+		iterationBuckets: _defaultIterationBuckets,
+		llmStarts:        make(map[string][]time.Time),
+		agentActions:     make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	prefix := ""
+	if h.namespace != "" {
+		prefix = h.namespace + "_"
+	}
+	h.llmRequests = newCounterVec(prefix+"llm_requests_total", "LLM calls by model and status.", "model", "status")
+	h.llmTokens = newCounterVec(prefix+"llm_tokens_total", "Tokens used by model and type.", "model", "type")
+	h.llmCost = newCounterVec(prefix+"llm_cost_usd_total", "Estimated cost of LLM calls in US dollars.", "model")
+	h.llmDuration = newHistogramVec(prefix+"llm_request_duration_seconds", "Durations of LLM calls.",
+		h.durationBuckets, "model")
+	h.toolCalls = newCounterVec(prefix+"tool_calls_total", "Tool calls by tool and status.", "tool", "status")
+	h.agentIterations = newHistogramVec(prefix+"agent_iterations", "Actions taken by agent runs.",
+		h.iterationBuckets)
+	return h
+}
+
+func (h *Handler) HandleLLMStart(ctx context.Context, _ []string) {
+	key := callbacks.RunIDFromContext(ctx)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.llmStarts[key] = append(h.llmStarts[key], time.Now())
+}
+
+func (h *Handler) HandleLLMEnd(ctx context.Context, output llms.LLMResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	usage := make(map[string]*llms.Usage)
+	model := ""
+	for _, generations := range output.Generations {
+		for _, g := range generations {
+			if g == nil {
+				continue
+			}
+			m := g.ResponseMetadata()
+			if m.Model == "" {
+				m.Model = _unknownModel
+			}
+			if model == "" {
+				model = m.Model
+			}
+			if usage[m.Model] == nil {
+				usage[m.Model] = &llms.Usage{}
+			}
+			usage[m.Model].Add(m.Usage)
+		}
+	}
+	if model == "" {
+		model = _unknownModel
+	}
+	for m, u := range usage {
+		h.llmTokens.add(float64(u.PromptTokens), m, "prompt")
+		h.llmTokens.add(float64(u.CompletionTokens), m, "completion")
+		if u.CostUSD != 0 {
+			h.llmCost.add(u.CostUSD, m)
+		}
+	}
+	h.llmRequests.add(1, model, _statusSuccess)
+	h.observeDuration(ctx, model)
+}
+
+func (h *Handler) HandleLLMError(ctx context.Context, _ error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.llmRequests.add(1, _unknownModel, _statusError)
+	h.observeDuration(ctx, _unknownModel)
+}
+
+func (h *Handler) HandleToolEnd(_ context.Context, tool, _ string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.toolCalls.add(1, tool, _statusSuccess)
+}
+
+func (h *Handler) HandleToolError(_ context.Context, tool string, _ error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.toolCalls.add(1, tool, _statusError)
+}
+
+func (h *Handler) HandleAgentAction(ctx context.Context, _ schema.AgentAction) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.agentActions[callbacks.RunIDFromContext(ctx)]++
+}
+
+func (h *Handler) HandleAgentFinish(ctx context.Context, _ schema.AgentFinish) {
+	key := callbacks.RunIDFromContext(ctx)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.agentIterations.observe(float64(h.agentActions[key]))
+	delete(h.agentActions, key)
+}
+
+// observeDuration observes the duration of the innermost open LLM call of the
+// run of the context. h.mu must be held.
+func (h *Handler) observeDuration(ctx context.Context, model string) {
+	key := callbacks.RunIDFromContext(ctx)
+	starts := h.llmStarts[key]
+	if len(starts) == 0 {
+		return
+	}
+	h.llmDuration.observe(time.Since(starts[len(starts)-1]).Seconds(), model)
+	if len(starts) == 1 {
+		delete(h.llmStarts, key)
+		return
+	}
+	h.llmStarts[key] = starts[:len(starts)-1]
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+	h.mu.Lock()
+	h.llmRequests.write(&buf)
+	h.llmTokens.write(&buf)
+	h.llmCost.write(&buf)
+	h.llmDuration.write(&buf)
+	h.toolCalls.write(&buf)
+	h.agentIterations.write(&buf)
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
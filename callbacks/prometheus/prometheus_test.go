@@ -0,0 +1,84 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func scrape(t *testing.T, h *Handler) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(WithDurationBuckets(60), WithIterationBuckets(1, 2))
+	ctx := callbacks.WithRunID(context.Background(), "run")
+
+	h.HandleLLMStart(ctx, []string{"hi"})
+	h.HandleLLMEnd(ctx, llms.LLMResult{Generations: [][]*llms.Generation{{{
+		Text: "hello",
+		Metadata: &llms.ResponseMetadata{
+			Model: "gpt-4",
+			Usage: llms.Usage{PromptTokens: 3, CompletionTokens: 5, CostUSD: 0.25},
+		},
+	}}}})
+	h.HandleLLMStart(ctx, []string{"hi"})
+	h.HandleLLMError(ctx, errors.New("boom"))
+
+	h.HandleAgentAction(ctx, schema.AgentAction{Tool: "calculator"})
+	h.HandleToolStart(ctx, "calculator", "1+1")
+	h.HandleToolEnd(ctx, "calculator", "2")
+	h.HandleAgentAction(ctx, schema.AgentAction{Tool: "search"})
+	h.HandleToolStart(ctx, "search", `"q"`)
+	h.HandleToolError(ctx, "search", errors.New("offline"))
+	h.HandleAgentFinish(ctx, schema.AgentFinish{})
+
+	body := scrape(t, h)
+	for _, line := range []string{
+		"# TYPE langchaingo_llm_requests_total counter",
+		`langchaingo_llm_requests_total{model="gpt-4",status="success"} 1`,
+		`langchaingo_llm_requests_total{model="unknown",status="error"} 1`,
+		`langchaingo_llm_tokens_total{model="gpt-4",type="completion"} 5`,
+		`langchaingo_llm_tokens_total{model="gpt-4",type="prompt"} 3`,
+		`langchaingo_llm_cost_usd_total{model="gpt-4"} 0.25`,
+		"# TYPE langchaingo_llm_request_duration_seconds histogram",
+		`langchaingo_llm_request_duration_seconds_bucket{model="gpt-4",le="60"} 1`,
+		`langchaingo_llm_request_duration_seconds_bucket{model="gpt-4",le="+Inf"} 1`,
+		`langchaingo_llm_request_duration_seconds_count{model="unknown"} 1`,
+		`langchaingo_tool_calls_total{tool="calculator",status="success"} 1`,
+		`langchaingo_tool_calls_total{tool="search",status="error"} 1`,
+		`langchaingo_agent_iterations_bucket{le="1"} 0`,
+		`langchaingo_agent_iterations_bucket{le="2"} 1`,
+		`langchaingo_agent_iterations_bucket{le="+Inf"} 1`,
+		"langchaingo_agent_iterations_sum 2",
+		"langchaingo_agent_iterations_count 1",
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.Empty(t, h.llmStarts)
+	assert.Empty(t, h.agentActions)
+}
+
+func TestHandlerNamespaceAndEscaping(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(WithNamespace("svc"))
+	h.HandleToolEnd(context.Background(), "say \"hi\"\\\n", "")
+	assert.Contains(t, scrape(t, h), `svc_tool_calls_total{tool="say \"hi\"\\\n",status="success"} 1`)
+}
@@ -0,0 +1,275 @@
+package callbacks
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Scrubber masks sensitive data, such as secrets and personal information, in
+// a text.
+type Scrubber func(text string) string
+
+// RegexScrubber returns a scrubber replacing the matches of the regular
+// expression with the replacement, which can refer to submatches as in
+// regexp.Regexp.ReplaceAllString.
+func RegexScrubber(re *regexp.Regexp, replacement string) Scrubber {
+	return func(text string) string {
+		return re.ReplaceAllString(text, replacement)
+	}
+}
+
+var (
+	_emailRegexp  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	_secretRegexp = regexp.MustCompile(
+		`\b(sk-[A-Za-z0-9_-]{20,}|sk-ant-[A-Za-z0-9_-]{20,}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|xox[abprs]-[A-Za-z0-9-]{10,}|hf_[A-Za-z0-9]{30,})\b`) //nolint:lll
+	_bearerRegexp = regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9._~+/-]{8,}=*`)
+)
+
+// ScrubEmails masks email addresses as [EMAIL].
+func ScrubEmails(text string) string {
+	return _emailRegexp.ReplaceAllString(text, "[EMAIL]")
+}
+
+// ScrubSecrets masks the API keys of OpenAI, Anthropic, AWS, GitHub, Slack and
+// Hugging Face, and bearer tokens, as [SECRET].
+func ScrubSecrets(text string) string {
+	text = _secretRegexp.ReplaceAllString(text, "[SECRET]")
+	return _bearerRegexp.ReplaceAllString(text, "${1}[SECRET]")
+}
+
+// DefaultScrubbers are the scrubbers used by handlers given none: ScrubSecrets
+// and ScrubEmails.
+func DefaultScrubbers() []Scrubber {
+	return []Scrubber{ScrubSecrets, ScrubEmails}
+}
+
+// RedactingHandler is a Handler scrubbing the prompts, generations, inputs,
+// outputs, errors and agent logs of the events before passing them to the
+// handler it wraps, so that sensitive data does not reach logs or tracing
+// exports. Strings, chat messages and documents are scrubbed, including in
+// the maps and slices of chain inputs and outputs. Values of other types are
+// passed unchanged.
+type RedactingHandler struct {
+	Handler   Handler
+	Scrubbers []Scrubber
+}
+
+var _ Handler = RedactingHandler{}
+
+// NewRedactingHandler creates a handler scrubbing the events passed to the
+// handler with the scrubbers, or with DefaultScrubbers if none are given.
+func NewRedactingHandler(handler Handler, scrubbers ...Scrubber) RedactingHandler {
+	if len(scrubbers) == 0 {
+		scrubbers = DefaultScrubbers()
+	}
+	return RedactingHandler{Handler: handler, Scrubbers: scrubbers}
+}
+
+func (r RedactingHandler) HandleLLMStart(ctx context.Context, prompts []string) {
+	r.Handler.HandleLLMStart(ctx, r.strings(prompts))
+}
+
+func (r RedactingHandler) HandleLLMEnd(ctx context.Context, output llms.LLMResult) {
+	redacted := llms.LLMResult{
+		Generations: make([][]*llms.Generation, len(output.Generations)),
+		LLMOutput:   output.LLMOutput,
+	}
+	for i, generations := range output.Generations {
+		for _, g := range generations {
+			if g == nil {
+				redacted.Generations[i] = append(redacted.Generations[i], nil)
+				continue
+			}
+			c := *g
+			c.Text = r.scrub(g.Text)
+			if g.Message != nil {
+				m := r.message(*g.Message).(schema.AIChatMessage) //nolint:forcetypeassert
+				c.Message = &m
+			}
+			redacted.Generations[i] = append(redacted.Generations[i], &c)
+		}
+	}
+	r.Handler.HandleLLMEnd(ctx, redacted)
+}
+
+func (r RedactingHandler) HandleLLMError(ctx context.Context, err error) {
+	r.Handler.HandleLLMError(ctx, r.error(err))
+}
+
+func (r RedactingHandler) HandleStreamingFunc(ctx context.Context, chunk []byte) {
+	r.Handler.HandleStreamingFunc(ctx, []byte(r.scrub(string(chunk))))
+}
+
+func (r RedactingHandler) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	r.Handler.HandleChainStart(ctx, r.values(inputs))
+}
+
+func (r RedactingHandler) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	r.Handler.HandleChainEnd(ctx, r.values(outputs))
+}
+
+func (r RedactingHandler) HandleChainError(ctx context.Context, err error) {
+	r.Handler.HandleChainError(ctx, r.error(err))
+}
+
+func (r RedactingHandler) HandleToolStart(ctx context.Context, tool, input string) {
+	r.Handler.HandleToolStart(ctx, tool, r.scrub(input))
+}
+
+func (r RedactingHandler) HandleToolEnd(ctx context.Context, tool, output string) {
+	r.Handler.HandleToolEnd(ctx, tool, r.scrub(output))
+}
+
+func (r RedactingHandler) HandleToolError(ctx context.Context, tool string, err error) {
+	r.Handler.HandleToolError(ctx, tool, r.error(err))
+}
+
+func (r RedactingHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	action.ToolInput = r.scrub(action.ToolInput)
+	action.Log = r.scrub(action.Log)
+	r.Handler.HandleAgentAction(ctx, action)
+}
+
+func (r RedactingHandler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	finish.ReturnValues = r.values(finish.ReturnValues)
+	finish.Log = r.scrub(finish.Log)
+	r.Handler.HandleAgentFinish(ctx, finish)
+}
+
+func (r RedactingHandler) scrub(text string) string {
+	for _, s := range r.Scrubbers {
+		text = s(text)
+	}
+	return text
+}
+
+func (r RedactingHandler) strings(texts []string) []string {
+	if texts == nil {
+		return nil
+	}
+	scrubbed := make([]string, len(texts))
+	for i, text := range texts {
+		scrubbed[i] = r.scrub(text)
+	}
+	return scrubbed
+}
+
+func (r RedactingHandler) values(values map[string]any) map[string]any {
+	if values == nil {
+		return nil
+	}
+	scrubbed := make(map[string]any, len(values))
+	for key, v := range values {
+		scrubbed[key] = r.value(v)
+	}
+	return scrubbed
+}
+
+func (r RedactingHandler) value(v any) any {
+	switch v := v.(type) {
+	case string:
+		return r.scrub(v)
+	case []string:
+		return r.strings(v)
+	case map[string]any:
+		return r.values(v)
+	case []any:
+		scrubbed := make([]any, len(v))
+		for i, e := range v {
+			scrubbed[i] = r.value(e)
+		}
+		return scrubbed
+	case schema.ChatMessage:
+		return r.message(v)
+	case []schema.ChatMessage:
+		scrubbed := make([]schema.ChatMessage, len(v))
+		for i, m := range v {
+			scrubbed[i] = r.message(m)
+		}
+		return scrubbed
+	case schema.Document:
+		return r.document(v)
+	case []schema.Document:
+		scrubbed := make([]schema.Document, len(v))
+		for i, doc := range v {
+			scrubbed[i] = r.document(doc)
+		}
+		return scrubbed
+	}
+	return v
+}
+
+// message returns a copy of the message of the same type with its content
+// scrubbed. Messages of other types become generic messages.
+func (r RedactingHandler) message(m schema.ChatMessage) schema.ChatMessage { //nolint:ireturn
+	switch m := m.(type) {
+	case schema.AIChatMessage:
+		m.Content = r.scrub(m.Content)
+		if m.FunctionCall != nil {
+			call := *m.FunctionCall
+			call.Arguments = r.value(call.Arguments)
+			m.FunctionCall = &call
+		}
+		toolCalls := make([]schema.ToolCall, 0, len(m.ToolCalls))
+		for _, tc := range m.ToolCalls {
+			if tc.FunctionCall != nil {
+				call := *tc.FunctionCall
+				call.Arguments = r.value(call.Arguments)
+				tc.FunctionCall = &call
+			}
+			toolCalls = append(toolCalls, tc)
+		}
+		if m.ToolCalls != nil {
+			m.ToolCalls = toolCalls
+		}
+		return m
+	case schema.HumanChatMessage:
+		m.Content = r.scrub(m.Content)
+		parts := make([]schema.ContentPart, 0, len(m.Parts))
+		for _, p := range m.Parts {
+			p.Text = r.scrub(p.Text)
+			parts = append(parts, p)
+		}
+		if m.Parts != nil {
+			m.Parts = parts
+		}
+		return m
+	case schema.SystemChatMessage:
+		m.Content = r.scrub(m.Content)
+		return m
+	case schema.GenericChatMessage:
+		m.Content = r.scrub(m.Content)
+		return m
+	case schema.FunctionChatMessage:
+		m.Content = r.scrub(m.Content)
+		return m
+	case schema.ToolChatMessage:
+		m.Content = r.scrub(m.Content)
+		return m
+	}
+	return schema.GenericChatMessage{Role: string(m.GetType()), Content: r.scrub(m.GetContent())}
+}
+
+func (r RedactingHandler) document(doc schema.Document) schema.Document {
+	return schema.Document{PageContent: r.scrub(doc.PageContent), Metadata: r.values(doc.Metadata)}
+}
+
+// error returns an error with the message of err scrubbed, which errors.Is and
+// errors.As still match against err.
+func (r RedactingHandler) error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return redactedError{msg: r.scrub(err.Error()), err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e redactedError) Error() string { return e.msg }
+func (e redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,19 @@
+package callbacks
+
+import "context"
+
+type runIDKey struct{}
+
+// WithRunID returns a copy of the context carrying the id of the run it
+// belongs to, so that handlers and tools can tell the runs apart. Runs of
+// chains.RunManager carry their id.
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+// RunIDFromContext returns the id of the run of the context, or an empty
+// string if it has none.
+func RunIDFromContext(ctx context.Context) string {
+	runID, _ := ctx.Value(runIDKey{}).(string)
+	return runID
+}
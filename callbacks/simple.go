@@ -0,0 +1,27 @@
+package callbacks
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// SimpleHandler is a Handler doing nothing. Embed it in handlers only
+// interested in some of the events.
+type SimpleHandler struct{}
+
+var _ Handler = SimpleHandler{}
+
+func (SimpleHandler) HandleLLMStart(context.Context, []string)              {}
+func (SimpleHandler) HandleLLMEnd(context.Context, llms.LLMResult)          {}
+func (SimpleHandler) HandleLLMError(context.Context, error)                 {}
+func (SimpleHandler) HandleStreamingFunc(context.Context, []byte)           {}
+func (SimpleHandler) HandleChainStart(context.Context, map[string]any)      {}
+func (SimpleHandler) HandleChainEnd(context.Context, map[string]any)        {}
+func (SimpleHandler) HandleChainError(context.Context, error)               {}
+func (SimpleHandler) HandleToolStart(context.Context, string, string)       {}
+func (SimpleHandler) HandleToolEnd(context.Context, string, string)         {}
+func (SimpleHandler) HandleToolError(context.Context, string, error)        {}
+func (SimpleHandler) HandleAgentAction(context.Context, schema.AgentAction) {}
+func (SimpleHandler) HandleAgentFinish(context.Context, schema.AgentFinish) {}
@@ -0,0 +1,199 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// BatchResult is the result of a chain call for an input of Batch.
+type BatchResult struct {
+	Outputs map[string]any
+	Err     error
+}
+
+// BatchOption is a function type that can be used to modify a call to Batch.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	maxConcurrency int
+	coalesceSize   int
+	callOptions    []ChainCallOption
+}
+
+// WithBatchConcurrency is an option for setting the maximum number of chain
+// calls made concurrently. It defaults to 5.
+func WithBatchConcurrency(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.maxConcurrency = n
+	}
+}
+
+// WithBatchCoalescing is an option for making llm chains send up to size
+// prompts in each call to their llm, for providers handling multiple prompts
+// in one request. Coalesced calls skip the memory and callbacks of the chain.
+// It has no effect on other chains.
+func WithBatchCoalescing(size int) BatchOption {
+	return func(o *batchOptions) {
+		o.coalesceSize = size
+	}
+}
+
+// WithBatchCallOptions is an option for setting the options of the chain calls.
+func WithBatchCallOptions(options ...ChainCallOption) BatchOption {
+	return func(o *batchOptions) {
+		o.callOptions = append(o.callOptions, options...)
+	}
+}
+
+// Batch calls the chain with each of the inputs, with bounded concurrency. The
+// results are in the order of the inputs and hold the error of each call. The
+// returned error joins the errors of the failed calls, annotated with the
+// index of their input.
+func Batch(ctx context.Context, c Chain, inputs []map[string]any, options ...BatchOption) ([]BatchResult, error) {
+	opts := batchOptions{maxConcurrency: _defaultApplyMaxNumberWorkers}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.maxConcurrency <= 0 {
+		opts.maxConcurrency = _defaultApplyMaxNumberWorkers
+	}
+
+	// Each job is a group of inputs, of one input unless coalescing.
+	groupSize := 1
+	llmChain, coalesce := asLLMChain(c)
+	if coalesce && opts.coalesceSize > 1 {
+		groupSize = opts.coalesceSize
+	} else {
+		coalesce = false
+	}
+
+	results := make([]BatchResult, len(inputs))
+	sem := make(chan struct{}, opts.maxConcurrency)
+	var wg sync.WaitGroup
+	for start := 0; start < len(inputs); start += groupSize {
+		end := start + groupSize
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		if !acquire(ctx, sem) {
+			for i := start; i < len(inputs); i++ {
+				results[i].Err = ctx.Err()
+			}
+			break
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() { <-sem; wg.Done() }()
+			if coalesce {
+				callCoalesced(ctx, llmChain, inputs[start:end], results[start:end], opts.callOptions)
+				return
+			}
+			for i := start; i < end; i++ {
+				results[i].Outputs, results[i].Err = Call(ctx, c, inputs[i], opts.callOptions...)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return results, batchError(results)
+}
+
+// acquire takes a slot of the semaphore, returning false if the context is
+// done first.
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func asLLMChain(c Chain) (*LLMChain, bool) {
+	switch c := c.(type) {
+	case *LLMChain:
+		return c, true
+	case LLMChain:
+		return &c, true
+	}
+	return nil, false
+}
+
+// callCoalesced calls the llm of the chain once with the prompts of all the
+// inputs.
+func callCoalesced(ctx context.Context, c *LLMChain, inputs []map[string]any, results []BatchResult, options []ChainCallOption) { //nolint:lll
+	promptValues := make([]schema.PromptValue, 0, len(inputs))
+	indexes := make([]int, 0, len(inputs))
+	for i, input := range inputs {
+		if err := validateInputs(c, input); err != nil {
+			results[i].Err = err
+			continue
+		}
+		promptValue, err := c.Prompt.FormatPrompt(input)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		promptValues = append(promptValues, promptValue)
+		indexes = append(indexes, i)
+	}
+	if len(promptValues) == 0 {
+		return
+	}
+
+	err := llms.CheckSessionBudget(ctx)
+	var result llms.LLMResult
+	if err == nil {
+		result, err = c.LLM.GeneratePrompt(ctx, promptValues, getLLMCallOptions(options...)...)
+	}
+	if err == nil {
+		llms.TrackUsage(ctx, result)
+	}
+	for n, i := range indexes {
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		text, ok := generationText(result.Generations, n, len(promptValues))
+		if !ok {
+			results[i].Err = fmt.Errorf("%w: no generation for prompt %d", ErrInvalidOutputValues, n)
+			continue
+		}
+		parsed, err := c.OutputParser.ParseWithPrompt(text, promptValues[n])
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Outputs = map[string]any{c.OutputKey: parsed}
+	}
+}
+
+// generationText returns the text generated for the nth of the prompts. The
+// generations are either a row per prompt or a single row with a generation
+// per prompt.
+func generationText(generations [][]*llms.Generation, n, prompts int) (string, bool) {
+	switch {
+	case len(generations) == prompts && len(generations[n]) > 0:
+		return generations[n][0].Text, true
+	case len(generations) == 1 && len(generations[0]) == prompts:
+		return generations[0][n].Text, true
+	}
+	return "", false
+}
+
+func batchError(results []BatchResult) error {
+	var errs []error
+	for i, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("input %d: %w", i, r.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,102 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var errBadTopic = errors.New("bad topic")
+
+// echoLanguageModel echoes the prompts given to it, failing on prompts
+// containing "bad", and records the number of prompts of each call.
+type echoLanguageModel struct {
+	mu    sync.Mutex
+	calls []int
+}
+
+func (l *echoLanguageModel) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	l.mu.Lock()
+	l.calls = append(l.calls, len(promptValues))
+	l.mu.Unlock()
+
+	generations := make([]*llms.Generation, 0, len(promptValues))
+	for _, p := range promptValues {
+		if strings.Contains(p.String(), "bad") {
+			return llms.LLMResult{}, errBadTopic
+		}
+		generations = append(generations, &llms.Generation{Text: p.String()})
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{generations}}, nil
+}
+
+func (l *echoLanguageModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	llm := &echoLanguageModel{}
+	c := NewLLMChain(llm, prompts.NewPromptTemplate("about {{.topic}}", []string{"topic"}))
+	results, err := Batch(context.Background(), c, []map[string]any{
+		{"topic": "otters"},
+		{"topic": "bad"},
+		{"other": "x"},
+		{"topic": "owls"},
+	}, WithBatchConcurrency(2))
+	require.Error(t, err)
+	require.ErrorIs(t, err, errBadTopic)
+	require.ErrorIs(t, err, ErrMissingInputValues)
+	require.Contains(t, err.Error(), "input 1:")
+	require.Contains(t, err.Error(), "input 2:")
+
+	require.Len(t, results, 4)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, map[string]any{"text": "about otters"}, results[0].Outputs)
+	require.ErrorIs(t, results[1].Err, errBadTopic)
+	require.ErrorIs(t, results[2].Err, ErrMissingInputValues)
+	require.Equal(t, map[string]any{"text": "about owls"}, results[3].Outputs)
+	require.Equal(t, []int{1, 1, 1}, llm.calls)
+}
+
+func TestBatchCoalescing(t *testing.T) {
+	t.Parallel()
+
+	llm := &echoLanguageModel{}
+	c := NewLLMChain(llm, prompts.NewPromptTemplate("about {{.topic}}", []string{"topic"}))
+	inputs := []map[string]any{
+		{"topic": "a"},
+		{"topic": "b"},
+		{"other": "x"},
+		{"topic": "c"},
+		{"topic": "d"},
+	}
+	results, err := Batch(context.Background(), c, inputs, WithBatchCoalescing(3), WithBatchConcurrency(1))
+	require.ErrorIs(t, err, ErrMissingInputValues)
+
+	require.Equal(t, []int{2, 2}, llm.calls)
+	require.Equal(t, map[string]any{"text": "about a"}, results[0].Outputs)
+	require.Equal(t, map[string]any{"text": "about b"}, results[1].Outputs)
+	require.ErrorIs(t, results[2].Err, ErrMissingInputValues)
+	require.Equal(t, map[string]any{"text": "about c"}, results[3].Outputs)
+	require.Equal(t, map[string]any{"text": "about d"}, results[4].Outputs)
+}
+
+func TestBatchCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c := NewLLMChain(&echoLanguageModel{}, prompts.NewPromptTemplate("about {{.topic}}", []string{"topic"}))
+	results, err := Batch(ctx, c, []map[string]any{{"topic": "a"}, {"topic": "b"}}, WithBatchConcurrency(1))
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, results, 2)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/schema"
 )
 
@@ -41,16 +42,23 @@ func Call(ctx context.Context, c Chain, inputValues map[string]any, options ...C
 		fullValues[key] = value
 	}
 
-	if err := validateInputs(c, fullValues); err != nil {
-		return nil, err
+	var handler callbacks.Handler
+	if hh, ok := c.(callbacks.HandlerHaver); ok {
+		handler = hh.GetCallbackHandler()
+	}
+	if handler != nil {
+		handler.HandleChainStart(ctx, fullValues)
 	}
 
-	outputValues, err := c.Call(ctx, fullValues, options...)
+	outputValues, err := callChain(ctx, c, fullValues, options...)
 	if err != nil {
+		if handler != nil {
+			handler.HandleChainError(ctx, err)
+		}
 		return nil, err
 	}
-	if err := validateOutputs(c, outputValues); err != nil {
-		return nil, err
+	if handler != nil {
+		handler.HandleChainEnd(ctx, outputValues)
 	}
 
 	err = c.GetMemory().SaveContext(inputValues, outputValues)
@@ -61,6 +69,21 @@ func Call(ctx context.Context, c Chain, inputValues map[string]any, options ...C
 	return outputValues, nil
 }
 
+// callChain validates the inputs, calls the chain and validates the outputs.
+func callChain(ctx context.Context, c Chain, fullValues map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	if err := validateInputs(c, fullValues); err != nil {
+		return nil, err
+	}
+	outputValues, err := c.Call(ctx, fullValues, options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateOutputs(c, outputValues); err != nil {
+		return nil, err
+	}
+	return outputValues, nil
+}
+
 // Run can be used to execute a chain if the chain only expects one input and one
 // string output.
 func Run(ctx context.Context, c Chain, input any, options ...ChainCallOption) (string, error) {
@@ -214,6 +237,11 @@ func validateInputs(c Chain, inputValues map[string]any) error {
 			return fmt.Errorf("%w: %w: %v", ErrInvalidInputValues, ErrMissingInputValues, k)
 		}
 	}
+	if sc, ok := c.(SchemaChain); ok {
+		if err := validateTypes(sc.GetInputSchema(), inputValues); err != nil {
+			return fmt.Errorf("%w: %w: %w", ErrInvalidInputValues, ErrInputValuesWrongType, err)
+		}
+	}
 	return nil
 }
 
@@ -223,5 +251,10 @@ func validateOutputs(c Chain, outputValues map[string]any) error {
 			return fmt.Errorf("%w: %v", ErrInvalidOutputValues, k)
 		}
 	}
+	if sc, ok := c.(SchemaChain); ok {
+		if err := validateTypes(sc.GetOutputSchema(), outputValues); err != nil {
+			return fmt.Errorf("%w: %w", ErrOutputValuesWrongType, err)
+		}
+	}
 	return nil
 }
@@ -1,3 +1,9 @@
 // Package chains contains a standard interface for chains, a number of built in chains and
-// functions for calling and running chains.
+// functions for calling and running chains. Small pipelines of prompts, llms, output parsers
+// and chains can be composed as Runnable values with Pipe, Map, Branch and Fallback. Batch
+// calls a chain with many inputs with bounded concurrency. MultiPromptRouter lets an llm pick
+// the chain an input is given to. NewModeration checks the inputs and outputs of a chain with
+// safety checkers, such as the OpenAI moderation model. Warmup prepares a chain for its first
+// call, cutting the latency of the first request of serverless functions. NewImageRefinement
+// refines the prompts of an image generator with the critiques of a vision model.
 package chains
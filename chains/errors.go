@@ -20,6 +20,9 @@ var (
 	// ErrInvalidOutputValues is returned when expected output keys to a chain does
 	// not match the actual keys in the return output values map.
 	ErrInvalidOutputValues = errors.New("missing key in output values")
+	// ErrOutputValuesWrongType is returned if an output value of a chain is not
+	// of the type declared in its output schema.
+	ErrOutputValuesWrongType = errors.New("output key is of wrong type")
 
 	// ErrMultipleInputsInRun is returned in the run function if the chain expects
 	// more then one input values.
@@ -39,4 +42,7 @@ var (
 	ErrMultipleOutputsInPredict = errors.New("predict is not supported with a chain that returns multiple values")
 	// ErrChainInitialization is returned if a chain is not initialized appropriately.
 	ErrChainInitialization = errors.New("error initializing chain")
+	// ErrInvalidImageCritique is returned when the critique of an image by the
+	// llm of an image refinement chain holds no score.
+	ErrInvalidImageCritique = errors.New("invalid image critique")
 )
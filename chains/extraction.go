@@ -0,0 +1,225 @@
+package chains
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_extractionTemplate = `Extract the information described by the JSON schema below from the text.
+
+JSON schema:
+{{.schema}}
+
+Text:
+{{.input}}
+
+Answer with a JSON object matching the schema only.`
+
+	_extractionFunctionName = "extract"
+	_extractionOutputKey    = "output"
+)
+
+// ErrInvalidExtraction is returned by Extraction when the llm returns a value
+// that does not match the schema of the type extracted.
+var ErrInvalidExtraction = errors.New("extracted value does not match the schema")
+
+var _timeType = reflect.TypeOf(time.Time{})
+
+// Extraction is a chain extracting a value of type T from a text. The JSON
+// schema of T is derived from its fields: their json tags give their names,
+// fields tagged omitempty or of pointer type are optional, and a description
+// tag documents a field for the llm. The llm is offered an "extract" function
+// taking the schema, for chat models supporting function calling, and is
+// asked to answer with JSON otherwise.
+type Extraction[T any] struct {
+	LLM    llms.LanguageModel
+	Prompt prompts.PromptTemplate
+	// Schema is the JSON schema of T.
+	Schema    map[string]any
+	Memory    schema.Memory
+	OutputKey string
+}
+
+var _ Chain = &Extraction[struct{}]{}
+
+// NewExtraction creates a chain extracting a value of type T, which must be a
+// struct, with the llm.
+func NewExtraction[T any](llm llms.LanguageModel) *Extraction[T] {
+	return &Extraction[T]{
+		LLM:       llm,
+		Prompt:    prompts.NewPromptTemplate(_extractionTemplate, []string{"schema", "input"}),
+		Schema:    typeJSONSchema(reflect.TypeOf((*T)(nil)).Elem()),
+		Memory:    memory.NewSimple(),
+		OutputKey: _extractionOutputKey,
+	}
+}
+
+// Extract extracts a value of type T from the text.
+func (c *Extraction[T]) Extract(ctx context.Context, text string, options ...ChainCallOption) (T, error) {
+	var value T
+	outputs, err := Call(ctx, c, map[string]any{"input": text}, options...)
+	if err != nil {
+		return value, err
+	}
+	value, _ = outputs[c.OutputKey].(T)
+	return value, nil
+}
+
+// Call extracts a value of type T from the "input" and returns it under the
+// output key.
+func (c *Extraction[T]) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	schemaJSON, err := json.MarshalIndent(c.Schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	promptValue, err := c.Prompt.FormatPrompt(map[string]any{"schema": string(schemaJSON), "input": values["input"]})
+	if err != nil {
+		return nil, err
+	}
+
+	callOptions := getLLMCallOptions(options...)
+	if _, ok := c.LLM.(llms.ChatLLM); ok {
+		callOptions = append(callOptions,
+			llms.WithFunctions([]llms.FunctionDefinition{{
+				Name:        _extractionFunctionName,
+				Description: "Extracts the information described by the parameters from the text.",
+				Parameters:  c.Schema,
+			}}),
+			llms.WithForcedFunctionCall(_extractionFunctionName),
+		)
+	}
+	if err := llms.CheckSessionBudget(ctx); err != nil {
+		return nil, err
+	}
+	result, err := c.LLM.GeneratePrompt(ctx, []schema.PromptValue{promptValue}, callOptions...)
+	if err != nil {
+		return nil, err
+	}
+	llms.TrackUsage(ctx, result)
+	if len(result.Generations) == 0 || len(result.Generations[0]) == 0 {
+		return nil, fmt.Errorf("%w: no generation", ErrInvalidExtraction)
+	}
+
+	generation := result.Generations[0][0]
+	text := generation.Text
+	if m := generation.Message; m != nil && m.FunctionCall != nil && m.FunctionCall.Name == _extractionFunctionName {
+		text = llms.FunctionArguments(m.FunctionCall.Arguments)
+	}
+	value, err := decodeExtraction[T](text, c.Schema)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{c.OutputKey: value}, nil
+}
+
+// GetMemory returns the memory of the chain.
+func (c *Extraction[T]) GetMemory() schema.Memory { //nolint:ireturn
+	return c.Memory
+}
+
+// GetInputKeys returns the input key of the chain, "input".
+func (c *Extraction[T]) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+// GetOutputKeys returns the output key of the chain.
+func (c *Extraction[T]) GetOutputKeys() []string {
+	return []string{c.OutputKey}
+}
+
+// decodeExtraction decodes the JSON object of the text into a T, checking
+// that the required properties of the schema are set and that no unknown
+// property is.
+func decodeExtraction[T any](text string, jsonSchema map[string]any) (T, error) {
+	var value T
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return value, fmt.Errorf("%w: no JSON object in %q", ErrInvalidExtraction, text)
+	}
+	object := []byte(text[start : end+1])
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(object, &fields); err != nil {
+		return value, fmt.Errorf("%w: %w", ErrInvalidExtraction, err)
+	}
+	required, _ := jsonSchema["required"].([]string)
+	for _, name := range required {
+		if raw, ok := fields[name]; !ok || string(raw) == "null" {
+			return value, fmt.Errorf("%w: missing required property %q", ErrInvalidExtraction, name)
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(object))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&value); err != nil {
+		return value, fmt.Errorf("%w: %w", ErrInvalidExtraction, err)
+	}
+	return value, nil
+}
+
+// typeJSONSchema returns the JSON schema of the values of the type.
+func typeJSONSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeJSONSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeJSONSchema(t.Elem())}
+	case reflect.Struct:
+		if t == _timeType {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		return structJSONSchema(t)
+	}
+	return map[string]any{}
+}
+
+func structJSONSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property := typeJSONSchema(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+		properties[name] = property
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
@@ -0,0 +1,89 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type person struct {
+	Name     string   `json:"name" description:"the full name"`
+	Age      int      `json:"age"`
+	Email    *string  `json:"email"`
+	Nickname string   `json:"nickname,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// functionCallingLLM answers with a call of the extract function, checking
+// that it was offered and forced.
+type functionCallingLLM struct{ arguments string }
+
+func (l functionCallingLLM) GeneratePrompt(_ context.Context, _ []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if len(opts.Functions) != 1 || opts.ForcedFunctionCall != opts.Functions[0].Name {
+		return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: "no functions"}}}}, nil
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{
+		Message: &schema.AIChatMessage{FunctionCall: &schema.FunctionCall{
+			Name:      opts.Functions[0].Name,
+			Arguments: l.arguments,
+		}},
+	}}}}, nil
+}
+
+func (functionCallingLLM) GetNumTokens(text string) int { return len(text) }
+
+func (functionCallingLLM) Call(context.Context, []schema.ChatMessage, ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	return nil, nil
+}
+
+func (functionCallingLLM) Generate(context.Context, [][]schema.ChatMessage, ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	return nil, nil
+}
+
+func TestExtractionSchema(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":     map[string]any{"type": "string", "description": "the full name"},
+			"age":      map[string]any{"type": "integer"},
+			"email":    map[string]any{"type": "string"},
+			"nickname": map[string]any{"type": "string"},
+			"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+		"required": []string{"name", "age"},
+	}, NewExtraction[person](&testLanguageModel{}).Schema)
+}
+
+func TestExtraction(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewExtraction[person](functionCallingLLM{arguments: `{"name": "Ada Lovelace", "age": 36}`}).
+		Extract(context.Background(), "Ada Lovelace died at 36.")
+	require.NoError(t, err)
+	require.Equal(t, person{Name: "Ada Lovelace", Age: 36}, p)
+
+	llm := &testLanguageModel{expResult: "Here it is: {\"name\": \"Alan\", \"age\": 41, \"tags\": [\"math\"]}"}
+	p, err = NewExtraction[person](llm).Extract(context.Background(), "Alan was 41.")
+	require.NoError(t, err)
+	require.Equal(t, person{Name: "Alan", Age: 41, Tags: []string{"math"}}, p)
+	require.Contains(t, llm.recordedPrompt[0].String(), `"description": "the full name"`)
+
+	for _, text := range []string{
+		`no json`,
+		`{"name": "Alan"}`,
+		`{"name": "Alan", "age": "old"}`,
+		`{"name": "Alan", "age": 41, "height": 180}`,
+	} {
+		_, err := NewExtraction[person](&testLanguageModel{expResult: text}).Extract(context.Background(), "Alan")
+		require.ErrorIs(t, err, ErrInvalidExtraction, text)
+	}
+}
@@ -0,0 +1,240 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_imageRefinementDefaultRounds      = 3
+	_imageRefinementDefaultTargetScore = 9
+
+	// ImageRefinementImageKey, ImageRefinementPromptKey and
+	// ImageRefinementScoreKey are the output keys of the best image, the
+	// prompt it was generated from and its score.
+	ImageRefinementImageKey  = "image"
+	ImageRefinementPromptKey = "prompt"
+	ImageRefinementScoreKey  = "score"
+	// ImageRefinementRoundsKey is the output key of the rounds of the
+	// refinement, as []ImageRefinementRound.
+	ImageRefinementRoundsKey = "rounds"
+)
+
+//nolint:lll
+const _imageCritiqueTemplate = `You are reviewing an image made by an image generator to fulfill a request.
+
+Request: {{.request}}
+Prompt given to the generator: {{.prompt}}
+
+The image is attached. Rate from 0 to 10 how well the image fulfills the request, explain what should change, and write a new prompt for the generator fixing it. Answer with these three lines:
+SCORE: <number from 0 to 10>
+CRITIQUE: <what should change>
+PROMPT: <new prompt>`
+
+// ImageGenerator generates images from text prompts.
+type ImageGenerator interface {
+	// GenerateImage returns the encoded image, such as a PNG file, generated
+	// from the prompt.
+	GenerateImage(ctx context.Context, prompt string) ([]byte, error)
+}
+
+// ImageGeneratorFunc is a function generating images, implementing
+// ImageGenerator.
+type ImageGeneratorFunc func(ctx context.Context, prompt string) ([]byte, error)
+
+// GenerateImage calls the function.
+func (f ImageGeneratorFunc) GenerateImage(ctx context.Context, prompt string) ([]byte, error) {
+	return f(ctx, prompt)
+}
+
+// ImageRefinementRound is a round of an image refinement: the image generated
+// from the prompt and its critique.
+type ImageRefinementRound struct {
+	Prompt string
+	Image  []byte
+	// Score is the grade of the image, from 0 to 10.
+	Score    float64
+	Critique string
+	// NextPrompt is the prompt the critique suggests for the next round.
+	NextPrompt string
+}
+
+// ImageRefinement is a chain generating an image for a request, asking a
+// vision model to critique it against the request and to rewrite the prompt,
+// and generating again with the new prompt until the image reaches the target
+// score or the maximum number of rounds is run. It returns the image with the
+// best score, with the rounds as a trace.
+type ImageRefinement struct {
+	Generator ImageGenerator
+	// LLM is given the images and must be able to see them, as the chat
+	// models of the openai package with a vision model.
+	LLM    llms.LanguageModel
+	Memory schema.Memory
+	// MaxRounds is the maximum number of images generated.
+	MaxRounds int
+	// TargetScore is the score, from 0 to 10, stopping the refinement.
+	TargetScore float64
+	// InputKey is the key of the request. Defaults to "input".
+	InputKey string
+
+	critiquePrompt prompts.PromptTemplate
+}
+
+var _ Chain = ImageRefinement{}
+
+// ImageRefinementOption is a function that configures an ImageRefinement.
+type ImageRefinementOption func(*ImageRefinement)
+
+// WithImageRefinementRounds sets the maximum number of images generated.
+// Defaults to 3.
+func WithImageRefinementRounds(rounds int) ImageRefinementOption {
+	return func(c *ImageRefinement) {
+		c.MaxRounds = rounds
+	}
+}
+
+// WithImageRefinementTargetScore sets the score, from 0 to 10, at which the
+// refinement stops. Defaults to 9.
+func WithImageRefinementTargetScore(score float64) ImageRefinementOption {
+	return func(c *ImageRefinement) {
+		c.TargetScore = score
+	}
+}
+
+// NewImageRefinement creates a chain refining the images of the generator
+// for the "input" request with the critiques of the vision model.
+func NewImageRefinement(generator ImageGenerator, llm llms.LanguageModel, opts ...ImageRefinementOption) ImageRefinement {
+	c := ImageRefinement{
+		Generator:      generator,
+		LLM:            llm,
+		Memory:         memory.NewSimple(),
+		MaxRounds:      _imageRefinementDefaultRounds,
+		TargetScore:    _imageRefinementDefaultTargetScore,
+		InputKey:       "input",
+		critiquePrompt: prompts.NewPromptTemplate(_imageCritiqueTemplate, []string{"request", "prompt"}),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Call generates images for the request, the first one with the request as
+// prompt, until one reaches the target score, and returns the best one.
+func (c ImageRefinement) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	request, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: %s", ErrInvalidInputValues, ErrInputValuesWrongType, c.InputKey)
+	}
+	if c.MaxRounds < 1 {
+		return nil, fmt.Errorf("%w: no rounds", ErrChainInitialization)
+	}
+
+	var rounds []ImageRefinementRound
+	best := 0
+	prompt := request
+	for i := 0; i < c.MaxRounds; i++ {
+		round, err := c.round(ctx, request, prompt, options)
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, round)
+		if round.Score > rounds[best].Score {
+			best = i
+		}
+		if round.Score >= c.TargetScore || round.NextPrompt == "" {
+			break
+		}
+		prompt = round.NextPrompt
+	}
+
+	return map[string]any{
+		ImageRefinementImageKey:  rounds[best].Image,
+		ImageRefinementPromptKey: rounds[best].Prompt,
+		ImageRefinementScoreKey:  rounds[best].Score,
+		ImageRefinementRoundsKey: rounds,
+	}, nil
+}
+
+// round generates an image from the prompt and has it critiqued.
+func (c ImageRefinement) round(ctx context.Context, request, prompt string, options []ChainCallOption) (ImageRefinementRound, error) { //nolint:lll
+	image, err := c.Generator.GenerateImage(ctx, prompt)
+	if err != nil {
+		return ImageRefinementRound{}, fmt.Errorf("generating image: %w", err)
+	}
+	text, err := c.critiquePrompt.Format(map[string]any{"request": request, "prompt": prompt})
+	if err != nil {
+		return ImageRefinementRound{}, err
+	}
+	message := schema.HumanChatMessage{Content: text, Parts: []schema.ContentPart{schema.ImageDataPart("", image)}}
+	result, err := c.LLM.GeneratePrompt(ctx,
+		[]schema.PromptValue{prompts.ChatPromptValue{message}},
+		getLLMCallOptions(options...)...,
+	)
+	if err != nil {
+		return ImageRefinementRound{}, err
+	}
+	llms.TrackUsage(ctx, result)
+	if len(result.Generations) == 0 || len(result.Generations[0]) == 0 {
+		return ImageRefinementRound{}, fmt.Errorf("%w: no generation", ErrInvalidImageCritique)
+	}
+
+	round := ImageRefinementRound{Prompt: prompt, Image: image}
+	if err := parseImageCritique(result.Generations[0][0].Text, &round); err != nil {
+		return ImageRefinementRound{}, err
+	}
+	return round, nil
+}
+
+// parseImageCritique sets the score, critique and next prompt of the round
+// from the SCORE, CRITIQUE and PROMPT lines of the critique.
+func parseImageCritique(critique string, round *ImageRefinementRound) error {
+	hasScore := false
+	for _, line := range strings.Split(critique, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToUpper(strings.Trim(key, "* ")) {
+		case "SCORE":
+			score, _, _ := strings.Cut(value, "/")
+			s, err := strconv.ParseFloat(strings.Trim(score, "* "), 64)
+			if err != nil {
+				return fmt.Errorf("%w: score %q", ErrInvalidImageCritique, value)
+			}
+			round.Score, hasScore = s, true
+		case "CRITIQUE":
+			round.Critique = value
+		case "PROMPT":
+			round.NextPrompt = value
+		}
+	}
+	if !hasScore {
+		return fmt.Errorf("%w: no score in %q", ErrInvalidImageCritique, critique)
+	}
+	return nil
+}
+
+// GetMemory returns the memory of the chain.
+func (c ImageRefinement) GetMemory() schema.Memory { //nolint:ireturn
+	return c.Memory
+}
+
+// GetInputKeys returns the input key of the request.
+func (c ImageRefinement) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+// GetOutputKeys returns the keys of the best image, its prompt and score, and
+// of the rounds.
+func (c ImageRefinement) GetOutputKeys() []string {
+	return []string{ImageRefinementImageKey, ImageRefinementPromptKey, ImageRefinementScoreKey, ImageRefinementRoundsKey}
+}
@@ -0,0 +1,82 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// critiqueLLM answers with its critiques in turn and records the images it
+// was shown.
+type critiqueLLM struct {
+	critiques []string
+	images    []string
+}
+
+func (l *critiqueLLM) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	message, ok := promptValues[0].Messages()[0].(schema.HumanChatMessage)
+	if !ok || len(message.Parts) != 1 {
+		return llms.LLMResult{}, fmt.Errorf("unexpected prompt %v", promptValues[0])
+	}
+	l.images = append(l.images, string(message.Parts[0].Data))
+	critique := l.critiques[0]
+	l.critiques = l.critiques[1:]
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: critique}}}}, nil
+}
+
+func (l *critiqueLLM) GetNumTokens(text string) int {
+	return len(text)
+}
+
+var testImageGenerator = ImageGeneratorFunc(func(_ context.Context, prompt string) ([]byte, error) { //nolint:gochecknoglobals
+	return []byte("image of " + prompt), nil
+})
+
+func TestImageRefinement(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &critiqueLLM{critiques: []string{
+		"SCORE: 4\nCRITIQUE: The otter is missing.\nPROMPT: an otter on a rock",
+		"**Score:** 7/10\nCritique: Too dark.\nPrompt: an otter on a rock, bright daylight",
+		"SCORE: 6\nCRITIQUE: Blurry.\nPROMPT: a sharp photo of an otter",
+	}}
+	c := NewImageRefinement(testImageGenerator, llm)
+	outputs, err := Call(ctx, c, map[string]any{"input": "a rock"})
+	require.NoError(t, err)
+	require.Equal(t, []byte("image of an otter on a rock"), outputs[ImageRefinementImageKey])
+	require.Equal(t, "an otter on a rock", outputs[ImageRefinementPromptKey])
+	require.Equal(t, 7.0, outputs[ImageRefinementScoreKey])
+	require.Len(t, outputs[ImageRefinementRoundsKey], 3)
+	require.Equal(t, ImageRefinementRound{
+		Prompt:     "a rock",
+		Image:      []byte("image of a rock"),
+		Score:      4,
+		Critique:   "The otter is missing.",
+		NextPrompt: "an otter on a rock",
+	}, outputs[ImageRefinementRoundsKey].([]ImageRefinementRound)[0])
+	require.Equal(t, []string{
+		"image of a rock",
+		"image of an otter on a rock",
+		"image of an otter on a rock, bright daylight",
+	}, llm.images)
+}
+
+func TestImageRefinementStopsAtTargetScore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &critiqueLLM{critiques: []string{"SCORE: 8\nPROMPT: an otter", "SCORE: 10"}}
+	c := NewImageRefinement(testImageGenerator, llm, WithImageRefinementRounds(5), WithImageRefinementTargetScore(8))
+	outputs, err := Call(ctx, c, map[string]any{"input": "an otter"})
+	require.NoError(t, err)
+	require.Len(t, outputs[ImageRefinementRoundsKey], 1)
+
+	llm = &critiqueLLM{critiques: []string{"It looks great."}}
+	_, err = Call(ctx, NewImageRefinement(testImageGenerator, llm), map[string]any{"input": "an otter"})
+	require.ErrorIs(t, err, ErrInvalidImageCritique)
+}
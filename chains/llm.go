@@ -3,6 +3,7 @@ package chains
 import (
 	"context"
 
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/outputparser"
@@ -17,11 +18,17 @@ type LLMChain struct {
 	LLM          llms.LanguageModel
 	Memory       schema.Memory
 	OutputParser schema.OutputParser[any]
+	// CallbacksHandler is called with the start and end of the chain and of
+	// the llm call, and with the streamed chunks when streaming is enabled.
+	CallbacksHandler callbacks.Handler
 
 	OutputKey string
 }
 
-var _ Chain = &LLMChain{}
+var (
+	_ Chain                  = &LLMChain{}
+	_ callbacks.HandlerHaver = &LLMChain{}
+)
 
 // NewLLMChain creates a new LLMChain with an llm and a prompt.
 func NewLLMChain(llm llms.LanguageModel, prompt prompts.FormatPrompter) *LLMChain {
@@ -47,14 +54,30 @@ func (c LLMChain) Call(ctx context.Context, values map[string]any, options ...Ch
 		return nil, err
 	}
 
+	if err := llms.CheckSessionBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	if c.CallbacksHandler != nil {
+		c.CallbacksHandler.HandleLLMStart(ctx, []string{promptValue.String()})
+		options = append(options, c.streamToHandler(options...))
+	}
+
 	result, err := c.LLM.GeneratePrompt(
 		ctx,
 		[]schema.PromptValue{promptValue},
 		getLLMCallOptions(options...)...,
 	)
 	if err != nil {
+		if c.CallbacksHandler != nil {
+			c.CallbacksHandler.HandleLLMError(ctx, err)
+		}
 		return nil, err
 	}
+	llms.TrackUsage(ctx, result)
+	if c.CallbacksHandler != nil {
+		c.CallbacksHandler.HandleLLMEnd(ctx, result)
+	}
 
 	finalOutput, err := c.OutputParser.ParseWithPrompt(result.Generations[0][0].Text, promptValue)
 	if err != nil {
@@ -64,6 +87,31 @@ func (c LLMChain) Call(ctx context.Context, values map[string]any, options ...Ch
 	return map[string]any{c.OutputKey: finalOutput}, nil
 }
 
+// streamToHandler returns an option passing the streamed chunks to the
+// callbacks handler as well as to the streaming function of the options, if
+// any. Without a streaming function streaming is left disabled.
+func (c LLMChain) streamToHandler(options ...ChainCallOption) ChainCallOption {
+	opts := &chainCallOption{}
+	for _, opt := range options {
+		opt(opts)
+	}
+	streamingFunc := opts.StreamingFunc
+	return func(o *chainCallOption) {
+		if streamingFunc == nil {
+			return
+		}
+		o.StreamingFunc = func(ctx context.Context, chunk []byte) error {
+			c.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
+			return streamingFunc(ctx, chunk)
+		}
+	}
+}
+
+// GetCallbackHandler returns the callbacks handler of the chain.
+func (c LLMChain) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	return c.CallbacksHandler
+}
+
 // GetMemory returns the memory.
 func (c LLMChain) GetMemory() schema.Memory { //nolint:ireturn
 	return c.Memory //nolint:ireturn
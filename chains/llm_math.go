@@ -40,7 +40,7 @@ type LLMMathChain struct {
 	LLMChain *LLMChain
 }
 
-var _ Chain = LLMMathChain{}
+var _ SchemaChain = LLMMathChain{}
 
 func NewLLMMathChain(llm llms.LanguageModel) LLMMathChain {
 	p := prompts.NewPromptTemplate(_llmMathPrompt, []string{"question"})
@@ -79,6 +79,16 @@ func (c LLMMathChain) GetOutputKeys() []string {
 	return []string{"answer"}
 }
 
+// GetInputSchema returns the schema of the "question" input key.
+func (c LLMMathChain) GetInputSchema() []KeySchema {
+	return []KeySchema{{Name: "question", Type: ValueTypeString, Description: "The math question to answer."}}
+}
+
+// GetOutputSchema returns the schema of the "answer" output key.
+func (c LLMMathChain) GetOutputSchema() []KeySchema {
+	return []KeySchema{{Name: "answer", Type: ValueTypeString, Description: "The result of the calculation."}}
+}
+
 var starlarkBlockRegex = regexp.MustCompile("(?s)```starlark(.*)```")
 
 func (c LLMMathChain) processLLMResult(llmOutput string) (string, error) {
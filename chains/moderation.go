@@ -0,0 +1,129 @@
+package chains
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/safety"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ModerationChain is a chain checking the string inputs of another chain
+// before calling it, and its string outputs after, with safety checkers such
+// as safety.NewModeration, which uses the OpenAI moderation endpoint. Blocked
+// inputs never reach the chain, redacted inputs are given to it redacted, and
+// the annotations of both the inputs and the outputs are returned under the
+// SafetyAnnotationsKey.
+type ModerationChain struct {
+	Chain    Chain
+	Checkers []safety.Checker
+	// CheckInputs and CheckOutputs select what is checked, both are by
+	// default.
+	CheckInputs  bool
+	CheckOutputs bool
+}
+
+var (
+	_ Chain                  = ModerationChain{}
+	_ callbacks.HandlerHaver = ModerationChain{}
+)
+
+// ModerationChainOption is a function that configures a ModerationChain.
+type ModerationChainOption func(*ModerationChain)
+
+// WithModerationCheckers adds checkers run after the ones given to
+// NewModeration.
+func WithModerationCheckers(checkers ...safety.Checker) ModerationChainOption {
+	return func(c *ModerationChain) {
+		c.Checkers = append(c.Checkers, checkers...)
+	}
+}
+
+// WithModeratedInputs sets whether the inputs of the chain are checked.
+func WithModeratedInputs(check bool) ModerationChainOption {
+	return func(c *ModerationChain) {
+		c.CheckInputs = check
+	}
+}
+
+// WithModeratedOutputs sets whether the outputs of the chain are checked.
+func WithModeratedOutputs(check bool) ModerationChainOption {
+	return func(c *ModerationChain) {
+		c.CheckOutputs = check
+	}
+}
+
+// NewModeration creates a chain checking the inputs and outputs of the chain
+// with the checker. The action of the verdicts of the checker, such as the
+// one given to safety.NewModeration, decides whether flagged content is
+// blocked, redacted or annotated.
+func NewModeration(chain Chain, checker safety.Checker, opts ...ModerationChainOption) ModerationChain {
+	c := ModerationChain{
+		Chain:        chain,
+		Checkers:     []safety.Checker{checker},
+		CheckInputs:  true,
+		CheckOutputs: true,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Call checks the inputs, calls the chain and checks its outputs. Blocked
+// content makes the call fail with safety.ErrBlocked.
+func (c ModerationChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	var annotations []string
+	if c.CheckInputs {
+		checked := make(map[string]any, len(inputs))
+		for key, value := range inputs {
+			checked[key] = value
+		}
+		inputAnnotations, err := checkStringValues(ctx, checked, c.Checkers)
+		if err != nil {
+			return nil, err
+		}
+		inputs = checked
+		annotations = append(annotations, inputAnnotations...)
+	}
+
+	outputs, err := c.Chain.Call(ctx, inputs, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.CheckOutputs {
+		outputAnnotations, err := checkStringValues(ctx, outputs, c.Checkers)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, outputAnnotations...)
+	}
+	if len(annotations) > 0 {
+		outputs[SafetyAnnotationsKey] = annotations
+	}
+	return outputs, nil
+}
+
+// GetMemory returns the memory of the chain.
+func (c ModerationChain) GetMemory() schema.Memory { //nolint:ireturn
+	return c.Chain.GetMemory()
+}
+
+// GetInputKeys returns the input keys of the chain.
+func (c ModerationChain) GetInputKeys() []string {
+	return c.Chain.GetInputKeys()
+}
+
+// GetOutputKeys returns the output keys of the chain.
+func (c ModerationChain) GetOutputKeys() []string {
+	return c.Chain.GetOutputKeys()
+}
+
+// GetCallbackHandler returns the callbacks handler of the chain, if any.
+func (c ModerationChain) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	if hh, ok := c.Chain.(callbacks.HandlerHaver); ok {
+		return hh.GetCallbackHandler()
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package chains
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/safety"
+)
+
+type testModerator struct {
+	flagged string
+}
+
+func (m testModerator) Moderate(_ context.Context, texts []string) ([]openai.ModerationResult, error) {
+	results := make([]openai.ModerationResult, len(texts))
+	for i, text := range texts {
+		if strings.Contains(text, m.flagged) {
+			results[i] = openai.ModerationResult{Flagged: true, Categories: map[string]bool{"violence": true}}
+		}
+	}
+	return results, nil
+}
+
+func TestModerationChainInputs(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{}
+	redact := safety.NewModeration(testModerator{flagged: "fight"}, safety.Redact)
+	c := NewModeration(NewLLMChain(llm, prompts.NewPromptTemplate("about {{.topic}}", []string{"topic"})), redact)
+
+	outputs, err := Call(context.Background(), c, map[string]any{"topic": "a fight"})
+	require.NoError(t, err)
+	require.Equal(t, "about [content removed by moderation]", llm.recordedPrompt[0].String())
+	require.Equal(t, "about [content removed by moderation]", outputs["text"])
+
+	llm.recordedPrompt = nil
+	block := safety.NewModeration(testModerator{flagged: "fight"}, safety.Block)
+	c = NewModeration(NewLLMChain(llm, prompts.NewPromptTemplate("about {{.topic}}", []string{"topic"})), block)
+	_, err = Call(context.Background(), c, map[string]any{"topic": "a fight"})
+	require.ErrorIs(t, err, safety.ErrBlocked)
+	require.Nil(t, llm.recordedPrompt)
+}
+
+func TestModerationChainOutputsOnly(t *testing.T) {
+	t.Parallel()
+
+	llm := &testLanguageModel{expResult: "a fight broke out"}
+	annotate := safety.NewModeration(testModerator{flagged: "fight"}, safety.Annotate)
+	c := NewModeration(
+		NewLLMChain(llm, prompts.NewPromptTemplate("about {{.topic}}", []string{"topic"})),
+		annotate,
+		WithModeratedInputs(false),
+	)
+
+	outputs, err := Call(context.Background(), c, map[string]any{"topic": "a fight"})
+	require.NoError(t, err)
+	require.Equal(t, "about a fight", llm.recordedPrompt[0].String())
+	require.Equal(t, "a fight broke out", outputs["text"])
+	require.Equal(t, []string{"flagged by moderation: violence"}, outputs[SafetyAnnotationsKey])
+}
@@ -0,0 +1,439 @@
+package chains
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// nolint: lll
+	_openAPIOperationPrompt = `You are given the operations of an API:
+
+{{.operations}}
+
+Pick the operation best suited to answer the input and fill in its parameters from the input. The input could be a question that requires an API call for its answer, or a direct or indirect instruction to consume the API.
+
+Input: {{.input}}
+
+Respond with a JSON object only:
+
+{
+	"operation": [the id of the operation],
+	"parameters": [object with the values of the parameters of the operation],
+	"body": [object sent as the JSON body of the request, only if the operation takes a body]
+}`
+
+	// nolint: lll
+	_openAPIAnswerPrompt = `You called the {{.operation}} operation of an API to answer the input below.
+
+Input: {{.input}}
+
+Here is the response from the API:
+
+{{.api_response}}
+
+Now, summarize this response. Your summary should reflect the original input and highlight the key information from the API response that answers or relates to that input. Try to make your summary concise, yet informative.
+
+Summary:`
+
+	// _openAPIMaxResponseSize is the number of bytes of an API response given
+	// to the llm to summarize.
+	_openAPIMaxResponseSize = 16 << 10
+)
+
+var (
+	// ErrInvalidOpenAPISpec is returned by NewOpenAPIChain if the document is
+	// not an OpenAPI or Swagger document with a server and operations.
+	ErrInvalidOpenAPISpec = errors.New("invalid OpenAPI document")
+	// ErrUnknownOperation is returned if the llm picks an operation that is not
+	// in the document, or leaves out a required parameter.
+	ErrUnknownOperation = errors.New("unknown API operation")
+)
+
+var _openAPIJSONRegexp = regexp.MustCompile(`(?s)\{.*\}`)
+
+// APIParameter is a parameter of an APIOperation.
+type APIParameter struct {
+	Name string
+	// In is where the parameter goes: "path", "query" or "header".
+	In          string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// APIOperation is an operation of an OpenAPI document.
+type APIOperation struct {
+	// ID is the operationId of the operation, or its method and path if it has
+	// none.
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	Parameters  []APIParameter
+	RequestBody bool
+}
+
+// OpenAPIChain is a chain calling the operations of an API described by an
+// OpenAPI document: the llm picks an operation and its parameters from the
+// input, the chain calls the API, and the llm summarizes the response.
+type OpenAPIChain struct {
+	RequestChain *LLMChain
+	AnswerChain  *LLMChain
+	Request      HTTPRequest
+
+	BaseURL    string
+	Operations []APIOperation
+	// Headers are set on every request, for example to authenticate. They are
+	// not shown to the llm.
+	Headers map[string]string
+}
+
+var _ Chain = OpenAPIChain{}
+
+// OpenAPIChainOption is a function that configures an OpenAPIChain.
+type OpenAPIChainOption func(*OpenAPIChain)
+
+// WithOpenAPIHeaders sets headers set on every request, such as an
+// Authorization header.
+func WithOpenAPIHeaders(headers map[string]string) OpenAPIChainOption {
+	return func(c *OpenAPIChain) {
+		c.Headers = headers
+	}
+}
+
+// WithOpenAPIBaseURL sets the URL the paths of the operations are relative to,
+// instead of the first server of the document.
+func WithOpenAPIBaseURL(baseURL string) OpenAPIChainOption {
+	return func(c *OpenAPIChain) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithOpenAPIHTTPClient sets the client making the requests, http.DefaultClient
+// by default.
+func WithOpenAPIHTTPClient(client HTTPRequest) OpenAPIChainOption {
+	return func(c *OpenAPIChain) {
+		c.Request = client
+	}
+}
+
+// NewOpenAPIChain creates a chain calling the API described by the OpenAPI 3 or
+// Swagger 2 document, in JSON or YAML.
+func NewOpenAPIChain(llm llms.LanguageModel, spec []byte, opts ...OpenAPIChainOption) (OpenAPIChain, error) {
+	baseURL, operations, err := parseOpenAPI(spec)
+	if err != nil {
+		return OpenAPIChain{}, err
+	}
+
+	c := OpenAPIChain{
+		RequestChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_openAPIOperationPrompt, []string{"operations", "input"},
+		)),
+		AnswerChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_openAPIAnswerPrompt, []string{"operation", "input", "api_response"},
+		)),
+		Request:    http.DefaultClient,
+		BaseURL:    baseURL,
+		Operations: operations,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.BaseURL == "" {
+		return OpenAPIChain{}, fmt.Errorf("%w: no server url", ErrInvalidOpenAPISpec)
+	}
+	return c, nil
+}
+
+// Call picks an operation for the input, calls it and returns the summary of
+// the response as "answer".
+func (c OpenAPIChain) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	input, ok := values["input"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	choice, err := Predict(ctx, c.RequestChain, map[string]any{
+		"operations": formatAPIOperations(c.Operations),
+		"input":      input,
+	}, append(options, WithTemperature(0))...)
+	if err != nil {
+		return nil, err
+	}
+	var call struct {
+		Operation  string         `json:"operation"`
+		Parameters map[string]any `json:"parameters"`
+		Body       any            `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(_openAPIJSONRegexp.FindString(choice)), &call); err != nil {
+		return nil, fmt.Errorf("parse operation: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, call.Operation, call.Parameters, call.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Request.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, _openAPIMaxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err := Predict(ctx, c.AnswerChain, map[string]any{
+		"operation":    call.Operation,
+		"input":        input,
+		"api_response": fmt.Sprintf("%s\n%s", resp.Status, body),
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"answer": answer}, nil
+}
+
+// newRequest creates the request of the operation with the parameters and
+// the body.
+func (c OpenAPIChain) newRequest(ctx context.Context, id string, params map[string]any, body any) (*http.Request, error) { //nolint:lll
+	var op *APIOperation
+	for i := range c.Operations {
+		if c.Operations[i].ID == id {
+			op = &c.Operations[i]
+		}
+	}
+	if op == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOperation, id)
+	}
+
+	path := op.Path
+	query := url.Values{}
+	headers := make(map[string]string)
+	for _, p := range op.Parameters {
+		value, ok := params[p.Name]
+		if !ok || value == nil {
+			if p.Required {
+				return nil, fmt.Errorf("%w: %s needs the %s parameter", ErrUnknownOperation, id, p.Name)
+			}
+			continue
+		}
+		s := fmt.Sprint(value)
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(s))
+		case "query":
+			query.Set(p.Name, s)
+		case "header":
+			headers[p.Name] = s
+		}
+	}
+
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var bodyReader io.Reader
+	if op.RequestBody && body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, op.Method, u, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if bodyReader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	for key, value := range c.Headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+// GetMemory returns the memory of the chain.
+func (c OpenAPIChain) GetMemory() schema.Memory { //nolint:ireturn
+	return memory.NewSimple()
+}
+
+// GetInputKeys returns the input key of the chain, "input".
+func (c OpenAPIChain) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+// GetOutputKeys returns the output key of the chain, "answer".
+func (c OpenAPIChain) GetOutputKeys() []string {
+	return []string{"answer"}
+}
+
+type openAPIParameter struct {
+	Name        string `yaml:"name"`
+	In          string `yaml:"in"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	Type        string `yaml:"type"`
+	Schema      struct {
+		Type string `yaml:"type"`
+	} `yaml:"schema"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `yaml:"operationId"`
+	Summary     string             `yaml:"summary"`
+	Description string             `yaml:"description"`
+	Parameters  []openAPIParameter `yaml:"parameters"`
+	RequestBody any                `yaml:"requestBody"`
+}
+
+type openAPIDocument struct {
+	// Servers are the servers of an OpenAPI 3 document.
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	// Host, BasePath and Schemes give the server of a Swagger 2 document.
+	Host     string                          `yaml:"host"`
+	BasePath string                          `yaml:"basePath"`
+	Schemes  []string                        `yaml:"schemes"`
+	Paths    map[string]map[string]yaml.Node `yaml:"paths"`
+}
+
+// parseOpenAPI returns the base url and the operations of the document.
+func parseOpenAPI(spec []byte) (string, []APIOperation, error) {
+	var doc openAPIDocument
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrInvalidOpenAPISpec, err)
+	}
+
+	var baseURL string
+	switch {
+	case len(doc.Servers) > 0:
+		baseURL = doc.Servers[0].URL
+	case doc.Host != "":
+		scheme := "https"
+		if len(doc.Schemes) > 0 {
+			scheme = doc.Schemes[0]
+		}
+		baseURL = scheme + "://" + doc.Host + doc.BasePath
+	}
+
+	var operations []APIOperation
+	for path, item := range doc.Paths {
+		var shared []openAPIParameter
+		if node, ok := item["parameters"]; ok {
+			if err := node.Decode(&shared); err != nil {
+				return "", nil, fmt.Errorf("%w: %s: %w", ErrInvalidOpenAPISpec, path, err)
+			}
+		}
+		for method, node := range item {
+			method = strings.ToUpper(method)
+			if !isHTTPMethod(method) {
+				continue
+			}
+			var op openAPIOperation
+			if err := node.Decode(&op); err != nil {
+				return "", nil, fmt.Errorf("%w: %s %s: %w", ErrInvalidOpenAPISpec, method, path, err)
+			}
+			operations = append(operations, newAPIOperation(method, path, op, shared))
+		}
+	}
+	if len(operations) == 0 {
+		return "", nil, fmt.Errorf("%w: no operations", ErrInvalidOpenAPISpec)
+	}
+	sort.Slice(operations, func(i, j int) bool { return operations[i].ID < operations[j].ID })
+	return baseURL, operations, nil
+}
+
+func newAPIOperation(method, path string, op openAPIOperation, shared []openAPIParameter) APIOperation {
+	o := APIOperation{
+		ID:          op.OperationID,
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		RequestBody: op.RequestBody != nil,
+	}
+	if o.ID == "" {
+		o.ID = method + " " + path
+	}
+	if o.Summary == "" {
+		o.Summary = op.Description
+	}
+	params := make([]openAPIParameter, 0, len(shared)+len(op.Parameters))
+	params = append(append(params, shared...), op.Parameters...)
+	for _, p := range params {
+		if p.In == "body" {
+			// Swagger 2 describes the request body as a parameter.
+			o.RequestBody = true
+			continue
+		}
+		typ := p.Schema.Type
+		if typ == "" {
+			typ = p.Type
+		}
+		o.Parameters = append(o.Parameters, APIParameter{
+			Name:        p.Name,
+			In:          p.In,
+			Type:        typ,
+			Description: p.Description,
+			Required:    p.Required || p.In == "path",
+		})
+	}
+	return o
+}
+
+func isHTTPMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead:
+		return true
+	}
+	return false
+}
+
+func formatAPIOperations(operations []APIOperation) string {
+	var sb strings.Builder
+	for _, op := range operations {
+		fmt.Fprintf(&sb, "%s: %s %s", op.ID, op.Method, op.Path)
+		if op.Summary != "" {
+			fmt.Fprintf(&sb, " - %s", op.Summary)
+		}
+		sb.WriteString("\n")
+		for _, p := range op.Parameters {
+			fmt.Fprintf(&sb, "  %s (%s %s", p.Name, p.In, p.Type)
+			if p.Required {
+				sb.WriteString(", required")
+			}
+			sb.WriteString(")")
+			if p.Description != "" {
+				fmt.Fprintf(&sb, ": %s", p.Description)
+			}
+			sb.WriteString("\n")
+		}
+		if op.RequestBody {
+			sb.WriteString("  takes a JSON body\n")
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
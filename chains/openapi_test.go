@@ -0,0 +1,93 @@
+package chains
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _testOpenAPISpec = `
+openapi: 3.0.0
+servers:
+  - url: https://pets.example.com/v1
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        schema:
+          type: integer
+    get:
+      operationId: getPet
+      summary: Get a pet by id
+      parameters:
+        - name: fields
+          in: query
+          description: the fields to return
+          schema:
+            type: string
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json: {}
+`
+
+// openAPITestLLM picks the getPet operation and answers with the API response.
+type openAPITestLLM struct{ operation string }
+
+func (l openAPITestLLM) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	prompt := promptValues[0].String()
+	text := l.operation
+	if !strings.Contains(prompt, "Pick the operation") {
+		response := prompt[strings.Index(prompt, "Here is the response from the API:")+len("Here is the response from the API:"):]
+		text = strings.TrimSpace(response[:strings.Index(response, "Now, summarize")])
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: text}}}}, nil
+}
+
+func (openAPITestLLM) GetNumTokens(text string) int { return len(text) }
+
+func TestOpenAPIChain(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"path": "` + r.URL.Path + `", "query": "` + r.URL.RawQuery + `"}`))
+	}))
+	defer server.Close()
+
+	llm := openAPITestLLM{operation: `{"operation": "getPet", "parameters": {"petId": 7, "fields": "name"}}`}
+	chain, err := NewOpenAPIChain(llm, []byte(_testOpenAPISpec),
+		WithOpenAPIBaseURL(server.URL+"/v1"),
+		WithOpenAPIHeaders(map[string]string{"Authorization": "Bearer secret"}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "createPet", chain.Operations[0].ID)
+	require.True(t, chain.Operations[0].RequestBody)
+	require.Equal(t, []APIParameter{
+		{Name: "petId", In: "path", Type: "integer", Required: true},
+		{Name: "fields", In: "query", Type: "string", Description: "the fields to return"},
+	}, chain.Operations[1].Parameters)
+
+	answer, err := Run(context.Background(), chain, "what is the name of pet 7?")
+	require.NoError(t, err)
+	require.Equal(t, "200 OK\n"+`{"path": "/v1/pets/7", "query": "fields=name"}`, answer)
+
+	chain.RequestChain.LLM = openAPITestLLM{operation: `{"operation": "getPet", "parameters": {}}`}
+	_, err = Run(context.Background(), chain, "what is the name of a pet?")
+	require.ErrorIs(t, err, ErrUnknownOperation)
+
+	_, err = NewOpenAPIChain(llm, []byte("swagger: '2.0'\npaths: {}"))
+	require.ErrorIs(t, err, ErrInvalidOpenAPISpec)
+}
@@ -0,0 +1,170 @@
+package chains
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultRouterTemplate = `Given an input to a language model, select the destination best suited for
+the input. You will be given the names of the available destinations and a
+description of what each destination is best suited for.
+
+<< DESTINATIONS >>
+{{.destinations}}
+
+Answer with the name of the destination only. If none of the destinations is
+suited for the input, answer with DEFAULT.
+
+<< INPUT >>
+{{.input}}
+
+<< DESTINATION >>`
+
+const (
+	// RouterDefaultDestination is the destination of the inputs given to the
+	// default chain of a MultiPromptRouter.
+	RouterDefaultDestination = "DEFAULT"
+	// RouterDestinationKey is the output key of the name of the destination an
+	// input was routed to.
+	RouterDestinationKey = "destination"
+
+	_routerDefaultInputKey  = "input"
+	_routerDefaultOutputKey = "text"
+)
+
+// Destination is a chain a MultiPromptRouter can route inputs to. The chain
+// must take a single input and return a single output.
+type Destination struct {
+	Name string
+	// Description tells the llm which inputs the destination is suited for.
+	Description string
+	Chain       Chain
+}
+
+// MultiPromptRouter is a chain asking an llm which destination chain is best
+// suited for its input, and calling that chain with it.
+type MultiPromptRouter struct {
+	// RouterChain is given the "input" and the "destinations" and returns the
+	// name of a destination.
+	RouterChain  *LLMChain
+	Destinations []Destination
+	// DefaultChain is called with the inputs the router chain does not route to
+	// a destination.
+	DefaultChain Chain
+	Memory       schema.Memory
+
+	InputKey  string
+	OutputKey string
+}
+
+var _ Chain = MultiPromptRouter{}
+
+// NewMultiPromptRouter creates a router chain using the llm to pick one of the
+// destinations. The inputs no destination is picked for are given to the
+// default chain, or to the llm if the default chain is nil. An error wrapping
+// ErrChainInitialization is returned if the names of the destinations are not
+// unique or if a chain does not take a single input and return a single output.
+func NewMultiPromptRouter(llm llms.LanguageModel, destinations []Destination, defaultChain Chain) (MultiPromptRouter, error) { //nolint:lll
+	if defaultChain == nil {
+		defaultChain = NewLLMChain(llm, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+	}
+
+	names := make(map[string]bool, len(destinations))
+	for _, d := range destinations {
+		key := strings.ToLower(d.Name)
+		if d.Name == "" || key == strings.ToLower(RouterDefaultDestination) || names[key] {
+			return MultiPromptRouter{}, fmt.Errorf("%w: invalid or duplicate destination name %q",
+				ErrChainInitialization, d.Name)
+		}
+		names[key] = true
+		if err := validateRouterDestination(d.Name, d.Chain); err != nil {
+			return MultiPromptRouter{}, err
+		}
+	}
+	if err := validateRouterDestination(RouterDefaultDestination, defaultChain); err != nil {
+		return MultiPromptRouter{}, err
+	}
+
+	return MultiPromptRouter{
+		RouterChain: NewLLMChain(llm, prompts.NewPromptTemplate(
+			_defaultRouterTemplate, []string{"destinations", "input"},
+		)),
+		Destinations: destinations,
+		DefaultChain: defaultChain,
+		Memory:       memory.NewSimple(),
+		InputKey:     _routerDefaultInputKey,
+		OutputKey:    _routerDefaultOutputKey,
+	}, nil
+}
+
+func validateRouterDestination(name string, chain Chain) error {
+	if chain == nil {
+		return fmt.Errorf("%w: destination %q has no chain", ErrChainInitialization, name)
+	}
+	if len(chain.GetInputKeys()) != 1 || len(chain.GetOutputKeys()) != 1 {
+		return fmt.Errorf("%w: the chain of destination %q has input keys %v and output keys %v, one of each is expected",
+			ErrChainInitialization, name, chain.GetInputKeys(), chain.GetOutputKeys())
+	}
+	return nil
+}
+
+// Call routes the input to a destination and returns the output of its chain
+// and the name of the destination.
+func (c MultiPromptRouter) Call(ctx context.Context, values map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	input, ok := values[c.InputKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
+	}
+
+	var descriptions strings.Builder
+	for _, d := range c.Destinations {
+		fmt.Fprintf(&descriptions, "%s: %s\n", d.Name, d.Description)
+	}
+	answer, err := Predict(ctx, c.RouterChain, map[string]any{
+		"destinations": strings.TrimSuffix(descriptions.String(), "\n"),
+		"input":        input,
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	name, chain := c.route(answer)
+	output, err := Run(ctx, chain, input, options...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{c.OutputKey: output, RouterDestinationKey: name}, nil
+}
+
+// route returns the destination named in the answer of the router chain, or
+// the default chain if the answer names no destination.
+func (c MultiPromptRouter) route(answer string) (string, Chain) { //nolint:ireturn
+	answer = strings.Trim(strings.TrimSpace(answer), "\"'`.")
+	for _, d := range c.Destinations {
+		if strings.EqualFold(answer, d.Name) {
+			return d.Name, d.Chain
+		}
+	}
+	return RouterDefaultDestination, c.DefaultChain
+}
+
+// GetMemory returns the memory of the chain.
+func (c MultiPromptRouter) GetMemory() schema.Memory { //nolint:ireturn
+	return c.Memory
+}
+
+// GetInputKeys returns the input key of the chain.
+func (c MultiPromptRouter) GetInputKeys() []string {
+	return []string{c.InputKey}
+}
+
+// GetOutputKeys returns the output key of the chain and the destination key.
+func (c MultiPromptRouter) GetOutputKeys() []string {
+	return []string{c.OutputKey, RouterDestinationKey}
+}
@@ -0,0 +1,64 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestMultiPromptRouter(t *testing.T) {
+	t.Parallel()
+
+	destinations := []Destination{
+		{
+			Name:        "math",
+			Description: "good for math questions",
+			Chain:       NewLLMChain(&testLanguageModel{expResult: "4"}, prompts.NewPromptTemplate("{{.input}}", []string{"input"})),
+		},
+		{
+			Name:        "physics",
+			Description: "good for physics questions",
+			Chain:       NewLLMChain(&testLanguageModel{expResult: "E=mc2"}, prompts.NewPromptTemplate("{{.input}}", []string{"input"})),
+		},
+	}
+
+	testCases := []struct {
+		route       string
+		output      string
+		destination string
+	}{
+		{" Math.", "4", "math"},
+		{"physics", "E=mc2", "physics"},
+		{"poetry", "fallback", RouterDefaultDestination},
+	}
+	for _, tc := range testCases {
+		routerLLM := &testLanguageModel{expResult: tc.route}
+		fallback := NewLLMChain(&testLanguageModel{expResult: "fallback"}, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+		router, err := NewMultiPromptRouter(routerLLM, destinations, fallback)
+		require.NoError(t, err)
+
+		outputs, err := Call(context.Background(), router, map[string]any{"input": "what is 2+2?"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"text": tc.output, RouterDestinationKey: tc.destination}, outputs)
+		require.Contains(t, routerLLM.recordedPrompt[0].String(), "math: good for math questions\nphysics: good for physics")
+	}
+}
+
+func TestMultiPromptRouterValidation(t *testing.T) {
+	t.Parallel()
+
+	chain := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+	twoInputs := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.a}}{{.b}}", []string{"a", "b"}))
+
+	for _, destinations := range [][]Destination{
+		{{Name: "a", Chain: chain}, {Name: "A", Chain: chain}},
+		{{Name: "default", Chain: chain}},
+		{{Name: "a"}},
+		{{Name: "a", Chain: twoInputs}},
+	} {
+		_, err := NewMultiPromptRouter(&testLanguageModel{}, destinations, nil)
+		require.ErrorIs(t, err, ErrChainInitialization)
+	}
+}
@@ -0,0 +1,282 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	// ErrUnsupportedStep is returned when a runnable is built from a value that
+	// is not a supported step, see Pipe.
+	ErrUnsupportedStep = errors.New("unsupported runnable step")
+	// ErrInvalidStepInput is returned when a step of a runnable is given an
+	// input of a type it does not accept.
+	ErrInvalidStepInput = errors.New("invalid runnable step input")
+	// ErrNoBranch is returned when no case of a Branch without default step
+	// matches the input.
+	ErrNoBranch = errors.New("no branch matches the input")
+)
+
+// Runnable is a composable step of a pipeline, built with Pipe, Map, Branch or
+// Fallback.
+type Runnable interface {
+	// Invoke runs the step with the input.
+	Invoke(ctx context.Context, input any, options ...ChainCallOption) (any, error)
+	// Stream runs the step with the input, calling fn with the chunks streamed
+	// by the llms of the step.
+	Stream(ctx context.Context, input any, fn func(ctx context.Context, chunk []byte) error, options ...ChainCallOption) (any, error) //nolint:lll
+	// Batch runs the step with each of the inputs concurrently.
+	Batch(ctx context.Context, inputs []any, options ...ChainCallOption) ([]any, error)
+}
+
+// RunnableFunc is a function usable as a step of a pipeline.
+type RunnableFunc func(ctx context.Context, input any) (any, error)
+
+// runnable implements Runnable with an invoke function.
+type runnable struct {
+	invoke func(ctx context.Context, input any, options ...ChainCallOption) (any, error)
+}
+
+var _ Runnable = runnable{}
+
+func (r runnable) Invoke(ctx context.Context, input any, options ...ChainCallOption) (any, error) {
+	return r.invoke(ctx, input, options...)
+}
+
+func (r runnable) Stream(ctx context.Context, input any, fn func(ctx context.Context, chunk []byte) error, options ...ChainCallOption) (any, error) { //nolint:lll
+	return r.invoke(ctx, input, append(options, WithStreamingFunc(fn))...)
+}
+
+func (r runnable) Batch(ctx context.Context, inputs []any, options ...ChainCallOption) ([]any, error) {
+	outputs := make([]any, len(inputs))
+	errs := make([]error, len(inputs))
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		wg.Add(1)
+		go func(i int, input any) {
+			defer wg.Done()
+			outputs[i], errs[i] = r.invoke(ctx, input, options...)
+		}(i, input)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+	}
+	return outputs, nil
+}
+
+// Pipe returns a runnable running the steps in sequence, each step being given
+// the output of the previous one. The steps can be:
+//
+//   - a prompts.FormatPrompter, formatting a map[string]any, or a string when
+//     the prompt has a single input variable, into a schema.PromptValue.
+//   - an llms.LanguageModel, generating the text of a schema.PromptValue or a
+//     string.
+//   - a schema.OutputParser[any], parsing a string.
+//   - a Chain, called with a map[string]any, or a string when the chain has a
+//     single input key, and returning its output values.
+//   - a RunnableFunc or a func(context.Context, any) (any, error).
+//   - a Runnable.
+//
+// For example:
+//
+//	r := chains.Pipe(prompt, llm, outputparser.NewSimple())
+//	out, err := r.Invoke(ctx, map[string]any{"topic": "otters"})
+func Pipe(steps ...any) Runnable {
+	runnables := make([]Runnable, len(steps))
+	for i, step := range steps {
+		runnables[i] = AsRunnable(step)
+	}
+	return runnable{invoke: func(ctx context.Context, input any, options ...ChainCallOption) (any, error) {
+		var err error
+		for _, r := range runnables {
+			input, err = r.Invoke(ctx, input, options...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return input, nil
+	}}
+}
+
+// Map returns a runnable running the steps concurrently with the same input
+// and returning their outputs by key as a map[string]any.
+func Map(steps map[string]any) Runnable {
+	runnables := make(map[string]Runnable, len(steps))
+	for key, step := range steps {
+		runnables[key] = AsRunnable(step)
+	}
+	return runnable{invoke: func(ctx context.Context, input any, options ...ChainCallOption) (any, error) {
+		var (
+			mu      sync.Mutex
+			wg      sync.WaitGroup
+			outputs = make(map[string]any, len(runnables))
+			errs    []error
+		)
+		for key, r := range runnables {
+			wg.Add(1)
+			go func(key string, r Runnable) {
+				defer wg.Done()
+				output, err := r.Invoke(ctx, input, options...)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", key, err))
+					return
+				}
+				outputs[key] = output
+			}(key, r)
+		}
+		wg.Wait()
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		return outputs, nil
+	}}
+}
+
+// Case is a case of a Branch, see When.
+type Case struct {
+	Condition func(ctx context.Context, input any) bool
+	Step      any
+}
+
+// When returns a case of a Branch running the step when the condition holds.
+func When(condition func(ctx context.Context, input any) bool, step any) Case {
+	return Case{Condition: condition, Step: step}
+}
+
+// Branch returns a runnable running the step of the first case whose condition
+// holds for the input, or the default step if none does. A nil default step
+// makes the runnable return ErrNoBranch.
+func Branch(defaultStep any, cases ...Case) Runnable {
+	runnables := make([]Runnable, len(cases))
+	for i, c := range cases {
+		runnables[i] = AsRunnable(c.Step)
+	}
+	var defaultRunnable Runnable
+	if defaultStep != nil {
+		defaultRunnable = AsRunnable(defaultStep)
+	}
+	return runnable{invoke: func(ctx context.Context, input any, options ...ChainCallOption) (any, error) {
+		for i, c := range cases {
+			if c.Condition(ctx, input) {
+				return runnables[i].Invoke(ctx, input, options...)
+			}
+		}
+		if defaultRunnable == nil {
+			return nil, ErrNoBranch
+		}
+		return defaultRunnable.Invoke(ctx, input, options...)
+	}}
+}
+
+// Fallback returns a runnable running the primary step, and the fallbacks in
+// order while the previous steps fail. The errors of all the steps are
+// returned if they all fail. Cancellations are not retried.
+func Fallback(primary any, fallbacks ...any) Runnable {
+	runnables := make([]Runnable, 0, len(fallbacks)+1)
+	runnables = append(runnables, AsRunnable(primary))
+	for _, step := range fallbacks {
+		runnables = append(runnables, AsRunnable(step))
+	}
+	return runnable{invoke: func(ctx context.Context, input any, options ...ChainCallOption) (any, error) {
+		errs := make([]error, 0, len(runnables))
+		for _, r := range runnables {
+			output, err := r.Invoke(ctx, input, options...)
+			if err == nil {
+				return output, nil
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return nil, errors.Join(errs...)
+	}}
+}
+
+// AsRunnable returns the runnable of a step, see Pipe for the supported steps.
+// Unsupported steps return ErrUnsupportedStep when run.
+func AsRunnable(step any) Runnable { //nolint:ireturn,cyclop
+	switch s := step.(type) {
+	case Runnable:
+		return s
+	case RunnableFunc:
+		return runnable{invoke: func(ctx context.Context, input any, _ ...ChainCallOption) (any, error) {
+			return s(ctx, input)
+		}}
+	case func(context.Context, any) (any, error):
+		return AsRunnable(RunnableFunc(s))
+	case Chain:
+		return runnable{invoke: func(ctx context.Context, input any, options ...ChainCallOption) (any, error) {
+			values, err := inputValues(input, s.GetInputKeys())
+			if err != nil {
+				return nil, err
+			}
+			return Call(ctx, s, values, options...)
+		}}
+	case prompts.FormatPrompter:
+		return runnable{invoke: func(_ context.Context, input any, _ ...ChainCallOption) (any, error) {
+			values, err := inputValues(input, s.GetInputVariables())
+			if err != nil {
+				return nil, err
+			}
+			return s.FormatPrompt(values)
+		}}
+	case llms.LanguageModel:
+		return runnable{invoke: func(ctx context.Context, input any, options ...ChainCallOption) (any, error) {
+			var promptValue schema.PromptValue
+			switch in := input.(type) {
+			case schema.PromptValue:
+				promptValue = in
+			case string:
+				promptValue = prompts.StringPromptValue(in)
+			default:
+				return nil, fmt.Errorf("%w: llm given %T", ErrInvalidStepInput, input)
+			}
+			result, err := s.GeneratePrompt(ctx, []schema.PromptValue{promptValue}, getLLMCallOptions(options...)...)
+			if err != nil {
+				return nil, err
+			}
+			if len(result.Generations) == 0 || len(result.Generations[0]) == 0 {
+				return "", nil
+			}
+			return result.Generations[0][0].Text, nil
+		}}
+	case schema.OutputParser[any]:
+		return runnable{invoke: func(_ context.Context, input any, _ ...ChainCallOption) (any, error) {
+			text, ok := input.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: output parser given %T", ErrInvalidStepInput, input)
+			}
+			return s.Parse(text)
+		}}
+	}
+	return runnable{invoke: func(context.Context, any, ...ChainCallOption) (any, error) {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedStep, step)
+	}}
+}
+
+// inputValues returns the input as input values, a string being the value of
+// the only key.
+func inputValues(input any, keys []string) (map[string]any, error) {
+	switch in := input.(type) {
+	case map[string]any:
+		return in, nil
+	case string:
+		if len(keys) != 1 {
+			return nil, fmt.Errorf("%w: string given to a step with %d inputs", ErrInvalidStepInput, len(keys))
+		}
+		return map[string]any{keys[0]: in}, nil
+	}
+	return nil, fmt.Errorf("%w: %T", ErrInvalidStepInput, input)
+}
@@ -0,0 +1,92 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+func TestPipe(t *testing.T) {
+	t.Parallel()
+
+	prompt := prompts.NewPromptTemplate("Hello {{.name}}", []string{"name"})
+	upper := func(_ context.Context, input any) (any, error) {
+		return strings.ToUpper(input.(string)), nil //nolint:forcetypeassert
+	}
+	r := Pipe(prompt, streamingLanguageModel{}, outputparser.NewSimple(), upper)
+
+	out, err := r.Invoke(context.Background(), "otters")
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO OTTERS", out)
+
+	var chunks []string
+	out, err = r.Stream(context.Background(), map[string]any{"name": "bob"},
+		func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO BOB", out)
+	assert.Equal(t, []string{"Hello ", "bob"}, chunks)
+
+	outs, err := r.Batch(context.Background(), []any{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"HELLO A", "HELLO B"}, outs)
+
+	_, err = Pipe(prompt, 42).Invoke(context.Background(), "a")
+	require.ErrorIs(t, err, ErrUnsupportedStep)
+	_, err = r.Invoke(context.Background(), 1)
+	require.ErrorIs(t, err, ErrInvalidStepInput)
+}
+
+func TestPipeChain(t *testing.T) {
+	t.Parallel()
+
+	chain := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.input}}!", []string{"input"}))
+	out, err := Pipe(chain).Invoke(context.Background(), "hi")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"text": "hi!"}, out)
+}
+
+func TestMapBranchFallback(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+	failing := RunnableFunc(func(context.Context, any) (any, error) { return nil, errFailed })
+	echo := RunnableFunc(func(_ context.Context, input any) (any, error) { return input, nil })
+	constant := func(v string) RunnableFunc {
+		return func(context.Context, any) (any, error) { return v, nil }
+	}
+
+	out, err := Map(map[string]any{"echo": echo, "const": constant("c")}).Invoke(ctx, "in")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"echo": "in", "const": "c"}, out)
+	_, err = Map(map[string]any{"echo": echo, "fail": failing}).Invoke(ctx, "in")
+	require.ErrorIs(t, err, errFailed)
+
+	isQuestion := func(_ context.Context, input any) bool {
+		return strings.HasSuffix(input.(string), "?") //nolint:forcetypeassert
+	}
+	branch := Branch(constant("statement"), When(isQuestion, constant("question")))
+	out, err = branch.Invoke(ctx, "why?")
+	require.NoError(t, err)
+	assert.Equal(t, "question", out)
+	out, err = branch.Invoke(ctx, "because")
+	require.NoError(t, err)
+	assert.Equal(t, "statement", out)
+	_, err = Branch(nil, When(isQuestion, echo)).Invoke(ctx, "because")
+	require.ErrorIs(t, err, ErrNoBranch)
+
+	out, err = Fallback(failing, failing, constant("backup")).Invoke(ctx, "in")
+	require.NoError(t, err)
+	assert.Equal(t, "backup", out)
+	_, err = Fallback(failing, failing).Invoke(ctx, "in")
+	require.ErrorIs(t, err, errFailed)
+}
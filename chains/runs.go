@@ -0,0 +1,227 @@
+package chains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+)
+
+var (
+	// ErrRunStopped is the cause of the cancellation of runs stopped with
+	// RunManager.Stop, and is returned by RunManager.Call for them.
+	ErrRunStopped = errors.New("run stopped by user")
+	// ErrRunNotFound is returned by RunManager.Stop when no run with the id is
+	// in flight.
+	ErrRunNotFound = errors.New("run not found")
+	// ErrDuplicateRunID is returned by RunManager.Call when a run with the same
+	// id is in flight.
+	ErrDuplicateRunID = errors.New("duplicate run id")
+	// ErrShuttingDown is returned by RunManager.Call once RunManager.Shutdown
+	// has been called.
+	ErrShuttingDown = errors.New("run manager shutting down")
+	// ErrRunInterrupted is the cause of the cancellation of runs still in
+	// flight at the end of RunManager.Shutdown, and is returned by
+	// RunManager.Call for them.
+	ErrRunInterrupted = errors.New("run interrupted by shutdown")
+)
+
+// Flusher is implemented by sinks buffering what they record, such as audit
+// or metrics sinks, to write it out on shutdown.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// RunState is the state of a run of a RunManager.
+type RunState string
+
+const (
+	RunStateRunning   RunState = "running"
+	RunStateCompleted RunState = "completed"
+	RunStateFailed    RunState = "failed"
+	RunStateStopped   RunState = "stopped"
+)
+
+// RunInfo describes a run of a chain made with a RunManager.
+type RunInfo struct {
+	ID    string
+	State RunState
+	// Partial is the text streamed by the chain so far. It holds the partial
+	// answer of stopped runs.
+	Partial string
+	// Outputs are the outputs of completed runs.
+	Outputs map[string]any
+	// Err is the error of failed runs.
+	Err error
+}
+
+// RunManager calls chains under a run id, so that they can be stopped with
+// Stop while in flight. Completed runs are kept until removed with Forget.
+// Shutdown drains the runs in flight before the process exits.
+type RunManager struct {
+	mu           sync.Mutex
+	runs         map[string]*managedRun
+	shuttingDown bool
+	inFlight     sync.WaitGroup
+}
+
+type managedRun struct {
+	run     RunInfo
+	partial strings.Builder
+	cancel  context.CancelCauseFunc
+}
+
+// NewRunManager creates a new RunManager.
+func NewRunManager() *RunManager {
+	return &RunManager{runs: make(map[string]*managedRun)}
+}
+
+// Call calls the chain like Call, registering the run under the id, which is
+// set in the context with callbacks.WithRunID. The text streamed with the
+// streaming function of the options is recorded as the partial result of the
+// run, see WithStreamingFunc. Runs stopped with Stop return ErrRunStopped.
+func (m *RunManager) Call(ctx context.Context, runID string, c Chain, inputValues map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	ctx, cancel := context.WithCancelCause(callbacks.WithRunID(ctx, runID))
+	defer cancel(nil)
+
+	r := &managedRun{run: RunInfo{ID: runID, State: RunStateRunning}, cancel: cancel}
+	m.mu.Lock()
+	if m.shuttingDown {
+		m.mu.Unlock()
+		return nil, ErrShuttingDown
+	}
+	if existing, ok := m.runs[runID]; ok && existing.run.State == RunStateRunning {
+		m.mu.Unlock()
+		return nil, ErrDuplicateRunID
+	}
+	m.runs[runID] = r
+	m.inFlight.Add(1)
+	m.mu.Unlock()
+	defer m.inFlight.Done()
+
+	outputs, err := Call(ctx, c, inputValues, append(options, m.recordStream(r, options...))...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r.cancel = nil
+	r.run.Partial = r.partial.String()
+	switch {
+	case errors.Is(context.Cause(ctx), ErrRunStopped):
+		r.run.State = RunStateStopped
+		return nil, ErrRunStopped
+	case errors.Is(context.Cause(ctx), ErrRunInterrupted):
+		r.run.State = RunStateStopped
+		return nil, ErrRunInterrupted
+	case err != nil:
+		r.run.State = RunStateFailed
+		r.run.Err = err
+	default:
+		r.run.State = RunStateCompleted
+		r.run.Outputs = outputs
+	}
+	return outputs, err
+}
+
+// Stop stops the run in flight with the id, canceling its context with
+// ErrRunStopped as cause. Tools implementing tools.Interrupter are interrupted
+// by the agent executor.
+func (m *RunManager) Stop(runID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.runs[runID]
+	if !ok || r.cancel == nil {
+		return ErrRunNotFound
+	}
+	r.cancel(ErrRunStopped)
+	return nil
+}
+
+// Shutdown stops accepting new runs and waits for the runs in flight to
+// finish. When the context is done first, the remaining runs are canceled with
+// ErrRunInterrupted as cause, which makes the agent executor interrupt their
+// tool calls, and Shutdown waits for them to return. The flushers are then
+// flushed. The returned error joins the error of the context, if it ended the
+// wait, and the errors of the flushers.
+func (m *RunManager) Shutdown(ctx context.Context, flushers ...Flusher) error {
+	m.mu.Lock()
+	m.shuttingDown = true
+	m.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		m.inFlight.Wait()
+		close(drained)
+	}()
+
+	var errs []error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+		m.mu.Lock()
+		for _, r := range m.runs {
+			if r.cancel != nil {
+				r.cancel(ErrRunInterrupted)
+			}
+		}
+		m.mu.Unlock()
+		<-drained
+	}
+
+	// The flushers are given a new context, as the one of the shutdown may
+	// already be done.
+	for _, f := range flushers {
+		if err := f.Flush(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Get returns the run with the id, if known.
+func (m *RunManager) Get(runID string) (RunInfo, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.runs[runID]
+	if !ok {
+		return RunInfo{}, false
+	}
+	run := r.run
+	if run.State == RunStateRunning {
+		run.Partial = r.partial.String()
+	}
+	return run, true
+}
+
+// Forget removes a run that is no longer in flight.
+func (m *RunManager) Forget(runID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := m.runs[runID]; ok && r.cancel == nil {
+		delete(m.runs, runID)
+	}
+}
+
+// recordStream returns an option recording the streamed text in the partial
+// result of the run. Without a streaming function in the options, streaming is
+// left disabled and nothing is recorded.
+func (m *RunManager) recordStream(r *managedRun, options ...ChainCallOption) ChainCallOption {
+	opts := &chainCallOption{}
+	for _, opt := range options {
+		opt(opts)
+	}
+	streamingFunc := opts.StreamingFunc
+	return func(o *chainCallOption) {
+		if streamingFunc == nil {
+			return
+		}
+		o.StreamingFunc = func(ctx context.Context, chunk []byte) error {
+			m.mu.Lock()
+			r.partial.Write(chunk)
+			m.mu.Unlock()
+			return streamingFunc(ctx, chunk)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package chains
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// streamingLanguageModel streams the words of the prompt, then blocks until
+// canceled if the prompt contains "wait".
+type streamingLanguageModel struct {
+	streamed chan struct{}
+}
+
+func (l streamingLanguageModel) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	prompt := promptValues[0].String()
+	for _, word := range strings.SplitAfter(prompt, " ") {
+		if opts.StreamingFunc != nil {
+			if err := opts.StreamingFunc(ctx, []byte(word)); err != nil {
+				return llms.LLMResult{}, err
+			}
+		}
+	}
+	if strings.Contains(prompt, "wait") {
+		close(l.streamed)
+		<-ctx.Done()
+		return llms.LLMResult{}, ctx.Err()
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: prompt}}}}, nil
+}
+
+func (l streamingLanguageModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func TestRunManagerStop(t *testing.T) {
+	t.Parallel()
+
+	llm := streamingLanguageModel{streamed: make(chan struct{})}
+	c := NewLLMChain(llm, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+	m := NewRunManager()
+
+	var streamed strings.Builder
+	done := make(chan error)
+	go func() {
+		_, err := m.Call(context.Background(), "run", c, map[string]any{"input": "please wait"},
+			WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+				streamed.Write(chunk)
+				return nil
+			}))
+		done <- err
+	}()
+
+	<-llm.streamed
+	run, ok := m.Get("run")
+	require.True(t, ok)
+	assert.Equal(t, RunStateRunning, run.State)
+	_, err := m.Call(context.Background(), "run", c, map[string]any{"input": "hi"})
+	require.ErrorIs(t, err, ErrDuplicateRunID)
+
+	require.NoError(t, m.Stop("run"))
+	require.ErrorIs(t, <-done, ErrRunStopped)
+	run, _ = m.Get("run")
+	assert.Equal(t, RunInfo{ID: "run", State: RunStateStopped, Partial: "please wait"}, run)
+	assert.Equal(t, "please wait", streamed.String())
+	require.ErrorIs(t, m.Stop("run"), ErrRunNotFound)
+
+	m.Forget("run")
+	_, ok = m.Get("run")
+	assert.False(t, ok)
+}
+
+func TestRunManagerCompleted(t *testing.T) {
+	t.Parallel()
+
+	c := NewLLMChain(streamingLanguageModel{}, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+	m := NewRunManager()
+
+	outputs, err := m.Call(context.Background(), "run", c, map[string]any{"input": "hi"})
+	require.NoError(t, err)
+	run, ok := m.Get("run")
+	require.True(t, ok)
+	assert.Equal(t, RunInfo{ID: "run", State: RunStateCompleted, Outputs: outputs}, run)
+	require.ErrorIs(t, m.Stop("unknown"), ErrRunNotFound)
+}
+
+type countingFlusher struct{ flushed int }
+
+func (f *countingFlusher) Flush(context.Context) error {
+	f.flushed++
+	return nil
+}
+
+func TestRunManagerShutdown(t *testing.T) {
+	t.Parallel()
+
+	llm := streamingLanguageModel{streamed: make(chan struct{})}
+	c := NewLLMChain(llm, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+	m := NewRunManager()
+
+	done := make(chan error)
+	go func() {
+		_, err := m.Call(context.Background(), "run", c, map[string]any{"input": "please wait"})
+		done <- err
+	}()
+	<-llm.streamed
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	flusher := &countingFlusher{}
+	err := m.Shutdown(ctx, flusher)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, <-done, ErrRunInterrupted)
+	assert.Equal(t, 1, flusher.flushed)
+	run, _ := m.Get("run")
+	assert.Equal(t, RunStateStopped, run.State)
+
+	_, err = m.Call(context.Background(), "other", c, map[string]any{"input": "hi"})
+	require.ErrorIs(t, err, ErrShuttingDown)
+	require.NoError(t, NewRunManager().Shutdown(context.Background()))
+}
@@ -0,0 +1,92 @@
+package chains
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/safety"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// SafetyAnnotationsKey is the output key of the annotations of the safety
+// checks, set when a checker annotates the response.
+const SafetyAnnotationsKey = "safety_annotations"
+
+// SafeChain is a chain checking the string outputs of another chain with
+// safety checkers. The memory of the chain is saved after the checks, so
+// blocked responses are not remembered.
+type SafeChain struct {
+	Chain    Chain
+	Checkers []safety.Checker
+}
+
+var (
+	_ Chain                  = SafeChain{}
+	_ callbacks.HandlerHaver = SafeChain{}
+)
+
+// NewSafeChain creates a chain checking the outputs of the chain with the
+// checkers.
+func NewSafeChain(chain Chain, checkers ...safety.Checker) SafeChain {
+	return SafeChain{Chain: chain, Checkers: checkers}
+}
+
+// Call calls the chain and checks its string outputs. Blocked outputs make the
+// call fail with safety.ErrBlocked.
+func (c SafeChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
+	outputs, err := c.Chain.Call(ctx, inputs, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := checkStringValues(ctx, outputs, c.Checkers)
+	if err != nil {
+		return nil, err
+	}
+	if len(annotations) > 0 {
+		outputs[SafetyAnnotationsKey] = annotations
+	}
+	return outputs, nil
+}
+
+// GetMemory returns the memory of the chain.
+func (c SafeChain) GetMemory() schema.Memory { //nolint:ireturn
+	return c.Chain.GetMemory()
+}
+
+// GetInputKeys returns the input keys of the chain.
+func (c SafeChain) GetInputKeys() []string {
+	return c.Chain.GetInputKeys()
+}
+
+// GetOutputKeys returns the output keys of the chain.
+func (c SafeChain) GetOutputKeys() []string {
+	return c.Chain.GetOutputKeys()
+}
+
+// GetCallbackHandler returns the callbacks handler of the chain, if any.
+func (c SafeChain) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	if hh, ok := c.Chain.(callbacks.HandlerHaver); ok {
+		return hh.GetCallbackHandler()
+	}
+	return nil
+}
+
+// checkStringValues checks the string values with the checkers, replacing
+// them with their possibly redacted text, and returns the annotations.
+func checkStringValues(ctx context.Context, values map[string]any, checkers []safety.Checker) ([]string, error) {
+	var annotations []string
+	for key, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+		result, err := safety.Check(ctx, safety.Input{Text: text}, checkers...)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = result.Text
+		annotations = append(annotations, result.Annotations...)
+	}
+	return annotations, nil
+}
@@ -0,0 +1,28 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/safety"
+)
+
+func TestSafeChain(t *testing.T) {
+	t.Parallel()
+
+	annotate, err := safety.NewDenylist(safety.Annotate, `otters`)
+	require.NoError(t, err)
+	block, err := safety.NewDenylist(safety.Block, `forbidden`)
+	require.NoError(t, err)
+
+	c := NewSafeChain(NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("about {{.topic}}", []string{"topic"})), annotate, block) //nolint:lll
+	outputs, err := Call(context.Background(), c, map[string]any{"topic": "otters"})
+	require.NoError(t, err)
+	require.Equal(t, "about otters", outputs["text"])
+	require.Equal(t, []string{`denylisted content matching "otters"`}, outputs[SafetyAnnotationsKey])
+
+	_, err = Call(context.Background(), c, map[string]any{"topic": "forbidden things"})
+	require.ErrorIs(t, err, safety.ErrBlocked)
+}
@@ -0,0 +1,168 @@
+package chains
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ValueType is the type of an input or output value of a chain.
+type ValueType string
+
+const (
+	// ValueTypeAny accepts values of any type.
+	ValueTypeAny ValueType = "any"
+	// ValueTypeString accepts strings.
+	ValueTypeString ValueType = "string"
+	// ValueTypeNumber accepts integers and floats.
+	ValueTypeNumber ValueType = "number"
+	// ValueTypeBoolean accepts booleans.
+	ValueTypeBoolean ValueType = "boolean"
+	// ValueTypeArray accepts slices and arrays.
+	ValueTypeArray ValueType = "array"
+	// ValueTypeObject accepts maps and structs.
+	ValueTypeObject ValueType = "object"
+	// ValueTypeDocuments accepts a []schema.Document.
+	ValueTypeDocuments ValueType = "documents"
+)
+
+// KeySchema describes an input or output value of a chain.
+type KeySchema struct {
+	// Name is the key of the value.
+	Name string
+	// Type is the type of the value.
+	Type ValueType
+	// Description documents the value.
+	Description string
+}
+
+// SchemaChain is implemented by chains declaring the types of their input and
+// output values. The values are checked by Call, and the schemas can be turned
+// into JSON schemas with InputJSONSchema and OutputJSONSchema.
+type SchemaChain interface {
+	Chain
+	// GetInputSchema returns the schema of each of the input keys.
+	GetInputSchema() []KeySchema
+	// GetOutputSchema returns the schema of each of the output keys.
+	GetOutputSchema() []KeySchema
+}
+
+// InputSchema returns the schema of the inputs of the chain. Inputs of chains
+// not implementing SchemaChain are of type ValueTypeAny.
+func InputSchema(c Chain) []KeySchema {
+	if sc, ok := c.(SchemaChain); ok {
+		return sc.GetInputSchema()
+	}
+	return anySchema(c.GetInputKeys())
+}
+
+// OutputSchema returns the schema of the outputs of the chain. Outputs of chains
+// not implementing SchemaChain are of type ValueTypeAny.
+func OutputSchema(c Chain) []KeySchema {
+	if sc, ok := c.(SchemaChain); ok {
+		return sc.GetOutputSchema()
+	}
+	return anySchema(c.GetOutputKeys())
+}
+
+// InputJSONSchema returns the JSON schema of the inputs of the chain, for
+// example to validate requests or to document an api serving the chain.
+func InputJSONSchema(c Chain) map[string]any {
+	return JSONSchema(InputSchema(c))
+}
+
+// OutputJSONSchema returns the JSON schema of the outputs of the chain.
+func OutputJSONSchema(c Chain) map[string]any {
+	return JSONSchema(OutputSchema(c))
+}
+
+// JSONSchema returns the JSON schema of an object holding the keys.
+func JSONSchema(keys []KeySchema) map[string]any {
+	properties := make(map[string]any, len(keys))
+	required := make([]string, 0, len(keys))
+	for _, k := range keys {
+		property := jsonSchemaType(k.Type)
+		if k.Description != "" {
+			property["description"] = k.Description
+		}
+		properties[k.Name] = property
+		required = append(required, k.Name)
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func jsonSchemaType(t ValueType) map[string]any {
+	switch t {
+	case ValueTypeString, ValueTypeNumber, ValueTypeBoolean, ValueTypeArray, ValueTypeObject:
+		return map[string]any{"type": string(t)}
+	case ValueTypeDocuments:
+		return map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"page_content": map[string]any{"type": "string"},
+					"metadata":     map[string]any{"type": "object"},
+				},
+			},
+		}
+	case ValueTypeAny:
+	}
+	return map[string]any{}
+}
+
+func anySchema(keys []string) []KeySchema {
+	s := make([]KeySchema, 0, len(keys))
+	for _, k := range keys {
+		s = append(s, KeySchema{Name: k, Type: ValueTypeAny})
+	}
+	return s
+}
+
+// validateTypes checks the values against the schema of the keys. Missing
+// values are left to validateInputs and validateOutputs.
+func validateTypes(keys []KeySchema, values map[string]any) error {
+	for _, k := range keys {
+		v, ok := values[k.Name]
+		if !ok || hasType(v, k.Type) {
+			continue
+		}
+		return fmt.Errorf("%v is %T, not %s", k.Name, v, k.Type)
+	}
+	return nil
+}
+
+func hasType(v any, t ValueType) bool {
+	if t == ValueTypeDocuments {
+		_, ok := v.([]schema.Document)
+		return ok
+	}
+	if t == ValueTypeAny {
+		return true
+	}
+	if v == nil {
+		return false
+	}
+
+	switch reflect.TypeOf(v).Kind() { //nolint:exhaustive
+	case reflect.String:
+		return t == ValueTypeString
+	case reflect.Bool:
+		return t == ValueTypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return t == ValueTypeNumber
+	case reflect.Slice, reflect.Array:
+		return t == ValueTypeArray
+	case reflect.Map, reflect.Struct:
+		return t == ValueTypeObject
+	default:
+		return false
+	}
+}
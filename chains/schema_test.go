@@ -0,0 +1,66 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestCallValidatesInputTypes(t *testing.T) {
+	t.Parallel()
+
+	c := NewStuffDocuments(NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.context}}", []string{"context"})))
+
+	_, err := Call(context.Background(), c, map[string]any{"input_documents": "not documents"})
+	require.ErrorIs(t, err, ErrInvalidInputValues)
+	require.ErrorIs(t, err, ErrInputValuesWrongType)
+
+	out, err := Call(context.Background(), c, map[string]any{
+		"input_documents": []schema.Document{{PageContent: "foo"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "foo", out["text"])
+}
+
+func TestValidateTypes(t *testing.T) {
+	t.Parallel()
+
+	keys := []KeySchema{
+		{Name: "s", Type: ValueTypeString},
+		{Name: "n", Type: ValueTypeNumber},
+		{Name: "b", Type: ValueTypeBoolean},
+		{Name: "a", Type: ValueTypeArray},
+		{Name: "o", Type: ValueTypeObject},
+		{Name: "any", Type: ValueTypeAny},
+	}
+	require.NoError(t, validateTypes(keys, map[string]any{
+		"s": "x", "n": 1.5, "b": true, "a": []int{1}, "o": map[string]any{}, "any": nil,
+	}))
+	require.NoError(t, validateTypes(keys, map[string]any{"n": 3}))
+	require.Error(t, validateTypes(keys, map[string]any{"n": "3"}))
+	require.Error(t, validateTypes(keys, map[string]any{"s": nil}))
+}
+
+func TestInputJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"question": map[string]any{"type": "string", "description": "The math question to answer."},
+		},
+		"required": []string{"question"},
+	}, InputJSONSchema(NewLLMMathChain(&testLanguageModel{})))
+
+	// Chains without schema accept values of any type.
+	c := NewLLMChain(&testLanguageModel{}, prompts.NewPromptTemplate("{{.foo}}", []string{"foo"}))
+	require.Equal(t, map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"foo": map[string]any{}},
+		"required":   []string{"foo"},
+	}, InputJSONSchema(c))
+	require.Equal(t, []KeySchema{{Name: "text", Type: ValueTypeAny}}, OutputSchema(c))
+}
@@ -22,6 +22,12 @@ type SequentialChain struct {
 	memory     schema.Memory
 }
 
+// NewSequentialChain creates a chain running the chains in order. Each chain is
+// given the input values and the outputs of the chains before it, and the
+// chain returns the values of the output keys. The keys are validated when the
+// chain is created: an error wrapping ErrChainInitialization is returned if a
+// chain needs a key that is not known before it runs, if a chain outputs a key
+// that is already known, or if an output key is never set.
 func NewSequentialChain(chains []Chain, inputKeys []string, outputKeys []string, opts ...SequentialChainOption) (*SequentialChain, error) { //nolint:lll
 	s := &SequentialChain{
 		chains:     chains,
@@ -98,15 +104,25 @@ func (c *SequentialChain) validateSeqChain() error {
 // not be called directly. Use rather the Call, Run or Predict functions that
 // handles the memory and other aspects of the chain.
 func (c *SequentialChain) Call(ctx context.Context, inputs map[string]any, options ...ChainCallOption) (map[string]any, error) { //nolint:lll
-	var outputs map[string]any
-	var err error
+	// The values known so far, the inputs and the outputs of the chains run
+	// before, are all available to the next chain.
+	knownValues := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		knownValues[key] = value
+	}
 	for _, chain := range c.chains {
-		outputs, err = Call(ctx, chain, inputs, options...)
+		outputs, err := Call(ctx, chain, knownValues, options...)
 		if err != nil {
 			return nil, err
 		}
-		// Set the input for the next chain to the output of the current chain
-		inputs = outputs
+		for key, value := range outputs {
+			knownValues[key] = value
+		}
+	}
+
+	outputs := make(map[string]any, len(c.outputKeys))
+	for _, key := range c.outputKeys {
+		outputs[key] = knownValues[key]
 	}
 	return outputs, nil
 }
@@ -144,6 +160,10 @@ type SimpleSequentialChain struct {
 	memory schema.Memory
 }
 
+// NewSimpleSequentialChain creates a chain giving its "input" to the first
+// chain and the output of each chain to the next one, and returning the output
+// of the last chain as "output". An error is returned if a chain does not have
+// a single input key and a single output key.
 func NewSimpleSequentialChain(chains []Chain) (*SimpleSequentialChain, error) {
 	if err := validateSimpleSeq(chains); err != nil {
 		return nil, err
@@ -201,8 +221,11 @@ func (c *SimpleSequentialChain) GetOutputKeys() []string {
 	return []string{output}
 }
 
+// SequentialChainOption is a function that configures a SequentialChain.
 type SequentialChainOption func(*SequentialChain)
 
+// WithSeqChainMemory sets the memory of a SequentialChain. The memory keys are
+// known to the chains along with the input keys.
 func WithSeqChainMemory(memory schema.Memory) SequentialChainOption {
 	return func(c *SequentialChain) {
 		c.memory = memory
@@ -121,6 +121,34 @@ func TestSequentialChain(t *testing.T) {
 	assert.Equal(t, "Vey legit", res[_llmChainDefaultOutputKey])
 }
 
+func TestSequentialChainPassesKnownValues(t *testing.T) {
+	t.Parallel()
+
+	summarize := NewLLMChain(
+		&testLanguageModel{expResult: "chickens rule"},
+		prompts.NewPromptTemplate("Summarize {{.text}}", []string{"text"}),
+	)
+	summarize.OutputKey = "summary"
+	translateLLM := &testLanguageModel{expResult: "les poulets règnent"}
+	translate := NewLLMChain(
+		translateLLM,
+		prompts.NewPromptTemplate("Translate {{.summary}} to {{.language}}", []string{"summary", "language"}),
+	)
+	translate.OutputKey = "translation"
+
+	seqChain, err := NewSequentialChain(
+		[]Chain{summarize, translate},
+		[]string{"text", "language"},
+		[]string{"summary", "translation"},
+	)
+	require.NoError(t, err)
+
+	res, err := Call(context.Background(), seqChain, map[string]any{"text": "a story", "language": "French"})
+	require.NoError(t, err)
+	assert.Equal(t, "Translate chickens rule to French", translateLLM.recordedPrompt[0].String())
+	assert.Equal(t, map[string]any{"summary": "chickens rule", "translation": "les poulets règnent"}, res)
+}
+
 func TestSequentialChainErrors(t *testing.T) {
 	t.Parallel()
 
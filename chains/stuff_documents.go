@@ -36,7 +36,7 @@ type StuffDocuments struct {
 	Separator string
 }
 
-var _ Chain = StuffDocuments{}
+var _ SchemaChain = StuffDocuments{}
 
 // NewStuffDocuments creates a new stuff documents chain with a llm chain used
 // after formatting the documents.
@@ -85,3 +85,13 @@ func (c StuffDocuments) GetInputKeys() []string {
 func (c StuffDocuments) GetOutputKeys() []string {
 	return append([]string{}, c.LLMChain.GetOutputKeys()...)
 }
+
+// GetInputSchema returns the schema of the input key, a list of documents.
+func (c StuffDocuments) GetInputSchema() []KeySchema {
+	return []KeySchema{{Name: c.InputKey, Type: ValueTypeDocuments, Description: "The documents to combine."}}
+}
+
+// GetOutputSchema returns the schema of the output keys of the llm chain.
+func (c StuffDocuments) GetOutputSchema() []KeySchema {
+	return OutputSchema(c.LLMChain)
+}
@@ -1,10 +1,32 @@
 package chains
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/prompts"
 )
 
+// ErrUnknownSummarizationType is returned by LoadSummarizationChain for an
+// unknown summarization type.
+var ErrUnknownSummarizationType = errors.New("unknown summarization type")
+
+// SummarizationType is the strategy a summarization chain uses to fit the
+// documents in the context of the llm.
+type SummarizationType string
+
+const (
+	// SummarizationStuff puts all the documents in a single prompt.
+	SummarizationStuff SummarizationType = "stuff"
+	// SummarizationMapReduce summarizes each document on its own, then
+	// combines the summaries into one.
+	SummarizationMapReduce SummarizationType = "map_reduce"
+	// SummarizationRefine summarizes the first document, then refines the
+	// summary with each of the next documents.
+	SummarizationRefine SummarizationType = "refine"
+)
+
 const _stuffSummarizationTemplate = `Write a concise summary of the following:
 
 
@@ -59,3 +81,77 @@ func LoadMapReduceSummarization(llm llms.LanguageModel) MapReduceDocuments {
 
 	return NewMapReduceDocuments(mapChain, combineChain)
 }
+
+type summarizationOptions struct {
+	prompt        prompts.PromptTemplate
+	combinePrompt prompts.PromptTemplate
+	concurrency   int
+}
+
+// SummarizationOption is a function that configures a chain loaded with
+// LoadSummarizationChain.
+type SummarizationOption func(*summarizationOptions)
+
+// WithSummarizationPrompt sets the prompt summarizing the documents given in
+// "context": all of them with the stuff type, each of them with the map reduce
+// type, and the first one with the refine type.
+func WithSummarizationPrompt(prompt prompts.PromptTemplate) SummarizationOption {
+	return func(o *summarizationOptions) {
+		o.prompt = prompt
+	}
+}
+
+// WithSummarizationCombinePrompt sets the prompt combining the summaries given
+// in "context" with the map reduce type, or refining the summary given in
+// "existing_answer" with the document given in "context" with the refine type.
+// It is not used with the stuff type.
+func WithSummarizationCombinePrompt(prompt prompts.PromptTemplate) SummarizationOption {
+	return func(o *summarizationOptions) {
+		o.combinePrompt = prompt
+	}
+}
+
+// WithSummarizationConcurrency sets the maximum number of documents summarized
+// at the same time with the map reduce type.
+func WithSummarizationConcurrency(n int) SummarizationOption {
+	return func(o *summarizationOptions) {
+		o.concurrency = n
+	}
+}
+
+// LoadSummarizationChain loads a chain summarizing the documents given in
+// "input_documents" with the summarization type. The default prompts are the
+// ones of LoadStuffSummarization, LoadMapReduceSummarization and
+// LoadRefineSummarization.
+func LoadSummarizationChain(llm llms.LanguageModel, typ SummarizationType, opts ...SummarizationOption) (Chain, error) { //nolint:lll
+	o := summarizationOptions{
+		prompt:      prompts.NewPromptTemplate(_stuffSummarizationTemplate, []string{"context"}),
+		concurrency: _defaultApplyMaxNumberWorkers,
+	}
+	switch typ {
+	case SummarizationStuff, SummarizationMapReduce:
+		o.combinePrompt = prompts.NewPromptTemplate(_stuffSummarizationTemplate, []string{"context"})
+	case SummarizationRefine:
+		o.combinePrompt = prompts.NewPromptTemplate(
+			_refineSummarizationTemplate, []string{"existing_answer", "context"},
+		)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSummarizationType, typ)
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch typ {
+	case SummarizationStuff:
+		return NewStuffDocuments(NewLLMChain(llm, o.prompt)), nil
+	case SummarizationMapReduce:
+		chain := NewMapReduceDocuments(NewLLMChain(llm, o.prompt), NewStuffDocuments(NewLLMChain(llm, o.combinePrompt)))
+		if o.concurrency > 0 {
+			chain.MaxNumberOfConcurrent = o.concurrency
+		}
+		return chain, nil
+	default:
+		return NewRefineDocuments(NewLLMChain(llm, o.prompt), NewLLMChain(llm, o.combinePrompt)), nil
+	}
+}
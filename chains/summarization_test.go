@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/documentloaders"
 	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/textsplitter"
 )
@@ -87,3 +88,43 @@ func TestMapReduceSummarization(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+func TestLoadSummarizationChain(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{{PageContent: "foo"}, {PageContent: "bar"}}
+	summarize := prompts.NewPromptTemplate("sum({{.context}})", []string{"context"})
+	combine := prompts.NewPromptTemplate("combine({{.context}})", []string{"context"})
+	refine := prompts.NewPromptTemplate("refine({{.existing_answer}}, {{.context}})", []string{"existing_answer", "context"})
+
+	testCases := []struct {
+		typ      SummarizationType
+		expected string
+	}{
+		{SummarizationStuff, "sum(foo\n\nbar\n\n)"},
+		{SummarizationMapReduce, "combine(sum(foo)\n\nsum(bar)\n\n)"},
+		{SummarizationRefine, "refine(sum(foo), bar)"},
+	}
+	for _, tc := range testCases {
+		combinePrompt := combine
+		if tc.typ == SummarizationRefine {
+			combinePrompt = refine
+		}
+		chain, err := LoadSummarizationChain(&testLanguageModel{}, tc.typ,
+			WithSummarizationPrompt(summarize),
+			WithSummarizationCombinePrompt(combinePrompt),
+			WithSummarizationConcurrency(1),
+		)
+		require.NoError(t, err)
+		result, err := Call(context.Background(), chain, map[string]any{"input_documents": docs})
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, result["text"], tc.typ)
+	}
+
+	chain, err := LoadSummarizationChain(&testLanguageModel{}, SummarizationMapReduce, WithSummarizationConcurrency(3))
+	require.NoError(t, err)
+	require.Equal(t, 3, chain.(MapReduceDocuments).MaxNumberOfConcurrent) //nolint:forcetypeassert
+
+	_, err = LoadSummarizationChain(&testLanguageModel{}, "squash")
+	require.ErrorIs(t, err, ErrUnknownSummarizationType)
+}
@@ -0,0 +1,143 @@
+package chains
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _warmupPlaceholder is the value of the inputs when formatting a prompt to
+// find its static prefix. The NUL byte survives most template functions.
+const _warmupPlaceholder = "\x00"
+
+// Warmer is implemented by chains able to prepare for their first call, see
+// Warmup.
+type Warmer interface {
+	// Warmup prepares the chain for its first call.
+	Warmup(ctx context.Context, options ...WarmupOption) error
+}
+
+var (
+	_ Warmer = LLMChain{}
+	_ Warmer = &SequentialChain{}
+	_ Warmer = &SimpleSequentialChain{}
+)
+
+// WarmupOption is a function that can be used to modify the behavior of the
+// Warmup function.
+type WarmupOption func(*warmupOptions)
+
+type warmupOptions struct {
+	// primePromptCache makes llm chains send the static prefix of their
+	// prompt to the llm.
+	primePromptCache bool
+}
+
+// WithPromptCachePriming is an option for Warmup making llm chains send the
+// static prefix of their prompt to the llm, generating a single token, so
+// that providers caching prompt prefixes have it cached for the first call.
+// The request is billed.
+func WithPromptCachePriming(prime bool) WarmupOption {
+	return func(o *warmupOptions) {
+		o.primePromptCache = prime
+	}
+}
+
+// Warmup prepares the chain for its first call if it implements Warmer, and
+// does nothing otherwise. It is meant to be called once when a program starts,
+// such as in the initialization of a serverless function, to cut the latency
+// of the first request.
+func Warmup(ctx context.Context, c Chain, options ...WarmupOption) error {
+	if w, ok := c.(Warmer); ok {
+		return w.Warmup(ctx, options...)
+	}
+	return nil
+}
+
+// Warmup prepares the chain for its first call. The prompt is formatted with
+// placeholder inputs, which parses and caches its template, and the tokenizer
+// of the llm is loaded by counting the tokens of the static prefix of the
+// prompt, the part before the first input. The llm is then warmed up if it
+// implements llms.Warmer, and sent the static prefix with
+// WithPromptCachePriming.
+func (c LLMChain) Warmup(ctx context.Context, options ...WarmupOption) error {
+	opts := warmupOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	prefix, ok := staticPromptPrefix(c.Prompt)
+	if ok {
+		c.LLM.GetNumTokens(prefix.String())
+	}
+	if w, isWarmer := c.LLM.(llms.Warmer); isWarmer {
+		if err := w.Warmup(ctx); err != nil {
+			return err
+		}
+	}
+	if !opts.primePromptCache || !ok || prefix.String() == "" {
+		return nil
+	}
+	_, err := c.LLM.GeneratePrompt(ctx, []schema.PromptValue{prefix}, llms.WithMaxTokens(1))
+	return err
+}
+
+// Warmup prepares the chains for their first call.
+func (c *SequentialChain) Warmup(ctx context.Context, options ...WarmupOption) error {
+	return warmupChains(ctx, c.chains, options)
+}
+
+// Warmup prepares the chains for their first call.
+func (c *SimpleSequentialChain) Warmup(ctx context.Context, options ...WarmupOption) error {
+	return warmupChains(ctx, c.chains, options)
+}
+
+func warmupChains(ctx context.Context, chains []Chain, options []WarmupOption) error {
+	for _, chain := range chains {
+		if err := Warmup(ctx, chain, options...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// staticPromptPrefix returns the part of the prompt that does not depend on
+// the inputs: the messages before the first one with an input for chat
+// prompts, and the text before the first input otherwise. False is returned
+// if the prompt can not be formatted with text inputs or if where the inputs
+// go can not be told.
+func staticPromptPrefix(prompt prompts.FormatPrompter) (schema.PromptValue, bool) {
+	inputVariables := prompt.GetInputVariables()
+	values := make(map[string]any, len(inputVariables))
+	for _, v := range inputVariables {
+		values[v] = _warmupPlaceholder
+	}
+	value, err := prompt.FormatPrompt(values)
+	if err != nil {
+		return nil, false
+	}
+
+	if messages, isChat := value.(prompts.ChatPromptValue); isChat {
+		prefix := prompts.ChatPromptValue{}
+		for _, m := range messages {
+			if strings.Contains(m.GetContent(), _warmupPlaceholder) {
+				break
+			}
+			prefix = append(prefix, m)
+		}
+		return prefix, true
+	}
+
+	text := value.String()
+	i := strings.Index(text, _warmupPlaceholder)
+	if i < 0 && len(inputVariables) > 0 {
+		return nil, false
+	}
+	if i >= 0 {
+		text = text[:i]
+	}
+	return prompts.StringPromptValue(text), true
+}
@@ -0,0 +1,87 @@
+package chains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+type warmupLanguageModel struct {
+	warmedUp  int
+	counted   []string
+	prompts   []schema.PromptValue
+	maxTokens []int
+}
+
+func (l *warmupLanguageModel) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	l.prompts = append(l.prompts, promptValues...)
+	l.maxTokens = append(l.maxTokens, opts.MaxTokens)
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: "ok"}}}}, nil
+}
+
+func (l *warmupLanguageModel) GetNumTokens(text string) int {
+	l.counted = append(l.counted, text)
+	return len(text)
+}
+
+func (l *warmupLanguageModel) Warmup(context.Context) error {
+	l.warmedUp++
+	return nil
+}
+
+func TestLLMChainWarmup(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &warmupLanguageModel{}
+	chain := NewLLMChain(llm, prompts.NewPromptTemplate("You are a {{.role | upper}}. Answer: {{.question}}", []string{"role", "question"})) //nolint:lll
+	require.NoError(t, Warmup(ctx, chain))
+	require.Equal(t, 1, llm.warmedUp)
+	require.Equal(t, []string{"You are a "}, llm.counted)
+	require.Empty(t, llm.prompts)
+
+	require.NoError(t, Warmup(ctx, chain, WithPromptCachePriming(true)))
+	require.Equal(t, []schema.PromptValue{prompts.StringPromptValue("You are a ")}, llm.prompts)
+	require.Equal(t, []int{1}, llm.maxTokens)
+}
+
+func TestLLMChainWarmupChatPrompt(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &warmupLanguageModel{}
+	chain := NewLLMChain(llm, prompts.NewChatPromptTemplate([]prompts.MessageFormatter{
+		prompts.NewSystemMessagePromptTemplate("You answer questions about otters.", nil),
+		prompts.NewHumanMessagePromptTemplate("{{.question}}", []string{"question"}),
+	}))
+	seq, err := NewSimpleSequentialChain([]Chain{chain})
+	require.NoError(t, err)
+
+	require.NoError(t, Warmup(ctx, seq, WithPromptCachePriming(true)))
+	require.Equal(t, 1, llm.warmedUp)
+	require.Equal(t, []schema.PromptValue{prompts.ChatPromptValue{
+		schema.SystemChatMessage{Content: "You answer questions about otters."},
+	}}, llm.prompts)
+}
+
+func TestLLMChainWarmupWithoutStaticPrefix(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	llm := &warmupLanguageModel{}
+	chain := NewLLMChain(llm, prompts.NewPromptTemplate("{{.question}}", []string{"question"}))
+	require.NoError(t, Warmup(ctx, chain, WithPromptCachePriming(true)))
+	require.Equal(t, 1, llm.warmedUp)
+	require.Empty(t, llm.prompts)
+
+	// Chains that do not implement Warmer are left alone.
+	require.NoError(t, Warmup(ctx, &testLLMChain{}))
+}
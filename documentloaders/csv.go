@@ -29,7 +29,9 @@ func NewCSV(r io.Reader, columns ...string) CSV {
 	}
 }
 
-// Load reads from the io.Reader and returns a single document with the data.
+// Load reads from the io.Reader and returns a document per row. The metadata
+// of the documents hold the row number in "row" and the values of the columns
+// by name in "columns".
 func (c CSV) Load(_ context.Context) ([]schema.Document, error) {
 	var header []string
 	var docs []schema.Document
@@ -50,6 +52,7 @@ func (c CSV) Load(_ context.Context) ([]schema.Document, error) {
 		}
 
 		var content []string
+		columns := make(map[string]string, len(row))
 		for i, value := range row {
 			if c.columns != nil &&
 				len(c.columns) > 0 &&
@@ -59,12 +62,13 @@ func (c CSV) Load(_ context.Context) ([]schema.Document, error) {
 
 			line := fmt.Sprintf("%s: %s", header[i], value)
 			content = append(content, line)
+			columns[header[i]] = value
 		}
 
 		rown++
 		docs = append(docs, schema.Document{
 			PageContent: strings.Join(content, "\n"),
-			Metadata:    map[string]any{"row": rown},
+			Metadata:    map[string]any{"row": rown, "columns": columns},
 		})
 	}
 
@@ -43,6 +43,7 @@ func TestCSVLoaderWithFilteringColumns(t *testing.T) {
 
 	expected1 := "city: New York"
 	assert.Equal(t, docs[0].PageContent, expected1)
+	assert.Equal(t, map[string]any{"row": 1, "columns": map[string]string{"city": "New York"}}, docs[0].Metadata)
 
 	expected2 := "city: London"
 	assert.Equal(t, docs[1].PageContent, expected2)
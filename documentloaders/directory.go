@@ -0,0 +1,166 @@
+package documentloaders
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// FileLoaderFunc returns the loader of a file of the given size.
+type FileLoaderFunc func(f *os.File, size int64) Loader
+
+// Directory loads the files of a directory tree, choosing the loader of each
+// file from its extension.
+type Directory struct {
+	root      string
+	globs     []string
+	recursive bool
+	loaders   map[string]FileLoaderFunc
+}
+
+var _ Loader = Directory{}
+
+// DirectoryOption is a function type that can be used to modify a Directory.
+type DirectoryOption func(d *Directory)
+
+// WithGlob is an option for only loading the files matching one of the
+// patterns, see filepath.Match. Patterns with a path separator are matched
+// against the path of the files relative to the root, others against their
+// name.
+func WithGlob(patterns ...string) DirectoryOption {
+	return func(d *Directory) {
+		d.globs = append(d.globs, patterns...)
+	}
+}
+
+// WithRecursive is an option for loading the files of the subdirectories. It
+// is enabled by default.
+func WithRecursive(recursive bool) DirectoryOption {
+	return func(d *Directory) {
+		d.recursive = recursive
+	}
+}
+
+// WithFileLoader is an option for setting the loader of the files with the
+// extension, such as ".md".
+func WithFileLoader(ext string, loader FileLoaderFunc) DirectoryOption {
+	return func(d *Directory) {
+		d.loaders[strings.ToLower(ext)] = loader
+	}
+}
+
+// NewDirectory creates a new directory loader for the tree at root. Text,
+// markdown, HTML, CSV and PDF files are loaded, other files are skipped unless
+// a loader is set for their extension with WithFileLoader.
+func NewDirectory(root string, opts ...DirectoryOption) Directory {
+	d := Directory{
+		root:      root,
+		recursive: true,
+		loaders: map[string]FileLoaderFunc{
+			".txt":  func(f *os.File, _ int64) Loader { return NewText(f) },
+			".md":   func(f *os.File, _ int64) Loader { return NewText(f) },
+			".html": func(f *os.File, _ int64) Loader { return NewHTML(f) },
+			".htm":  func(f *os.File, _ int64) Loader { return NewHTML(f) },
+			".csv":  func(f *os.File, _ int64) Loader { return NewCSV(f) },
+			".pdf":  func(f *os.File, size int64) Loader { return NewPDF(f, size) },
+		},
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	return d
+}
+
+// Load walks the directory tree in lexical order and returns the documents of
+// the files. The path of the file relative to the root is added to the metadata
+// of the documents as "source".
+func (d Directory) Load(ctx context.Context) ([]schema.Document, error) {
+	var docs []schema.Document
+	err := filepath.WalkDir(d.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != d.root && !d.recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(d.root, path)
+		if err != nil {
+			return err
+		}
+		newLoader, ok := d.loaders[strings.ToLower(filepath.Ext(path))]
+		if !ok || !d.matches(rel) {
+			return nil
+		}
+
+		fileDocs, err := d.loadFile(ctx, path, newLoader)
+		if err != nil {
+			return err
+		}
+		for _, doc := range fileDocs {
+			if doc.Metadata == nil {
+				doc.Metadata = map[string]any{}
+			}
+			doc.Metadata["source"] = filepath.ToSlash(rel)
+			docs = append(docs, doc)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// LoadAndSplit loads the files of the directory tree and splits them into
+// multiple documents using a text splitter.
+func (d Directory) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := d.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (d Directory) loadFile(ctx context.Context, path string, newLoader FileLoaderFunc) ([]schema.Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return newLoader(f, info.Size()).Load(ctx)
+}
+
+// matches reports whether the file at the relative path matches the globs.
+func (d Directory) matches(rel string) bool {
+	if len(d.globs) == 0 {
+		return true
+	}
+	for _, pattern := range d.globs {
+		name := filepath.Base(rel)
+		if strings.ContainsRune(pattern, '/') || strings.ContainsRune(pattern, filepath.Separator) {
+			name = filepath.ToSlash(rel)
+			pattern = filepath.ToSlash(pattern)
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryLoader(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"a.txt":         "alpha",
+		"b.log":         "skipped",
+		"docs/c.md":     "# gamma",
+		"docs/d.html":   "<html><head><title>Delta</title></head><body>delta</body></html>",
+		"docs/sub/e.md": "epsilon",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	sources := func(opts ...DirectoryOption) []string {
+		docs, err := NewDirectory(root, opts...).Load(context.Background())
+		require.NoError(t, err)
+		var sources []string
+		for _, doc := range docs {
+			sources = append(sources, doc.Metadata["source"].(string)) //nolint:forcetypeassert
+		}
+		return sources
+	}
+
+	assert.Equal(t, []string{"a.txt", "docs/c.md", "docs/d.html", "docs/sub/e.md"}, sources())
+	assert.Equal(t, []string{"a.txt"}, sources(WithRecursive(false)))
+	assert.Equal(t, []string{"docs/c.md", "docs/sub/e.md"}, sources(WithGlob("*.md")))
+	assert.Equal(t, []string{"docs/c.md"}, sources(WithGlob("docs/*.md")))
+	assert.Contains(t, sources(WithFileLoader(".log", func(f *os.File, _ int64) Loader {
+		return NewText(f)
+	})), "b.log")
+
+	docs, err := NewDirectory(root, WithGlob("*.html")).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "delta", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"title": "Delta", "source": "docs/d.html"}, docs[0].Metadata)
+}
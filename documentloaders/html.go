@@ -23,7 +23,8 @@ func NewHTML(r io.Reader) HTML {
 	return HTML{r}
 }
 
-// Load reads from the io.Reader and returns a single document with the data.
+// Load reads from the io.Reader and returns a single document with the text of
+// the body. The title of the page, if any, is kept in the "title" metadata.
 func (h HTML) Load(_ context.Context) ([]schema.Document, error) {
 	doc, err := goquery.NewDocumentFromReader(h.r)
 	if err != nil {
@@ -40,10 +41,15 @@ func (h HTML) Load(_ context.Context) ([]schema.Document, error) {
 	sanitized := bluemonday.UGCPolicy().Sanitize(sel.Text())
 	pagecontent := strings.TrimSpace(sanitized)
 
+	metadata := map[string]any{}
+	if title := strings.TrimSpace(doc.Find("head title").First().Text()); title != "" {
+		metadata["title"] = title
+	}
+
 	return []schema.Document{
 		{
 			PageContent: pagecontent,
-			Metadata:    map[string]any{},
+			Metadata:    metadata,
 		},
 	}, nil
 }
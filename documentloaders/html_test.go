@@ -45,6 +45,6 @@ func TestHTMLLoader(t *testing.T) {
 	assert.NotContains(t, content, notexpected[4])
 	assert.NotContains(t, content, notexpected[5])
 
-	expectedMetadata := map[string]any{}
+	expectedMetadata := map[string]any{"title": "langchaingo html example"}
 	assert.Equal(t, docs[0].Metadata, expectedMetadata)
 }
@@ -0,0 +1,62 @@
+package documentloaders
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/tools/webfetch"
+)
+
+// URL loads a web page. The page is fetched with a webfetch.Fetcher, which
+// protects against server side request forgery when the url comes from an
+// untrusted source.
+type URL struct {
+	url     string
+	fetcher *webfetch.Fetcher
+}
+
+var _ Loader = URL{}
+
+// NewURL creates a new url loader. The options are the ones of the fetcher,
+// see webfetch.NewFetcher.
+func NewURL(url string, options ...webfetch.Option) URL {
+	return URL{url: url, fetcher: webfetch.NewFetcher(options...)}
+}
+
+// Load fetches the page and returns a single document, with the text of the
+// body for html pages. The url of the page is kept in the "source" metadata.
+func (u URL) Load(ctx context.Context) ([]schema.Document, error) {
+	res, err := u.fetcher.Fetch(ctx, u.url)
+	if err != nil {
+		return nil, err
+	}
+
+	var loader Loader = NewText(bytes.NewReader(res.Body))
+	if strings.Contains(res.ContentType, "html") {
+		loader = NewHTML(bytes.NewReader(res.Body))
+	}
+	docs, err := loader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range docs {
+		if docs[i].Metadata == nil {
+			docs[i].Metadata = map[string]any{}
+		}
+		docs[i].Metadata["source"] = res.URL
+	}
+	return docs, nil
+}
+
+// LoadAndSplit fetches the page and splits it into multiple documents using a
+// text splitter.
+func (u URL) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := u.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
@@ -0,0 +1,30 @@
+package documentloaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/tools/webfetch"
+)
+
+func TestURLLoader(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<html><head><title>Otters</title></head><body><p>Otters hold hands.</p></body></html>`))
+	}))
+	defer s.Close()
+
+	docs, err := NewURL(s.URL, webfetch.WithAllowPrivateNetworks(true)).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "Otters hold hands.", docs[0].PageContent)
+	require.Equal(t, map[string]any{"title": "Otters", "source": s.URL}, docs[0].Metadata)
+
+	_, err = NewURL(s.URL).Load(context.Background())
+	require.ErrorIs(t, err, webfetch.ErrBlockedAddress)
+}
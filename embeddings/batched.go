@@ -0,0 +1,179 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrUnexpectedEmbeddingCount is returned when a client does not return one
+// vector for each of the texts of a batch.
+var ErrUnexpectedEmbeddingCount = errors.New("unexpected number of embeddings")
+
+// EmbedderClient is the interface of the LLM clients able to create embeddings,
+// like openai.LLM, vertexai.LLM or bedrock.LLM.
+type EmbedderClient interface {
+	CreateEmbedding(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// BatchedEmbedder is an Embedder wrapping the CreateEmbedding method of a
+// client. Texts are sent in batches, with several batches in flight at once,
+// failed batches are retried and vectors can be stored in a Cache so the same
+// text is only embedded once.
+type BatchedEmbedder struct {
+	client EmbedderClient
+
+	model          string
+	stripNewLines  bool
+	batchSize      int
+	maxConcurrency int
+	maxRetries     int
+	retryDelay     time.Duration
+	cache          Cache
+	rateLimiter    *llms.RateLimiter
+}
+
+var _ Embedder = (*BatchedEmbedder)(nil)
+
+// NewBatchedEmbedder creates a new BatchedEmbedder for the client.
+func NewBatchedEmbedder(client EmbedderClient, opts ...BatchedEmbedderOption) *BatchedEmbedder {
+	e := &BatchedEmbedder{
+		client:         client,
+		stripNewLines:  _defaultStripNewLines,
+		batchSize:      _defaultBatchSize,
+		maxConcurrency: _defaultMaxConcurrency,
+		maxRetries:     _defaultMaxRetries,
+		retryDelay:     _defaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts. Cached
+// vectors are reused and only the other texts are sent to the client.
+func (e *BatchedEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	texts = MaybeRemoveNewLines(append([]string(nil), texts...), e.stripNewLines)
+
+	emb := make([][]float64, len(texts))
+	missing := make([]int, 0, len(texts))
+	for i, text := range texts {
+		if e.cache != nil {
+			if v, ok := e.cache.Get(ctx, CacheKey(e.model, text)); ok {
+				emb[i] = v
+				continue
+			}
+		}
+		missing = append(missing, i)
+	}
+
+	batches := batchIndexes(missing, e.batchSize)
+	errs := make([]error, len(batches))
+	sem := make(chan struct{}, e.maxConcurrency)
+	var wg sync.WaitGroup
+	for b, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(b int, batch []int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[b] = e.embedBatch(ctx, texts, batch, emb)
+		}(b, batch)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return emb, nil
+}
+
+// EmbedQuery embeds a single text.
+func (e *BatchedEmbedder) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	emb, err := e.EmbedDocuments(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return emb[0], nil
+}
+
+// embedBatch embeds the texts at the indexes of the batch, storing the vectors
+// in emb and in the cache.
+func (e *BatchedEmbedder) embedBatch(ctx context.Context, texts []string, batch []int, emb [][]float64) error {
+	batchTexts := make([]string, len(batch))
+	for i, idx := range batch {
+		batchTexts[i] = texts[idx]
+	}
+
+	vectors, err := e.createEmbedding(ctx, batchTexts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(batch) {
+		return ErrUnexpectedEmbeddingCount
+	}
+
+	for i, idx := range batch {
+		emb[idx] = vectors[i]
+		if e.cache != nil {
+			if err := e.cache.Set(ctx, CacheKey(e.model, batchTexts[i]), vectors[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// createEmbedding calls the client, retrying failed calls with an exponential
+// backoff, and waiting for the rate limiter before each call.
+func (e *BatchedEmbedder) createEmbedding(ctx context.Context, texts []string) ([][]float64, error) {
+	tokens := 0
+	for _, text := range texts {
+		tokens += llms.EstimateTokens(text)
+	}
+	delay := e.retryDelay
+	for attempt := 0; ; attempt++ {
+		if e.rateLimiter != nil {
+			if err := e.rateLimiter.Wait(ctx, tokens); err != nil {
+				return nil, err
+			}
+		}
+		vectors, err := e.client.CreateEmbedding(ctx, texts)
+		if err == nil || attempt >= e.maxRetries || ctx.Err() != nil {
+			return vectors, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// batchIndexes splits the indexes in batches of at most size indexes. All the
+// indexes are in one batch if size is not positive.
+func batchIndexes(indexes []int, size int) [][]int {
+	if size <= 0 {
+		size = len(indexes)
+	}
+	var batches [][]int
+	for len(indexes) > 0 {
+		n := size
+		if n > len(indexes) {
+			n = len(indexes)
+		}
+		batches = append(batches, indexes[:n])
+		indexes = indexes[n:]
+	}
+	return batches
+}
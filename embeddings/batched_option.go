@@ -0,0 +1,79 @@
+package embeddings
+
+import (
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+const (
+	_defaultStripNewLines  = true
+	_defaultBatchSize      = 512
+	_defaultMaxConcurrency = 1
+	_defaultMaxRetries     = 3
+	_defaultRetryDelay     = time.Second
+)
+
+// BatchedEmbedderOption is a function type that can be used to modify a
+// BatchedEmbedder.
+type BatchedEmbedderOption func(e *BatchedEmbedder)
+
+// WithModel is an option for naming the model used by the client. The name is
+// part of the cache keys, so vectors of different models are never mixed.
+func WithModel(model string) BatchedEmbedderOption {
+	return func(e *BatchedEmbedder) {
+		e.model = model
+	}
+}
+
+// WithStripNewLines is an option for specifying if new lines are replaced by
+// spaces before embedding.
+func WithStripNewLines(stripNewLines bool) BatchedEmbedderOption {
+	return func(e *BatchedEmbedder) {
+		e.stripNewLines = stripNewLines
+	}
+}
+
+// WithBatchSize is an option for specifying the number of texts sent to the
+// client at once.
+func WithBatchSize(batchSize int) BatchedEmbedderOption {
+	return func(e *BatchedEmbedder) {
+		e.batchSize = batchSize
+	}
+}
+
+// WithMaxConcurrency is an option for specifying the number of batches sent
+// at once. Values lower than one are ignored.
+func WithMaxConcurrency(maxConcurrency int) BatchedEmbedderOption {
+	return func(e *BatchedEmbedder) {
+		if maxConcurrency > 0 {
+			e.maxConcurrency = maxConcurrency
+		}
+	}
+}
+
+// WithRetries is an option for specifying how many times a failed batch is
+// retried, and the delay before the first retry. The delay doubles after each
+// retry.
+func WithRetries(maxRetries int, delay time.Duration) BatchedEmbedderOption {
+	return func(e *BatchedEmbedder) {
+		e.maxRetries = maxRetries
+		e.retryDelay = delay
+	}
+}
+
+// WithCache is an option for storing the vectors in a cache.
+func WithCache(cache Cache) BatchedEmbedderOption {
+	return func(e *BatchedEmbedder) {
+		e.cache = cache
+	}
+}
+
+// WithRateLimiter is an option for waiting for the rate limiter before sending
+// each batch, so that batch jobs stay under the quotas of the provider. The
+// tokens of a batch are estimated from the length of its texts.
+func WithRateLimiter(limiter *llms.RateLimiter) BatchedEmbedderOption {
+	return func(e *BatchedEmbedder) {
+		e.rateLimiter = limiter
+	}
+}
@@ -0,0 +1,136 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+var errTemporary = errors.New("temporary error")
+
+// fakeClient embeds each text as a vector holding its length, failing the
+// first calls when failures is set.
+type fakeClient struct {
+	mu       sync.Mutex
+	calls    [][]string
+	failures int
+}
+
+func (c *fakeClient) CreateEmbedding(_ context.Context, texts []string) ([][]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, texts)
+	if c.failures > 0 {
+		c.failures--
+		return nil, errTemporary
+	}
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{float64(len(text))}
+	}
+	return vectors, nil
+}
+
+func TestBatchedEmbedderBatches(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{}
+	e := NewBatchedEmbedder(client, WithBatchSize(2), WithMaxConcurrency(2))
+
+	emb, err := e.EmbedDocuments(context.Background(), []string{"a", "bb", "ccc", "dd\nd", "e"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1}, {2}, {3}, {4}, {1}}, emb)
+	assert.Len(t, client.calls, 3)
+	for _, call := range client.calls {
+		assert.LessOrEqual(t, len(call), 2)
+		for _, text := range call {
+			assert.NotContains(t, text, "\n")
+		}
+	}
+}
+
+func TestBatchedEmbedderRetries(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{failures: 2}
+	e := NewBatchedEmbedder(client, WithRetries(2, time.Millisecond))
+	emb, err := e.EmbedQuery(context.Background(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{3}, emb)
+	assert.Len(t, client.calls, 3)
+
+	client = &fakeClient{failures: 2}
+	e = NewBatchedEmbedder(client, WithRetries(1, time.Millisecond))
+	_, err = e.EmbedQuery(context.Background(), "foo")
+	require.ErrorIs(t, err, errTemporary)
+	assert.Len(t, client.calls, 2)
+}
+
+func TestBatchedEmbedderRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeClient{}
+	e := NewBatchedEmbedder(client, WithBatchSize(1), WithRateLimiter(llms.NewRateLimiter(2, 0)))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := e.EmbedDocuments(ctx, []string{"a", "b", "c"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, client.calls, 2)
+}
+
+func TestBatchedEmbedderCache(t *testing.T) {
+	t.Parallel()
+
+	fileCache, err := NewFileCache(t.TempDir())
+	require.NoError(t, err)
+
+	for name, cache := range map[string]Cache{"memory": NewInMemoryCache(), "file": fileCache} {
+		name, cache := name, cache
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &fakeClient{}
+			e := NewBatchedEmbedder(client, WithModel(name), WithCache(cache))
+			_, err := e.EmbedDocuments(context.Background(), []string{"foo", "bar"})
+			require.NoError(t, err)
+
+			emb, err := e.EmbedDocuments(context.Background(), []string{"bar", "bazz", "foo"})
+			require.NoError(t, err)
+			assert.Equal(t, [][]float64{{3}, {4}, {3}}, emb)
+			assert.Equal(t, [][]string{{"foo", "bar"}, {"bazz"}}, client.calls)
+
+			// Vectors of another model are not reused.
+			other := NewBatchedEmbedder(client, WithModel(strings.ToUpper(name)), WithCache(cache))
+			_, err = other.EmbedQuery(context.Background(), "foo")
+			require.NoError(t, err)
+			assert.Len(t, client.calls, 3)
+		})
+	}
+}
+
+func TestFileCachePersists(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	c, err := NewFileCache(dir)
+	require.NoError(t, err)
+	key := CacheKey("model", "text")
+	require.NoError(t, c.Set(context.Background(), key, []float64{0.5, 1}))
+
+	c, err = NewFileCache(dir)
+	require.NoError(t, err)
+	v, ok := c.Get(context.Background(), key)
+	require.True(t, ok)
+	assert.Equal(t, []float64{0.5, 1}, v)
+
+	_, ok = c.Get(context.Background(), CacheKey("other", "text"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,70 @@
+package bedrock
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/bedrock"
+)
+
+// Bedrock is the embedder using the Titan or Cohere embedding models of Amazon
+// Bedrock.
+type Bedrock struct {
+	client *bedrock.LLM
+
+	StripNewLines bool
+	// DocumentInputType and QueryInputType are the input types of the texts of
+	// documents and queries. Only Cohere models use them.
+	DocumentInputType string
+	QueryInputType    string
+	// Dimensions is the number of dimensions of the embeddings, the default of
+	// the model if zero. Only Titan models supporting it use it.
+	Dimensions int
+}
+
+var _ embeddings.Embedder = &Bedrock{}
+
+// NewBedrock creates a new Bedrock embedder with options.
+func NewBedrock(opts ...Option) (*Bedrock, error) {
+	v, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// EmbedDocuments creates one vector embedding for each of the texts.
+func (e *Bedrock) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	return e.client.CreateEmbeddingWithOptions(
+		ctx,
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.embeddingOptions(e.DocumentInputType)...,
+	)
+}
+
+// EmbedQuery embeds a single text.
+func (e *Bedrock) EmbedQuery(ctx context.Context, text string) ([]float64, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.client.CreateEmbeddingWithOptions(ctx, []string{text}, e.embeddingOptions(e.QueryInputType)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
+
+func (e *Bedrock) embeddingOptions(inputType string) []bedrock.EmbeddingOption {
+	opts := []bedrock.EmbeddingOption{}
+	if inputType != "" {
+		opts = append(opts, bedrock.WithEmbeddingInputType(inputType))
+	}
+	if e.Dimensions > 0 {
+		opts = append(opts, bedrock.WithEmbeddingDimensions(e.Dimensions))
+	}
+	return opts
+}
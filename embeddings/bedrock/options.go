@@ -0,0 +1,69 @@
+package bedrock
+
+import (
+	"github.com/tmc/langchaingo/llms/bedrock"
+)
+
+const (
+	_defaultStripNewLines     = true
+	_defaultDocumentInputType = "search_document"
+	_defaultQueryInputType    = "search_query"
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Bedrock)
+
+// WithClient is an option for providing the LLM client. Its embedding model is
+// set with bedrock.WithEmbeddingModel.
+func WithClient(client *bedrock.LLM) Option {
+	return func(p *Bedrock) {
+		p.client = client
+	}
+}
+
+// WithStripNewLines is an option for specifying the should it strip new lines.
+func WithStripNewLines(stripNewLines bool) Option {
+	return func(p *Bedrock) {
+		p.StripNewLines = stripNewLines
+	}
+}
+
+// WithInputTypes is an option for specifying the input types of the texts of
+// documents and queries embedded by Cohere models. If not set,
+// "search_document" and "search_query" are used.
+func WithInputTypes(document, query string) Option {
+	return func(p *Bedrock) {
+		p.DocumentInputType = document
+		p.QueryInputType = query
+	}
+}
+
+// WithDimensions is an option for specifying the number of dimensions of the
+// embeddings of the Titan models supporting it.
+func WithDimensions(dimensions int) Option {
+	return func(p *Bedrock) {
+		p.Dimensions = dimensions
+	}
+}
+
+func applyOptions(opts ...Option) (*Bedrock, error) {
+	b := &Bedrock{
+		StripNewLines:     _defaultStripNewLines,
+		DocumentInputType: _defaultDocumentInputType,
+		QueryInputType:    _defaultQueryInputType,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.client == nil {
+		client, err := bedrock.New()
+		if err != nil {
+			return nil, err
+		}
+		b.client = client
+	}
+
+	return b, nil
+}
@@ -0,0 +1,117 @@
+package embeddings
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores vector embeddings by key, see CacheKey.
+type Cache interface {
+	// Get returns the vector stored for the key, if any.
+	Get(ctx context.Context, key string) ([]float64, bool)
+	// Set stores the vector for the key.
+	Set(ctx context.Context, key string, vector []float64) error
+}
+
+// CacheKey returns the cache key of the embedding of a text by a model: the
+// hex encoded SHA-256 hash of both.
+func CacheKey(model, text string) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// InMemoryCache is a Cache keeping the vectors in memory.
+type InMemoryCache struct {
+	mu      sync.RWMutex
+	vectors map[string][]float64
+}
+
+var _ Cache = (*InMemoryCache)(nil)
+
+// NewInMemoryCache creates a new empty InMemoryCache.
+func NewInMemoryCache() *InMemoryCache {
+	return &InMemoryCache{vectors: make(map[string][]float64)}
+}
+
+// Get returns the vector stored for the key, if any.
+func (c *InMemoryCache) Get(_ context.Context, key string) ([]float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.vectors[key]
+	return v, ok
+}
+
+// Set stores the vector for the key.
+func (c *InMemoryCache) Set(_ context.Context, key string, vector []float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.vectors[key] = vector
+	return nil
+}
+
+// FileCache is a Cache persisting each vector as a JSON file in a directory,
+// so vectors survive restarts of the program.
+type FileCache struct {
+	dir string
+}
+
+var _ Cache = FileCache{}
+
+// NewFileCache creates a new FileCache in the directory, creating it if needed.
+func NewFileCache(dir string) (FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gomnd
+		return FileCache{}, err
+	}
+	return FileCache{dir: dir}, nil
+}
+
+// Get returns the vector stored for the key, if any. Unreadable files are
+// treated as missing.
+func (c FileCache) Get(_ context.Context, key string) ([]float64, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var v []float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// Set stores the vector for the key. The file is written atomically so
+// concurrent readers never see a partial vector.
+func (c FileCache) Set(_ context.Context, key string, vector []float64) error {
+	data, err := json.Marshal(vector)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(f.Name()))
+	}
+	return nil
+}
+
+func (c FileCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
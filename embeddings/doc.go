@@ -6,7 +6,13 @@ The main components of this package are:
 
 - Embedder interface: a common interface for creating vector embeddings from texts.
 - OpenAI: an Embedder implementation using the OpenAI API.
-- VertexAIPaLM: an Embedder implementation using Google PaLM (VertexAI) API.
+- VertexAIPaLM: an Embedder implementation using Google PaLM (VertexAI) API,
+  with models such as text-embedding-004 and their task types and dimensions.
+- Bedrock: an Embedder implementation using the Titan and Cohere models of
+  Amazon Bedrock.
+- BatchedEmbedder: an Embedder wrapping the CreateEmbedding method of any LLM
+  client, with batching, concurrency, retries and an optional Cache.
+- Float32Embedder interface: implemented by embedders returning float32 vectors without converting them from float64, used by EmbedFloat32 and EmbedQueryFloat32.
 - Helper functions: utility functions for embedding, such as `batchTexts` and `maybeRemoveNewLines`.
 
 The package provides a flexible way to handle different APIs for generating
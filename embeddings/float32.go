@@ -0,0 +1,80 @@
+package embeddings
+
+import "context"
+
+// Float32Embedder is implemented by embedders which can return vectors of
+// float32 without converting them from float64, halving the memory they take.
+// Vector stores keeping float32 vectors embed texts with EmbedFloat32 and
+// EmbedQueryFloat32, which use it when the embedder implements it.
+type Float32Embedder interface {
+	// EmbedDocumentsFloat32 returns a vector for each text.
+	EmbedDocumentsFloat32(ctx context.Context, texts []string) ([][]float32, error)
+	// EmbedQueryFloat32 embeds a single text.
+	EmbedQueryFloat32(ctx context.Context, text string) ([]float32, error)
+}
+
+// EmbedFloat32 returns a float32 vector for each text, from the embedder
+// directly if it implements Float32Embedder, or converted from the vectors of
+// EmbedDocuments otherwise.
+func EmbedFloat32(ctx context.Context, e Embedder, texts []string) ([][]float32, error) {
+	if e32, ok := e.(Float32Embedder); ok {
+		return e32.EmbedDocumentsFloat32(ctx, texts)
+	}
+
+	vectors, err := e.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	vectors32 := make([][]float32, len(vectors))
+	for i, v := range vectors {
+		vectors32[i] = ToFloat32(v)
+	}
+	return vectors32, nil
+}
+
+// EmbedQueryFloat32 embeds a single text as a float32 vector, from the embedder
+// directly if it implements Float32Embedder, or converted from the vector of
+// EmbedQuery otherwise.
+func EmbedQueryFloat32(ctx context.Context, e Embedder, text string) ([]float32, error) {
+	if e32, ok := e.(Float32Embedder); ok {
+		return e32.EmbedQueryFloat32(ctx, text)
+	}
+
+	vector, err := e.EmbedQuery(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return ToFloat32(vector), nil
+}
+
+// ToFloat32 converts a vector of float64 to float32.
+func ToFloat32(v []float64) []float32 {
+	v32 := make([]float32, len(v))
+	for i, f := range v {
+		v32[i] = float32(f)
+	}
+	return v32
+}
+
+// ToFloat64 converts a vector of float32 to float64.
+func ToFloat64(v []float32) []float64 {
+	v64 := make([]float64, len(v))
+	for i, f := range v {
+		v64[i] = float64(f)
+	}
+	return v64
+}
+
+// CombineVectorsFloat32 is CombineVectors for float32 vectors. Only the vectors
+// of the chunks of one text are converted to float64 at a time.
+func CombineVectorsFloat32(vectors [][]float32, weights []int) ([]float32, error) {
+	vectors64 := make([][]float64, len(vectors))
+	for i, v := range vectors {
+		vectors64[i] = ToFloat64(v)
+	}
+	combined, err := CombineVectors(vectors64, weights)
+	if err != nil {
+		return nil, err
+	}
+	return ToFloat32(combined), nil
+}
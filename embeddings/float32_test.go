@@ -0,0 +1,64 @@
+package embeddings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type float64Embedder struct{}
+
+func (float64Embedder) EmbedDocuments(_ context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float64{float64(len(text)), 0.5}
+	}
+	return vectors, nil
+}
+
+func (float64Embedder) EmbedQuery(_ context.Context, text string) ([]float64, error) {
+	return []float64{float64(len(text)), 0.5}, nil
+}
+
+type float32Embedder struct{ float64Embedder }
+
+func (float32Embedder) EmbedDocumentsFloat32(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = []float32{32}
+	}
+	return vectors, nil
+}
+
+func (float32Embedder) EmbedQueryFloat32(context.Context, string) ([]float32, error) {
+	return []float32{32}, nil
+}
+
+func TestEmbedFloat32(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	vectors, err := EmbedFloat32(ctx, float64Embedder{}, []string{"a", "abc"})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 0.5}, {3, 0.5}}, vectors)
+	vector, err := EmbedQueryFloat32(ctx, float64Embedder{}, "ab")
+	require.NoError(t, err)
+	require.Equal(t, []float32{2, 0.5}, vector)
+
+	vectors, err = EmbedFloat32(ctx, float32Embedder{}, []string{"a", "abc"})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{32}, {32}}, vectors)
+	vector, err = EmbedQueryFloat32(ctx, float32Embedder{}, "ab")
+	require.NoError(t, err)
+	require.Equal(t, []float32{32}, vector)
+}
+
+func TestCombineVectorsFloat32(t *testing.T) {
+	t.Parallel()
+
+	combined, err := CombineVectorsFloat32([][]float32{{1, 0}, {0, 1}}, []int{1, 1})
+	require.NoError(t, err)
+	require.InDeltaSlice(t, []float32{0.70710677, 0.70710677}, combined, 1e-6)
+	require.Equal(t, []float64{0.5}, ToFloat64(ToFloat32([]float64{0.5})))
+}
@@ -18,7 +18,10 @@ type Huggingface struct {
 	BatchSize     int
 }
 
-var _ embeddings.Embedder = &Huggingface{}
+var (
+	_ embeddings.Embedder        = &Huggingface{}
+	_ embeddings.Float32Embedder = &Huggingface{}
+)
 
 func NewHuggingface(opts ...Option) (*Huggingface, error) {
 	v, err := applyOptions(opts...)
@@ -70,3 +73,48 @@ func (e *Huggingface) EmbedQuery(ctx context.Context, text string) ([]float64, e
 
 	return emb[0], nil
 }
+
+// EmbedDocumentsFloat32 is EmbedDocuments returning the float32 vectors of the
+// API without converting them to float64.
+func (e *Huggingface) EmbedDocumentsFloat32(ctx context.Context, texts []string) ([][]float32, error) {
+	batchedTexts := embeddings.BatchTexts(
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.BatchSize,
+	)
+
+	emb := make([][]float32, 0, len(texts))
+	for _, texts := range batchedTexts {
+		curTextEmbeddings, err := e.client.CreateEmbeddingFloat32(ctx, texts, e.Model, e.Task)
+		if err != nil {
+			return nil, err
+		}
+
+		textLengths := make([]int, 0, len(texts))
+		for _, text := range texts {
+			textLengths = append(textLengths, len(text))
+		}
+
+		combined, err := embeddings.CombineVectorsFloat32(curTextEmbeddings, textLengths)
+		if err != nil {
+			return nil, err
+		}
+
+		emb = append(emb, combined)
+	}
+
+	return emb, nil
+}
+
+// EmbedQueryFloat32 is EmbedQuery returning a float32 vector.
+func (e *Huggingface) EmbedQueryFloat32(ctx context.Context, text string) ([]float32, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.client.CreateEmbeddingFloat32(ctx, []string{text}, e.Model, e.Task)
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
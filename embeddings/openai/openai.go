@@ -16,7 +16,10 @@ type OpenAI struct {
 	BatchSize     int
 }
 
-var _ embeddings.Embedder = OpenAI{}
+var (
+	_ embeddings.Embedder        = OpenAI{}
+	_ embeddings.Float32Embedder = OpenAI{}
+)
 
 // NewOpenAI creates a new OpenAI with options. Options for client, strip new lines and batch.
 func NewOpenAI(opts ...Option) (OpenAI, error) {
@@ -71,3 +74,48 @@ func (e OpenAI) EmbedQuery(ctx context.Context, text string) ([]float64, error)
 
 	return emb[0], nil
 }
+
+// EmbedDocumentsFloat32 is EmbedDocuments returning float32 vectors decoded
+// from the response without going through float64.
+func (e OpenAI) EmbedDocumentsFloat32(ctx context.Context, texts []string) ([][]float32, error) {
+	batchedTexts := embeddings.BatchTexts(
+		embeddings.MaybeRemoveNewLines(texts, e.StripNewLines),
+		e.BatchSize,
+	)
+
+	emb := make([][]float32, 0, len(texts))
+	for _, texts := range batchedTexts {
+		curTextEmbeddings, err := e.client.CreateEmbeddingFloat32(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+
+		textLengths := make([]int, 0, len(texts))
+		for _, text := range texts {
+			textLengths = append(textLengths, len(text))
+		}
+
+		combined, err := embeddings.CombineVectorsFloat32(curTextEmbeddings, textLengths)
+		if err != nil {
+			return nil, err
+		}
+
+		emb = append(emb, combined)
+	}
+
+	return emb, nil
+}
+
+// EmbedQueryFloat32 is EmbedQuery returning a float32 vector.
+func (e OpenAI) EmbedQueryFloat32(ctx context.Context, text string) ([]float32, error) {
+	if e.StripNewLines {
+		text = strings.ReplaceAll(text, "\n", " ")
+	}
+
+	emb, err := e.client.CreateEmbeddingFloat32(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+
+	return emb[0], nil
+}
@@ -33,6 +33,32 @@ func WithBatchSize(batchSize int) Option {
 	}
 }
 
+// WithModel is an option for specifying the embedding model, such as
+// "text-embedding-004".
+func WithModel(model string) Option {
+	return func(p *VertexAIPaLM) {
+		p.Model = model
+	}
+}
+
+// WithTaskTypes is an option for specifying the task types of the embeddings
+// of documents and queries, such as vertexai.TaskTypeRetrievalDocument and
+// vertexai.TaskTypeRetrievalQuery.
+func WithTaskTypes(document, query vertexai.TaskType) Option {
+	return func(p *VertexAIPaLM) {
+		p.DocumentTaskType = document
+		p.QueryTaskType = query
+	}
+}
+
+// WithDimensions is an option for specifying the number of dimensions of the
+// embeddings, for the models supporting it.
+func WithDimensions(dimensions int) Option {
+	return func(p *VertexAIPaLM) {
+		p.Dimensions = dimensions
+	}
+}
+
 func applyClientOptions(opts ...Option) (*VertexAIPaLM, error) {
 	v := &VertexAIPaLM{
 		StripNewLines: _defaultStripNewLines,
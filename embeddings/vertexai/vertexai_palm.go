@@ -14,6 +14,15 @@ type VertexAIPaLM struct { //nolint:revive
 
 	StripNewLines bool
 	BatchSize     int
+	// Model is the embedding model, the default model of the client if empty.
+	Model string
+	// DocumentTaskType and QueryTaskType are the task types of the embeddings
+	// of documents and queries, unset if empty.
+	DocumentTaskType vertexai.TaskType
+	QueryTaskType    vertexai.TaskType
+	// Dimensions is the number of dimensions of the embeddings, the default of
+	// the model if zero.
+	Dimensions int
 }
 
 var _ embeddings.Embedder = VertexAIPaLM{}
@@ -37,7 +46,7 @@ func (e VertexAIPaLM) EmbedDocuments(ctx context.Context, texts []string) ([][]f
 
 	emb := make([][]float64, 0, len(texts))
 	for _, texts := range batchedTexts {
-		curTextEmbeddings, err := e.client.CreateEmbedding(ctx, texts)
+		curTextEmbeddings, err := e.client.CreateEmbeddingWithOptions(ctx, texts, e.embeddingOptions(e.DocumentTaskType)...)
 		if err != nil {
 			return nil, err
 		}
@@ -64,10 +73,24 @@ func (e VertexAIPaLM) EmbedQuery(ctx context.Context, text string) ([]float64, e
 		text = strings.ReplaceAll(text, "\n", " ")
 	}
 
-	emb, err := e.client.CreateEmbedding(ctx, []string{text})
+	emb, err := e.client.CreateEmbeddingWithOptions(ctx, []string{text}, e.embeddingOptions(e.QueryTaskType)...)
 	if err != nil {
 		return nil, err
 	}
 
 	return emb[0], nil
 }
+
+func (e VertexAIPaLM) embeddingOptions(taskType vertexai.TaskType) []vertexai.EmbeddingOption {
+	opts := []vertexai.EmbeddingOption{}
+	if e.Model != "" {
+		opts = append(opts, vertexai.WithEmbeddingModel(e.Model))
+	}
+	if taskType != "" {
+		opts = append(opts, vertexai.WithEmbeddingTaskType(taskType))
+	}
+	if e.Dimensions > 0 {
+		opts = append(opts, vertexai.WithEmbeddingDimensions(e.Dimensions))
+	}
+	return opts
+}
@@ -0,0 +1,28 @@
+/*
+Package evaluation grades the outputs of llms, chains and agents with a
+language model as judge, for regression testing prompts and chains in Go test
+suites.
+
+QAEvaluator grades answers against reference answers, CriteriaEvaluator grades
+an output against criteria such as helpfulness or conciseness, and
+PairwiseEvaluator picks the better of two outputs. RubricEvaluator grades an
+output on the criteria of a rubric, which LoadRubric reads from a YAML file
+with their descriptions, scales and graded examples. Each returns a score
+between 0 and 1 with the reasoning of the judge:
+
+	judge, err := openai.NewChat(openai.WithModel("gpt-4"))
+	...
+	qa := evaluation.NewQAEvaluator(judge)
+	result, err := qa.Evaluate(ctx, evaluation.QAExample{
+		Input:      "What is the capital of France?",
+		Reference:  "Paris",
+		Prediction: answer,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1.0, result.Score, result.Reasoning)
+
+EvaluateToolSelection measures how often an agent picks the expected tool on
+labeled requests, and ToolDescriptionOptimizer asks a language model for tool
+descriptions fixing its mistakes, reporting the accuracy before and after.
+*/
+package evaluation
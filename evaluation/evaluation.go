@@ -0,0 +1,60 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+// ErrInvalidGrade is returned when the answer of the judge holds no grade.
+var ErrInvalidGrade = errors.New("judge answer holds no grade")
+
+// Result is the grade given to an output.
+type Result struct {
+	// Score is between 0 and 1, 1 being the best grade.
+	Score float64
+	// Value is the grade as given by the judge, such as "CORRECT" or "Y".
+	Value string
+	// Reasoning is the explanation of the judge.
+	Reasoning string
+}
+
+// judge is an llm chain grading outputs, called with a zero temperature so
+// that grades are reproducible.
+type judge struct {
+	chain *chains.LLMChain
+}
+
+func newJudge(llm llms.LanguageModel, template string, inputVariables []string) judge {
+	return judge{chain: chains.NewLLMChain(llm, prompts.NewPromptTemplate(template, inputVariables))}
+}
+
+func (j judge) grade(ctx context.Context, inputs map[string]any) (string, error) {
+	answer, err := chains.Predict(ctx, j.chain, inputs, chains.WithTemperature(0))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// lastLineValue returns the value following the prefix on the last line of the
+// answer starting with it, case insensitively, and the lines of the answer
+// before it as the reasoning.
+func lastLineValue(answer, prefix string) (string, string, error) {
+	lines := strings.Split(answer, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if len(line) < len(prefix) || !strings.EqualFold(line[:len(prefix)], prefix) {
+			continue
+		}
+		value := strings.ToUpper(strings.TrimSpace(line[len(prefix):]))
+		reasoning := strings.TrimSpace(strings.Join(lines[:i], "\n"))
+		return strings.Trim(value, ".*"), reasoning, nil
+	}
+	return "", "", fmt.Errorf("%w: no line starting with %q in %q", ErrInvalidGrade, prefix, answer)
+}
@@ -0,0 +1,176 @@
+package evaluation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// testJudge answers with its answer and records the prompt it was given.
+type testJudge struct {
+	answer string
+	prompt string
+}
+
+func (j *testJudge) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	j.prompt = promptValues[0].String()
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: j.answer}}}}, nil
+}
+
+func (j *testJudge) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func TestQAEvaluator(t *testing.T) {
+	t.Parallel()
+
+	judge := &testJudge{answer: "The student says Paris, as does the reference.\nGRADE: CORRECT"}
+	result, err := NewQAEvaluator(judge).Evaluate(context.Background(), QAExample{
+		Input:      "What is the capital of France?",
+		Reference:  "Paris",
+		Prediction: "It is Paris.",
+	})
+	require.NoError(t, err)
+	require.Equal(t, Result{
+		Score:     1,
+		Value:     "CORRECT",
+		Reasoning: "The student says Paris, as does the reference.",
+	}, result)
+	require.Contains(t, judge.prompt, "Student answer: It is Paris.")
+
+	judge.answer = "grade: incorrect"
+	result, err = NewQAEvaluator(judge).Evaluate(context.Background(), QAExample{})
+	require.NoError(t, err)
+	require.Equal(t, 0.0, result.Score)
+
+	judge.answer = "I am not sure."
+	_, err = NewQAEvaluator(judge).Evaluate(context.Background(), QAExample{})
+	require.ErrorIs(t, err, ErrInvalidGrade)
+}
+
+func TestCriteriaEvaluator(t *testing.T) {
+	t.Parallel()
+
+	judge := &testJudge{answer: "It answers the question but rambles.\nhelpfulness: Y\nConciseness: N"}
+	e := NewCriteriaEvaluator(judge, CriterionHelpfulness, CriterionConciseness)
+	result, err := e.Evaluate(context.Background(), "What is 2+2?", "Well, let me think at length... 4.", "")
+	require.NoError(t, err)
+	require.Equal(t, CriteriaResult{
+		Score: 0.5,
+		Criteria: map[string]Result{
+			"helpfulness": {Score: 1, Value: "Y"},
+			"conciseness": {Score: 0, Value: "N"},
+		},
+		Reasoning: "It answers the question but rambles.",
+	}, result)
+	require.Contains(t, judge.prompt, "conciseness: Is the submission concise and to the point?")
+	require.NotContains(t, judge.prompt, "Reference answer")
+
+	judge.answer = "helpfulness: Y"
+	_, err = e.Evaluate(context.Background(), "What is 2+2?", "4", "4")
+	require.ErrorIs(t, err, ErrInvalidGrade)
+	require.Contains(t, judge.prompt, "Reference answer: 4")
+}
+
+func TestPairwiseEvaluator(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		answer    string
+		preferred Preference
+		score     float64
+	}{
+		{"A is accurate.\nVERDICT: A", PreferA, 1},
+		{"B is accurate.\nVerdict: B", PreferB, 0},
+		{"Both are fine.\nVERDICT: TIE", PreferTie, 0.5},
+	}
+	for _, c := range cases {
+		judge := &testJudge{answer: c.answer}
+		result, err := NewPairwiseEvaluator(judge).Evaluate(context.Background(), "Say hi", "hi", "hello", "")
+		require.NoError(t, err)
+		require.Equal(t, c.preferred, result.Preferred)
+		require.Equal(t, c.score, result.Score)
+		require.NotEmpty(t, result.Reasoning)
+		require.Contains(t, judge.prompt, "[Answer of assistant B]\nhello")
+	}
+}
+
+type testTool struct {
+	name        string
+	description string
+}
+
+func (t testTool) Name() string                                 { return t.name }
+func (t testTool) Description() string                          { return t.description }
+func (t testTool) Call(context.Context, string) (string, error) { return "", nil }
+
+// keywordAgent uses the first tool whose description contains a word of the
+// input.
+type keywordAgent struct {
+	tools []tools.Tool
+}
+
+func (a keywordAgent) Plan(_ context.Context, _ []schema.AgentStep, inputs map[string]string) ([]schema.AgentAction, *schema.AgentFinish, error) { //nolint:lll
+	for _, t := range a.tools {
+		for _, word := range strings.Fields(inputs["input"]) {
+			if strings.Contains(t.Description(), word) {
+				return []schema.AgentAction{{Tool: t.Name(), ToolInput: inputs["input"]}}, nil, nil
+			}
+		}
+	}
+	return nil, &schema.AgentFinish{}, nil
+}
+
+func (a keywordAgent) GetInputKeys() []string  { return []string{"input"} }
+func (a keywordAgent) GetOutputKeys() []string { return []string{"output"} }
+
+func TestToolDescriptionOptimizer(t *testing.T) {
+	t.Parallel()
+
+	newAgent := func(ts []tools.Tool) agents.Agent { return keywordAgent{tools: ts} }
+	ts := []tools.Tool{
+		testTool{name: "calculator", description: "computes math"},
+		testTool{name: "search", description: "finds news"},
+	}
+	examples := []ToolSelectionExample{
+		{Input: "math 2+2", Tool: "calculator"},
+		{Input: "weather today", Tool: "search"},
+		{Input: "latest news", Tool: "search"},
+		{Input: "hello", Tool: ""},
+	}
+
+	selection, err := EvaluateToolSelection(context.Background(), newAgent, ts, examples)
+	require.NoError(t, err)
+	require.Equal(t, 0.75, selection.Accuracy)
+	require.Equal(t, []ToolSelectionMistake{{Input: "weather today", Expected: "search"}}, selection.Mistakes)
+
+	judge := &testJudge{answer: "```json\n{\"search\": \"finds news and weather\"}\n```"}
+	report, err := NewToolDescriptionOptimizer(judge, newAgent).Optimize(context.Background(), ts, examples)
+	require.NoError(t, err)
+	require.Contains(t, judge.prompt, "Request: weather today\nExpected tool: search\nPicked tool: (no tool)")
+	require.Contains(t, judge.prompt, "search: finds news\n")
+	require.Equal(t, 1.0, report.After.Accuracy)
+	require.Equal(t, []ToolDescriptionChange{
+		{Tool: "search", Before: "finds news", After: "finds news and weather"},
+	}, report.Changes)
+	require.Equal(t, "finds news and weather", report.Tools[1].Description())
+	require.Equal(t, "Tool selection accuracy: 75.0% -> 100.0%\n\nsearch\n  before: finds news\n  after:  finds news and weather\n",
+		report.String())
+
+	// Descriptions making the agent worse are not kept.
+	judge.answer = `{"calculator": "computes"}`
+	report, err = NewToolDescriptionOptimizer(judge, newAgent).Optimize(context.Background(), ts, examples)
+	require.NoError(t, err)
+	require.Equal(t, 0.75, report.After.Accuracy)
+	require.Empty(t, report.Changes)
+	require.Equal(t, ts, report.Tools)
+
+	_, err = EvaluateToolSelection(context.Background(), newAgent, ts, nil)
+	require.ErrorIs(t, err, ErrNoToolSelectionExamples)
+}
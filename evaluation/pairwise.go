@@ -0,0 +1,90 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+//nolint:lll
+const _pairwiseTemplate = `Act as a fair judge and compare the answers of two AI assistants to the input below. Choose the assistant that follows the instructions of the user and answers the input better, considering helpfulness, relevance, accuracy, depth and level of detail.{{if .reference}} A reference answer is given to judge accuracy.{{end}} Do not let the order or the length of the answers, or the names of the assistants, influence your decision.
+
+[Input]
+{{.input}}
+{{- if .reference}}
+
+[Reference answer]
+{{.reference}}
+{{- end}}
+
+[Answer of assistant A]
+{{.prediction_a}}
+
+[Answer of assistant B]
+{{.prediction_b}}
+
+Explain your reasoning, then write VERDICT: A if assistant A is better, VERDICT: B if assistant B is better, or VERDICT: TIE on the last line.`
+
+// Preference is the output preferred by a PairwiseEvaluator.
+type Preference string
+
+const (
+	PreferA   Preference = "A"
+	PreferB   Preference = "B"
+	PreferTie Preference = "TIE"
+)
+
+// PairwiseResult is the outcome of the comparison of two outputs.
+type PairwiseResult struct {
+	Preferred Preference
+	// Score is 1 if the first output is preferred, 0 if the second one is and
+	// 0.5 for a tie.
+	Score float64
+	// Reasoning is the explanation of the judge.
+	Reasoning string
+}
+
+// PairwiseEvaluator compares two outputs for the same input, for example the
+// outputs of two versions of a prompt.
+type PairwiseEvaluator struct {
+	judge judge
+}
+
+// NewPairwiseEvaluator creates a pairwise evaluator with the language model as
+// judge.
+func NewPairwiseEvaluator(llm llms.LanguageModel) PairwiseEvaluator {
+	return PairwiseEvaluator{
+		judge: newJudge(llm, _pairwiseTemplate, []string{"input", "reference", "prediction_a", "prediction_b"}),
+	}
+}
+
+// Evaluate compares the predictions a and b made for the input. The reference
+// answer is shown to the judge if it is not empty.
+func (e PairwiseEvaluator) Evaluate(ctx context.Context, input, predictionA, predictionB, reference string) (PairwiseResult, error) { //nolint:lll
+	answer, err := e.judge.grade(ctx, map[string]any{
+		"input":        input,
+		"reference":    reference,
+		"prediction_a": predictionA,
+		"prediction_b": predictionB,
+	})
+	if err != nil {
+		return PairwiseResult{}, fmt.Errorf("pairwise evaluator: %w", err)
+	}
+
+	value, reasoning, err := lastLineValue(answer, "VERDICT:")
+	if err != nil {
+		return PairwiseResult{}, err
+	}
+	result := PairwiseResult{Preferred: Preference(value), Reasoning: reasoning}
+	switch result.Preferred {
+	case PreferA:
+		result.Score = 1
+	case PreferB:
+	case PreferTie:
+		result.Score = 0.5
+	default:
+		return PairwiseResult{}, fmt.Errorf("%w: unknown verdict %q", ErrInvalidGrade, value)
+	}
+	return result, nil
+}
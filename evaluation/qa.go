@@ -0,0 +1,61 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+//nolint:lll
+const _qaTemplate = `You are a teacher grading a quiz. You are given a question, the reference answer and the answer of a student. Grade the answer of the student on its factual accuracy compared to the reference answer only. Ignore differences in punctuation and phrasing. An answer containing more information than the reference answer is correct as long as it does not contradict it.
+
+Question: {{.input}}
+Reference answer: {{.reference}}
+Student answer: {{.prediction}}
+
+Explain your reasoning step by step, then write GRADE: CORRECT or GRADE: INCORRECT on the last line.`
+
+// QAExample is a question with its reference answer and the answer to grade.
+type QAExample struct {
+	Input      string
+	Reference  string
+	Prediction string
+}
+
+// QAEvaluator grades answers to questions against reference answers. The score
+// of correct answers is 1 and the one of incorrect answers is 0.
+type QAEvaluator struct {
+	judge judge
+}
+
+// NewQAEvaluator creates a QA evaluator with the language model as judge.
+func NewQAEvaluator(llm llms.LanguageModel) QAEvaluator {
+	return QAEvaluator{judge: newJudge(llm, _qaTemplate, []string{"input", "reference", "prediction"})}
+}
+
+// Evaluate grades the prediction of the example.
+func (e QAEvaluator) Evaluate(ctx context.Context, example QAExample) (Result, error) {
+	answer, err := e.judge.grade(ctx, map[string]any{
+		"input":      example.Input,
+		"reference":  example.Reference,
+		"prediction": example.Prediction,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("qa evaluator: %w", err)
+	}
+
+	value, reasoning, err := lastLineValue(answer, "GRADE:")
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Value: value, Reasoning: reasoning}
+	switch value {
+	case "CORRECT":
+		result.Score = 1
+	case "INCORRECT":
+	default:
+		return Result{}, fmt.Errorf("%w: unknown grade %q", ErrInvalidGrade, value)
+	}
+	return result, nil
+}
@@ -0,0 +1,285 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"gopkg.in/yaml.v3"
+)
+
+//nolint:lll
+const _rubricTemplate = `You are assessing a submitted answer to an input against a rubric.
+
+[BEGIN DATA]
+Input: {{.input}}
+Submission: {{.prediction}}
+{{- if .reference}}
+Reference answer: {{.reference}}
+{{- end}}
+Rubric:
+{{.rubric}}
+[END DATA]
+
+Grade the submission on each criterion of the rubric. First explain your reasoning step by step. Then write one line per criterion, in the order above, made of the name of the criterion, a colon, and its grade: a whole number of its scale for the criteria with a scale, and Y if the submission meets it or N if it does not for the others, for example "{{.example}}: {{.exampleGrade}}".`
+
+var (
+	// ErrUnsupportedRubricFile is returned when loading a file whose extension
+	// is not .yaml, .yml or .json.
+	ErrUnsupportedRubricFile = errors.New("unsupported rubric file extension")
+	// ErrInvalidRubric is returned when loading or using a rubric that can not
+	// be graded on, such as one without criteria.
+	ErrInvalidRubric = errors.New("invalid rubric")
+)
+
+// Rubric is a set of criteria an output is graded on, which can be kept in
+// YAML files maintained apart from the code, see LoadRubric.
+type Rubric struct {
+	Name        string            `json:"name,omitempty"        yaml:"name,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Criteria    []RubricCriterion `json:"criteria"              yaml:"criteria"`
+}
+
+// RubricCriterion is a criterion of a rubric. Criteria without a scale are
+// met or not, as the criteria of a CriteriaEvaluator.
+type RubricCriterion struct {
+	Name        string `json:"name"        yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	// Scale is the range of the grades of the criterion, if graded on a scale.
+	Scale *Scale `json:"scale,omitempty" yaml:"scale,omitempty"`
+	// Examples are graded outputs shown to the judge.
+	Examples []RubricExample `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
+
+// Scale is the range of the grades of a criterion, from Min, the worst
+// grade, to Max, the best one.
+type Scale struct {
+	Min int `json:"min" yaml:"min"`
+	Max int `json:"max" yaml:"max"`
+	// Labels describe what some grades stand for, such as 1: "off topic".
+	Labels map[int]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// RubricExample is an output graded on a criterion.
+type RubricExample struct {
+	Input      string `json:"input,omitempty"     yaml:"input,omitempty"`
+	Prediction string `json:"prediction"          yaml:"prediction"`
+	// Grade is a number of the scale of the criterion, or Y or N for criteria
+	// without a scale.
+	Grade     string `json:"grade"               yaml:"grade"`
+	Reasoning string `json:"reasoning,omitempty" yaml:"reasoning,omitempty"`
+}
+
+// LoadRubric loads a rubric from a YAML or JSON file, such as:
+//
+//	name: support answers
+//	criteria:
+//	  - name: politeness
+//	    description: Is the answer polite?
+//	  - name: accuracy
+//	    description: Does the answer match the documentation?
+//	    scale:
+//	      min: 1
+//	      max: 5
+//	      labels:
+//	        1: contradicts the documentation
+//	        5: fully accurate
+//	    examples:
+//	      - input: How do I reset my password?
+//	        prediction: Call us.
+//	        grade: 2
+//	        reasoning: The documentation describes a reset link.
+func LoadRubric(path string) (Rubric, error) {
+	unmarshal := yaml.Unmarshal
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	case ".json":
+		unmarshal = json.Unmarshal
+	default:
+		return Rubric{}, fmt.Errorf("%w: %s", ErrUnsupportedRubricFile, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rubric{}, err
+	}
+	var rubric Rubric
+	if err := unmarshal(data, &rubric); err != nil {
+		return Rubric{}, fmt.Errorf("%w: %s: %w", ErrInvalidRubric, path, err)
+	}
+	if err := rubric.Validate(); err != nil {
+		return Rubric{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return rubric, nil
+}
+
+// Validate checks that the rubric can be graded on: it must have criteria
+// with distinct names and descriptions, scales whose minimum is below their
+// maximum, and examples whose grades are valid.
+func (r Rubric) Validate() error {
+	if len(r.Criteria) == 0 {
+		return fmt.Errorf("%w: no criteria", ErrInvalidRubric)
+	}
+	names := make(map[string]bool, len(r.Criteria))
+	for _, c := range r.Criteria {
+		name := strings.ToLower(c.Name)
+		switch {
+		case strings.TrimSpace(c.Name) == "" || strings.ContainsAny(c.Name, ":\n"):
+			return fmt.Errorf("%w: invalid criterion name %q", ErrInvalidRubric, c.Name)
+		case names[name]:
+			return fmt.Errorf("%w: duplicate criterion %q", ErrInvalidRubric, c.Name)
+		case strings.TrimSpace(c.Description) == "":
+			return fmt.Errorf("%w: criterion %q has no description", ErrInvalidRubric, c.Name)
+		}
+		names[name] = true
+
+		if c.Scale != nil {
+			if c.Scale.Min >= c.Scale.Max {
+				return fmt.Errorf("%w: criterion %q has an empty scale", ErrInvalidRubric, c.Name)
+			}
+			for grade := range c.Scale.Labels {
+				if grade < c.Scale.Min || grade > c.Scale.Max {
+					return fmt.Errorf("%w: criterion %q has a label for %d, outside its scale", ErrInvalidRubric, c.Name, grade)
+				}
+			}
+		}
+		for _, e := range c.Examples {
+			if _, err := c.score(e.Grade); err != nil {
+				return fmt.Errorf("%w: example of criterion %q: %w", ErrInvalidRubric, c.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// score returns the score of the grade given on the criterion.
+func (c RubricCriterion) score(grade string) (float64, error) {
+	grade = strings.ToUpper(strings.TrimSpace(grade))
+	if c.Scale == nil {
+		switch grade {
+		case "Y", "YES":
+			return 1, nil
+		case "N", "NO":
+			return 0, nil
+		}
+		return 0, fmt.Errorf("%w: unknown grade %q for %s", ErrInvalidGrade, grade, c.Name)
+	}
+
+	// Judges sometimes write grades as fractions, such as 4/5.
+	value, _, _ := strings.Cut(grade, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < c.Scale.Min || n > c.Scale.Max {
+		return 0, fmt.Errorf("%w: grade %q for %s is not a number from %d to %d",
+			ErrInvalidGrade, grade, c.Name, c.Scale.Min, c.Scale.Max)
+	}
+	return float64(n-c.Scale.Min) / float64(c.Scale.Max-c.Scale.Min), nil
+}
+
+// describe returns the description of the criterion shown to the judge.
+func (c RubricCriterion) describe() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s\n", c.Name, c.Description)
+	if c.Scale == nil {
+		sb.WriteString("  Grade: Y or N.\n")
+	} else {
+		fmt.Fprintf(&sb, "  Grade: a whole number from %d (worst) to %d (best).\n", c.Scale.Min, c.Scale.Max)
+		grades := make([]int, 0, len(c.Scale.Labels))
+		for grade := range c.Scale.Labels {
+			grades = append(grades, grade)
+		}
+		sort.Ints(grades)
+		for _, grade := range grades {
+			fmt.Fprintf(&sb, "  %d: %s\n", grade, c.Scale.Labels[grade])
+		}
+	}
+	for _, e := range c.Examples {
+		sb.WriteString("  Example:")
+		if e.Input != "" {
+			fmt.Fprintf(&sb, " input %q,", e.Input)
+		}
+		fmt.Fprintf(&sb, " submission %q, grade %s", e.Prediction, strings.TrimSpace(e.Grade))
+		if e.Reasoning != "" {
+			fmt.Fprintf(&sb, ", because %s", e.Reasoning)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// exampleGrade returns a grade of the criterion, used in the instructions of
+// the judge.
+func (c RubricCriterion) exampleGrade() string {
+	if c.Scale == nil {
+		return "Y"
+	}
+	return strconv.Itoa(c.Scale.Max)
+}
+
+// RubricEvaluator grades outputs on the criteria of a rubric.
+type RubricEvaluator struct {
+	judge  judge
+	rubric Rubric
+}
+
+// NewRubricEvaluator creates an evaluator grading outputs on the criteria of
+// the rubric with the language model as judge. An error wrapping
+// ErrInvalidRubric is returned if the rubric is not valid.
+func NewRubricEvaluator(llm llms.LanguageModel, rubric Rubric) (RubricEvaluator, error) {
+	if err := rubric.Validate(); err != nil {
+		return RubricEvaluator{}, err
+	}
+	return RubricEvaluator{
+		judge: newJudge(llm, _rubricTemplate,
+			[]string{"input", "prediction", "reference", "rubric", "example", "exampleGrade"}),
+		rubric: rubric,
+	}, nil
+}
+
+// Evaluate grades the prediction made for the input on each criterion. The
+// score of a criterion graded on a scale is its grade brought between 0 and 1,
+// and the score of the result is the mean of the scores of the criteria. The
+// reference answer is shown to the judge if it is not empty.
+func (e RubricEvaluator) Evaluate(ctx context.Context, input, prediction, reference string) (CriteriaResult, error) {
+	descriptions := make([]string, len(e.rubric.Criteria))
+	for i, c := range e.rubric.Criteria {
+		descriptions[i] = c.describe()
+	}
+	first := e.rubric.Criteria[0]
+	answer, err := e.judge.grade(ctx, map[string]any{
+		"input":        input,
+		"prediction":   prediction,
+		"reference":    reference,
+		"rubric":       strings.TrimRight(strings.Join(descriptions, ""), "\n"),
+		"example":      first.Name,
+		"exampleGrade": first.exampleGrade(),
+	})
+	if err != nil {
+		return CriteriaResult{}, fmt.Errorf("rubric evaluator: %w", err)
+	}
+
+	result := CriteriaResult{Criteria: make(map[string]Result, len(e.rubric.Criteria))}
+	var total float64
+	for i, c := range e.rubric.Criteria {
+		value, reasoning, err := lastLineValue(answer, c.Name+":")
+		if err != nil {
+			return CriteriaResult{}, err
+		}
+		if i == 0 {
+			result.Reasoning = reasoning
+		}
+		score, err := c.score(value)
+		if err != nil {
+			return CriteriaResult{}, err
+		}
+		result.Criteria[c.Name] = Result{Score: score, Value: value}
+		total += score
+	}
+	result.Score = total / float64(len(e.rubric.Criteria))
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package evaluation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRubric(t *testing.T) {
+	t.Parallel()
+
+	rubric, err := LoadRubric(filepath.Join("testdata", "support.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, Rubric{
+		Name:        "support answers",
+		Description: "Answers of the support assistant.",
+		Criteria: []RubricCriterion{
+			{Name: "politeness", Description: "Is the answer polite?"},
+			{
+				Name:        "accuracy",
+				Description: "Does the answer match the documentation?",
+				Scale: &Scale{Min: 1, Max: 5, Labels: map[int]string{
+					1: "contradicts the documentation",
+					5: "fully accurate",
+				}},
+				Examples: []RubricExample{{
+					Input:      "How do I reset my password?",
+					Prediction: "Call us.",
+					Grade:      "2",
+					Reasoning:  "the documentation describes a reset link",
+				}},
+			},
+		},
+	}, rubric)
+
+	dir := t.TempDir()
+	for content, want := range map[string]error{
+		"criteria: []": ErrInvalidRubric,
+		"criteria:\n  - name: a\n    description: b\n  - name: A\n    description: c":                         ErrInvalidRubric,
+		"criteria:\n  - name: a\n    description: b\n    scale: {min: 5, max: 1}":                             ErrInvalidRubric,
+		"criteria:\n  - name: a\n    description: b\n    examples: [{prediction: x, grade: maybe}]":           ErrInvalidGrade,
+		"criteria:\n  - name: a\n    description: b\n    scale: {min: 1, max: 3}\n    examples: [{grade: 4}]": ErrInvalidGrade,
+		"criteria: {": ErrInvalidRubric,
+	} {
+		path := filepath.Join(dir, "rubric.yml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := LoadRubric(path)
+		require.ErrorIs(t, err, want, content)
+	}
+
+	_, err = LoadRubric(filepath.Join("testdata", "rubric.txt"))
+	require.ErrorIs(t, err, ErrUnsupportedRubricFile)
+}
+
+func TestRubricEvaluator(t *testing.T) {
+	t.Parallel()
+
+	rubric, err := LoadRubric(filepath.Join("testdata", "support.yaml"))
+	require.NoError(t, err)
+	judge := &testJudge{answer: "Polite, but it leaves out a step.\npoliteness: Y\nAccuracy: 4/5"}
+	e, err := NewRubricEvaluator(judge, rubric)
+	require.NoError(t, err)
+
+	result, err := e.Evaluate(context.Background(), "How do I change my email?", "Open the settings, please.", "")
+	require.NoError(t, err)
+	require.Equal(t, CriteriaResult{
+		Score: 0.875,
+		Criteria: map[string]Result{
+			"politeness": {Score: 1, Value: "Y"},
+			"accuracy":   {Score: 0.75, Value: "4/5"},
+		},
+		Reasoning: "Polite, but it leaves out a step.",
+	}, result)
+	require.Contains(t, judge.prompt, "accuracy: Does the answer match the documentation?\n"+
+		"  Grade: a whole number from 1 (worst) to 5 (best).\n"+
+		"  1: contradicts the documentation\n"+
+		"  5: fully accurate\n"+
+		`  Example: input "How do I reset my password?", submission "Call us.", grade 2, because the documentation describes a reset link`) //nolint:lll
+	require.Contains(t, judge.prompt, `for example "politeness: Y"`)
+
+	judge.answer = "politeness: Y\naccuracy: 7"
+	_, err = e.Evaluate(context.Background(), "", "", "")
+	require.ErrorIs(t, err, ErrInvalidGrade)
+
+	_, err = NewRubricEvaluator(judge, Rubric{})
+	require.ErrorIs(t, err, ErrInvalidRubric)
+}
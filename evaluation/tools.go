@@ -0,0 +1,267 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/tools"
+)
+
+//nolint:lll
+const _toolDescriptionsTemplate = `You are improving the descriptions of the tools of an AI agent. The agent picks the tool to use for a request from their names and descriptions only. On a set of labeled requests, it picked the wrong tool for the following ones:
+
+{{.mistakes}}
+The tools and their current descriptions are:
+
+{{.tools}}
+Rewrite the descriptions so that the agent picks the expected tool for requests like these, without breaking the requests it gets right. Keep each description short, say what the tool is for and when to use it rather than another tool. Do not rename the tools.
+
+Answer with a JSON object mapping the name of each tool whose description you changed to its new description.`
+
+// ErrNoToolSelectionExamples is returned when tool selection is evaluated
+// without examples.
+var ErrNoToolSelectionExamples = errors.New("no tool selection examples")
+
+// ToolSelectionExample is a request to an agent with the name of the tool it
+// should use first, or an empty name if it should answer without tools.
+type ToolSelectionExample struct {
+	Input string
+	Tool  string
+}
+
+// ToolSelectionMistake is an example for which an agent used the wrong tool.
+type ToolSelectionMistake struct {
+	Input    string
+	Expected string
+	// Picked is the tool the agent used, empty if it answered without tools
+	// or its output could not be parsed.
+	Picked string
+}
+
+// ToolSelection is how well an agent picks its tools.
+type ToolSelection struct {
+	// Accuracy is the share of the examples for which the agent used the
+	// expected tool, between 0 and 1.
+	Accuracy float64
+	Mistakes []ToolSelectionMistake
+}
+
+// AgentFactory creates the agent evaluated with the tools, such as:
+//
+//	func(ts []tools.Tool) agents.Agent { return agents.NewOneShotAgent(llm, ts) }
+type AgentFactory func(tools []tools.Tool) agents.Agent
+
+// EvaluateToolSelection evaluates how well the agent created with the tools
+// picks the tool expected by each example, by planning the first step of each
+// example with the input as the "input" value.
+func EvaluateToolSelection(ctx context.Context, newAgent AgentFactory, ts []tools.Tool, examples []ToolSelectionExample) (ToolSelection, error) { //nolint:lll
+	if len(examples) == 0 {
+		return ToolSelection{}, ErrNoToolSelectionExamples
+	}
+
+	agent := newAgent(ts)
+	selection := ToolSelection{}
+	for _, example := range examples {
+		picked := ""
+		actions, _, err := agent.Plan(ctx, nil, map[string]string{"input": example.Input})
+		switch {
+		case errors.Is(err, agents.ErrUnableToParseOutput):
+		case err != nil:
+			return ToolSelection{}, fmt.Errorf("tool selection: %w", err)
+		case len(actions) > 0:
+			picked = actions[0].Tool
+		}
+		if !strings.EqualFold(strings.TrimSpace(picked), example.Tool) {
+			selection.Mistakes = append(selection.Mistakes, ToolSelectionMistake{
+				Input:    example.Input,
+				Expected: example.Tool,
+				Picked:   picked,
+			})
+		}
+	}
+	selection.Accuracy = float64(len(examples)-len(selection.Mistakes)) / float64(len(examples))
+	return selection, nil
+}
+
+// ToolDescriptionChange is a description of a tool changed by a
+// ToolDescriptionOptimizer.
+type ToolDescriptionChange struct {
+	Tool   string
+	Before string
+	After  string
+}
+
+// ToolDescriptionReport is the outcome of the optimization of the descriptions
+// of tools.
+type ToolDescriptionReport struct {
+	// Before and After are the tool selections with the original descriptions
+	// and with the optimized ones.
+	Before ToolSelection
+	After  ToolSelection
+	// Changes are the descriptions changed, empty if no proposal improved the
+	// accuracy.
+	Changes []ToolDescriptionChange
+	// Tools are the tools with the optimized descriptions.
+	Tools []tools.Tool
+}
+
+// String returns the report as text, with the accuracies, the changed
+// descriptions and the mistakes left.
+func (r ToolDescriptionReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Tool selection accuracy: %.1f%% -> %.1f%%\n", r.Before.Accuracy*100, r.After.Accuracy*100)
+	for _, c := range r.Changes {
+		fmt.Fprintf(&sb, "\n%s\n  before: %s\n  after:  %s\n", c.Tool, c.Before, c.After)
+	}
+	if len(r.After.Mistakes) > 0 {
+		sb.WriteString("\nRemaining mistakes:\n")
+		sb.WriteString(formatMistakes(r.After.Mistakes))
+	}
+	return sb.String()
+}
+
+// ToolDescriptionOptimizer improves the descriptions of the tools of an agent,
+// asking a language model for descriptions fixing the mistakes the agent makes
+// on labeled examples, and keeping them only if they improve its accuracy.
+type ToolDescriptionOptimizer struct {
+	judge    judge
+	newAgent AgentFactory
+	rounds   int
+}
+
+// ToolDescriptionOption is a function that configures a
+// ToolDescriptionOptimizer.
+type ToolDescriptionOption func(*ToolDescriptionOptimizer)
+
+// WithOptimizationRounds sets the number of times descriptions are proposed,
+// each round starting from the best descriptions so far. Defaults to 1.
+func WithOptimizationRounds(rounds int) ToolDescriptionOption {
+	return func(o *ToolDescriptionOptimizer) {
+		o.rounds = rounds
+	}
+}
+
+// NewToolDescriptionOptimizer creates an optimizer of the descriptions of the
+// tools of the agents created by newAgent, with the language model proposing
+// the descriptions.
+func NewToolDescriptionOptimizer(llm llms.LanguageModel, newAgent AgentFactory, opts ...ToolDescriptionOption) ToolDescriptionOptimizer { //nolint:lll
+	o := ToolDescriptionOptimizer{
+		judge:    newJudge(llm, _toolDescriptionsTemplate, []string{"mistakes", "tools"}),
+		newAgent: newAgent,
+		rounds:   1,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Optimize evaluates the tool selection of the agent on the examples, proposes
+// better descriptions for the tools and evaluates them again.
+func (o ToolDescriptionOptimizer) Optimize(ctx context.Context, ts []tools.Tool, examples []ToolSelectionExample) (ToolDescriptionReport, error) { //nolint:lll
+	before, err := EvaluateToolSelection(ctx, o.newAgent, ts, examples)
+	if err != nil {
+		return ToolDescriptionReport{}, err
+	}
+
+	best, bestTools := before, ts
+	for round := 0; round < o.rounds && len(best.Mistakes) > 0; round++ {
+		proposed, err := o.propose(ctx, bestTools, best.Mistakes)
+		if err != nil {
+			return ToolDescriptionReport{}, err
+		}
+		selection, err := EvaluateToolSelection(ctx, o.newAgent, proposed, examples)
+		if err != nil {
+			return ToolDescriptionReport{}, err
+		}
+		if selection.Accuracy > best.Accuracy {
+			best, bestTools = selection, proposed
+		}
+	}
+
+	report := ToolDescriptionReport{Before: before, After: best, Tools: bestTools}
+	for i, t := range bestTools {
+		if t.Description() != ts[i].Description() {
+			report.Changes = append(report.Changes, ToolDescriptionChange{
+				Tool:   t.Name(),
+				Before: ts[i].Description(),
+				After:  t.Description(),
+			})
+		}
+	}
+	return report, nil
+}
+
+// propose returns the tools with the descriptions proposed by the language
+// model to fix the mistakes.
+func (o ToolDescriptionOptimizer) propose(ctx context.Context, ts []tools.Tool, mistakes []ToolSelectionMistake) ([]tools.Tool, error) { //nolint:lll
+	var toolList strings.Builder
+	for _, t := range ts {
+		fmt.Fprintf(&toolList, "%s: %s\n", t.Name(), strings.TrimSpace(t.Description()))
+	}
+	answer, err := o.judge.grade(ctx, map[string]any{
+		"mistakes": formatMistakes(mistakes),
+		"tools":    toolList.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tool description optimizer: %w", err)
+	}
+
+	parsed, err := outputparser.NewJSON().Parse(answer)
+	if err != nil {
+		return nil, fmt.Errorf("tool description optimizer: %w", err)
+	}
+	descriptions, ok := parsed.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("tool description optimizer: %w", outputparser.ParseError{
+			Text:   answer,
+			Reason: "expected a JSON object of descriptions",
+		})
+	}
+
+	proposed := make([]tools.Tool, 0, len(ts))
+	for _, t := range ts {
+		if d, ok := descriptions[t.Name()].(string); ok && strings.TrimSpace(d) != "" {
+			t = describedTool{Tool: unwrapDescribed(t), description: strings.TrimSpace(d)}
+		}
+		proposed = append(proposed, t)
+	}
+	return proposed, nil
+}
+
+func formatMistakes(mistakes []ToolSelectionMistake) string {
+	var sb strings.Builder
+	for _, m := range mistakes {
+		expected, picked := m.Expected, m.Picked
+		if expected == "" {
+			expected = "(no tool)"
+		}
+		if picked == "" {
+			picked = "(no tool)"
+		}
+		fmt.Fprintf(&sb, "Request: %s\nExpected tool: %s\nPicked tool: %s\n\n", m.Input, expected, picked)
+	}
+	return sb.String()
+}
+
+// describedTool is a tool with another description.
+type describedTool struct {
+	tools.Tool
+	description string
+}
+
+func (t describedTool) Description() string {
+	return t.description
+}
+
+func unwrapDescribed(t tools.Tool) tools.Tool { //nolint:ireturn
+	if d, ok := t.(describedTool); ok {
+		return d.Tool
+	}
+	return t
+}
@@ -0,0 +1,134 @@
+package rundiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Diff returns a readable diff of the runs, empty if they made the same calls.
+// The steps of the runs are compared in order. For each step whose prompts,
+// options, outputs or error differ, the lines of the differing fields are
+// listed, prefixed by "-" when only in the first run and "+" when only in the
+// second.
+func Diff(a, b Run) string {
+	var sb strings.Builder
+	for i := 0; i < len(a.Steps) || i < len(b.Steps); i++ {
+		var stepA, stepB Step
+		header := fmt.Sprintf("step %d", i+1)
+		switch {
+		case i >= len(a.Steps):
+			header += " (only in the second run)"
+			stepB = b.Steps[i]
+		case i >= len(b.Steps):
+			header += " (only in the first run)"
+			stepA = a.Steps[i]
+		default:
+			stepA, stepB = a.Steps[i], b.Steps[i]
+		}
+		diffStep(&sb, header, stepA, stepB)
+	}
+	return sb.String()
+}
+
+func diffStep(sb *strings.Builder, header string, a, b Step) {
+	type field struct {
+		name string
+		a, b string
+	}
+	var fields []field
+	for i := 0; i < len(a.Prompts) || i < len(b.Prompts); i++ {
+		fields = append(fields, field{fmt.Sprintf("prompt %d", i+1), index(a.Prompts, i), index(b.Prompts, i)})
+	}
+	fields = append(fields, field{"options", optionsText(a), optionsText(b)})
+	for i := 0; i < len(a.Outputs) || i < len(b.Outputs); i++ {
+		fields = append(fields, field{fmt.Sprintf("output %d", i+1), index(a.Outputs, i), index(b.Outputs, i)})
+	}
+	fields = append(fields, field{"error", a.Err, b.Err})
+
+	for _, f := range fields {
+		if f.a == f.b {
+			continue
+		}
+		fmt.Fprintf(sb, "%s %s:\n", header, f.name)
+		for _, line := range diffLines(splitLines(f.a), splitLines(f.b)) {
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+		}
+	}
+}
+
+func index(texts []string, i int) string {
+	if i < len(texts) {
+		return texts[i]
+	}
+	return ""
+}
+
+// optionsText returns the options of the step as indented JSON, or an empty
+// string for steps missing from a run.
+func optionsText(s Step) string {
+	if s.Prompts == nil && s.Outputs == nil && s.Err == "" {
+		return ""
+	}
+	b, err := json.MarshalIndent(s.Options, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%+v", s.Options)
+	}
+	return string(b)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// diffLines returns the lines of a and b prefixed by "  " when in both, "- "
+// when only in a and "+ " when only in b, following their longest common
+// subsequence.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+	return lines
+}
@@ -0,0 +1,14 @@
+// Package rundiff helps pinpoint regressions introduced by changes to prompt
+// templates, memory or call options.
+//
+// A Recorder wraps the language model of a chain or agent and records each
+// call made during a run: the rendered prompts, the options and the outputs
+// of the model. Runs can be saved as JSON, and Diff compares two of them step
+// by step:
+//
+//	recorder := rundiff.NewRecorder(llm)
+//	chain := chains.NewLLMChain(recorder, prompt)
+//	_, err := chains.Call(ctx, chain, inputs)
+//	...
+//	fmt.Print(rundiff.Diff(baseline, recorder.Run()))
+package rundiff
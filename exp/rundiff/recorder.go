@@ -0,0 +1,98 @@
+package rundiff
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Step is a call of the language model made during a run.
+type Step struct {
+	// Prompts are the rendered prompts given to the model.
+	Prompts []string `json:"prompts"`
+	// Options are the options of the call, with the options setting
+	// functions such as the streaming function left out.
+	Options llms.CallOptions `json:"options"`
+	// Outputs are the texts generated for the prompts, or the function calls
+	// of chat models calling a function.
+	Outputs []string `json:"outputs"`
+	// Err is the error of failed calls.
+	Err string `json:"error,omitempty"`
+}
+
+// Run is the sequence of calls of the language model made during a run.
+type Run struct {
+	Steps []Step `json:"steps"`
+}
+
+// Recorder is a language model recording the calls made to the model it
+// wraps.
+type Recorder struct {
+	LLM llms.LanguageModel
+
+	mu  sync.Mutex
+	run Run
+}
+
+var _ llms.LanguageModel = &Recorder{}
+
+// NewRecorder creates a recorder of the calls made to the language model.
+func NewRecorder(llm llms.LanguageModel) *Recorder {
+	return &Recorder{LLM: llm}
+}
+
+// GeneratePrompt calls the wrapped model and records the call.
+func (r *Recorder) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	step := Step{Prompts: make([]string, len(promptValues))}
+	for i, p := range promptValues {
+		step.Prompts[i] = p.String()
+	}
+	for _, opt := range options {
+		opt(&step.Options)
+	}
+
+	result, err := r.LLM.GeneratePrompt(ctx, promptValues, options...)
+	if err != nil {
+		step.Err = err.Error()
+	}
+	for _, generations := range result.Generations {
+		for _, g := range generations {
+			step.Outputs = append(step.Outputs, generationOutput(g))
+		}
+	}
+
+	r.mu.Lock()
+	r.run.Steps = append(r.run.Steps, step)
+	r.mu.Unlock()
+	return result, err
+}
+
+// GetNumTokens returns the number of tokens of the text for the wrapped
+// model.
+func (r *Recorder) GetNumTokens(text string) int {
+	return r.LLM.GetNumTokens(text)
+}
+
+// Run returns the calls recorded so far.
+func (r *Recorder) Run() Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Run{Steps: append([]Step(nil), r.run.Steps...)}
+}
+
+// Reset forgets the calls recorded so far, to record another run.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run = Run{}
+}
+
+func generationOutput(g *llms.Generation) string {
+	if g.Text == "" && g.Message != nil && g.Message.FunctionCall != nil {
+		call := g.Message.FunctionCall
+		return call.Name + "(" + llms.FunctionArguments(call.Arguments) + ")"
+	}
+	return g.Text
+}
@@ -0,0 +1,63 @@
+package rundiff
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// echoLanguageModel answers with the upper cased prompt.
+type echoLanguageModel struct{}
+
+func (echoLanguageModel) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	generations := make([][]*llms.Generation, len(promptValues))
+	for i, p := range promptValues {
+		generations[i] = []*llms.Generation{{Text: strings.ToUpper(p.String())}}
+	}
+	return llms.LLMResult{Generations: generations}, nil
+}
+
+func (echoLanguageModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	recorder := NewRecorder(echoLanguageModel{})
+
+	chain := chains.NewLLMChain(recorder, prompts.NewPromptTemplate("Answer briefly.\nQuestion: {{.q}}", []string{"q"}))
+	_, err := chains.Call(ctx, chain, map[string]any{"q": "why"}, chains.WithTemperature(0.2))
+	require.NoError(t, err)
+	baseline := recorder.Run()
+	require.Len(t, baseline.Steps, 1)
+
+	// Runs survive a JSON round trip.
+	data, err := json.Marshal(baseline)
+	require.NoError(t, err)
+	var loaded Run
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	require.Empty(t, Diff(baseline, loaded))
+
+	recorder.Reset()
+	chain = chains.NewLLMChain(recorder, prompts.NewPromptTemplate("Answer in detail.\nQuestion: {{.q}}", []string{"q"}))
+	_, err = chains.Call(ctx, chain, map[string]any{"q": "why"}, chains.WithTemperature(0.2))
+	require.NoError(t, err)
+	_, err = chains.Call(ctx, chain, map[string]any{"q": "how"}, chains.WithTemperature(0.2))
+	require.NoError(t, err)
+
+	diff := Diff(baseline, recorder.Run())
+	require.Contains(t, diff, "step 1 prompt 1:\n- Answer briefly.\n+ Answer in detail.\n  Question: why\n")
+	require.Contains(t, diff, "step 1 output 1:\n- ANSWER BRIEFLY.\n+ ANSWER IN DETAIL.\n  QUESTION: WHY\n")
+	require.NotContains(t, diff, "step 1 options")
+	require.Contains(t, diff, "step 2 (only in the second run) prompt 1:\n+ Answer in detail.\n+ Question: how\n")
+	require.Contains(t, diff, `+   "temperature": 0.2,`)
+}
@@ -0,0 +1,8 @@
+// Package testkit contains helpers for end-to-end testing of conversational
+// chains and agents.
+//
+// A SimulatedUser is driven by a language model playing a Persona. It talks to
+// the chain under test for a number of turns, trying to reach the goal of a
+// Script. The resulting Transcript can then be checked with one or more
+// Evaluators, making it possible to write conversational regression tests.
+package testkit
@@ -0,0 +1,126 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+// Result is the outcome of an evaluation.
+type Result struct {
+	Passed bool
+	Reason string
+}
+
+// Evaluator is the interface for checks run on the transcript of a conversation.
+type Evaluator interface {
+	Evaluate(ctx context.Context, script Script, transcript Transcript) (Result, error)
+}
+
+// Evaluate runs all the evaluators on the transcript and returns their results
+// in order. It stops at the first evaluator returning an error.
+func Evaluate(ctx context.Context, script Script, transcript Transcript, evaluators ...Evaluator) ([]Result, error) {
+	results := make([]Result, 0, len(evaluators))
+	for _, e := range evaluators {
+		r, err := e.Evaluate(ctx, script, transcript)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// CompletedEvaluator passes if the simulated user signaled goal completion.
+type CompletedEvaluator struct{}
+
+var _ Evaluator = CompletedEvaluator{}
+
+// Evaluate implements the Evaluator interface.
+func (CompletedEvaluator) Evaluate(_ context.Context, _ Script, transcript Transcript) (Result, error) {
+	if !transcript.Completed {
+		return Result{Reason: "the simulated user did not signal goal completion"}, nil
+	}
+	return Result{Passed: true}, nil
+}
+
+// ContainsEvaluator passes if the replies of the agent contain all of the
+// substrings, in any turn.
+type ContainsEvaluator struct {
+	Substrings []string
+	// IgnoreCase makes the match case insensitive.
+	IgnoreCase bool
+}
+
+var _ Evaluator = ContainsEvaluator{}
+
+// Evaluate implements the Evaluator interface.
+func (e ContainsEvaluator) Evaluate(_ context.Context, _ Script, transcript Transcript) (Result, error) {
+	replies := make([]string, 0, len(transcript.Turns))
+	for _, turn := range transcript.Turns {
+		replies = append(replies, turn.Agent)
+	}
+	text := strings.Join(replies, "\n")
+	if e.IgnoreCase {
+		text = strings.ToLower(text)
+	}
+
+	for _, s := range e.Substrings {
+		needle := s
+		if e.IgnoreCase {
+			needle = strings.ToLower(s)
+		}
+		if !strings.Contains(text, needle) {
+			return Result{Reason: fmt.Sprintf("agent never replied with %q", s)}, nil
+		}
+	}
+	return Result{Passed: true}, nil
+}
+
+//nolint:lll
+const _goalEvaluatorTemplate = `You are grading a conversation between a user and an AI assistant.
+
+The goal of the user was: {{.goal}}
+
+Conversation:
+{{.transcript}}
+Was the goal of the user achieved? Answer YES or NO on the first line, followed by a one sentence explanation on the second line.`
+
+// GoalEvaluator uses a language model as a judge to decide if the goal of the
+// script was achieved in the conversation.
+type GoalEvaluator struct {
+	chain *chains.LLMChain
+}
+
+var _ Evaluator = GoalEvaluator{}
+
+// NewGoalEvaluator creates a new goal evaluator with the language model as judge.
+func NewGoalEvaluator(llm llms.LanguageModel) GoalEvaluator {
+	return GoalEvaluator{
+		chain: chains.NewLLMChain(llm, prompts.NewPromptTemplate(
+			_goalEvaluatorTemplate,
+			[]string{"goal", "transcript"},
+		)),
+	}
+}
+
+// Evaluate implements the Evaluator interface.
+func (e GoalEvaluator) Evaluate(ctx context.Context, script Script, transcript Transcript) (Result, error) {
+	verdict, err := chains.Predict(ctx, e.chain, map[string]any{
+		"goal":       script.Goal,
+		"transcript": transcript.String(),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("goal evaluator: %w", err)
+	}
+
+	answer, reason, _ := strings.Cut(strings.TrimSpace(verdict), "\n")
+	return Result{
+		Passed: strings.HasPrefix(strings.ToUpper(strings.TrimSpace(answer)), "YES"),
+		Reason: strings.TrimSpace(reason),
+	}, nil
+}
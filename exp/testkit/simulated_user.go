@@ -0,0 +1,168 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+const (
+	_defaultMaxTurns  = 5
+	_defaultStopToken = "GOAL_COMPLETE"
+)
+
+var (
+	// ErrMissingGoal is returned when running a script without a goal.
+	ErrMissingGoal = errors.New("script has no goal")
+	// ErrTooManyTurns is returned when the simulated user did not signal goal
+	// completion before the maximum number of turns was reached.
+	ErrTooManyTurns = errors.New("maximum number of turns reached")
+)
+
+//nolint:lll
+const _simulatedUserTemplate = `You are role playing a user talking to an AI assistant. Stay in character and never reveal that you are an AI.
+
+Your name is {{.name}}. {{.description}}
+
+Your goal in this conversation is: {{.goal}}
+
+Write only your next message to the assistant. Keep it short. When the goal has been achieved, or it is clear it can not be achieved, reply with exactly {{.stop_token}} and nothing else.
+
+Conversation so far:
+{{.transcript}}
+User:`
+
+// Persona describes the user the language model plays.
+type Persona struct {
+	// Name is the name of the user.
+	Name string
+	// Description describes the background, tone and behavior of the user.
+	Description string
+}
+
+// Script describes what the simulated user tries to achieve.
+type Script struct {
+	// Goal is what the user wants to get done by talking to the agent.
+	Goal string
+	// Steps are optional messages sent verbatim as the first user turns, before
+	// the language model takes over.
+	Steps []string
+	// MaxTurns is the maximum number of turns in the conversation. Defaults to 5.
+	MaxTurns int
+}
+
+// Turn is one exchange between the simulated user and the agent.
+type Turn struct {
+	User  string
+	Agent string
+}
+
+// Transcript is the record of a conversation.
+type Transcript struct {
+	Turns []Turn
+	// Completed is true if the simulated user signaled that the goal was done.
+	Completed bool
+}
+
+// String formats the transcript as a "User:"/"Assistant:" dialog.
+func (t Transcript) String() string {
+	var b strings.Builder
+	for _, turn := range t.Turns {
+		fmt.Fprintf(&b, "User: %s\nAssistant: %s\n", turn.User, turn.Agent)
+	}
+	return b.String()
+}
+
+// SimulatedUser is a language model playing a user that converses with a chain.
+type SimulatedUser struct {
+	chain     *chains.LLMChain
+	persona   Persona
+	stopToken string
+}
+
+// SimulatedUserOption is a function that configures a SimulatedUser.
+type SimulatedUserOption func(*SimulatedUser)
+
+// WithStopToken sets the token the simulated user replies with when the goal of
+// the script has been reached. Defaults to "GOAL_COMPLETE".
+func WithStopToken(token string) SimulatedUserOption {
+	return func(u *SimulatedUser) {
+		u.stopToken = token
+	}
+}
+
+// NewSimulatedUser creates a new simulated user playing the persona.
+func NewSimulatedUser(llm llms.LanguageModel, persona Persona, opts ...SimulatedUserOption) *SimulatedUser {
+	u := &SimulatedUser{
+		chain: chains.NewLLMChain(llm, prompts.NewPromptTemplate(
+			_simulatedUserTemplate,
+			[]string{"name", "description", "goal", "stop_token", "transcript"},
+		)),
+		persona:   persona,
+		stopToken: _defaultStopToken,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// Converse runs the script against the agent. The agent must accept a single
+// input and return a single string output, as required by chains.Run. The
+// transcript is returned together with ErrTooManyTurns if the simulated user
+// did not signal goal completion within the maximum number of turns.
+func (u *SimulatedUser) Converse(ctx context.Context, agent chains.Chain, script Script) (Transcript, error) {
+	var transcript Transcript
+	if script.Goal == "" {
+		return transcript, ErrMissingGoal
+	}
+
+	maxTurns := script.MaxTurns
+	if maxTurns <= 0 {
+		maxTurns = _defaultMaxTurns
+	}
+
+	for i := 0; i < maxTurns; i++ {
+		var message string
+		if i < len(script.Steps) {
+			message = script.Steps[i]
+		} else {
+			var err error
+			message, err = u.nextMessage(ctx, script, transcript)
+			if err != nil {
+				return transcript, err
+			}
+			if strings.Contains(message, u.stopToken) {
+				transcript.Completed = true
+				return transcript, nil
+			}
+		}
+
+		reply, err := chains.Run(ctx, agent, message)
+		if err != nil {
+			return transcript, fmt.Errorf("agent turn %d: %w", i+1, err)
+		}
+		transcript.Turns = append(transcript.Turns, Turn{User: message, Agent: reply})
+	}
+
+	return transcript, ErrTooManyTurns
+}
+
+func (u *SimulatedUser) nextMessage(ctx context.Context, script Script, transcript Transcript) (string, error) {
+	message, err := chains.Predict(ctx, u.chain, map[string]any{
+		"name":        u.persona.Name,
+		"description": u.persona.Description,
+		"goal":        script.Goal,
+		"stop_token":  u.stopToken,
+		"transcript":  transcript.String(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("simulated user: %w", err)
+	}
+	return strings.TrimSpace(message), nil
+}
@@ -0,0 +1,89 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// scriptedLanguageModel returns its responses in order, one per call.
+type scriptedLanguageModel struct {
+	responses []string
+	calls     int
+}
+
+func (l *scriptedLanguageModel) GeneratePrompt(_ context.Context, _ []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	text := l.responses[l.calls%len(l.responses)]
+	l.calls++
+	return llms.LLMResult{
+		Generations: [][]*llms.Generation{{&llms.Generation{Text: text}}},
+	}, nil
+}
+
+func (l *scriptedLanguageModel) GetNumTokens(text string) int {
+	return len(text)
+}
+
+var _ llms.LanguageModel = &scriptedLanguageModel{}
+
+func newEchoAgent() chains.Chain {
+	return chains.NewLLMChain(
+		&scriptedLanguageModel{responses: []string{"Your table for two is booked at 7pm."}},
+		prompts.NewPromptTemplate("{{.input}}", []string{"input"}),
+	)
+}
+
+func TestSimulatedUserConverse(t *testing.T) {
+	t.Parallel()
+
+	user := NewSimulatedUser(
+		&scriptedLanguageModel{responses: []string{"Make it 7pm please.", "GOAL_COMPLETE"}},
+		Persona{Name: "Ada", Description: "You are hungry and impatient."},
+	)
+	script := Script{
+		Goal:  "Book a table for two.",
+		Steps: []string{"I want a table for two tonight."},
+	}
+
+	transcript, err := user.Converse(context.Background(), newEchoAgent(), script)
+	require.NoError(t, err)
+	require.True(t, transcript.Completed)
+	require.Len(t, transcript.Turns, 2)
+	require.Equal(t, "I want a table for two tonight.", transcript.Turns[0].User)
+	require.Equal(t, "Make it 7pm please.", transcript.Turns[1].User)
+
+	judge := NewGoalEvaluator(&scriptedLanguageModel{responses: []string{"YES\nThe table was booked."}})
+	results, err := Evaluate(context.Background(), script, transcript,
+		CompletedEvaluator{},
+		ContainsEvaluator{Substrings: []string{"BOOKED"}, IgnoreCase: true},
+		judge,
+	)
+	require.NoError(t, err)
+	for _, r := range results {
+		require.True(t, r.Passed, r.Reason)
+	}
+}
+
+func TestSimulatedUserTooManyTurns(t *testing.T) {
+	t.Parallel()
+
+	user := NewSimulatedUser(
+		&scriptedLanguageModel{responses: []string{"Are you sure?"}},
+		Persona{Name: "Bob"},
+	)
+	transcript, err := user.Converse(context.Background(), newEchoAgent(), Script{
+		Goal:     "Book a table.",
+		MaxTurns: 3,
+	})
+	require.ErrorIs(t, err, ErrTooManyTurns)
+	require.Len(t, transcript.Turns, 3)
+
+	r, err := CompletedEvaluator{}.Evaluate(context.Background(), Script{}, transcript)
+	require.NoError(t, err)
+	require.False(t, r.Passed)
+}
@@ -0,0 +1,17 @@
+// Package transcript renders the runs of chains and agents as Markdown or HTML
+// transcripts, to share in bug reports and demos.
+//
+// A Transcript is a sequence of entries: chat messages, prompts and
+// completions of the model, agent actions, tool calls, images and the final
+// answer. Transcripts are built with the Add methods, or recorded during a run
+// with a Recorder, a callbacks.Handler:
+//
+//	recorder := transcript.NewRecorder("Weather question")
+//	executor := agents.NewExecutor(agent, tools, agents.WithCallbacksHandler(recorder))
+//	_, err := chains.Run(ctx, executor, input)
+//	...
+//	err = recorder.Transcript().WriteHTML(w)
+//
+// Long entries, such as prompts and tool calls, are rendered in collapsible
+// <details> sections, which GitHub also renders in Markdown.
+package transcript
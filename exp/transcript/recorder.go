@@ -0,0 +1,110 @@
+package transcript
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Recorder is a callbacks.Handler recording the prompts, completions, agent
+// actions, tool calls and final answer of a run into a transcript. It is safe
+// for concurrent use, but the entries of concurrent runs are interleaved.
+type Recorder struct {
+	callbacks.SimpleHandler
+
+	mu         sync.Mutex
+	transcript Transcript
+}
+
+var _ callbacks.Handler = (*Recorder)(nil)
+
+// NewRecorder creates a recorder of a transcript with the title.
+func NewRecorder(title string) *Recorder {
+	return &Recorder{transcript: Transcript{Title: title}}
+}
+
+// Transcript returns a copy of the transcript recorded so far.
+func (r *Recorder) Transcript() *Transcript {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Transcript{
+		Title:   r.transcript.Title,
+		Entries: append([]Entry(nil), r.transcript.Entries...),
+	}
+}
+
+// Do calls f with the transcript being recorded, to add entries to it such as
+// the messages of the user or generated images.
+func (r *Recorder) Do(f func(t *Transcript)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f(&r.transcript)
+}
+
+func (r *Recorder) HandleLLMStart(_ context.Context, prompts []string) {
+	r.Do(func(t *Transcript) {
+		for _, p := range prompts {
+			t.Entries = append(t.Entries, Entry{Kind: EntryPrompt, Text: p})
+		}
+	})
+}
+
+func (r *Recorder) HandleLLMEnd(_ context.Context, output llms.LLMResult) {
+	r.Do(func(t *Transcript) {
+		for _, generations := range output.Generations {
+			for _, g := range generations {
+				t.Entries = append(t.Entries, Entry{Kind: EntryCompletion, Text: g.Text})
+			}
+		}
+	})
+}
+
+func (r *Recorder) HandleLLMError(_ context.Context, err error) {
+	r.Do(func(t *Transcript) { t.AddError(err) })
+}
+
+func (r *Recorder) HandleToolStart(_ context.Context, tool, input string) {
+	r.Do(func(t *Transcript) {
+		t.Entries = append(t.Entries, Entry{Kind: EntryToolCall, Tool: tool, Input: input})
+	})
+}
+
+func (r *Recorder) HandleToolEnd(_ context.Context, tool, output string) {
+	r.Do(func(t *Transcript) { r.endToolCall(t, tool, output, nil) })
+}
+
+func (r *Recorder) HandleToolError(_ context.Context, tool string, err error) {
+	r.Do(func(t *Transcript) { r.endToolCall(t, tool, "", err) })
+}
+
+func (r *Recorder) HandleAgentAction(_ context.Context, action schema.AgentAction) {
+	r.Do(func(t *Transcript) { t.AddAgentAction(action) })
+}
+
+func (r *Recorder) HandleAgentFinish(_ context.Context, finish schema.AgentFinish) {
+	text, ok := finish.ReturnValues["output"].(string)
+	if !ok {
+		text = fmt.Sprint(finish.ReturnValues)
+	}
+	r.Do(func(t *Transcript) { t.AddFinal(text) })
+}
+
+// endToolCall sets the result of the last call of the tool started, or adds
+// the call if its start was not recorded.
+func (r *Recorder) endToolCall(t *Transcript, tool, output string, err error) {
+	for i := len(t.Entries) - 1; i >= 0; i-- {
+		e := &t.Entries[i]
+		if e.Kind == EntryToolCall && e.Tool == tool && e.Output == "" && e.Err == "" {
+			e.Output = output
+			if err != nil {
+				e.Err = err.Error()
+			}
+			return
+		}
+	}
+	t.AddToolCall(tool, "", output, err)
+}
@@ -0,0 +1,125 @@
+package transcript
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Markdown returns the transcript rendered as Markdown.
+func (t *Transcript) Markdown() string {
+	var sb strings.Builder
+	if t.Title != "" {
+		fmt.Fprintf(&sb, "# %s\n\n", t.Title)
+	}
+	for _, e := range t.Entries {
+		switch e.Kind {
+		case EntryMessage:
+			fmt.Fprintf(&sb, "**%s:** %s\n\n", e.Role, e.Text)
+		case EntryPrompt:
+			writeMarkdownDetails(&sb, "Prompt", e.Text)
+		case EntryCompletion:
+			writeMarkdownDetails(&sb, "Completion", e.Text)
+		case EntryAgentAction:
+			writeMarkdownDetails(&sb, "Agent action: "+e.Tool, e.Text)
+		case EntryToolCall:
+			fmt.Fprintf(&sb, "<details>\n<summary>%s</summary>\n\n**Input**\n\n%s\n\n",
+				template.HTMLEscapeString(toolCallSummary(e)), codeBlock(e.Input))
+			if e.Err != "" {
+				fmt.Fprintf(&sb, "**Error**\n\n%s\n\n</details>\n\n", codeBlock(e.Err))
+			} else {
+				fmt.Fprintf(&sb, "**Output**\n\n%s\n\n</details>\n\n", codeBlock(e.Output))
+			}
+		case EntryImage:
+			fmt.Fprintf(&sb, "![%s](%s)\n\n", strings.ReplaceAll(e.Text, "]", "\\]"), e.URL)
+		case EntryFinal:
+			fmt.Fprintf(&sb, "**Final answer:** %s\n\n", e.Text)
+		case EntryError:
+			fmt.Fprintf(&sb, "**Error:** %s\n\n", e.Err)
+		}
+	}
+	return sb.String()
+}
+
+// WriteMarkdown writes the transcript rendered as Markdown.
+func (t *Transcript) WriteMarkdown(w io.Writer) error {
+	_, err := io.WriteString(w, t.Markdown())
+	return err
+}
+
+// WriteHTML writes the transcript rendered as a standalone HTML page.
+func (t *Transcript) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, t)
+}
+
+// writeMarkdownDetails writes a collapsible section with the summary and the
+// text in a code block.
+func writeMarkdownDetails(sb *strings.Builder, summary, text string) {
+	fmt.Fprintf(sb, "<details>\n<summary>%s</summary>\n\n%s\n\n</details>\n\n",
+		template.HTMLEscapeString(summary), codeBlock(text))
+}
+
+// codeBlock returns the text in a fenced code block, with a fence longer than
+// the backtick runs of the text.
+func codeBlock(text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + strings.TrimRight(text, "\n") + "\n" + fence
+}
+
+func toolCallSummary(e Entry) string {
+	if e.Err != "" {
+		return "Tool call: " + e.Tool + " (failed)"
+	}
+	return "Tool call: " + e.Tool
+}
+
+// imageURL marks the data URLs of images as safe, html/template rejecting
+// data URLs otherwise.
+func imageURL(url string) template.URL {
+	for _, prefix := range []string{"data:image/", "https://", "http://"} {
+		if strings.HasPrefix(url, prefix) {
+			return template.URL(url) //nolint:gosec
+		}
+	}
+	return template.URL("#")
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{ //nolint:gochecknoglobals
+	"imageURL":        imageURL,
+	"toolCallSummary": toolCallSummary,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ if .Title }}{{ .Title }}{{ else }}Transcript{{ end }}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; line-height: 1.5; }
+.message, .final, .error { white-space: pre-wrap; margin: 1em 0; }
+.error { color: #b00020; }
+details { margin: 1em 0; border: 1px solid #ddd; border-radius: 4px; padding: 0.5em; }
+summary { cursor: pointer; color: #555; }
+pre { white-space: pre-wrap; background: #f6f8fa; padding: 0.5em; }
+img { max-width: 100%; }
+</style>
+</head>
+<body>
+{{ if .Title }}<h1>{{ .Title }}</h1>
+{{ end }}
+{{- range .Entries }}
+{{- if eq .Kind "message" }}<div class="message"><strong>{{ .Role }}:</strong> {{ .Text }}</div>
+{{ else if eq .Kind "prompt" }}<details><summary>Prompt</summary><pre>{{ .Text }}</pre></details>
+{{ else if eq .Kind "completion" }}<details><summary>Completion</summary><pre>{{ .Text }}</pre></details>
+{{ else if eq .Kind "agent_action" }}<details><summary>Agent action: {{ .Tool }}</summary><pre>{{ .Text }}</pre></details>
+{{ else if eq .Kind "tool_call" }}<details><summary>{{ toolCallSummary . }}</summary><strong>Input</strong><pre>{{ .Input }}</pre>
+{{- if .Err }}<strong>Error</strong><pre>{{ .Err }}</pre>{{ else }}<strong>Output</strong><pre>{{ .Output }}</pre>{{ end }}</details>
+{{ else if eq .Kind "image" }}<img src="{{ imageURL .URL }}" alt="{{ .Text }}">
+{{ else if eq .Kind "final" }}<div class="final"><strong>Final answer:</strong> {{ .Text }}</div>
+{{ else if eq .Kind "error" }}<div class="error"><strong>Error:</strong> {{ .Err }}</div>
+{{ end }}
+{{- end }}</body>
+</html>
+`))
@@ -0,0 +1,145 @@
+package transcript
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EntryKind is the kind of an entry of a transcript.
+type EntryKind string
+
+const (
+	// EntryMessage is a chat message, with its Role and Text.
+	EntryMessage EntryKind = "message"
+	// EntryPrompt is a prompt given to the model, in Text.
+	EntryPrompt EntryKind = "prompt"
+	// EntryCompletion is a text generated by the model, in Text.
+	EntryCompletion EntryKind = "completion"
+	// EntryAgentAction is the decision of an agent to call the Tool with the
+	// Input, explained by the Text.
+	EntryAgentAction EntryKind = "agent_action"
+	// EntryToolCall is a call of the Tool with the Input, which returned the
+	// Output or failed with Err.
+	EntryToolCall EntryKind = "tool_call"
+	// EntryImage is an image, with its URL, which can be a data URL, and its
+	// alternative Text.
+	EntryImage EntryKind = "image"
+	// EntryFinal is the final answer of the run, in Text.
+	EntryFinal EntryKind = "final"
+	// EntryError is the error, in Err, which ended a step of the run.
+	EntryError EntryKind = "error"
+)
+
+// Entry is an entry of a transcript. Which fields are set depends on its kind.
+type Entry struct {
+	Kind   EntryKind `json:"kind"`
+	Role   string    `json:"role,omitempty"`
+	Text   string    `json:"text,omitempty"`
+	Tool   string    `json:"tool,omitempty"`
+	Input  string    `json:"input,omitempty"`
+	Output string    `json:"output,omitempty"`
+	Err    string    `json:"error,omitempty"`
+	URL    string    `json:"url,omitempty"`
+}
+
+// Transcript is the transcript of a run.
+type Transcript struct {
+	Title   string  `json:"title,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+// New creates an empty transcript with the title.
+func New(title string) *Transcript {
+	return &Transcript{Title: title}
+}
+
+// AddMessages adds chat messages, such as the history of a conversation.
+func (t *Transcript) AddMessages(messages ...schema.ChatMessage) {
+	for _, m := range messages {
+		t.Entries = append(t.Entries, Entry{Kind: EntryMessage, Role: messageRole(m), Text: m.GetContent()})
+	}
+}
+
+// AddMessage adds a chat message with the role.
+func (t *Transcript) AddMessage(role, text string) {
+	t.Entries = append(t.Entries, Entry{Kind: EntryMessage, Role: role, Text: text})
+}
+
+// AddAgentSteps adds the actions of an agent with the observations of their
+// tool calls, such as the intermediate steps returned by an executor.
+func (t *Transcript) AddAgentSteps(steps ...schema.AgentStep) {
+	for _, s := range steps {
+		t.AddAgentAction(s.Action)
+		t.Entries = append(t.Entries, Entry{
+			Kind:   EntryToolCall,
+			Tool:   s.Action.Tool,
+			Input:  s.Action.ToolInput,
+			Output: s.Observation,
+		})
+	}
+}
+
+// AddAgentAction adds the action of an agent.
+func (t *Transcript) AddAgentAction(action schema.AgentAction) {
+	t.Entries = append(t.Entries, Entry{
+		Kind:  EntryAgentAction,
+		Tool:  action.Tool,
+		Input: action.ToolInput,
+		Text:  action.Log,
+	})
+}
+
+// AddToolCall adds a call of the tool, which returned the output or failed
+// with err.
+func (t *Transcript) AddToolCall(tool, input, output string, err error) {
+	entry := Entry{Kind: EntryToolCall, Tool: tool, Input: input, Output: output}
+	if err != nil {
+		entry.Err = err.Error()
+	}
+	t.Entries = append(t.Entries, entry)
+}
+
+// AddImage adds the image at the URL, with its alternative text.
+func (t *Transcript) AddImage(url, alt string) {
+	t.Entries = append(t.Entries, Entry{Kind: EntryImage, URL: url, Text: alt})
+}
+
+// AddImageData adds an image of the MIME type, such as image/png, embedded in
+// the transcript as a data URL, with its alternative text.
+func (t *Transcript) AddImageData(mimeType string, data []byte, alt string) {
+	t.AddImage(fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(data)), alt)
+}
+
+// AddFinal adds the final answer of the run.
+func (t *Transcript) AddFinal(text string) {
+	t.Entries = append(t.Entries, Entry{Kind: EntryFinal, Text: text})
+}
+
+// AddError adds an error ending a step of the run.
+func (t *Transcript) AddError(err error) {
+	t.Entries = append(t.Entries, Entry{Kind: EntryError, Err: err.Error()})
+}
+
+func messageRole(m schema.ChatMessage) string {
+	if g, ok := m.(schema.GenericChatMessage); ok {
+		return g.Role
+	}
+	role, ok := roleNames[m.GetType()]
+	if !ok {
+		role = string(m.GetType())
+	}
+	if n, ok := m.(schema.Named); ok && n.GetName() != "" {
+		role += " (" + n.GetName() + ")"
+	}
+	return role
+}
+
+var roleNames = map[schema.ChatMessageType]string{ //nolint:gochecknoglobals
+	schema.ChatMessageTypeAI:       "AI",
+	schema.ChatMessageTypeHuman:    "Human",
+	schema.ChatMessageTypeSystem:   "System",
+	schema.ChatMessageTypeFunction: "Function",
+	schema.ChatMessageTypeTool:     "Tool",
+}
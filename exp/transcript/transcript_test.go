@@ -0,0 +1,87 @@
+package transcript
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := NewRecorder("Weather")
+	r.Do(func(t *Transcript) {
+		t.AddMessages(schema.HumanChatMessage{Content: "Weather in Paris?"})
+	})
+	r.HandleLLMStart(ctx, []string{"Answer the question: Weather in Paris?"})
+	r.HandleLLMEnd(ctx, llms.LLMResult{Generations: [][]*llms.Generation{{{Text: "Action: search"}}}})
+	r.HandleAgentAction(ctx, schema.AgentAction{Tool: "search", ToolInput: "paris weather", Log: "I should search."})
+	r.HandleToolStart(ctx, "search", "paris weather")
+	r.HandleToolEnd(ctx, "search", "sunny")
+	r.HandleToolStart(ctx, "forecast", "paris")
+	r.HandleToolError(ctx, "forecast", errors.New("offline"))
+	r.HandleAgentFinish(ctx, schema.AgentFinish{ReturnValues: map[string]any{"output": "It is sunny."}})
+
+	require.Equal(t, []Entry{
+		{Kind: EntryMessage, Role: "Human", Text: "Weather in Paris?"},
+		{Kind: EntryPrompt, Text: "Answer the question: Weather in Paris?"},
+		{Kind: EntryCompletion, Text: "Action: search"},
+		{Kind: EntryAgentAction, Tool: "search", Input: "paris weather", Text: "I should search."},
+		{Kind: EntryToolCall, Tool: "search", Input: "paris weather", Output: "sunny"},
+		{Kind: EntryToolCall, Tool: "forecast", Input: "paris", Err: "offline"},
+		{Kind: EntryFinal, Text: "It is sunny."},
+	}, r.Transcript().Entries)
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	tr := New("Demo")
+	tr.AddMessages(
+		schema.SystemChatMessage{Content: "Be brief."},
+		schema.ToolChatMessage{Name: "search", Content: "sunny"},
+	)
+	tr.AddAgentSteps(schema.AgentStep{
+		Action:      schema.AgentAction{Tool: "code", ToolInput: "```go\nfmt.Println()\n```", Log: "Run <code>"},
+		Observation: "done",
+	})
+	tr.AddImage("https://example.com/cat.png", "a cat")
+	tr.AddFinal("Done.")
+
+	expected := "# Demo\n\n" +
+		"**System:** Be brief.\n\n" +
+		"**Tool (search):** sunny\n\n" +
+		"<details>\n<summary>Agent action: code</summary>\n\n```\nRun <code>\n```\n\n</details>\n\n" +
+		"<details>\n<summary>Tool call: code</summary>\n\n**Input**\n\n````\n```go\nfmt.Println()\n```\n````\n\n" +
+		"**Output**\n\n```\ndone\n```\n\n</details>\n\n" +
+		"![a cat](https://example.com/cat.png)\n\n" +
+		"**Final answer:** Done.\n\n"
+	require.Equal(t, expected, tr.Markdown())
+}
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+
+	tr := New("Demo <1>")
+	tr.AddMessage("Human", "<script>alert(1)</script>")
+	tr.AddToolCall("search", "query", "", errors.New("offline"))
+	tr.AddImageData("image/png", []byte{0x89, 'P', 'N', 'G'}, "chart")
+	tr.AddImage("javascript:alert(1)", "bad")
+
+	var sb strings.Builder
+	require.NoError(t, tr.WriteHTML(&sb))
+	html := sb.String()
+	require.Contains(t, html, "<h1>Demo &lt;1&gt;</h1>")
+	require.Contains(t, html, "&lt;script&gt;alert(1)&lt;/script&gt;")
+	require.Contains(t, html, "<summary>Tool call: search (failed)</summary>")
+	require.Contains(t, html, "<strong>Error</strong><pre>offline</pre>")
+	require.Contains(t, html, `<img src="data:image/png;base64,iVBORw==" alt="chart">`)
+	require.Contains(t, html, `<img src="#" alt="bad">`)
+	require.NotContains(t, html, "<script>")
+}
@@ -0,0 +1,222 @@
+// Package redis is a minimal client of the Redis protocol, shared by the
+// packages storing data in a Redis server.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+)
+
+const _maxIdleConns = 4
+
+// ErrRedis is returned when the Redis server replies with an error or a reply
+// that can not be parsed.
+var ErrRedis = errors.New("redis error")
+
+// errMalformedReply is returned for replies that can not be parsed, after
+// which the connection is out of sync.
+var errMalformedReply = fmt.Errorf("%w: malformed reply", ErrRedis)
+
+// Client sends commands to a Redis server over a small pool of connections.
+// It is safe for concurrent use.
+type Client struct {
+	addr     string
+	password string
+	db       int
+	dialer   net.Dialer
+
+	mu   sync.Mutex
+	idle []*conn
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// New creates a client of the server at the address, such as
+// "localhost:6379", authenticating with the password if not empty and using
+// the database. Connections are opened when needed.
+func New(addr, password string, db int) *Client {
+	return &Client{addr: addr, password: password, db: db}
+}
+
+// Do sends the command and returns its reply: a []byte for strings and
+// integers, nil for null replies and a []any for arrays.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	replies, err := c.Pipeline(ctx, args)
+	if len(replies) == 0 {
+		return nil, err
+	}
+	return replies[0], err
+}
+
+// Pipeline sends the commands on the same connection, so that commands
+// between MULTI and EXEC run as a transaction, and returns their replies and
+// the first error replied, once all the replies are read.
+func (c *Client) Pipeline(ctx context.Context, commands ...[]string) ([]any, error) {
+	cn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := cn.do(ctx, commands...)
+	if err != nil && (!errors.Is(err, ErrRedis) || errors.Is(err, errMalformedReply)) {
+		cn.Close()
+		return nil, err
+	}
+	c.release(cn)
+	return replies, err
+}
+
+// Close closes the idle connections.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	idle := c.idle
+	c.idle = nil
+	c.mu.Unlock()
+
+	var errs []error
+	for _, cn := range idle {
+		errs = append(errs, cn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// conn returns an idle connection, or a new one authenticated and set to the
+// database.
+func (c *Client) conn(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	nc, err := c.dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc)}
+	var setup [][]string
+	if c.password != "" {
+		setup = append(setup, []string{"AUTH", c.password})
+	}
+	if c.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.db)})
+	}
+	if len(setup) > 0 {
+		if _, err := cn.do(ctx, setup...); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+func (c *Client) release(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.idle) >= _maxIdleConns {
+		cn.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+// do sends the commands on the connection and reads their replies.
+func (c *conn) do(ctx context.Context, commands ...[]string) ([]any, error) {
+	deadline, _ := ctx.Deadline()
+	if err := c.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	for _, args := range commands {
+		buf = append(buf, "*"+strconv.Itoa(len(args))+"\r\n"...)
+		for _, arg := range args {
+			buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"...)
+			buf = append(buf, arg...)
+			buf = append(buf, "\r\n"...)
+		}
+	}
+	if _, err := c.Write(buf); err != nil {
+		return nil, err
+	}
+
+	replies := make([]any, len(commands))
+	var firstErr error
+	for i := range commands {
+		reply, err := c.readReply()
+		if err != nil && (!errors.Is(err, ErrRedis) || errors.Is(err, errMalformedReply)) {
+			return nil, err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		replies[i] = reply
+	}
+	return replies, firstErr
+}
+
+// readReply reads a simple string, error, integer, bulk string or array
+// reply. The elements of an array are all read before an error of one of
+// them is returned.
+func (c *conn) readReply() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("%w %q", errMalformedReply, line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+', ':':
+		return []byte(payload), nil
+	case '-':
+		return nil, fmt.Errorf("%w: %s", ErrRedis, payload)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q", errMalformedReply, line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q", errMalformedReply, line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		elements := make([]any, n)
+		var firstErr error
+		for i := range elements {
+			element, err := c.readReply()
+			if err != nil && (!errors.Is(err, ErrRedis) || errors.Is(err, errMalformedReply)) {
+				return nil, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			elements[i] = element
+		}
+		return elements, firstErr
+	}
+	return nil, fmt.Errorf("%w %q", errMalformedReply, line)
+}
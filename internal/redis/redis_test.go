@@ -0,0 +1,31 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/redis"
+	"github.com/tmc/langchaingo/internal/redis/redistest"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	server := redistest.NewServer(t)
+	c := redis.New(server.Addr, "", 0)
+	defer c.Close()
+
+	replies, err := c.Pipeline(ctx, []string{"RPUSH", "list", "a", "b"}, []string{"LRANGE", "list", "0", "-1"})
+	require.NoError(t, err)
+	require.Equal(t, []any{[]byte("2"), []any{[]byte("a"), []byte("b")}}, replies)
+
+	// An error of a command of a transaction is returned once all the replies
+	// are read, and the connection stays usable.
+	_, err = c.Pipeline(ctx, []string{"MULTI"}, []string{"BOGUS"}, []string{"DEL", "list"}, []string{"EXEC"})
+	require.ErrorIs(t, err, redis.ErrRedis)
+	reply, err := c.Do(ctx, "LRANGE", "list", "0", "-1")
+	require.NoError(t, err)
+	require.Equal(t, []any{}, reply)
+}
@@ -0,0 +1,174 @@
+// Package redistest provides a fake Redis server for tests.
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Server is a fake Redis server serving the string, list, key expiry and
+// transaction commands the packages of the module use, and recording them.
+type Server struct {
+	Addr string
+
+	mu       sync.Mutex
+	strings  map[string]string
+	lists    map[string][]string
+	ttls     map[string]string
+	commands [][]string
+}
+
+// NewServer starts a fake server, closed at the end of the test.
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	s := &Server{
+		Addr:    l.Addr().String(),
+		strings: make(map[string]string),
+		lists:   make(map[string][]string),
+		ttls:    make(map[string]string),
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// Commands returns the commands received.
+func (s *Server) Commands() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.commands...)
+}
+
+// TTL returns the last time to live in milliseconds set on the key with PX or
+// PEXPIRE, if any.
+func (s *Server) TTL(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ttls[key]
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	var queued [][]string
+	inMulti := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, args)
+		var reply string
+		switch {
+		case args[0] == "MULTI":
+			inMulti, queued = true, nil
+			reply = "+OK\r\n"
+		case args[0] == "EXEC":
+			reply = "*" + strconv.Itoa(len(queued)) + "\r\n"
+			for _, cmd := range queued {
+				reply += s.exec(cmd)
+			}
+			inMulti, queued = false, nil
+		case inMulti:
+			queued = append(queued, args)
+			reply = "+QUEUED\r\n"
+		default:
+			reply = s.exec(args)
+		}
+		s.mu.Unlock()
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// exec runs the command and returns its reply. s.mu must be held.
+func (s *Server) exec(args []string) string {
+	switch args[0] {
+	case "AUTH", "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		if v, ok := s.strings[args[1]]; ok {
+			return bulk(v)
+		}
+		return "$-1\r\n"
+	case "SET":
+		s.strings[args[1]] = args[2]
+		if len(args) == 5 && args[3] == "PX" {
+			s.ttls[args[1]] = args[4]
+		}
+		return "+OK\r\n"
+	case "RPUSH":
+		s.lists[args[1]] = append(s.lists[args[1]], args[2:]...)
+		return ":" + strconv.Itoa(len(s.lists[args[1]])) + "\r\n"
+	case "LRANGE":
+		list := s.lists[args[1]]
+		reply := "*" + strconv.Itoa(len(list)) + "\r\n"
+		for _, v := range list {
+			reply += bulk(v)
+		}
+		return reply
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.lists[key]; ok {
+				n++
+			} else if _, ok := s.strings[key]; ok {
+				n++
+			}
+			delete(s.lists, key)
+			delete(s.strings, key)
+			delete(s.ttls, key)
+		}
+		return ":" + strconv.Itoa(n) + "\r\n"
+	case "PEXPIRE":
+		s.ttls[args[1]] = args[2]
+		return ":1\r\n"
+	}
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+func bulk(v string) string {
+	return "$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	var n int
+	if _, err := fmt.Fscanf(r, "*%d\r\n", &n); err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		var size int
+		if _, err := fmt.Fscanf(r, "$%d\r\n", &size); err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
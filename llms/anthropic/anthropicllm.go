@@ -3,7 +3,9 @@ package anthropic
 import (
 	"context"
 	"errors"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/anthropic/internal/anthropicclient"
@@ -18,7 +20,8 @@ var (
 )
 
 type LLM struct {
-	client *anthropicclient.Client
+	client       *anthropicclient.Client
+	rateLimiters *llms.RateLimiters
 }
 
 var (
@@ -30,7 +33,8 @@ var (
 func New(opts ...Option) (*LLM, error) {
 	c, err := newClient(opts...)
 	return &LLM{
-		client: c,
+		client:       c,
+		rateLimiters: &llms.RateLimiters{},
 	}, err
 }
 
@@ -47,7 +51,8 @@ func newClient(opts ...Option) (*anthropicclient.Client, error) {
 		return nil, ErrMissingToken
 	}
 
-	return anthropicclient.New(options.token, options.model)
+	return anthropicclient.New(options.token, options.model,
+		anthropicclient.WithHTTPClient(llms.HookDoer(http.DefaultClient)))
 }
 
 // Call requests a completion for the given prompt.
@@ -67,9 +72,18 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 	for _, opt := range options {
 		opt(&opts)
 	}
+	trim := llms.ApplyTrimToBoundary(&opts)
+	ctx, cancel, budget := llms.ApplyLatencyBudget(ctx, &opts)
+	defer cancel()
+	ctx = llms.ApplyTransportHook(ctx, opts)
 
 	generations := make([]*llms.Generation, 0, len(prompts))
 	for _, prompt := range prompts {
+		if err := llms.ApplyRateLimit(ctx, o.rateLimiters, o.model(opts), opts, prompt); err != nil {
+			return nil, err
+		}
+		budget.Reset()
+		start := time.Now()
 		result, err := o.client.CreateCompletion(ctx, &anthropicclient.CompletionRequest{
 			Model:         opts.Model,
 			Prompt:        prompt,
@@ -79,17 +93,34 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 			TopP:          opts.TopP,
 			StreamingFunc: opts.StreamingFunc,
 		})
+		if partial := budget.Partial(err); partial != nil {
+			generations = append(generations, partial)
+			break
+		}
 		if err != nil {
 			return nil, err
 		}
-		generations = append(generations, &llms.Generation{
-			Text: result.Text,
-		})
+		metadata := llms.NewResponseMetadata(result.Model, result.StopReason, llms.Usage{}, time.Since(start), result)
+		generation := &llms.Generation{
+			Text:           result.Text,
+			GenerationInfo: metadata.GenerationInfo(),
+			Metadata:       metadata,
+		}
+		trim.Trim(generation)
+		generations = append(generations, generation)
 	}
 
 	return generations, nil
 }
 
+// model returns the model used for a call.
+func (o *LLM) model(opts llms.CallOptions) string {
+	if opts.Model != "" {
+		return opts.Model
+	}
+	return o.client.Model
+}
+
 func (o *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
 	return llms.GeneratePrompt(ctx, o, promptValues, options...)
 }
@@ -74,7 +74,9 @@ type CompletionRequest struct {
 
 // Completion is a completion.
 type Completion struct {
-	Text string `json:"text"`
+	Text       string `json:"text"`
+	Model      string `json:"model,omitempty"`
+	StopReason string `json:"stop_reason,omitempty"`
 }
 
 // CreateCompletion creates a completion.
@@ -93,7 +95,9 @@ func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*C
 		return nil, err
 	}
 	return &Completion{
-		Text: resp.Completion,
+		Text:       resp.Completion,
+		Model:      resp.Model,
+		StopReason: resp.StopReason,
 	}, nil
 }
 
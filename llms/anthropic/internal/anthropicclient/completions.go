@@ -79,10 +79,6 @@ func (c *Client) createCompletion(ctx context.Context, payload *completionPayloa
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	if c.baseURL == "" {
-		c.baseURL = defaultBaseURL
-	}
-
 	url := fmt.Sprintf("%s/complete", c.baseURL)
 	// Build request
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payloadBytes))
@@ -0,0 +1,197 @@
+package bedrock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/bedrock/internal/bedrockclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	ErrEmptyResponse            = errors.New("no response")
+	ErrMissingRegion            = errors.New("missing the AWS region, set it in the AWS_REGION environment variable")
+	ErrMissingCredentials       = errors.New("missing the AWS credentials, set them in the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables") //nolint:lll
+	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
+)
+
+// LLM is a langchaingo LLM using the AWS bedrock runtime api. Requests are
+// signed with AWS Signature Version 4.
+type LLM struct {
+	client           *bedrockclient.Client
+	modelID          string
+	embeddingModelID string
+	// rateLimiters is shared with the Chat made from the LLM.
+	rateLimiters *llms.RateLimiters
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// New returns a new bedrock LLM.
+func New(opts ...Option) (*LLM, error) {
+	options := &options{
+		modelID:          os.Getenv(modelEnvVarName),
+		embeddingModelID: defaultEmbeddingModel,
+		region:           os.Getenv(regionEnvVarName),
+		credentials: Credentials{
+			AccessKeyID:     os.Getenv(accessKeyIDEnvVarName),
+			SecretAccessKey: os.Getenv(secretAccessKeyEnvVarName),
+			SessionToken:    os.Getenv(sessionTokenEnvVarName),
+		},
+		httpClient: http.DefaultClient,
+	}
+	if options.region == "" {
+		options.region = os.Getenv(defaultRegionEnvVarName)
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.modelID == "" {
+		options.modelID = defaultModel
+	}
+
+	if options.region == "" {
+		return nil, ErrMissingRegion
+	}
+	provider := options.provider
+	if provider == nil {
+		if options.credentials.AccessKeyID == "" || options.credentials.SecretAccessKey == "" {
+			return nil, ErrMissingCredentials
+		}
+		provider = bedrockclient.StaticCredentials(options.credentials)
+	}
+
+	return &LLM{
+		client: bedrockclient.New(options.region, options.endpoint,
+			provider, llms.HookDoer(options.httpClient)),
+		modelID:          options.modelID,
+		embeddingModelID: options.embeddingModelID,
+		rateLimiters:     &llms.RateLimiters{},
+	}, nil
+}
+
+// Call requests a completion for the given prompt.
+func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	r, err := o.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(r) == 0 {
+		return "", ErrEmptyResponse
+	}
+	return r[0].Text, nil
+}
+
+func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	messageSets := make([][]bedrockclient.Message, 0, len(prompts))
+	for _, prompt := range prompts {
+		messageSets = append(messageSets, []bedrockclient.Message{
+			{Role: bedrockclient.RoleUser, Content: prompt},
+		})
+	}
+
+	return o.generate(ctx, messageSets, options...)
+}
+
+func (o *LLM) generate(ctx context.Context, messageSets [][]bedrockclient.Message, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	trim := llms.ApplyTrimToBoundary(&opts)
+	ctx, cancel, budget := llms.ApplyLatencyBudget(ctx, &opts)
+	defer cancel()
+	ctx = llms.ApplyTransportHook(ctx, opts)
+	modelID := o.modelID
+	if opts.Model != "" {
+		modelID = opts.Model
+	}
+
+	generations := make([]*llms.Generation, 0, len(messageSets))
+	for _, messages := range messageSets {
+		prompts := make([]string, 0, len(messages))
+		for _, m := range messages {
+			prompts = append(prompts, m.Content)
+		}
+		if err := llms.ApplyRateLimit(ctx, o.rateLimiters, modelID, opts, prompts...); err != nil {
+			return nil, err
+		}
+		budget.Reset()
+		start := time.Now()
+		result, err := o.client.CreateCompletion(ctx, &bedrockclient.CompletionRequest{
+			ModelID:       modelID,
+			Messages:      messages,
+			MaxTokens:     opts.MaxTokens,
+			Temperature:   opts.Temperature,
+			TopP:          opts.TopP,
+			TopK:          opts.TopK,
+			StopWords:     opts.StopWords,
+			StreamingFunc: opts.StreamingFunc,
+		})
+		if partial := budget.Partial(err); partial != nil {
+			generations = append(generations, partial)
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		metadata := llms.NewResponseMetadata(modelID, result.StopReason, llms.Usage{
+			PromptTokens:     result.InputTokens,
+			CompletionTokens: result.OutputTokens,
+		}, time.Since(start), result)
+		generation := &llms.Generation{
+			Text:           result.Text,
+			Message:        &schema.AIChatMessage{Content: result.Text},
+			GenerationInfo: metadata.GenerationInfo(),
+			Metadata:       metadata,
+		}
+		trim.Trim(generation)
+		generations = append(generations, generation)
+	}
+
+	return generations, nil
+}
+
+func (o *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GeneratePrompt(ctx, o, promptValues, options...)
+}
+
+func (o *LLM) GetNumTokens(text string) int {
+	return llms.CountTokens(o.modelID, text)
+}
+
+// CreateEmbedding creates embeddings for the given input texts using a Titan
+// or Cohere embedding model.
+func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
+	return o.CreateEmbeddingWithOptions(ctx, inputTexts)
+}
+
+// CreateEmbeddingWithOptions does the same as CreateEmbedding, with options
+// such as the input type of the texts or the dimensions of the embeddings.
+func (o *LLM) CreateEmbeddingWithOptions(ctx context.Context, inputTexts []string, options ...EmbeddingOption) ([][]float64, error) { //nolint:lll
+	opts := bedrockclient.EmbeddingOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	embeddings, err := o.client.CreateEmbedding(ctx, o.embeddingModelID, inputTexts, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	if len(inputTexts) != len(embeddings) {
+		return embeddings, ErrUnexpectedResponseLength
+	}
+	return embeddings, nil
+}
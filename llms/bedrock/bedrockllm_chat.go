@@ -0,0 +1,63 @@
+package bedrock
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/bedrock/internal/bedrockclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Chat is a langchaingo chat LLM using the AWS bedrock runtime api. The chat
+// messages are formatted into the prompt format of the model family.
+type Chat struct {
+	LLM
+}
+
+// NewChat returns a new bedrock chat LLM.
+func NewChat(opts ...Option) (*Chat, error) {
+	llm, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Chat{LLM: *llm}, nil
+}
+
+// Call requests a chat response for the given messages.
+func (o *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { // nolint: lll
+	r, err := o.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return r[0].Message, nil
+}
+
+func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll
+	sets := make([][]bedrockclient.Message, 0, len(messageSets))
+	for _, messageSet := range messageSets {
+		msgs := make([]bedrockclient.Message, 0, len(messageSet))
+		for _, m := range messageSet {
+			msg := bedrockclient.Message{Content: m.GetContent()}
+			switch m.GetType() {
+			case schema.ChatMessageTypeSystem:
+				msg.Role = bedrockclient.RoleSystem
+			case schema.ChatMessageTypeAI:
+				msg.Role = bedrockclient.RoleAssistant
+			case schema.ChatMessageTypeHuman, schema.ChatMessageTypeGeneric, schema.ChatMessageTypeFunction,
+				schema.ChatMessageTypeTool:
+				msg.Role = bedrockclient.RoleUser
+			}
+			msgs = append(msgs, msg)
+		}
+		sets = append(sets, msgs)
+	}
+
+	return o.generate(ctx, sets, options...)
+}
+
+func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
+}
@@ -0,0 +1,122 @@
+package bedrock
+
+import "github.com/tmc/langchaingo/llms/bedrock/internal/bedrockclient"
+
+const (
+	modelEnvVarName           = "BEDROCK_MODEL_ID"
+	regionEnvVarName          = "AWS_REGION"
+	defaultRegionEnvVarName   = "AWS_DEFAULT_REGION"
+	accessKeyIDEnvVarName     = "AWS_ACCESS_KEY_ID"     //nolint:gosec
+	secretAccessKeyEnvVarName = "AWS_SECRET_ACCESS_KEY" //nolint:gosec
+	sessionTokenEnvVarName    = "AWS_SESSION_TOKEN"     //nolint:gosec
+
+	defaultModel          = "anthropic.claude-v2"
+	defaultEmbeddingModel = "amazon.titan-embed-text-v1"
+)
+
+// Credentials are the AWS credentials used to sign requests.
+type Credentials = bedrockclient.Credentials
+
+// CredentialsProvider returns the credentials used to sign a request. It is
+// called before each request.
+type CredentialsProvider = bedrockclient.CredentialsProvider
+
+type options struct {
+	modelID          string
+	embeddingModelID string
+	region           string
+	endpoint         string
+	credentials      Credentials
+	provider         CredentialsProvider
+
+	httpClient bedrockclient.Doer
+}
+
+type Option func(*options)
+
+// WithModel sets the id of the model to use, e.g. "anthropic.claude-v2",
+// "amazon.titan-text-express-v1" or "meta.llama2-13b-chat-v1". If not set, the
+// model is read from the BEDROCK_MODEL_ID environment variable, and defaults to
+// "anthropic.claude-v2".
+func WithModel(modelID string) Option {
+	return func(opts *options) {
+		opts.modelID = modelID
+	}
+}
+
+// WithEmbeddingModel sets the id of the Titan or Cohere model used by
+// CreateEmbedding, such as "amazon.titan-embed-text-v2:0" or
+// "cohere.embed-english-v3". If not set, "amazon.titan-embed-text-v1" is used.
+func WithEmbeddingModel(modelID string) Option {
+	return func(opts *options) {
+		opts.embeddingModelID = modelID
+	}
+}
+
+// WithRegion sets the AWS region of the bedrock endpoint. If not set, the region
+// is read from the AWS_REGION or AWS_DEFAULT_REGION environment variables.
+func WithRegion(region string) Option {
+	return func(opts *options) {
+		opts.region = region
+	}
+}
+
+// WithCredentials sets the AWS credentials used to sign the requests. If not
+// set, the credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+// and AWS_SESSION_TOKEN environment variables. The session token is only needed
+// for temporary credentials.
+func WithCredentials(accessKeyID, secretAccessKey, sessionToken string) Option {
+	return func(opts *options) {
+		opts.credentials = Credentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			SessionToken:    sessionToken,
+		}
+	}
+}
+
+// WithCredentialsProvider sets a provider of the credentials used to sign the
+// requests, e.g. to use temporary credentials from a profile, SSO or the
+// instance metadata service. It takes precedence over WithCredentials.
+func WithCredentialsProvider(provider CredentialsProvider) Option {
+	return func(opts *options) {
+		opts.provider = provider
+	}
+}
+
+// WithEndpoint overrides the url of the bedrock runtime api, e.g. to use a VPC
+// endpoint. If not set, the public endpoint of the region is used.
+func WithEndpoint(endpoint string) Option {
+	return func(opts *options) {
+		opts.endpoint = endpoint
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client. If not set, the default value
+// is http.DefaultClient.
+func WithHTTPClient(client bedrockclient.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+// EmbeddingOption is a function that configures the embeddings created by
+// CreateEmbeddingWithOptions.
+type EmbeddingOption func(*bedrockclient.EmbeddingOptions)
+
+// WithEmbeddingInputType sets the type of the embedded texts, such as
+// "search_document" or "search_query". Only Cohere models use it.
+func WithEmbeddingInputType(inputType string) EmbeddingOption {
+	return func(opts *bedrockclient.EmbeddingOptions) {
+		opts.InputType = inputType
+	}
+}
+
+// WithEmbeddingDimensions sets the number of dimensions of the embeddings. Only
+// the Titan models supporting it, such as "amazon.titan-embed-text-v2:0", use
+// it.
+func WithEmbeddingDimensions(dimensions int) EmbeddingOption {
+	return func(opts *bedrockclient.EmbeddingOptions) {
+		opts.Dimensions = dimensions
+	}
+}
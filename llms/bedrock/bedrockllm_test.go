@@ -0,0 +1,22 @@
+package bedrock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCredentials(t *testing.T) {
+	t.Setenv(regionEnvVarName, "us-east-1")
+	t.Setenv(accessKeyIDEnvVarName, "")
+	t.Setenv(secretAccessKeyEnvVarName, "")
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrMissingCredentials)
+
+	_, err = New(WithCredentialsProvider(func(context.Context) (Credentials, error) {
+		return Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	}))
+	require.NoError(t, err)
+}
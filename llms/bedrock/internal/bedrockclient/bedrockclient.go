@@ -0,0 +1,262 @@
+package bedrockclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnsupportedProvider is returned if the model id belongs to a model
+	// family that is not supported.
+	ErrUnsupportedProvider = errors.New("unsupported model provider")
+	// ErrEmptyResponse is returned if the model returns no output.
+	ErrEmptyResponse = errors.New("empty response")
+)
+
+const (
+	_contentTypeJSON = "application/json"
+	_service         = "bedrock"
+)
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Credentials are the AWS credentials used to sign requests.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is only needed for temporary credentials.
+	SessionToken string
+}
+
+// CredentialsProvider returns the credentials used to sign a request. It is
+// called before each request, so providers of temporary credentials can
+// refresh them.
+type CredentialsProvider func(ctx context.Context) (Credentials, error)
+
+// StaticCredentials returns a provider always returning the credentials.
+func StaticCredentials(credentials Credentials) CredentialsProvider {
+	return func(context.Context) (Credentials, error) {
+		return credentials, nil
+	}
+}
+
+// Client is a client for the bedrock runtime api.
+type Client struct {
+	region      string
+	endpoint    string
+	credentials CredentialsProvider
+	httpClient  Doer
+
+	// now returns the time used to sign requests.
+	now func() time.Time
+}
+
+// New returns a new bedrock client. If endpoint is empty, the public bedrock
+// runtime endpoint of the region is used.
+func New(region, endpoint string, credentials CredentialsProvider, httpClient Doer) *Client {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	}
+	return &Client{
+		region:      region,
+		endpoint:    strings.TrimRight(endpoint, "/"),
+		credentials: credentials,
+		httpClient:  httpClient,
+		now:         time.Now,
+	}
+}
+
+// Role is the role of the author of a message.
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is a message in a conversation with a model.
+type Message struct {
+	Role    Role
+	Content string
+}
+
+// CompletionRequest is a request to create a completion.
+type CompletionRequest struct {
+	ModelID     string
+	Messages    []Message
+	MaxTokens   int
+	Temperature float64
+	TopP        float64
+	TopK        int
+	StopWords   []string
+
+	StreamingFunc func(ctx context.Context, chunk []byte) error
+}
+
+// Completion is a completion returned by a model.
+type Completion struct {
+	Text         string
+	StopReason   string
+	InputTokens  int
+	OutputTokens int
+}
+
+// provider encodes requests to and decodes responses from a family of models.
+type provider interface {
+	encodeRequest(r *CompletionRequest) ([]byte, error)
+	decodeResponse(body []byte) (*Completion, error)
+	// decodeChunk decodes a chunk of a streamed response. The text of the
+	// returned completion only contains the text of the chunk.
+	decodeChunk(chunk []byte) (*Completion, error)
+}
+
+// getProvider returns the provider of the model family the model id belongs to.
+// Model ids are in the form "<provider>.<model>", e.g. "anthropic.claude-v2".
+func getProvider(modelID string) (provider, error) { //nolint:ireturn
+	name, _, _ := strings.Cut(modelID, ".")
+	switch name {
+	case "anthropic":
+		return anthropicProvider{}, nil
+	case "amazon":
+		return amazonProvider{}, nil
+	case "meta":
+		return metaProvider{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProvider, modelID)
+	}
+}
+
+// CreateCompletion creates a completion. If a streaming function is set, the
+// response is streamed and the streaming function is called with each chunk.
+func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*Completion, error) {
+	p, err := getProvider(r.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.encodeRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.StreamingFunc != nil {
+		return c.createStreamedCompletion(ctx, p, r, body)
+	}
+
+	resp, err := c.invoke(ctx, r.ModelID, "invoke", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return p.decodeResponse(respBody)
+}
+
+func (c *Client) createStreamedCompletion(
+	ctx context.Context,
+	p provider,
+	r *CompletionRequest,
+	body []byte,
+) (*Completion, error) {
+	resp, err := c.invoke(ctx, r.ModelID, "invoke-with-response-stream", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &Completion{}
+	var sb strings.Builder
+	dec := newEventStreamDecoder(resp.Body)
+	for {
+		payload, err := dec.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if payload == nil {
+			continue
+		}
+		partial, err := p.decodeChunk(payload)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.StreamingFunc(ctx, []byte(partial.Text)); err != nil {
+			return nil, err
+		}
+		sb.WriteString(partial.Text)
+		mergeCompletionInfo(result, partial)
+	}
+
+	result.Text = sb.String()
+	return result, nil
+}
+
+type errorMessage struct {
+	Message string `json:"message"`
+}
+
+// invoke sends a signed request with the body to the action of the model and
+// returns the response if the status code is 200.
+func (c *Client) invoke(ctx context.Context, modelID, action string, body []byte) (*http.Response, error) {
+	path := "/model/" + escapePath(modelID) + "/" + action
+	u, err := url.Parse(c.endpoint + path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", _contentTypeJSON)
+	req.Header.Set("Accept", _contentTypeJSON)
+	credentials, err := c.credentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving credentials: %w", err)
+	}
+	newSigner(credentials, c.region, _service).sign(req, body, c.now())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg := fmt.Sprintf("API returned unexpected status code: %d", resp.StatusCode)
+
+		var errResp errorMessage
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Message == "" {
+			return nil, errors.New(msg) // nolint:goerr113
+		}
+		return nil, fmt.Errorf("%s: %s", msg, errResp.Message) // nolint:goerr113
+	}
+	return resp, nil
+}
+
+func mergeCompletionInfo(dst, src *Completion) {
+	if src.StopReason != "" {
+		dst.StopReason = src.StopReason
+	}
+	if src.InputTokens != 0 {
+		dst.InputTokens = src.InputTokens
+	}
+	if src.OutputTokens != 0 {
+		dst.OutputTokens = src.OutputTokens
+	}
+}
@@ -0,0 +1,187 @@
+package bedrockclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer responds to every request with the response, and records the
+// last request.
+type fakeServer struct {
+	response []byte
+	// record the request that was sent to the server
+	recordedPath string
+	recordedAuth string
+	recordedBody []byte
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.recordedPath = r.URL.EscapedPath()
+	f.recordedAuth = r.Header.Get("Authorization")
+	f.recordedBody, _ = io.ReadAll(r.Body)
+	_, _ = w.Write(f.response)
+}
+
+func newTestClient(t *testing.T, f *fakeServer) *Client {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	credentials := StaticCredentials(Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	return New("us-east-1", srv.URL, credentials, srv.Client())
+}
+
+func TestCreateCompletion(t *testing.T) {
+	t.Parallel()
+
+	messages := []Message{
+		{Role: RoleSystem, Content: "Be brief."},
+		{Role: RoleUser, Content: "Hi"},
+		{Role: RoleAssistant, Content: "Hello"},
+		{Role: RoleUser, Content: "Bye"},
+	}
+
+	tests := []struct {
+		modelID        string
+		response       string
+		expectedPrompt string
+		expected       *Completion
+	}{
+		{
+			modelID:        "anthropic.claude-v2",
+			response:       `{"completion":" Goodbye","stop_reason":"stop_sequence"}`,
+			expectedPrompt: "Be brief.\n\nHuman: Hi\n\nAssistant: Hello\n\nHuman: Bye\n\nAssistant:",
+			expected:       &Completion{Text: " Goodbye", StopReason: "stop_sequence"},
+		},
+		{
+			modelID:        "amazon.titan-text-express-v1",
+			response:       `{"inputTextTokenCount":9,"results":[{"tokenCount":2,"outputText":"Goodbye","completionReason":"FINISH"}]}`, //nolint:lll
+			expectedPrompt: "Be brief.\nUser: Hi\nBot: Hello\nUser: Bye\nBot:",
+			expected:       &Completion{Text: "Goodbye", StopReason: "FINISH", InputTokens: 9, OutputTokens: 2},
+		},
+		{
+			modelID:        "meta.llama2-13b-chat-v1",
+			response:       `{"generation":"Goodbye","prompt_token_count":9,"generation_token_count":2,"stop_reason":"stop"}`,
+			expectedPrompt: "<s>[INST] <<SYS>>\nBe brief.\n<</SYS>>\n\nHi [/INST] Hello </s><s>[INST] Bye [/INST]",
+			expected:       &Completion{Text: "Goodbye", StopReason: "stop", InputTokens: 9, OutputTokens: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.modelID, func(t *testing.T) {
+			t.Parallel()
+
+			server := &fakeServer{response: []byte(tc.response)}
+			result, err := newTestClient(t, server).CreateCompletion(context.Background(), &CompletionRequest{
+				ModelID:  tc.modelID,
+				Messages: messages,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+
+			var body map[string]any
+			assert.Equal(t, "/model/"+tc.modelID+"/invoke", server.recordedPath)
+			assert.True(t, strings.HasPrefix(server.recordedAuth, "AWS4-HMAC-SHA256 Credential=AKID/"))
+			require.NoError(t, json.Unmarshal(server.recordedBody, &body))
+			prompt, ok := body["prompt"]
+			if !ok {
+				prompt = body["inputText"]
+			}
+			assert.Equal(t, tc.expectedPrompt, prompt)
+		})
+	}
+}
+
+func TestCreateCompletionUnsupportedProvider(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTestClient(t, &fakeServer{}).CreateCompletion(context.Background(), &CompletionRequest{
+		ModelID: "unknown.model",
+	})
+	assert.ErrorIs(t, err, ErrUnsupportedProvider)
+}
+
+func TestCreateEmbedding(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeServer{response: []byte(`{"embedding":[0.1,0.2],"inputTextTokenCount":2}`)}
+	embeddings, err := newTestClient(t, server).CreateEmbedding(context.Background(),
+		"amazon.titan-embed-text-v2:0", []string{"hello"}, EmbeddingOptions{InputType: "search_query", Dimensions: 256})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.1, 0.2}}, embeddings)
+	assert.Equal(t, "/model/amazon.titan-embed-text-v2%3A0/invoke", server.recordedPath)
+	assert.JSONEq(t, `{"inputText":"hello","dimensions":256}`, string(server.recordedBody))
+
+	server = &fakeServer{response: []byte(`{"embeddings":[[0.1],[0.2]]}`)}
+	embeddings, err = newTestClient(t, server).CreateEmbedding(context.Background(),
+		"cohere.embed-english-v3", []string{"hello", "world"}, EmbeddingOptions{InputType: "search_query", Dimensions: 256})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.1}, {0.2}}, embeddings)
+	assert.JSONEq(t, `{"texts":["hello","world"],"input_type":"search_query"}`, string(server.recordedBody))
+
+	_, err = newTestClient(t, &fakeServer{}).CreateEmbedding(context.Background(),
+		"unknown.model", []string{"hello"}, EmbeddingOptions{})
+	assert.ErrorIs(t, err, ErrUnsupportedProvider)
+}
+
+// encodeEvent encodes a chunk event in the application/vnd.amazon.eventstream
+// format.
+func encodeEvent(t *testing.T, chunk string) []byte {
+	t.Helper()
+
+	var headers bytes.Buffer
+	for _, h := range [][2]string{{":message-type", "event"}, {":event-type", "chunk"}} {
+		headers.WriteByte(byte(len(h[0])))
+		headers.WriteString(h[0])
+		headers.WriteByte(7)
+		require.NoError(t, binary.Write(&headers, binary.BigEndian, uint16(len(h[1]))))
+		headers.WriteString(h[1])
+	}
+	payload := fmt.Sprintf(`{"bytes":%q}`, base64.StdEncoding.EncodeToString([]byte(chunk)))
+
+	var msg bytes.Buffer
+	total := uint32(12 + headers.Len() + len(payload) + 4)
+	require.NoError(t, binary.Write(&msg, binary.BigEndian, total))
+	require.NoError(t, binary.Write(&msg, binary.BigEndian, uint32(headers.Len())))
+	require.NoError(t, binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes())))
+	msg.Write(headers.Bytes())
+	msg.WriteString(payload)
+	require.NoError(t, binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes())))
+	return msg.Bytes()
+}
+
+func TestCreateCompletionStreaming(t *testing.T) {
+	t.Parallel()
+
+	var stream []byte
+	stream = append(stream, encodeEvent(t, `{"completion":" Good"}`)...)
+	stream = append(stream, encodeEvent(t, `{"completion":"bye","stop_reason":"stop_sequence"}`)...)
+	server := &fakeServer{response: stream}
+
+	var chunks []string
+	result, err := newTestClient(t, server).CreateCompletion(context.Background(), &CompletionRequest{
+		ModelID:  "anthropic.claude-v2:1",
+		Messages: []Message{{Role: RoleUser, Content: "Bye"}},
+		StreamingFunc: func(_ context.Context, chunk []byte) error {
+			chunks = append(chunks, string(chunk))
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/model/anthropic.claude-v2%3A1/invoke-with-response-stream", server.recordedPath)
+	assert.Equal(t, []string{" Good", "bye"}, chunks)
+	assert.Equal(t, &Completion{Text: " Goodbye", StopReason: "stop_sequence"}, result)
+}
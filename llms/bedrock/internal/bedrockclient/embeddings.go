@@ -0,0 +1,111 @@
+package bedrockclient
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// _cohereEmbeddingBatchSize is the maximum number of texts of a request to a
+// Cohere embedding model.
+const _cohereEmbeddingBatchSize = 96
+
+// EmbeddingOptions are the options of the embeddings created by
+// CreateEmbedding.
+type EmbeddingOptions struct {
+	// InputType is the type of the texts embedded by Cohere models, such as
+	// "search_document" or "search_query". It is ignored by Titan models.
+	InputType string
+	// Dimensions is the number of dimensions of the embeddings of the Titan
+	// models supporting it, such as amazon.titan-embed-text-v2:0. It is
+	// ignored by Cohere models.
+	Dimensions int
+}
+
+type titanEmbeddingRequest struct {
+	InputText  string `json:"inputText"`
+	Dimensions int    `json:"dimensions,omitempty"`
+}
+
+type titanEmbeddingResponse struct {
+	Embedding           []float64 `json:"embedding"`
+	InputTextTokenCount int       `json:"inputTextTokenCount"`
+}
+
+type cohereEmbeddingRequest struct {
+	Texts     []string `json:"texts"`
+	InputType string   `json:"input_type,omitempty"`
+}
+
+type cohereEmbeddingResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+// CreateEmbedding creates an embedding for each of the texts using a Titan or
+// Cohere embedding model. The Titan models embed one text per request, the
+// Cohere models up to 96.
+func (c *Client) CreateEmbedding(ctx context.Context, modelID string, texts []string, opts EmbeddingOptions) ([][]float64, error) { //nolint:lll
+	switch {
+	case strings.HasPrefix(modelID, "cohere."):
+		return c.createCohereEmbedding(ctx, modelID, texts, opts)
+	case strings.HasPrefix(modelID, "amazon."):
+		return c.createTitanEmbedding(ctx, modelID, texts, opts)
+	}
+	return nil, ErrUnsupportedProvider
+}
+
+func (c *Client) createTitanEmbedding(ctx context.Context, modelID string, texts []string, opts EmbeddingOptions) ([][]float64, error) { //nolint:lll
+	embeddings := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		body, err := json.Marshal(titanEmbeddingRequest{InputText: text, Dimensions: opts.Dimensions})
+		if err != nil {
+			return nil, err
+		}
+
+		var resp titanEmbeddingResponse
+		if err := c.createEmbedding(ctx, modelID, body, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Embedding) == 0 {
+			return nil, ErrEmptyResponse
+		}
+		embeddings = append(embeddings, resp.Embedding)
+	}
+
+	return embeddings, nil
+}
+
+func (c *Client) createCohereEmbedding(ctx context.Context, modelID string, texts []string, opts EmbeddingOptions) ([][]float64, error) { //nolint:lll
+	embeddings := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += _cohereEmbeddingBatchSize {
+		end := start + _cohereEmbeddingBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		body, err := json.Marshal(cohereEmbeddingRequest{Texts: texts[start:end], InputType: opts.InputType})
+		if err != nil {
+			return nil, err
+		}
+
+		var resp cohereEmbeddingResponse
+		if err := c.createEmbedding(ctx, modelID, body, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) == 0 {
+			return nil, ErrEmptyResponse
+		}
+		embeddings = append(embeddings, resp.Embeddings...)
+	}
+
+	return embeddings, nil
+}
+
+func (c *Client) createEmbedding(ctx context.Context, modelID string, body []byte, resp any) error {
+	r, err := c.invoke(ctx, modelID, "invoke", body)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	return json.NewDecoder(r.Body).Decode(resp)
+}
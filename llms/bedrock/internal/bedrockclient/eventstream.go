@@ -0,0 +1,137 @@
+package bedrockclient
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// ErrInvalidEventStream is returned when a streamed response can not be decoded.
+var ErrInvalidEventStream = errors.New("invalid event stream")
+
+const (
+	// The prelude holds the total length, the headers length and a checksum.
+	_preludeLength = 12
+	// The message ends with a checksum of the whole message.
+	_messageCRCLength = 4
+	// Messages are at most 16MB.
+	_maxMessageLength = 16 * 1024 * 1024
+)
+
+// eventStreamDecoder decodes the messages of a response in the
+// application/vnd.amazon.eventstream format, as returned by the streaming
+// bedrock api.
+type eventStreamDecoder struct {
+	r *bufio.Reader
+}
+
+func newEventStreamDecoder(r io.Reader) *eventStreamDecoder {
+	return &eventStreamDecoder{r: bufio.NewReader(r)}
+}
+
+type chunkPayload struct {
+	Bytes []byte `json:"bytes"`
+}
+
+// next returns the model output of the next chunk event of the stream, or nil
+// for other events. It returns io.EOF at the end of the stream.
+func (d *eventStreamDecoder) next() ([]byte, error) {
+	headers, payload, err := d.readMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	switch headers[":message-type"] {
+	case "event":
+		if headers[":event-type"] != "chunk" {
+			return nil, nil
+		}
+		var chunk chunkPayload
+		if err := json.Unmarshal(payload, &chunk); err != nil {
+			return nil, err
+		}
+		return chunk.Bytes, nil
+	case "exception", "error":
+		var errResp errorMessage
+		_ = json.Unmarshal(payload, &errResp)
+		return nil, fmt.Errorf("%s: %s", headers[":exception-type"], errResp.Message) // nolint:goerr113
+	default:
+		return nil, nil
+	}
+}
+
+func (d *eventStreamDecoder) readMessage() (map[string]string, []byte, error) {
+	prelude := make([]byte, _preludeLength)
+	if _, err := io.ReadFull(d.r, prelude); err != nil {
+		return nil, nil, err
+	}
+	totalLength := binary.BigEndian.Uint32(prelude[0:4])
+	headersLength := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, nil, fmt.Errorf("%w: prelude checksum mismatch", ErrInvalidEventStream)
+	}
+	if totalLength > _maxMessageLength ||
+		totalLength < _preludeLength+headersLength+_messageCRCLength {
+		return nil, nil, fmt.Errorf("%w: bad message length %d", ErrInvalidEventStream, totalLength)
+	}
+
+	message := make([]byte, totalLength)
+	copy(message, prelude)
+	if _, err := io.ReadFull(d.r, message[_preludeLength:]); err != nil {
+		return nil, nil, err
+	}
+	crcOffset := totalLength - _messageCRCLength
+	if crc32.ChecksumIEEE(message[:crcOffset]) != binary.BigEndian.Uint32(message[crcOffset:]) {
+		return nil, nil, fmt.Errorf("%w: message checksum mismatch", ErrInvalidEventStream)
+	}
+
+	headersEnd := _preludeLength + headersLength
+	headers, err := decodeHeaders(message[_preludeLength:headersEnd])
+	if err != nil {
+		return nil, nil, err
+	}
+	return headers, message[headersEnd:crcOffset], nil
+}
+
+// decodeHeaders decodes the headers of a message. Only the values of string
+// headers are kept, the others are skipped.
+func decodeHeaders(b []byte) (map[string]string, error) {
+	// Size of the value of each header type, -1 for variable length values.
+	sizes := [...]int{0, 0, 1, 2, 4, 8, -1, -1, 8, 16}
+	const stringType = 7
+
+	headers := make(map[string]string)
+	for len(b) > 0 {
+		nameLength := int(b[0])
+		if len(b) < 1+nameLength+1 {
+			return nil, fmt.Errorf("%w: truncated header", ErrInvalidEventStream)
+		}
+		name := string(b[1 : 1+nameLength])
+		typ := int(b[1+nameLength])
+		b = b[2+nameLength:]
+		if typ >= len(sizes) {
+			return nil, fmt.Errorf("%w: unknown header type %d", ErrInvalidEventStream, typ)
+		}
+
+		size := sizes[typ]
+		if size < 0 {
+			if len(b) < 2 {
+				return nil, fmt.Errorf("%w: truncated header", ErrInvalidEventStream)
+			}
+			size = int(binary.BigEndian.Uint16(b[:2]))
+			b = b[2:]
+		}
+		if len(b) < size {
+			return nil, fmt.Errorf("%w: truncated header", ErrInvalidEventStream)
+		}
+		if typ == stringType {
+			headers[name] = string(b[:size])
+		}
+		b = b[size:]
+	}
+	return headers, nil
+}
@@ -0,0 +1,102 @@
+package bedrockclient
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// amazonProvider handles the Titan text models.
+type amazonProvider struct{}
+
+type amazonTextGenerationConfig struct {
+	MaxTokenCount int      `json:"maxTokenCount,omitempty"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+	Temperature   float64  `json:"temperature,omitempty"`
+	TopP          float64  `json:"topP,omitempty"`
+}
+
+type amazonRequest struct {
+	InputText            string                     `json:"inputText"`
+	TextGenerationConfig amazonTextGenerationConfig `json:"textGenerationConfig"`
+}
+
+type amazonResult struct {
+	TokenCount       int    `json:"tokenCount"`
+	OutputText       string `json:"outputText"`
+	CompletionReason string `json:"completionReason"`
+}
+
+type amazonResponse struct {
+	InputTextTokenCount int            `json:"inputTextTokenCount"`
+	Results             []amazonResult `json:"results"`
+}
+
+type amazonChunk struct {
+	OutputText                string `json:"outputText"`
+	TotalOutputTextTokenCount int    `json:"totalOutputTextTokenCount"`
+	CompletionReason          string `json:"completionReason"`
+	InputTextTokenCount       int    `json:"inputTextTokenCount"`
+}
+
+func (amazonProvider) encodeRequest(r *CompletionRequest) ([]byte, error) {
+	return json.Marshal(amazonRequest{
+		InputText: amazonPrompt(r.Messages),
+		TextGenerationConfig: amazonTextGenerationConfig{
+			MaxTokenCount: r.MaxTokens,
+			StopSequences: r.StopWords,
+			Temperature:   r.Temperature,
+			TopP:          r.TopP,
+		},
+	})
+}
+
+func (amazonProvider) decodeResponse(body []byte) (*Completion, error) {
+	var resp amazonResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return &Completion{
+		Text:         resp.Results[0].OutputText,
+		StopReason:   resp.Results[0].CompletionReason,
+		InputTokens:  resp.InputTextTokenCount,
+		OutputTokens: resp.Results[0].TokenCount,
+	}, nil
+}
+
+func (amazonProvider) decodeChunk(chunk []byte) (*Completion, error) {
+	var resp amazonChunk
+	if err := json.Unmarshal(chunk, &resp); err != nil {
+		return nil, err
+	}
+	return &Completion{
+		Text:         resp.OutputText,
+		StopReason:   resp.CompletionReason,
+		InputTokens:  resp.InputTextTokenCount,
+		OutputTokens: resp.TotalOutputTextTokenCount,
+	}, nil
+}
+
+// amazonPrompt formats the messages as a "User:"/"Bot:" transcript, which is
+// the conversational format recommended for the Titan models.
+func amazonPrompt(messages []Message) string {
+	if len(messages) == 1 && messages[0].Role == RoleUser {
+		return messages[0].Content
+	}
+
+	lines := make([]string, 0, len(messages)+1)
+	for _, m := range messages {
+		switch m.Role {
+		case RoleSystem:
+			lines = append(lines, m.Content)
+		case RoleAssistant:
+			lines = append(lines, "Bot: "+m.Content)
+		case RoleUser:
+			lines = append(lines, "User: "+m.Content)
+		}
+	}
+	lines = append(lines, "Bot:")
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,75 @@
+package bedrockclient
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	_anthropicDefaultMaxTokens = 256
+	_anthropicHumanPrefix      = "\n\nHuman: "
+	_anthropicAssistantPrefix  = "\n\nAssistant:"
+)
+
+// anthropicProvider handles the Claude text completion models.
+type anthropicProvider struct{}
+
+type anthropicRequest struct {
+	Prompt      string   `json:"prompt"`
+	MaxTokens   int      `json:"max_tokens_to_sample"`
+	Temperature float64  `json:"temperature,omitempty"`
+	TopP        float64  `json:"top_p,omitempty"`
+	TopK        int      `json:"top_k,omitempty"`
+	StopWords   []string `json:"stop_sequences,omitempty"`
+}
+
+type anthropicResponse struct {
+	Completion string `json:"completion"`
+	StopReason string `json:"stop_reason"`
+}
+
+func (anthropicProvider) encodeRequest(r *CompletionRequest) ([]byte, error) {
+	maxTokens := r.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = _anthropicDefaultMaxTokens
+	}
+
+	return json.Marshal(anthropicRequest{
+		Prompt:      anthropicPrompt(r.Messages),
+		MaxTokens:   maxTokens,
+		Temperature: r.Temperature,
+		TopP:        r.TopP,
+		TopK:        r.TopK,
+		StopWords:   r.StopWords,
+	})
+}
+
+func (anthropicProvider) decodeResponse(body []byte) (*Completion, error) {
+	var resp anthropicResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return &Completion{Text: resp.Completion, StopReason: resp.StopReason}, nil
+}
+
+func (p anthropicProvider) decodeChunk(chunk []byte) (*Completion, error) {
+	return p.decodeResponse(chunk)
+}
+
+// anthropicPrompt formats the messages with the alternating human and assistant
+// turns the Claude models expect. A system message is placed before the first turn.
+func anthropicPrompt(messages []Message) string {
+	var sb strings.Builder
+	for _, m := range messages {
+		switch m.Role {
+		case RoleSystem:
+			sb.WriteString(m.Content)
+		case RoleAssistant:
+			sb.WriteString(_anthropicAssistantPrefix + " " + m.Content)
+		case RoleUser:
+			sb.WriteString(_anthropicHumanPrefix + m.Content)
+		}
+	}
+	sb.WriteString(_anthropicAssistantPrefix)
+	return sb.String()
+}
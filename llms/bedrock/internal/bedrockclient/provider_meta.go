@@ -0,0 +1,73 @@
+package bedrockclient
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// metaProvider handles the Llama 2 chat models.
+type metaProvider struct{}
+
+type metaRequest struct {
+	Prompt      string  `json:"prompt"`
+	MaxGenLen   int     `json:"max_gen_len,omitempty"`
+	Temperature float64 `json:"temperature,omitempty"`
+	TopP        float64 `json:"top_p,omitempty"`
+}
+
+type metaResponse struct {
+	Generation           string `json:"generation"`
+	PromptTokenCount     int    `json:"prompt_token_count"`
+	GenerationTokenCount int    `json:"generation_token_count"`
+	StopReason           string `json:"stop_reason"`
+}
+
+func (metaProvider) encodeRequest(r *CompletionRequest) ([]byte, error) {
+	return json.Marshal(metaRequest{
+		Prompt:      metaPrompt(r.Messages),
+		MaxGenLen:   r.MaxTokens,
+		Temperature: r.Temperature,
+		TopP:        r.TopP,
+	})
+}
+
+func (metaProvider) decodeResponse(body []byte) (*Completion, error) {
+	var resp metaResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return &Completion{
+		Text:         resp.Generation,
+		StopReason:   resp.StopReason,
+		InputTokens:  resp.PromptTokenCount,
+		OutputTokens: resp.GenerationTokenCount,
+	}, nil
+}
+
+func (p metaProvider) decodeChunk(chunk []byte) (*Completion, error) {
+	return p.decodeResponse(chunk)
+}
+
+// metaPrompt formats the messages with the [INST] instruction tags used by the
+// Llama 2 chat models. A system message is wrapped in <<SYS>> tags inside the
+// first instruction.
+func metaPrompt(messages []Message) string {
+	var sb strings.Builder
+	var system string
+	for _, m := range messages {
+		switch m.Role {
+		case RoleSystem:
+			system = "<<SYS>>\n" + m.Content + "\n<</SYS>>\n\n"
+		case RoleUser:
+			sb.WriteString("<s>[INST] " + system + m.Content + " [/INST]")
+			system = ""
+		case RoleAssistant:
+			sb.WriteString(" " + m.Content + " </s>")
+		}
+	}
+	// A system message that is not followed by a user message gets its own instruction.
+	if system != "" {
+		sb.WriteString("<s>[INST] " + system + "[/INST]")
+	}
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package bedrockclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSign checks the signer against requests of the AWS Signature Version 4
+// test suite.
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	credentials := Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		method   string
+		expected string
+	}{
+		{
+			name:   "get-vanilla",
+			method: http.MethodGet,
+			expected: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:   "post-vanilla",
+			method: http.MethodPost,
+			expected: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest(tc.method, "https://example.amazonaws.com/", nil) //nolint:noctx
+			require.NoError(t, err)
+			newSigner(credentials, "us-east-1", "service").sign(req, nil, now)
+			assert.Equal(t, tc.expected, req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func TestCanonicalURI(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "/", canonicalURI(""))
+	assert.Equal(t, "/model/anthropic.claude-v2%253A1/invoke",
+		canonicalURI("/model/anthropic.claude-v2%3A1/invoke"))
+}
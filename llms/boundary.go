@@ -0,0 +1,133 @@
+package llms
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Boundary is where generations cut short by MaxTokens are trimmed, see
+// WithTrimToBoundary.
+type Boundary string
+
+const (
+	// BoundarySentence trims generations to their last complete sentence.
+	BoundarySentence Boundary = "sentence"
+	// BoundaryParagraph trims generations to their last complete paragraph, or
+	// to their last complete sentence if it would drop most of the text.
+	BoundaryParagraph Boundary = "paragraph"
+)
+
+const (
+	// _boundaryExtraTokensShare is the share of MaxTokens requested on top of
+	// it, so that the sentence cut by MaxTokens can be finished.
+	_boundaryExtraTokensShare = 0.1
+	// _boundaryMinExtraTokens is the least number of tokens requested on top
+	// of MaxTokens.
+	_boundaryMinExtraTokens = 16
+)
+
+// BoundaryTrim trims the generations of a call made with WithTrimToBoundary.
+type BoundaryTrim struct {
+	boundary  Boundary
+	maxTokens int
+}
+
+// ApplyTrimToBoundary applies the boundary set with WithTrimToBoundary to a
+// call. It raises MaxTokens slightly, so that the generation can go on past
+// MaxTokens to the end of its sentence. Providers call it at the start of
+// Generate and call Trim on each generation. The returned BoundaryTrim is nil
+// if no boundary is set.
+func ApplyTrimToBoundary(opts *CallOptions) *BoundaryTrim {
+	if opts.TrimBoundary == "" {
+		return nil
+	}
+
+	t := &BoundaryTrim{boundary: opts.TrimBoundary, maxTokens: opts.MaxTokens}
+	if opts.MaxTokens > 0 {
+		extra := int(float64(opts.MaxTokens) * _boundaryExtraTokensShare)
+		if extra < _boundaryMinExtraTokens {
+			extra = _boundaryMinExtraTokens
+		}
+		opts.MaxTokens += extra
+	}
+	return t
+}
+
+// Trim trims the generation to its last complete sentence or paragraph if it
+// was cut short, that is if it stopped because of its length or generated more
+// than the MaxTokens of the call. The Truncated field of its metadata, and the
+// "Truncated" key of its generation info, are then set so that callers can
+// offer to continue it. Text already streamed is not trimmed.
+// It is safe to call on a nil BoundaryTrim.
+func (t *BoundaryTrim) Trim(g *Generation) {
+	if t == nil || g == nil {
+		return
+	}
+	metadata := g.ResponseMetadata()
+	lengthStop := isLengthFinishReason(metadata.FinishReason)
+	if !lengthStop && (t.maxTokens == 0 || metadata.Usage.CompletionTokens <= t.maxTokens) {
+		return
+	}
+
+	g.Text = trimToBoundary(g.Text, t.boundary)
+	if g.Message != nil {
+		msg := *g.Message
+		msg.Content = trimToBoundary(msg.Content, t.boundary)
+		g.Message = &msg
+	}
+	if g.Metadata != nil {
+		g.Metadata.Truncated = true
+	}
+	if g.GenerationInfo == nil {
+		g.GenerationInfo = map[string]any{}
+	}
+	g.GenerationInfo["Truncated"] = true
+}
+
+// isLengthFinishReason reports whether the finish reason of a provider means
+// that the generation reached its maximum length.
+func isLengthFinishReason(reason string) bool {
+	switch strings.ToLower(reason) {
+	case "length", "max_tokens", "max_length":
+		return true
+	}
+	return false
+}
+
+// trimToBoundary returns the text up to its last complete paragraph or
+// sentence, or the whole text if it has none.
+func trimToBoundary(text string, boundary Boundary) string {
+	if boundary == BoundaryParagraph {
+		if i := strings.LastIndex(strings.TrimRight(text, " \t\r\n"), "\n\n"); i >= len(text)/2 {
+			return strings.TrimRight(text[:i], " \t\r\n")
+		}
+	}
+	if end := lastSentenceEnd(text); end > 0 {
+		return text[:end]
+	}
+	return text
+}
+
+// lastSentenceEnd returns the index after the last sentence terminator of the
+// text followed by a space, closing quotes or brackets, or the end of the text,
+// or 0 if there is none.
+func lastSentenceEnd(text string) int {
+	runes := []rune(text)
+	end, offset := 0, 0
+	for i, r := range runes {
+		offset += len(string(r))
+		if !strings.ContainsRune(".!?。！？", r) {
+			continue
+		}
+		j := i + 1
+		n := offset
+		for j < len(runes) && strings.ContainsRune(`"')]”’»`, runes[j]) {
+			n += len(string(runes[j]))
+			j++
+		}
+		if j == len(runes) || unicode.IsSpace(runes[j]) || strings.ContainsRune("。！？", r) {
+			end = n
+		}
+	}
+	return end
+}
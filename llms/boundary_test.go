@@ -0,0 +1,80 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestApplyTrimToBoundary(t *testing.T) {
+	t.Parallel()
+
+	opts := CallOptions{MaxTokens: 100}
+	assert.Nil(t, ApplyTrimToBoundary(&opts))
+	assert.Equal(t, 100, opts.MaxTokens)
+
+	opts = CallOptions{MaxTokens: 100, TrimBoundary: BoundarySentence}
+	trim := ApplyTrimToBoundary(&opts)
+	assert.NotNil(t, trim)
+	assert.Equal(t, 116, opts.MaxTokens)
+
+	opts = CallOptions{MaxTokens: 1000, TrimBoundary: BoundarySentence}
+	ApplyTrimToBoundary(&opts)
+	assert.Equal(t, 1100, opts.MaxTokens)
+
+	var nilTrim *BoundaryTrim
+	nilTrim.Trim(&Generation{Text: "Hi. Th"})
+}
+
+func TestBoundaryTrim(t *testing.T) {
+	t.Parallel()
+
+	trim := ApplyTrimToBoundary(&CallOptions{MaxTokens: 10, TrimBoundary: BoundarySentence})
+
+	text := "It is sunny. Bring a \"hat.\" And some wat"
+	g := &Generation{
+		Text:     text,
+		Message:  &schema.AIChatMessage{Content: text},
+		Metadata: NewResponseMetadata("m", "length", Usage{CompletionTokens: 12}, 0, nil),
+	}
+	g.GenerationInfo = g.Metadata.GenerationInfo()
+	trim.Trim(g)
+	assert.Equal(t, "It is sunny. Bring a \"hat.\"", g.Text)
+	assert.Equal(t, "It is sunny. Bring a \"hat.\"", g.Message.Content)
+	assert.True(t, g.ResponseMetadata().Truncated)
+	assert.Equal(t, true, g.GenerationInfo["Truncated"])
+
+	// Generations within MaxTokens are kept as they are.
+	g = &Generation{Text: "It is sunny. Bring a", GenerationInfo: map[string]any{"CompletionTokens": 8}}
+	trim.Trim(g)
+	assert.Equal(t, "It is sunny. Bring a", g.Text)
+	assert.False(t, g.ResponseMetadata().Truncated)
+
+	// Generations past MaxTokens are trimmed even without a length stop reason.
+	g = &Generation{Text: "Version 1.2 is out! Upgr", GenerationInfo: map[string]any{"CompletionTokens": 11}}
+	trim.Trim(g)
+	assert.Equal(t, "Version 1.2 is out!", g.Text)
+	assert.True(t, g.ResponseMetadata().Truncated)
+
+	trim = ApplyTrimToBoundary(&CallOptions{TrimBoundary: BoundaryParagraph})
+	g = &Generation{
+		Text:           "First paragraph.\n\nSecond one. Still the second paragraph.\n\nThird, cut",
+		GenerationInfo: map[string]any{"StopReason": "max_tokens"},
+	}
+	trim.Trim(g)
+	assert.Equal(t, "First paragraph.\n\nSecond one. Still the second paragraph.", g.Text)
+
+	// Paragraphs dropping most of the text fall back to sentences.
+	g = &Generation{
+		Text:           "Short.\n\nA much longer second paragraph. Its end is cut",
+		GenerationInfo: map[string]any{"StopReason": "MAX_TOKENS"},
+	}
+	trim.Trim(g)
+	assert.Equal(t, "Short.\n\nA much longer second paragraph.", g.Text)
+
+	g = &Generation{Text: "No sentence ends here", GenerationInfo: map[string]any{"StopReason": "length"}}
+	trim.Trim(g)
+	assert.Equal(t, "No sentence ends here", g.Text)
+	assert.True(t, g.ResponseMetadata().Truncated)
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const _defaultMaxEntries = 1000
+
+// Backend stores the cached generations.
+type Backend interface {
+	// Get returns the value stored for the key, and false if there is none.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value for the key, expiring after the ttl if it is not
+	// zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// InMemory is a Backend keeping the values in memory, evicting the least
+// recently used ones past its maximum number of entries.
+type InMemory struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu sync.Mutex
+	// lru has the entries, the most recently used first.
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type inMemoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+var _ Backend = (*InMemory)(nil)
+
+// NewInMemory creates a new empty InMemory backend keeping up to maxEntries
+// values, or 1000 if maxEntries is not positive.
+func NewInMemory(maxEntries int) *InMemory {
+	if maxEntries <= 0 {
+		maxEntries = _defaultMaxEntries
+	}
+	return &InMemory{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored for the key, unless it expired.
+func (c *InMemory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := elem.Value.(*inMemoryEntry) //nolint:forcetypeassert
+	if !e.expires.IsZero() && !c.now().Before(e.expires) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	c.lru.MoveToFront(elem)
+	return e.value, true, nil
+}
+
+// Set stores the value for the key, evicting the least recently used value
+// if the backend is full.
+func (c *InMemory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &inMemoryEntry{key: key, value: value}
+	if ttl > 0 {
+		e.expires = c.now().Add(ttl)
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = e
+		c.lru.MoveToFront(elem)
+		return nil
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*inMemoryEntry).key) //nolint:forcetypeassert
+	}
+	return nil
+}
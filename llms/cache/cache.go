@@ -0,0 +1,304 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrNoGeneration is returned by LLM.Call when no text was generated.
+var ErrNoGeneration = errors.New("no generation")
+
+// LLM is an LLM caching the generations of the LLM it wraps.
+type LLM struct {
+	LLM   llms.LLM
+	cache *cache
+}
+
+// Chat is a chat model caching the generations of the chat model it wraps.
+type Chat struct {
+	Chat  llms.ChatLLM
+	cache *cache
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+)
+
+// NewLLM creates an LLM caching the generations of the llm in the backend.
+func NewLLM(llm llms.LLM, backend Backend, opts ...Option) *LLM {
+	return &LLM{LLM: llm, cache: newCache(backend, opts)}
+}
+
+// NewChat creates a chat model caching the generations of the chat model in
+// the backend.
+func NewChat(chat llms.ChatLLM, backend Backend, opts ...Option) *Chat {
+	return &Chat{Chat: chat, cache: newCache(backend, opts)}
+}
+
+// Call generates text for the prompt, from the cache if possible.
+func (l *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	generations, err := l.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(generations) == 0 || generations[0] == nil {
+		return "", ErrNoGeneration
+	}
+	return generations[0].Text, nil
+}
+
+// Generate generates text for the prompts, from the cache if possible.
+func (l *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	var text string
+	if len(prompts) == 1 {
+		text = prompts[0]
+	}
+	return l.cache.generate(ctx, prompts, text, options, func() ([]*llms.Generation, error) {
+		return l.LLM.Generate(ctx, prompts, options...)
+	})
+}
+
+// GeneratePrompt generates text for the prompt values, from the cache if
+// possible.
+func (l *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GeneratePrompt(ctx, l, promptValues, options...)
+}
+
+// GetNumTokens returns the number of tokens of the text for the wrapped model,
+// if it is a language model, or for the model name of the cache.
+func (l *LLM) GetNumTokens(text string) int {
+	if lm, ok := l.LLM.(llms.LanguageModel); ok {
+		return lm.GetNumTokens(text)
+	}
+	return llms.CountTokens(l.cache.opts.model, text)
+}
+
+// Call generates a message for the messages, from the cache if possible.
+func (c *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := c.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(generations) == 0 || generations[0] == nil || generations[0].Message == nil {
+		return nil, llms.ErrNoMessage
+	}
+	return generations[0].Message, nil
+}
+
+// Generate generates messages for the sets of messages, from the cache if
+// possible.
+func (c *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	return c.cache.generateChat(ctx, c.Chat, messageSets, options)
+}
+
+// GeneratePrompt generates messages for the chat prompt values, from the
+// cache if possible.
+func (c *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, c, promptValues, options...)
+}
+
+// GetNumTokens returns the number of tokens of the text for the wrapped model,
+// if it is a language model, or for the model name of the cache.
+func (c *Chat) GetNumTokens(text string) int {
+	if lm, ok := c.Chat.(llms.LanguageModel); ok {
+		return lm.GetNumTokens(text)
+	}
+	return llms.CountTokens(c.cache.opts.model, text)
+}
+
+// Middleware returns a middleware caching the generations of the chat models
+// it wraps in the backend, see llms.WrapChat.
+func Middleware(backend Backend, opts ...Option) llms.Middleware {
+	c := newCache(backend, opts)
+	return func(next llms.Generator) llms.Generator {
+		return llms.GeneratorFunc(func(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+			return c.generateChat(ctx, next, messageSets, options)
+		})
+	}
+}
+
+// cache looks up and stores the generations of calls in a backend, and the
+// embeddings of their prompts for semantic caching.
+type cache struct {
+	backend Backend
+	opts    options
+
+	mu sync.Mutex
+	// semantic are the embeddings of the prompts of the cached calls, the
+	// oldest first.
+	semantic []semanticEntry
+}
+
+type semanticEntry struct {
+	// scope is the hash of the model and options of the call, as only calls
+	// with the same ones can share generations.
+	scope  [sha256.Size]byte
+	vector []float64
+	key    string
+}
+
+func newCache(backend Backend, opts []Option) *cache {
+	o := options{maxSemanticEntries: _defaultMaxEntries}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &cache{backend: backend, opts: o}
+}
+
+// generateChat returns the cached generations of the sets of messages, or
+// those of the generator which are then cached.
+func (c *cache) generateChat(ctx context.Context, g llms.Generator, messageSets [][]schema.ChatMessage, options []llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	type message struct {
+		Type    schema.ChatMessageType
+		Message schema.ChatMessage
+	}
+	sets := make([][]message, len(messageSets))
+	for i, messages := range messageSets {
+		for _, m := range messages {
+			sets[i] = append(sets[i], message{Type: m.GetType(), Message: m})
+		}
+	}
+	var text string
+	if len(messageSets) == 1 {
+		text, _ = schema.GetBufferString(messageSets[0], "Human", "AI")
+	}
+	return c.generate(ctx, sets, text, options, func() ([]*llms.Generation, error) {
+		return g.Generate(ctx, messageSets, options...)
+	})
+}
+
+// generate returns the cached generations of the call of the input with the
+// options, or those of a call with a prompt similar to text if text is not
+// empty and semantic caching is enabled. Otherwise, it calls call and caches
+// its generations. Calls with a streaming function, a transport hook or a
+// prompt shrinker are never cached, as their functions can not be compared.
+// Errors of the backend and of the embedder make calls go to the model.
+func (c *cache) generate(ctx context.Context, input any, text string, options []llms.CallOption, call func() ([]*llms.Generation, error)) ([]*llms.Generation, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.StreamingFunc != nil || opts.TransportHook != nil || opts.PromptShrinker != nil {
+		return call()
+	}
+	if opts.Model == "" {
+		opts.Model = c.opts.model
+	}
+
+	scope, key, ok := cacheKey(opts, input)
+	if !ok {
+		return call()
+	}
+	if generations, ok := c.get(ctx, key); ok {
+		return generations, nil
+	}
+
+	var vector []float64
+	if c.opts.embedder != nil && text != "" {
+		vector, _ = c.opts.embedder.EmbedQuery(ctx, text)
+		if similar := c.similar(scope, vector); similar != "" {
+			if generations, ok := c.get(ctx, similar); ok {
+				return generations, nil
+			}
+		}
+	}
+
+	generations, err := call()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(generations)
+	if err != nil || c.backend.Set(ctx, key, data, c.opts.ttl) != nil {
+		return generations, nil //nolint:nilerr
+	}
+	if vector != nil {
+		c.addSemantic(semanticEntry{scope: scope, vector: vector, key: key})
+	}
+	return generations, nil
+}
+
+// get returns the generations cached for the key, if any.
+func (c *cache) get(ctx context.Context, key string) ([]*llms.Generation, bool) {
+	data, ok, err := c.backend.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false
+	}
+	var generations []*llms.Generation
+	if err := json.Unmarshal(data, &generations); err != nil {
+		return nil, false
+	}
+	return generations, true
+}
+
+// similar returns the key of the call of the scope with the most similar
+// prompt embedding, if similar enough.
+func (c *cache) similar(scope [sha256.Size]byte, vector []float64) string {
+	if vector == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, best := "", c.opts.threshold
+	for _, e := range c.semantic {
+		if e.scope != scope {
+			continue
+		}
+		if score := cosine(vector, e.vector); score >= best {
+			key, best = e.key, score
+		}
+	}
+	return key
+}
+
+func (c *cache) addSemantic(e semanticEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.semantic = append(c.semantic, e)
+	if n := len(c.semantic) - c.opts.maxSemanticEntries; n > 0 {
+		c.semantic = append(c.semantic[:0], c.semantic[n:]...)
+	}
+}
+
+// cacheKey returns the hash of the model and options of a call, and its cache
+// key, the hex encoded hash of both and of its input. It returns false if the
+// options or input can not be encoded.
+func cacheKey(opts llms.CallOptions, input any) ([sha256.Size]byte, string, bool) {
+	options, err := json.Marshal(opts)
+	if err != nil {
+		return [sha256.Size]byte{}, "", false
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return [sha256.Size]byte{}, "", false
+	}
+	scope := sha256.Sum256(options)
+	h := sha256.New()
+	h.Write(scope[:])
+	h.Write(data)
+	return scope, hex.EncodeToString(h.Sum(nil)), true
+}
+
+func cosine(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
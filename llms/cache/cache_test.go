@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// countingLLM answers with the number of calls made to it.
+type countingLLM struct {
+	calls int
+}
+
+func (l *countingLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	generations, err := l.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	return generations[0].Text, nil
+}
+
+func (l *countingLLM) Generate(_ context.Context, prompts []string, _ ...llms.CallOption) ([]*llms.Generation, error) {
+	l.calls++
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for range prompts {
+		text := strings.Repeat("x", l.calls)
+		generations = append(generations, &llms.Generation{Text: text, Message: &schema.AIChatMessage{Content: text}})
+	}
+	return generations, nil
+}
+
+// countingChat is a chat model answering with the number of calls made to it.
+type countingChat struct {
+	countingLLM
+}
+
+func (c *countingChat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := c.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return generations[0].Message, nil
+}
+
+func (c *countingChat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	return c.countingLLM.Generate(ctx, make([]string, len(messageSets)), options...)
+}
+
+// letterEmbedder embeds texts as the number of times they contain "a" and
+// "b".
+type letterEmbedder struct{}
+
+func (e letterEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		v, _ := e.EmbedQuery(ctx, text)
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+func (letterEmbedder) EmbedQuery(_ context.Context, text string) ([]float64, error) {
+	return []float64{float64(strings.Count(text, "a")), float64(strings.Count(text, "b"))}, nil
+}
+
+func TestInMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	c := NewInMemory(2)
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, _ := c.Get(ctx, "a")
+	require.True(t, ok)
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok, _ = c.Get(ctx, "b")
+	assert.False(t, ok, "least recently used value evicted")
+	value, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), time.Minute))
+	now = now.Add(time.Minute)
+	_, ok, _ = c.Get(ctx, "c")
+	assert.False(t, ok, "expired value")
+}
+
+func TestChat(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	model := &countingChat{}
+	chat := NewChat(model, NewInMemory(0), WithModelName("gpt-4"))
+	hello := []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}}
+
+	msg, err := chat.Call(ctx, hello)
+	require.NoError(t, err)
+	assert.Equal(t, "x", msg.Content)
+	msg, err = chat.Call(ctx, hello)
+	require.NoError(t, err)
+	assert.Equal(t, "x", msg.Content)
+	assert.Equal(t, 1, model.calls)
+
+	msg, err = chat.Call(ctx, hello, llms.WithTemperature(0.5))
+	require.NoError(t, err)
+	assert.Equal(t, "xx", msg.Content)
+	msg, err = chat.Call(ctx, []schema.ChatMessage{schema.AIChatMessage{Content: "hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, "xxx", msg.Content)
+
+	_, err = chat.Call(ctx, hello, llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+	require.NoError(t, err)
+	assert.Equal(t, 4, model.calls)
+}
+
+func TestLLMSemantic(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	model := &countingLLM{}
+	llm := NewLLM(model, NewInMemory(0), WithSemantic(letterEmbedder{}, 0.99))
+
+	text, err := llm.Call(ctx, "aab")
+	require.NoError(t, err)
+	assert.Equal(t, "x", text)
+	text, err = llm.Call(ctx, "b a a")
+	require.NoError(t, err)
+	assert.Equal(t, "x", text)
+	assert.Equal(t, 1, model.calls)
+
+	text, err = llm.Call(ctx, "abb")
+	require.NoError(t, err)
+	assert.Equal(t, "xx", text)
+	text, err = llm.Call(ctx, "aab", llms.WithModel("other"))
+	require.NoError(t, err)
+	assert.Equal(t, "xxx", text)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	model := &countingChat{}
+	chat := llms.WrapChat(model, Middleware(NewInMemory(0)))
+	hello := []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}}
+	for i := 0; i < 2; i++ {
+		msg, err := chat.Call(ctx, hello)
+		require.NoError(t, err)
+		assert.Equal(t, "x", msg.Content)
+	}
+	assert.Equal(t, 1, model.calls)
+}
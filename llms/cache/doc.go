@@ -0,0 +1,14 @@
+// Package cache caches the generations of LLMs and chat models, to cut the
+// cost and latency of repeated calls in development and in deterministic
+// pipelines.
+//
+// NewLLM and NewChat wrap a model with a cache. Calls with the same model,
+// prompts or messages and options are answered from the cache. With
+// WithSemantic, single prompt calls are also answered with the generations of
+// a previous call whose prompt has a similar embedding. Middleware caches
+// chat models composed with other middlewares with llms.WrapChat.
+//
+// Generations are stored in a Backend: InMemory keeps them in the memory of
+// the process, evicting the least recently used ones, and Redis in a Redis
+// server shared by processes.
+package cache
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+// Option is a function type that can be used to modify the cache.
+type Option func(o *options)
+
+type options struct {
+	ttl                time.Duration
+	model              string
+	embedder           embeddings.Embedder
+	threshold          float64
+	maxSemanticEntries int
+}
+
+// WithTTL is an option for setting how long generations are cached. Defaults
+// to 0, caching them until the backend evicts them.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithModelName is an option for setting the name of the wrapped model, part
+// of the cache keys of the calls not setting a model with llms.WithModel, so
+// that models sharing a backend do not share their generations.
+func WithModelName(model string) Option {
+	return func(o *options) {
+		o.model = model
+	}
+}
+
+// WithSemantic is an option for answering single prompt calls with the
+// generations of a previous call with the same options whose prompt embedding
+// has a cosine similarity of at least the threshold, such as 0.95.
+func WithSemantic(embedder embeddings.Embedder, threshold float64) Option {
+	return func(o *options) {
+		o.embedder = embedder
+		o.threshold = threshold
+	}
+}
+
+// WithSemanticMaxEntries is an option for setting the number of prompt
+// embeddings kept in memory for semantic caching, the oldest ones being
+// evicted first. Defaults to 1000.
+func WithSemanticMaxEntries(maxEntries int) Option {
+	return func(o *options) {
+		o.maxSemanticEntries = maxEntries
+	}
+}
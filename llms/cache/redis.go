@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tmc/langchaingo/internal/redis"
+)
+
+const _defaultRedisKeyPrefix = "langchaingo:llm:"
+
+// ErrRedis is returned when the Redis server replies with an error or a reply
+// that can not be parsed.
+var ErrRedis = redis.ErrRedis
+
+// Redis is a Backend storing the values in a Redis server, so that they are
+// shared by processes and survive restarts. It speaks the Redis protocol over
+// a small pool of connections.
+type Redis struct {
+	addr     string
+	password string
+	db       int
+	prefix   string
+
+	client *redis.Client
+}
+
+var _ Backend = (*Redis)(nil)
+
+// RedisOption is a function type that can be used to modify a Redis backend.
+type RedisOption func(r *Redis)
+
+// WithRedisPassword is an option for setting the password the connections
+// authenticate with.
+func WithRedisPassword(password string) RedisOption {
+	return func(r *Redis) {
+		r.password = password
+	}
+}
+
+// WithRedisDB is an option for setting the database the values are stored in.
+// Defaults to 0.
+func WithRedisDB(db int) RedisOption {
+	return func(r *Redis) {
+		r.db = db
+	}
+}
+
+// WithRedisKeyPrefix is an option for setting the prefix of the keys of the
+// values. Defaults to "langchaingo:llm:".
+func WithRedisKeyPrefix(prefix string) RedisOption {
+	return func(r *Redis) {
+		r.prefix = prefix
+	}
+}
+
+// NewRedis creates a new Redis backend for the server at the address, such as
+// "localhost:6379". Connections are opened when needed.
+func NewRedis(addr string, opts ...RedisOption) *Redis {
+	r := &Redis{addr: addr, prefix: _defaultRedisKeyPrefix}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.client = redis.New(r.addr, r.password, r.db)
+	return r
+}
+
+// Get returns the value stored for the key, if any.
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := r.client.Do(ctx, "GET", r.prefix+key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: unexpected reply to GET", ErrRedis)
+	}
+	return value, true, nil
+}
+
+// Set stores the value for the key, expiring after the ttl if it is not zero.
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", r.prefix + key, string(value)}
+	if ms := ttl.Milliseconds(); ms > 0 {
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+	_, err := r.client.Do(ctx, args...)
+	return err
+}
+
+// Close closes the idle connections.
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
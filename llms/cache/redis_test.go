@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/redis/redistest"
+)
+
+func TestRedis(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	server := redistest.NewServer(t)
+	r := NewRedis(server.Addr, WithRedisPassword("secret"), WithRedisDB(2), WithRedisKeyPrefix("test:"))
+	defer r.Close()
+
+	_, ok, err := r.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, r.Set(ctx, "key", []byte("line 1\r\nline 2"), 1500*time.Millisecond))
+	value, ok, err := r.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "line 1\r\nline 2", string(value))
+
+	_, err = r.client.Do(ctx, "FLUSHALL")
+	require.ErrorIs(t, err, ErrRedis)
+	// The connection is still usable after an error reply.
+	_, ok, err = r.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, [][]string{
+		{"AUTH", "secret"},
+		{"SELECT", "2"},
+		{"GET", "test:key"},
+		{"SET", "test:key", "line 1\r\nline 2", "PX", "1500"},
+		{"GET", "test:key"},
+		{"FLUSHALL"},
+		{"GET", "test:key"},
+	}, server.Commands())
+}
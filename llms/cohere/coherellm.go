@@ -18,7 +18,8 @@ var (
 )
 
 type LLM struct {
-	client *cohereclient.Client
+	client       *cohereclient.Client
+	rateLimiters *llms.RateLimiters
 }
 
 var (
@@ -46,6 +47,10 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 	generations := make([]*llms.Generation, 0, len(prompts))
 
 	for _, prompt := range prompts {
+		// The client has a single model.
+		if err := llms.ApplyRateLimit(ctx, o.rateLimiters, "", opts, prompt); err != nil {
+			return nil, err
+		}
 		result, err := o.client.CreateGeneration(ctx, &cohereclient.GenerationRequest{
 			Prompt: prompt,
 		})
@@ -76,7 +81,8 @@ func (o *LLM) GeneratePrompt(
 func New(opts ...Option) (*LLM, error) {
 	c, err := newClient(opts...)
 	return &LLM{
-		client: c,
+		client:       c,
+		rateLimiters: &llms.RateLimiters{},
 	}, err
 }
 
@@ -60,6 +60,9 @@ func New(token string, baseURL string, model string, opts ...Option) (*Client, e
 			return nil, err
 		}
 	}
+	if c.baseURL == "" {
+		c.baseURL = "https://api.cohere.ai"
+	}
 
 	return c, nil
 }
@@ -87,10 +90,6 @@ type generateResponsePayload struct {
 }
 
 func (c *Client) CreateGeneration(ctx context.Context, r *GenerationRequest) (*Generation, error) {
-	if c.baseURL == "" {
-		c.baseURL = "https://api.cohere.ai"
-	}
-
 	payload := generateRequestPayload{
 		Prompt: r.Prompt,
 		Model:  c.model,
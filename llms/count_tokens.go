@@ -48,6 +48,13 @@ func GetModelContextSize(model string) int {
 	return contextSize
 }
 
+// ContextSizer is implemented by language models knowing the size of the
+// context window of their model.
+type ContextSizer interface {
+	// GetContextSize returns the size of the context window, in tokens.
+	GetContextSize() int
+}
+
 // CountTokens gets the number of tokens the text contains.
 func CountTokens(model, text string) int {
 	e, err := tiktoken.EncodingForModel(model)
@@ -0,0 +1,168 @@
+package llms
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrNoMessage is returned by DedupChat.Call when no message was generated.
+var ErrNoMessage = errors.New("no message generated")
+
+// DedupChat is a chat model sharing a single request between identical
+// concurrent calls of the model it wraps, such as the calls of a user retrying
+// in a hurry. Calls are identical when they have the same messages and
+// options. Calls with a streaming function, a transport hook or a prompt
+// shrinker are never shared, as their functions can not be compared.
+type DedupChat struct {
+	Chat ChatLLM
+
+	mu       sync.Mutex
+	inFlight map[[sha256.Size]byte]*dedupCall
+}
+
+// dedupCall is a request in flight, whose result is given to all its callers
+// once done is closed.
+type dedupCall struct {
+	done        chan struct{}
+	generations []*Generation
+	err         error
+}
+
+var (
+	_ ChatLLM       = &DedupChat{}
+	_ LanguageModel = &DedupChat{}
+)
+
+// NewDedupChat creates a chat model deduplicating the concurrent calls of the
+// chat model.
+func NewDedupChat(chat ChatLLM) *DedupChat {
+	return &DedupChat{Chat: chat, inFlight: make(map[[sha256.Size]byte]*dedupCall)}
+}
+
+// Call generates a message for the messages, sharing the request with the
+// identical calls in flight.
+func (d *DedupChat) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := d.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(generations) == 0 || generations[0].Message == nil {
+		return nil, ErrNoMessage
+	}
+	return generations[0].Message, nil
+}
+
+// Generate generates messages for the sets of messages, sharing the request
+// with the identical calls in flight. A call whose shared request was canceled
+// by the context of another caller makes its own request.
+func (d *DedupChat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	key, ok := dedupKey(messageSets, options)
+	if !ok {
+		return d.Chat.Generate(ctx, messageSets, options...)
+	}
+
+	for {
+		d.mu.Lock()
+		call, shared := d.inFlight[key]
+		if !shared {
+			call = &dedupCall{done: make(chan struct{})}
+			d.inFlight[key] = call
+		}
+		d.mu.Unlock()
+
+		if !shared {
+			return d.lead(ctx, key, call, messageSets, options)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+		}
+		if call.err != nil && (errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded)) {
+			continue
+		}
+		return copyGenerations(call.generations), call.err
+	}
+}
+
+// lead makes the request of the call, and gives its result to the callers
+// sharing it.
+func (d *DedupChat) lead(ctx context.Context, key [sha256.Size]byte, call *dedupCall, messageSets [][]schema.ChatMessage, options []CallOption) ([]*Generation, error) { //nolint:lll
+	defer func() {
+		d.mu.Lock()
+		delete(d.inFlight, key)
+		d.mu.Unlock()
+		close(call.done)
+	}()
+	call.err = context.Canceled // Followers retry if the request panics.
+	call.generations, call.err = d.Chat.Generate(ctx, messageSets, options...)
+	return call.generations, call.err
+}
+
+// GeneratePrompt generates messages for the chat prompt values.
+func (d *DedupChat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GenerateChatPrompt(ctx, d, promptValues, options...)
+}
+
+// GetNumTokens returns the number of tokens of the text for the wrapped model,
+// if it is a language model, or for gpt-3.5-turbo.
+func (d *DedupChat) GetNumTokens(text string) int {
+	if lm, ok := d.Chat.(LanguageModel); ok {
+		return lm.GetNumTokens(text)
+	}
+	return CountTokens("gpt-3.5-turbo", text)
+}
+
+// dedupKey returns the hash identifying the call, and false if the call can
+// not be shared.
+func dedupKey(messageSets [][]schema.ChatMessage, options []CallOption) ([sha256.Size]byte, bool) {
+	var opts CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.StreamingFunc != nil || opts.TransportHook != nil || opts.PromptShrinker != nil {
+		return [sha256.Size]byte{}, false
+	}
+
+	type message struct {
+		Type    schema.ChatMessageType
+		Message schema.ChatMessage
+	}
+	sets := make([][]message, len(messageSets))
+	for i, messages := range messageSets {
+		for _, m := range messages {
+			sets[i] = append(sets[i], message{Type: m.GetType(), Message: m})
+		}
+	}
+	data, err := json.Marshal(struct {
+		Messages [][]message
+		Options  CallOptions
+	}{sets, opts})
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256(data), true
+}
+
+// copyGenerations returns copies of the generations, so that the callers of a
+// shared request can change them independently.
+func copyGenerations(generations []*Generation) []*Generation {
+	if generations == nil {
+		return nil
+	}
+	copies := make([]*Generation, len(generations))
+	for i, g := range generations {
+		if g == nil {
+			continue
+		}
+		c := *g
+		copies[i] = &c
+	}
+	return copies
+}
@@ -0,0 +1,111 @@
+package llms
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// slowChat answers with the content of the last message after a delay,
+// counting its requests.
+type slowChat struct {
+	requests int32
+	delay    time.Duration
+}
+
+func (c *slowChat) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := c.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return generations[0].Message, nil
+}
+
+func (c *slowChat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, _ ...CallOption) ([]*Generation, error) { //nolint:lll
+	atomic.AddInt32(&c.requests, 1)
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(c.delay):
+	}
+	generations := make([]*Generation, len(messageSets))
+	for i, messages := range messageSets {
+		text := messages[len(messages)-1].GetContent()
+		generations[i] = &Generation{Text: text, Message: &schema.AIChatMessage{Content: text}}
+	}
+	return generations, nil
+}
+
+func TestDedupChat(t *testing.T) {
+	t.Parallel()
+
+	chat := &slowChat{delay: 50 * time.Millisecond}
+	dedup := NewDedupChat(chat)
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}}
+
+	var wg sync.WaitGroup
+	results := make([]*schema.AIChatMessage, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			msg, err := dedup.Call(context.Background(), messages, WithTemperature(0.5))
+			require.NoError(t, err)
+			results[i] = msg
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&chat.requests))
+	for _, msg := range results {
+		require.Equal(t, "hello", msg.Content)
+	}
+
+	// Different options, or a streaming function, are not shared.
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := dedup.Call(context.Background(), messages, WithTemperature(0.1))
+		require.NoError(t, err)
+	}()
+	go func() {
+		defer wg.Done()
+		_, err := dedup.Call(context.Background(), messages, WithTemperature(0.5),
+			WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+		require.NoError(t, err)
+	}()
+	_, err := dedup.Call(context.Background(), messages, WithTemperature(0.5))
+	require.NoError(t, err)
+	wg.Wait()
+	require.Equal(t, int32(4), atomic.LoadInt32(&chat.requests))
+}
+
+func TestDedupChatCanceledLeader(t *testing.T) {
+	t.Parallel()
+
+	chat := &slowChat{delay: 50 * time.Millisecond}
+	dedup := NewDedupChat(chat)
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := dedup.Call(ctx, messages)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	msg, err := dedup.Call(context.Background(), messages)
+	require.NoError(t, err)
+	require.Equal(t, "hello", msg.Content)
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Equal(t, int32(2), atomic.LoadInt32(&chat.requests))
+}
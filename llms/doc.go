@@ -8,6 +8,8 @@
 // 3. OpenAI:            llms/openai/
 // 4. Vertex AI:         llms/vertexai/
 // 5. Cohere:            llms/cohere/
+// 6. AWS Bedrock:       llms/bedrock/
+// 7. OpenAI-compatible: llms/openaicompat/
 //
 // Each subpackage includes provider-specific LLM implementations and helper files for communication
 // with supported LLM providers. The internal directories within these subpackages contain provider-specific
@@ -0,0 +1,42 @@
+package llms
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownFunction is returned when the function forced with
+// WithForcedFunctionCall is not one of the functions of the request.
+var ErrUnknownFunction = errors.New("forced function call of an unknown function")
+
+// ValidateFunctionCall returns ErrUnknownFunction if the options force a call
+// of a function they do not define. Providers call it at the start of
+// Generate, before sending any request.
+func ValidateFunctionCall(opts CallOptions) error {
+	if opts.ForcedFunctionCall == "" {
+		return nil
+	}
+	for _, fn := range opts.Functions {
+		if fn.Name == opts.ForcedFunctionCall {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownFunction, opts.ForcedFunctionCall)
+}
+
+// ToolChoice returns the tool choice of the request of the options, for the
+// providers sending the functions as tools: a function forced with
+// WithForcedFunctionCall as {"type": "function", "function": {"name": ...}},
+// else the function call behavior, if any.
+func ToolChoice(opts CallOptions) any {
+	if opts.ForcedFunctionCall != "" {
+		return map[string]any{
+			"type":     "function",
+			"function": map[string]string{"name": opts.ForcedFunctionCall},
+		}
+	}
+	if opts.FunctionCallBehavior != "" {
+		return string(opts.FunctionCallBehavior)
+	}
+	return nil
+}
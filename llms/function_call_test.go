@@ -0,0 +1,33 @@
+package llms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFunctionCall(t *testing.T) {
+	t.Parallel()
+
+	opts := CallOptions{}
+	WithFunctions([]FunctionDefinition{{Name: "extract"}, {Name: "search"}})(&opts)
+	require.NoError(t, ValidateFunctionCall(opts))
+
+	WithForcedFunctionCall("search")(&opts)
+	require.NoError(t, ValidateFunctionCall(opts))
+
+	WithForcedFunctionCall("lookup")(&opts)
+	require.ErrorIs(t, ValidateFunctionCall(opts), ErrUnknownFunction)
+	require.ErrorIs(t, ValidateFunctionCall(CallOptions{ForcedFunctionCall: "search"}), ErrUnknownFunction)
+}
+
+func TestToolChoice(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, ToolChoice(CallOptions{}))
+	require.Equal(t, "none", ToolChoice(CallOptions{FunctionCallBehavior: FunctionCallBehaviorNone}))
+	require.Equal(t, map[string]any{
+		"type":     "function",
+		"function": map[string]string{"name": "extract"},
+	}, ToolChoice(CallOptions{FunctionCallBehavior: FunctionCallBehaviorAuto, ForcedFunctionCall: "extract"}))
+}
@@ -0,0 +1,14 @@
+// Package health collects the health of the providers and models used by an
+// application, to build dashboards without scraping logs: the state of their
+// circuit breakers, their recent error rates and latencies, and the hit rates
+// of their caches.
+//
+// A Monitor records the calls of the chat models wrapped with Chat, or with
+// its Middleware, and the lookups of the embedding caches wrapped with Cache. Circuit breakers report
+// their state with SetBreakerState. The health is read with Snapshot, or
+// served as JSON by the Monitor, which is an http.Handler:
+//
+//	monitor := health.NewMonitor()
+//	chat := monitor.Chat("openai", "gpt-4", openaiChat)
+//	http.Handle("/debug/llm-health", monitor)
+package health
@@ -0,0 +1,209 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const _defaultWindow = 100
+
+// BreakerState is the state of the circuit breaker of a provider and model.
+type BreakerState string
+
+const (
+	// BreakerUnknown is the state of models without a reported circuit
+	// breaker.
+	BreakerUnknown BreakerState = ""
+	// BreakerClosed lets calls through.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen fails calls without sending them.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets trial calls through.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// Stats is the health of a provider and model.
+type Stats struct {
+	Provider     string       `json:"provider"`
+	Model        string       `json:"model"`
+	BreakerState BreakerState `json:"breaker_state,omitempty"`
+	// Calls and Errors are the numbers of calls and failed calls since the
+	// monitor was created.
+	Calls  int64 `json:"calls"`
+	Errors int64 `json:"errors"`
+	// ErrorRate and AverageLatency are computed over the recent calls, see
+	// WithWindow. AverageLatency is encoded in nanoseconds.
+	ErrorRate      float64       `json:"error_rate"`
+	AverageLatency time.Duration `json:"average_latency"`
+	CacheHits      int64         `json:"cache_hits"`
+	CacheMisses    int64         `json:"cache_misses"`
+	// CacheHitRate is the share of the cache lookups that were hits.
+	CacheHitRate float64   `json:"cache_hit_rate"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastErrorAt  time.Time `json:"last_error_at,omitempty"`
+}
+
+// Monitor collects the health of providers and models. It is safe for
+// concurrent use, and serves the snapshot of the health as JSON over HTTP.
+type Monitor struct {
+	window int
+	now    func() time.Time
+
+	mu     sync.Mutex
+	models map[modelKey]*modelHealth
+}
+
+type modelKey struct {
+	provider, model string
+}
+
+// modelHealth is the health of a model, with the outcomes of its recent calls
+// in a ring buffer.
+type modelHealth struct {
+	stats  Stats
+	recent []outcome
+	next   int
+}
+
+type outcome struct {
+	latency time.Duration
+	failed  bool
+}
+
+var _ http.Handler = (*Monitor)(nil)
+
+// Option is a function type that can be used to modify the monitor.
+type Option func(m *Monitor)
+
+// WithWindow is an option for setting the number of recent calls error rates
+// and average latencies are computed over. Defaults to 100.
+func WithWindow(calls int) Option {
+	return func(m *Monitor) {
+		m.window = calls
+	}
+}
+
+// NewMonitor creates a new monitor with options.
+func NewMonitor(opts ...Option) *Monitor {
+	m := &Monitor{
+		window: _defaultWindow,
+		now:    time.Now,
+		models: make(map[modelKey]*modelHealth),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.window < 1 {
+		m.window = 1
+	}
+	return m
+}
+
+// RecordCall records a call of the model of the provider, which took the
+// latency and failed with err if it is not nil.
+func (m *Monitor) RecordCall(provider, model string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := m.model(provider, model)
+	h.stats.Calls++
+	o := outcome{latency: latency, failed: err != nil}
+	if o.failed {
+		h.stats.Errors++
+		h.stats.LastError = err.Error()
+		h.stats.LastErrorAt = m.now()
+	}
+	if len(h.recent) < m.window {
+		h.recent = append(h.recent, o)
+		return
+	}
+	h.recent[h.next] = o
+	h.next = (h.next + 1) % m.window
+}
+
+// RecordCacheLookup records a lookup in a cache of results of the model of
+// the provider, which hit if the result was cached.
+func (m *Monitor) RecordCacheLookup(provider, model string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h := m.model(provider, model)
+	if hit {
+		h.stats.CacheHits++
+	} else {
+		h.stats.CacheMisses++
+	}
+}
+
+// SetBreakerState records the state of the circuit breaker of the model of the
+// provider. Circuit breakers call it when their state changes.
+func (m *Monitor) SetBreakerState(provider, model string, state BreakerState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.model(provider, model).stats.BreakerState = state
+}
+
+// Snapshot returns the health of the providers and models, sorted by provider
+// and model.
+func (m *Monitor) Snapshot() []Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]Stats, 0, len(m.models))
+	for _, h := range m.models {
+		stats := h.stats
+		if len(h.recent) > 0 {
+			var failed int
+			var latency time.Duration
+			for _, o := range h.recent {
+				latency += o.latency
+				if o.failed {
+					failed++
+				}
+			}
+			stats.ErrorRate = float64(failed) / float64(len(h.recent))
+			stats.AverageLatency = latency / time.Duration(len(h.recent))
+		}
+		if lookups := stats.CacheHits + stats.CacheMisses; lookups > 0 {
+			stats.CacheHitRate = float64(stats.CacheHits) / float64(lookups)
+		}
+		snapshot = append(snapshot, stats)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Provider != snapshot[j].Provider {
+			return snapshot[i].Provider < snapshot[j].Provider
+		}
+		return snapshot[i].Model < snapshot[j].Model
+	})
+	return snapshot
+}
+
+// ServeHTTP writes the snapshot of the health as a JSON object, with the
+// stats of each provider and model under "models".
+func (m *Monitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(struct {
+		Models []Stats `json:"models"`
+	}{Models: m.Snapshot()})
+}
+
+// model returns the health of the model, creating it if needed. m.mu must be
+// held.
+func (m *Monitor) model(provider, model string) *modelHealth {
+	key := modelKey{provider: provider, model: model}
+	h, ok := m.models[key]
+	if !ok {
+		h = &modelHealth{stats: Stats{Provider: provider, Model: model}}
+		m.models[key] = h
+	}
+	return h
+}
@@ -0,0 +1,107 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// testChat answers with its answer, or fails with its error.
+type testChat struct {
+	err error
+}
+
+func (c testChat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := c.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return generations[0].Message, nil
+}
+
+func (c testChat) Generate(context.Context, [][]schema.ChatMessage, ...llms.CallOption) ([]*llms.Generation, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []*llms.Generation{{Message: &schema.AIChatMessage{Content: "hi"}}}, nil
+}
+
+func TestMonitor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	m := NewMonitor(WithWindow(2))
+	m.now = func() time.Time { return time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC) }
+
+	m.RecordCall("openai", "gpt-4", time.Second, errors.New("rate limited"))
+	m.RecordCall("openai", "gpt-4", 2*time.Second, nil)
+	m.RecordCall("openai", "gpt-4", 4*time.Second, nil)
+	m.SetBreakerState("openai", "gpt-4", BreakerHalfOpen)
+
+	chat := m.Chat("anthropic", "claude-2", testChat{})
+	_, err := chat.Call(ctx, []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}})
+	require.NoError(t, err)
+	failing := llms.WrapChat(testChat{err: errors.New("overloaded")}, m.Middleware("anthropic", "claude-2"))
+	_, err = failing.Call(ctx, nil, llms.WithModel("claude-instant-1"))
+	require.Error(t, err)
+
+	cache := m.Cache("openai", "text-embedding-ada-002", embeddings.NewInMemoryCache())
+	require.NoError(t, cache.Set(ctx, "key", []float64{1}))
+	_, ok := cache.Get(ctx, "key")
+	require.True(t, ok)
+	_, ok = cache.Get(ctx, "other")
+	require.False(t, ok)
+
+	snapshot := m.Snapshot()
+	require.Len(t, snapshot, 4)
+	assert.Equal(t, Stats{Provider: "anthropic", Model: "claude-2", Calls: 1}, zeroLatency(snapshot[0]))
+	assert.Equal(t, "claude-instant-1", snapshot[1].Model)
+	assert.Equal(t, 1.0, snapshot[1].ErrorRate)
+	assert.Equal(t, "overloaded", snapshot[1].LastError)
+	assert.Equal(t, Stats{
+		Provider:       "openai",
+		Model:          "gpt-4",
+		BreakerState:   BreakerHalfOpen,
+		Calls:          3,
+		Errors:         1,
+		AverageLatency: 3 * time.Second,
+		LastError:      "rate limited",
+		LastErrorAt:    time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+	}, snapshot[2])
+	assert.Equal(t, Stats{
+		Provider:     "openai",
+		Model:        "text-embedding-ada-002",
+		CacheHits:    1,
+		CacheMisses:  1,
+		CacheHitRate: 0.5,
+	}, snapshot[3])
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var body struct {
+		Models []Stats `json:"models"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, snapshot[2], body.Models[2])
+
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/health", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func zeroLatency(s Stats) Stats {
+	s.AverageLatency = 0
+	return s
+}
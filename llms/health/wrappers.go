@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Chat is a chat model recording its calls in a monitor.
+type Chat struct {
+	llms.ChatLLM
+
+	monitor  *Monitor
+	provider string
+	model    string
+}
+
+var (
+	_ llms.ChatLLM       = Chat{}
+	_ llms.LanguageModel = Chat{}
+)
+
+// Chat returns the chat model recording its calls as calls of the model of
+// the provider, or of the model given with llms.WithModel.
+func (m *Monitor) Chat(provider, model string, chat llms.ChatLLM) Chat {
+	return Chat{ChatLLM: chat, monitor: m, provider: provider, model: model}
+}
+
+// Call generates a message for the messages, recording the call.
+func (c Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	start := time.Now()
+	msg, err := c.ChatLLM.Call(ctx, messages, options...)
+	c.monitor.RecordCall(c.provider, c.modelOf(options), time.Since(start), err)
+	return msg, err
+}
+
+// Generate generates messages for the sets of messages, recording the call.
+func (c Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	start := time.Now()
+	generations, err := c.ChatLLM.Generate(ctx, messageSets, options...)
+	c.monitor.RecordCall(c.provider, c.modelOf(options), time.Since(start), err)
+	return generations, err
+}
+
+// GeneratePrompt generates messages for the chat prompt values, recording the
+// call.
+func (c Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, c, promptValues, options...)
+}
+
+// GetNumTokens returns the number of tokens of the text for the wrapped model,
+// if it is a language model, or for the model of the chat.
+func (c Chat) GetNumTokens(text string) int {
+	if lm, ok := c.ChatLLM.(llms.LanguageModel); ok {
+		return lm.GetNumTokens(text)
+	}
+	return llms.CountTokens(c.model, text)
+}
+
+func (c Chat) modelOf(options []llms.CallOption) string {
+	return modelOf(c.model, options)
+}
+
+// Middleware returns a middleware recording the calls of the chat models it
+// wraps as calls of the model of the provider, or of the model given with
+// llms.WithModel, see llms.WrapChat.
+func (m *Monitor) Middleware(provider, model string) llms.Middleware {
+	return func(next llms.Generator) llms.Generator {
+		return llms.GeneratorFunc(func(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+			start := time.Now()
+			generations, err := next.Generate(ctx, messageSets, options...)
+			m.RecordCall(provider, modelOf(model, options), time.Since(start), err)
+			return generations, err
+		})
+	}
+}
+
+// modelOf returns the model of a call, the model given with llms.WithModel if
+// any.
+func modelOf(model string, options []llms.CallOption) string {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Model != "" {
+		return opts.Model
+	}
+	return model
+}
+
+// cache is an embeddings cache recording its lookups in a monitor.
+type cache struct {
+	embeddings.Cache
+
+	monitor  *Monitor
+	provider string
+	model    string
+}
+
+// Cache returns the embeddings cache recording its lookups as cache lookups of
+// the embedding model of the provider.
+func (m *Monitor) Cache(provider, model string, c embeddings.Cache) embeddings.Cache { //nolint:ireturn
+	return cache{Cache: c, monitor: m, provider: provider, model: model}
+}
+
+func (c cache) Get(ctx context.Context, key string) ([]float64, bool) {
+	vector, ok := c.Cache.Get(ctx, key)
+	c.monitor.RecordCacheLookup(c.provider, c.model, ok)
+	return vector, ok
+}
@@ -3,6 +3,7 @@ package huggingface
 import (
 	"context"
 	"errors"
+	"net/http"
 	"os"
 
 	"github.com/tmc/langchaingo/llms"
@@ -17,7 +18,9 @@ var (
 )
 
 type LLM struct {
-	client *huggingfaceclient.Client
+	client       *huggingfaceclient.Client
+	task         huggingfaceclient.InferenceTask
+	rateLimiters *llms.RateLimiters
 }
 
 var (
@@ -37,28 +40,43 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 }
 
 func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
-	opts := &llms.CallOptions{Model: defaultModel}
+	opts := &llms.CallOptions{}
 	for _, opt := range options {
 		opt(opts)
 	}
-	result, err := o.client.RunInference(ctx, &huggingfaceclient.InferenceRequest{
-		Model:             o.client.Model,
-		Prompt:            prompts[0],
-		Task:              huggingfaceclient.InferenceTaskTextGeneration,
-		Temperature:       opts.Temperature,
-		TopP:              opts.TopP,
-		TopK:              opts.TopK,
-		MinLength:         opts.MinLength,
-		MaxLength:         opts.MaxLength,
-		RepetitionPenalty: opts.RepetitionPenalty,
-		Seed:              opts.Seed,
-	})
-	if err != nil {
-		return nil, err
+	ctx, cancel, _ := llms.ApplyLatencyBudget(ctx, opts)
+	defer cancel()
+	ctx = llms.ApplyTransportHook(ctx, *opts)
+	model := o.client.Model
+	if opts.Model != "" {
+		model = opts.Model
 	}
-	return []*llms.Generation{
-		{Text: result.Text},
-	}, nil
+
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for _, prompt := range prompts {
+		if err := llms.ApplyRateLimit(ctx, o.rateLimiters, model, *opts, prompt); err != nil {
+			return nil, err
+		}
+		result, err := o.client.RunInference(ctx, &huggingfaceclient.InferenceRequest{
+			Model:             model,
+			Prompt:            prompt,
+			Task:              o.task,
+			Temperature:       opts.Temperature,
+			TopP:              opts.TopP,
+			TopK:              opts.TopK,
+			MinLength:         opts.MinLength,
+			MaxLength:         opts.MaxLength,
+			MaxNewTokens:      opts.MaxTokens,
+			RepetitionPenalty: opts.RepetitionPenalty,
+			Seed:              opts.Seed,
+			StopWords:         opts.StopWords,
+		})
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, &llms.Generation{Text: result.Text})
+	}
+	return generations, nil
 }
 
 func (o *LLM) GeneratePrompt(ctx context.Context, prompts []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
@@ -73,23 +91,34 @@ func New(opts ...Option) (*LLM, error) {
 	options := &options{
 		token: os.Getenv(tokenEnvVarName),
 		model: defaultModel,
+		task:  InferenceTaskTextGeneration,
 	}
 
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	if len(options.token) == 0 {
+	// A token is only required when using the Inference API.
+	if len(options.token) == 0 && options.url == "" {
 		return nil, ErrMissingToken
 	}
 
-	c, err := huggingfaceclient.New(options.token, options.model)
+	httpClient := options.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	c, err := huggingfaceclient.New(options.token, options.model, options.url, llms.HookDoer(httpClient))
 	if err != nil {
 		return nil, err
 	}
+	if options.maxRetries != nil {
+		c.MaxRetries = *options.maxRetries
+	}
 
 	return &LLM{
-		client: c,
+		client:       c,
+		task:         huggingfaceclient.InferenceTask(options.task),
+		rateLimiters: &llms.RateLimiters{},
 	}, nil
 }
 
@@ -118,3 +147,29 @@ func (o *LLM) CreateEmbedding(
 	}
 	return embeddings, nil
 }
+
+// CreateEmbeddingFloat32 creates float32 embeddings for the given input texts.
+func (o *LLM) CreateEmbeddingFloat32(
+	ctx context.Context,
+	inputTexts []string,
+	model string,
+	task string,
+) ([][]float32, error) {
+	embeddings, err := o.client.CreateEmbeddingFloat32(ctx, model, task, &huggingfaceclient.EmbeddingRequest{
+		Inputs: inputTexts,
+		Options: map[string]any{
+			"use_gpu":        false,
+			"wait_for_model": true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	if len(inputTexts) != len(embeddings) {
+		return embeddings, ErrUnexpectedResponseLength
+	}
+	return embeddings, nil
+}
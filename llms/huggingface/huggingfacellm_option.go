@@ -1,13 +1,30 @@
 package huggingface
 
+import "github.com/tmc/langchaingo/llms/huggingface/internal/huggingfaceclient"
+
 const (
 	tokenEnvVarName = "HUGGINGFACEHUB_API_TOKEN"
 	defaultModel    = "gpt2"
 )
 
+// InferenceTask is the task the model is run with.
+type InferenceTask huggingfaceclient.InferenceTask
+
+const (
+	// InferenceTaskTextGeneration is the task for causal language models such as gpt2.
+	InferenceTaskTextGeneration = InferenceTask(huggingfaceclient.InferenceTaskTextGeneration)
+	// InferenceTaskText2TextGeneration is the task for encoder-decoder models such as flan-t5.
+	InferenceTaskText2TextGeneration = InferenceTask(huggingfaceclient.InferenceTaskText2TextGeneration)
+)
+
 type options struct {
-	token string
-	model string
+	token      string
+	model      string
+	url        string
+	task       InferenceTask
+	maxRetries *int
+
+	httpClient huggingfaceclient.Doer
 }
 
 type Option func(*options)
@@ -27,3 +44,37 @@ func WithModel(model string) Option {
 		opts.model = model
 	}
 }
+
+// WithURL passes the url of a dedicated inference endpoint to the client, such as a
+// Hugging Face Inference Endpoint or a local text-generation-inference server. If
+// set, requests are sent to the url instead of the Inference API, and the token is
+// optional.
+func WithURL(url string) Option {
+	return func(opts *options) {
+		opts.url = url
+	}
+}
+
+// WithTask sets the inference task the model is run with. If not set,
+// InferenceTaskTextGeneration is used.
+func WithTask(task InferenceTask) Option {
+	return func(opts *options) {
+		opts.task = task
+	}
+}
+
+// WithMaxRetries sets the number of times a request is retried while the model is
+// loading. If not set, requests are retried 3 times.
+func WithMaxRetries(maxRetries int) Option {
+	return func(opts *options) {
+		opts.maxRetries = &maxRetries
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client. If not set, the default value
+// is http.DefaultClient.
+func WithHTTPClient(client huggingfaceclient.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
@@ -0,0 +1,115 @@
+package huggingface
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+type recordedRequest struct {
+	path    string
+	payload map[string]any
+}
+
+// newRecordingServer returns a server answering each request with the inputs
+// of the request, and the requests it received.
+func newRecordingServer(t *testing.T) (*httptest.Server, func() []recordedRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []recordedRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		mu.Lock()
+		requests = append(requests, recordedRequest{path: r.URL.Path, payload: payload})
+		mu.Unlock()
+		_, _ = fmt.Fprintf(w, `[{"generated_text":"re: %s"}]`, payload["inputs"])
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []recordedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+// redirectTransport sends all requests to the host of the target url.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGenerateWithURL(t *testing.T) {
+	t.Setenv(tokenEnvVarName, "")
+
+	server, requests := newRecordingServer(t)
+	llm, err := New(WithURL(server.URL+"/generate"), WithTask(InferenceTaskText2TextGeneration))
+	require.NoError(t, err)
+
+	generations, err := llm.Generate(context.Background(), []string{"one", "two"},
+		llms.WithMaxTokens(10),
+		llms.WithStopWords([]string{"\n"}),
+	)
+	require.NoError(t, err)
+	require.Len(t, generations, 2)
+	assert.Equal(t, "re: one", generations[0].Text)
+	assert.Equal(t, "re: two", generations[1].Text)
+
+	recorded := requests()
+	require.Len(t, recorded, 2)
+	for i, input := range []string{"one", "two"} {
+		assert.Equal(t, "/generate", recorded[i].path)
+		assert.Equal(t, map[string]any{
+			"inputs": input,
+			"parameters": map[string]any{
+				"max_new_tokens": float64(10),
+				"stop":           []any{"\n"},
+			},
+		}, recorded[i].payload)
+	}
+}
+
+func TestGenerateModelOverride(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newRecordingServer(t)
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	llm, err := New(
+		WithToken("token"),
+		WithHTTPClient(&http.Client{Transport: redirectTransport{target: target}}),
+	)
+	require.NoError(t, err)
+
+	_, err = llm.Call(context.Background(), "hello")
+	require.NoError(t, err)
+	_, err = llm.Call(context.Background(), "hello", llms.WithModel("bigscience/bloom"))
+	require.NoError(t, err)
+
+	recorded := requests()
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "/models/"+defaultModel, recorded[0].path)
+	assert.Equal(t, "/models/bigscience/bloom", recorded[1].path)
+	// Text generation models must not echo the prompt.
+	assert.Equal(t, false, recorded[1].payload["parameters"].(map[string]any)["return_full_text"])
+}
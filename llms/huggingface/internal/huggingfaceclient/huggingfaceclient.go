@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 )
 
 var (
@@ -11,22 +13,54 @@ var (
 	ErrEmptyResponse = errors.New("empty response")
 )
 
-const huggingfaceAPIBaseURL = "https://api-inference.huggingface.co"
+const (
+	huggingfaceAPIBaseURL = "https://api-inference.huggingface.co"
+
+	defaultMaxRetries    = 3
+	defaultMaxRetryDelay = 30 * time.Second
+)
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
 
 type Client struct {
 	Token string
 	Model string
 	url   string
+	// endpoint is the url of a dedicated inference endpoint, such as a local
+	// text-generation-inference server. If set, it is used instead of the
+	// Inference API url for the model.
+	endpoint string
+
+	// MaxRetries is the number of times a request is retried when the model is
+	// still loading.
+	MaxRetries int
+	// MaxRetryDelay is the maximum time to wait between retries.
+	MaxRetryDelay time.Duration
+
+	httpClient Doer
 }
 
-func New(token string, model string) (*Client, error) {
-	if token == "" {
+// New creates a new client. If the endpoint is empty, the Hugging Face Inference
+// API is used and the token must be set. If httpClient is nil,
+// http.DefaultClient is used.
+func New(token string, model string, endpoint string, httpClient Doer) (*Client, error) {
+	if token == "" && endpoint == "" {
 		return nil, ErrInvalidToken
 	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
 	return &Client{
-		Token: token,
-		Model: model,
-		url:   huggingfaceAPIBaseURL,
+		Token:         token,
+		Model:         model,
+		url:           huggingfaceAPIBaseURL,
+		endpoint:      endpoint,
+		MaxRetries:    defaultMaxRetries,
+		MaxRetryDelay: defaultMaxRetryDelay,
+		httpClient:    httpClient,
 	}, nil
 }
 
@@ -39,8 +73,10 @@ type InferenceRequest struct {
 	TopK              int           `json:"top_k,omitempty"`
 	MinLength         int           `json:"min_length,omitempty"`
 	MaxLength         int           `json:"max_length,omitempty"`
+	MaxNewTokens      int           `json:"max_new_tokens,omitempty"`
 	RepetitionPenalty float64       `json:"repetition_penalty,omitempty"`
 	Seed              int           `json:"seed,omitempty"`
+	StopWords         []string      `json:"stop,omitempty"`
 }
 
 type InferenceResponse struct {
@@ -57,22 +93,27 @@ func (c *Client) RunInference(ctx context.Context, request *InferenceRequest) (*
 			TopK:              request.TopK,
 			MinLength:         request.MinLength,
 			MaxLength:         request.MaxLength,
+			MaxNewTokens:      request.MaxNewTokens,
 			RepetitionPenalty: request.RepetitionPenalty,
 			Seed:              request.Seed,
+			Stop:              request.StopWords,
 		},
 	}
-	resp, err := c.runInference(ctx, payload)
+	// Text generation models echo the prompt by default.
+	if request.Task == InferenceTaskTextGeneration {
+		returnFullText := false
+		payload.Parameters.ReturnFullText = &returnFullText
+	}
+
+	resp, err := c.runInferenceWithRetries(ctx, payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run inference: %w", err)
 	}
 	if len(resp) == 0 {
 		return nil, ErrEmptyResponse
 	}
-	text := resp[0].Text
-	// TODO: Add response cleaning based on Model.
-	// e.g., for gpt2, text = text[len(request.Prompt)+1:]
 	return &InferenceResponse{
-		Text: text,
+		Text: resp[0].Text,
 	}, nil
 }
 
@@ -104,6 +145,29 @@ func (c *Client) CreateEmbedding(
 	return c.convertFloat32ToFloat64(resp), nil
 }
 
+// CreateEmbeddingFloat32 creates embeddings as returned by the API, without
+// converting them to float64.
+func (c *Client) CreateEmbeddingFloat32(
+	ctx context.Context,
+	model string,
+	task string,
+	r *EmbeddingRequest,
+) ([][]float32, error) {
+	resp, err := c.createEmbedding(ctx, model, task, &embeddingPayload{
+		Inputs:  r.Inputs,
+		Options: r.Options,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	return resp, nil
+}
+
 func (c *Client) convertFloat32ToFloat64(input [][]float32) [][]float64 {
 	output := make([][]float64, len(input))
 	for i, row := range input {
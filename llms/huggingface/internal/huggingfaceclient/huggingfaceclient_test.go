@@ -7,7 +7,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -38,7 +40,7 @@ func TestRunInference(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			client, err := New("token", "model")
+			client, err := New("token", "model", "", nil)
 			require.NoError(t, err)
 			// Override the URL to point to our mock server.
 			client.url = server.URL
@@ -52,6 +54,146 @@ func TestRunInference(t *testing.T) {
 	}
 }
 
+func TestRunInferenceRetriesWhileModelIsLoading(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"Model gpt2 is currently loading","estimated_time":20.0}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(fmt.Sprintf(`[{"generated_text":"%s"}]`, goodResponse)))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New("", "model", server.URL, nil)
+	require.NoError(t, err)
+	client.MaxRetryDelay = time.Millisecond
+
+	resp, err := client.RunInference(context.TODO(), &InferenceRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &InferenceResponse{Text: goodResponse}, resp)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	client.MaxRetries = 1
+	atomic.StoreInt32(&calls, 0)
+	_, err = client.RunInference(context.TODO(), &InferenceRequest{})
+	assert.ErrorIs(t, err, ErrModelLoading)
+}
+
+func TestRunInferenceRetriesWithoutEstimatedTime(t *testing.T) {
+	t.Parallel()
+
+	// Dedicated endpoints such as text-generation-inference return a 503
+	// without an estimated time while loading.
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`[{"generated_text":"%s"}]`, goodResponse)))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New("", "model", server.URL, nil)
+	require.NoError(t, err)
+	client.MaxRetryDelay = time.Millisecond
+
+	resp, err := client.RunInference(context.TODO(), &InferenceRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &InferenceResponse{Text: goodResponse}, resp)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRunInferenceRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		token           string
+		endpoint        string
+		req             *InferenceRequest
+		expectedPath    string
+		expectedAuth    string
+		expectedPayload string
+	}{
+		{
+			name:  "text generation",
+			token: "token",
+			req: &InferenceRequest{
+				Model:        "gpt2",
+				Prompt:       "Hello",
+				Task:         InferenceTaskTextGeneration,
+				MaxNewTokens: 20,
+				StopWords:    []string{"\n"},
+			},
+			expectedPath: "/models/gpt2",
+			expectedAuth: "Bearer token",
+			expectedPayload: `{"inputs":"Hello","parameters":` +
+				`{"max_new_tokens":20,"stop":["\n"],"return_full_text":false}}`,
+		},
+		{
+			name:  "text2text generation",
+			token: "token",
+			req: &InferenceRequest{
+				Model:        "google/flan-t5-xl",
+				Prompt:       "Hello",
+				Task:         InferenceTaskText2TextGeneration,
+				MaxNewTokens: 20,
+			},
+			expectedPath:    "/models/google/flan-t5-xl",
+			expectedAuth:    "Bearer token",
+			expectedPayload: `{"inputs":"Hello","parameters":{"max_new_tokens":20}}`,
+		},
+		{
+			name:     "endpoint without token",
+			endpoint: "/generate",
+			req: &InferenceRequest{
+				Model:  "gpt2",
+				Prompt: "Hello",
+				Task:   InferenceTaskText2TextGeneration,
+			},
+			expectedPath:    "/generate",
+			expectedPayload: `{"inputs":"Hello","parameters":{}}`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var path, auth string
+			var payload []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				auth = r.Header.Get("Authorization")
+				payload, _ = io.ReadAll(r.Body)
+				_, _ = w.Write([]byte(fmt.Sprintf(`[{"generated_text":"%s"}]`, goodResponse)))
+			}))
+			t.Cleanup(server.Close)
+
+			endpoint := ""
+			if tc.endpoint != "" {
+				endpoint = server.URL + tc.endpoint
+			}
+			client, err := New(tc.token, "model", endpoint, nil)
+			require.NoError(t, err)
+			client.url = server.URL
+
+			_, err = client.RunInference(context.TODO(), tc.req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPath, path)
+			assert.Equal(t, tc.expectedAuth, auth)
+			assert.JSONEq(t, tc.expectedPayload, string(payload))
+		})
+	}
+}
+
 func mockServer(t *testing.T) *httptest.Server {
 	t.Helper()
 
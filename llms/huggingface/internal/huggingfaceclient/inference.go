@@ -8,9 +8,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
-var ErrUnexpectedStatusCode = errors.New("unexpected status code")
+var (
+	ErrUnexpectedStatusCode = errors.New("unexpected status code")
+	// ErrModelLoading is returned if the model is still loading after all retries.
+	ErrModelLoading = errors.New("model is loading")
+)
+
+// _initialRetryDelay is the first wait between retries when the server does not
+// estimate the loading time of the model.
+const _initialRetryDelay = time.Second
 
 // InferenceTask is the type of inference task to run.
 type InferenceTask string
@@ -27,13 +36,16 @@ type inferencePayload struct {
 }
 
 type parameters struct {
-	Temperature       float64 `json:"temperature,omitempty"`
-	TopP              float64 `json:"top_p,omitempty"`
-	TopK              int     `json:"top_k,omitempty"`
-	MinLength         int     `json:"min_length,omitempty"`
-	MaxLength         int     `json:"max_length,omitempty"`
-	RepetitionPenalty float64 `json:"repetition_penalty,omitempty"`
-	Seed              int     `json:"seed,omitempty"`
+	Temperature       float64  `json:"temperature,omitempty"`
+	TopP              float64  `json:"top_p,omitempty"`
+	TopK              int      `json:"top_k,omitempty"`
+	MinLength         int      `json:"min_length,omitempty"`
+	MaxLength         int      `json:"max_length,omitempty"`
+	MaxNewTokens      int      `json:"max_new_tokens,omitempty"`
+	RepetitionPenalty float64  `json:"repetition_penalty,omitempty"`
+	Seed              int      `json:"seed,omitempty"`
+	Stop              []string `json:"stop,omitempty"`
+	ReturnFullText    *bool    `json:"return_full_text,omitempty"`
 }
 
 type (
@@ -43,17 +55,82 @@ type (
 	}
 )
 
+// loadingResponse is the body returned with a 503 status code while the model is
+// being loaded by the Inference API.
+type loadingResponse struct {
+	Error         string  `json:"error"`
+	EstimatedTime float64 `json:"estimated_time"`
+}
+
+// loadingError is returned by runInference if the model is loading. The wait
+// is zero if the server did not estimate the loading time.
+type loadingError struct {
+	wait time.Duration
+	body string
+}
+
+func (e loadingError) Error() string {
+	if e.wait > 0 {
+		return fmt.Sprintf("%s, estimated time %s", ErrModelLoading, e.wait)
+	}
+	return fmt.Sprintf("%s: %d, body: %s", ErrModelLoading, http.StatusServiceUnavailable, e.body)
+}
+
+func (e loadingError) Unwrap() error {
+	return ErrModelLoading
+}
+
+// runInferenceWithRetries runs the inference and retries while the model is loading,
+// waiting for the estimated loading time given by the api between each attempt. If
+// there is no estimate, the wait doubles from one second with each attempt.
+func (c *Client) runInferenceWithRetries(ctx context.Context, payload *inferencePayload) (inferenceResponsePayload, error) { //nolint:lll
+	for attempt := 0; ; attempt++ {
+		resp, err := c.runInference(ctx, payload)
+		var loadingErr loadingError
+		if !errors.As(err, &loadingErr) || attempt >= c.MaxRetries {
+			return resp, err
+		}
+
+		wait := loadingErr.wait
+		if wait <= 0 {
+			// Cap the shift so the delay can not overflow.
+			shift := attempt
+			if shift > 16 {
+				shift = 16
+			}
+			wait = _initialRetryDelay << shift
+		}
+		if wait > c.MaxRetryDelay {
+			wait = c.MaxRetryDelay
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) inferenceURL(model string) string {
+	if c.endpoint != "" {
+		return c.endpoint
+	}
+	return fmt.Sprintf("%s/models/%s", c.url, model)
+}
+
 func (c *Client) runInference(ctx context.Context, payload *inferencePayload) (inferenceResponsePayload, error) {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 	body := bytes.NewReader(payloadBytes)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/models/%s", c.url, payload.Model), body) //nolint:lll
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.inferenceURL(payload.Model), body)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	// debug print the http request with httputil:
@@ -64,7 +141,7 @@ func (c *Client) runInference(ctx context.Context, payload *inferencePayload) (i
 	// }
 	// fmt.Fprintf(os.Stderr, "%s", reqDump)
 
-	r, err := http.DefaultClient.Do(req)
+	r, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +153,17 @@ func (c *Client) runInference(ctx context.Context, payload *inferencePayload) (i
 			return nil, fmt.Errorf("failed to read response body: %w", err)
 		}
 
+		// The Inference API gives an estimate of the loading time, dedicated
+		// endpoints such as text-generation-inference only return a 503.
+		if r.StatusCode == http.StatusServiceUnavailable {
+			var loading loadingResponse
+			_ = json.Unmarshal(b, &loading)
+			return nil, loadingError{
+				wait: time.Duration(loading.EstimatedTime * float64(time.Second)),
+				body: string(b),
+			}
+		}
+
 		if len(b) > 0 {
 			err = fmt.Errorf("%w: %d, body: %s", ErrUnexpectedStatusCode, r.StatusCode, string(b))
 		} else {
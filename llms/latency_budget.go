@@ -0,0 +1,120 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrLatencyBudgetExceeded is returned by streaming functions wrapped by
+// ApplyLatencyBudget to stop the stream before the end of the budget.
+var ErrLatencyBudgetExceeded = errors.New("latency budget exceeded")
+
+// StopReasonLatencyBudget is the stop reason of generations cut short by the
+// end of their latency budget.
+const StopReasonLatencyBudget = "latency_budget"
+
+const (
+	// _latencyBudgetTokensPerSecond is the generation speed assumed when
+	// lowering the maximum number of tokens to fit in a latency budget.
+	_latencyBudgetTokensPerSecond = 30
+	// _latencyBudgetFirstTokenDelay is the time assumed to pass before the
+	// first token is generated.
+	_latencyBudgetFirstTokenDelay = 500 * time.Millisecond
+	// _latencyBudgetStreamShare is the share of the budget after which
+	// streaming is stopped, leaving time to return the partial response.
+	_latencyBudgetStreamShare = 0.9
+)
+
+// LatencyBudget tracks a call made with a latency budget, see WithLatencyBudget.
+type LatencyBudget struct {
+	streamDeadline time.Time
+
+	mu       sync.Mutex
+	streamed strings.Builder
+}
+
+// ApplyLatencyBudget applies the latency budget set with WithLatencyBudget to a
+// call. It returns a context that ends with the budget, lowers MaxTokens to the
+// number of tokens that can be generated in time, and wraps the streaming
+// function to stop the stream before the deadline. Providers call it at the
+// start of Generate and use Partial to return the streamed text when the
+// budget stops the stream. The returned LatencyBudget is nil if no budget is set.
+func ApplyLatencyBudget(ctx context.Context, opts *CallOptions) (context.Context, context.CancelFunc, *LatencyBudget) { //nolint:lll
+	if opts.LatencyBudget <= 0 {
+		return ctx, func() {}, nil
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithDeadline(ctx, start.Add(opts.LatencyBudget))
+
+	maxTokens := int((opts.LatencyBudget - _latencyBudgetFirstTokenDelay).Seconds() * _latencyBudgetTokensPerSecond)
+	if maxTokens < 1 {
+		maxTokens = 1
+	}
+	if opts.MaxTokens == 0 || opts.MaxTokens > maxTokens {
+		opts.MaxTokens = maxTokens
+	}
+
+	b := &LatencyBudget{
+		streamDeadline: start.Add(time.Duration(float64(opts.LatencyBudget) * _latencyBudgetStreamShare)),
+	}
+	if streamingFunc := opts.StreamingFunc; streamingFunc != nil {
+		opts.StreamingFunc = func(ctx context.Context, chunk []byte) error {
+			if err := streamingFunc(ctx, chunk); err != nil {
+				return err
+			}
+			b.mu.Lock()
+			b.streamed.Write(chunk)
+			b.mu.Unlock()
+			if time.Now().After(b.streamDeadline) {
+				return ErrLatencyBudgetExceeded
+			}
+			return nil
+		}
+	}
+
+	return ctx, cancel, b
+}
+
+// Reset forgets the text streamed so far. Providers call it before each
+// request of a call. It is safe to call on a nil LatencyBudget.
+func (b *LatencyBudget) Reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.streamed.Reset()
+}
+
+// Partial returns a generation holding the text streamed since the last Reset
+// if err was caused by the end of the latency budget, or nil otherwise. The
+// finish reason of its metadata, and the "StopReason" key of its generation
+// info, are StopReasonLatencyBudget.
+// It is safe to call on a nil LatencyBudget.
+func (b *LatencyBudget) Partial(err error) *Generation {
+	if b == nil || err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrLatencyBudgetExceeded) && !errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.streamed.Len() == 0 {
+		return nil
+	}
+	text := b.streamed.String()
+	return &Generation{
+		Text:           text,
+		Message:        &schema.AIChatMessage{Content: text},
+		GenerationInfo: map[string]any{"StopReason": StopReasonLatencyBudget},
+		Metadata:       &ResponseMetadata{FinishReason: StopReasonLatencyBudget},
+	}
+}
@@ -0,0 +1,87 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyLatencyBudgetWithoutBudget(t *testing.T) {
+	t.Parallel()
+
+	opts := CallOptions{MaxTokens: 500}
+	ctx, cancel, budget := ApplyLatencyBudget(context.Background(), &opts)
+	defer cancel()
+
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+	assert.Nil(t, budget)
+	assert.Equal(t, 500, opts.MaxTokens)
+	assert.Nil(t, budget.Partial(ErrLatencyBudgetExceeded))
+}
+
+func TestApplyLatencyBudgetMaxTokens(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		budget    time.Duration
+		maxTokens int
+		want      int
+	}{
+		{"unset", 2 * time.Second, 0, 45},
+		{"lowered", 2 * time.Second, 1000, 45},
+		{"kept", 2 * time.Second, 10, 10},
+		{"tiny budget", 100 * time.Millisecond, 0, 1},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := CallOptions{MaxTokens: tc.maxTokens, LatencyBudget: tc.budget}
+			ctx, cancel, _ := ApplyLatencyBudget(context.Background(), &opts)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(tc.budget), deadline, tc.budget)
+			assert.Equal(t, tc.want, opts.MaxTokens)
+		})
+	}
+}
+
+func TestApplyLatencyBudgetStopsStream(t *testing.T) {
+	t.Parallel()
+
+	var received []string
+	opts := CallOptions{
+		StreamingFunc: func(ctx context.Context, chunk []byte) error {
+			received = append(received, string(chunk))
+			return nil
+		},
+	}
+	WithLatencyBudget(50 * time.Millisecond)(&opts)
+	ctx, cancel, budget := ApplyLatencyBudget(context.Background(), &opts)
+	defer cancel()
+
+	require.NoError(t, opts.StreamingFunc(ctx, []byte("Hello")))
+	time.Sleep(50 * time.Millisecond)
+	err := opts.StreamingFunc(ctx, []byte(" world"))
+	require.ErrorIs(t, err, ErrLatencyBudgetExceeded)
+	assert.Equal(t, []string{"Hello", " world"}, received)
+
+	partial := budget.Partial(err)
+	require.NotNil(t, partial)
+	assert.Equal(t, "Hello world", partial.Text)
+	assert.Equal(t, "Hello world", partial.Message.Content)
+	assert.Equal(t, StopReasonLatencyBudget, partial.GenerationInfo["StopReason"])
+
+	assert.Nil(t, budget.Partial(errors.New("other error")))
+	budget.Reset()
+	assert.Nil(t, budget.Partial(err))
+}
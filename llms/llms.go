@@ -2,6 +2,7 @@ package llms
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/tmc/langchaingo/schema"
 )
@@ -34,6 +35,8 @@ type Generation struct {
 	Message *schema.AIChatMessage `json:"message"`
 	// GenerationInfo is the generation info. This can contain vendor-specific information.
 	GenerationInfo map[string]any `json:"generation_info"`
+	// Metadata describes the response of the provider, see ResponseMetadata.
+	Metadata *ResponseMetadata `json:"metadata,omitempty"`
 }
 
 // LLMResult is the class that contains all relevant information for an LLM Result.
@@ -63,3 +66,17 @@ func GenerateChatPrompt(ctx context.Context, l ChatLLM, promptValues []schema.Pr
 		Generations: [][]*Generation{generations},
 	}, err
 }
+
+// FunctionArguments returns the arguments of a function call as the JSON
+// string expected by the OpenAI API. Models return the arguments as a string,
+// other values are encoded to JSON.
+func FunctionArguments(arguments any) string {
+	if s, ok := arguments.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(arguments)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
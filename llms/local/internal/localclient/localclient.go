@@ -24,6 +24,9 @@ func New(binPath string, globalAsArgs bool, args ...string) (*Client, error) {
 // CompletionRequest is a request to create a completion.
 type CompletionRequest struct {
 	Prompt string `json:"prompt"`
+	// Args are passed to the binary after the args of the client, for this
+	// request only.
+	Args []string `json:"args"`
 }
 
 // Completion is a completion.
@@ -35,6 +38,7 @@ type Completion struct {
 func (c *Client) CreateCompletion(ctx context.Context, r *CompletionRequest) (*Completion, error) {
 	resp, err := c.createCompletion(ctx, &completionPayload{
 		Prompt: r.Prompt,
+		Args:   r.Args,
 	})
 	if err != nil {
 		return nil, err
@@ -22,7 +22,8 @@ var (
 
 // LLM is a local LLM implementation.
 type LLM struct {
-	client *localclient.Client
+	client       *localclient.Client
+	rateLimiters *llms.RateLimiters
 }
 
 // _ ensures that LLM implements the llms.LLM and language model interface.
@@ -43,30 +44,33 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 	return r[0].Text, nil
 }
 
-func (o *LLM) appendGlobalsToArgs(opts llms.CallOptions) []string {
+// globalArgs returns the options of a call as arguments in the --key=value
+// format.
+func globalArgs(opts llms.CallOptions) []string {
+	var args []string
 	if opts.Temperature != 0 {
-		o.client.Args = append(o.client.Args, fmt.Sprintf("--temperature=%f", opts.Temperature))
+		args = append(args, fmt.Sprintf("--temperature=%f", opts.Temperature))
 	}
 	if opts.TopP != 0 {
-		o.client.Args = append(o.client.Args, fmt.Sprintf("--top_p=%f", opts.TopP))
+		args = append(args, fmt.Sprintf("--top_p=%f", opts.TopP))
 	}
 	if opts.TopK != 0 {
-		o.client.Args = append(o.client.Args, fmt.Sprintf("--top_k=%d", opts.TopK))
+		args = append(args, fmt.Sprintf("--top_k=%d", opts.TopK))
 	}
 	if opts.MinLength != 0 {
-		o.client.Args = append(o.client.Args, fmt.Sprintf("--min_length=%d", opts.MinLength))
+		args = append(args, fmt.Sprintf("--min_length=%d", opts.MinLength))
 	}
 	if opts.MaxLength != 0 {
-		o.client.Args = append(o.client.Args, fmt.Sprintf("--max_length=%d", opts.MaxLength))
+		args = append(args, fmt.Sprintf("--max_length=%d", opts.MaxLength))
 	}
 	if opts.RepetitionPenalty != 0 {
-		o.client.Args = append(o.client.Args, fmt.Sprintf("--repetition_penalty=%f", opts.RepetitionPenalty))
+		args = append(args, fmt.Sprintf("--repetition_penalty=%f", opts.RepetitionPenalty))
 	}
 	if opts.Seed != 0 {
-		o.client.Args = append(o.client.Args, fmt.Sprintf("--seed=%d", opts.Seed))
+		args = append(args, fmt.Sprintf("--seed=%d", opts.Seed))
 	}
 
-	return o.client.Args
+	return args
 }
 
 // Generate generates completions using the local LLM binary.
@@ -76,21 +80,29 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 		opt(opts)
 	}
 
-	// If o.client.GlobalAsArgs is true
+	// The options are passed as --key=value arguments of each request, the
+	// args of the client staying the same across calls.
+	var args []string
 	if o.client.GlobalAsArgs {
-		// Then add the option to the args in --key=value format
-		o.appendGlobalsToArgs(*opts)
+		args = globalArgs(*opts)
 	}
 
-	result, err := o.client.CreateCompletion(ctx, &localclient.CompletionRequest{
-		Prompt: prompts[0],
-	})
-	if err != nil {
-		return nil, err
+	generations := make([]*llms.Generation, 0, len(prompts))
+	for _, prompt := range prompts {
+		// The binary runs a single model.
+		if err := llms.ApplyRateLimit(ctx, o.rateLimiters, "", *opts, prompt); err != nil {
+			return nil, err
+		}
+		result, err := o.client.CreateCompletion(ctx, &localclient.CompletionRequest{
+			Prompt: prompt,
+			Args:   args,
+		})
+		if err != nil {
+			return nil, err
+		}
+		generations = append(generations, &llms.Generation{Text: result.Text})
 	}
-	return []*llms.Generation{
-		{Text: result.Text},
-	}, nil
+	return generations, nil
 }
 
 func (o *LLM) GeneratePrompt(
@@ -123,6 +135,7 @@ func New(opts ...Option) (*LLM, error) {
 
 	c, err := localclient.New(path, options.globalAsArgs, strings.Split(options.args, " ")...)
 	return &LLM{
-		client: c,
+		client:       c,
+		rateLimiters: &llms.RateLimiters{},
 	}, err
 }
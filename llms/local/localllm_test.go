@@ -0,0 +1,60 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestGenerateKeepsArgsPerCall(t *testing.T) {
+	t.Parallel()
+
+	llm, err := New(WithBin("echo"), WithArgs("-n"), WithGlobalAsArgs())
+	require.NoError(t, err)
+
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		go func(i int) {
+			res, err := llm.Generate(context.Background(), []string{fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)},
+				llms.WithSeed(i+1))
+			if err != nil {
+				errs <- err
+				return
+			}
+			got := []string{res[0].Text, res[1].Text}
+			want := []string{fmt.Sprintf("--seed=%d a%d", i+1, i), fmt.Sprintf("--seed=%d b%d", i+1, i)}
+			if strings.Join(got, "|") != strings.Join(want, "|") {
+				errs <- fmt.Errorf("call %d: got %q, want %q", i, got, want) //nolint:goerr113
+				return
+			}
+			errs <- nil
+		}(i)
+	}
+	for i := 0; i < cap(errs); i++ {
+		require.NoError(t, <-errs)
+	}
+}
+
+func TestGenerateRateLimit(t *testing.T) {
+	t.Parallel()
+
+	llm, err := New(WithBin("echo"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = llm.Generate(ctx, []string{"a"}, llms.WithRateLimit(1, 0))
+	require.NoError(t, err)
+	_, err = llm.Generate(ctx, []string{"b"}, llms.WithRateLimit(1, 0))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	other, err := New(WithBin("echo"))
+	require.NoError(t, err)
+	_, err = other.Generate(ctx, []string{"c"}, llms.WithRateLimit(1, 0))
+	require.NoError(t, err, "the limiters of other LLMs are not shared")
+}
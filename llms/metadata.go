@@ -0,0 +1,75 @@
+package llms
+
+import "time"
+
+// ResponseMetadata describes the response of the provider a generation was
+// made from.
+type ResponseMetadata struct {
+	// Model is the model that generated the response, as reported by the
+	// provider.
+	Model string `json:"model,omitempty"`
+	// FinishReason is why the generation stopped, such as "stop" or
+	// "length", or StopReasonLatencyBudget.
+	FinishReason string `json:"finish_reason,omitempty"`
+	// Usage is the usage of the call, with its cost estimated for the model.
+	Usage Usage `json:"usage"`
+	// Latency is the time the provider took to respond.
+	Latency time.Duration `json:"latency,omitempty"`
+	// Truncated is whether the generation was cut short by its maximum length
+	// and trimmed, see WithTrimToBoundary.
+	Truncated bool `json:"truncated,omitempty"`
+	// Raw is the decoded response of the provider, for inspection or logging.
+	Raw any `json:"-"`
+}
+
+// NewResponseMetadata creates the metadata of a response of the model, with the
+// cost of its usage estimated with ModelPrice.
+func NewResponseMetadata(model, finishReason string, usage Usage, latency time.Duration, raw any) *ResponseMetadata {
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	if usage.CostUSD == 0 {
+		usage.CostUSD = estimateCost(model, usage)
+	}
+	return &ResponseMetadata{
+		Model:        model,
+		FinishReason: finishReason,
+		Usage:        usage,
+		Latency:      latency,
+		Raw:          raw,
+	}
+}
+
+// GenerationInfo returns the metadata as the generation info kept for
+// compatibility, under the "PromptTokens", "CompletionTokens", "TotalTokens",
+// "Model", "StopReason" and "Truncated" keys.
+func (m *ResponseMetadata) GenerationInfo() map[string]any {
+	info := map[string]any{
+		"PromptTokens":     m.Usage.PromptTokens,
+		"CompletionTokens": m.Usage.CompletionTokens,
+		"TotalTokens":      m.Usage.TotalTokens,
+	}
+	if m.Model != "" {
+		info["Model"] = m.Model
+	}
+	if m.FinishReason != "" {
+		info["StopReason"] = m.FinishReason
+	}
+	if m.Truncated {
+		info["Truncated"] = true
+	}
+	return info
+}
+
+// ResponseMetadata returns the metadata of the generation. For generations of
+// providers only setting the generation info, it is read from it.
+func (g *Generation) ResponseMetadata() ResponseMetadata {
+	if g.Metadata != nil {
+		return *g.Metadata
+	}
+	m := ResponseMetadata{Usage: GenerationUsage(g.GenerationInfo)}
+	m.Model, _ = g.GenerationInfo["Model"].(string)
+	m.FinishReason, _ = g.GenerationInfo["StopReason"].(string)
+	m.Truncated, _ = g.GenerationInfo["Truncated"].(bool)
+	return m
+}
@@ -0,0 +1,52 @@
+package llms
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResponseMetadata(t *testing.T) {
+	t.Parallel()
+
+	m := NewResponseMetadata("gpt-4-0613", "stop", Usage{PromptTokens: 1000, CompletionTokens: 500}, time.Second, "raw")
+	assert.Equal(t, 1500, m.Usage.TotalTokens)
+	assert.InDelta(t, 0.06, m.Usage.CostUSD, 1e-9)
+	assert.Equal(t, map[string]any{
+		"PromptTokens":     1000,
+		"CompletionTokens": 500,
+		"TotalTokens":      1500,
+		"Model":            "gpt-4-0613",
+		"StopReason":       "stop",
+	}, m.GenerationInfo())
+
+	g := &Generation{GenerationInfo: m.GenerationInfo(), Metadata: m}
+	assert.Equal(t, *m, g.ResponseMetadata())
+}
+
+func TestGenerationResponseMetadataFromInfo(t *testing.T) {
+	t.Parallel()
+
+	g := &Generation{GenerationInfo: map[string]any{
+		"PromptTokens":     10,
+		"CompletionTokens": 5,
+		"StopReason":       "length",
+	}}
+	assert.Equal(t, ResponseMetadata{
+		FinishReason: "length",
+		Usage:        Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, g.ResponseMetadata())
+}
+
+func TestTrackUsageReadsMetadata(t *testing.T) {
+	t.Parallel()
+
+	tracker := &UsageTracker{}
+	ctx := WithUsageTracker(context.Background(), tracker)
+	TrackUsage(ctx, LLMResult{Generations: [][]*Generation{{
+		{Metadata: NewResponseMetadata("local", "", Usage{PromptTokens: 3, CompletionTokens: 4}, 0, nil)},
+	}}})
+	assert.Equal(t, Usage{PromptTokens: 3, CompletionTokens: 4, TotalTokens: 7}, tracker.Usage())
+}
@@ -0,0 +1,98 @@
+package llms
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Generator generates messages for sets of messages. Chat models are
+// generators.
+type Generator interface {
+	Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error)
+}
+
+// GeneratorFunc is a function generating messages for sets of messages.
+type GeneratorFunc func(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) //nolint:lll
+
+// Generate calls the function.
+func (f GeneratorFunc) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	return f(ctx, messageSets, options...)
+}
+
+// Middleware wraps the calls to a generator, to add a cross-cutting concern
+// such as redaction, caching, logging, rate limiting or token budgeting to any
+// provider. A middleware can change the messages and options before calling
+// next, change the generations it returns, or answer without calling it.
+type Middleware func(next Generator) Generator
+
+// ChainMiddlewares returns a middleware calling the middlewares in order, the
+// first one being the outermost.
+func ChainMiddlewares(middlewares ...Middleware) Middleware {
+	return func(next Generator) Generator {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+		return next
+	}
+}
+
+// MiddlewareChat is a chat model calling the chat model it wraps through
+// middlewares.
+type MiddlewareChat struct {
+	Chat      ChatLLM
+	generator Generator
+}
+
+var (
+	_ ChatLLM       = &MiddlewareChat{}
+	_ LanguageModel = &MiddlewareChat{}
+	_ Warmer        = &MiddlewareChat{}
+)
+
+// WrapChat creates a chat model calling the chat model through the
+// middlewares, the first one being the outermost.
+func WrapChat(chat ChatLLM, middlewares ...Middleware) *MiddlewareChat {
+	return &MiddlewareChat{Chat: chat, generator: ChainMiddlewares(middlewares...)(chat)}
+}
+
+// Call generates a message for the messages through the middlewares.
+func (c *MiddlewareChat) Call(ctx context.Context, messages []schema.ChatMessage, options ...CallOption) (*schema.AIChatMessage, error) { //nolint:lll
+	generations, err := c.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(generations) == 0 || generations[0] == nil || generations[0].Message == nil {
+		return nil, ErrNoMessage
+	}
+	return generations[0].Message, nil
+}
+
+// Generate generates messages for the sets of messages through the
+// middlewares.
+func (c *MiddlewareChat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+	return c.generator.Generate(ctx, messageSets, options...)
+}
+
+// GeneratePrompt generates messages for the chat prompt values through the
+// middlewares.
+func (c *MiddlewareChat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...CallOption) (LLMResult, error) { //nolint:lll
+	return GenerateChatPrompt(ctx, c, promptValues, options...)
+}
+
+// GetNumTokens returns the number of tokens of the text for the wrapped model,
+// if it is a language model, or for gpt-3.5-turbo.
+func (c *MiddlewareChat) GetNumTokens(text string) int {
+	if lm, ok := c.Chat.(LanguageModel); ok {
+		return lm.GetNumTokens(text)
+	}
+	return CountTokens("gpt-3.5-turbo", text)
+}
+
+// Warmup warms the wrapped model up, if it is a Warmer.
+func (c *MiddlewareChat) Warmup(ctx context.Context) error {
+	if w, ok := c.Chat.(Warmer); ok {
+		return w.Warmup(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package llms
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestWrapChat(t *testing.T) {
+	t.Parallel()
+
+	var trace []string
+	tracing := func(name string) Middleware {
+		return func(next Generator) Generator {
+			return GeneratorFunc(func(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+				trace = append(trace, name)
+				return next.Generate(ctx, messageSets, options...)
+			})
+		}
+	}
+	redacting := func(next Generator) Generator {
+		return GeneratorFunc(func(ctx context.Context, messageSets [][]schema.ChatMessage, options ...CallOption) ([]*Generation, error) { //nolint:lll
+			redacted := make([][]schema.ChatMessage, len(messageSets))
+			for i, messages := range messageSets {
+				for _, m := range messages {
+					content := strings.ReplaceAll(m.GetContent(), "hunter2", "[redacted]")
+					redacted[i] = append(redacted[i], schema.HumanChatMessage{Content: content})
+				}
+			}
+			return next.Generate(ctx, redacted, options...)
+		})
+	}
+
+	chat := WrapChat(&slowChat{}, tracing("outer"), redacting, tracing("inner"))
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "my password is hunter2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "my password is [redacted]", msg.Content)
+	require.Equal(t, []string{"outer", "inner"}, trace)
+
+	short := func(Generator) Generator {
+		return GeneratorFunc(func(context.Context, [][]schema.ChatMessage, ...CallOption) ([]*Generation, error) {
+			return nil, nil
+		})
+	}
+	_, err = WrapChat(&slowChat{}, short).Call(context.Background(), nil)
+	require.ErrorIs(t, err, ErrNoMessage)
+}
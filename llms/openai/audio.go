@@ -0,0 +1,76 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
+)
+
+// Transcribe transcribes the audio to text with the whisper model. The format
+// of the audio is detected from its content.
+func (o *LLM) Transcribe(ctx context.Context, audio []byte) (string, error) {
+	return transcribe(ctx, o.client, audio)
+}
+
+// Transcribe transcribes the audio to text with the whisper model. The format
+// of the audio is detected from its content.
+func (o *Chat) Transcribe(ctx context.Context, audio []byte) (string, error) {
+	return transcribe(ctx, o.client, audio)
+}
+
+// Translate transcribes the audio to English text with the whisper model,
+// whatever the language spoken.
+func (o *LLM) Translate(ctx context.Context, audio []byte) (string, error) {
+	return translate(ctx, o.client, audio)
+}
+
+// Translate transcribes the audio to English text with the whisper model,
+// whatever the language spoken.
+func (o *Chat) Translate(ctx context.Context, audio []byte) (string, error) {
+	return translate(ctx, o.client, audio)
+}
+
+// Synthesize synthesizes speech for the text with the text to speech model,
+// returning mp3 audio.
+func (o *LLM) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	return o.client.CreateSpeech(ctx, &openaiclient.SpeechRequest{Input: text})
+}
+
+// Synthesize synthesizes speech for the text with the text to speech model,
+// returning mp3 audio.
+func (o *Chat) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	return o.client.CreateSpeech(ctx, &openaiclient.SpeechRequest{Input: text})
+}
+
+func transcribe(ctx context.Context, client *openaiclient.Client, audio []byte) (string, error) {
+	return client.CreateTranscription(ctx, &openaiclient.TranscriptionRequest{
+		Audio:    audio,
+		FileName: audioFileName(audio),
+	})
+}
+
+func translate(ctx context.Context, client *openaiclient.Client, audio []byte) (string, error) {
+	return client.CreateTranslation(ctx, &openaiclient.TranscriptionRequest{
+		Audio:    audio,
+		FileName: audioFileName(audio),
+	})
+}
+
+// audioFileName returns a file name with the extension of the format of the
+// audio, which the API uses to decode it.
+func audioFileName(audio []byte) string {
+	switch {
+	case bytes.HasPrefix(audio, []byte("RIFF")):
+		return "audio.wav"
+	case bytes.HasPrefix(audio, []byte("OggS")):
+		return "audio.ogg"
+	case bytes.HasPrefix(audio, []byte("fLaC")):
+		return "audio.flac"
+	case bytes.HasPrefix(audio, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return "audio.webm"
+	case len(audio) > 8 && bytes.Equal(audio[4:8], []byte("ftyp")):
+		return "audio.m4a"
+	}
+	return "audio.mp3"
+}
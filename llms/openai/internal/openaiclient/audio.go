@@ -0,0 +1,161 @@
+package openaiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+const (
+	defaultTranscriptionModel = "whisper-1"
+	defaultSpeechModel        = "tts-1"
+	defaultSpeechVoice        = "alloy"
+)
+
+// TranscriptionRequest is a request to transcribe audio with a speech to text
+// model.
+type TranscriptionRequest struct {
+	Model string
+	Audio []byte
+	// FileName is the name of the audio file, the extension of which gives the
+	// format of the audio.
+	FileName string
+	Language string
+	Prompt   string
+}
+
+type transcriptionResponsePayload struct {
+	Text string `json:"text"`
+}
+
+// SpeechRequest is a request to synthesize speech with a text to speech model.
+type SpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float64 `json:"speed,omitempty"`
+}
+
+// CreateTranscription transcribes the audio of the request to text.
+func (c *Client) CreateTranscription(ctx context.Context, r *TranscriptionRequest) (string, error) {
+	return c.createAudioText(ctx, "/audio/transcriptions", r)
+}
+
+// CreateTranslation transcribes the audio of the request to English text,
+// whatever the language spoken. The language of the request is ignored.
+func (c *Client) CreateTranslation(ctx context.Context, r *TranscriptionRequest) (string, error) {
+	return c.createAudioText(ctx, "/audio/translations", &TranscriptionRequest{
+		Model:    r.Model,
+		Audio:    r.Audio,
+		FileName: r.FileName,
+		Prompt:   r.Prompt,
+	})
+}
+
+// createAudioText posts the audio of the request to an endpoint returning its
+// text.
+func (c *Client) createAudioText(ctx context.Context, path string, r *TranscriptionRequest) (string, error) {
+	if r.Model == "" {
+		r.Model = defaultTranscriptionModel
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", r.FileName)
+	if err != nil {
+		return "", fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := part.Write(r.Audio); err != nil {
+		return "", fmt.Errorf("write audio: %w", err)
+	}
+	fields := map[string]string{"model": r.Model, "language": r.Language, "prompt": r.Prompt}
+	for _, name := range []string{"model", "language", "prompt"} {
+		if fields[name] == "" {
+			continue
+		}
+		if err := w.WriteField(name, fields[name]); err != nil {
+			return "", fmt.Errorf("write field %s: %w", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("close form: %w", err)
+	}
+
+	resp, err := c.postAudio(ctx, path, w.FormDataContentType(), &body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var response transcriptionResponsePayload
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	return response.Text, nil
+}
+
+// CreateSpeech synthesizes speech for the input of the request and returns the
+// audio, an mp3 file unless another response format is requested.
+func (c *Client) CreateSpeech(ctx context.Context, r *SpeechRequest) ([]byte, error) {
+	if r.Model == "" {
+		r.Model = defaultSpeechModel
+	}
+	if r.Voice == "" {
+		r.Voice = defaultSpeechVoice
+	}
+	payloadBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	resp, err := c.postAudio(ctx, "/audio/speech", "application/json", bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if len(audio) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return audio, nil
+}
+
+// postAudio posts the body to an audio endpoint and returns the response if
+// its status is OK.
+func (c *Client) postAudio(ctx context.Context, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL(path), body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", contentType)
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		defer r.Body.Close()
+		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
+
+		var errResp errorMessage
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return nil, errors.New(msg) // nolint:goerr113
+		}
+
+		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+	}
+	return r, nil
+}
@@ -36,6 +36,15 @@ type ChatRequest struct {
 	// If a specific function should be invoked, use the format:
 	// `{"name": "my_function"}`
 	FunctionCallBehavior FunctionCallBehavior `json:"function_call,omitempty"`
+	// ForcedFunctionCall is the name of the function the model must call,
+	// sent as function_call={"name": ...} in place of FunctionCallBehavior.
+	ForcedFunctionCall string `json:"-"`
+
+	// Tools are the tools the model may call, sent in place of Functions.
+	Tools []Tool `json:"tools,omitempty"`
+	// ToolChoice is "auto", "none" or {"type": "function", "function":
+	// {"name": ...}} to force the call of a tool.
+	ToolChoice any `json:"tool_choice,omitempty"`
 
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
@@ -54,6 +63,45 @@ type ChatMessage struct {
 
 	// FunctionCall represents a function call to be made in the message.
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+
+	// ToolCalls are the tool calls made in an assistant message.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID is the id of the tool call a tool message is the result of.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+
+	// MultiContent is sent as the content in place of Content if not empty,
+	// for user messages with images given to vision models.
+	MultiContent []ContentPart `json:"-"`
+}
+
+// MarshalJSON encodes the message, with its parts as the content if it has
+// any.
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	type message ChatMessage
+	if len(m.MultiContent) == 0 {
+		return json.Marshal(message(m))
+	}
+	return json.Marshal(struct {
+		message
+		Content []ContentPart `json:"content"`
+	}{message: message(m), Content: m.MultiContent})
+}
+
+// ContentPart is a part of the content of a message.
+type ContentPart struct {
+	// Type is "text" or "image_url".
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL is the url of an image given to a vision model, which may be a
+// data url with the base64 encoded image.
+type ImageURL struct {
+	URL string `json:"url"`
+	// Detail is the resolution the model looks at the image at: low, high or
+	// auto.
+	Detail string `json:"detail,omitempty"`
 }
 
 // ChatChoice is a choice in a chat response.
@@ -110,6 +158,14 @@ type FunctionDefinition struct {
 	Parameters any `json:"parameters"`
 }
 
+// Tool is a tool that can be called by the model.
+type Tool struct {
+	// Type is the type of the tool, only "function" is supported.
+	Type string `json:"type"`
+	// Function is the definition of the function.
+	Function FunctionDefinition `json:"function"`
+}
+
 // FunctionCallBehavior is the behavior to use when calling functions.
 type FunctionCallBehavior string
 
@@ -130,21 +186,28 @@ type FunctionCall struct {
 	Arguments any `json:"arguments"`
 }
 
+// ToolCall is a call to a tool.
+type ToolCall struct {
+	// ID is the id of the call.
+	ID string `json:"id"`
+	// Type is the type of the tool, only "function" is supported.
+	Type string `json:"type"`
+	// Function is the function to call.
+	Function FunctionCall `json:"function"`
+}
+
 func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatResponse, error) {
 	if payload.StreamingFunc != nil {
 		payload.Stream = true
 	}
 	// Build request payload
-	payloadBytes, err := json.Marshal(payload)
+	payloadBytes, err := marshalChatRequest(payload)
 	if err != nil {
 		return nil, err
 	}
 
 	// Build request
 	body := bytes.NewReader(payloadBytes)
-	if c.baseURL == "" {
-		c.baseURL = defaultBaseURL
-	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/chat/completions"), body)
 	if err != nil {
 		return nil, err
@@ -179,6 +242,21 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatRes
 	return &response, json.NewDecoder(r.Body).Decode(&response)
 }
 
+// marshalChatRequest marshals the request, with its function_call set to the
+// name of its forced function call if any.
+func marshalChatRequest(payload *ChatRequest) ([]byte, error) {
+	if payload.ForcedFunctionCall == "" {
+		return json.Marshal(payload)
+	}
+	return json.Marshal(struct {
+		*ChatRequest
+		FunctionCall map[string]string `json:"function_call"`
+	}{
+		ChatRequest:  payload,
+		FunctionCall: map[string]string{"name": payload.ForcedFunctionCall},
+	})
+}
+
 func parseStreamingChatResponse(ctx context.Context, r *http.Response, payload *ChatRequest) (*ChatResponse, error) {
 	scanner := bufio.NewScanner(r.Body)
 	responseChan := make(chan StreamedChatResponsePayload)
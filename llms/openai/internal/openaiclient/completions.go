@@ -83,10 +83,6 @@ func (c *Client) createCompletion(ctx context.Context, payload *completionPayloa
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	if c.baseURL == "" {
-		c.baseURL = defaultBaseURL
-	}
-
 	// Build request
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/completions"), bytes.NewReader(payloadBytes))
 	if err != nil {
@@ -18,12 +18,14 @@ type embeddingPayload struct {
 	Input []string `json:"input"`
 }
 
-type embeddingResponsePayload struct {
+// embeddingResponsePayload is the response of the embeddings API, with the
+// embeddings decoded as float64 or, to save memory, as float32.
+type embeddingResponsePayload[T float32 | float64] struct {
 	Object string `json:"object"`
 	Data   []struct {
-		Object    string    `json:"object"`
-		Embedding []float64 `json:"embedding"`
-		Index     int       `json:"index"`
+		Object    string `json:"object"`
+		Embedding []T    `json:"embedding"`
+		Index     int    `json:"index"`
 	} `json:"data"`
 	Model string `json:"model"`
 	Usage struct {
@@ -33,24 +35,21 @@ type embeddingResponsePayload struct {
 }
 
 // nolint:lll
-func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload) (*embeddingResponsePayload, error) {
+func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload, response any) error {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("marshal payload: %w", err)
-	}
-	if c.baseURL == "" {
-		c.baseURL = defaultBaseURL
+		return fmt.Errorf("marshal payload: %w", err)
 	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/embeddings"), bytes.NewReader(payloadBytes))
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return fmt.Errorf("create request: %w", err)
 	}
 
 	c.setHeaders(req)
 
 	r, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("send request: %w", err)
+		return fmt.Errorf("send request: %w", err)
 	}
 	defer r.Body.Close()
 
@@ -61,17 +60,15 @@ func (c *Client) createEmbedding(ctx context.Context, payload *embeddingPayload)
 		// status code.
 		var errResp errorMessage
 		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg) // nolint:goerr113
+			return errors.New(msg) // nolint:goerr113
 		}
 
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+		return fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
 	}
 
-	var response embeddingResponsePayload
-
-	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := json.NewDecoder(r.Body).Decode(response); err != nil {
+		return fmt.Errorf("decode response: %w", err)
 	}
 
-	return &response, nil
+	return nil
 }
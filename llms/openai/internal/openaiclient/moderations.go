@@ -0,0 +1,86 @@
+package openaiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type moderationPayload struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+type moderationResponsePayload struct {
+	ID      string             `json:"id"`
+	Model   string             `json:"model"`
+	Results []ModerationResult `json:"results"`
+}
+
+// ModerationRequest is a request to classify texts with the moderation model.
+type ModerationRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// ModerationResult is the classification of a text by the moderation model.
+type ModerationResult struct {
+	Flagged        bool               `json:"flagged"`
+	Categories     map[string]bool    `json:"categories"`
+	CategoryScores map[string]float64 `json:"category_scores"`
+}
+
+// CreateModeration classifies the texts with the moderation model.
+func (c *Client) CreateModeration(ctx context.Context, r *ModerationRequest) ([]ModerationResult, error) {
+	resp, err := c.createModeration(ctx, &moderationPayload{
+		Model: r.Model,
+		Input: r.Input,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return resp.Results, nil
+}
+
+// nolint:lll
+func (c *Client) createModeration(ctx context.Context, payload *moderationPayload) (*moderationResponsePayload, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildURL("/moderations"), bytes.NewReader(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
+
+		var errResp errorMessage
+		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+			return nil, errors.New(msg) // nolint:goerr113
+		}
+
+		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+	}
+
+	var response moderationResponsePayload
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &response, nil
+}
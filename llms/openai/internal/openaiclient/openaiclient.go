@@ -65,6 +65,11 @@ func New(token string, model string, baseURL string, organization string,
 			return nil, err
 		}
 	}
+	// The client is shared by concurrent calls: its fields are set once here
+	// and only read by requests.
+	if c.baseURL == "" {
+		c.baseURL = defaultBaseURL
+	}
 
 	return c, nil
 }
@@ -123,10 +128,11 @@ func (c *Client) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]
 		r.Model = defaultEmbeddingModel
 	}
 
-	resp, err := c.createEmbedding(ctx, &embeddingPayload{
+	var resp embeddingResponsePayload[float64]
+	err := c.createEmbedding(ctx, &embeddingPayload{
 		Model: r.Model,
 		Input: r.Input,
-	})
+	}, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +149,34 @@ func (c *Client) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]
 	return embeddings, nil
 }
 
+// CreateEmbeddingFloat32 creates embeddings decoded as float32, without
+// converting them from float64.
+func (c *Client) CreateEmbeddingFloat32(ctx context.Context, r *EmbeddingRequest) ([][]float32, error) {
+	if r.Model == "" {
+		r.Model = defaultEmbeddingModel
+	}
+
+	var resp embeddingResponsePayload[float32]
+	err := c.createEmbedding(ctx, &embeddingPayload{
+		Model: r.Model,
+		Input: r.Input,
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Data) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	embeddings := make([][]float32, 0, len(resp.Data))
+	for i := 0; i < len(resp.Data); i++ {
+		embeddings = append(embeddings, resp.Data[i].Embedding)
+	}
+
+	return embeddings, nil
+}
+
 // CreateChat creates chat request.
 func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatResponse, error) {
 	if r.Model == "" {
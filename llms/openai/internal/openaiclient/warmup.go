@@ -0,0 +1,36 @@
+package openaiclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Warmup lists the models of the API, which opens a connection to the API
+// kept by the http client for the next requests and checks the credentials.
+func (c *Client) Warmup(ctx context.Context) error {
+	url := c.buildURL("/models")
+	if IsAzure(c.apiType) {
+		url = fmt.Sprintf("%s/openai/models?api-version=%s", strings.TrimRight(c.baseURL, "/"), c.apiVersion)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer r.Body.Close()
+	// The body is read for the connection to be reused.
+	_, _ = io.Copy(io.Discard, r.Body)
+
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned unexpected status code: %d", r.StatusCode) // nolint:goerr113
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
+)
+
+const _defaultKeyCooldown = time.Minute
+
+// KeyUsage is the usage of an API key of a KeyRotator.
+type KeyUsage struct {
+	// Key is the key with all but its last four characters masked.
+	Key string
+	// Requests is the number of requests sent with the key.
+	Requests int
+	// RateLimited is the number of requests rejected with a 429 status, for
+	// rate limit or quota errors.
+	RateLimited int
+	// The tokens used by the requests, read from the responses that are not
+	// streamed.
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// KeyRotator spreads the requests of clients over several API keys, such as
+// the keys of several organizations. The keys are used in turn, and a request
+// rejected with a 429 status is sent again with the next key, the rejected key
+// being skipped until its cooldown is over.
+type KeyRotator struct {
+	keys     []string
+	cooldown time.Duration
+
+	mu           sync.Mutex
+	next         int
+	limitedUntil []time.Time
+	usage        []KeyUsage
+}
+
+// KeyRotatorOption is a function that configures a KeyRotator.
+type KeyRotatorOption func(*KeyRotator)
+
+// WithKeyCooldown sets how long a key rejected with a 429 status is skipped
+// when the response has no Retry-After header, one minute by default.
+func WithKeyCooldown(cooldown time.Duration) KeyRotatorOption {
+	return func(r *KeyRotator) {
+		r.cooldown = cooldown
+	}
+}
+
+// NewKeyRotator creates a rotator of the keys, given to clients with
+// WithKeyRotator.
+func NewKeyRotator(keys []string, opts ...KeyRotatorOption) *KeyRotator {
+	r := &KeyRotator{
+		keys:         keys,
+		cooldown:     _defaultKeyCooldown,
+		limitedUntil: make([]time.Time, len(keys)),
+		usage:        make([]KeyUsage, len(keys)),
+	}
+	for i, key := range keys {
+		r.usage[i].Key = maskKey(key)
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Usage returns the usage of each key, in the order of the keys.
+func (r *KeyRotator) Usage() []KeyUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]KeyUsage(nil), r.usage...)
+}
+
+// doer returns a Doer sending the requests with d and the keys of the rotator.
+func (r *KeyRotator) doer(d openaiclient.Doer) openaiclient.Doer { //nolint:ireturn
+	return rotatingDoer{rotator: r, d: d}
+}
+
+// pick returns the next key not cooling down, or the one whose cooldown ends
+// first if all are, and counts the request.
+func (r *KeyRotator) pick() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	picked := -1
+	for n := 0; n < len(r.keys); n++ {
+		i := (r.next + n) % len(r.keys)
+		if !r.limitedUntil[i].After(now) {
+			picked = i
+			break
+		}
+		if picked < 0 || r.limitedUntil[i].Before(r.limitedUntil[picked]) {
+			picked = i
+		}
+	}
+	r.next = (picked + 1) % len(r.keys)
+	r.usage[picked].Requests++
+	return picked
+}
+
+func (r *KeyRotator) rateLimited(i int, retryAfter time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if retryAfter <= 0 {
+		retryAfter = r.cooldown
+	}
+	r.limitedUntil[i] = time.Now().Add(retryAfter)
+	r.usage[i].RateLimited++
+}
+
+func (r *KeyRotator) addTokens(i, prompt, completion, total int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usage[i].PromptTokens += prompt
+	r.usage[i].CompletionTokens += completion
+	r.usage[i].TotalTokens += total
+}
+
+type rotatingDoer struct {
+	rotator *KeyRotator
+	d       openaiclient.Doer
+}
+
+func (d rotatingDoer) Do(req *http.Request) (*http.Response, error) {
+	r := d.rotator
+	if len(r.keys) == 0 {
+		return d.d.Do(req)
+	}
+	for attempt := 0; ; attempt++ {
+		attemptReq := req.Clone(req.Context())
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+		i := r.pick()
+		setKey(attemptReq, r.keys[i])
+
+		resp, err := d.d.Do(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			r.rateLimited(i, retryAfter(resp))
+			if attempt+1 < len(r.keys) && (req.Body == nil || req.GetBody != nil) {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				continue
+			}
+			return resp, nil
+		}
+		if resp.StatusCode == http.StatusOK && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+			if err := readUsage(resp, r, i); err != nil {
+				return nil, err
+			}
+		}
+		return resp, nil
+	}
+}
+
+// setKey sets the key in the header the client authenticates with.
+func setKey(req *http.Request, key string) {
+	if req.Header.Get("api-key") != "" {
+		req.Header.Set("api-key", key)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+}
+
+// readUsage adds the usage of the JSON body of the response to the usage of
+// the key, leaving the body unread for the client.
+func readUsage(resp *http.Response, r *KeyRotator, i int) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+			TotalTokens      int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		r.addTokens(i, payload.Usage.PromptTokens, payload.Usage.CompletionTokens, payload.Usage.TotalTokens)
+	}
+	return nil
+}
+
+// retryAfter returns the delay of the Retry-After header of the response, in
+// seconds, or zero if it has none.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", len(key)-4) + key[len(key)-4:]
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
 )
 
@@ -35,10 +36,16 @@ func newClient(opts ...Option) (*openaiclient.Client, error) {
 		options.apiVersion = DefaultAPIVersion
 	}
 
+	httpClient := llms.HookDoer(options.httpClient)
+	if r := options.keyRotator; r != nil && len(r.keys) > 0 {
+		options.token = r.keys[0]
+		httpClient = r.doer(httpClient)
+	}
+
 	if len(options.token) == 0 {
 		return nil, ErrMissingToken
 	}
 
 	return openaiclient.New(options.token, options.model, options.baseURL, options.organization,
-		openaiclient.APIType(options.apiType), options.apiVersion, options.httpClient)
+		openaiclient.APIType(options.apiType), options.apiVersion, httpClient)
 }
@@ -0,0 +1,33 @@
+package openai
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
+)
+
+// ModerationResult is the classification of a text by the moderation model.
+type ModerationResult = openaiclient.ModerationResult
+
+// Moderate classifies the texts with the moderation model, returning a
+// result per text.
+func (o *LLM) Moderate(ctx context.Context, inputTexts []string) ([]ModerationResult, error) {
+	return moderate(ctx, o.client, inputTexts)
+}
+
+// Moderate classifies the texts with the moderation model, returning a
+// result per text.
+func (o *Chat) Moderate(ctx context.Context, inputTexts []string) ([]ModerationResult, error) {
+	return moderate(ctx, o.client, inputTexts)
+}
+
+func moderate(ctx context.Context, client *openaiclient.Client, inputTexts []string) ([]ModerationResult, error) {
+	results, err := client.CreateModeration(ctx, &openaiclient.ModerationRequest{Input: inputTexts})
+	if err != nil {
+		return nil, err
+	}
+	if len(inputTexts) != len(results) {
+		return results, ErrUnexpectedResponseLength
+	}
+	return results, nil
+}
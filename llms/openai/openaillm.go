@@ -9,19 +9,23 @@ import (
 )
 
 type LLM struct {
-	client *openaiclient.Client
+	client       *openaiclient.Client
+	rateLimiters *llms.RateLimiters
 }
 
 var (
 	_ llms.LLM           = (*LLM)(nil)
 	_ llms.LanguageModel = (*LLM)(nil)
+	_ llms.ContextSizer  = (*LLM)(nil)
+	_ llms.Warmer        = (*LLM)(nil)
 )
 
 // New returns a new OpenAI LLM.
 func New(opts ...Option) (*LLM, error) {
 	c, err := newClient(opts...)
 	return &LLM{
-		client: c,
+		client:       c,
+		rateLimiters: &llms.RateLimiters{},
 	}, err
 }
 
@@ -42,9 +46,19 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 	for _, opt := range options {
 		opt(&opts)
 	}
+	ctx, cancel, _ := llms.ApplyLatencyBudget(ctx, &opts)
+	defer cancel()
+	ctx = llms.ApplyTransportHook(ctx, opts)
 
 	generations := make([]*llms.Generation, 0, len(prompts))
 	for _, prompt := range prompts {
+		prompt, err := llms.ValidatePrompt(ctx, o.model(opts), prompt, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := llms.ApplyRateLimit(ctx, o.rateLimiters, o.model(opts), opts, prompt); err != nil {
+			return nil, err
+		}
 		result, err := o.client.CreateCompletion(ctx, &openaiclient.CompletionRequest{
 			Model:            opts.Model,
 			Prompt:           prompt,
@@ -67,6 +81,14 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 	return generations, nil
 }
 
+// model returns the model used for a call.
+func (o *LLM) model(opts llms.CallOptions) string {
+	if opts.Model != "" {
+		return opts.Model
+	}
+	return o.client.Model
+}
+
 func (o *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
 	return llms.GeneratePrompt(ctx, o, promptValues, options...)
 }
@@ -75,6 +97,17 @@ func (o *LLM) GetNumTokens(text string) int {
 	return llms.CountTokens(o.client.Model, text)
 }
 
+func (o *LLM) GetContextSize() int {
+	return llms.GetModelContextSize(o.client.Model)
+}
+
+// Warmup loads the tokenizer of the model and opens a connection to the API,
+// checking the credentials, so that the first call is not slowed down.
+func (o *LLM) Warmup(ctx context.Context) error {
+	o.GetNumTokens("")
+	return o.client.Warmup(ctx)
+}
+
 // CreateEmbedding creates embeddings for the given input texts.
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
 	embeddings, err := o.client.CreateEmbedding(ctx, &openaiclient.EmbeddingRequest{
@@ -91,3 +124,20 @@ func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]flo
 	}
 	return embeddings, nil
 }
+
+// CreateEmbeddingFloat32 creates float32 embeddings for the given input texts.
+func (o *LLM) CreateEmbeddingFloat32(ctx context.Context, inputTexts []string) ([][]float32, error) {
+	embeddings, err := o.client.CreateEmbeddingFloat32(ctx, &openaiclient.EmbeddingRequest{
+		Input: inputTexts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	if len(inputTexts) != len(embeddings) {
+		return embeddings, ErrUnexpectedResponseLength
+	}
+	return embeddings, nil
+}
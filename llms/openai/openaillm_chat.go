@@ -2,7 +2,7 @@ package openai
 
 import (
 	"context"
-	"reflect"
+	"time"
 
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai/internal/openaiclient"
@@ -12,19 +12,23 @@ import (
 type ChatMessage = openaiclient.ChatMessage
 
 type Chat struct {
-	client *openaiclient.Client
+	client       *openaiclient.Client
+	rateLimiters *llms.RateLimiters
 }
 
 var (
 	_ llms.ChatLLM       = (*Chat)(nil)
 	_ llms.LanguageModel = (*Chat)(nil)
+	_ llms.ContextSizer  = (*Chat)(nil)
+	_ llms.Warmer        = (*Chat)(nil)
 )
 
 // NewChat returns a new OpenAI chat LLM.
 func NewChat(opts ...Option) (*Chat, error) {
 	c, err := newClient(opts...)
 	return &Chat{
-		client: c,
+		client:       c,
+		rateLimiters: &llms.RateLimiters{},
 	}, err
 }
 
@@ -45,8 +49,22 @@ func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage,
 	for _, opt := range options {
 		opt(&opts)
 	}
+	if err := llms.ValidateFunctionCall(opts); err != nil {
+		return nil, err
+	}
+	trim := llms.ApplyTrimToBoundary(&opts)
+	ctx, cancel, budget := llms.ApplyLatencyBudget(ctx, &opts)
+	defer cancel()
+	ctx = llms.ApplyTransportHook(ctx, opts)
 	generations := make([]*llms.Generation, 0, len(messageSets))
 	for _, messageSet := range messageSets {
+		messageSet, err := llms.ValidateMessages(ctx, o.model(opts), messageSet, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := llms.ApplyChatRateLimit(ctx, o.rateLimiters, o.model(opts), opts, messageSet); err != nil {
+			return nil, err
+		}
 		msgs := make([]*openaiclient.ChatMessage, len(messageSet))
 		for i, m := range messageSet {
 			msg := &openaiclient.ChatMessage{
@@ -64,10 +82,14 @@ func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage,
 				msg.Role = "user"
 			case schema.ChatMessageTypeFunction:
 				msg.Role = "function"
+			case schema.ChatMessageTypeTool:
+				msg.Role = "tool"
 			}
 			if n, ok := m.(schema.Named); ok {
 				msg.Name = n.GetName()
 			}
+			setToolFields(msg, m)
+			setContentParts(msg, m)
 			msgs[i] = msg
 		}
 		req := &openaiclient.ChatRequest{
@@ -80,50 +102,164 @@ func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage,
 			N:                opts.N,
 			FrequencyPenalty: opts.FrequencyPenalty,
 			PresencePenalty:  opts.PresencePenalty,
-
-			FunctionCallBehavior: openaiclient.FunctionCallBehavior(opts.FunctionCallBehavior),
-		}
-		for _, fn := range opts.Functions {
-			req.Functions = append(req.Functions, openaiclient.FunctionDefinition{
-				Name:        fn.Name,
-				Description: fn.Description,
-				Parameters:  fn.Parameters,
-			})
 		}
+		setFunctions(req, opts)
+		budget.Reset()
+		start := time.Now()
 		result, err := o.client.CreateChat(ctx, req)
+		if partial := budget.Partial(err); partial != nil {
+			generations = append(generations, partial)
+			break
+		}
 		if err != nil {
 			return nil, err
 		}
 		if len(result.Choices) == 0 {
 			return nil, ErrEmptyResponse
 		}
-		generationInfo := make(map[string]any, reflect.ValueOf(result.Usage).NumField())
-		generationInfo["CompletionTokens"] = result.Usage.CompletionTokens
-		generationInfo["PromptTokens"] = result.Usage.PromptTokens
-		generationInfo["TotalTokens"] = result.Usage.TotalTokens
+		metadata := llms.NewResponseMetadata(result.Model, result.Choices[0].FinishReason, llms.Usage{
+			PromptTokens:     int(result.Usage.PromptTokens),
+			CompletionTokens: int(result.Usage.CompletionTokens),
+			TotalTokens:      int(result.Usage.TotalTokens),
+		}, time.Since(start), result)
 		msg := &schema.AIChatMessage{
 			Content: result.Choices[0].Message.Content,
 		}
-		if result.Choices[0].FinishReason == "function_call" {
+		if result.Choices[0].Message.FunctionCall != nil {
 			msg.FunctionCall = &schema.FunctionCall{
 				Name:      result.Choices[0].Message.FunctionCall.Name,
 				Arguments: result.Choices[0].Message.FunctionCall.Arguments,
 			}
 		}
-		generations = append(generations, &llms.Generation{
+		for _, tc := range result.Choices[0].Message.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+				ID:   tc.ID,
+				Type: tc.Type,
+				FunctionCall: &schema.FunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+			})
+		}
+		generation := &llms.Generation{
 			Message:        msg,
 			Text:           msg.Content,
-			GenerationInfo: generationInfo,
-		})
+			GenerationInfo: metadata.GenerationInfo(),
+			Metadata:       metadata,
+		}
+		trim.Trim(generation)
+		generations = append(generations, generation)
 	}
 
 	return generations, nil
 }
 
+// setFunctions sets the functions of the request, as tools if the options
+// say so, with the behavior or forced call of the options.
+func setFunctions(req *openaiclient.ChatRequest, opts llms.CallOptions) {
+	for _, fn := range opts.Functions {
+		def := openaiclient.FunctionDefinition{
+			Name:        fn.Name,
+			Description: fn.Description,
+			Parameters:  fn.Parameters,
+		}
+		if opts.FunctionsAsTools {
+			req.Tools = append(req.Tools, openaiclient.Tool{Type: "function", Function: def})
+		} else {
+			req.Functions = append(req.Functions, def)
+		}
+	}
+	if opts.FunctionsAsTools {
+		if len(req.Tools) > 0 {
+			req.ToolChoice = llms.ToolChoice(opts)
+		}
+		return
+	}
+	req.FunctionCallBehavior = openaiclient.FunctionCallBehavior(opts.FunctionCallBehavior)
+	req.ForcedFunctionCall = opts.ForcedFunctionCall
+}
+
+// setToolFields sets the function and tool calls of assistant messages and the
+// tool call id of tool messages, so that tool conversations round-trip.
+func setToolFields(msg *openaiclient.ChatMessage, m schema.ChatMessage) {
+	if p, ok := m.(*schema.AIChatMessage); ok {
+		m = *p
+	}
+	switch m := m.(type) {
+	case schema.AIChatMessage:
+		if m.FunctionCall != nil {
+			msg.FunctionCall = &openaiclient.FunctionCall{
+				Name:      m.FunctionCall.Name,
+				Arguments: llms.FunctionArguments(m.FunctionCall.Arguments),
+			}
+		}
+		for _, tc := range m.ToolCalls {
+			call := openaiclient.ToolCall{ID: tc.ID, Type: tc.Type}
+			if tc.FunctionCall != nil {
+				call.Function = openaiclient.FunctionCall{
+					Name:      tc.FunctionCall.Name,
+					Arguments: llms.FunctionArguments(tc.FunctionCall.Arguments),
+				}
+			}
+			msg.ToolCalls = append(msg.ToolCalls, call)
+		}
+	case schema.ToolChatMessage:
+		msg.ToolCallID = m.ID
+	}
+}
+
+// setContentParts sends the parts of the content of messages having parts
+// other than their text, such as images for vision models.
+func setContentParts(msg *openaiclient.ChatMessage, m schema.ChatMessage) {
+	mp, ok := m.(schema.MultiPartMessage)
+	if !ok {
+		return
+	}
+	parts := mp.GetContentParts()
+	if len(parts) == 0 || len(parts) == 1 && parts[0].Type == schema.ContentPartTypeText {
+		return
+	}
+	for _, p := range parts {
+		switch p.Type {
+		case schema.ContentPartTypeText:
+			msg.MultiContent = append(msg.MultiContent, openaiclient.ContentPart{Type: "text", Text: p.Text})
+		case schema.ContentPartTypeImageURL:
+			msg.MultiContent = append(msg.MultiContent, openaiclient.ContentPart{
+				Type:     "image_url",
+				ImageURL: &openaiclient.ImageURL{URL: p.URL, Detail: string(p.Detail)},
+			})
+		case schema.ContentPartTypeImageData:
+			msg.MultiContent = append(msg.MultiContent, openaiclient.ContentPart{
+				Type:     "image_url",
+				ImageURL: &openaiclient.ImageURL{URL: p.DataURL(), Detail: string(p.Detail)},
+			})
+		}
+	}
+}
+
+// model returns the model used for a call.
+func (o *Chat) model(opts llms.CallOptions) string {
+	if opts.Model != "" {
+		return opts.Model
+	}
+	return o.client.Model
+}
+
 func (o *Chat) GetNumTokens(text string) int {
 	return llms.CountTokens(o.client.Model, text)
 }
 
+func (o *Chat) GetContextSize() int {
+	return llms.GetModelContextSize(o.client.Model)
+}
+
+// Warmup loads the tokenizer of the model and opens a connection to the API,
+// checking the credentials, so that the first call is not slowed down.
+func (o *Chat) Warmup(ctx context.Context) error {
+	o.GetNumTokens("")
+	return o.client.Warmup(ctx)
+}
+
 func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
 	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
 }
@@ -144,3 +280,20 @@ func (o *Chat) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]fl
 	}
 	return embeddings, nil
 }
+
+// CreateEmbeddingFloat32 creates float32 embeddings for the given input texts.
+func (o *Chat) CreateEmbeddingFloat32(ctx context.Context, inputTexts []string) ([][]float32, error) {
+	embeddings, err := o.client.CreateEmbeddingFloat32(ctx, &openaiclient.EmbeddingRequest{
+		Input: inputTexts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	if len(inputTexts) != len(embeddings) {
+		return embeddings, ErrUnexpectedResponseLength
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,80 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// newChatServer returns a server answering chat requests with the response,
+// and the last request it received.
+func newChatServer(t *testing.T, response string) (*httptest.Server, *map[string]any) {
+	t.Helper()
+	var req map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = nil
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &req
+}
+
+func TestChatForcedFunctionCall(t *testing.T) {
+	t.Parallel()
+
+	// A forced call ends with "stop", not "function_call".
+	srv, req := newChatServer(t, `{"model":"gpt-4","choices":[{"finish_reason":"stop","message":`+
+		`{"role":"assistant","content":"","function_call":{"name":"extract","arguments":"{\"a\":1}"}}}]}`)
+	chat, err := NewChat(WithToken("token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "hi"}},
+		llms.WithFunctions([]llms.FunctionDefinition{{Name: "extract", Parameters: map[string]any{}}}),
+		llms.WithForcedFunctionCall("extract"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "extract"}, (*req)["function_call"])
+	require.NotNil(t, msg.FunctionCall)
+	require.Equal(t, "extract", msg.FunctionCall.Name)
+	require.Equal(t, `{"a":1}`, msg.FunctionCall.Arguments)
+}
+
+func TestChatFunctionsAsTools(t *testing.T) {
+	t.Parallel()
+
+	srv, req := newChatServer(t, `{"model":"gpt-4","choices":[{"finish_reason":"stop","message":`+
+		`{"role":"assistant","content":"","tool_calls":[{"id":"call_1","type":"function",`+
+		`"function":{"name":"extract","arguments":"{}"}}]}}]}`)
+	chat, err := NewChat(WithToken("token"), WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "hi"}}
+	functions := llms.WithFunctions([]llms.FunctionDefinition{{Name: "extract", Parameters: map[string]any{}}})
+
+	msg, err := chat.Call(context.Background(), messages, functions,
+		llms.WithFunctionsAsTools(), llms.WithForcedFunctionCall("extract"))
+	require.NoError(t, err)
+	require.NotContains(t, *req, "functions")
+	require.NotContains(t, *req, "function_call")
+	require.Equal(t, []any{map[string]any{
+		"type":     "function",
+		"function": map[string]any{"name": "extract", "description": "", "parameters": map[string]any{}},
+	}}, (*req)["tools"])
+	require.Equal(t, map[string]any{"type": "function", "function": map[string]any{"name": "extract"}},
+		(*req)["tool_choice"])
+	require.Len(t, msg.ToolCalls, 1)
+	require.Equal(t, "extract", msg.ToolCalls[0].FunctionCall.Name)
+
+	_, err = chat.Call(context.Background(), messages, functions,
+		llms.WithFunctionsAsTools(), llms.WithFunctionCallBehavior(llms.FunctionCallBehaviorNone))
+	require.NoError(t, err)
+	require.Equal(t, "none", (*req)["tool_choice"])
+}
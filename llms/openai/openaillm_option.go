@@ -31,6 +31,7 @@ type options struct {
 	apiVersion string // required when APIType is APITypeAzure or APITypeAzureAD
 
 	httpClient openaiclient.Doer
+	keyRotator *KeyRotator
 }
 
 type Option func(*options)
@@ -91,3 +92,11 @@ func WithHTTPClient(client openaiclient.Doer) Option {
 		opts.httpClient = client
 	}
 }
+
+// WithKeyRotator spreads the requests of the client over the keys of the
+// rotator, which replace the token. Rotators can be shared by clients.
+func WithKeyRotator(rotator *KeyRotator) Option {
+	return func(opts *options) {
+		opts.keyRotator = rotator
+	}
+}
@@ -0,0 +1,239 @@
+package compatclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrEmptyResponse is returned when the server returns an empty response.
+var ErrEmptyResponse = errors.New("empty response")
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client is a client for servers implementing the OpenAI chat completions API.
+type Client struct {
+	baseURL    string
+	token      string
+	Model      string
+	httpClient Doer
+}
+
+// New returns a new client for the server at baseURL. The token is optional,
+// many self-hosted servers do not require one.
+func New(baseURL, token, model string, httpClient Doer) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		Model:      model,
+		httpClient: httpClient,
+	}
+}
+
+// ChatMessage is a message in a chat request.
+type ChatMessage struct {
+	Role         string        `json:"role"`
+	Content      string        `json:"content"`
+	Name         string        `json:"name,omitempty"`
+	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
+	ToolCallID   string        `json:"tool_call_id,omitempty"`
+}
+
+// FunctionDefinition is a definition of a function that can be called by the model.
+type FunctionDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Parameters  any    `json:"parameters"`
+}
+
+// Tool is a tool that can be called by the model.
+type Tool struct {
+	Type     string             `json:"type"`
+	Function FunctionDefinition `json:"function"`
+}
+
+// FunctionCall is a call to a function.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ToolCall is a call to a tool.
+type ToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// ChatRequest is a request to create a chat completion. Fields left to their
+// zero value are not sent to the server.
+type ChatRequest struct {
+	Model            string         `json:"model"`
+	Messages         []*ChatMessage `json:"messages"`
+	Temperature      float64        `json:"temperature,omitempty"`
+	TopP             float64        `json:"top_p,omitempty"`
+	MaxTokens        int            `json:"max_tokens,omitempty"`
+	N                int            `json:"n,omitempty"`
+	StopWords        []string       `json:"stop,omitempty"`
+	Stream           bool           `json:"stream,omitempty"`
+	FrequencyPenalty float64        `json:"frequency_penalty,omitempty"`
+	PresencePenalty  float64        `json:"presence_penalty,omitempty"`
+	Seed             int            `json:"seed,omitempty"`
+	Logprobs         bool           `json:"logprobs,omitempty"`
+
+	Functions []FunctionDefinition `json:"functions,omitempty"`
+	// FunctionCall is "auto", "none" or {"name": ...} to force the call of a
+	// function.
+	FunctionCall any `json:"function_call,omitempty"`
+	// Tools are the tools the model may call, sent in place of Functions.
+	Tools []Tool `json:"tools,omitempty"`
+	// ToolChoice is "auto", "none" or {"type": "function", "function":
+	// {"name": ...}} to force the call of a tool.
+	ToolChoice any `json:"tool_choice,omitempty"`
+
+	// StreamingFunc is a function to be called for each chunk of a streaming response.
+	// Return an error to stop streaming early.
+	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
+}
+
+// ChatChoice is a choice in a chat response.
+type ChatChoice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+	Logprobs     any         `json:"logprobs,omitempty"`
+}
+
+// ChatUsage is the usage of a chat completion request.
+type ChatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ChatResponse is a response to a chat request.
+type ChatResponse struct {
+	ID      string        `json:"id,omitempty"`
+	Model   string        `json:"model,omitempty"`
+	Choices []*ChatChoice `json:"choices,omitempty"`
+	Usage   ChatUsage     `json:"usage"`
+}
+
+type streamedChatResponse struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content,omitempty"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason,omitempty"`
+	} `json:"choices"`
+}
+
+type errorMessage struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CreateChat creates a chat completion.
+func (c *Client) CreateChat(ctx context.Context, r *ChatRequest) (*ChatResponse, error) {
+	if r.Model == "" {
+		r.Model = c.Model
+	}
+	r.Stream = r.StreamingFunc != nil
+
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg := fmt.Sprintf("API returned unexpected status code: %d", resp.StatusCode)
+		var errResp errorMessage
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error.Message == "" {
+			return nil, errors.New(msg) // nolint:goerr113
+		}
+		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message) // nolint:goerr113
+	}
+
+	var response *ChatResponse
+	if r.StreamingFunc != nil {
+		response, err = parseStreamingChatResponse(ctx, resp, r)
+	} else {
+		response = &ChatResponse{}
+		err = json.NewDecoder(resp.Body).Decode(response)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Choices) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return response, nil
+}
+
+func parseStreamingChatResponse(ctx context.Context, r *http.Response, payload *ChatRequest) (*ChatResponse, error) {
+	choice := &ChatChoice{Message: ChatMessage{Role: "assistant"}}
+	var content strings.Builder
+
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			// Skip blank lines, comments and other server-sent event fields.
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk streamedChatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode stream payload: %w", err)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+		if chunk.Choices[0].FinishReason != "" {
+			choice.FinishReason = chunk.Choices[0].FinishReason
+		}
+		delta := chunk.Choices[0].Delta.Content
+		if delta == "" {
+			continue
+		}
+		content.WriteString(delta)
+		if err := payload.StreamingFunc(ctx, []byte(delta)); err != nil {
+			return nil, fmt.Errorf("streaming func returned an error: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	choice.Message.Content = content.String()
+	return &ChatResponse{Choices: []*ChatChoice{choice}}, nil
+}
@@ -0,0 +1,92 @@
+package openaicompat
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openaicompat/internal/compatclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	ErrEmptyResponse  = errors.New("no response")
+	ErrMissingBaseURL = errors.New("missing the server base url, set it in the OPENAI_COMPAT_BASE_URL environment variable") //nolint:lll
+)
+
+// LLM is a completion LLM for servers speaking the OpenAI chat completions
+// dialect, such as vLLM, LM Studio, LiteLLM or the llama.cpp server. Each prompt
+// is sent as a single user message.
+type LLM struct {
+	chat *Chat
+}
+
+var (
+	_ llms.LLM           = (*LLM)(nil)
+	_ llms.LanguageModel = (*LLM)(nil)
+	_ llms.ContextSizer  = (*LLM)(nil)
+)
+
+// New returns a new OpenAI-compatible LLM.
+func New(opts ...Option) (*LLM, error) {
+	c, err := NewChat(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &LLM{chat: c}, nil
+}
+
+// Call requests a completion for the given prompt.
+func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	r, err := o.Generate(ctx, []string{prompt}, options...)
+	if err != nil {
+		return "", err
+	}
+	if len(r) == 0 {
+		return "", ErrEmptyResponse
+	}
+	return r[0].Text, nil
+}
+
+func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) {
+	messageSets := make([][]schema.ChatMessage, 0, len(prompts))
+	for _, prompt := range prompts {
+		messageSets = append(messageSets, []schema.ChatMessage{schema.HumanChatMessage{Content: prompt}})
+	}
+	return o.chat.Generate(ctx, messageSets, options...)
+}
+
+func (o *LLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GeneratePrompt(ctx, o, promptValues, options...)
+}
+
+func (o *LLM) GetNumTokens(text string) int {
+	return o.chat.GetNumTokens(text)
+}
+
+func (o *LLM) GetContextSize() int {
+	return o.chat.GetContextSize()
+}
+
+// newClient is wrapper for compatclient internal package.
+func newClient(opts ...Option) (*compatclient.Client, Features, error) {
+	options := &options{
+		token:      os.Getenv(tokenEnvVarName),
+		model:      os.Getenv(modelEnvVarName),
+		baseURL:    os.Getenv(baseURLEnvVarName),
+		httpClient: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.baseURL == "" {
+		return nil, Features{}, ErrMissingBaseURL
+	}
+
+	return compatclient.New(options.baseURL, options.token, options.model, llms.HookDoer(options.httpClient)),
+		options.features, nil
+}
@@ -0,0 +1,284 @@
+package openaicompat
+
+import (
+	"context"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openaicompat/internal/compatclient"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Chat is a chat LLM for servers speaking the OpenAI chat completions dialect.
+// Optional request fields are only sent when enabled in the Features of the
+// chat, see WithFeatures.
+type Chat struct {
+	client       *compatclient.Client
+	features     Features
+	rateLimiters *llms.RateLimiters
+}
+
+var (
+	_ llms.ChatLLM       = (*Chat)(nil)
+	_ llms.LanguageModel = (*Chat)(nil)
+	_ llms.ContextSizer  = (*Chat)(nil)
+)
+
+// NewChat returns a new OpenAI-compatible chat LLM.
+func NewChat(opts ...Option) (*Chat, error) {
+	c, features, err := newClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Chat{
+		client:       c,
+		features:     features,
+		rateLimiters: &llms.RateLimiters{},
+	}, nil
+}
+
+// Call requests a chat response for the given messages.
+func (o *Chat) Call(ctx context.Context, messages []schema.ChatMessage, options ...llms.CallOption) (*schema.AIChatMessage, error) { // nolint: lll
+	r, err := o.Generate(ctx, [][]schema.ChatMessage{messages}, options...)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return r[0].Message, nil
+}
+
+// Generate requests a chat response for each of the message sets. When more
+// than one choice is requested with llms.WithN, the generation holds the first
+// choice and the text of all choices is stored in the "Choices" key of the
+// generation info.
+func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage, options ...llms.CallOption) ([]*llms.Generation, error) { // nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if err := llms.ValidateFunctionCall(opts); err != nil {
+		return nil, err
+	}
+	trim := llms.ApplyTrimToBoundary(&opts)
+	ctx, cancel, budget := llms.ApplyLatencyBudget(ctx, &opts)
+	defer cancel()
+	ctx = llms.ApplyTransportHook(ctx, opts)
+
+	generations := make([]*llms.Generation, 0, len(messageSets))
+	for _, messageSet := range messageSets {
+		model := opts.Model
+		if model == "" {
+			model = o.client.Model
+		}
+		messageSet, err := llms.ValidateMessages(ctx, model, messageSet, opts)
+		if err != nil {
+			return nil, err
+		}
+		// Servers without support for n get one request per choice.
+		requests := 1
+		if opts.N > 1 && !o.features.SupportsN {
+			requests = opts.N
+		}
+
+		choices := make([]*compatclient.ChatChoice, 0, requests)
+		responses := make([]*compatclient.ChatResponse, 0, requests)
+		var usage compatclient.ChatUsage
+		start := time.Now()
+		for i := 0; i < requests; i++ {
+			// Each request is built anew, as the client sets its defaults on it.
+			req := o.newRequest(messageSet, opts)
+			if err := llms.ApplyChatRateLimit(ctx, o.rateLimiters, model, opts, messageSet); err != nil {
+				return nil, err
+			}
+			budget.Reset()
+			result, err := o.client.CreateChat(ctx, req)
+			if partial := budget.Partial(err); partial != nil {
+				return append(generations, partial), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			choices = append(choices, result.Choices...)
+			responses = append(responses, result)
+			usage.PromptTokens += result.Usage.PromptTokens
+			usage.CompletionTokens += result.Usage.CompletionTokens
+			usage.TotalTokens += result.Usage.TotalTokens
+		}
+
+		generation := newGeneration(choices, usage, responses, time.Since(start))
+		trim.Trim(generation)
+		generations = append(generations, generation)
+	}
+
+	return generations, nil
+}
+
+// newRequest builds the request for the messages, leaving out the fields for
+// features the server does not support.
+func (o *Chat) newRequest(messages []schema.ChatMessage, opts llms.CallOptions) *compatclient.ChatRequest {
+	msgs := make([]*compatclient.ChatMessage, len(messages))
+	for i, m := range messages {
+		msg := &compatclient.ChatMessage{
+			Content: m.GetContent(),
+		}
+		switch m.GetType() {
+		case schema.ChatMessageTypeSystem:
+			msg.Role = "system"
+		case schema.ChatMessageTypeAI:
+			msg.Role = "assistant"
+		case schema.ChatMessageTypeHuman, schema.ChatMessageTypeGeneric:
+			msg.Role = "user"
+		case schema.ChatMessageTypeFunction:
+			msg.Role = "function"
+		case schema.ChatMessageTypeTool:
+			msg.Role = "tool"
+		}
+		if n, ok := m.(schema.Named); ok {
+			msg.Name = n.GetName()
+		}
+		setToolFields(msg, m)
+		msgs[i] = msg
+	}
+
+	req := &compatclient.ChatRequest{
+		Model:            opts.Model,
+		Messages:         msgs,
+		Temperature:      opts.Temperature,
+		TopP:             opts.TopP,
+		MaxTokens:        opts.MaxTokens,
+		StopWords:        opts.StopWords,
+		FrequencyPenalty: opts.FrequencyPenalty,
+		PresencePenalty:  opts.PresencePenalty,
+		Seed:             opts.Seed,
+		StreamingFunc:    opts.StreamingFunc,
+	}
+	if o.features.SupportsN && opts.N > 1 {
+		req.N = opts.N
+	}
+	if o.features.SupportsLogprobs {
+		req.Logprobs = true
+	}
+	if o.features.SupportsFunctions {
+		for _, fn := range opts.Functions {
+			def := compatclient.FunctionDefinition{
+				Name:        fn.Name,
+				Description: fn.Description,
+				Parameters:  fn.Parameters,
+			}
+			if opts.FunctionsAsTools {
+				req.Tools = append(req.Tools, compatclient.Tool{Type: "function", Function: def})
+			} else {
+				req.Functions = append(req.Functions, def)
+			}
+		}
+		switch {
+		case len(req.Tools) > 0:
+			req.ToolChoice = llms.ToolChoice(opts)
+		case len(req.Functions) == 0:
+		case opts.ForcedFunctionCall != "":
+			req.FunctionCall = map[string]string{"name": opts.ForcedFunctionCall}
+		case opts.FunctionCallBehavior != "":
+			req.FunctionCall = string(opts.FunctionCallBehavior)
+		default:
+			req.FunctionCall = string(llms.FunctionCallBehaviorAuto)
+		}
+	}
+	return req
+}
+
+// setToolFields sets the function and tool calls of assistant messages and the
+// tool call id of tool messages, so that tool conversations round-trip.
+func setToolFields(msg *compatclient.ChatMessage, m schema.ChatMessage) {
+	if p, ok := m.(*schema.AIChatMessage); ok {
+		m = *p
+	}
+	switch m := m.(type) {
+	case schema.AIChatMessage:
+		if m.FunctionCall != nil {
+			msg.FunctionCall = &compatclient.FunctionCall{
+				Name:      m.FunctionCall.Name,
+				Arguments: llms.FunctionArguments(m.FunctionCall.Arguments),
+			}
+		}
+		for _, tc := range m.ToolCalls {
+			call := compatclient.ToolCall{ID: tc.ID, Type: tc.Type}
+			if tc.FunctionCall != nil {
+				call.Function = compatclient.FunctionCall{
+					Name:      tc.FunctionCall.Name,
+					Arguments: llms.FunctionArguments(tc.FunctionCall.Arguments),
+				}
+			}
+			msg.ToolCalls = append(msg.ToolCalls, call)
+		}
+	case schema.ToolChatMessage:
+		msg.ToolCallID = m.ID
+	}
+}
+
+// newGeneration returns the generation of the choices of the responses, the
+// first of which is its message. The raw response of its metadata is the
+// response, or the responses when there are several.
+func newGeneration(choices []*compatclient.ChatChoice, usage compatclient.ChatUsage, responses []*compatclient.ChatResponse, latency time.Duration) *llms.Generation { //nolint:lll
+	first := choices[0]
+	msg := &schema.AIChatMessage{
+		Content: first.Message.Content,
+	}
+	if first.Message.FunctionCall != nil {
+		msg.FunctionCall = &schema.FunctionCall{
+			Name:      first.Message.FunctionCall.Name,
+			Arguments: first.Message.FunctionCall.Arguments,
+		}
+	}
+	for _, tc := range first.Message.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			ID:   tc.ID,
+			Type: tc.Type,
+			FunctionCall: &schema.FunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: tc.Function.Arguments,
+			},
+		})
+	}
+
+	var raw any = responses
+	if len(responses) == 1 {
+		raw = responses[0]
+	}
+	metadata := llms.NewResponseMetadata(responses[0].Model, first.FinishReason, llms.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}, latency, raw)
+	generationInfo := metadata.GenerationInfo()
+	if first.Logprobs != nil {
+		generationInfo["Logprobs"] = first.Logprobs
+	}
+	if len(choices) > 1 {
+		texts := make([]string, 0, len(choices))
+		for _, c := range choices {
+			texts = append(texts, c.Message.Content)
+		}
+		generationInfo["Choices"] = texts
+	}
+
+	return &llms.Generation{
+		Message:        msg,
+		Text:           msg.Content,
+		GenerationInfo: generationInfo,
+		Metadata:       metadata,
+	}
+}
+
+func (o *Chat) GetNumTokens(text string) int {
+	return llms.CountTokens(o.client.Model, text)
+}
+
+func (o *Chat) GetContextSize() int {
+	return llms.GetModelContextSize(o.client.Model)
+}
+
+func (o *Chat) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	return llms.GenerateChatPrompt(ctx, o, promptValues, options...)
+}
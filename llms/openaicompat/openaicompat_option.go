@@ -0,0 +1,78 @@
+package openaicompat
+
+import (
+	"github.com/tmc/langchaingo/llms/openaicompat/internal/compatclient"
+)
+
+const (
+	tokenEnvVarName   = "OPENAI_COMPAT_API_KEY"  //nolint:gosec
+	modelEnvVarName   = "OPENAI_COMPAT_MODEL"    //nolint:gosec
+	baseURLEnvVarName = "OPENAI_COMPAT_BASE_URL" //nolint:gosec
+)
+
+// Features describes which optional fields of the OpenAI chat completions API
+// the server understands. Fields for unsupported features are never sent, so
+// requests do not fail with unknown-field errors.
+type Features struct {
+	// SupportsFunctions enables the functions and function_call fields. When
+	// false, function definitions passed with llms.WithFunctions are dropped.
+	SupportsFunctions bool
+	// SupportsLogprobs enables requesting log probabilities. When true, they
+	// are returned in the "Logprobs" key of the generation info.
+	SupportsLogprobs bool
+	// SupportsN enables the n field. When false and more than one choice is
+	// requested with llms.WithN, one request per choice is sent instead.
+	SupportsN bool
+}
+
+type options struct {
+	token    string
+	model    string
+	baseURL  string
+	features Features
+
+	httpClient compatclient.Doer
+}
+
+type Option func(*options)
+
+// WithToken passes the API token to the client. If not set, the token is read
+// from the OPENAI_COMPAT_API_KEY environment variable. The token is optional.
+func WithToken(token string) Option {
+	return func(opts *options) {
+		opts.token = token
+	}
+}
+
+// WithModel passes the model name to the client. If not set, the model is read
+// from the OPENAI_COMPAT_MODEL environment variable.
+func WithModel(model string) Option {
+	return func(opts *options) {
+		opts.model = model
+	}
+}
+
+// WithBaseURL passes the base url of the server to the client, for example
+// http://localhost:8000/v1. If not set, the base url is read from the
+// OPENAI_COMPAT_BASE_URL environment variable.
+func WithBaseURL(baseURL string) Option {
+	return func(opts *options) {
+		opts.baseURL = baseURL
+	}
+}
+
+// WithFeatures sets the features supported by the server. If not set, all
+// optional features are disabled.
+func WithFeatures(features Features) Option {
+	return func(opts *options) {
+		opts.features = features
+	}
+}
+
+// WithHTTPClient allows setting a custom HTTP client. If not set, the default value
+// is http.DefaultClient.
+func WithHTTPClient(client compatclient.Doer) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
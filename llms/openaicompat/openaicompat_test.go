@@ -0,0 +1,289 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// strictRequest only has the fields understood by a minimal server.
+type strictRequest struct {
+	Model       string           `json:"model"`
+	Messages    []map[string]any `json:"messages"`
+	Temperature float64          `json:"temperature"`
+	MaxTokens   int              `json:"max_tokens"`
+	Stream      bool             `json:"stream"`
+}
+
+// newStrictServer returns a server that rejects requests with unknown fields,
+// like many self-hosted gateways do.
+func newStrictServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		var req strictRequest
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":{"message":%q}}`, err.Error())
+			return
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"answer %d"}}],`+
+			`"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`, n)
+	}))
+}
+
+func TestChatDropsUnsupportedFields(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	srv := newStrictServer(t, &calls)
+	defer srv.Close()
+
+	chat, err := NewChat(WithBaseURL(srv.URL), WithModel("local"))
+	require.NoError(t, err)
+
+	res, err := chat.Generate(context.Background(),
+		[][]schema.ChatMessage{{schema.HumanChatMessage{Content: "hi"}}},
+		llms.WithN(3),
+		llms.WithFunctions([]llms.FunctionDefinition{{Name: "lookup", Parameters: map[string]any{}}}),
+	)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, int32(3), calls)
+	require.Equal(t, "answer 1", res[0].Text)
+	require.Equal(t, []string{"answer 1", "answer 2", "answer 3"}, res[0].GenerationInfo["Choices"])
+	require.Equal(t, 15, res[0].GenerationInfo["TotalTokens"])
+	require.Equal(t, 15, res[0].Metadata.Usage.TotalTokens)
+	require.Len(t, res[0].Metadata.Raw, 3)
+}
+
+func TestChatSendsSupportedFields(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	srv := newStrictServer(t, &calls)
+	defer srv.Close()
+
+	chat, err := NewChat(WithBaseURL(srv.URL), WithFeatures(Features{SupportsLogprobs: true}))
+	require.NoError(t, err)
+
+	_, err = chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "hi"}})
+	require.ErrorContains(t, err, "logprobs")
+}
+
+func TestNewRequiresBaseURL(t *testing.T) {
+	t.Setenv(baseURLEnvVarName, "")
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrMissingBaseURL)
+}
+
+func TestLLMStreaming(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	llm, err := New(WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	var chunks []string
+	res, err := llm.Call(context.Background(), "hi", llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "Hello", res)
+	require.Equal(t, []string{"Hel", "lo"}, chunks)
+}
+
+func TestChatValidatesContext(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	srv := newStrictServer(t, &calls)
+	defer srv.Close()
+
+	chat, err := NewChat(WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = chat.Call(context.Background(),
+		[]schema.ChatMessage{schema.HumanChatMessage{Content: strings.Repeat("word ", 100)}},
+		llms.WithContextValidation(10),
+	)
+	require.ErrorIs(t, err, llms.ErrContextTooLong)
+	require.Equal(t, int32(0), calls)
+}
+
+func TestChatToolCallsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var sent []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req strictRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		sent = req.Messages
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"","tool_calls":[`+
+			`{"id":"call_1","type":"function","function":{"name":"weather","arguments":"{\"city\":\"Paris\"}"}}]},`+
+			`"finish_reason":"tool_calls"}]}`)
+	}))
+	defer srv.Close()
+
+	chat, err := NewChat(WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "weather in Paris?"}}
+	ai, err := chat.Call(context.Background(), messages)
+	require.NoError(t, err)
+	require.Equal(t, []schema.ToolCall{{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+	}}, ai.ToolCalls)
+
+	messages = append(messages, ai, schema.ToolChatMessage{ID: "call_1", Name: "weather", Content: "sunny"})
+	_, err = chat.Call(context.Background(), messages)
+	require.NoError(t, err)
+	require.Len(t, sent, 3)
+	require.Equal(t, "assistant", sent[1]["role"])
+	require.Equal(t, []any{map[string]any{
+		"id":       "call_1",
+		"type":     "function",
+		"function": map[string]any{"name": "weather", "arguments": `{"city":"Paris"}`},
+	}}, sent[1]["tool_calls"])
+	require.Equal(t, map[string]any{
+		"role": "tool", "content": "sunny", "name": "weather", "tool_call_id": "call_1",
+	}, sent[2])
+}
+
+func TestChatConcurrentCallsKeepOptions(t *testing.T) {
+	t.Parallel()
+
+	// The server answers with the prompt and the options of each request, so
+	// that options leaking from one call to another show in the answers.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req strictRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		answer := fmt.Sprintf("%s %.1f %d", req.Messages[0]["content"], req.Temperature, req.MaxTokens)
+		if req.Stream {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", answer)
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":%q}}]}`, answer)
+	}))
+	defer srv.Close()
+
+	chat, err := NewChat(WithBaseURL(srv.URL), WithFeatures(Features{SupportsN: false}))
+	require.NoError(t, err)
+
+	errs := make(chan error, 16)
+	for i := 0; i < cap(errs); i++ {
+		go func(i int) {
+			opts := []llms.CallOption{llms.WithTemperature(float64(i) / 10), llms.WithMaxTokens(i + 1), llms.WithN(2)}
+			if i%2 == 0 {
+				opts = append(opts, llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+			}
+			prompts := [][]schema.ChatMessage{
+				{schema.HumanChatMessage{Content: fmt.Sprintf("a%d", i)}},
+				{schema.HumanChatMessage{Content: fmt.Sprintf("b%d", i)}},
+			}
+			res, err := chat.Generate(context.Background(), prompts, opts...)
+			if err != nil {
+				errs <- err
+				return
+			}
+			for j, prefix := range []string{"a", "b"} {
+				want := fmt.Sprintf("%s%d %.1f %d", prefix, i, float64(i)/10, i+1)
+				if got := res[j].GenerationInfo["Choices"]; fmt.Sprint(got) != fmt.Sprint([]string{want, want}) {
+					errs <- fmt.Errorf("call %d: got %v, want %q twice", i, got, want) //nolint:goerr113
+					return
+				}
+			}
+			errs <- nil
+		}(i)
+	}
+	for i := 0; i < cap(errs); i++ {
+		require.NoError(t, <-errs)
+	}
+}
+
+func TestChatForcedFunctionCall(t *testing.T) {
+	t.Parallel()
+
+	var functionCall any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		functionCall = req["function_call"]
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","function_call":{"name":"extract","arguments":"{}"}}}]}`)
+	}))
+	defer srv.Close()
+
+	chat, err := NewChat(WithBaseURL(srv.URL), WithFeatures(Features{SupportsFunctions: true}))
+	require.NoError(t, err)
+	functions := llms.WithFunctions([]llms.FunctionDefinition{{Name: "extract", Parameters: map[string]any{}}})
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "hi"}}
+
+	_, err = chat.Call(context.Background(), messages, functions, llms.WithForcedFunctionCall("extract"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "extract"}, functionCall)
+
+	_, err = chat.Call(context.Background(), messages, functions)
+	require.NoError(t, err)
+	require.Equal(t, "auto", functionCall)
+
+	_, err = chat.Call(context.Background(), messages, functions, llms.WithForcedFunctionCall("lookup"))
+	require.ErrorIs(t, err, llms.ErrUnknownFunction)
+}
+
+func TestChatFunctionsAsTools(t *testing.T) {
+	t.Parallel()
+
+	var req map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = nil
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"finish_reason":"stop","message":{"role":"assistant","tool_calls":`+
+			`[{"id":"call_1","type":"function","function":{"name":"extract","arguments":"{}"}}]}}]}`)
+	}))
+	defer srv.Close()
+
+	chat, err := NewChat(WithBaseURL(srv.URL), WithFeatures(Features{SupportsFunctions: true}))
+	require.NoError(t, err)
+	msg, err := chat.Call(context.Background(), []schema.ChatMessage{schema.HumanChatMessage{Content: "hi"}},
+		llms.WithFunctions([]llms.FunctionDefinition{{Name: "extract", Parameters: map[string]any{}}}),
+		llms.WithFunctionsAsTools(), llms.WithForcedFunctionCall("extract"))
+	require.NoError(t, err)
+	require.NotContains(t, req, "functions")
+	require.NotContains(t, req, "function_call")
+	require.Len(t, req["tools"], 1)
+	require.Equal(t, map[string]any{"type": "function", "function": map[string]any{"name": "extract"}},
+		req["tool_choice"])
+	require.Len(t, msg.ToolCalls, 1)
+}
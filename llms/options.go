@@ -1,6 +1,9 @@
 package llms
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // CallOption is a function that configures a CallOptions.
 type CallOption func(*CallOptions)
@@ -17,7 +20,7 @@ type CallOptions struct {
 	StopWords []string `json:"stop_words"`
 	// StreamingFunc is a function to be called for each chunk of a streaming response.
 	// Return an error to stop streaming early.
-	StreamingFunc func(ctx context.Context, chunk []byte) error
+	StreamingFunc func(ctx context.Context, chunk []byte) error `json:"-"`
 	// TopK is the number of tokens to consider for top-k sampling.
 	TopK int `json:"top_k"`
 	// TopP is the cumulative probability for top-p sampling.
@@ -36,14 +39,38 @@ type CallOptions struct {
 	FrequencyPenalty float64 `json:"frequency_penalty"`
 	// PresencePenalty is the presence penalty for sampling.
 	PresencePenalty float64 `json:"presence_penalty"`
+	// LatencyBudget is the time the call may take, see WithLatencyBudget.
+	LatencyBudget time.Duration `json:"latency_budget"`
+	// ValidateContext is whether the prompt is checked against the context
+	// window of the model before it is sent, see ValidatePrompt.
+	ValidateContext bool `json:"validate_context"`
+	// ContextSize overrides the size of the context window of the model.
+	ContextSize int `json:"context_size"`
+	// PromptShrinker is called to shorten prompts that are too long.
+	PromptShrinker PromptShrinker `json:"-"`
+	// TransportHook intercepts the HTTP requests of the call, see
+	// WithTransportHook.
+	TransportHook TransportHook `json:"-"`
+	// TrimBoundary is where generations cut short by MaxTokens are trimmed,
+	// see WithTrimToBoundary.
+	TrimBoundary Boundary `json:"trim_boundary"`
+	// RequestsPerMinute and TokensPerMinute limit the requests sent to the
+	// model, see WithRateLimit.
+	RequestsPerMinute int `json:"-"`
+	TokensPerMinute   int `json:"-"`
 
 	// Function defitions to include in the request.
 	Functions []FunctionDefinition `json:"functions"`
 	// FunctionCallBehavior is the behavior to use when calling functions.
 	//
-	// If a specific function should be invoked, use the format:
-	// `{"name": "my_function"}`
+	// To have a specific function called, use ForcedFunctionCall.
 	FunctionCallBehavior FunctionCallBehavior `json:"function_call"`
+	// ForcedFunctionCall is the name of the function the model must call, see
+	// WithForcedFunctionCall.
+	ForcedFunctionCall string `json:"forced_function_call"`
+	// FunctionsAsTools is whether the functions are sent as tools, see
+	// WithFunctionsAsTools.
+	FunctionsAsTools bool `json:"functions_as_tools"`
 }
 
 // FunctionDefinition is a definition of a function that can be called by the model.
@@ -178,9 +205,71 @@ func WithFunctionCallBehavior(behavior FunctionCallBehavior) CallOption {
 	}
 }
 
+// WithForcedFunctionCall will add an option to force the model to call the
+// function with the name, one of the functions of the request, rather than
+// letting it choose whether to call a function. It is sent as function_call,
+// or as tool_choice with WithFunctionsAsTools. Providers return
+// ErrUnknownFunction if no function of the request has the name.
+func WithForcedFunctionCall(name string) CallOption {
+	return func(o *CallOptions) {
+		o.ForcedFunctionCall = name
+	}
+}
+
+// WithFunctionsAsTools will add an option to send the functions of the request
+// as tools, with the function call behavior or forced function call as the
+// tool choice, for the models supporting tools rather than functions. Calls
+// of the functions are then returned as the tool calls of the message.
+func WithFunctionsAsTools() CallOption {
+	return func(o *CallOptions) {
+		o.FunctionsAsTools = true
+	}
+}
+
 // WithFunctions will add an option to set the functions to include in the request.
 func WithFunctions(functions []FunctionDefinition) CallOption {
 	return func(o *CallOptions) {
 		o.Functions = functions
 	}
 }
+
+// WithLatencyBudget will add an option to bound the duration of the call.
+// Providers end the request when the budget is spent, lower MaxTokens to what
+// can be generated in time and stop streaming early, returning the text
+// streamed so far.
+func WithLatencyBudget(budget time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.LatencyBudget = budget
+	}
+}
+
+// WithContextValidation will add an option to check that the prompt and
+// MaxTokens fit in the context window of the model before sending it, returning
+// a *ContextTooLongError otherwise. A contextSize of 0 uses the known size of
+// the model, see GetModelContextSize.
+func WithContextValidation(contextSize int) CallOption {
+	return func(o *CallOptions) {
+		o.ValidateContext = true
+		o.ContextSize = contextSize
+	}
+}
+
+// WithPromptShrinker will add an option to validate the prompt like
+// WithContextValidation, calling shrinker to shorten prompts that are too long.
+func WithPromptShrinker(shrinker PromptShrinker) CallOption {
+	return func(o *CallOptions) {
+		o.ValidateContext = true
+		o.PromptShrinker = shrinker
+	}
+}
+
+// WithTrimToBoundary will add an option to trim generations cut short by
+// MaxTokens to their last complete sentence or paragraph instead of in the
+// middle of a word. Providers request slightly more tokens than MaxTokens to
+// finish the sentence, and mark the trimmed generations as truncated, see
+// ResponseMetadata.Truncated.
+func WithTrimToBoundary(boundary Boundary) CallOption {
+	return func(o *CallOptions) {
+		o.TrimBoundary = boundary
+	}
+}
@@ -0,0 +1,110 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrContextTooLong is returned when a prompt and the requested completion do
+// not fit in the context window of the model. The returned errors are of type
+// *ContextTooLongError and hold the size of the overflow.
+var ErrContextTooLong = errors.New("prompt too long for the model context")
+
+// _maxShrinkAttempts is the number of times a prompt shrinker is called before
+// giving up on a prompt.
+const _maxShrinkAttempts = 3
+
+// ContextTooLongError describes a prompt that does not fit in the context
+// window of a model.
+type ContextTooLongError struct {
+	// Model is the model the prompt was validated for.
+	Model string
+	// ContextSize is the size of the context window of the model, in tokens.
+	ContextSize int
+	// PromptTokens is the number of tokens of the prompt.
+	PromptTokens int
+	// MaxTokens is the number of tokens reserved for the completion.
+	MaxTokens int
+}
+
+// Overflow returns the number of tokens to remove from the prompt so that it
+// fits in the context window.
+func (e *ContextTooLongError) Overflow() int {
+	return e.PromptTokens + e.MaxTokens - e.ContextSize
+}
+
+func (e *ContextTooLongError) Error() string {
+	return fmt.Sprintf("%s: %d prompt tokens and %d completion tokens exceed the %d tokens of %s by %d",
+		ErrContextTooLong, e.PromptTokens, e.MaxTokens, e.ContextSize, e.Model, e.Overflow())
+}
+
+func (e *ContextTooLongError) Unwrap() error {
+	return ErrContextTooLong
+}
+
+// PromptShrinker is called with the messages of a prompt that does not fit in
+// the context window of the model and the number of tokens to remove. It
+// returns the messages to send instead, for example after dropping old messages
+// or summarizing documents.
+type PromptShrinker func(ctx context.Context, messages []schema.ChatMessage, overflow int) ([]schema.ChatMessage, error) //nolint:lll
+
+// ValidatePrompt checks that the prompt fits in the context window of the model
+// when validation was enabled with WithContextValidation or WithPromptShrinker.
+// Prompts that are too long are given to the prompt shrinker, if any, and a
+// *ContextTooLongError is returned if they still do not fit.
+func ValidatePrompt(ctx context.Context, model, prompt string, opts CallOptions) (string, error) {
+	if !opts.ValidateContext {
+		return prompt, nil
+	}
+	messages, err := ValidateMessages(ctx, model, []schema.ChatMessage{schema.HumanChatMessage{Content: prompt}}, opts)
+	if err != nil {
+		return "", err
+	}
+	contents := make([]string, 0, len(messages))
+	for _, m := range messages {
+		contents = append(contents, m.GetContent())
+	}
+	return strings.Join(contents, "\n"), nil
+}
+
+// ValidateMessages is like ValidatePrompt for the messages of a chat. The
+// number of tokens of the messages is estimated as the sum of the tokens of
+// their contents.
+func ValidateMessages(ctx context.Context, model string, messages []schema.ChatMessage, opts CallOptions) ([]schema.ChatMessage, error) { //nolint:lll
+	if !opts.ValidateContext {
+		return messages, nil
+	}
+	contextSize := opts.ContextSize
+	if contextSize <= 0 {
+		contextSize = GetModelContextSize(model)
+	}
+
+	for attempt := 0; ; attempt++ {
+		promptTokens := 0
+		for _, m := range messages {
+			promptTokens += CountTokens(model, m.GetContent())
+		}
+		if promptTokens+opts.MaxTokens <= contextSize {
+			return messages, nil
+		}
+
+		tooLong := &ContextTooLongError{
+			Model:        model,
+			ContextSize:  contextSize,
+			PromptTokens: promptTokens,
+			MaxTokens:    opts.MaxTokens,
+		}
+		if opts.PromptShrinker == nil || attempt >= _maxShrinkAttempts {
+			return nil, tooLong
+		}
+		shrunk, err := opts.PromptShrinker(ctx, messages, tooLong.Overflow())
+		if err != nil {
+			return nil, errors.Join(tooLong, err)
+		}
+		messages = shrunk
+	}
+}
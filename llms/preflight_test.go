@@ -0,0 +1,72 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestValidatePrompt(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	long := strings.Repeat("word ", 100)
+
+	// Validation is off by default.
+	prompt, err := ValidatePrompt(ctx, "gpt-3.5-turbo", long, CallOptions{ContextSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, long, prompt)
+
+	opts := CallOptions{}
+	WithContextValidation(50)(&opts)
+	opts.MaxTokens = 20
+	_, err = ValidatePrompt(ctx, "gpt-3.5-turbo", long, opts)
+	require.ErrorIs(t, err, ErrContextTooLong)
+	var tooLong *ContextTooLongError
+	require.ErrorAs(t, err, &tooLong)
+	assert.Equal(t, 50, tooLong.ContextSize)
+	assert.Equal(t, 20, tooLong.MaxTokens)
+	assert.Equal(t, tooLong.PromptTokens-30, tooLong.Overflow())
+
+	prompt, err = ValidatePrompt(ctx, "gpt-3.5-turbo", "short prompt", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "short prompt", prompt)
+}
+
+func TestValidateMessagesShrinks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	messages := []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be brief"},
+		schema.HumanChatMessage{Content: strings.Repeat("word ", 100)},
+		schema.HumanChatMessage{Content: "hi"},
+	}
+
+	var overflows []int
+	opts := CallOptions{}
+	WithContextValidation(20)(&opts)
+	WithPromptShrinker(func(_ context.Context, messages []schema.ChatMessage, overflow int) ([]schema.ChatMessage, error) { //nolint:lll
+		overflows = append(overflows, overflow)
+		return append(messages[:1:1], messages[2:]...), nil
+	})(&opts)
+
+	got, err := ValidateMessages(ctx, "gpt-3.5-turbo", messages, opts)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+	require.Len(t, overflows, 1)
+	assert.Positive(t, overflows[0])
+
+	errShrink := errors.New("cannot shrink")
+	WithPromptShrinker(func(context.Context, []schema.ChatMessage, int) ([]schema.ChatMessage, error) {
+		return nil, errShrink
+	})(&opts)
+	_, err = ValidateMessages(ctx, "gpt-3.5-turbo", messages, opts)
+	require.ErrorIs(t, err, ErrContextTooLong)
+	require.ErrorIs(t, err, errShrink)
+}
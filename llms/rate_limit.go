@@ -0,0 +1,191 @@
+package llms
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// RateLimiter limits the requests and tokens sent to a provider per minute
+// with token buckets. Calls over the limits wait their turn, in order.
+type RateLimiter struct {
+	mu       sync.Mutex
+	requests *bucket
+	tokens   *bucket
+}
+
+// bucket is a token bucket holding up to a minute of its rate. Reservations
+// can make it negative, the next ones then waiting for it to refill.
+type bucket struct {
+	capacity  float64
+	perSecond float64
+	available float64
+	last      time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing the numbers of requests and
+// tokens per minute. Zero or negative numbers are not limited.
+func NewRateLimiter(requestsPerMinute, tokensPerMinute int) *RateLimiter {
+	return &RateLimiter{
+		requests: newBucket(requestsPerMinute),
+		tokens:   newBucket(tokensPerMinute),
+	}
+}
+
+func newBucket(perMinute int) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{
+		capacity:  float64(perMinute),
+		perSecond: float64(perMinute) / time.Minute.Seconds(),
+		available: float64(perMinute),
+	}
+}
+
+// Wait blocks until a request with the number of tokens can be sent, or the
+// context is done. Requests of more tokens than the limit per minute wait for
+// the whole minute. It returns the error of the context without waiting if
+// the context ends before the request can be sent.
+func (l *RateLimiter) Wait(ctx context.Context, tokens int) error {
+	now := time.Now()
+	l.mu.Lock()
+	wait := l.requests.reserve(1, now)
+	if w := l.tokens.reserve(float64(tokens), now); w > wait {
+		wait = w
+	}
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	cancel := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.requests.cancel(1)
+		l.tokens.cancel(float64(tokens))
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		cancel()
+		return context.DeadlineExceeded
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes n from the bucket, returning how long to wait for it. A nil
+// bucket does not limit.
+func (b *bucket) reserve(n float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	if !b.last.IsZero() {
+		b.available += now.Sub(b.last).Seconds() * b.perSecond
+		if b.available > b.capacity {
+			b.available = b.capacity
+		}
+	}
+	b.last = now
+	if n > b.capacity {
+		n = b.capacity
+	}
+	b.available -= n
+	if b.available >= 0 {
+		return 0
+	}
+	return time.Duration(-b.available / b.perSecond * float64(time.Second))
+}
+
+// cancel gives back n taken by a reservation that was not used.
+func (b *bucket) cancel(n float64) {
+	if b == nil {
+		return
+	}
+	if n > b.capacity {
+		n = b.capacity
+	}
+	b.available += n
+	if b.available > b.capacity {
+		b.available = b.capacity
+	}
+}
+
+// WithRateLimit will add an option to limit the requests and tokens per minute
+// sent to the model by all the calls made through the same LLM with the same
+// limits, waiting until a request can be sent. The tokens of a request are
+// estimated from the length of its prompt and its maximum number of tokens.
+// It is supported by all the providers.
+func WithRateLimit(requestsPerMinute, tokensPerMinute int) CallOption {
+	return func(o *CallOptions) {
+		o.RequestsPerMinute = requestsPerMinute
+		o.TokensPerMinute = tokensPerMinute
+	}
+}
+
+// RateLimiters holds the rate limiters of the limits set with WithRateLimit,
+// for each model and limits. Providers create one with their client, so that
+// the limiters are shared by the calls made through the same LLM, and its
+// copies, and freed with it. The zero value is ready to use.
+type RateLimiters struct {
+	mu       sync.Mutex
+	limiters map[rateLimiterKey]*RateLimiter
+}
+
+type rateLimiterKey struct {
+	model            string
+	requests, tokens int
+}
+
+// get returns the limiter of the model and limits, creating it if needed.
+func (r *RateLimiters) get(model string, requests, tokens int) *RateLimiter {
+	key := rateLimiterKey{model: model, requests: requests, tokens: tokens}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.limiters[key]
+	if !ok {
+		if r.limiters == nil {
+			r.limiters = make(map[rateLimiterKey]*RateLimiter)
+		}
+		l = NewRateLimiter(requests, tokens)
+		r.limiters[key] = l
+	}
+	return l
+}
+
+// ApplyRateLimit waits until the request of the prompts can be sent to the
+// model under the limits set with WithRateLimit, if any, with the limiters of
+// the provider. Providers call it before each request.
+func ApplyRateLimit(ctx context.Context, limiters *RateLimiters, model string, opts CallOptions, prompts ...string) error { //nolint:lll
+	if opts.RequestsPerMinute <= 0 && opts.TokensPerMinute <= 0 {
+		return nil
+	}
+	tokens := opts.MaxTokens
+	for _, prompt := range prompts {
+		tokens += EstimateTokens(prompt)
+	}
+	return limiters.get(model, opts.RequestsPerMinute, opts.TokensPerMinute).Wait(ctx, tokens)
+}
+
+// ApplyChatRateLimit is ApplyRateLimit for the request of the messages.
+func ApplyChatRateLimit(ctx context.Context, limiters *RateLimiters, model string, opts CallOptions, messages []schema.ChatMessage) error { //nolint:lll
+	prompts := make([]string, 0, len(messages))
+	for _, m := range messages {
+		prompts = append(prompts, m.GetContent())
+	}
+	return ApplyRateLimit(ctx, limiters, model, opts, prompts...)
+}
+
+// EstimateTokens estimates the number of tokens of the text from its length,
+// without loading a tokenizer.
+func EstimateTokens(text string) int {
+	return (len([]rune(text)) + _tokenApproximation - 1) / _tokenApproximation
+}
@@ -0,0 +1,55 @@
+package llms
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := NewRateLimiter(2, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, l.Wait(ctx, 1000))
+	require.NoError(t, l.Wait(ctx, 1000))
+	start := time.Now()
+	require.ErrorIs(t, l.Wait(ctx, 1), context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 100*time.Millisecond, "no waiting past the deadline")
+
+	// 6000 tokens per minute refill 100 tokens per second.
+	l = NewRateLimiter(0, 6000)
+	require.NoError(t, l.Wait(context.Background(), 10000))
+	start = time.Now()
+	require.NoError(t, l.Wait(context.Background(), 10))
+	require.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, l.Wait(canceled, 6000), context.Canceled)
+}
+
+func TestApplyRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var limiters RateLimiters
+	var opts CallOptions
+	require.NoError(t, ApplyRateLimit(context.Background(), &limiters, "model", opts))
+
+	WithRateLimit(1, 0)(&opts)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	messages := []schema.ChatMessage{schema.HumanChatMessage{Content: "hello"}}
+	require.NoError(t, ApplyChatRateLimit(ctx, &limiters, "model", opts, messages))
+	require.ErrorIs(t, ApplyChatRateLimit(ctx, &limiters, "model", opts, messages), context.DeadlineExceeded)
+	require.NoError(t, ApplyRateLimit(ctx, &limiters, "other-model", opts, "hello"))
+
+	// The limiters of other LLMs are not shared.
+	require.NoError(t, ApplyRateLimit(ctx, &RateLimiters{}, "model", opts, "hello"))
+
+	require.Equal(t, 2, EstimateTokens("hello"))
+}
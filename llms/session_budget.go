@@ -0,0 +1,145 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSessionBudgetExceeded is returned by CheckSessionBudget once the llm calls
+// of a session used more tokens or cost more than the budget of the session.
+var ErrSessionBudgetExceeded = errors.New("session budget exceeded")
+
+// _defaultSessionBudgetWarnAt is the share of the budget of a session at which
+// the warning function is called.
+const _defaultSessionBudgetWarnAt = 0.8
+
+// SessionBudget limits the tokens and the cost of the llm calls of each
+// session, across all the chains and agents called with a context returned by
+// Context. The usage is read from the generation info of the llm results, as
+// with UsageTracker.
+type SessionBudget struct {
+	maxTotalTokens int
+	maxCostUSD     float64
+	warnAt         float64
+	warn           func(ctx context.Context, sessionID string, usage Usage)
+
+	mu       sync.Mutex
+	sessions map[string]*sessionUsage
+}
+
+type sessionUsage struct {
+	usage  Usage
+	warned bool
+}
+
+// SessionBudgetOption is a function that configures a SessionBudget.
+type SessionBudgetOption func(*SessionBudget)
+
+// WithSessionBudgetWarning sets a function called once per session when its
+// usage reaches the share of the budget, 0.8 by default, before calls are
+// stopped.
+func WithSessionBudgetWarning(share float64, warn func(ctx context.Context, sessionID string, usage Usage)) SessionBudgetOption { //nolint:lll
+	return func(b *SessionBudget) {
+		b.warnAt = share
+		b.warn = warn
+	}
+}
+
+// NewSessionBudget creates a budget of maxTotalTokens tokens and maxCostUSD US
+// dollars per session. A zero limit is not checked.
+func NewSessionBudget(maxTotalTokens int, maxCostUSD float64, opts ...SessionBudgetOption) *SessionBudget {
+	b := &SessionBudget{
+		maxTotalTokens: maxTotalTokens,
+		maxCostUSD:     maxCostUSD,
+		warnAt:         _defaultSessionBudgetWarnAt,
+		sessions:       make(map[string]*sessionUsage),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+type sessionBudgetKey struct{}
+
+type sessionBudgetValue struct {
+	budget    *SessionBudget
+	sessionID string
+}
+
+// Context returns a context with which the usage of llm calls counts against
+// the budget of the session, and with which chains refuse to call the llm once
+// the budget is exceeded.
+func (b *SessionBudget) Context(ctx context.Context, sessionID string) context.Context {
+	ctx = context.WithValue(ctx, sessionBudgetKey{}, sessionBudgetValue{budget: b, sessionID: sessionID})
+	t := &UsageTracker{}
+	t.onAdd = func(u Usage) { b.add(ctx, sessionID, u) }
+	return WithUsageTracker(ctx, t)
+}
+
+// Usage returns the usage of the session so far.
+func (b *SessionBudget) Usage(sessionID string) Usage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if s, ok := b.sessions[sessionID]; ok {
+		return s.usage
+	}
+	return Usage{}
+}
+
+// Reset forgets the usage of the session, for example at the start of a new
+// billing period.
+func (b *SessionBudget) Reset(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sessions, sessionID)
+}
+
+func (b *SessionBudget) add(ctx context.Context, sessionID string, u Usage) {
+	b.mu.Lock()
+	s, ok := b.sessions[sessionID]
+	if !ok {
+		s = &sessionUsage{}
+		b.sessions[sessionID] = s
+	}
+	s.usage.Add(u)
+	warn := b.warn != nil && !s.warned && b.reached(s.usage, b.warnAt)
+	if warn {
+		s.warned = true
+	}
+	usage := s.usage
+	b.mu.Unlock()
+
+	if warn {
+		b.warn(ctx, sessionID, usage)
+	}
+}
+
+// reached reports whether the usage reached the share of one of the limits.
+func (b *SessionBudget) reached(u Usage, share float64) bool {
+	return (b.maxTotalTokens > 0 && float64(u.TotalTokens) >= share*float64(b.maxTotalTokens)) ||
+		(b.maxCostUSD > 0 && u.CostUSD >= share*b.maxCostUSD)
+}
+
+// check returns ErrSessionBudgetExceeded if the session is over its budget.
+func (b *SessionBudget) check(sessionID string) error {
+	u := b.Usage(sessionID)
+	if (b.maxTotalTokens > 0 && u.TotalTokens > b.maxTotalTokens) || (b.maxCostUSD > 0 && u.CostUSD > b.maxCostUSD) {
+		return fmt.Errorf("%w: session %q used %d tokens and $%.4f, the budget is %d tokens and $%.4f",
+			ErrSessionBudgetExceeded, sessionID, u.TotalTokens, u.CostUSD, b.maxTotalTokens, b.maxCostUSD)
+	}
+	return nil
+}
+
+// CheckSessionBudget returns an error wrapping ErrSessionBudgetExceeded if the
+// session of the context, if any, is over its budget. Chains call it before
+// their llm calls.
+func CheckSessionBudget(ctx context.Context) error {
+	v, ok := ctx.Value(sessionBudgetKey{}).(sessionBudgetValue)
+	if !ok {
+		return nil
+	}
+	return v.budget.check(v.sessionID)
+}
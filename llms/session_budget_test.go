@@ -0,0 +1,41 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionBudget(t *testing.T) {
+	t.Parallel()
+
+	var warnings []string
+	budget := NewSessionBudget(100, 0, WithSessionBudgetWarning(0.5, func(_ context.Context, sessionID string, u Usage) {
+		warnings = append(warnings, sessionID)
+	}))
+	result := LLMResult{Generations: [][]*Generation{{{GenerationInfo: map[string]any{"TotalTokens": 40}}}}}
+
+	alice := budget.Context(context.Background(), "alice")
+	bob := budget.Context(context.Background(), "bob")
+	aliceRun := &UsageTracker{}
+	TrackUsage(WithUsageTracker(alice, aliceRun), result)
+	require.NoError(t, CheckSessionBudget(alice))
+	require.Empty(t, warnings)
+
+	TrackUsage(budget.Context(context.Background(), "alice"), result)
+	require.NoError(t, CheckSessionBudget(alice))
+	require.Equal(t, []string{"alice"}, warnings)
+
+	TrackUsage(alice, result)
+	require.ErrorIs(t, CheckSessionBudget(alice), ErrSessionBudgetExceeded)
+	require.Equal(t, []string{"alice"}, warnings)
+	require.Equal(t, 120, budget.Usage("alice").TotalTokens)
+	require.Equal(t, 40, aliceRun.Usage().TotalTokens)
+
+	require.NoError(t, CheckSessionBudget(bob))
+	require.NoError(t, CheckSessionBudget(context.Background()))
+
+	budget.Reset("alice")
+	require.NoError(t, CheckSessionBudget(alice))
+}
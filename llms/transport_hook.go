@@ -0,0 +1,57 @@
+package llms
+
+import (
+	"context"
+	"net/http"
+)
+
+// TransportHook intercepts a raw HTTP request of a provider. It can inspect or
+// change the request before sending it with next, inspect, change or replace
+// the response, or fail without sending the request, for example to inject
+// latency or errors when testing retry and fallback logic.
+type TransportHook func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error)
+
+// WithTransportHook will add an option to intercept the HTTP requests made by
+// the call. It is supported by the providers talking HTTP through a client
+// wrapped with HookDoer: openai, openaicompat, anthropic, huggingface and
+// bedrock.
+func WithTransportHook(hook TransportHook) CallOption {
+	return func(o *CallOptions) {
+		o.TransportHook = hook
+	}
+}
+
+type transportHookKey struct{}
+
+// ApplyTransportHook returns a context carrying the transport hook set with
+// WithTransportHook, if any, to the client of the provider. Providers call it
+// at the start of Generate.
+func ApplyTransportHook(ctx context.Context, opts CallOptions) context.Context {
+	if opts.TransportHook == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, transportHookKey{}, opts.TransportHook)
+}
+
+// Doer performs a HTTP request.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HookDoer returns a Doer sending the requests with d, through the transport
+// hook of the context of each request, if any.
+func HookDoer(d Doer) Doer { //nolint:ireturn
+	return hookDoer{d: d}
+}
+
+type hookDoer struct {
+	d Doer
+}
+
+func (h hookDoer) Do(req *http.Request) (*http.Response, error) {
+	hook, ok := req.Context().Value(transportHookKey{}).(TransportHook)
+	if !ok {
+		return h.d.Do(req)
+	}
+	return hook(req, h.d.Do)
+}
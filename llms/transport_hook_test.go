@@ -0,0 +1,71 @@
+package llms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookDoerWithoutHook(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	ctx := ApplyTransportHook(context.Background(), CallOptions{})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := HookDoer(server.Client()).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+}
+
+func TestHookDoerWithHook(t *testing.T) {
+	t.Parallel()
+
+	var served int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		assert.Equal(t, "hooked", r.Header.Get("X-Test"))
+	}))
+	defer server.Close()
+
+	var opts CallOptions
+	WithTransportHook(func(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+		req.Header.Set("X-Test", "hooked")
+		return next(req)
+	})(&opts)
+
+	ctx := ApplyTransportHook(context.Background(), opts)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := HookDoer(server.Client()).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, served)
+}
+
+func TestHookDoerInjectedError(t *testing.T) {
+	t.Parallel()
+
+	errInjected := errors.New("injected")
+	var opts CallOptions
+	WithTransportHook(func(*http.Request, func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+		return nil, errInjected
+	})(&opts)
+
+	ctx := ApplyTransportHook(context.Background(), opts)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://127.0.0.1:1", nil)
+	require.NoError(t, err)
+	_, err = HookDoer(http.DefaultClient).Do(req) //nolint:bodyclose
+	require.ErrorIs(t, err, errInjected)
+}
@@ -0,0 +1,162 @@
+package llms
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Usage is the number of tokens used by llm calls and their estimated cost.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	// CostUSD is the cost of the calls in US dollars, estimated with the
+	// prices of ModelPrice. It is zero for models without a known price.
+	CostUSD float64
+}
+
+// Add adds the usage of other to the usage.
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.CostUSD += other.CostUSD
+}
+
+// Price is the price of the tokens of a model in US dollars per 1000 tokens.
+type Price struct {
+	Prompt     float64
+	Completion float64
+}
+
+// nolint:gochecknoglobals
+var modelToPrice = map[string]Price{
+	"gpt-3.5-turbo-16k": {Prompt: 0.003, Completion: 0.004},
+	"gpt-3.5-turbo":     {Prompt: 0.0015, Completion: 0.002},
+	"gpt-4-32k":         {Prompt: 0.06, Completion: 0.12},
+	"gpt-4":             {Prompt: 0.03, Completion: 0.06},
+	"text-davinci-003":  {Prompt: 0.02, Completion: 0.02},
+}
+
+// ModelPrice returns the price of the tokens of a model. Dated versions of a
+// model, such as "gpt-4-0613", have the price of the model. The returned bool
+// is false if the price of the model is not known.
+func ModelPrice(model string) (Price, bool) {
+	var (
+		price   Price
+		matched string
+	)
+	for name, p := range modelToPrice {
+		if strings.HasPrefix(model, name) && len(name) > len(matched) {
+			price, matched = p, name
+		}
+	}
+	return price, matched != ""
+}
+
+// GenerationUsage returns the usage reported in the generation info of a
+// generation under "PromptTokens", "CompletionTokens" and "TotalTokens", with
+// its cost estimated for the model reported under "Model", if any.
+func GenerationUsage(info map[string]any) Usage {
+	u := Usage{
+		PromptTokens:     intValue(info["PromptTokens"]),
+		CompletionTokens: intValue(info["CompletionTokens"]),
+		TotalTokens:      intValue(info["TotalTokens"]),
+	}
+	if u.TotalTokens == 0 {
+		u.TotalTokens = u.PromptTokens + u.CompletionTokens
+	}
+	if model, ok := info["Model"].(string); ok {
+		u.CostUSD = estimateCost(model, u)
+	}
+	return u
+}
+
+// estimateCost returns the cost of the usage for the model, zero if its price
+// is not known.
+func estimateCost(model string, u Usage) float64 {
+	price, ok := ModelPrice(model)
+	if !ok {
+		return 0
+	}
+	return (float64(u.PromptTokens)*price.Prompt + float64(u.CompletionTokens)*price.Completion) / 1000
+}
+
+func intValue(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float32:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+// UsageTracker sums the usage of the llm calls made with a context returned
+// by WithUsageTracker.
+type UsageTracker struct {
+	parent *UsageTracker
+	// onAdd is called with the usage added to the tracker, if set.
+	onAdd func(Usage)
+
+	mu    sync.Mutex
+	usage Usage
+}
+
+type usageTrackerKey struct{}
+
+// WithUsageTracker returns a context with which the usage of llm calls is added
+// to the tracker, and to the trackers of the parent context. A tracker is
+// given to a single context.
+func WithUsageTracker(ctx context.Context, t *UsageTracker) context.Context {
+	t.parent = usageTrackerFromContext(ctx)
+	return context.WithValue(ctx, usageTrackerKey{}, t)
+}
+
+func usageTrackerFromContext(ctx context.Context) *UsageTracker {
+	t, _ := ctx.Value(usageTrackerKey{}).(*UsageTracker)
+	return t
+}
+
+// Usage returns the usage tracked so far.
+func (t *UsageTracker) Usage() Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+func (t *UsageTracker) add(u Usage) {
+	for ; t != nil; t = t.parent {
+		t.mu.Lock()
+		t.usage.Add(u)
+		t.mu.Unlock()
+		if t.onAdd != nil {
+			t.onAdd(u)
+		}
+	}
+}
+
+// TrackUsage adds the usage of the generations of the result to the usage
+// trackers of the context. Chains call it with the result of their llm calls.
+func TrackUsage(ctx context.Context, result LLMResult) {
+	t := usageTrackerFromContext(ctx)
+	if t == nil {
+		return
+	}
+	var u Usage
+	for _, generations := range result.Generations {
+		for _, g := range generations {
+			if g != nil {
+				u.Add(g.ResponseMetadata().Usage)
+			}
+		}
+	}
+	t.add(u)
+}
@@ -0,0 +1,44 @@
+package llms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationUsage(t *testing.T) {
+	t.Parallel()
+
+	u := GenerationUsage(map[string]any{
+		"PromptTokens":     float64(1000),
+		"CompletionTokens": float64(500),
+		"TotalTokens":      float64(1500),
+		"Model":            "gpt-4-0613",
+	})
+	require.Equal(t, 1500, u.TotalTokens)
+	require.InDelta(t, 0.06, u.CostUSD, 1e-9)
+
+	u = GenerationUsage(map[string]any{"PromptTokens": 10, "CompletionTokens": 5, "Model": "unknown"})
+	require.Equal(t, Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}, u)
+
+	price, ok := ModelPrice("gpt-3.5-turbo-16k-0613")
+	require.True(t, ok)
+	require.Equal(t, Price{Prompt: 0.003, Completion: 0.004}, price)
+}
+
+func TestTrackUsage(t *testing.T) {
+	t.Parallel()
+
+	result := LLMResult{Generations: [][]*Generation{{{GenerationInfo: map[string]any{"TotalTokens": 7}}}}}
+	TrackUsage(context.Background(), result)
+
+	outer := &UsageTracker{}
+	ctx := WithUsageTracker(context.Background(), outer)
+	TrackUsage(ctx, result)
+	inner := &UsageTracker{}
+	TrackUsage(WithUsageTracker(ctx, inner), result)
+
+	require.Equal(t, 7, inner.Usage().TotalTokens)
+	require.Equal(t, 14, outer.Usage().TotalTokens)
+}
@@ -116,18 +116,57 @@ func (c *PaLMClient) CreateCompletion(ctx context.Context, r *CompletionRequest)
 // EmbeddingRequest is a request to create an embedding.
 type EmbeddingRequest struct {
 	Input []string `json:"input"`
+	// Model is the embedding model, textembedding-gecko if empty.
+	Model string `json:"model,omitempty"`
+	// TaskType is the task the embeddings are used for, such as
+	// RETRIEVAL_DOCUMENT, for the models supporting it.
+	TaskType string `json:"task_type,omitempty"`
+	// OutputDimensionality is the number of dimensions the embeddings are
+	// truncated to, for the models supporting it.
+	OutputDimensionality int `json:"output_dimensionality,omitempty"`
 }
 
 // CreateEmbedding creates embeddings.
 func (c *PaLMClient) CreateEmbedding(ctx context.Context, r *EmbeddingRequest) ([][]float64, error) {
+	model := r.Model
+	if model == "" {
+		model = embeddingModelName
+	}
+	instances := make([]*structpb.Value, 0, len(r.Input))
+	for _, text := range r.Input {
+		instance := map[string]interface{}{"content": text}
+		if r.TaskType != "" {
+			instance["task_type"] = r.TaskType
+		}
+		content, err := structpb.NewStruct(instance)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, structpb.NewStructValue(content))
+	}
 	params := map[string]interface{}{}
-	responses, err := c.batchPredict(ctx, embeddingModelName, r.Input, params)
+	if r.OutputDimensionality > 0 {
+		params["outputDimensionality"] = r.OutputDimensionality
+	}
+	parameters, err := structpb.NewStruct(params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Predict(ctx, &aiplatformpb.PredictRequest{
+		Endpoint:   c.projectLocationPublisherModelPath(c.projectID, defaultLocation, defaultPublisher, model),
+		Instances:  instances,
+		Parameters: structpb.NewStructValue(parameters),
+	})
 	if err != nil {
 		return nil, err
 	}
+	if len(resp.Predictions) == 0 {
+		return nil, ErrEmptyResponse
+	}
 
 	embeddings := [][]float64{}
-	for _, res := range responses {
+	for _, res := range resp.Predictions {
 		value := res.GetStructValue().AsMap()
 		embedding, ok := value["embeddings"].(map[string]interface{})
 		if !ok {
@@ -22,7 +22,8 @@ const (
 )
 
 type LLM struct {
-	client *vertexaiclient.PaLMClient
+	client       *vertexaiclient.PaLMClient
+	rateLimiters *llms.RateLimiters
 }
 
 var (
@@ -47,6 +48,12 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 	for _, opt := range options {
 		opt(&opts)
 	}
+	ctx, cancel, _ := llms.ApplyLatencyBudget(ctx, &opts)
+	defer cancel()
+	// The prompts are sent in a single request.
+	if err := llms.ApplyRateLimit(ctx, o.rateLimiters, vertexaiclient.TextModelName, opts, prompts...); err != nil {
+		return nil, err
+	}
 	results, err := o.client.CreateCompletion(ctx, &vertexaiclient.CompletionRequest{
 		Prompts:     prompts,
 		MaxTokens:   opts.MaxTokens,
@@ -67,9 +74,20 @@ func (o *LLM) Generate(ctx context.Context, prompts []string, options ...llms.Ca
 
 // CreateEmbedding creates embeddings for the given input texts.
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float64, error) {
-	embeddings, err := o.client.CreateEmbedding(ctx, &vertexaiclient.EmbeddingRequest{
+	return o.CreateEmbeddingWithOptions(ctx, inputTexts)
+}
+
+// CreateEmbeddingWithOptions does the same as CreateEmbedding, with options
+// such as the embedding model, the task type or the output dimensionality.
+func (o *LLM) CreateEmbeddingWithOptions(ctx context.Context, inputTexts []string, options ...EmbeddingOption) ([][]float64, error) { //nolint:lll
+	req := &vertexaiclient.EmbeddingRequest{
 		Input: inputTexts,
-	})
+	}
+	for _, opt := range options {
+		opt(req)
+	}
+
+	embeddings, err := o.client.CreateEmbedding(ctx, req)
 	if err != nil {
 		return [][]float64{}, err
 	}
@@ -95,7 +113,8 @@ func (o *LLM) GetNumTokens(text string) int {
 type ChatMessage = vertexaiclient.ChatMessage
 
 type Chat struct {
-	client *vertexaiclient.PaLMClient
+	client       *vertexaiclient.PaLMClient
+	rateLimiters *llms.RateLimiters
 }
 
 var (
@@ -121,12 +140,17 @@ func (o *Chat) Generate(ctx context.Context, messageSets [][]schema.ChatMessage,
 	for _, opt := range options {
 		opt(&opts)
 	}
+	ctx, cancel, _ := llms.ApplyLatencyBudget(ctx, &opts)
+	defer cancel()
 	if opts.StreamingFunc != nil {
 		return nil, ErrNotImplemented
 	}
 
 	generations := make([]*llms.Generation, 0, len(messageSets))
 	for _, messages := range messageSets {
+		if err := llms.ApplyChatRateLimit(ctx, o.rateLimiters, vertexaiclient.ChatModelName, opts, messages); err != nil {
+			return nil, err
+		}
 		msgs := toClientChatMessage(messages)
 		result, err := o.client.CreateChat(ctx, &vertexaiclient.ChatRequest{
 			Temperature: opts.Temperature,
@@ -173,7 +197,7 @@ func toClientChatMessage(messages []schema.ChatMessage) []*vertexaiclient.ChatMe
 			msg.Author = userAuthor
 		case schema.ChatMessageTypeGeneric:
 			msg.Author = userAuthor
-		case schema.ChatMessageTypeFunction:
+		case schema.ChatMessageTypeFunction, schema.ChatMessageTypeTool:
 			msg.Author = userAuthor
 		}
 		if n, ok := m.(schema.Named); ok {
@@ -187,13 +211,13 @@ func toClientChatMessage(messages []schema.ChatMessage) []*vertexaiclient.ChatMe
 // New returns a new VertexAI PaLM LLM.
 func New(opts ...Option) (*LLM, error) {
 	client, err := newClient(opts...)
-	return &LLM{client: client}, err
+	return &LLM{client: client, rateLimiters: &llms.RateLimiters{}}, err
 }
 
 // New returns a new VertexAI PaLM Chat LLM.
 func NewChat(opts ...Option) (*Chat, error) {
 	client, err := newClient(opts...)
-	return &Chat{client: client}, err
+	return &Chat{client: client, rateLimiters: &llms.RateLimiters{}}, err
 }
 
 func newClient(opts ...Option) (*vertexaiclient.PaLMClient, error) {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/tmc/langchaingo/llms/vertexai/internal/vertexaiclient"
 	"google.golang.org/api/option"
 )
 
@@ -77,3 +78,44 @@ func convertByteArrayOption(fopt func([]byte) option.ClientOption) func([]byte)
 		}
 	}
 }
+
+// TaskType is the task embeddings are created for, letting the models
+// supporting it, such as text-embedding-004, optimize them.
+type TaskType string
+
+const (
+	TaskTypeRetrievalQuery     TaskType = "RETRIEVAL_QUERY"
+	TaskTypeRetrievalDocument  TaskType = "RETRIEVAL_DOCUMENT"
+	TaskTypeSemanticSimilarity TaskType = "SEMANTIC_SIMILARITY"
+	TaskTypeClassification     TaskType = "CLASSIFICATION"
+	TaskTypeClustering         TaskType = "CLUSTERING"
+	TaskTypeQuestionAnswering  TaskType = "QUESTION_ANSWERING"
+	TaskTypeFactVerification   TaskType = "FACT_VERIFICATION"
+)
+
+// EmbeddingOption is a function that configures the embeddings created by
+// CreateEmbeddingWithOptions.
+type EmbeddingOption func(*vertexaiclient.EmbeddingRequest)
+
+// WithEmbeddingModel sets the embedding model, such as "text-embedding-004".
+// If not set, "textembedding-gecko" is used.
+func WithEmbeddingModel(model string) EmbeddingOption {
+	return func(req *vertexaiclient.EmbeddingRequest) {
+		req.Model = model
+	}
+}
+
+// WithEmbeddingTaskType sets the task the embeddings are created for.
+func WithEmbeddingTaskType(taskType TaskType) EmbeddingOption {
+	return func(req *vertexaiclient.EmbeddingRequest) {
+		req.TaskType = string(taskType)
+	}
+}
+
+// WithEmbeddingDimensions sets the number of dimensions the embeddings are
+// truncated to. Only newer models, such as text-embedding-004, support it.
+func WithEmbeddingDimensions(dimensions int) EmbeddingOption {
+	return func(req *vertexaiclient.EmbeddingRequest) {
+		req.OutputDimensionality = dimensions
+	}
+}
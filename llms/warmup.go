@@ -0,0 +1,11 @@
+package llms
+
+import "context"
+
+// Warmer is implemented by language models able to prepare for their first
+// call, for example by loading their tokenizer and opening a connection to
+// the provider, to cut the latency of the first request.
+type Warmer interface {
+	// Warmup prepares the model. It may call the provider, but not generate.
+	Warmup(ctx context.Context) error
+}
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -22,8 +23,11 @@ type ConversationBuffer struct {
 	MemoryKey      string
 }
 
-// Statically assert that ConversationBuffer implement the memory interface.
-var _ schema.Memory = &ConversationBuffer{}
+// Statically assert that ConversationBuffer implement the memory and purger interfaces.
+var (
+	_ schema.Memory = &ConversationBuffer{}
+	_ schema.Purger = &ConversationBuffer{}
+)
 
 // NewConversationBuffer is a function for crating a new buffer memory.
 func NewConversationBuffer(options ...ConversationBufferOption) *ConversationBuffer {
@@ -95,6 +99,18 @@ func (m *ConversationBuffer) Clear() error {
 	return m.ChatHistory.Clear()
 }
 
+// Purge deletes all messages belonging to the subject from the chat history. If
+// the chat history does not implement schema.Purger, schema.ErrPurgeNotSupported
+// is returned.
+func (m *ConversationBuffer) Purge(ctx context.Context, subjectID string) error {
+	p, ok := m.ChatHistory.(schema.Purger)
+	if !ok {
+		return schema.ErrPurgeNotSupported
+	}
+
+	return p.Purge(ctx, subjectID)
+}
+
 func (m *ConversationBuffer) GetMemoryKey() string {
 	return m.MemoryKey
 }
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -114,3 +115,29 @@ func TestBufferMemoryWithChatHistoryOption(t *testing.T) {
 	expected := map[string]any{"history": "Human: user message test\nAI: ai message test"}
 	assert.Equal(t, expected, result)
 }
+
+// unpurgeableHistory hides the Purge method of the wrapped chat history.
+type unpurgeableHistory struct {
+	schema.ChatMessageHistory
+}
+
+func TestBufferMemoryPurge(t *testing.T) {
+	t.Parallel()
+
+	m := NewConversationBuffer(WithChatHistory(NewChatMessageHistory(WithSubjectID("user-1"))))
+	err := m.SaveContext(map[string]any{"foo": "bar"}, map[string]any{"bar": "foo"})
+	require.NoError(t, err)
+
+	err = m.Purge(context.Background(), "user-1")
+	require.NoError(t, err)
+	messages, err := m.ChatHistory.Messages()
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	err = NewConversationBuffer().Purge(context.Background(), "user-1")
+	assert.ErrorIs(t, err, ErrNoSubjectID)
+
+	m = NewConversationBuffer(WithChatHistory(unpurgeableHistory{NewChatMessageHistory()}))
+	err = m.Purge(context.Background(), "user-1")
+	assert.ErrorIs(t, err, schema.ErrPurgeNotSupported)
+}
@@ -1,14 +1,29 @@
 package memory
 
-import "github.com/tmc/langchaingo/schema"
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ErrNoSubjectID is returned when purging a chat history created without the
+// WithSubjectID option, as it can not be told whether it belongs to the subject.
+// It wraps schema.ErrPurgeNotSupported.
+var ErrNoSubjectID = fmt.Errorf("%w: chat history has no subject id", schema.ErrPurgeNotSupported)
 
 // ChatMessageHistory is a struct that stores chat messages.
 type ChatMessageHistory struct {
-	messages []schema.ChatMessage
+	messages  []schema.ChatMessage
+	subjectID string
 }
 
-// Statically assert that ChatMessageHistory implement the chat message history interface.
-var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+// Statically assert that ChatMessageHistory implement the chat message history
+// and purger interfaces.
+var (
+	_ schema.ChatMessageHistory = &ChatMessageHistory{}
+	_ schema.Purger             = &ChatMessageHistory{}
+)
 
 // NewChatMessageHistory creates a new ChatMessageHistory using chat message options.
 func NewChatMessageHistory(options ...ChatMessageHistoryOption) *ChatMessageHistory {
@@ -46,3 +61,20 @@ func (h *ChatMessageHistory) SetMessages(messages []schema.ChatMessage) error {
 	h.messages = messages
 	return nil
 }
+
+// Purge removes all messages if the history belongs to the given subject. The
+// subject of the history is set with the WithSubjectID option, and ErrNoSubjectID
+// is returned if it was not set.
+func (h *ChatMessageHistory) Purge(_ context.Context, subjectID string) error {
+	if subjectID == "" {
+		return schema.ErrMissingSubjectID
+	}
+	if h.subjectID == "" {
+		return ErrNoSubjectID
+	}
+	if h.subjectID != subjectID {
+		return nil
+	}
+
+	return h.Clear()
+}
@@ -14,6 +14,14 @@ func WithPreviousMessages(previousMessages []schema.ChatMessage) ChatMessageHist
 	}
 }
 
+// WithSubjectID is an option for NewChatMessageHistory for setting the id of
+// the user or session the history belongs to. The id is used by Purge.
+func WithSubjectID(subjectID string) ChatMessageHistoryOption {
+	return func(m *ChatMessageHistory) {
+		m.subjectID = subjectID
+	}
+}
+
 func applyChatOptions(options ...ChatMessageHistoryOption) *ChatMessageHistory {
 	h := &ChatMessageHistory{
 		messages: make([]schema.ChatMessage, 0),
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,3 +43,30 @@ func TestChatMessageHistory(t *testing.T) {
 		schema.HumanChatMessage{Content: "zoo"},
 	}, messages)
 }
+
+func TestChatMessageHistoryPurge(t *testing.T) {
+	t.Parallel()
+
+	h := NewChatMessageHistory(WithSubjectID("user-1"))
+	err := h.AddUserMessage("foo")
+	assert.NoError(t, err)
+
+	err = schema.PurgeAll(context.Background(), "user-2", h)
+	assert.NoError(t, err)
+	messages, err := h.Messages()
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+
+	err = schema.PurgeAll(context.Background(), "user-1", h)
+	assert.NoError(t, err)
+	messages, err = h.Messages()
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+
+	err = schema.PurgeAll(context.Background(), "", h)
+	assert.ErrorIs(t, err, schema.ErrMissingSubjectID)
+
+	err = NewChatMessageHistory().Purge(context.Background(), "user-1")
+	assert.ErrorIs(t, err, ErrNoSubjectID)
+	assert.ErrorIs(t, err, schema.ErrPurgeNotSupported)
+}
@@ -5,5 +5,8 @@ a variety of implementations for storing and retrieving that data.
 The main components of this package are:
 - ChatMessageHistory: a struct that stores chat messages.
 - ConversationBuffer: a simple form of memory that remembers previous conversational back and forths directly.
+- ConversationSummary: a memory keeping a summary of the conversation made with an llm.
+- ConversationSummaryBuffer: a memory keeping the latest messages within a token limit and a summary of the older ones.
+- EntityMemory: a memory keeping summaries of the facts learnt about the entities of the conversation in an EntityStore.
 */
 package memory
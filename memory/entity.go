@@ -0,0 +1,328 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultEntityExtractionTemplate = `You are an AI assistant reading the transcript of a conversation between an AI and a human. Extract all of the proper nouns from the last line of conversation. As a guideline, a proper noun is generally capitalized. You should definitely extract all names and places.
+
+The conversation history is provided just in case of a coreference (e.g. "What do you know about him" where "him" is defined in a previous line) -- ignore items mentioned there that are not in the last line.
+
+Return the output as a single comma-separated list, or NONE if there is nothing of note to return (e.g. the user is just issuing a greeting or having a simple conversation).
+
+EXAMPLE
+Conversation history:
+Person #1: how's it going today?
+AI: "It's going great! How about you?"
+Person #1: good! busy working on Langchain. lots to do.
+AI: "That sounds like a lot of work! What kind of things are you doing to make Langchain better?"
+Last line:
+Person #1: i'm trying to improve Langchain's interfaces, the UX, its integrations with various products the user might want ... a lot of stuff.
+Output: Langchain
+END OF EXAMPLE
+
+Conversation history (for reference only):
+{{.history}}
+Last line of conversation (for extraction):
+Human: {{.input}}
+
+Output:`
+
+const _defaultEntitySummarizationTemplate = `You are an AI assistant helping a human keep track of facts about relevant people, places, and concepts in their life. Update the summary of the provided entity in the "Entity" section based on the last line of your conversation with the human. If you are writing the summary for the first time, return a single sentence.
+The update should only include facts that are relayed in the last line of conversation about the provided entity, and should only contain facts about the provided entity.
+
+If there is no new information about the provided entity or the information is not worth noting (not an important or relevant fact to remember long-term), return the existing summary unchanged.
+
+Full conversation history (for context):
+{{.history}}
+
+Entity to summarize:
+{{.entity}}
+
+Existing summary of {{.entity}}:
+{{.summary}}
+
+Last line of conversation:
+Human: {{.input}}
+Updated summary:`
+
+const (
+	_defaultEntitiesKey = "entities"
+	_defaultEntityK     = 6
+	_noEntities         = "NONE"
+)
+
+// NewEntityExtractionPrompt returns the default prompt used to extract the
+// entities of the last input of a conversation. It has the "history" and
+// "input" input variables.
+func NewEntityExtractionPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_defaultEntityExtractionTemplate, []string{"history", "input"})
+}
+
+// NewEntitySummarizationPrompt returns the default prompt used to update the
+// summary of an entity. It has the "history", "entity", "summary" and "input"
+// input variables.
+func NewEntitySummarizationPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(
+		_defaultEntitySummarizationTemplate,
+		[]string{"history", "entity", "summary", "input"},
+	)
+}
+
+// EntityStore is a key-value store of the summaries of entities.
+type EntityStore interface {
+	// Get returns the summary of the entity, and whether it exists.
+	Get(entity string) (string, bool, error)
+	// Set sets the summary of the entity.
+	Set(entity, summary string) error
+	// Delete deletes the summary of the entity.
+	Delete(entity string) error
+	// Clear deletes the summaries of all the entities.
+	Clear() error
+}
+
+// InMemoryEntityStore is an entity store keeping the summaries in memory.
+type InMemoryEntityStore struct {
+	mu       sync.RWMutex
+	entities map[string]string
+}
+
+// Statically assert that InMemoryEntityStore implement the entity store interface.
+var _ EntityStore = &InMemoryEntityStore{}
+
+// NewInMemoryEntityStore creates a new InMemoryEntityStore.
+func NewInMemoryEntityStore() *InMemoryEntityStore {
+	return &InMemoryEntityStore{entities: make(map[string]string)}
+}
+
+func (s *InMemoryEntityStore) Get(entity string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	summary, ok := s.entities[entity]
+	return summary, ok, nil
+}
+
+func (s *InMemoryEntityStore) Set(entity, summary string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entities[entity] = summary
+	return nil
+}
+
+func (s *InMemoryEntityStore) Delete(entity string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entities, entity)
+	return nil
+}
+
+func (s *InMemoryEntityStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entities = make(map[string]string)
+	return nil
+}
+
+// EntityMemory is a memory extracting the named entities of each input of the
+// conversation with an llm, and keeping a summary of the facts learnt about
+// each of them in a store. The summaries of the entities of the input are
+// loaded alongside the latest messages of the conversation.
+type EntityMemory struct {
+	ConversationBuffer
+	LLM   llms.LanguageModel
+	Store EntityStore
+
+	ExtractionPrompt    prompts.PromptTemplate
+	SummarizationPrompt prompts.PromptTemplate
+	// EntitiesKey is the memory key of the summaries of the entities. Defaults
+	// to "entities".
+	EntitiesKey string
+	// K is the number of latest messages loaded and given to the llm as
+	// context. Defaults to 6.
+	K int
+
+	mu sync.Mutex
+	// entities are the entities of the input of the last load, whose
+	// summaries are updated when the context is saved.
+	entities []string
+}
+
+// Statically assert that EntityMemory implement the memory interface.
+var _ schema.Memory = &EntityMemory{}
+
+// NewEntityMemory creates a new entity memory using the llm to extract and
+// summarize the entities, and keeping their summaries in the store.
+func NewEntityMemory(llm llms.LanguageModel, store EntityStore, options ...ConversationBufferOption) *EntityMemory {
+	return &EntityMemory{
+		ConversationBuffer:  *applyBufferOptions(options...),
+		LLM:                 llm,
+		Store:               store,
+		ExtractionPrompt:    NewEntityExtractionPrompt(),
+		SummarizationPrompt: NewEntitySummarizationPrompt(),
+		EntitiesKey:         _defaultEntitiesKey,
+		K:                   _defaultEntityK,
+	}
+}
+
+// MemoryVariables returns the memory key of the messages and the one of the
+// summaries of the entities.
+func (m *EntityMemory) MemoryVariables() []string {
+	return []string{m.MemoryKey, m.EntitiesKey}
+}
+
+// LoadMemoryVariables extracts the entities of the input and returns their
+// summaries, one "entity: summary" line each, with the latest messages. If
+// ReturnMessages is set to true the messages are returned as a slice of
+// schema.ChatMessage.
+func (m *EntityMemory) LoadMemoryVariables(inputs map[string]any) (map[string]any, error) {
+	input, err := getInputValue(inputs, m.InputKey)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := m.latestMessages()
+	if err != nil {
+		return nil, err
+	}
+	history, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := predict(m.LLM, m.ExtractionPrompt, map[string]any{"history": history, "input": input})
+	if err != nil {
+		return nil, err
+	}
+	entities := parseEntities(output)
+
+	summaries := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		summary, ok, err := m.Store.Get(entity)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			summaries = append(summaries, fmt.Sprintf("%s: %s", entity, summary))
+		}
+	}
+
+	m.mu.Lock()
+	m.entities = entities
+	m.mu.Unlock()
+
+	if m.ReturnMessages {
+		return map[string]any{m.MemoryKey: messages, m.EntitiesKey: strings.Join(summaries, "\n")}, nil
+	}
+	return map[string]any{m.MemoryKey: history, m.EntitiesKey: strings.Join(summaries, "\n")}, nil
+}
+
+// SaveContext saves the messages and updates the summaries of the entities
+// extracted by the last load.
+func (m *EntityMemory) SaveContext(inputValues map[string]any, outputValues map[string]any) error {
+	if err := m.ConversationBuffer.SaveContext(inputValues, outputValues); err != nil {
+		return err
+	}
+	input, err := getInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return err
+	}
+	messages, err := m.latestMessages()
+	if err != nil {
+		return err
+	}
+	history, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	entities := m.entities
+	m.entities = nil
+	m.mu.Unlock()
+
+	for _, entity := range entities {
+		summary, _, err := m.Store.Get(entity)
+		if err != nil {
+			return err
+		}
+		summary, err = predict(m.LLM, m.SummarizationPrompt, map[string]any{
+			"history": history,
+			"entity":  entity,
+			"summary": summary,
+			"input":   input,
+		})
+		if err != nil {
+			return err
+		}
+		if err := m.Store.Set(entity, summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear clears the chat history and the entity store.
+func (m *EntityMemory) Clear() error {
+	m.mu.Lock()
+	m.entities = nil
+	m.mu.Unlock()
+	if err := m.Store.Clear(); err != nil {
+		return err
+	}
+	return m.ConversationBuffer.Clear()
+}
+
+func (m *EntityMemory) latestMessages() ([]schema.ChatMessage, error) {
+	messages, err := m.ChatHistory.Messages()
+	if err != nil {
+		return nil, err
+	}
+	if m.K > 0 && len(messages) > m.K {
+		messages = messages[len(messages)-m.K:]
+	}
+	return messages, nil
+}
+
+// parseEntities parses the comma separated entities returned by the llm,
+// without duplicates and in a stable order.
+func parseEntities(output string) []string {
+	output = strings.TrimSpace(output)
+	if output == "" || strings.EqualFold(output, _noEntities) {
+		return nil
+	}
+	seen := make(map[string]bool)
+	entities := make([]string, 0)
+	for _, entity := range strings.Split(output, ",") {
+		entity = strings.TrimSpace(entity)
+		if entity == "" || seen[entity] {
+			continue
+		}
+		seen[entity] = true
+		entities = append(entities, entity)
+	}
+	sort.Strings(entities)
+	return entities
+}
+
+// predict returns the text generated by the llm for the prompt.
+func predict(llm llms.LanguageModel, prompt prompts.PromptTemplate, values map[string]any) (string, error) {
+	promptValue, err := prompt.FormatPrompt(values)
+	if err != nil {
+		return "", err
+	}
+	result, err := llm.GeneratePrompt(context.Background(), []schema.PromptValue{promptValue})
+	if err != nil {
+		return "", err
+	}
+	if len(result.Generations) == 0 || len(result.Generations[0]) == 0 {
+		return "", nil
+	}
+	return strings.TrimSpace(result.Generations[0][0].Text), nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+var (
+	lastLineRegexp = regexp.MustCompile(`(?s)Last line of conversation \(for extraction\):\nHuman: (.*)\n\nOutput:`)
+	entityRegexp   = regexp.MustCompile(`(?s)Entity to summarize:\n(.*?)\n\nExisting summary of .*?:\n(.*?)\n\nLast line of conversation:\nHuman: (.*)\nUpdated summary:`) //nolint:lll
+)
+
+// entityLLM extracts the capitalized words of the last line as entities, and
+// summarizes an entity by appending the last line to its summary.
+type entityLLM struct{}
+
+var _ llms.LanguageModel = entityLLM{}
+
+func (entityLLM) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	prompt := promptValues[0].String()
+	var text string
+	if m := lastLineRegexp.FindStringSubmatch(prompt); m != nil {
+		var entities []string
+		for _, word := range strings.Fields(m[1]) {
+			if word[0] >= 'A' && word[0] <= 'Z' {
+				entities = append(entities, strings.Trim(word, ".,"))
+			}
+		}
+		text = _noEntities
+		if len(entities) > 0 {
+			text = strings.Join(entities, ", ")
+		}
+	} else if m := entityRegexp.FindStringSubmatch(prompt); m != nil {
+		text = strings.TrimSpace(m[2] + " " + m[3])
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: text}}}}, nil
+}
+
+func (entityLLM) GetNumTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestEntityMemory(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryEntityStore()
+	m := NewEntityMemory(entityLLM{}, store)
+	assert.Equal(t, []string{"history", "entities"}, m.MemoryVariables())
+
+	turn := func(input, output string) map[string]any {
+		t.Helper()
+		vars, err := m.LoadMemoryVariables(map[string]any{"input": input})
+		require.NoError(t, err)
+		require.NoError(t, m.SaveContext(map[string]any{"input": input}, map[string]any{"output": output}))
+		return vars
+	}
+
+	vars := turn("my friend Sam lives in Paris", "nice")
+	assert.Equal(t, "", vars["entities"])
+	summary, ok, err := store.Get("Sam")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "my friend Sam lives in Paris", summary)
+
+	turn("hello", "hi")
+
+	vars = turn("what does Sam do", "no idea")
+	assert.Equal(t, "Sam: my friend Sam lives in Paris", vars["entities"])
+	assert.Equal(t, "Human: my friend Sam lives in Paris\nAI: nice\nHuman: hello\nAI: hi", vars["history"])
+	summary, _, err = store.Get("Sam")
+	require.NoError(t, err)
+	assert.Equal(t, "my friend Sam lives in Paris what does Sam do", summary)
+	summary, _, err = store.Get("Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "my friend Sam lives in Paris", summary)
+
+	require.NoError(t, m.Clear())
+	_, ok, err = store.Get("Sam")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestParseEntities(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, parseEntities(" NONE\n"))
+	assert.Empty(t, parseEntities(""))
+	assert.Equal(t, []string{"Paris", "Sam"}, parseEntities("Sam, Paris, Sam,"))
+}
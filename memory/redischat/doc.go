@@ -0,0 +1,12 @@
+// Package redischat contains an implementation of the chat message history
+// interface storing the messages of a session in a Redis list, so that
+// conversations are shared by the instances of a service and expire after a
+// time without activity:
+//
+//	history, err := redischat.New("localhost:6379", sessionID, redischat.WithTTL(24*time.Hour))
+//	defer history.Close()
+//	mem := memory.NewConversationBuffer(memory.WithChatHistory(history))
+//
+// The messages are stored under the "message_store:" prefix, as by the Python
+// RedisChatMessageHistory.
+package redischat
@@ -0,0 +1,39 @@
+package redischat
+
+import "time"
+
+// Option is a function type that can be used to modify the chat message history.
+type Option func(h *ChatMessageHistory)
+
+// WithTTL is an option for setting the time after which the messages of the
+// session expire, counted from the last message added. Defaults to no
+// expiration.
+func WithTTL(ttl time.Duration) Option {
+	return func(h *ChatMessageHistory) {
+		h.ttl = ttl
+	}
+}
+
+// WithKeyPrefix is an option for setting the prefix of the key of the list of
+// the messages, followed by the session id. Defaults to "message_store:".
+func WithKeyPrefix(prefix string) Option {
+	return func(h *ChatMessageHistory) {
+		h.keyPrefix = prefix
+	}
+}
+
+// WithPassword is an option for setting the password the connections
+// authenticate with.
+func WithPassword(password string) Option {
+	return func(h *ChatMessageHistory) {
+		h.password = password
+	}
+}
+
+// WithDB is an option for setting the database the messages are stored in.
+// Defaults to 0.
+func WithDB(db int) Option {
+	return func(h *ChatMessageHistory) {
+		h.db = db
+	}
+}
@@ -0,0 +1,158 @@
+package redischat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tmc/langchaingo/internal/redis"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultKeyPrefix = "message_store:"
+
+var (
+	// ErrMissingSessionID is returned when the chat history is created without
+	// a session id.
+	ErrMissingSessionID = errors.New("missing session id")
+	// ErrRedis is returned when the Redis server replies with an error or a
+	// reply that can not be parsed.
+	ErrRedis = redis.ErrRedis
+)
+
+// ChatMessageHistory is a chat message history stored in a Redis list, with
+// an element per message.
+type ChatMessageHistory struct {
+	addr      string
+	password  string
+	db        int
+	keyPrefix string
+	ttl       time.Duration
+	key       string
+
+	client *redis.Client
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// storedMessage is the encoding of a message in the list.
+type storedMessage struct {
+	Type schema.ChatMessageType `json:"type"`
+	Data json.RawMessage        `json:"data"`
+}
+
+// New creates a chat message history for the session, stored in the Redis
+// server at the address, such as "localhost:6379". Connections are opened
+// when needed.
+func New(addr, sessionID string, options ...Option) (*ChatMessageHistory, error) {
+	h := &ChatMessageHistory{addr: addr, keyPrefix: _defaultKeyPrefix}
+	for _, opt := range options {
+		opt(h)
+	}
+	if sessionID == "" {
+		return nil, ErrMissingSessionID
+	}
+	h.key = h.keyPrefix + sessionID
+	h.client = redis.New(h.addr, h.password, h.db)
+	return h, nil
+}
+
+// Messages returns the messages of the session in the order they were added.
+func (h *ChatMessageHistory) Messages() ([]schema.ChatMessage, error) {
+	reply, err := h.client.Do(context.Background(), "LRANGE", h.key, "0", "-1")
+	if err != nil {
+		return nil, err
+	}
+	elements, _ := reply.([]any)
+	messages := make([]schema.ChatMessage, 0, len(elements))
+	for _, element := range elements {
+		data, ok := element.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected reply to LRANGE", ErrRedis)
+		}
+		var stored storedMessage
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, err
+		}
+		message, err := schema.UnmarshalChatMessage(stored.Type, stored.Data)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(text string) error {
+	return h.AddMessage(schema.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds an user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(text string) error {
+	return h.AddMessage(schema.HumanChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the chat message history, and restarts the
+// time to live of the messages of the session.
+func (h *ChatMessageHistory) AddMessage(message schema.ChatMessage) error {
+	data, err := encode(message)
+	if err != nil {
+		return err
+	}
+	_, err = h.client.Pipeline(context.Background(), h.withExpiry([]string{"RPUSH", h.key, data})...)
+	return err
+}
+
+// Clear removes the messages of the session.
+func (h *ChatMessageHistory) Clear() error {
+	_, err := h.client.Do(context.Background(), "DEL", h.key)
+	return err
+}
+
+// SetMessages replaces the messages of the session in a transaction.
+func (h *ChatMessageHistory) SetMessages(messages []schema.ChatMessage) error {
+	commands := [][]string{{"MULTI"}, {"DEL", h.key}}
+	if len(messages) > 0 {
+		push := []string{"RPUSH", h.key}
+		for _, message := range messages {
+			data, err := encode(message)
+			if err != nil {
+				return err
+			}
+			push = append(push, data)
+		}
+		commands = append(commands, h.withExpiry(push)...)
+	}
+	commands = append(commands, []string{"EXEC"})
+	_, err := h.client.Pipeline(context.Background(), commands...)
+	return err
+}
+
+// Close closes the idle connections to the server.
+func (h *ChatMessageHistory) Close() error {
+	return h.client.Close()
+}
+
+// withExpiry returns the command followed by the command setting the time to
+// live of the key, if any.
+func (h *ChatMessageHistory) withExpiry(command []string) [][]string {
+	commands := [][]string{command}
+	if ms := h.ttl.Milliseconds(); ms > 0 {
+		commands = append(commands, []string{"PEXPIRE", h.key, strconv.FormatInt(ms, 10)})
+	}
+	return commands
+}
+
+func encode(message schema.ChatMessage) (string, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return "", err
+	}
+	stored, err := json.Marshal(storedMessage{Type: message.GetType(), Data: data})
+	return string(stored), err
+}
@@ -0,0 +1,75 @@
+package redischat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/internal/redis/redistest"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	server := redistest.NewServer(t)
+	h, err := New(server.Addr, "session-1", WithTTL(time.Hour), WithPassword("secret"))
+	require.NoError(t, err)
+	defer h.Close()
+	other, err := New(server.Addr, "session-2")
+	require.NoError(t, err)
+	defer other.Close()
+
+	require.NoError(t, h.AddUserMessage("hi"))
+	require.NoError(t, h.AddAIMessage("hello"))
+	require.NoError(t, h.AddMessage(schema.AIChatMessage{
+		ToolCalls: []schema.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &schema.FunctionCall{Name: "search", Arguments: `{"q":"otters"}`},
+		}},
+	}))
+	require.NoError(t, h.AddMessage(schema.ToolChatMessage{ID: "call_1", Name: "search", Content: "otters"}))
+	require.NoError(t, other.AddUserMessage("other session"))
+	require.Equal(t, "3600000", server.TTL("message_store:session-1"))
+	require.Empty(t, server.TTL("message_store:session-2"))
+
+	// A new history of the session reads the same messages.
+	reopened, err := New(server.Addr, "session-1")
+	require.NoError(t, err)
+	defer reopened.Close()
+	messages, err := reopened.Messages()
+	require.NoError(t, err)
+	require.Equal(t, []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "hi"},
+		schema.AIChatMessage{Content: "hello"},
+		schema.AIChatMessage{ToolCalls: []schema.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &schema.FunctionCall{Name: "search", Arguments: `{"q":"otters"}`},
+		}}},
+		schema.ToolChatMessage{ID: "call_1", Name: "search", Content: "otters"},
+	}, messages)
+
+	require.NoError(t, h.SetMessages([]schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be brief"},
+		schema.GenericChatMessage{Role: "moderator", Content: "ok"},
+	}))
+	messages, err = h.Messages()
+	require.NoError(t, err)
+	require.Equal(t, []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be brief"},
+		schema.GenericChatMessage{Role: "moderator", Content: "ok"},
+	}, messages)
+
+	require.NoError(t, h.Clear())
+	messages, err = h.Messages()
+	require.NoError(t, err)
+	require.Empty(t, messages)
+	messages, err = other.Messages()
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	_, err = New(server.Addr, "")
+	require.ErrorIs(t, err, ErrMissingSessionID)
+}
@@ -0,0 +1,12 @@
+// Package sqlchat contains an implementation of the chat message history
+// interface storing the messages in a SQLite or PostgreSQL database, so that
+// conversations persist across restarts and can be shared by several
+// instances of a service. The driver of the database must be imported by the
+// caller, for example:
+//
+//	import _ "github.com/jackc/pgx/v5/stdlib"
+//
+//	db, err := sql.Open("pgx", dsn)
+//	history, err := sqlchat.New(ctx, db, sessionID, sqlchat.WithDialect(sqlchat.Postgres))
+//	mem := memory.NewConversationBuffer(memory.WithChatHistory(history))
+package sqlchat
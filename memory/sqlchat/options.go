@@ -0,0 +1,20 @@
+package sqlchat
+
+// Option is a function type that can be used to modify the chat message history.
+type Option func(h *ChatMessageHistory)
+
+// WithDialect is an option for setting the SQL dialect of the database.
+// Defaults to SQLite.
+func WithDialect(dialect Dialect) Option {
+	return func(h *ChatMessageHistory) {
+		h.dialect = dialect
+	}
+}
+
+// WithTableName is an option for setting the table of the messages. Defaults
+// to "langchain_chat_history".
+func WithTableName(tableName string) Option {
+	return func(h *ChatMessageHistory) {
+		h.tableName = tableName
+	}
+}
@@ -0,0 +1,203 @@
+package sqlchat
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultTableName = "langchain_chat_history"
+
+var (
+	// ErrInvalidTableName is returned when the table name is not a valid SQL
+	// identifier.
+	ErrInvalidTableName = errors.New("invalid table name")
+	// ErrUnsupportedDialect is returned when the dialect is neither SQLite nor
+	// Postgres.
+	ErrUnsupportedDialect = errors.New("unsupported dialect")
+	// ErrMissingSessionID is returned when the chat history is created without
+	// a session id.
+	ErrMissingSessionID = errors.New("missing session id")
+)
+
+var tableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Dialect is the SQL dialect of the database.
+type Dialect string
+
+const (
+	// SQLite is the dialect of SQLite databases, for example opened with the
+	// github.com/mattn/go-sqlite3 driver.
+	SQLite Dialect = "sqlite3"
+	// Postgres is the dialect of PostgreSQL databases, for example opened with
+	// the github.com/jackc/pgx/v5/stdlib driver.
+	Postgres Dialect = "postgres"
+)
+
+// ChatMessageHistory is a chat message history stored in a SQL database, with
+// a row per message. Histories of different sessions can share a table.
+type ChatMessageHistory struct {
+	db        *sql.DB
+	dialect   Dialect
+	tableName string
+	sessionID string
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// New creates a chat message history for the session, creating the table of
+// the messages if it does not exist.
+func New(ctx context.Context, db *sql.DB, sessionID string, options ...Option) (*ChatMessageHistory, error) {
+	h := &ChatMessageHistory{
+		db:        db,
+		dialect:   SQLite,
+		tableName: _defaultTableName,
+		sessionID: sessionID,
+	}
+	for _, opt := range options {
+		opt(h)
+	}
+
+	if h.sessionID == "" {
+		return nil, ErrMissingSessionID
+	}
+	if !tableNameRegexp.MatchString(h.tableName) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTableName, h.tableName)
+	}
+
+	var idColumn string
+	switch h.dialect {
+	case SQLite:
+		idColumn = "id INTEGER PRIMARY KEY AUTOINCREMENT"
+	case Postgres:
+		idColumn = "id BIGSERIAL PRIMARY KEY"
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedDialect, h.dialect)
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	%s,
+	session_id TEXT NOT NULL,
+	type TEXT NOT NULL,
+	message TEXT NOT NULL
+)`, h.tableName, idColumn))
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf(
+		"CREATE INDEX IF NOT EXISTS %s_session_id ON %s (session_id, id)", h.tableName, h.tableName,
+	))
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Messages returns the messages of the session in the order they were added.
+func (h *ChatMessageHistory) Messages() ([]schema.ChatMessage, error) {
+	rows, err := h.db.QueryContext(context.Background(), h.query(
+		"SELECT type, message FROM %s WHERE session_id = ? ORDER BY id",
+	), h.sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]schema.ChatMessage, 0)
+	for rows.Next() {
+		var typ, data string
+		if err := rows.Scan(&typ, &data); err != nil {
+			return nil, err
+		}
+		message, err := schema.UnmarshalChatMessage(schema.ChatMessageType(typ), []byte(data))
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(text string) error {
+	return h.AddMessage(schema.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds an user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(text string) error {
+	return h.AddMessage(schema.HumanChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the chat message history.
+func (h *ChatMessageHistory) AddMessage(message schema.ChatMessage) error {
+	return h.insert(context.Background(), h.db, message)
+}
+
+// Clear removes the messages of the session.
+func (h *ChatMessageHistory) Clear() error {
+	_, err := h.db.ExecContext(context.Background(), h.query("DELETE FROM %s WHERE session_id = ?"), h.sessionID)
+	return err
+}
+
+// SetMessages replaces the messages of the session in a transaction.
+func (h *ChatMessageHistory) SetMessages(messages []schema.ChatMessage) error {
+	ctx := context.Background()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, h.query("DELETE FROM %s WHERE session_id = ?"), h.sessionID); err != nil {
+		return err
+	}
+	for _, message := range messages {
+		if err := h.insert(ctx, tx, message); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (h *ChatMessageHistory) insert(ctx context.Context, db execer, message schema.ChatMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, h.query(
+		"INSERT INTO %s (session_id, type, message) VALUES (?, ?, ?)",
+	), h.sessionID, string(message.GetType()), string(data))
+	return err
+}
+
+// query returns the query on the table of the history, with the "?"
+// placeholders rewritten for the dialect.
+func (h *ChatMessageHistory) query(format string) string {
+	query := fmt.Sprintf(format, h.tableName)
+	if h.dialect != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,123 @@
+package sqlchat
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func testHistory(t *testing.T, db *sql.DB, options ...Option) {
+	t.Helper()
+	ctx := context.Background()
+
+	h, err := New(ctx, db, "session-1", options...)
+	require.NoError(t, err)
+	other, err := New(ctx, db, "session-2", options...)
+	require.NoError(t, err)
+	require.NoError(t, h.Clear())
+	require.NoError(t, other.Clear())
+
+	require.NoError(t, h.AddUserMessage("hi"))
+	require.NoError(t, h.AddAIMessage("hello"))
+	require.NoError(t, h.AddMessage(schema.AIChatMessage{
+		ToolCalls: []schema.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &schema.FunctionCall{Name: "search", Arguments: `{"q":"otters"}`},
+		}},
+	}))
+	require.NoError(t, h.AddMessage(schema.ToolChatMessage{ID: "call_1", Name: "search", Content: "otters"}))
+	require.NoError(t, other.AddUserMessage("other session"))
+
+	// A new history of the session reads the same messages.
+	reopened, err := New(ctx, db, "session-1", options...)
+	require.NoError(t, err)
+	messages, err := reopened.Messages()
+	require.NoError(t, err)
+	require.Equal(t, []schema.ChatMessage{
+		schema.HumanChatMessage{Content: "hi"},
+		schema.AIChatMessage{Content: "hello"},
+		schema.AIChatMessage{ToolCalls: []schema.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &schema.FunctionCall{Name: "search", Arguments: `{"q":"otters"}`},
+		}}},
+		schema.ToolChatMessage{ID: "call_1", Name: "search", Content: "otters"},
+	}, messages)
+
+	require.NoError(t, h.SetMessages([]schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be brief"},
+		schema.GenericChatMessage{Role: "moderator", Content: "ok"},
+	}))
+	messages, err = h.Messages()
+	require.NoError(t, err)
+	require.Equal(t, []schema.ChatMessage{
+		schema.SystemChatMessage{Content: "be brief"},
+		schema.GenericChatMessage{Role: "moderator", Content: "ok"},
+	}, messages)
+
+	require.NoError(t, h.Clear())
+	messages, err = h.Messages()
+	require.NoError(t, err)
+	require.Empty(t, messages)
+
+	messages, err = other.Messages()
+	require.NoError(t, err)
+	require.Equal(t, []schema.ChatMessage{schema.HumanChatMessage{Content: "other session"}}, messages)
+}
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "chat.sqlite"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	testHistory(t, db)
+}
+
+func TestPostgres(t *testing.T) {
+	t.Parallel()
+
+	dsn := os.Getenv("PGVECTOR_CONNECTION_STRING")
+	if dsn == "" {
+		t.Skip("PGVECTOR_CONNECTION_STRING not set")
+	}
+	db, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	defer db.Close()
+
+	testHistory(t, db, WithDialect(Postgres), WithTableName("langchain_chat_history_test"))
+}
+
+func TestNewErrors(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "chat.sqlite"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = New(context.Background(), db, "")
+	require.ErrorIs(t, err, ErrMissingSessionID)
+	_, err = New(context.Background(), db, "s", WithTableName("chat; DROP TABLE x"))
+	require.ErrorIs(t, err, ErrInvalidTableName)
+	_, err = New(context.Background(), db, "s", WithDialect("mssql"))
+	require.ErrorIs(t, err, ErrUnsupportedDialect)
+}
+
+func TestPostgresPlaceholders(t *testing.T) {
+	t.Parallel()
+
+	h := &ChatMessageHistory{dialect: Postgres, tableName: "chat"}
+	require.Equal(t,
+		"INSERT INTO chat (session_id, type, message) VALUES ($1, $2, $3)",
+		h.query("INSERT INTO %s (session_id, type, message) VALUES (?, ?, ?)"),
+	)
+}
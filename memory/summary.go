@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultSummaryTemplate = `Progressively summarize the lines of conversation provided, adding onto the previous summary returning a new summary.
+
+EXAMPLE
+Current summary:
+The human asks what the AI thinks of artificial intelligence. The AI thinks artificial intelligence is a force for good.
+
+New lines of conversation:
+Human: Why do you think artificial intelligence is a force for good?
+AI: Because artificial intelligence will help humans reach their full potential.
+
+New summary:
+The human asks what the AI thinks of artificial intelligence. The AI thinks artificial intelligence is a force for good because it will help humans reach their full potential.
+END OF EXAMPLE
+
+Current summary:
+{{.summary}}
+
+New lines of conversation:
+{{.new_lines}}
+
+New summary:`
+
+// NewSummaryPrompt returns the default prompt used to summarize conversations.
+// It has the "summary" and "new_lines" input variables.
+func NewSummaryPrompt() prompts.PromptTemplate {
+	return prompts.NewPromptTemplate(_defaultSummaryTemplate, []string{"summary", "new_lines"})
+}
+
+// ConversationSummary is a memory summarizing the conversation with an llm
+// as it goes, so that long conversations stay within the context limits.
+type ConversationSummary struct {
+	ConversationBuffer
+	LLM    llms.LanguageModel
+	Prompt prompts.PromptTemplate
+	// Summary is the summary of the conversation so far.
+	Summary string
+}
+
+// Statically assert that ConversationSummary implement the memory interface.
+var _ schema.Memory = &ConversationSummary{}
+
+// NewConversationSummary creates a new summary memory using the llm to
+// summarize the conversation.
+func NewConversationSummary(llm llms.LanguageModel, options ...ConversationBufferOption) *ConversationSummary {
+	return &ConversationSummary{
+		ConversationBuffer: *applyBufferOptions(options...),
+		LLM:                llm,
+		Prompt:             NewSummaryPrompt(),
+	}
+}
+
+// LoadMemoryVariables returns the summary of the conversation. If
+// ReturnMessages is set to true the summary is returned as a system message.
+func (m *ConversationSummary) LoadMemoryVariables(map[string]any) (map[string]any, error) {
+	if m.ReturnMessages {
+		return map[string]any{m.MemoryKey: summaryMessages(m.Summary)}, nil
+	}
+	return map[string]any{m.MemoryKey: m.Summary}, nil
+}
+
+// SaveContext adds the new lines of the conversation to the summary.
+func (m *ConversationSummary) SaveContext(inputValues map[string]any, outputValues map[string]any) error {
+	if err := m.ConversationBuffer.SaveContext(inputValues, outputValues); err != nil {
+		return err
+	}
+	messages, err := m.ChatHistory.Messages()
+	if err != nil {
+		return err
+	}
+	summary, err := summarize(m.LLM, m.Prompt, m.Summary, messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return err
+	}
+	m.Summary = summary
+	return m.ChatHistory.SetMessages(nil)
+}
+
+// Clear clears the chat history and the summary.
+func (m *ConversationSummary) Clear() error {
+	m.Summary = ""
+	return m.ConversationBuffer.Clear()
+}
+
+// ConversationSummaryBuffer is a memory keeping the latest messages of the
+// conversation within a token limit, and a summary of the older messages made
+// with an llm.
+type ConversationSummaryBuffer struct {
+	ConversationSummary
+	MaxTokenLimit int
+}
+
+// Statically assert that ConversationSummaryBuffer implement the memory interface.
+var _ schema.Memory = &ConversationSummaryBuffer{}
+
+// NewConversationSummaryBuffer creates a new summary buffer memory keeping
+// the latest messages within maxTokenLimit tokens, as counted by the llm.
+func NewConversationSummaryBuffer(
+	llm llms.LanguageModel,
+	maxTokenLimit int,
+	options ...ConversationBufferOption,
+) *ConversationSummaryBuffer {
+	return &ConversationSummaryBuffer{
+		ConversationSummary: *NewConversationSummary(llm, options...),
+		MaxTokenLimit:       maxTokenLimit,
+	}
+}
+
+// LoadMemoryVariables returns the summary of the older messages followed by
+// the latest messages. If ReturnMessages is set to true the summary is
+// returned as a system message before the latest messages.
+func (m *ConversationSummaryBuffer) LoadMemoryVariables(map[string]any) (map[string]any, error) {
+	messages, err := m.ChatHistory.Messages()
+	if err != nil {
+		return nil, err
+	}
+	messages = append(summaryMessages(m.Summary), messages...)
+	if m.ReturnMessages {
+		return map[string]any{m.MemoryKey: messages}, nil
+	}
+
+	bufferString, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{m.MemoryKey: bufferString}, nil
+}
+
+// SaveContext saves the messages and, when the messages exceed the token
+// limit, adds the oldest messages to the summary.
+func (m *ConversationSummaryBuffer) SaveContext(inputValues map[string]any, outputValues map[string]any) error {
+	if err := m.ConversationBuffer.SaveContext(inputValues, outputValues); err != nil {
+		return err
+	}
+	messages, err := m.ChatHistory.Messages()
+	if err != nil {
+		return err
+	}
+
+	var pruned []schema.ChatMessage
+	for len(messages) > 0 {
+		bufferString, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+		if err != nil {
+			return err
+		}
+		if m.LLM.GetNumTokens(bufferString) <= m.MaxTokenLimit {
+			break
+		}
+		pruned = append(pruned, messages[0])
+		messages = messages[1:]
+	}
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	summary, err := summarize(m.LLM, m.Prompt, m.Summary, pruned, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return err
+	}
+	m.Summary = summary
+	return m.ChatHistory.SetMessages(messages)
+}
+
+// summarize returns the summary with the messages added using the llm.
+func summarize(
+	llm llms.LanguageModel,
+	prompt prompts.PromptTemplate,
+	summary string,
+	messages []schema.ChatMessage,
+	humanPrefix, aiPrefix string,
+) (string, error) {
+	newLines, err := schema.GetBufferString(messages, humanPrefix, aiPrefix)
+	if err != nil {
+		return "", err
+	}
+	newSummary, err := predict(llm, prompt, map[string]any{"summary": summary, "new_lines": newLines})
+	if err != nil {
+		return "", err
+	}
+	if newSummary == "" {
+		return summary, nil
+	}
+	return newSummary, nil
+}
+
+func summaryMessages(summary string) []schema.ChatMessage {
+	if summary == "" {
+		return []schema.ChatMessage{}
+	}
+	return []schema.ChatMessage{schema.SystemChatMessage{Content: summary}}
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// summarizingLLM summarizes by appending the first word of each new line to
+// the current summary, and counts tokens as words.
+type summarizingLLM struct{}
+
+var _ llms.LanguageModel = summarizingLLM{}
+
+func (summarizingLLM) GeneratePrompt(_ context.Context, promptValues []schema.PromptValue, _ ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	prompt := promptValues[0].String()
+	parts := strings.Split(prompt, "Current summary:\n")
+	current := strings.SplitN(parts[len(parts)-1], "\n", 2)[0]
+	newLines := strings.Split(strings.Split(prompt, "New lines of conversation:\n")[2], "\n\nNew summary:")[0]
+	words := strings.Fields(current)
+	for _, line := range strings.Split(newLines, "\n") {
+		words = append(words, strings.Fields(line)[1])
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: strings.Join(words, " ")}}}}, nil
+}
+
+func (summarizingLLM) GetNumTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestConversationSummary(t *testing.T) {
+	t.Parallel()
+
+	m := NewConversationSummary(summarizingLLM{})
+	require.NoError(t, m.SaveContext(map[string]any{"input": "hello there"}, map[string]any{"output": "hi human"}))
+	require.NoError(t, m.SaveContext(map[string]any{"input": "bye now"}, map[string]any{"output": "see you"}))
+
+	vars, err := m.LoadMemoryVariables(map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "hello hi bye see"}, vars)
+	messages, err := m.ChatHistory.Messages()
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	require.NoError(t, m.Clear())
+	vars, err = m.LoadMemoryVariables(map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, vars)
+}
+
+func TestConversationSummaryBuffer(t *testing.T) {
+	t.Parallel()
+
+	m := NewConversationSummaryBuffer(summarizingLLM{}, 8)
+	require.NoError(t, m.SaveContext(map[string]any{"input": "one"}, map[string]any{"output": "two"}))
+	vars, err := m.LoadMemoryVariables(map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "Human: one\nAI: two"}, vars)
+
+	require.NoError(t, m.SaveContext(map[string]any{"input": "three"}, map[string]any{"output": "four"}))
+	require.NoError(t, m.SaveContext(map[string]any{"input": "five"}, map[string]any{"output": "six"}))
+	vars, err = m.LoadMemoryVariables(map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "System: one two\nHuman: three\nAI: four\nHuman: five\nAI: six"}, vars)
+
+	m.ReturnMessages = true
+	vars, err = m.LoadMemoryVariables(map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, schema.SystemChatMessage{Content: "one two"}, vars["history"].([]schema.ChatMessage)[0]) //nolint:forcetypeassert,lll
+}
@@ -8,7 +8,10 @@ import (
 // ConversationTokenBuffer for storing conversation memory.
 type ConversationTokenBuffer struct {
 	ConversationBuffer
-	LLM           llms.LanguageModel
+	LLM llms.LanguageModel
+	// Model is the name of the model the tokens are counted for with
+	// llms.CountTokens. If empty, the tokens are counted by the LLM.
+	Model         string
 	MaxTokenLimit int
 }
 
@@ -30,6 +33,21 @@ func NewConversationTokenBuffer(
 	return tb
 }
 
+// NewConversationTokenBufferForModel creates a new token buffer memory
+// keeping the messages within maxTokenLimit tokens, as counted with
+// llms.CountTokens for the model.
+func NewConversationTokenBufferForModel(
+	model string,
+	maxTokenLimit int,
+	options ...ConversationBufferOption,
+) *ConversationTokenBuffer {
+	return &ConversationTokenBuffer{
+		Model:              model,
+		MaxTokenLimit:      maxTokenLimit,
+		ConversationBuffer: *applyBufferOptions(options...),
+	}
+}
+
 // MemoryVariables uses ConversationBuffer method for memory variables.
 func (tb *ConversationTokenBuffer) MemoryVariables() []string {
 	return tb.ConversationBuffer.MemoryVariables()
@@ -99,5 +117,8 @@ func (tb *ConversationTokenBuffer) getNumTokensFromMessages() (int, error) {
 		return 0, err
 	}
 
+	if tb.Model != "" {
+		return llms.CountTokens(tb.Model, bufferString), nil
+	}
 	return tb.LLM.GetNumTokens(bufferString), nil
 }
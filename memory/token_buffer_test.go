@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tmc/langchaingo/schema"
 )
@@ -93,3 +94,27 @@ func TestTokenBufferMemoryWithPreLoadedHistory(t *testing.T) {
 	expected := map[string]any{"history": "Human: bar\nAI: foo"}
 	assert.Equal(t, expected, result)
 }
+
+func TestTokenBufferMemoryForModel(t *testing.T) {
+	t.Parallel()
+
+	m := NewConversationTokenBufferForModel("gpt-3.5-turbo", 20)
+	limit := func() {
+		messages, err := m.ChatHistory.Messages()
+		require.NoError(t, err)
+		bufferString, err := schema.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, llms.CountTokens(m.Model, bufferString), m.MaxTokenLimit)
+	}
+
+	for _, turn := range []string{"one", "two", "three", "four", "five"} {
+		err := m.SaveContext(map[string]any{"input": "tell me " + turn}, map[string]any{"output": "here is " + turn})
+		require.NoError(t, err)
+		limit()
+	}
+
+	result, err := m.LoadMemoryVariables(map[string]any{})
+	require.NoError(t, err)
+	assert.Contains(t, result["history"], "AI: here is five")
+	assert.NotContains(t, result["history"], "one")
+}
@@ -8,6 +8,19 @@ The outputparser package includes the following parsers:
   - Simple: a basic parser that returns the raw text as-is without any processing.
   - Structured: a parser that expects a JSON-formatted response and returns it as
     a map[string]string while validating against a provided schema.
+  - JSON: a parser that extracts the first JSON object or array of a response,
+    repairing common mistakes and optionally asking an LLM to fix it, and
+    returns it as a map[string]any or a []any.
+  - StreamingJSON: a JSON parser which also implements Streaming, parsing the
+    partial output of a streamed response with ParseChunk, so that its fields
+    can be shown as they arrive.
+  - Struct: a parser generated from a Go struct with NewStructFromType, which
+    describes the struct, its nested types and the constraints of its validate
+    tags in the format instructions, and parses a response into the struct,
+    reporting every invalid field.
+  - Retry: a parser wrapping another one, which asks an LLM to correct the
+    responses the wrapped parser fails to parse, giving it the prompt, the
+    response and the parse error.
   - Combining: a parser that combines the output of multiple parsers into a single parser.
   - CommaSeparatedList: a parser that takes a string with comma-separated values
     and returns them as a string slice.
@@ -0,0 +1,201 @@
+package outputparser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _jsonFixPromptTemplate is the prompt asking the fix llm of the JSON parser to
+// fix the output of an llm. The first verb is the output, the second verb is
+// the error parsing it.
+const _jsonFixPromptTemplate = `The following text should contain a single valid JSON object or array, but it could not be parsed:
+
+%s
+
+Error: %s
+
+Rewrite it as valid JSON, keeping its content. Respond with the JSON only.` //nolint:lll
+
+// JSON is an output parser that parses the output of an llm as JSON. Unlike
+// Structured, it tolerates markdown code fences and text around the JSON: the
+// first JSON object or array of the output is parsed, after repairing single
+// quoted strings, trailing commas and the Python constants True, False and
+// None. If FixLLM is set, output that still can not be parsed is given once to
+// FixLLM to fix before failing.
+type JSON struct {
+	// FixLLM is the llm asked to fix the JSON that can not be repaired, if set.
+	FixLLM llms.LLM
+}
+
+// NewJSON returns a new JSON output parser, which can be given an llm fixing
+// the JSON it can not repair by setting its FixLLM.
+func NewJSON() JSON {
+	return JSON{}
+}
+
+// Statically assert that JSON implements the OutputParser interface.
+var _ schema.OutputParser[any] = JSON{}
+
+// GetFormatInstructions returns instructions on the expected output format.
+func (p JSON) GetFormatInstructions() string {
+	return "Your output should be valid JSON, such as a JSON object in a markdown code snippet: \n```json\n{\"key\": \"value\"}\n```" //nolint:lll
+}
+
+// Parse parses the first JSON value of the output of an llm into a
+// map[string]any or a []any.
+func (p JSON) Parse(text string) (any, error) {
+	return p.ParseContext(context.Background(), text)
+}
+
+// ParseWithPrompt does the same as Parse.
+func (p JSON) ParseWithPrompt(text string, _ schema.PromptValue) (any, error) {
+	return p.Parse(text)
+}
+
+// ParseContext does the same as Parse, calling the fix llm with the context.
+func (p JSON) ParseContext(ctx context.Context, text string) (any, error) {
+	parsed, err := parseJSON(text)
+	if err == nil || p.FixLLM == nil {
+		return parsed, err
+	}
+
+	fixed, fixErr := p.FixLLM.Call(ctx, fmt.Sprintf(_jsonFixPromptTemplate, text, err))
+	if fixErr != nil {
+		return nil, fmt.Errorf("fix json: %w", fixErr)
+	}
+	return parseJSON(fixed)
+}
+
+// Type returns the type of the parser.
+func (p JSON) Type() string {
+	return "json_parser"
+}
+
+// parseJSON parses the first JSON object or array of the text, repairing it if
+// it is not valid JSON.
+func parseJSON(text string) (any, error) {
+	extracted, ok := extractJSON(text)
+	if !ok {
+		return nil, ParseError{Text: text, Reason: "no JSON object or array in output"}
+	}
+
+	var parsed any
+	err := json.Unmarshal([]byte(extracted), &parsed)
+	if err == nil {
+		return parsed, nil
+	}
+	if json.Unmarshal([]byte(repairJSON(extracted)), &parsed) == nil {
+		return parsed, nil
+	}
+	return nil, ParseError{Text: text, Reason: fmt.Sprintf("invalid JSON: %s", err)}
+}
+
+// extractJSON returns the first JSON object or array of the text, up to its
+// closing bracket, or to the end of the text if it is not closed.
+func extractJSON(text string) (string, bool) {
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return "", false
+	}
+
+	var (
+		depth int
+		quote byte
+	)
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return text[start : i+1], true
+			}
+		}
+	}
+	return text[start:], true
+}
+
+// repairJSON fixes common mistakes of llms writing JSON: strings in single
+// quotes, trailing commas and the Python constants True, False and None.
+func repairJSON(text string) string {
+	var sb strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"' || c == '\'':
+			i = repairJSONString(&sb, text, i)
+		case c == ',':
+			// Drop the comma if the next character is a closing bracket.
+			j := i + 1
+			for j < len(text) && strings.IndexByte(" \t\r\n", text[j]) >= 0 {
+				j++
+			}
+			if j < len(text) && (text[j] == '}' || text[j] == ']') {
+				continue
+			}
+			sb.WriteByte(c)
+		case isIdentByte(c) && (i == 0 || !isIdentByte(text[i-1])):
+			j := i
+			for j < len(text) && isIdentByte(text[j]) {
+				j++
+			}
+			word := text[i:j]
+			if replacement, ok := map[string]string{"True": "true", "False": "false", "None": "null"}[word]; ok {
+				word = replacement
+			}
+			sb.WriteString(word)
+			i = j - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// repairJSONString writes the string starting at the quote at index start as
+// a double quoted JSON string, and returns the index of its closing quote.
+func repairJSONString(sb *strings.Builder, text string, start int) int {
+	quote := text[start]
+	sb.WriteByte('"')
+	i := start + 1
+	for ; i < len(text) && text[i] != quote; i++ {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text):
+			i++
+			if text[i] == '\'' {
+				sb.WriteByte('\'')
+			} else {
+				sb.WriteByte('\\')
+				sb.WriteByte(text[i])
+			}
+		case c == '"':
+			sb.WriteString(`\"`)
+		case c == '\n':
+			sb.WriteString(`\n`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return i
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
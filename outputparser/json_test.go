@@ -0,0 +1,100 @@
+package outputparser_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+)
+
+type fixLLM struct {
+	response string
+	prompts  []string
+}
+
+func (l *fixLLM) Call(_ context.Context, prompt string, _ ...llms.CallOption) (string, error) {
+	l.prompts = append(l.prompts, prompt)
+	return l.response, nil
+}
+
+func (l *fixLLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	text, err := l.Call(ctx, prompts[0], options...)
+	return []*llms.Generation{{Text: text}}, err
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{
+			name:     "plain",
+			input:    `{"answer": "yes", "score": 2}`,
+			expected: map[string]any{"answer": "yes", "score": 2.0},
+		},
+		{
+			name:     "fenced with prose",
+			input:    "Sure! Here it is:\n```json\n{\"answer\": \"a } in text\"}\n```\nLet me know if you need more.",
+			expected: map[string]any{"answer": "a } in text"},
+		},
+		{
+			name:     "array",
+			input:    `The list is [1, 2, 3].`,
+			expected: []any{1.0, 2.0, 3.0},
+		},
+		{
+			name:     "single quotes and trailing commas",
+			input:    `{'answer': 'it\'s "fine"', 'tags': ['a', 'b',],}`,
+			expected: map[string]any{"answer": `it's "fine"`, "tags": []any{"a", "b"}},
+		},
+		{
+			name:     "python constants",
+			input:    `{"ok": True, "failed": False, "error": None, "text": "True"}`,
+			expected: map[string]any{"ok": true, "failed": false, "error": nil, "text": "True"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := outputparser.NewJSON().Parse(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	_, err := outputparser.NewJSON().Parse("no json here")
+	require.ErrorAs(t, err, &outputparser.ParseError{})
+	_, err = outputparser.NewJSON().Parse(`{"answer": yes}`)
+	require.ErrorAs(t, err, &outputparser.ParseError{})
+}
+
+func TestJSONFix(t *testing.T) {
+	t.Parallel()
+
+	llm := &fixLLM{response: "```json\n{\"answer\": \"yes\"}\n```"}
+	parser := outputparser.JSON{FixLLM: llm}
+
+	actual, err := parser.Parse(`{"answer": "yes"`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"answer": "yes"}, actual)
+	require.Len(t, llm.prompts, 1)
+	require.Contains(t, llm.prompts[0], `{"answer": "yes"`)
+
+	actual, err = parser.Parse(`{"answer": "no"}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"answer": "no"}, actual)
+	require.Len(t, llm.prompts, 1)
+
+	llm.response = "still not json"
+	_, err = parser.Parse(`{"answer": yes}`)
+	require.ErrorAs(t, err, &outputparser.ParseError{})
+	require.Len(t, llm.prompts, 2)
+}
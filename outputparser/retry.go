@@ -0,0 +1,105 @@
+package outputparser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _retryPromptTemplate is the prompt asking the llm of the retry parser to
+// correct a response. The verbs are the prompt, the response, the error
+// parsing it and the format instructions.
+const _retryPromptTemplate = `Prompt:
+%s
+
+Response:
+%s
+
+The response above does not satisfy the constraints of the prompt and could not be parsed: %s
+%s
+Please try again. Respond with the corrected response only.`
+
+// _retryPromptTemplateWithoutPrompt is the prompt of the retry parser when the
+// prompt of the response is unknown.
+const _retryPromptTemplateWithoutPrompt = `Response:
+%s
+
+The response above could not be parsed: %s
+%s
+Please try again. Respond with the corrected response only.`
+
+// Retry is an output parser that parses the output of an llm with another
+// parser and, when the output can not be parsed, gives the prompt, the output
+// and the parse error back to an llm, asking it for a corrected response. It
+// does so up to MaxAttempts times before failing.
+type Retry struct {
+	Parser      schema.OutputParser[any]
+	LLM         llms.LLM
+	MaxAttempts int
+}
+
+// NewRetryParser returns a new Retry parser asking the llm for at most
+// maxAttempts corrected responses when the inner parser fails.
+func NewRetryParser(inner schema.OutputParser[any], llm llms.LLM, maxAttempts int) Retry {
+	return Retry{Parser: inner, LLM: llm, MaxAttempts: maxAttempts}
+}
+
+// Statically assert that Retry implements the OutputParser interface.
+var _ schema.OutputParser[any] = Retry{}
+
+// GetFormatInstructions returns the format instructions of the inner parser.
+func (p Retry) GetFormatInstructions() string {
+	return p.Parser.GetFormatInstructions()
+}
+
+// Parse parses the output of an llm with the inner parser, asking the llm for
+// corrected responses without the prompt if it fails.
+func (p Retry) Parse(text string) (any, error) {
+	return p.ParseWithPromptContext(context.Background(), text, nil)
+}
+
+// ParseWithPrompt parses the output of an llm for the prompt with the inner
+// parser, asking the llm for corrected responses if it fails.
+func (p Retry) ParseWithPrompt(text string, prompt schema.PromptValue) (any, error) {
+	return p.ParseWithPromptContext(context.Background(), text, prompt)
+}
+
+// ParseWithPromptContext does the same as ParseWithPrompt, calling the llm
+// with the context. The prompt can be nil.
+func (p Retry) ParseWithPromptContext(ctx context.Context, text string, prompt schema.PromptValue) (any, error) {
+	parsed, err := p.parse(text, prompt)
+	for attempt := 0; err != nil && attempt < p.MaxAttempts; attempt++ {
+		instructions := p.Parser.GetFormatInstructions()
+		if instructions != "" {
+			instructions = "\n" + instructions + "\n"
+		}
+		var retryPrompt string
+		if prompt != nil {
+			retryPrompt = fmt.Sprintf(_retryPromptTemplate, prompt.String(), text, err, instructions)
+		} else {
+			retryPrompt = fmt.Sprintf(_retryPromptTemplateWithoutPrompt, text, err, instructions)
+		}
+
+		var callErr error
+		text, callErr = p.LLM.Call(ctx, retryPrompt)
+		if callErr != nil {
+			return nil, fmt.Errorf("retry parse: %w", callErr)
+		}
+		parsed, err = p.parse(text, prompt)
+	}
+	return parsed, err
+}
+
+func (p Retry) parse(text string, prompt schema.PromptValue) (any, error) {
+	if prompt != nil {
+		return p.Parser.ParseWithPrompt(text, prompt)
+	}
+	return p.Parser.Parse(text)
+}
+
+// Type returns the type of the parser.
+func (p Retry) Type() string {
+	return "retry_parser"
+}
@@ -0,0 +1,65 @@
+package outputparser_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+type sequenceLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (l *sequenceLLM) Call(_ context.Context, prompt string, _ ...llms.CallOption) (string, error) {
+	l.prompts = append(l.prompts, prompt)
+	if len(l.responses) == 0 {
+		return "", errors.New("no more responses")
+	}
+	response := l.responses[0]
+	l.responses = l.responses[1:]
+	return response, nil
+}
+
+func (l *sequenceLLM) Generate(ctx context.Context, prompts []string, options ...llms.CallOption) ([]*llms.Generation, error) { //nolint:lll
+	text, err := l.Call(ctx, prompts[0], options...)
+	return []*llms.Generation{{Text: text}}, err
+}
+
+func TestRetryParser(t *testing.T) {
+	t.Parallel()
+
+	prompt := prompts.StringPromptValue("Is the sky blue? Answer YES or NO.")
+
+	llm := &sequenceLLM{responses: []string{"maybe", "YES"}}
+	parser := outputparser.NewRetryParser(outputparser.NewBooleanParser(), llm, 2)
+	parsed, err := parser.ParseWithPrompt("Sure, it is blue!", prompt)
+	require.NoError(t, err)
+	require.Equal(t, true, parsed)
+	require.Len(t, llm.prompts, 2)
+	require.Contains(t, llm.prompts[0], "Is the sky blue? Answer YES or NO.")
+	require.Contains(t, llm.prompts[0], "Sure, it is blue!")
+	require.Contains(t, llm.prompts[0], "Expected output to be either 'YES' or 'NO'")
+	require.Contains(t, llm.prompts[1], "maybe")
+
+	llm = &sequenceLLM{responses: []string{"maybe"}}
+	parser = outputparser.NewRetryParser(outputparser.NewBooleanParser(), llm, 1)
+	_, err = parser.Parse("perhaps")
+	require.ErrorAs(t, err, &outputparser.ParseError{})
+	require.Len(t, llm.prompts, 1)
+	require.NotContains(t, llm.prompts[0], "Prompt:")
+
+	llm = &sequenceLLM{}
+	parsed, err = outputparser.NewRetryParser(outputparser.NewBooleanParser(), llm, 3).Parse("NO")
+	require.NoError(t, err)
+	require.Equal(t, false, parsed)
+	require.Empty(t, llm.prompts)
+
+	_, err = outputparser.NewRetryParser(outputparser.NewBooleanParser(), llm, 3).Parse("perhaps")
+	require.ErrorContains(t, err, "no more responses")
+}
@@ -0,0 +1,144 @@
+package outputparser
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Streaming is implemented by output parsers parsing the output of an llm as
+// it is streamed, so that the fields of a structured output can be shown as
+// they arrive instead of once the completion is done.
+type Streaming interface {
+	// ParseChunk adds the chunk to the output streamed so far, and returns the
+	// value parsed from the partial output, nil if there is none yet.
+	ParseChunk(delta []byte) (any, error)
+}
+
+// StreamingJSON is a JSON output parser which also parses partial outputs.
+// The partial JSON is completed as parsed: open strings, such as the value of
+// a field being written, are closed with the text streamed so far, and fields
+// and items not written far enough to be parsed, such as a key without its
+// value, are left out. Once the output is done, Parse parses it like JSON.
+type StreamingJSON struct {
+	JSON
+	buf []byte
+}
+
+// NewStreamingJSON returns a new StreamingJSON output parser.
+func NewStreamingJSON() *StreamingJSON {
+	return &StreamingJSON{}
+}
+
+// Statically assert that StreamingJSON implements the Streaming and
+// OutputParser interfaces.
+var (
+	_ Streaming                = (*StreamingJSON)(nil)
+	_ schema.OutputParser[any] = (*StreamingJSON)(nil)
+)
+
+// ParseChunk adds the chunk to the output streamed so far, and returns the
+// map[string]any or []any parsed from the partial output.
+func (p *StreamingJSON) ParseChunk(delta []byte) (any, error) {
+	p.buf = append(p.buf, delta...)
+	return parsePartialJSON(string(p.buf))
+}
+
+// Text returns the output streamed so far.
+func (p *StreamingJSON) Text() string {
+	return string(p.buf)
+}
+
+// Reset forgets the output streamed so far, to parse another one.
+func (p *StreamingJSON) Reset() {
+	p.buf = p.buf[:0]
+}
+
+// Type returns the type of the parser.
+func (p *StreamingJSON) Type() string {
+	return "streaming_json_parser"
+}
+
+// partialJSONCut is a place where a partial JSON text can be cut and completed
+// by closing the brackets still open.
+type partialJSONCut struct {
+	end     int
+	closers string
+}
+
+// parsePartialJSON parses the first JSON object or array of the text, which
+// may not be complete yet. It returns nil if no object or array started.
+func parsePartialJSON(text string) (any, error) {
+	extracted, ok := extractJSON(text)
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	var (
+		stack   []byte
+		cuts    []partialJSONCut
+		inStr   bool
+		escaped bool
+	)
+	closers := func() string {
+		s := make([]byte, len(stack))
+		for i, open := range stack {
+			s[len(stack)-1-i] = map[byte]byte{'{': '}', '[': ']'}[open]
+		}
+		return string(s)
+	}
+	for i := 0; i < len(extracted); i++ {
+		c := extracted[i]
+		switch {
+		case inStr:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inStr = false
+			}
+		case c == '"':
+			inStr = true
+		case c == '{' || c == '[':
+			stack = append(stack, c)
+			cuts = append(cuts, partialJSONCut{end: i + 1, closers: closers()})
+		case c == '}' || c == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case c == ',':
+			cuts = append(cuts, partialJSONCut{end: i, closers: closers()})
+		}
+	}
+
+	// The whole text is tried first, closing the string being written.
+	whole := strings.TrimRight(extracted, " \t\r\n")
+	if inStr {
+		if escaped {
+			whole = whole[:len(whole)-1]
+		}
+		if j := strings.LastIndex(whole, `\u`); j >= 0 && j > len(whole)-6 {
+			whole = whole[:j]
+		}
+		whole += `"`
+	}
+	candidates := make([]string, 0, len(cuts)+1)
+	candidates = append(candidates, whole+closers())
+	for i := len(cuts) - 1; i >= 0; i-- {
+		candidates = append(candidates, extracted[:cuts[i].end]+cuts[i].closers)
+	}
+
+	for _, candidate := range candidates {
+		var parsed any
+		if json.Unmarshal([]byte(candidate), &parsed) == nil {
+			return parsed, nil
+		}
+		if json.Unmarshal([]byte(repairJSON(candidate)), &parsed) == nil {
+			return parsed, nil
+		}
+	}
+	return nil, ParseError{Text: text, Reason: "invalid partial JSON"}
+}
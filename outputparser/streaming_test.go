@@ -0,0 +1,41 @@
+package outputparser_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/outputparser"
+)
+
+func TestStreamingJSON(t *testing.T) {
+	t.Parallel()
+
+	parser := outputparser.NewStreamingJSON()
+	steps := []struct {
+		chunk    string
+		expected any
+	}{
+		{"Sure:\n```json\n", nil},
+		{`{"prompt": "a cat`, map[string]any{"prompt": "a cat"}},
+		{` on a mat\u00`, map[string]any{"prompt": "a cat on a mat"}},
+		{`e9", "neg`, map[string]any{"prompt": "a cat on a maté"}},
+		{`ative": `, map[string]any{"prompt": "a cat on a maté"}},
+		{`"dogs\`, map[string]any{"prompt": "a cat on a maté", "negative": "dogs"}},
+		{`n", "tags": ["a", tr`, map[string]any{"prompt": "a cat on a maté", "negative": "dogs\n", "tags": []any{"a"}}},
+		{"ue]}\n```", map[string]any{"prompt": "a cat on a maté", "negative": "dogs\n", "tags": []any{"a", true}}},
+	}
+	for _, step := range steps {
+		parsed, err := parser.ParseChunk([]byte(step.chunk))
+		require.NoError(t, err)
+		require.Equal(t, step.expected, parsed, "after chunk %q", step.chunk)
+	}
+
+	parsed, err := parser.Parse(parser.Text())
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"prompt": "a cat on a maté", "negative": "dogs\n", "tags": []any{"a", true}}, parsed)
+
+	parser.Reset()
+	parsed, err = parser.ParseChunk([]byte(`[{"a": 1}, {"b"`))
+	require.NoError(t, err)
+	require.Equal(t, []any{map[string]any{"a": 1.0}, map[string]any{}}, parsed)
+}
@@ -0,0 +1,399 @@
+package outputparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _structFormatInstructionTemplate is a template for the format instructions
+// of the struct output parser. The verb is the JSON schema of the struct.
+const _structFormatInstructionTemplate = "The output should be a markdown code snippet with a JSON object matching the following JSON schema:\n```json\n%s\n```" //nolint:lll
+
+var _timeType = reflect.TypeOf(time.Time{}) //nolint:gochecknoglobals
+
+// FieldError is a field of the output of an llm which does not match the
+// struct the output is parsed into.
+type FieldError struct {
+	// Path is the path of the field in the output, such as "items[0].name".
+	Path   string
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	return e.Path + " " + e.Reason
+}
+
+// ValidationError is returned by Struct parsers when fields of the output do
+// not match the struct, with an error for each of them.
+type ValidationError struct {
+	Text   string
+	Fields []FieldError
+}
+
+func (e ValidationError) Error() string {
+	reasons := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		reasons[i] = f.Error()
+	}
+	return fmt.Sprintf("parse text %s. invalid fields: %s", e.Text, strings.Join(reasons, "; "))
+}
+
+// Struct is an output parser that parses the output of an llm into a value of
+// type T, a struct. The format instructions give the JSON schema of T, derived
+// from its fields: their json tags give their names, fields tagged omitempty
+// or of pointer type are optional, and a description tag documents a field.
+// Nested structs, slices and maps are described too. The validate tag of the
+// fields adds constraints, separated by commas:
+//
+//	required        the field must be set, even if optional.
+//	oneof=a b c     the field must be one of the values, an enum.
+//	min=n, max=n    bounds of numbers, or of the length of strings and slices.
+//
+// The output is parsed like the JSON parser does, and every field not
+// matching T is reported in a ValidationError.
+type Struct[T any] struct {
+	// Schema is the JSON schema of T.
+	Schema map[string]any
+}
+
+// NewStructFromType returns a new output parser of values of type T, which
+// must be a struct.
+func NewStructFromType[T any]() Struct[T] {
+	return Struct[T]{Schema: typeJSONSchema(reflect.TypeOf((*T)(nil)).Elem(), nil, map[reflect.Type]bool{})}
+}
+
+// Statically assert that Struct implements the OutputParser interface.
+var _ schema.OutputParser[any] = Struct[struct{}]{}
+
+// GetFormatInstructions returns the JSON schema the output should match.
+func (p Struct[T]) GetFormatInstructions() string {
+	schemaJSON, _ := json.MarshalIndent(p.Schema, "", "  ")
+	return fmt.Sprintf(_structFormatInstructionTemplate, schemaJSON)
+}
+
+// ParseValue parses the output of an llm into a T.
+func (p Struct[T]) ParseValue(text string) (T, error) {
+	var value T
+	raw, err := parseJSON(text)
+	if err != nil {
+		return value, err
+	}
+
+	var fields []FieldError
+	validateValue(reflect.TypeOf(value), raw, "", &fields)
+	if len(fields) > 0 {
+		return value, ValidationError{Text: text, Fields: fields}
+	}
+
+	// The parsed value is encoded again, as the text may have been repaired.
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, ParseError{Text: text, Reason: err.Error()}
+	}
+	return value, nil
+}
+
+// Parse parses the output of an llm into a T.
+func (p Struct[T]) Parse(text string) (any, error) {
+	return p.ParseValue(text)
+}
+
+// ParseWithPrompt does the same as Parse.
+func (p Struct[T]) ParseWithPrompt(text string, _ schema.PromptValue) (any, error) {
+	return p.ParseValue(text)
+}
+
+// Type returns the type of the parser.
+func (p Struct[T]) Type() string {
+	return "struct_parser"
+}
+
+// structField is an exported field of a struct as encoded in JSON.
+type structField struct {
+	reflect.StructField
+	name     string
+	optional bool
+	rules    fieldRules
+}
+
+// fieldRules are the constraints of the validate tag of a field.
+type fieldRules struct {
+	required bool
+	oneOf    []string
+	min, max *float64
+}
+
+func structFields(t reflect.Type) []structField {
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		f := structField{StructField: field, name: name, rules: parseRules(field.Tag.Get("validate"))}
+		f.optional = !f.rules.required && (strings.Contains(opts, "omitempty") || field.Type.Kind() == reflect.Pointer)
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func parseRules(tag string) fieldRules {
+	var rules fieldRules
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			rules.required = true
+		case "oneof":
+			rules.oneOf = strings.Fields(arg)
+		case "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+			if name == "min" {
+				rules.min = &n
+			} else {
+				rules.max = &n
+			}
+		}
+	}
+	return rules
+}
+
+// typeJSONSchema returns the JSON schema of the values of the type, with the
+// constraints of the rules if any. Structs being described are seen, and are
+// described as plain objects when they nest themselves.
+func typeJSONSchema(t reflect.Type, rules *fieldRules, seen map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var s map[string]any
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		s = map[string]any{"type": "string"}
+	case reflect.Bool:
+		s = map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		s = map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		s = map[string]any{"type": "array", "items": typeJSONSchema(t.Elem(), nil, seen)}
+	case reflect.Map:
+		s = map[string]any{"type": "object", "additionalProperties": typeJSONSchema(t.Elem(), nil, seen)}
+	case reflect.Struct:
+		switch {
+		case t == _timeType:
+			s = map[string]any{"type": "string", "format": "date-time"}
+		case seen[t]:
+			s = map[string]any{"type": "object"}
+		default:
+			seen[t] = true
+			s = structJSONSchema(t, seen)
+			delete(seen, t)
+		}
+	default:
+		s = map[string]any{}
+	}
+	if rules != nil {
+		addRulesJSONSchema(s, *rules)
+	}
+	return s
+}
+
+func structJSONSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	properties := make(map[string]any)
+	required := make([]string, 0)
+	for _, field := range structFields(t) {
+		property := typeJSONSchema(field.Type, &field.rules, seen)
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+		properties[field.name] = property
+		if !field.optional {
+			required = append(required, field.name)
+		}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+func addRulesJSONSchema(s map[string]any, rules fieldRules) {
+	if len(rules.oneOf) > 0 {
+		enum := make([]any, len(rules.oneOf))
+		for i, v := range rules.oneOf {
+			enum[i] = v
+			if s["type"] != "string" {
+				if n, err := strconv.ParseFloat(v, 64); err == nil {
+					enum[i] = n
+				}
+			}
+		}
+		s["enum"] = enum
+	}
+	minKey, maxKey := "minimum", "maximum"
+	switch s["type"] {
+	case "string":
+		minKey, maxKey = "minLength", "maxLength"
+	case "array":
+		minKey, maxKey = "minItems", "maxItems"
+	}
+	if rules.min != nil {
+		s[minKey] = *rules.min
+	}
+	if rules.max != nil {
+		s[maxKey] = *rules.max
+	}
+}
+
+// validateValue checks that the value decoded from JSON matches the type,
+// adding an error for each field which does not.
+func validateValue(t reflect.Type, v any, path string, errs *[]FieldError) {
+	for t.Kind() == reflect.Pointer {
+		if v == nil {
+			return
+		}
+		t = t.Elem()
+	}
+	fail := func(reason string, args ...any) {
+		p := path
+		if p == "" {
+			p = "output"
+		}
+		*errs = append(*errs, FieldError{Path: p, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			fail("must be a string")
+		}
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			fail("must be a boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := v.(float64); !ok || n != math.Trunc(n) {
+			fail("must be an integer")
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := v.(float64); !ok {
+			fail("must be a number")
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := v.([]any)
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		for i, item := range items {
+			validateValue(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		for k, item := range m {
+			validateValue(t.Elem(), item, joinPath(path, k), errs)
+		}
+	case reflect.Struct:
+		if t == _timeType {
+			s, ok := v.(string)
+			if _, err := time.Parse(time.RFC3339, s); !ok || err != nil {
+				fail("must be a RFC 3339 date-time")
+			}
+			return
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		validateStruct(t, m, path, errs)
+	}
+}
+
+func validateStruct(t reflect.Type, m map[string]any, path string, errs *[]FieldError) {
+	for _, field := range structFields(t) {
+		fieldPath := joinPath(path, field.name)
+		v, ok := m[field.name]
+		if !ok || v == nil {
+			if !field.optional {
+				*errs = append(*errs, FieldError{Path: fieldPath, Reason: "is required"})
+			}
+			continue
+		}
+		n := len(*errs)
+		validateValue(field.Type, v, fieldPath, errs)
+		if len(*errs) == n {
+			if reason := checkRules(field.rules, v); reason != "" {
+				*errs = append(*errs, FieldError{Path: fieldPath, Reason: reason})
+			}
+		}
+	}
+}
+
+// checkRules returns why the value, of the type of its field, breaks the
+// rules, or an empty string.
+func checkRules(rules fieldRules, v any) string {
+	if len(rules.oneOf) > 0 {
+		s := fmt.Sprint(v)
+		if n, ok := v.(float64); ok {
+			s = strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		found := false
+		for _, allowed := range rules.oneOf {
+			found = found || s == allowed
+		}
+		if !found {
+			return fmt.Sprintf("must be one of %s", strings.Join(rules.oneOf, ", "))
+		}
+	}
+
+	size, verb, unit := 0.0, "be", ""
+	switch v := v.(type) {
+	case float64:
+		size = v
+	case string:
+		size, verb, unit = float64(len([]rune(v))), "have", " characters"
+	case []any:
+		size, verb, unit = float64(len(v)), "have", " items"
+	default:
+		return ""
+	}
+	if rules.min != nil && size < *rules.min {
+		return fmt.Sprintf("must %s at least %v%s", verb, *rules.min, unit)
+	}
+	if rules.max != nil && size > *rules.max {
+		return fmt.Sprintf("must %s at most %v%s", verb, *rules.max, unit)
+	}
+	return ""
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
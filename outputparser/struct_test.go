@@ -0,0 +1,91 @@
+package outputparser_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/outputparser"
+)
+
+type address struct {
+	City    string `json:"city"`
+	Country string `json:"country" validate:"min=2,max=2" description:"ISO 3166 country code"`
+}
+
+type person struct {
+	Name     string   `json:"name" description:"The full name"`
+	Age      int      `json:"age" validate:"min=0,max=150"`
+	Role     string   `json:"role" validate:"oneof=admin user"`
+	Address  address  `json:"address"`
+	Previous []string `json:"previous,omitempty" validate:"max=2"`
+	Nickname *string  `json:"nickname"`
+	Children []person `json:"children,omitempty"`
+}
+
+func TestStructFromType(t *testing.T) {
+	t.Parallel()
+
+	parser := outputparser.NewStructFromType[person]()
+
+	properties, ok := parser.Schema["properties"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, []string{"name", "age", "role", "address"}, parser.Schema["required"])
+	require.Equal(t, map[string]any{"type": "string", "enum": []any{"admin", "user"}}, properties["role"])
+	require.Equal(t, map[string]any{"type": "integer", "minimum": 0.0, "maximum": 150.0}, properties["age"])
+	require.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"city": map[string]any{"type": "string"},
+			"country": map[string]any{
+				"type": "string", "minLength": 2.0, "maxLength": 2.0, "description": "ISO 3166 country code",
+			},
+		},
+		"required": []string{"city", "country"},
+	}, properties["address"])
+	require.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "object"}}, properties["children"])
+	require.Contains(t, parser.GetFormatInstructions(), `"description": "The full name"`)
+
+	value, err := parser.ParseValue("Here you go:\n```json\n" + `{
+		"name": "Ada", "age": 36, "role": "admin",
+		"address": {"city": "London", "country": "GB"},
+		"children": [{"name": "Byron", "age": 1, "role": "user", "address": {"city": "London", "country": "GB"}},],
+	}` + "\n```")
+	require.NoError(t, err)
+	require.Equal(t, "Ada", value.Name)
+	require.Equal(t, "GB", value.Address.Country)
+	require.Nil(t, value.Nickname)
+	require.Len(t, value.Children, 1)
+	require.Equal(t, "Byron", value.Children[0].Name)
+
+	parsed, err := parser.Parse(`{"name": "Ada", "age": 36, "role": "admin", "address": {"city": "London", "country": "GB"}}`)
+	require.NoError(t, err)
+	require.IsType(t, person{}, parsed)
+}
+
+func TestStructFromTypeValidation(t *testing.T) {
+	t.Parallel()
+
+	parser := outputparser.NewStructFromType[person]()
+
+	_, err := parser.ParseValue(`{
+		"name": 3, "age": 1.5, "role": "root",
+		"address": {"country": "GBR"},
+		"previous": ["a", "b", "c"],
+		"children": [{"name": "Byron", "age": -1, "role": "user", "address": "London"}]
+	}`)
+	var validationErr outputparser.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, []outputparser.FieldError{
+		{Path: "name", Reason: "must be a string"},
+		{Path: "age", Reason: "must be an integer"},
+		{Path: "role", Reason: "must be one of admin, user"},
+		{Path: "address.city", Reason: "is required"},
+		{Path: "address.country", Reason: "must have at most 2 characters"},
+		{Path: "previous", Reason: "must have at most 2 items"},
+		{Path: "children[0].age", Reason: "must be at least 0"},
+		{Path: "children[0].address", Reason: "must be an object"},
+	}, validationErr.Fields)
+
+	_, err = parser.ParseValue("no json")
+	require.ErrorAs(t, err, &outputparser.ParseError{})
+}
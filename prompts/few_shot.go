@@ -131,7 +131,7 @@ func (p *FewShotPrompt) Format(values map[string]interface{}) (string, error) {
 	}
 
 	template := assemblePieces(p.Prefix, p.Suffix, exampleStrings, p.ExampleSeparator)
-	return defaultformatterMapping[p.TemplateFormat](template, resolvedValues)
+	return defaultformatterMapping[p.TemplateFormat](template, resolvedValues, nil)
 }
 
 // assemblePieces assembles the pieces of the few-shot prompt.
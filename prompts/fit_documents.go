@@ -0,0 +1,154 @@
+package prompts
+
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultFitModel = "gpt-3.5-turbo"
+
+// FitStrategy is how FitDocuments makes documents fit in a token budget.
+type FitStrategy int
+
+const (
+	// FitHead keeps the first documents, cutting the end of the last one kept.
+	FitHead FitStrategy = iota
+	// FitTail keeps the last documents, cutting the start of the first one
+	// kept.
+	FitTail
+	// FitProportional keeps all the documents, cutting the end of each of them
+	// to a share of the budget proportional to its number of tokens.
+	FitProportional
+)
+
+// FitReport describes what FitDocuments cut from the documents.
+type FitReport struct {
+	// InputTokens and OutputTokens are the numbers of tokens of the documents
+	// given and returned.
+	InputTokens  int
+	OutputTokens int
+	// Truncated are the indexes of the documents returned cut.
+	Truncated []int
+	// Dropped are the indexes of the documents left out.
+	Dropped []int
+}
+
+// FitOption is a function that configures FitDocuments.
+type FitOption func(*fitOptions)
+
+type fitOptions struct {
+	countTokens func(text string) int
+}
+
+// WithTokenCounter sets the function counting the tokens of texts, such as the
+// GetNumTokens method of the language model the documents are given to. The
+// tokens are counted with llms.CountTokens for gpt-3.5-turbo by default.
+func WithTokenCounter(countTokens func(text string) int) FitOption {
+	return func(o *fitOptions) {
+		o.countTokens = countTokens
+	}
+}
+
+// FitDocuments returns the documents cut with the strategy so that their
+// contents hold at most budget tokens altogether, and a report of what was
+// cut. Documents are cut on character boundaries and keep their metadata. The
+// tokens of the text joining the documents in a prompt are not counted, and
+// should be left out of the budget.
+func FitDocuments(docs []schema.Document, budget int, strategy FitStrategy, options ...FitOption) ([]schema.Document, FitReport) { //nolint:lll
+	opts := fitOptions{
+		countTokens: func(text string) int { return llms.CountTokens(_defaultFitModel, text) },
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if budget < 0 {
+		budget = 0
+	}
+
+	tokens := make([]int, len(docs))
+	var report FitReport
+	for i, doc := range docs {
+		tokens[i] = opts.countTokens(doc.PageContent)
+		report.InputTokens += tokens[i]
+	}
+
+	budgets := make([]int, len(docs))
+	switch strategy {
+	case FitHead:
+		remaining := budget
+		for i := range docs {
+			budgets[i] = minInt(tokens[i], remaining)
+			remaining -= budgets[i]
+		}
+	case FitTail:
+		remaining := budget
+		for i := len(docs) - 1; i >= 0; i-- {
+			budgets[i] = minInt(tokens[i], remaining)
+			remaining -= budgets[i]
+		}
+	case FitProportional:
+		for i := range docs {
+			budgets[i] = tokens[i]
+			if report.InputTokens > budget {
+				budgets[i] = tokens[i] * budget / report.InputTokens
+			}
+		}
+	}
+
+	fitted := make([]schema.Document, 0, len(docs))
+	for i, doc := range docs {
+		switch {
+		case budgets[i] >= tokens[i]:
+			report.OutputTokens += tokens[i]
+		case budgets[i] == 0:
+			report.Dropped = append(report.Dropped, i)
+			continue
+		default:
+			var n int
+			doc.PageContent, n = cutText(doc.PageContent, budgets[i], strategy == FitTail, opts.countTokens)
+			if n == 0 {
+				report.Dropped = append(report.Dropped, i)
+				continue
+			}
+			report.Truncated = append(report.Truncated, i)
+			report.OutputTokens += n
+		}
+		fitted = append(fitted, doc)
+	}
+	return fitted, report
+}
+
+// cutText returns the longest start, or end if fromEnd is set, of the text
+// holding at most budget tokens, and its number of tokens.
+func cutText(text string, budget int, fromEnd bool, countTokens func(string) int) (string, int) {
+	runes := []rune(text)
+	part := func(n int) string {
+		if fromEnd {
+			return string(runes[len(runes)-n:])
+		}
+		return string(runes[:n])
+	}
+
+	// Search the largest number of runes whose text fits in the budget.
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if countTokens(part(mid)) <= budget {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	cut := part(lo)
+	if lo == 0 {
+		return cut, 0
+	}
+	return cut, countTokens(cut)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
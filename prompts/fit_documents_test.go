@@ -0,0 +1,89 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+func fitContents(docs []schema.Document) []string {
+	contents := make([]string, len(docs))
+	for i, doc := range docs {
+		contents[i] = strings.TrimSpace(doc.PageContent)
+	}
+	return contents
+}
+
+func TestFitDocuments(t *testing.T) {
+	t.Parallel()
+
+	docs := []schema.Document{
+		{PageContent: "one two three four", Metadata: map[string]any{"source": "a"}},
+		{PageContent: "five six", Metadata: map[string]any{"source": "b"}},
+		{PageContent: "seven eight nine ten eleven twelve", Metadata: map[string]any{"source": "c"}},
+	}
+
+	cases := []struct {
+		name     string
+		budget   int
+		strategy FitStrategy
+		contents []string
+		report   FitReport
+	}{
+		{
+			name:     "fits",
+			budget:   12,
+			strategy: FitHead,
+			contents: []string{"one two three four", "five six", "seven eight nine ten eleven twelve"},
+			report:   FitReport{InputTokens: 12, OutputTokens: 12},
+		},
+		{
+			name:     "head",
+			budget:   7,
+			strategy: FitHead,
+			contents: []string{"one two three four", "five six", "seven"},
+			report:   FitReport{InputTokens: 12, OutputTokens: 7, Truncated: []int{2}},
+		},
+		{
+			name:     "head drops",
+			budget:   3,
+			strategy: FitHead,
+			contents: []string{"one two three"},
+			report:   FitReport{InputTokens: 12, OutputTokens: 3, Truncated: []int{0}, Dropped: []int{1, 2}},
+		},
+		{
+			name:     "tail",
+			budget:   7,
+			strategy: FitTail,
+			contents: []string{"six", "seven eight nine ten eleven twelve"},
+			report:   FitReport{InputTokens: 12, OutputTokens: 7, Truncated: []int{1}, Dropped: []int{0}},
+		},
+		{
+			name:     "proportional",
+			budget:   6,
+			strategy: FitProportional,
+			contents: []string{"one two", "five", "seven eight nine"},
+			report:   FitReport{InputTokens: 12, OutputTokens: 6, Truncated: []int{0, 1, 2}},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			fitted, report := FitDocuments(docs, c.budget, c.strategy, WithTokenCounter(countWords))
+			require.Equal(t, c.contents, fitContents(fitted))
+			require.Equal(t, c.report, report)
+		})
+	}
+
+	fitted, _ := FitDocuments(docs, 7, FitTail, WithTokenCounter(countWords))
+	require.Equal(t, "b", fitted[0].Metadata["source"])
+	require.Equal(t, "five six", docs[1].PageContent)
+}
@@ -0,0 +1,1794 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidJinja2Template is returned when a jinja2 template can not be
+// parsed or rendered.
+var ErrInvalidJinja2Template = errors.New("invalid jinja2 template")
+
+// _maxJinjaRange is the largest number of items of range(), as in the jinja2
+// sandbox.
+const _maxJinjaRange = 100000
+
+// interpolateJinja2 interpolates the template with the values using the subset
+// of jinja2 described in the documentation of TemplateFormatJinja2. The funcs
+// can be called as functions and used as filters.
+func interpolateJinja2(tmpl string, values map[string]any, funcs map[string]any) (string, error) {
+	// As jinja2 does by default, a single trailing newline is not rendered.
+	if strings.HasSuffix(tmpl, "\r\n") {
+		tmpl = tmpl[:len(tmpl)-2]
+	} else {
+		tmpl = strings.TrimSuffix(tmpl, "\n")
+	}
+	tokens, err := lexJinja(tmpl)
+	if err != nil {
+		return "", err
+	}
+	p := &jinjaParser{tokens: tokens}
+	nodes, end, err := p.parseBody()
+	if err != nil {
+		return "", err
+	}
+	if end != "" {
+		return "", jinjaErrorf("unexpected {%% %s %%}", end)
+	}
+
+	sb := new(strings.Builder)
+	s := &jinjaScope{vars: []map[string]any{values, {}}, funcs: funcs}
+	if err := renderJinjaNodes(sb, nodes, s); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func jinjaErrorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidJinja2Template, fmt.Sprintf(format, args...))
+}
+
+// Lexing of the template into text, output, statement tokens.
+
+type jinjaTokenKind int
+
+const (
+	jinjaTextToken jinjaTokenKind = iota
+	jinjaOutputToken
+	jinjaStatementToken
+)
+
+type jinjaToken struct {
+	kind jinjaTokenKind
+	text string
+}
+
+// lexJinja splits the template into text, {{ output }} and {% statement %}
+// tokens, dropping {# comments #} and applying the whitespace control of the
+// tags, a "-" after the opening or before the closing delimiter stripping the
+// whitespace before or after the tag.
+func lexJinja(tmpl string) ([]jinjaToken, error) {
+	var tokens []jinjaToken
+	trimNext := false
+	for len(tmpl) > 0 {
+		start := nextJinjaTag(tmpl)
+		text := tmpl
+		if start >= 0 {
+			text = tmpl[:start]
+		}
+		if trimNext {
+			text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		}
+		if start < 0 {
+			tokens = append(tokens, jinjaToken{kind: jinjaTextToken, text: text})
+			break
+		}
+
+		open := tmpl[start : start+2]
+		closing := map[string]string{"{{": "}}", "{%": "%}", "{#": "#}"}[open]
+		rest := tmpl[start+2:]
+		end := strings.Index(rest, closing)
+		if end < 0 {
+			return nil, jinjaErrorf("unclosed %s", open)
+		}
+		inner := rest[:end]
+		tmpl = rest[end+2:]
+
+		if strings.HasPrefix(inner, "-") {
+			inner = inner[1:]
+			text = strings.TrimRightFunc(text, unicode.IsSpace)
+		}
+		trimNext = strings.HasSuffix(inner, "-")
+		if trimNext {
+			inner = inner[:len(inner)-1]
+		}
+		if text != "" {
+			tokens = append(tokens, jinjaToken{kind: jinjaTextToken, text: text})
+		}
+		switch open {
+		case "{{":
+			tokens = append(tokens, jinjaToken{kind: jinjaOutputToken, text: strings.TrimSpace(inner)})
+		case "{%":
+			tokens = append(tokens, jinjaToken{kind: jinjaStatementToken, text: strings.TrimSpace(inner)})
+		}
+	}
+	return tokens, nil
+}
+
+func nextJinjaTag(s string) int {
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] == '{' && (s[i+1] == '{' || s[i+1] == '%' || s[i+1] == '#') {
+			return i
+		}
+	}
+	return -1
+}
+
+// Parsing of the tokens into a tree of nodes.
+
+type jinjaNode interface {
+	render(sb *strings.Builder, s *jinjaScope) error
+}
+
+type jinjaTextNode string
+
+type jinjaOutputNode struct {
+	expr jinjaExpr
+}
+
+type jinjaIfNode struct {
+	conds    []jinjaExpr
+	bodies   [][]jinjaNode
+	elseBody []jinjaNode
+}
+
+type jinjaForNode struct {
+	vars     []string
+	iter     jinjaExpr
+	body     []jinjaNode
+	elseBody []jinjaNode
+}
+
+type jinjaSetNode struct {
+	name string
+	expr jinjaExpr
+}
+
+type jinjaParser struct {
+	tokens []jinjaToken
+	pos    int
+}
+
+// parseBody parses nodes until the end of the tokens, or until a statement
+// ending a block, such as endif, whose text is returned.
+func (p *jinjaParser) parseBody() ([]jinjaNode, string, error) {
+	var nodes []jinjaNode
+	for p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		p.pos++
+		switch tok.kind {
+		case jinjaTextToken:
+			nodes = append(nodes, jinjaTextNode(tok.text))
+		case jinjaOutputToken:
+			expr, err := parseJinjaExpr(tok.text)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jinjaOutputNode{expr: expr})
+		case jinjaStatementToken:
+			keyword, args, _ := strings.Cut(tok.text, " ")
+			args = strings.TrimSpace(args)
+			var (
+				node jinjaNode
+				err  error
+			)
+			switch keyword {
+			case "if":
+				node, err = p.parseIf(args)
+			case "for":
+				node, err = p.parseFor(args)
+			case "set":
+				node, err = parseJinjaSet(args)
+			case "elif", "else", "endif", "endfor":
+				return nodes, tok.text, nil
+			default:
+				return nil, "", jinjaErrorf("unknown statement %q", tok.text)
+			}
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, "", nil
+}
+
+func (p *jinjaParser) parseIf(cond string) (jinjaNode, error) {
+	node := jinjaIfNode{}
+	for {
+		expr, err := parseJinjaExpr(cond)
+		if err != nil {
+			return nil, err
+		}
+		body, end, err := p.parseBody()
+		if err != nil {
+			return nil, err
+		}
+		node.conds = append(node.conds, expr)
+		node.bodies = append(node.bodies, body)
+
+		switch keyword, args, _ := strings.Cut(end, " "); keyword {
+		case "elif":
+			cond = args
+			continue
+		case "else":
+			node.elseBody, end, err = p.parseBody()
+			if err != nil {
+				return nil, err
+			}
+			if end != "endif" {
+				return nil, jinjaErrorf("expected endif, got %q", end)
+			}
+			return node, nil
+		case "endif":
+			return node, nil
+		default:
+			return nil, jinjaErrorf("unclosed if %q", cond)
+		}
+	}
+}
+
+func (p *jinjaParser) parseFor(args string) (jinjaNode, error) {
+	targets, iter, ok := strings.Cut(args, " in ")
+	if !ok {
+		return nil, jinjaErrorf("invalid for %q", args)
+	}
+	node := jinjaForNode{}
+	for _, name := range strings.Split(targets, ",") {
+		name = strings.TrimSpace(name)
+		if !isJinjaName(name) {
+			return nil, jinjaErrorf("invalid loop variable %q", name)
+		}
+		node.vars = append(node.vars, name)
+	}
+	expr, err := parseJinjaLoopIter(iter)
+	if err != nil {
+		return nil, err
+	}
+	node.iter = expr
+
+	body, end, err := p.parseBody()
+	if err != nil {
+		return nil, err
+	}
+	node.body = body
+	if end == "else" {
+		node.elseBody, end, err = p.parseBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if end != "endfor" {
+		return nil, jinjaErrorf("unclosed for %q", args)
+	}
+	return node, nil
+}
+
+func parseJinjaSet(args string) (jinjaNode, error) {
+	name, value, ok := strings.Cut(args, "=")
+	name = strings.TrimSpace(name)
+	if !ok || !isJinjaName(name) {
+		return nil, jinjaErrorf("invalid set %q", args)
+	}
+	expr, err := parseJinjaExpr(value)
+	if err != nil {
+		return nil, err
+	}
+	return jinjaSetNode{name: name, expr: expr}, nil
+}
+
+func isJinjaName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rendering of the nodes.
+
+// jinjaScope holds the variables visible to the nodes, innermost last.
+type jinjaScope struct {
+	vars  []map[string]any
+	funcs map[string]any
+}
+
+// jinjaUndefined is the value of undefined variables and attributes, rendered
+// as an empty string.
+type jinjaUndefined struct{}
+
+func (s *jinjaScope) lookup(name string) any {
+	for i := len(s.vars) - 1; i >= 0; i-- {
+		if v, ok := s.vars[i][name]; ok {
+			return v
+		}
+	}
+	return jinjaUndefined{}
+}
+
+func renderJinjaNodes(sb *strings.Builder, nodes []jinjaNode, s *jinjaScope) error {
+	for _, n := range nodes {
+		if err := n.render(sb, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n jinjaTextNode) render(sb *strings.Builder, _ *jinjaScope) error {
+	sb.WriteString(string(n))
+	return nil
+}
+
+func (n jinjaOutputNode) render(sb *strings.Builder, s *jinjaScope) error {
+	v, err := n.expr.eval(s)
+	if err != nil {
+		return err
+	}
+	text, err := jinjaString(v)
+	if err != nil {
+		return err
+	}
+	sb.WriteString(text)
+	return nil
+}
+
+func (n jinjaIfNode) render(sb *strings.Builder, s *jinjaScope) error {
+	for i, cond := range n.conds {
+		v, err := cond.eval(s)
+		if err != nil {
+			return err
+		}
+		if jinjaTruthy(v) {
+			return renderJinjaNodes(sb, n.bodies[i], s)
+		}
+	}
+	return renderJinjaNodes(sb, n.elseBody, s)
+}
+
+func (n jinjaForNode) render(sb *strings.Builder, s *jinjaScope) error {
+	v, err := n.iter.eval(s)
+	if err != nil {
+		return err
+	}
+	items, err := jinjaItems(v)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return renderJinjaNodes(sb, n.elseBody, s)
+	}
+
+	scope := map[string]any{}
+	s.vars = append(s.vars, scope)
+	defer func() { s.vars = s.vars[:len(s.vars)-1] }()
+	for i, item := range items {
+		for k := range scope {
+			delete(scope, k)
+		}
+		loop := map[string]any{
+			"index":     i + 1,
+			"index0":    i,
+			"revindex":  len(items) - i,
+			"revindex0": len(items) - i - 1,
+			"first":     i == 0,
+			"last":      i == len(items)-1,
+			"length":    len(items),
+		}
+		if i > 0 {
+			loop["previtem"] = items[i-1]
+		}
+		if i < len(items)-1 {
+			loop["nextitem"] = items[i+1]
+		}
+		scope["loop"] = loop
+		if len(n.vars) == 1 {
+			scope[n.vars[0]] = item
+		} else {
+			values, err := jinjaItems(item)
+			if err != nil || len(values) != len(n.vars) {
+				return jinjaErrorf("can not unpack %v into %d loop variables", item, len(n.vars))
+			}
+			for j, name := range n.vars {
+				scope[name] = values[j]
+			}
+		}
+		if err := renderJinjaNodes(sb, n.body, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n jinjaSetNode) render(_ *strings.Builder, s *jinjaScope) error {
+	v, err := n.expr.eval(s)
+	if err != nil {
+		return err
+	}
+	s.vars[len(s.vars)-1][n.name] = v
+	return nil
+}
+
+// Expressions.
+
+type jinjaExpr interface {
+	eval(s *jinjaScope) (any, error)
+}
+
+type (
+	jinjaLiteral struct{ value any }
+	jinjaName    struct{ name string }
+	jinjaList    struct{ items []jinjaExpr }
+	jinjaAttr    struct {
+		x    jinjaExpr
+		name string
+	}
+	jinjaIndex struct{ x, index jinjaExpr }
+	jinjaCall  struct {
+		name string
+		args []jinjaExpr
+	}
+	jinjaMethod struct {
+		x    jinjaExpr
+		name string
+	}
+	jinjaFilter struct {
+		x    jinjaExpr
+		name string
+		args []jinjaExpr
+	}
+	jinjaTest struct {
+		x      jinjaExpr
+		name   string
+		negate bool
+	}
+	jinjaUnary struct {
+		op string
+		x  jinjaExpr
+	}
+	jinjaBinary struct {
+		op   string
+		l, r jinjaExpr
+	}
+	jinjaCond struct{ cond, then, otherwise jinjaExpr }
+)
+
+func (e jinjaLiteral) eval(*jinjaScope) (any, error) { return e.value, nil }
+
+func (e jinjaName) eval(s *jinjaScope) (any, error) { return s.lookup(e.name), nil }
+
+func (e jinjaList) eval(s *jinjaScope) (any, error) {
+	items := make([]any, len(e.items))
+	for i, item := range e.items {
+		v, err := item.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = v
+	}
+	return items, nil
+}
+
+func (e jinjaAttr) eval(s *jinjaScope) (any, error) {
+	x, err := e.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := x.(jinjaUndefined); ok {
+		return nil, jinjaErrorf("attribute %q of an undefined value", e.name)
+	}
+	return jinjaGet(x, e.name), nil
+}
+
+func (e jinjaIndex) eval(s *jinjaScope) (any, error) {
+	x, err := e.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := x.(jinjaUndefined); ok {
+		return nil, jinjaErrorf("index of an undefined value")
+	}
+	index, err := e.index.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	return jinjaGet(x, index), nil
+}
+
+func (e jinjaCall) eval(s *jinjaScope) (any, error) {
+	args, err := evalJinjaArgs(e.args, s)
+	if err != nil {
+		return nil, err
+	}
+	if fn, ok := s.funcs[e.name]; ok {
+		return callJinjaFunc(e.name, fn, args)
+	}
+	if e.name == "range" {
+		return jinjaRange(args)
+	}
+	return nil, jinjaErrorf("unknown function %q", e.name)
+}
+
+func (e jinjaMethod) eval(s *jinjaScope) (any, error) {
+	x, err := e.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	keys, values, ok := jinjaMapEntries(x)
+	if !ok {
+		return nil, jinjaErrorf("%s() called on %T, not a mapping", e.name, x)
+	}
+	result := make([]any, len(keys))
+	for i := range keys {
+		switch e.name {
+		case "items":
+			result[i] = []any{keys[i], values[i]}
+		case "keys":
+			result[i] = keys[i]
+		case "values":
+			result[i] = values[i]
+		}
+	}
+	return result, nil
+}
+
+func (e jinjaFilter) eval(s *jinjaScope) (any, error) {
+	x, err := e.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	args, err := evalJinjaArgs(e.args, s)
+	if err != nil {
+		return nil, err
+	}
+	if filter, ok := jinjaFilters[e.name]; ok {
+		if len(args) < filter.minArgs || len(args) > filter.maxArgs {
+			return nil, jinjaErrorf("%s filter called with %d arguments", e.name, len(args))
+		}
+		return filter.fn(x, args)
+	}
+	if fn, ok := s.funcs[e.name]; ok {
+		return callJinjaFunc(e.name, fn, append([]any{x}, args...))
+	}
+	return nil, jinjaErrorf("unknown filter %q", e.name)
+}
+
+func (e jinjaTest) eval(s *jinjaScope) (any, error) {
+	x, err := e.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	var result bool
+	switch e.name {
+	case "defined":
+		_, undefined := x.(jinjaUndefined)
+		result = !undefined
+	case "undefined":
+		_, result = x.(jinjaUndefined)
+	case "none":
+		result = x == nil
+	case "string":
+		_, result = x.(string)
+	case "number":
+		_, _, result = jinjaNumber(x)
+	case "even", "odd":
+		n, isInt, ok := jinjaNumber(x)
+		if !ok || !isInt {
+			return nil, jinjaErrorf("%s test of %v, not an integer", e.name, x)
+		}
+		result = (int64(n)%2 == 0) == (e.name == "even")
+	default:
+		return nil, jinjaErrorf("unknown test %q", e.name)
+	}
+	return result != e.negate, nil
+}
+
+func (e jinjaUnary) eval(s *jinjaScope) (any, error) {
+	x, err := e.x.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if e.op == "not" {
+		return !jinjaTruthy(x), nil
+	}
+	n, isInt, ok := jinjaNumber(x)
+	if !ok {
+		return nil, jinjaErrorf("-%v, not a number", x)
+	}
+	if isInt {
+		return -int64(n), nil
+	}
+	return -n, nil
+}
+
+func (e jinjaBinary) eval(s *jinjaScope) (any, error) {
+	l, err := e.l.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "and":
+		if !jinjaTruthy(l) {
+			return l, nil
+		}
+		return e.r.eval(s)
+	case "or":
+		if jinjaTruthy(l) {
+			return l, nil
+		}
+		return e.r.eval(s)
+	}
+	r, err := e.r.eval(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "==":
+		return jinjaEqual(l, r), nil
+	case "!=":
+		return !jinjaEqual(l, r), nil
+	case "in":
+		return jinjaContains(r, l)
+	case "not in":
+		in, err := jinjaContains(r, l)
+		return !in, err
+	case "~":
+		ls, err := jinjaString(l)
+		if err != nil {
+			return nil, err
+		}
+		rs, err := jinjaString(r)
+		return ls + rs, err
+	case "<", ">", "<=", ">=":
+		c, err := jinjaCompare(l, r)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]bool{"<": c < 0, ">": c > 0, "<=": c <= 0, ">=": c >= 0}[e.op], nil
+	}
+	return jinjaArithmetic(e.op, l, r)
+}
+
+func (e jinjaCond) eval(s *jinjaScope) (any, error) {
+	cond, err := e.cond.eval(s)
+	if err != nil {
+		return nil, err
+	}
+	if jinjaTruthy(cond) {
+		return e.then.eval(s)
+	}
+	if e.otherwise == nil {
+		return jinjaUndefined{}, nil
+	}
+	return e.otherwise.eval(s)
+}
+
+func evalJinjaArgs(exprs []jinjaExpr, s *jinjaScope) ([]any, error) {
+	args := make([]any, len(exprs))
+	for i, e := range exprs {
+		v, err := e.eval(s)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+// Lexing and parsing of expressions.
+
+type jinjaExprToken struct {
+	kind  byte // 'n' name, 'i' integer, 'f' float, 's' string, 'o' operator
+	text  string
+	value any
+}
+
+func lexJinjaExpr(src string) ([]jinjaExprToken, error) {
+	var tokens []jinjaExprToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, jinjaExprToken{kind: 'n', text: src[i:j]})
+			i = j
+		case unicode.IsDigit(rune(c)):
+			j := i
+			isFloat := false
+			for j < len(src) && (unicode.IsDigit(rune(src[j])) || (src[j] == '.' && !isFloat)) {
+				isFloat = isFloat || src[j] == '.'
+				j++
+			}
+			if isFloat {
+				f, err := strconv.ParseFloat(src[i:j], 64)
+				if err != nil {
+					return nil, jinjaErrorf("invalid number %q", src[i:j])
+				}
+				tokens = append(tokens, jinjaExprToken{kind: 'f', text: src[i:j], value: f})
+			} else {
+				n, err := strconv.ParseInt(src[i:j], 10, 64)
+				if err != nil {
+					return nil, jinjaErrorf("invalid number %q", src[i:j])
+				}
+				tokens = append(tokens, jinjaExprToken{kind: 'i', text: src[i:j], value: n})
+			}
+			i = j
+		case c == '"' || c == '\'':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != c; j++ {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+					switch src[j] {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					case 'r':
+						sb.WriteByte('\r')
+					case '\\', '\'', '"':
+						sb.WriteByte(src[j])
+					case 'x', 'u', 'U', 'N', '0', '1', '2', '3', '4', '5', '6', '7', 'a', 'b', 'f', 'v':
+						return nil, jinjaErrorf("unsupported escape \\%c in %q", src[j], src)
+					default:
+						// As in Python, unknown escapes are kept.
+						sb.WriteByte('\\')
+						sb.WriteByte(src[j])
+					}
+					continue
+				}
+				sb.WriteByte(src[j])
+			}
+			if j >= len(src) {
+				return nil, jinjaErrorf("unclosed string in %q", src)
+			}
+			tokens = append(tokens, jinjaExprToken{kind: 's', text: src[i : j+1], value: sb.String()})
+			i = j + 1
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "<", ">", "(", ")", "[", "]", ".", ",", "|", "~", "+", "-", "*", "//", "/", "%"} { //nolint:lll
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, jinjaErrorf("unexpected %q in %q", c, src)
+			}
+			tokens = append(tokens, jinjaExprToken{kind: 'o', text: op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+type jinjaExprParser struct {
+	src    string
+	tokens []jinjaExprToken
+	pos    int
+}
+
+func parseJinjaExpr(src string) (jinjaExpr, error) {
+	tokens, err := lexJinjaExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &jinjaExprParser{src: src, tokens: tokens}
+	expr, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, jinjaErrorf("unexpected %q in %q", p.tokens[p.pos].text, src)
+	}
+	return expr, nil
+}
+
+// parseJinjaLoopIter parses the iterable of a for loop, which can not have a
+// loop filter, "for x in items if x", nor be a conditional expression.
+func parseJinjaLoopIter(src string) (jinjaExpr, error) {
+	tokens, err := lexJinjaExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &jinjaExprParser{src: src, tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("if") {
+		return nil, jinjaErrorf("loop filters are not supported in %q", src)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, jinjaErrorf("unexpected %q in %q", p.tokens[p.pos].text, src)
+	}
+	return expr, nil
+}
+
+func (p *jinjaExprParser) peek() (jinjaExprToken, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return jinjaExprToken{}, false
+}
+
+// accept consumes the next token if it is a name or operator with the text.
+func (p *jinjaExprParser) accept(text string) bool {
+	tok, ok := p.peek()
+	if ok && (tok.kind == 'n' || tok.kind == 'o') && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *jinjaExprParser) expect(text string) error {
+	if !p.accept(text) {
+		return jinjaErrorf("expected %q in %q", text, p.src)
+	}
+	return nil
+}
+
+func (p *jinjaExprParser) name() (string, error) {
+	tok, ok := p.peek()
+	if !ok || tok.kind != 'n' {
+		return "", jinjaErrorf("expected a name in %q", p.src)
+	}
+	p.pos++
+	return tok.text, nil
+}
+
+// parseConditional parses "x if cond else y" expressions.
+func (p *jinjaExprParser) parseConditional() (jinjaExpr, error) {
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("if") {
+		return expr, nil
+	}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	result := jinjaCond{cond: cond, then: expr}
+	if p.accept("else") {
+		if result.otherwise, err = p.parseConditional(); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (p *jinjaExprParser) parseOr() (jinjaExpr, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = jinjaBinary{op: "or", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *jinjaExprParser) parseAnd() (jinjaExpr, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = jinjaBinary{op: "and", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *jinjaExprParser) parseNot() (jinjaExpr, error) {
+	if p.accept("not") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return jinjaUnary{op: "not", x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *jinjaExprParser) parseComparison() (jinjaExpr, error) {
+	l, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	compared := false
+	for {
+		op := ""
+		for _, candidate := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+			if p.accept(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" && p.pos+1 < len(p.tokens) && p.tokens[p.pos].text == "not" && p.tokens[p.pos+1].text == "in" {
+			p.pos += 2
+			op = "not in"
+		}
+		if op == "" && p.accept("is") {
+			test := jinjaTest{x: l, negate: p.accept("not")}
+			if test.name, err = p.name(); err != nil {
+				return nil, err
+			}
+			l = test
+			continue
+		}
+		if op == "" {
+			return l, nil
+		}
+		if compared {
+			return nil, jinjaErrorf("chained comparisons are not supported in %q", p.src)
+		}
+		compared = true
+		r, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		l = jinjaBinary{op: op, l: l, r: r}
+	}
+}
+
+func (p *jinjaExprParser) parseAdditive() (jinjaExpr, error) {
+	l, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, candidate := range []string{"+", "-", "~"} {
+			if p.accept(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return l, nil
+		}
+		r, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		l = jinjaBinary{op: op, l: l, r: r}
+	}
+}
+
+func (p *jinjaExprParser) parseMultiplicative() (jinjaExpr, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := ""
+		for _, candidate := range []string{"*", "//", "/", "%"} {
+			if p.accept(candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = jinjaBinary{op: op, l: l, r: r}
+	}
+}
+
+func (p *jinjaExprParser) parseUnary() (jinjaExpr, error) {
+	if p.accept("-") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return jinjaUnary{op: "-", x: x}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix parses attributes, indexes, method calls and filters following
+// a primary expression.
+func (p *jinjaExprParser) parsePostfix() (jinjaExpr, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if p.accept("(") {
+				if name != "items" && name != "keys" && name != "values" {
+					return nil, jinjaErrorf("unknown method %q", name)
+				}
+				if err := p.expect(")"); err != nil {
+					return nil, err
+				}
+				x = jinjaMethod{x: x, name: name}
+				continue
+			}
+			x = jinjaAttr{x: x, name: name}
+		case p.accept("["):
+			index, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = jinjaIndex{x: x, index: index}
+		case p.accept("|"):
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			filter := jinjaFilter{x: x, name: name}
+			if p.accept("(") {
+				if filter.args, err = p.parseArgs(); err != nil {
+					return nil, err
+				}
+			}
+			x = filter
+		default:
+			return x, nil
+		}
+	}
+}
+
+// parseArgs parses arguments until the closing parenthesis.
+func (p *jinjaExprParser) parseArgs() ([]jinjaExpr, error) {
+	return p.parseItems(")")
+}
+
+func (p *jinjaExprParser) parseItems(closing string) ([]jinjaExpr, error) {
+	var items []jinjaExpr
+	for !p.accept(closing) {
+		if len(items) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+			if p.accept(closing) {
+				break
+			}
+		}
+		item, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (p *jinjaExprParser) parsePrimary() (jinjaExpr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, jinjaErrorf("unexpected end of %q", p.src)
+	}
+	p.pos++
+	switch tok.kind {
+	case 'i', 'f', 's':
+		return jinjaLiteral{value: tok.value}, nil
+	case 'n':
+		switch tok.text {
+		case "true", "True":
+			return jinjaLiteral{value: true}, nil
+		case "false", "False":
+			return jinjaLiteral{value: false}, nil
+		case "none", "None":
+			return jinjaLiteral{value: nil}, nil
+		}
+		if p.accept("(") {
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return jinjaCall{name: tok.text, args: args}, nil
+		}
+		return jinjaName{name: tok.text}, nil
+	}
+	switch tok.text {
+	case "(":
+		expr, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	case "[":
+		items, err := p.parseItems("]")
+		if err != nil {
+			return nil, err
+		}
+		return jinjaList{items: items}, nil
+	}
+	return nil, jinjaErrorf("unexpected %q in %q", tok.text, p.src)
+}
+
+// Values.
+
+// jinjaString returns the text of a value as rendered by jinja2: None for
+// nil, the Python representation of lists and mappings, and an error for the
+// values without one, such as structs, unless they are a fmt.Stringer.
+func jinjaString(v any) (string, error) {
+	switch v := v.(type) {
+	case jinjaUndefined:
+		return "", nil
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	return jinjaRepr(v)
+}
+
+// jinjaRepr returns the Python representation of a value, as the items of
+// lists and mappings are rendered.
+func jinjaRepr(v any) (string, error) { //nolint:cyclop
+	switch v := v.(type) {
+	case nil:
+		return "None", nil
+	case jinjaUndefined:
+		return "Undefined", nil
+	case string:
+		return pythonQuote(v), nil
+	case bool:
+		if v {
+			return "True", nil
+		}
+		return "False", nil
+	case fmt.Stringer:
+		return pythonQuote(v.String()), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return pythonFloat(rv.Float()), nil
+	case reflect.Slice, reflect.Array:
+		items, err := jinjaItems(v)
+		if err != nil {
+			return "", err
+		}
+		texts := make([]string, len(items))
+		for i, item := range items {
+			if texts[i], err = jinjaRepr(item); err != nil {
+				return "", err
+			}
+		}
+		return "[" + strings.Join(texts, ", ") + "]", nil
+	case reflect.Map:
+		keys, values, _ := jinjaMapEntries(v)
+		texts := make([]string, len(keys))
+		for i := range keys {
+			k, err := jinjaRepr(keys[i])
+			if err != nil {
+				return "", err
+			}
+			value, err := jinjaRepr(values[i])
+			if err != nil {
+				return "", err
+			}
+			texts[i] = k + ": " + value
+		}
+		return "{" + strings.Join(texts, ", ") + "}", nil
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return "None", nil
+		}
+		return jinjaRepr(rv.Elem().Interface())
+	}
+	return "", jinjaErrorf("can not render a %T", v)
+}
+
+// pythonQuote returns the string quoted as by Python's repr: with single
+// quotes, or double quotes if it only has single quotes.
+func pythonQuote(s string) string {
+	quote := '\''
+	if strings.ContainsRune(s, '\'') && !strings.ContainsRune(s, '"') {
+		quote = '"'
+	}
+	var sb strings.Builder
+	sb.WriteRune(quote)
+	for _, r := range s {
+		switch {
+		case r == quote || r == '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&sb, `\x%02x`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteRune(quote)
+	return sb.String()
+}
+
+// pythonFloat formats the float as Python does: with a decimal point, and in
+// scientific notation when very large or small.
+func pythonFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	if abs := math.Abs(f); abs != 0 && (abs >= 1e16 || abs < 1e-4) {
+		return strconv.FormatFloat(f, 'e', -1, 64)
+	}
+	text := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(text, ".") {
+		text += ".0"
+	}
+	return text
+}
+
+func jinjaTruthy(v any) bool {
+	switch v := v.(type) {
+	case nil, jinjaUndefined:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	if n, _, ok := jinjaNumber(v); ok {
+		return n != 0
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() > 0
+	case reflect.Pointer, reflect.Interface:
+		return !rv.IsNil()
+	}
+	return true
+}
+
+// jinjaNumber returns the value of numbers as a float64, and whether they
+// are integers.
+func jinjaNumber(v any) (float64, bool, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true, true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), false, true
+	}
+	return 0, false, false
+}
+
+// jinjaInt returns the value of integers.
+func jinjaInt(v any) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+func jinjaEqual(l, r any) bool {
+	_, lUndefined := l.(jinjaUndefined)
+	_, rUndefined := r.(jinjaUndefined)
+	if lUndefined || rUndefined {
+		return lUndefined && rUndefined
+	}
+	ln, _, lok := jinjaNumber(l)
+	rn, _, rok := jinjaNumber(r)
+	if lok && rok {
+		return ln == rn
+	}
+	return reflect.DeepEqual(l, r)
+}
+
+func jinjaCompare(l, r any) (int, error) {
+	ln, _, lok := jinjaNumber(l)
+	rn, _, rok := jinjaNumber(r)
+	switch {
+	case lok && rok:
+		switch {
+		case ln < rn:
+			return -1, nil
+		case ln > rn:
+			return 1, nil
+		}
+		return 0, nil
+	case isString(l) && isString(r):
+		return strings.Compare(l.(string), r.(string)), nil
+	}
+	return 0, jinjaErrorf("can not compare %T and %T", l, r)
+}
+
+func isString(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+// jinjaArithmetic applies the operator as Python does: / is a float division,
+// // a floor division and % the remainder of the floor division.
+func jinjaArithmetic(op string, l, r any) (any, error) { //nolint:cyclop
+	if op == "+" && isString(l) && isString(r) {
+		return l.(string) + r.(string), nil
+	}
+	ln, lInt, lok := jinjaNumber(l)
+	rn, rInt, rok := jinjaNumber(r)
+	if !lok || !rok {
+		return nil, jinjaErrorf("%T %s %T, not numbers", l, op, r)
+	}
+	if (op == "/" || op == "//" || op == "%") && rn == 0 {
+		return nil, jinjaErrorf("division by zero")
+	}
+	if lInt && rInt && op != "/" {
+		a, _ := jinjaInt(l)
+		b, _ := jinjaInt(r)
+		switch op {
+		case "+":
+			return a + b, nil
+		case "-":
+			return a - b, nil
+		case "*":
+			return a * b, nil
+		case "//":
+			q := a / b
+			if (a%b != 0) && ((a < 0) != (b < 0)) {
+				q--
+			}
+			return q, nil
+		case "%":
+			m := a % b
+			if m != 0 && ((m < 0) != (b < 0)) {
+				m += b
+			}
+			return m, nil
+		}
+	}
+	switch op {
+	case "+":
+		return ln + rn, nil
+	case "-":
+		return ln - rn, nil
+	case "*":
+		return ln * rn, nil
+	case "/":
+		return ln / rn, nil
+	case "//":
+		return math.Floor(ln / rn), nil
+	case "%":
+		m := math.Mod(ln, rn)
+		if m != 0 && ((m < 0) != (rn < 0)) {
+			m += rn
+		}
+		return m, nil
+	}
+	return nil, jinjaErrorf("unknown operator %s", op)
+}
+
+func jinjaContains(container, item any) (bool, error) {
+	if s, ok := container.(string); ok {
+		sub, ok := item.(string)
+		if !ok {
+			return false, jinjaErrorf("%T in a string, not a string", item)
+		}
+		return strings.Contains(s, sub), nil
+	}
+	if keys, _, ok := jinjaMapEntries(container); ok {
+		for _, k := range keys {
+			if jinjaEqual(k, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	items, err := jinjaItems(container)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range items {
+		if jinjaEqual(v, item) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// jinjaItems returns the items iterated over by a for loop: the elements of
+// slices and arrays, the keys of maps in order and the characters of strings.
+func jinjaItems(v any) ([]any, error) {
+	switch v := v.(type) {
+	case jinjaUndefined:
+		return nil, nil
+	case []any:
+		return v, nil
+	case string:
+		items := make([]any, 0, len(v))
+		for _, r := range v {
+			items = append(items, string(r))
+		}
+		return items, nil
+	}
+	if keys, _, ok := jinjaMapEntries(v); ok {
+		return keys, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, jinjaErrorf("can not iterate over %T", v)
+	}
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, nil
+}
+
+// jinjaMapEntries returns the keys and values of maps, ordered by key, as Go
+// maps have no insertion order.
+func jinjaMapEntries(v any) ([]any, []any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil, nil, false
+	}
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i].Interface(), keys[j].Interface()
+		if c, err := jinjaCompare(a, b); err == nil {
+			return c < 0
+		}
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	})
+	ks := make([]any, len(keys))
+	vs := make([]any, len(keys))
+	for i, k := range keys {
+		ks[i] = k.Interface()
+		vs[i] = rv.MapIndex(k).Interface()
+	}
+	return ks, vs, true
+}
+
+// jinjaGet returns the attribute, key or index of a value, undefined if it has
+// none.
+func jinjaGet(v any, key any) any { //nolint:cyclop
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return jinjaUndefined{}
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Map:
+		k := reflect.ValueOf(key)
+		if !k.IsValid() {
+			return jinjaUndefined{}
+		}
+		keyType := rv.Type().Key()
+		if !k.Type().AssignableTo(keyType) {
+			// Numbers are converted to the numeric keys of the map, and
+			// strings to its string keys.
+			_, _, numericKey := jinjaNumber(reflect.Zero(keyType).Interface())
+			_, _, isNumber := jinjaNumber(key)
+			sameKind := (numericKey && isNumber) || (keyType.Kind() == reflect.String && k.Kind() == reflect.String)
+			if !sameKind || !k.Type().ConvertibleTo(keyType) {
+				return jinjaUndefined{}
+			}
+			k = k.Convert(keyType)
+		}
+		value := rv.MapIndex(k)
+		if !value.IsValid() {
+			return jinjaUndefined{}
+		}
+		return value.Interface()
+	case reflect.Struct:
+		name, ok := key.(string)
+		if !ok {
+			return jinjaUndefined{}
+		}
+		field := rv.FieldByName(name)
+		if !field.IsValid() || !field.CanInterface() {
+			return jinjaUndefined{}
+		}
+		return field.Interface()
+	case reflect.Slice, reflect.Array, reflect.String:
+		n, ok := jinjaInt(key)
+		if !ok {
+			return jinjaUndefined{}
+		}
+		length := rv.Len()
+		var runes []rune
+		if rv.Kind() == reflect.String {
+			runes = []rune(rv.String())
+			length = len(runes)
+		}
+		if n < 0 {
+			n += int64(length)
+		}
+		if n < 0 || n >= int64(length) {
+			return jinjaUndefined{}
+		}
+		if runes != nil {
+			return string(runes[n])
+		}
+		return rv.Index(int(n)).Interface()
+	}
+	return jinjaUndefined{}
+}
+
+// callJinjaFunc calls a Go function with the arguments, converted to the types
+// of its parameters. The function returns a value, and optionally an error.
+func callJinjaFunc(name string, fn any, args []any) (any, error) {
+	fv := reflect.ValueOf(fn)
+	if !fv.IsValid() || fv.Kind() != reflect.Func || fv.IsNil() {
+		return nil, jinjaErrorf("%s is not a function", name)
+	}
+	ft := fv.Type()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if ft.NumOut() < 1 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return nil, jinjaErrorf("%s is not a function returning a value and optionally an error", name)
+	}
+	if len(args) < ft.NumIn()-btoi(ft.IsVariadic()) || (!ft.IsVariadic() && len(args) > ft.NumIn()) {
+		return nil, jinjaErrorf("%s called with %d arguments", name, len(args))
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var pt reflect.Type
+		if ft.IsVariadic() && i >= ft.NumIn()-1 {
+			pt = ft.In(ft.NumIn() - 1).Elem()
+		} else {
+			pt = ft.In(i)
+		}
+		if _, ok := arg.(jinjaUndefined); ok {
+			arg = nil
+		}
+		av := reflect.ValueOf(arg)
+		_, _, isNumber := jinjaNumber(arg)
+		_, _, numericParam := jinjaNumber(reflect.Zero(pt).Interface())
+		switch {
+		case !av.IsValid():
+			av = reflect.Zero(pt)
+		case av.Type().AssignableTo(pt):
+		case isNumber && numericParam, av.Kind() == pt.Kind() && av.Type().ConvertibleTo(pt):
+			av = av.Convert(pt)
+		default:
+			return nil, jinjaErrorf("argument %d of %s is a %T, not a %s", i+1, name, arg, pt)
+		}
+		in[i] = av
+	}
+
+	out := fv.Call(in)
+	if len(out) == 2 && !out[1].IsNil() {
+		err, _ := out[1].Interface().(error)
+		return nil, err
+	}
+	return out[0].Interface(), nil
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// jinjaRange returns the integers of range(stop), range(start, stop) or
+// range(start, stop, step).
+func jinjaRange(args []any) (any, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, jinjaErrorf("range called with %d arguments", len(args))
+	}
+	bounds := make([]int64, len(args))
+	for i, arg := range args {
+		n, ok := jinjaInt(arg)
+		if !ok {
+			return nil, jinjaErrorf("range argument %d is a %T, not an integer", i+1, arg)
+		}
+		bounds[i] = n
+	}
+	start, stop, step := int64(0), bounds[0], int64(1)
+	if len(bounds) > 1 {
+		start, stop = bounds[0], bounds[1]
+	}
+	if len(bounds) > 2 {
+		step = bounds[2]
+	}
+	if step == 0 {
+		return nil, jinjaErrorf("range step must not be zero")
+	}
+	var items []any
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		if len(items) == _maxJinjaRange {
+			return nil, jinjaErrorf("range has more than %d items", _maxJinjaRange)
+		}
+		items = append(items, i)
+	}
+	return items, nil
+}
+
+// jinjaBuiltinFilter is a builtin filter, called with the filtered value and between
+// minArgs and maxArgs arguments.
+type jinjaBuiltinFilter struct {
+	minArgs, maxArgs int
+	fn               func(v any, args []any) (any, error)
+}
+
+// stringFilter returns a filter without arguments applying the function to
+// the text of the value.
+func stringFilter(fn func(string) string) jinjaBuiltinFilter {
+	return jinjaBuiltinFilter{fn: func(v any, _ []any) (any, error) {
+		s, err := jinjaString(v)
+		return fn(s), err
+	}}
+}
+
+// jinjaFilters are the builtin filters.
+var jinjaFilters = map[string]jinjaBuiltinFilter{ //nolint:gochecknoglobals
+	"upper": stringFilter(strings.ToUpper),
+	"lower": stringFilter(strings.ToLower),
+	"capitalize": stringFilter(func(s string) string {
+		s = strings.ToLower(s)
+		for i, r := range s {
+			return string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+		}
+		return s
+	}),
+	"title":  stringFilter(jinjaTitle),
+	"string": stringFilter(func(s string) string { return s }),
+	"trim": {maxArgs: 1, fn: func(v any, args []any) (any, error) {
+		s, err := jinjaString(v)
+		if err != nil || len(args) == 0 {
+			return strings.TrimSpace(s), err
+		}
+		chars, err := jinjaString(args[0])
+		return strings.Trim(s, chars), err
+	}},
+	"length":  {fn: jinjaLength},
+	"count":   {fn: jinjaLength},
+	"default": {minArgs: 1, maxArgs: 2, fn: jinjaDefault},
+	"d":       {minArgs: 1, maxArgs: 2, fn: jinjaDefault},
+	"join": {maxArgs: 1, fn: func(v any, args []any) (any, error) {
+		items, err := jinjaItems(v)
+		if err != nil {
+			return nil, err
+		}
+		sep := ""
+		if len(args) > 0 {
+			if sep, err = jinjaString(args[0]); err != nil {
+				return nil, err
+			}
+		}
+		texts := make([]string, len(items))
+		for i, item := range items {
+			if texts[i], err = jinjaString(item); err != nil {
+				return nil, err
+			}
+		}
+		return strings.Join(texts, sep), nil
+	}},
+	"first": {fn: func(v any, _ []any) (any, error) {
+		items, err := jinjaItems(v)
+		if err != nil || len(items) == 0 {
+			return jinjaUndefined{}, err
+		}
+		return items[0], nil
+	}},
+	"last": {fn: func(v any, _ []any) (any, error) {
+		items, err := jinjaItems(v)
+		if err != nil || len(items) == 0 {
+			return jinjaUndefined{}, err
+		}
+		return items[len(items)-1], nil
+	}},
+	"replace": {minArgs: 2, maxArgs: 3, fn: func(v any, args []any) (any, error) {
+		texts := make([]string, 3)
+		for i, x := range append([]any{v}, args[:2]...) {
+			var err error
+			if texts[i], err = jinjaString(x); err != nil {
+				return nil, err
+			}
+		}
+		count := int64(-1)
+		if len(args) == 3 {
+			var ok bool
+			if count, ok = jinjaInt(args[2]); !ok {
+				return nil, jinjaErrorf("replace count is a %T, not an integer", args[2])
+			}
+		}
+		return strings.Replace(texts[0], texts[1], texts[2], int(count)), nil
+	}},
+}
+
+func jinjaLength(v any, _ []any) (any, error) {
+	if s, ok := v.(string); ok {
+		return len([]rune(s)), nil
+	}
+	items, err := jinjaItems(v)
+	return len(items), err
+}
+
+func jinjaDefault(v any, args []any) (any, error) {
+	_, undefined := v.(jinjaUndefined)
+	if undefined || (len(args) > 1 && jinjaTruthy(args[1]) && !jinjaTruthy(v)) {
+		return args[0], nil
+	}
+	return v, nil
+}
+
+// jinjaTitle upper cases the first letter of the words of the text, and lower
+// cases the others, words starting after spaces, dashes and brackets.
+func jinjaTitle(s string) string {
+	var sb strings.Builder
+	start := true
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r) || strings.ContainsRune("-([{<", r):
+			sb.WriteRune(r)
+			start = true
+		case start:
+			sb.WriteRune(unicode.ToUpper(r))
+			start = false
+		default:
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return sb.String()
+}
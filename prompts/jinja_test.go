@@ -0,0 +1,175 @@
+package prompts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolateJinja2(t *testing.T) {
+	t.Parallel()
+
+	type doc struct {
+		Title string
+	}
+	values := map[string]any{
+		"name":   "world",
+		"count":  3,
+		"items":  []string{"a", "b", "c"},
+		"user":   map[string]any{"name": "Ada", "tags": []any{"x", "y"}},
+		"doc":    &doc{Title: "Go"},
+		"empty":  "",
+		"scores": map[string]int{"b": 2, "a": 1},
+	}
+	funcs := map[string]any{
+		"shout": func(s string) string { return strings.ToUpper(s) + "!" },
+		"add":   func(a, b int) int { return a + b },
+		"fail":  func() (string, error) { return "", errors.New("failed") },
+	}
+
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"Variable", "Hello {{ name }}!", "Hello world!"},
+		{"Attributes", "{{ user.name }} {{ user['name'] }} {{ user.tags[1] }} {{ doc.Title }}", "Ada Ada y Go"},
+		{"Undefined", "[{{ missing }}{{ user.missing }}]", "[]"},
+		{"Lists", "{{ items }} {{ user.tags }} {{ [1, 'a', none, true] }}", "['a', 'b', 'c'] ['x', 'y'] [1, 'a', None, True]"}, //nolint:lll
+		{"Mappings", "{{ scores }} {{ user }}", "{'a': 1, 'b': 2} {'name': 'Ada', 'tags': ['x', 'y']}"},
+		{"Quotes", `{{ ["it's", 'a"b', 'x\\ny'] }}`, `["it's", 'a"b', 'x\\ny']`},
+		{"None and booleans", "{{ none }} {{ true }} {{ 1 < 2 }}", "None True True"},
+		{"Floats", "{{ 4 / 2 }} {{ 0.5 * 3 }} {{ 10000000000.0 * 10000000000 }} {{ 0.001 / 100 }}", "2.0 1.5 1e+20 1e-05"},
+		{"Floor division", "{{ 7 // 2 }} {{ -7 // 2 }} {{ 7.0 // 2 }}", "3 -4 3.0"},
+		{"Modulo", "{{ -7 % 3 }} {{ 7 % -3 }} {{ 7.5 % 2 }}", "2 -2 1.5"},
+		{"Range", "{% for i in range(3) %}{{ i }}{% endfor %} {{ range(1, 10, 3) }} {{ range(3, 0, -1) }}", "012 [1, 4, 7] [3, 2, 1]"}, //nolint:lll
+		{"Title", "{{ 'hello wORLD-wide (web)' | title }} {{ 'hello' | capitalize }}", "Hello World-Wide (Web) Hello"},
+		{"Trim", "[{{ '  a  ' | trim }}] [{{ 'xxaxx' | trim('x') }}]", "[a] [a]"},
+		{"Replace", "{{ 'aaa' | replace('a', 'b') }} {{ 'aaa' | replace('a', 'b', 2) }}", "bbb bba"},
+		{"Default", "{{ missing | d('x') }} {{ empty | default('y', true) }} {{ empty | default('y') }}|", "x y |"},
+		{"Escapes", "{{ 'a\\tb\\nc' }} {{ 'it\\'s' }} {{ 'a\\qb' }}", "a\tb\nc it's a\\qb"},
+		{"Loop items", "{% for i in items %}{{ loop.previtem }}<{{ i }}>{{ loop.nextitem }} {% endfor %}", "<a>b a<b>c b<c> "}, //nolint:lll
+		{"Trailing newline", "{{ name }}\n", "world"},
+		{"String index", "{{ 'héllo'[1] }} {{ name[-1] }}", "é d"},
+		{"Filters", "{{ name | upper }} {{ items | join(', ') }} {{ items | length }} {{ missing | default('none') }}", "WORLD a, b, c 3 none"}, //nolint:lll
+		{"Functions", "{{ shout(name) }} {{ name | shout }} {{ add(count, 2) }}", "WORLD! WORLD! 5"},
+		{"Arithmetic", "{{ count * 2 + 1 }} {{ 7 / 2 }} {{ 7 % 4 }} {{ name ~ count }}", "7 3.5 3 world3"},
+		{"If", "{% if count > 5 %}many{% elif count > 1 %}some{% else %}one{% endif %}", "some"},
+		{"Logic", "{% if name == 'world' and not empty and 'b' in items %}yes{% endif %}", "yes"},
+		{"Tests", "{{ missing is defined }} {{ name is defined }} {{ count is odd }} {{ empty is not none }}", "False True True True"}, //nolint:lll
+		{"Conditional expression", "{{ 'a' if empty else 'b' }}", "b"},
+		{"For", "{% for item in items %}{{ loop.index }}.{{ item }}{% if not loop.last %} {% endif %}{% endfor %}", "1.a 2.b 3.c"}, //nolint:lll
+		{"For items", "{% for k, v in scores.items() %}{{ k }}={{ v }};{% endfor %}", "a=1;b=2;"},
+		{"For else", "{% for item in missing %}{{ item }}{% else %}nothing{% endfor %}", "nothing"},
+		{"Set", "{% set greeting = 'Hi ' ~ name %}{{ greeting }}", "Hi world"},
+		{"Comment", "a{# comment #}b", "ab"},
+		{"Whitespace control", "<ul>\n  {%- for item in items %}\n  <li>{{ item }}</li>\n  {%- endfor %}\n</ul>", "<ul>\n  <li>a</li>\n  <li>b</li>\n  <li>c</li>\n</ul>"}, //nolint:lll
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := interpolateJinja2(tc.template, values, funcs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	errTestCases := []struct {
+		name     string
+		template string
+	}{
+		{"Unclosed tag", "Hello {{ name"},
+		{"Unclosed block", "{% if name %}Hello"},
+		{"Unknown statement", "{% macro m() %}{% endmacro %}"},
+		{"Unknown filter", "{{ name | unknown }}"},
+		{"Unknown function", "{{ unknown(name) }}"},
+		{"Invalid expression", "{{ name + }}"},
+		{"Loop filter", "{% for i in items if i %}{{ i }}{% endfor %}"},
+		{"Chained comparison", "{{ 1 < count < 5 }}"},
+		{"Filter arguments", "{{ name | upper(1) }}"},
+		{"Missing filter arguments", "{{ name | replace('a') }}"},
+		{"Attribute of undefined", "{{ user.missing.deeper }}"},
+		{"Unsupported escape", `{{ 'a\x41' }}`},
+		{"Struct", "{{ doc }}"},
+		{"Power", "{{ 2 ** 3 }}"},
+		{"Dict literal", "{{ {'a': 1} }}"},
+		{"Tuple", "{% for a, b in [(1, 2)] %}{% endfor %}"},
+		{"Slice", "{{ items[1:] }}"},
+		{"String method", "{{ name.upper() }}"},
+		{"Division by zero", "{{ 1 // 0 }}"},
+		{"Range step", "{{ range(1, 2, 0) }}"},
+		{"Range size", "{{ range(1000000) }}"},
+	}
+
+	for _, tc := range errTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := interpolateJinja2(tc.template, values, funcs)
+			require.ErrorIs(t, err, ErrInvalidJinja2Template)
+		})
+	}
+
+	_, err := interpolateJinja2("{{ fail() }}", values, funcs)
+	require.EqualError(t, err, "failed")
+}
+
+func FuzzInterpolateJinja2(f *testing.F) {
+	seeds := []string{
+		"Hello {{ name }}!",
+		"{% for k, v in scores.items() %}{{ loop.index }}{{ k }}={{ v }}{% else %}-{% endfor %}",
+		"{% if count > 1 and 'a' in items %}{{ items | join(', ') | upper }}{% elif x %}{% endif %}",
+		"{% set x = count // 2 %}{{ x if x else range(3) }}{{ 'a\\n' ~ [1, 2.5, none] }}",
+		"{{- name | replace('o', '0', 1) | default('x') -}} {# c #}",
+		"{{ user.tags[-1] is defined }} {{ 7 % -3 }}",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	values := map[string]any{
+		"name":   "world",
+		"count":  3,
+		"items":  []string{"a", "b", "c"},
+		"user":   map[string]any{"name": "Ada", "tags": []any{"x", "y"}},
+		"scores": map[string]int{"b": 2, "a": 1},
+	}
+	f.Fuzz(func(t *testing.T, template string) {
+		_, err := interpolateJinja2(template, values, nil)
+		if err != nil && !errors.Is(err, ErrInvalidJinja2Template) {
+			t.Fatalf("error not wrapping ErrInvalidJinja2Template: %v", err)
+		}
+	})
+}
+
+func TestPromptTemplateFormats(t *testing.T) {
+	t.Parallel()
+
+	funcs := map[string]any{"shout": func(s string) string { return strings.ToUpper(s) + "!" }}
+
+	goTemplate := PromptTemplate{
+		Template:       `{{ range .items }}{{ shout . }} {{ end }}`,
+		InputVariables: []string{"items"},
+		TemplateFormat: TemplateFormatGoTemplate,
+		TemplateFuncs:  funcs,
+	}
+	result, err := goTemplate.Format(map[string]any{"items": []string{"a", "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, "A! B! ", result)
+
+	jinja2 := PromptTemplate{
+		Template:       `{% for item in items %}{{ item | shout }} {% endfor %}`,
+		InputVariables: []string{"items"},
+		TemplateFormat: TemplateFormatJinja2,
+		TemplateFuncs:  funcs,
+	}
+	result, err = jinja2.Format(map[string]any{"items": []string{"a", "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, "A! B! ", result)
+}
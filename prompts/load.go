@@ -0,0 +1,363 @@
+package prompts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/schema"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnsupportedPromptFile is returned when loading or saving a file whose
+	// extension is not .json, .yaml or .yml.
+	ErrUnsupportedPromptFile = errors.New("unsupported prompt file extension")
+	// ErrUnsupportedPrompt is returned when saving a prompt, message or output
+	// parser that can not be represented in a file.
+	ErrUnsupportedPrompt = errors.New("unsupported prompt type")
+	// ErrInvalidPromptFile is returned when loading a file not describing a
+	// prompt.
+	ErrInvalidPromptFile = errors.New("invalid prompt file")
+)
+
+const (
+	_promptFileType = "prompt"
+	_chatFileType   = "chat_prompt"
+)
+
+// promptFile is the content of a prompt file. Its "_type" is "prompt" for a
+// PromptTemplate and "chat_prompt" for a ChatPromptTemplate.
+type promptFile struct {
+	Type             string            `json:"_type"                       yaml:"_type"`
+	Template         string            `json:"template,omitempty"          yaml:"template,omitempty"`
+	InputVariables   []string          `json:"input_variables,omitempty"   yaml:"input_variables,omitempty"`
+	TemplateFormat   TemplateFormat    `json:"template_format,omitempty"   yaml:"template_format,omitempty"`
+	PartialVariables map[string]string `json:"partial_variables,omitempty" yaml:"partial_variables,omitempty"`
+	OutputParser     *outputParserFile `json:"output_parser,omitempty"     yaml:"output_parser,omitempty"`
+	Messages         []messageFile     `json:"messages,omitempty"          yaml:"messages,omitempty"`
+}
+
+// messageFile is a message of a chat prompt file. Its role is "system",
+// "human" or "ai", any other role making a generic message.
+type messageFile struct {
+	Role           string         `json:"role"                      yaml:"role"`
+	Template       string         `json:"template"                  yaml:"template"`
+	InputVariables []string       `json:"input_variables,omitempty" yaml:"input_variables,omitempty"`
+	TemplateFormat TemplateFormat `json:"template_format,omitempty" yaml:"template_format,omitempty"`
+}
+
+// outputParserFile is the output parser of a prompt file, with the fields of
+// the parsers of its type.
+type outputParserFile struct {
+	Type            string               `json:"_type"                      yaml:"_type"`
+	TrueStr         string               `json:"true_val,omitempty"         yaml:"true_val,omitempty"`
+	FalseStr        string               `json:"false_val,omitempty"        yaml:"false_val,omitempty"`
+	Regex           string               `json:"regex,omitempty"            yaml:"regex,omitempty"`
+	ResponseSchemas []responseSchemaFile `json:"response_schemas,omitempty" yaml:"response_schemas,omitempty"`
+}
+
+type responseSchemaFile struct {
+	Name        string `json:"name"        yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// Load loads a PromptTemplate or a ChatPromptTemplate from a JSON or YAML file,
+// as written by Save.
+func Load(path string) (FormatPrompter, error) { //nolint:ireturn
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file promptFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedPromptFile, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPromptFile, path, err)
+	}
+	prompt, err := file.prompt()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return prompt, nil
+}
+
+// Save writes a PromptTemplate or a ChatPromptTemplate to a JSON or YAML file,
+// depending on the extension of the path. Partial variables must be strings,
+// and the messages of chat prompts must be system, human, AI or generic
+// message prompt templates. Template funcs and the fix LLM of JSON output
+// parsers are not saved.
+func Save(prompt FormatPrompter, path string) error {
+	file, err := newPromptFile(prompt)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err = json.MarshalIndent(file, "", "  ")
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(file)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedPromptFile, path)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func newPromptFile(prompt FormatPrompter) (promptFile, error) {
+	switch p := prompt.(type) {
+	case PromptTemplate:
+		partials, err := stringPartials(p.PartialVariables)
+		if err != nil {
+			return promptFile{}, err
+		}
+		parser, err := newOutputParserFile(p.OutputParser)
+		if err != nil {
+			return promptFile{}, err
+		}
+		return promptFile{
+			Type:             _promptFileType,
+			Template:         p.Template,
+			InputVariables:   p.InputVariables,
+			TemplateFormat:   p.TemplateFormat,
+			PartialVariables: partials,
+			OutputParser:     parser,
+		}, nil
+	case ChatPromptTemplate:
+		partials, err := stringPartials(p.PartialVariables)
+		if err != nil {
+			return promptFile{}, err
+		}
+		file := promptFile{Type: _chatFileType, PartialVariables: partials}
+		for _, m := range p.Messages {
+			message, err := newMessageFile(m)
+			if err != nil {
+				return promptFile{}, err
+			}
+			file.Messages = append(file.Messages, message)
+		}
+		return file, nil
+	}
+	return promptFile{}, fmt.Errorf("%w: %T", ErrUnsupportedPrompt, prompt)
+}
+
+func newMessageFile(m MessageFormatter) (messageFile, error) {
+	var (
+		role   string
+		prompt PromptTemplate
+	)
+	switch m := m.(type) {
+	case SystemMessagePromptTemplate:
+		role, prompt = "system", m.Prompt
+	case HumanMessagePromptTemplate:
+		role, prompt = "human", m.Prompt
+	case AIMessagePromptTemplate:
+		role, prompt = "ai", m.Prompt
+	case GenericMessagePromptTemplate:
+		role, prompt = m.Role, m.Prompt
+	default:
+		return messageFile{}, fmt.Errorf("%w: message %T", ErrUnsupportedPrompt, m)
+	}
+	if len(prompt.PartialVariables) > 0 || prompt.OutputParser != nil {
+		return messageFile{}, fmt.Errorf("%w: message with partial variables or output parser", ErrUnsupportedPrompt)
+	}
+	return messageFile{
+		Role:           role,
+		Template:       prompt.Template,
+		InputVariables: prompt.InputVariables,
+		TemplateFormat: prompt.TemplateFormat,
+	}, nil
+}
+
+func stringPartials(partials map[string]any) (map[string]string, error) {
+	if len(partials) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(partials))
+	for name, value := range partials {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPartialVariableType, name)
+		}
+		values[name] = s
+	}
+	return values, nil
+}
+
+func newOutputParserFile(parser schema.OutputParser[any]) (*outputParserFile, error) {
+	switch p := parser.(type) {
+	case nil:
+		return nil, nil
+	case outputparser.Simple:
+		return &outputParserFile{Type: p.Type()}, nil
+	case outputparser.BooleanParser:
+		return &outputParserFile{Type: p.Type(), TrueStr: p.TrueStr, FalseStr: p.FalseStr}, nil
+	case outputparser.RegexParser:
+		return &outputParserFile{Type: p.Type(), Regex: p.Expression.String()}, nil
+	case outputparser.Structured:
+		file := &outputParserFile{Type: p.Type()}
+		for _, s := range p.ResponseSchemas {
+			file.ResponseSchemas = append(file.ResponseSchemas, responseSchemaFile(s))
+		}
+		return file, nil
+	case outputparser.JSON:
+		return &outputParserFile{Type: p.Type()}, nil
+	}
+	return nil, fmt.Errorf("%w: output parser %T", ErrUnsupportedPrompt, parser)
+}
+
+func (f promptFile) prompt() (FormatPrompter, error) { //nolint:ireturn
+	partials := make(map[string]any, len(f.PartialVariables))
+	for name, value := range f.PartialVariables {
+		partials[name] = value
+	}
+	switch f.Type {
+	case _promptFileType:
+		prompt := NewPromptTemplate(f.Template, f.InputVariables)
+		if f.TemplateFormat != "" {
+			prompt.TemplateFormat = f.TemplateFormat
+		}
+		if len(partials) > 0 {
+			prompt.PartialVariables = partials
+		}
+		if f.OutputParser != nil {
+			parser, err := f.OutputParser.parser()
+			if err != nil {
+				return nil, err
+			}
+			prompt.OutputParser = parser
+		}
+		return prompt, nil
+	case _chatFileType:
+		prompt := ChatPromptTemplate{}
+		if len(partials) > 0 {
+			prompt.PartialVariables = partials
+		}
+		for _, m := range f.Messages {
+			prompt.Messages = append(prompt.Messages, m.formatter())
+		}
+		return prompt, nil
+	}
+	return nil, fmt.Errorf("%w: unknown _type %q", ErrInvalidPromptFile, f.Type)
+}
+
+func (m messageFile) formatter() MessageFormatter { //nolint:ireturn
+	prompt := NewPromptTemplate(m.Template, m.InputVariables)
+	if m.TemplateFormat != "" {
+		prompt.TemplateFormat = m.TemplateFormat
+	}
+	switch m.Role {
+	case "system":
+		return SystemMessagePromptTemplate{Prompt: prompt}
+	case "human":
+		return HumanMessagePromptTemplate{Prompt: prompt}
+	case "ai":
+		return AIMessagePromptTemplate{Prompt: prompt}
+	}
+	return GenericMessagePromptTemplate{Prompt: prompt, Role: m.Role}
+}
+
+func (f outputParserFile) parser() (schema.OutputParser[any], error) { //nolint:ireturn
+	switch f.Type {
+	case outputparser.Simple{}.Type():
+		return outputparser.NewSimple(), nil
+	case outputparser.BooleanParser{}.Type():
+		parser := outputparser.NewBooleanParser()
+		if f.TrueStr != "" {
+			parser.TrueStr = f.TrueStr
+		}
+		if f.FalseStr != "" {
+			parser.FalseStr = f.FalseStr
+		}
+		return parser, nil
+	case outputparser.RegexParser{}.Type():
+		if _, err := regexp.Compile(f.Regex); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPromptFile, err)
+		}
+		return outputparser.NewRegexParser(f.Regex), nil
+	case outputparser.Structured{}.Type():
+		schemas := make([]outputparser.ResponseSchema, len(f.ResponseSchemas))
+		for i, s := range f.ResponseSchemas {
+			schemas[i] = outputparser.ResponseSchema(s)
+		}
+		return outputparser.NewStructured(schemas), nil
+	case outputparser.JSON{}.Type():
+		return outputparser.NewJSON(), nil
+	}
+	return nil, fmt.Errorf("%w: unknown output parser %q", ErrInvalidPromptFile, f.Type)
+}
+
+// ReloadingPrompt is a prompt loaded from a file with Load, and loaded again
+// when the file changes, so that prompts can be edited without restarting.
+type ReloadingPrompt struct {
+	path string
+
+	mu      sync.Mutex
+	prompt  FormatPrompter
+	modTime time.Time
+}
+
+var _ FormatPrompter = &ReloadingPrompt{}
+
+// NewReloadingPrompt loads the prompt of the file.
+func NewReloadingPrompt(path string) (*ReloadingPrompt, error) {
+	p := &ReloadingPrompt{path: path}
+	if _, err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FormatPrompt formats the prompt of the file, loading it again first if the
+// file was modified. A file that can not be loaded makes it fail.
+func (p *ReloadingPrompt) FormatPrompt(values map[string]any) (schema.PromptValue, error) { //nolint:ireturn
+	prompt, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	return prompt.FormatPrompt(values)
+}
+
+// GetInputVariables returns the input variables of the prompt last loaded.
+func (p *ReloadingPrompt) GetInputVariables() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.prompt.GetInputVariables()
+}
+
+// load returns the prompt, loading it again if the file was modified since it
+// was last loaded.
+func (p *ReloadingPrompt) load() (FormatPrompter, error) { //nolint:ireturn
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.prompt != nil && info.ModTime().Equal(p.modTime) {
+		return p.prompt, nil
+	}
+	prompt, err := Load(p.path)
+	if err != nil {
+		return nil, err
+	}
+	p.prompt, p.modTime = prompt, info.ModTime()
+	return prompt, nil
+}
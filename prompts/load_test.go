@@ -0,0 +1,108 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/outputparser"
+)
+
+func TestSaveLoadPromptTemplate(t *testing.T) {
+	t.Parallel()
+
+	prompt := NewPromptTemplate("{{.greeting}}, {{.name}}!", []string{"name"})
+	prompt.PartialVariables = map[string]any{"greeting": "Hello"}
+	prompt.OutputParser = outputparser.NewRegexParser(`(?P<name>\w+)`)
+
+	for _, name := range []string{"prompt.json", "prompt.yaml"} {
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, Save(prompt, path))
+
+		loaded, err := Load(path)
+		require.NoError(t, err)
+		require.Equal(t, prompt, loaded)
+
+		value, err := loaded.FormatPrompt(map[string]any{"name": "Ada"})
+		require.NoError(t, err)
+		require.Equal(t, "Hello, Ada!", value.String())
+	}
+}
+
+func TestSaveLoadChatPromptTemplate(t *testing.T) {
+	t.Parallel()
+
+	prompt := NewChatPromptTemplate([]MessageFormatter{
+		NewSystemMessagePromptTemplate("You are {{.persona}}.", []string{"persona"}),
+		NewHumanMessagePromptTemplate("{{.question}}", []string{"question"}),
+		NewAIMessagePromptTemplate("Let me think.", nil),
+		NewGenericMessagePromptTemplate("critic", "Be brief.", nil),
+	})
+
+	path := filepath.Join(t.TempDir(), "chat.yml")
+	require.NoError(t, Save(prompt, path))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, prompt, loaded)
+}
+
+func TestLoadYAML(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "prompt.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`_type: prompt
+template: |
+  Is {{.claim}} true?
+input_variables: [claim]
+output_parser:
+  _type: boolean_parser
+`), 0o600))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	prompt, ok := loaded.(PromptTemplate)
+	require.True(t, ok)
+	require.Equal(t, "Is {{.claim}} true?\n", prompt.Template)
+	require.Equal(t, TemplateFormatGoTemplate, prompt.TemplateFormat)
+	require.Equal(t, outputparser.NewBooleanParser(), prompt.OutputParser)
+}
+
+func TestLoadSaveErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.ErrorIs(t, Save(NewPromptTemplate("hi", nil), filepath.Join(dir, "prompt.txt")), ErrUnsupportedPromptFile)
+
+	prompt := NewPromptTemplate("{{.now}}", nil)
+	prompt.PartialVariables = map[string]any{"now": func() string { return "now" }}
+	require.ErrorIs(t, Save(prompt, filepath.Join(dir, "prompt.json")), ErrInvalidPartialVariableType)
+
+	path := filepath.Join(dir, "unknown.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"_type": "few_shot"}`), 0o600))
+	_, err := Load(path)
+	require.ErrorIs(t, err, ErrInvalidPromptFile)
+}
+
+func TestReloadingPrompt(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "prompt.json")
+	require.NoError(t, Save(NewPromptTemplate("Hello {{.name}}", []string{"name"}), path))
+
+	prompt, err := NewReloadingPrompt(path)
+	require.NoError(t, err)
+	value, err := prompt.FormatPrompt(map[string]any{"name": "Ada"})
+	require.NoError(t, err)
+	require.Equal(t, "Hello Ada", value.String())
+
+	require.NoError(t, Save(NewPromptTemplate("Goodbye {{.name}}", []string{"name"}), path))
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	value, err = prompt.FormatPrompt(map[string]any{"name": "Ada"})
+	require.NoError(t, err)
+	require.Equal(t, "Goodbye Ada", value.String())
+	require.Equal(t, []string{"name"}, prompt.GetInputVariables())
+}
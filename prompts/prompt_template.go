@@ -31,6 +31,10 @@ type PromptTemplate struct {
 	// PartialVariables represents a map of variable names to values or functions that return values.
 	// If the value is a function, it will be called when the prompt template is rendered.
 	PartialVariables map[string]any
+
+	// TemplateFuncs are custom functions the template can call, in addition to the
+	// sprig functions of go templates. Jinja2 templates can also use them as filters.
+	TemplateFuncs map[string]any
 }
 
 // NewPromptTemplate returns a new prompt template.
@@ -54,7 +58,7 @@ func (p PromptTemplate) Format(values map[string]any) (string, error) {
 		return "", err
 	}
 
-	return RenderTemplate(p.Template, p.TemplateFormat, resolvedValues)
+	return RenderTemplateWithFuncs(p.Template, p.TemplateFormat, resolvedValues, p.TemplateFuncs)
 }
 
 // FormatPrompt formats the prompt template and returns a string prompt value.
@@ -3,7 +3,10 @@ package prompts
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
@@ -20,23 +23,49 @@ type TemplateFormat string
 const (
 	// TemplateFormatGoTemplate is the format for go-template.
 	TemplateFormatGoTemplate TemplateFormat = "go-template"
+	// TemplateFormatJinja2 is the format for jinja2 templates. Only a subset of
+	// jinja2 is supported, and templates using anything else fail to render:
+	//   - statements: {% if %}/{% elif %}/{% else %}, {% for %} with {% else %},
+	//     {% set %}, {# comments #} and whitespace control with -;
+	//   - expressions: variables, attributes, indexes, strings, numbers, true,
+	//     false, none, list literals and x if cond else y;
+	//   - operators: + - * / // % ~, comparisons, in, not in, and, or, not;
+	//   - filters: upper, lower, capitalize, title, string, trim, length, count,
+	//     default (d), join, first, last and replace, and the template funcs;
+	//   - tests: defined, undefined, none, string, number, odd and even, and
+	//     is not;
+	//   - the items, keys and values methods of mappings, range() and the
+	//     index, index0, revindex, revindex0, first, last, length, previtem
+	//     and nextitem attributes of loop.
+	// Values render as Python's str: None, True, and lists and mappings as
+	// ['a', 1] and {'a': 1}, mappings being iterated in key order. Undefined
+	// variables render as empty strings, and one trailing newline is removed.
+	TemplateFormatJinja2 TemplateFormat = "jinja2"
 )
 
-// interpolator is the function that interpolates the given template with the given values.
-type interpolator func(template string, values map[string]any) (string, error)
+// interpolator is the function that interpolates the given template with the given values
+// and custom template functions.
+type interpolator func(template string, values map[string]any, funcs map[string]any) (string, error)
 
 // defaultFormatterMapping is the default mapping of TemplateFormat to interpolator.
 var defaultformatterMapping = map[TemplateFormat]interpolator{ //nolint:gochecknoglobals
 	TemplateFormatGoTemplate: interpolateGoTemplate,
+	TemplateFormatJinja2:     interpolateJinja2,
 }
 
+// _maxParsedTemplates is the number of parsed go templates cached, so that
+// templates built from data do not grow the cache without bound.
+const _maxParsedTemplates = 512
+
+var (
+	_parsedTemplates     sync.Map     //nolint:gochecknoglobals
+	_parsedTemplateCount atomic.Int64 //nolint:gochecknoglobals
+)
+
 // interpolateGoTemplate interpolates the given template with the given values by using
-// text/template.
-func interpolateGoTemplate(tmpl string, values map[string]any) (string, error) {
-	parsedTmpl, err := template.New("template").
-		Option("missingkey=error").
-		Funcs(sprig.FuncMap()).
-		Parse(tmpl)
+// text/template, with the sprig functions and the given funcs.
+func interpolateGoTemplate(tmpl string, values map[string]any, funcs map[string]any) (string, error) {
+	parsedTmpl, err := parseGoTemplate(tmpl, funcs)
 	if err != nil {
 		return "", err
 	}
@@ -48,11 +77,36 @@ func interpolateGoTemplate(tmpl string, values map[string]any) (string, error) {
 	return sb.String(), nil
 }
 
+// parseGoTemplate parses the go template. Templates without custom functions
+// are parsed once and cached, up to _maxParsedTemplates, executing a parsed
+// template being safe for concurrent use.
+func parseGoTemplate(tmpl string, funcs map[string]any) (*template.Template, error) {
+	if len(funcs) == 0 {
+		if parsed, ok := _parsedTemplates.Load(tmpl); ok {
+			return parsed.(*template.Template), nil //nolint:forcetypeassert
+		}
+	}
+	parsed, err := template.New("template").
+		Option("missingkey=error").
+		Funcs(sprig.FuncMap()).
+		Funcs(funcs).
+		Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	if len(funcs) == 0 && _parsedTemplateCount.Add(1) <= _maxParsedTemplates {
+		_parsedTemplates.Store(tmpl, parsed)
+	}
+	return parsed, nil
+}
+
 func newInvalidTemplateError(gotTemplateFormat TemplateFormat) error {
+	formats := maps.Keys(defaultformatterMapping)
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
 	return fmt.Errorf("%w, got: %s, should be one of %s",
 		ErrInvalidTemplateFormat,
 		gotTemplateFormat,
-		maps.Keys(defaultformatterMapping),
+		formats,
 	)
 }
 
@@ -75,9 +129,15 @@ func CheckValidTemplate(template string, templateFormat TemplateFormat, inputVar
 
 // RenderTemplate renders the template with the given values.
 func RenderTemplate(tmpl string, tmplFormat TemplateFormat, values map[string]any) (string, error) {
+	return RenderTemplateWithFuncs(tmpl, tmplFormat, values, nil)
+}
+
+// RenderTemplateWithFuncs renders the template with the given values and custom template
+// functions. The functions return a value, and optionally an error.
+func RenderTemplateWithFuncs(tmpl string, tmplFormat TemplateFormat, values map[string]any, funcs map[string]any) (string, error) { //nolint:lll
 	formatter, ok := defaultformatterMapping[tmplFormat]
 	if !ok {
 		return "", newInvalidTemplateError(tmplFormat)
 	}
-	return formatter(tmpl, values)
+	return formatter(tmpl, values, funcs)
 }
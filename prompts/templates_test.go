@@ -53,7 +53,7 @@ func TestInterpolateGoTemplate(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			actual, err := interpolateGoTemplate(tc.template, tc.templateValues)
+			actual, err := interpolateGoTemplate(tc.template, tc.templateValues, nil)
 			require.NoError(t, err)
 			assert.Equal(t, tc.expected, actual)
 		})
@@ -79,7 +79,7 @@ func TestInterpolateGoTemplate(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			_, err := interpolateGoTemplate(tc.template, map[string]any{})
+			_, err := interpolateGoTemplate(tc.template, map[string]any{}, nil)
 			require.Error(t, err)
 			assert.EqualError(t, err, tc.errValue)
 		})
@@ -95,7 +95,7 @@ func TestCheckValidTemplate(t *testing.T) {
 		err := CheckValidTemplate("Hello, {test}", "unknown", []string{"test"})
 		require.Error(t, err)
 		assert.ErrorIs(t, err, ErrInvalidTemplateFormat)
-		assert.EqualError(t, err, "invalid template format, got: unknown, should be one of [go-template]")
+		assert.EqualError(t, err, "invalid template format, got: unknown, should be one of [go-template jinja2]")
 	})
 
 	t.Run("TemplateErrored", func(t *testing.T) {
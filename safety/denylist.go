@@ -0,0 +1,57 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+const _defaultRedaction = "[REDACTED]"
+
+// Denylist is a checker matching responses against regular expressions.
+type Denylist struct {
+	action    Action
+	patterns  []*regexp.Regexp
+	redaction string
+}
+
+var _ Checker = &Denylist{}
+
+// NewDenylist creates a denylist applying the action to responses matching
+// any of the patterns. With Redact the matches are replaced with
+// "[REDACTED]", other matches of the observations are not redacted but still
+// trigger the other actions.
+func NewDenylist(action Action, patterns ...string) (*Denylist, error) {
+	d := &Denylist{action: action, redaction: _defaultRedaction}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		d.patterns = append(d.patterns, re)
+	}
+	return d, nil
+}
+
+// Check checks the text and observations of the response.
+func (d *Denylist) Check(_ context.Context, in Input) (Verdict, error) {
+	if d.action == Redact {
+		text := in.Text
+		for _, re := range d.patterns {
+			text = re.ReplaceAllLiteralString(text, d.redaction)
+		}
+		if text != in.Text {
+			return Verdict{Action: Redact, Text: text, Reason: "denylisted content redacted"}, nil
+		}
+		return Verdict{Action: Allow}, nil
+	}
+
+	for _, s := range append([]string{in.Text}, in.Observations...) {
+		for _, re := range d.patterns {
+			if re.MatchString(s) {
+				return Verdict{Action: d.action, Text: in.Text, Reason: fmt.Sprintf("denylisted content matching %q", re)}, nil
+			}
+		}
+	}
+	return Verdict{Action: Allow}, nil
+}
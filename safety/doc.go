@@ -0,0 +1,18 @@
+/*
+Package safety checks the responses of chains and agents after they are
+generated, to block, redact or annotate unsafe content.
+
+A Checker is given the final text of a response and the observations of the
+tools called to produce it, and returns a Verdict. Denylist matches regular
+expressions and Moderation uses the OpenAI moderation model:
+
+	moderation := safety.NewModeration(llm, safety.Block)
+	secrets, err := safety.NewDenylist(safety.Redact, `sk-[A-Za-z0-9]{20,}`)
+
+	executor := agents.NewExecutor(agent, tools, agents.WithSafetyCheckers(moderation, secrets))
+	chain := chains.NewSafeChain(llmChain, moderation, secrets)
+
+Blocked responses return ErrBlocked, and the annotations are returned under
+the "safety_annotations" output key.
+*/
+package safety
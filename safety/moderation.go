@@ -0,0 +1,69 @@
+package safety
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+const _defaultModerationRedaction = "[content removed by moderation]"
+
+// Moderator classifies texts, for example openai.LLM and openai.Chat.
+type Moderator interface {
+	Moderate(ctx context.Context, inputTexts []string) ([]openai.ModerationResult, error)
+}
+
+// Moderation is a checker classifying responses with the OpenAI moderation
+// model.
+type Moderation struct {
+	moderator Moderator
+	action    Action
+}
+
+var _ Checker = Moderation{}
+
+// NewModeration creates a checker applying the action to the responses whose
+// text or observations are flagged by the moderator. With Redact the whole
+// text is replaced.
+func NewModeration(moderator Moderator, action Action) Moderation {
+	return Moderation{moderator: moderator, action: action}
+}
+
+// Check classifies the text and observations of the response.
+func (m Moderation) Check(ctx context.Context, in Input) (Verdict, error) {
+	results, err := m.moderator.Moderate(ctx, append([]string{in.Text}, in.Observations...))
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	var flagged bool
+	flaggedCategories := make(map[string]bool)
+	for _, r := range results {
+		if !r.Flagged {
+			continue
+		}
+		flagged = true
+		for category, ok := range r.Categories {
+			if ok {
+				flaggedCategories[category] = true
+			}
+		}
+	}
+	if !flagged {
+		return Verdict{Action: Allow}, nil
+	}
+	categories := make([]string, 0, len(flaggedCategories))
+	for category := range flaggedCategories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	return Verdict{
+		Action: m.action,
+		Text:   _defaultModerationRedaction,
+		Reason: fmt.Sprintf("flagged by moderation: %s", strings.Join(categories, ", ")),
+	}, nil
+}
@@ -0,0 +1,102 @@
+package safety
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrBlocked is returned when a checker blocks a response.
+var ErrBlocked = errors.New("response blocked by content safety check")
+
+// Action is what a checker does with a response.
+type Action int
+
+const (
+	// Allow lets the response through unchanged.
+	Allow Action = iota
+	// Annotate lets the response through with the reason of the verdict as
+	// an annotation.
+	Annotate
+	// Redact replaces the response with the text of the verdict.
+	Redact
+	// Block rejects the response with ErrBlocked.
+	Block
+)
+
+// String returns the name of the action.
+func (a Action) String() string {
+	switch a {
+	case Allow:
+		return "allow"
+	case Annotate:
+		return "annotate"
+	case Redact:
+		return "redact"
+	case Block:
+		return "block"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// Input is what is checked: the final text of a response and the observations
+// of the tools called to produce it.
+type Input struct {
+	Text         string
+	Observations []string
+}
+
+// Verdict is the decision of a checker on a response.
+type Verdict struct {
+	Action Action
+	// Text is the text replacing the response for Redact.
+	Text string
+	// Reason explains the verdict, it is the annotation for Annotate.
+	Reason string
+}
+
+// Checker checks responses after they are generated.
+type Checker interface {
+	Check(ctx context.Context, in Input) (Verdict, error)
+}
+
+// CheckerFunc is a function usable as a Checker.
+type CheckerFunc func(ctx context.Context, in Input) (Verdict, error)
+
+var _ Checker = CheckerFunc(nil)
+
+// Check calls the function.
+func (f CheckerFunc) Check(ctx context.Context, in Input) (Verdict, error) {
+	return f(ctx, in)
+}
+
+// Result is the outcome of checking a response.
+type Result struct {
+	// Text is the response, redacted if a checker asked to.
+	Text string
+	// Annotations are the reasons of the Annotate verdicts.
+	Annotations []string
+}
+
+// Check runs the checkers in order on the input. Redactions are seen by the
+// next checkers. A Block verdict stops the checks and returns ErrBlocked with
+// its reason.
+func Check(ctx context.Context, in Input, checkers ...Checker) (Result, error) {
+	result := Result{Text: in.Text}
+	for _, c := range checkers {
+		verdict, err := c.Check(ctx, Input{Text: result.Text, Observations: in.Observations})
+		if err != nil {
+			return Result{}, err
+		}
+		switch verdict.Action {
+		case Allow:
+		case Annotate:
+			result.Annotations = append(result.Annotations, verdict.Reason)
+		case Redact:
+			result.Text = verdict.Text
+		case Block:
+			return Result{}, fmt.Errorf("%w: %s", ErrBlocked, verdict.Reason)
+		}
+	}
+	return result, nil
+}
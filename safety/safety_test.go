@@ -0,0 +1,82 @@
+package safety
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+type fakeModerator struct {
+	flagged map[string][]string
+}
+
+func (m fakeModerator) Moderate(_ context.Context, inputTexts []string) ([]openai.ModerationResult, error) {
+	results := make([]openai.ModerationResult, len(inputTexts))
+	for i, text := range inputTexts {
+		categories := map[string]bool{}
+		for _, c := range m.flagged[text] {
+			categories[c] = true
+		}
+		results[i] = openai.ModerationResult{Flagged: len(categories) > 0, Categories: categories}
+	}
+	return results, nil
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	redact, err := NewDenylist(Redact, `\d{3}-\d{4}`)
+	require.NoError(t, err)
+	annotate, err := NewDenylist(Annotate, `(?i)password`)
+	require.NoError(t, err)
+	block, err := NewDenylist(Block, `(?i)secret plan`)
+	require.NoError(t, err)
+
+	result, err := Check(context.Background(), Input{
+		Text:         "call 555-1234",
+		Observations: []string{"the password is hunter2"},
+	}, redact, annotate, block)
+	require.NoError(t, err)
+	require.Equal(t, "call [REDACTED]", result.Text)
+	require.Equal(t, []string{`denylisted content matching "(?i)password"`}, result.Annotations)
+
+	_, err = Check(context.Background(), Input{Text: "the Secret Plan"}, redact, block)
+	require.ErrorIs(t, err, ErrBlocked)
+
+	result, err = Check(context.Background(), Input{Text: "hello"}, redact, annotate, block)
+	require.NoError(t, err)
+	require.Equal(t, Result{Text: "hello"}, result)
+
+	_, err = NewDenylist(Block, `(`)
+	require.Error(t, err)
+}
+
+func TestModeration(t *testing.T) {
+	t.Parallel()
+
+	moderator := fakeModerator{flagged: map[string][]string{
+		"bad answer":      {"violence", "harassment"},
+		"bad observation": {"violence"},
+	}}
+
+	verdict, err := NewModeration(moderator, Block).Check(context.Background(), Input{Text: "fine"})
+	require.NoError(t, err)
+	require.Equal(t, Allow, verdict.Action)
+
+	verdict, err = NewModeration(moderator, Redact).Check(context.Background(), Input{
+		Text:         "bad answer",
+		Observations: []string{"bad observation"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Verdict{
+		Action: Redact,
+		Text:   "[content removed by moderation]",
+		Reason: "flagged by moderation: harassment, violence",
+	}, verdict)
+
+	_, err = Check(context.Background(), Input{Text: "ok", Observations: []string{"bad observation"}},
+		NewModeration(moderator, Block))
+	require.ErrorIs(t, err, ErrBlocked)
+}
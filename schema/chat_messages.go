@@ -24,6 +24,8 @@ const (
 	ChatMessageTypeGeneric ChatMessageType = "generic"
 	// ChatMessageTypeFunction is a message sent by a function.
 	ChatMessageTypeFunction ChatMessageType = "function"
+	// ChatMessageTypeTool is a message sent by a tool.
+	ChatMessageTypeTool ChatMessageType = "tool"
 )
 
 // ChatMessage represents a message in a chat.
@@ -46,6 +48,7 @@ var (
 	_ ChatMessage = SystemChatMessage{}
 	_ ChatMessage = GenericChatMessage{}
 	_ ChatMessage = FunctionChatMessage{}
+	_ ChatMessage = ToolChatMessage{}
 )
 
 // AIChatMessage is a message sent by an AI.
@@ -55,6 +58,10 @@ type AIChatMessage struct {
 
 	// FunctionCall represents the model choosing to call a function.
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+
+	// ToolCalls are the tools the model chose to call. Their results are given
+	// back with a ToolChatMessage per call.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
 }
 
 func (m AIChatMessage) GetType() ChatMessageType { return ChatMessageTypeAI }
@@ -63,6 +70,10 @@ func (m AIChatMessage) GetContent() string       { return m.Content }
 // HumanChatMessage is a message sent by a human.
 type HumanChatMessage struct {
 	Content string
+
+	// Parts are given after the content to multimodal models, such as images
+	// for vision models. Other models only get the content.
+	Parts []ContentPart `json:"parts,omitempty"`
 }
 
 func (m HumanChatMessage) GetType() ChatMessageType { return ChatMessageTypeHuman }
@@ -103,6 +114,28 @@ func (m FunctionChatMessage) GetType() ChatMessageType { return ChatMessageTypeF
 func (m FunctionChatMessage) GetContent() string       { return m.Content }
 func (m FunctionChatMessage) GetName() string          { return m.Name }
 
+// ToolChatMessage is a chat message representing the result of a tool call.
+type ToolChatMessage struct {
+	// ID is the id of the tool call of the AIChatMessage this is the result of.
+	ID      string `json:"tool_call_id"`
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content"`
+}
+
+func (m ToolChatMessage) GetType() ChatMessageType { return ChatMessageTypeTool }
+func (m ToolChatMessage) GetContent() string       { return m.Content }
+func (m ToolChatMessage) GetName() string          { return m.Name }
+
+// ToolCall is a call to a tool chosen by the model.
+type ToolCall struct {
+	// ID identifies the call, the ToolChatMessage with its result has the
+	// same id.
+	ID string `json:"id"`
+	// Type is the type of the tool, "function" for function tools.
+	Type         string        `json:"type"`
+	FunctionCall *FunctionCall `json:"function,omitempty"`
+}
+
 // ChatGeneration is the output of a single chat generation.
 type ChatGeneration struct {
 	Generation
@@ -124,6 +157,9 @@ func GetBufferString(messages []ChatMessage, humanPrefix string, aiPrefix string
 			return "", err
 		}
 		msg := fmt.Sprintf("%s: %s", role, m.GetContent())
+		if m, ok := m.(HumanChatMessage); ok {
+			msg += partsBufferString(m.Parts)
+		}
 		if m, ok := m.(AIChatMessage); ok && m.FunctionCall != nil {
 			j, err := json.Marshal(m.FunctionCall)
 			if err != nil {
@@ -131,11 +167,33 @@ func GetBufferString(messages []ChatMessage, humanPrefix string, aiPrefix string
 			}
 			msg = fmt.Sprintf("%s %s", msg, string(j))
 		}
+		if m, ok := m.(AIChatMessage); ok && len(m.ToolCalls) > 0 {
+			j, err := json.Marshal(m.ToolCalls)
+			if err != nil {
+				return "", err
+			}
+			msg = fmt.Sprintf("%s %s", msg, string(j))
+		}
 		result = append(result, msg)
 	}
 	return strings.Join(result, "\n"), nil
 }
 
+// partsBufferString returns the text of the parts, images being shown as
+// [image].
+func partsBufferString(parts []ContentPart) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		sb.WriteString(" ")
+		if p.Type == ContentPartTypeText {
+			sb.WriteString(p.Text)
+			continue
+		}
+		sb.WriteString("[image]")
+	}
+	return sb.String()
+}
+
 func getMessageRole(m ChatMessage, humanPrefix, aiPrefix string) (string, error) {
 	var role string
 	switch m.GetType() {
@@ -153,8 +211,51 @@ func getMessageRole(m ChatMessage, humanPrefix, aiPrefix string) (string, error)
 		role = cgm.Role
 	case ChatMessageTypeFunction:
 		role = "Function"
+	case ChatMessageTypeTool:
+		role = "Tool"
 	default:
 		return "", ErrUnexpectedChatMessageType
 	}
 	return role, nil
 }
+
+// UnmarshalChatMessage decodes the JSON encoding of a chat message of the type,
+// such as stored by the persistent chat message histories.
+func UnmarshalChatMessage(typ ChatMessageType, data []byte) (ChatMessage, error) { //nolint:ireturn
+	var (
+		message ChatMessage
+		err     error
+	)
+	switch typ {
+	case ChatMessageTypeAI:
+		var m AIChatMessage
+		err = json.Unmarshal(data, &m)
+		message = m
+	case ChatMessageTypeHuman:
+		var m HumanChatMessage
+		err = json.Unmarshal(data, &m)
+		message = m
+	case ChatMessageTypeSystem:
+		var m SystemChatMessage
+		err = json.Unmarshal(data, &m)
+		message = m
+	case ChatMessageTypeGeneric:
+		var m GenericChatMessage
+		err = json.Unmarshal(data, &m)
+		message = m
+	case ChatMessageTypeFunction:
+		var m FunctionChatMessage
+		err = json.Unmarshal(data, &m)
+		message = m
+	case ChatMessageTypeTool:
+		var m ToolChatMessage
+		err = json.Unmarshal(data, &m)
+		message = m
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedChatMessageType, typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
@@ -37,6 +37,34 @@ func TestGetBufferString(t *testing.T) {
 			expected:    "Human: Hello, how are you?\nAI: I'm doing great!\nSystem: Please be polite.\nModerator: Keep the conversation on topic.", //nolint:lll
 			expectError: false,
 		},
+		{
+			name: "Tool calls",
+			messages: []schema.ChatMessage{
+				schema.AIChatMessage{ToolCalls: []schema.ToolCall{{
+					ID:           "call_1",
+					Type:         "function",
+					FunctionCall: &schema.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+				}}},
+				schema.ToolChatMessage{ID: "call_1", Name: "weather", Content: "sunny"},
+			},
+			humanPrefix: "Human",
+			aiPrefix:    "AI",
+			expected:    "AI:  [{\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"weather\",\"arguments\":\"{\\\"city\\\":\\\"Paris\\\"}\"}}]\nTool: sunny", //nolint:lll
+			expectError: false,
+		},
+		{
+			name: "Content parts",
+			messages: []schema.ChatMessage{
+				schema.HumanChatMessage{Content: "What is this?", Parts: []schema.ContentPart{
+					schema.ImageURLPart("https://example.com/otter.png"),
+					schema.TextPart("Be brief."),
+				}},
+			},
+			humanPrefix: "Human",
+			aiPrefix:    "AI",
+			expected:    "Human: What is this? [image] Be brief.",
+			expectError: false,
+		},
 		{
 			name: "Unsupported message type",
 			messages: []schema.ChatMessage{
@@ -69,3 +97,22 @@ type unsupportedChatMessage struct{}
 
 func (m unsupportedChatMessage) GetType() schema.ChatMessageType { return "unsupported" }
 func (m unsupportedChatMessage) GetContent() string              { return "Unsupported message" }
+
+func TestContentParts(t *testing.T) {
+	t.Parallel()
+
+	png := []byte("\x89PNG\r\n\x1a\n")
+	m := schema.HumanChatMessage{Content: "Caption this.", Parts: []schema.ContentPart{
+		schema.ImageDataPart("", png),
+	}}
+	parts := m.GetContentParts()
+	if len(parts) != 2 || parts[0].Type != schema.ContentPartTypeText || parts[0].Text != "Caption this." {
+		t.Fatalf("unexpected parts: %+v", parts)
+	}
+	if got, want := parts[1].DataURL(), "data:image/png;base64,iVBORw0KGgo="; got != want {
+		t.Errorf("expected: %q, got: %q", want, got)
+	}
+	if got, want := schema.ImageDataPart("image/jpeg", nil).DataURL(), "data:image/jpeg;base64,"; got != want {
+		t.Errorf("expected: %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+// ContentPartType is the type of a part of the content of a message.
+type ContentPartType string
+
+const (
+	// ContentPartTypeText is a part holding text.
+	ContentPartTypeText ContentPartType = "text"
+	// ContentPartTypeImageURL is a part holding the url of an image, which
+	// may be a data url.
+	ContentPartTypeImageURL ContentPartType = "image_url"
+	// ContentPartTypeImageData is a part holding the bytes of an image.
+	ContentPartTypeImageData ContentPartType = "image_data"
+)
+
+// ImageDetail is the resolution at which vision models look at an image.
+type ImageDetail string
+
+const (
+	ImageDetailAuto ImageDetail = "auto"
+	ImageDetailLow  ImageDetail = "low"
+	ImageDetailHigh ImageDetail = "high"
+)
+
+// ContentPart is a part of the content of a message sent to multimodal
+// models, such as an image given to a vision model.
+type ContentPart struct {
+	Type ContentPartType `json:"type"`
+	// Text is the text of text parts.
+	Text string `json:"text,omitempty"`
+	// URL is the url of image url parts.
+	URL string `json:"url,omitempty"`
+	// Data and MIMEType are the bytes and media type, such as "image/png", of
+	// image data parts. The media type is detected from the bytes if empty.
+	Data     []byte `json:"data,omitempty"`
+	MIMEType string `json:"mime_type,omitempty"`
+	// Detail is the resolution at which vision models look at images.
+	// Providers choose if empty.
+	Detail ImageDetail `json:"detail,omitempty"`
+}
+
+// TextPart returns a part holding the text.
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: ContentPartTypeText, Text: text}
+}
+
+// ImageURLPart returns a part holding the url of an image.
+func ImageURLPart(url string) ContentPart {
+	return ContentPart{Type: ContentPartTypeImageURL, URL: url}
+}
+
+// ImageDataPart returns a part holding the bytes of an image of the media
+// type, which is detected from the bytes if empty.
+func ImageDataPart(mimeType string, data []byte) ContentPart {
+	return ContentPart{Type: ContentPartTypeImageData, MIMEType: mimeType, Data: data}
+}
+
+// DataURL returns the data url of an image data part, with the base64
+// encoded bytes, such as "data:image/png;base64,iVBORw0KGgo...".
+func (p ContentPart) DataURL() string {
+	mimeType := p.MIMEType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(p.Data)
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(p.Data)
+}
+
+// MultiPartMessage is implemented by chat messages whose content may have
+// parts other than their text, such as images.
+type MultiPartMessage interface {
+	ChatMessage
+	// GetContentParts returns the parts of the content: the text of the
+	// message, if any, followed by its other parts.
+	GetContentParts() []ContentPart
+}
+
+var _ MultiPartMessage = HumanChatMessage{}
+
+// GetContentParts returns the content of the message followed by its parts.
+func (m HumanChatMessage) GetContentParts() []ContentPart {
+	parts := make([]ContentPart, 0, len(m.Parts)+1)
+	if m.Content != "" {
+		parts = append(parts, TextPart(m.Content))
+	}
+	return append(parts, m.Parts...)
+}
@@ -6,5 +6,6 @@
 // access to the underlying API in a way that expects chat messages. These
 // messages have a content field (which is usually text) and are associated
 // with a user (or role). Right now the supported users are System, Human, AI,
-// and a generic/arbitrary user.
+// and a generic/arbitrary user. Human messages can also hold content parts,
+// such as images given to vision models.
 package schema
@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrMissingSubjectID is returned by purgers when they are called with an
+	// empty subject id.
+	ErrMissingSubjectID = errors.New("missing subject id")
+	// ErrPurgeNotSupported is returned when purging is requested from a store
+	// wrapping another store that can not be purged.
+	ErrPurgeNotSupported = errors.New("purge not supported")
+)
+
+// Purger is the interface for stores that can permanently delete all the data
+// they hold about a data subject, such as a user or a session. Purgers are used
+// to satisfy right-to-be-forgotten requests.
+type Purger interface {
+	// Purge deletes all data belonging to the subject with the given id.
+	Purge(ctx context.Context, subjectID string) error
+}
+
+// PurgeAll calls Purge with the subject id on all the purgers. Every purger is
+// called even if some of them fail, and the errors are joined together.
+func PurgeAll(ctx context.Context, subjectID string, purgers ...Purger) error {
+	if subjectID == "" {
+		return ErrMissingSubjectID
+	}
+
+	errs := make([]error, 0)
+	for _, p := range purgers {
+		if err := p.Purge(ctx, subjectID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
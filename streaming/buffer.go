@@ -0,0 +1,137 @@
+package streaming
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+// Chunking tells how a Buffer coalesces the chunks streamed by an llm.
+type Chunking int
+
+const (
+	// ChunkNone passes the chunks through unchanged.
+	ChunkNone Chunking = iota
+	// ChunkWords emits whole words, cutting after the last whitespace.
+	ChunkWords
+	// ChunkSentences emits whole sentences, cutting after the last sentence
+	// terminator followed by a whitespace, or the last newline.
+	ChunkSentences
+	// ChunkLines emits whole lines, cutting after the last newline. It suits
+	// log-style outputs.
+	ChunkLines
+)
+
+// Buffer coalesces the tiny chunks streamed by llms into word, sentence or line
+// sized chunks before passing them to a streaming function, to reduce render
+// thrash in frontends. It is safe for concurrent use.
+type Buffer struct {
+	fn       func(ctx context.Context, chunk []byte) error
+	chunking Chunking
+	maxDelay time.Duration
+
+	mu    sync.Mutex
+	buf   []byte
+	ctx   context.Context //nolint:containedctx
+	timer *time.Timer
+	// gen identifies the current timer, so that an expired timer racing with
+	// a write does not flush for its successor.
+	gen int
+	err error
+}
+
+// NewBuffer creates a new Buffer passing the coalesced chunks to fn. With a
+// positive max delay, text buffered for longer than the delay is passed even
+// if incomplete.
+func NewBuffer(fn func(ctx context.Context, chunk []byte) error, chunking Chunking, maxDelay time.Duration) *Buffer {
+	return &Buffer{fn: fn, chunking: chunking, maxDelay: maxDelay}
+}
+
+// Write buffers the chunk and passes the complete words, sentences or lines
+// to the streaming function. Its signature is the one of the StreamingFunc
+// call options. It returns the first error of the streaming function.
+func (b *Buffer) Write(ctx context.Context, chunk []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return b.err
+	}
+	if b.chunking == ChunkNone {
+		b.err = b.fn(ctx, chunk)
+		return b.err
+	}
+
+	b.ctx = ctx
+	b.buf = append(b.buf, chunk...)
+	if n := b.cut(); n > 0 {
+		b.emit(n)
+	}
+	if len(b.buf) > 0 && b.timer == nil && b.maxDelay > 0 {
+		b.gen++
+		gen := b.gen
+		b.timer = time.AfterFunc(b.maxDelay, func() { b.expire(gen) })
+	}
+	return b.err
+}
+
+// Flush passes the buffered text to the streaming function. It must be called
+// at the end of the stream.
+func (b *Buffer) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == nil && len(b.buf) > 0 {
+		b.ctx = ctx
+		b.emit(len(b.buf))
+	}
+	return b.err
+}
+
+func (b *Buffer) expire(gen int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.gen {
+		return
+	}
+	b.timer = nil
+	if b.err == nil && len(b.buf) > 0 {
+		b.emit(len(b.buf))
+	}
+}
+
+// emit passes the first n buffered bytes to the streaming function.
+func (b *Buffer) emit(n int) {
+	if b.timer != nil && n == len(b.buf) {
+		b.timer.Stop()
+		b.timer = nil
+		b.gen++
+	}
+	chunk := make([]byte, n)
+	copy(chunk, b.buf)
+	b.buf = append(b.buf[:0], b.buf[n:]...)
+	b.err = b.fn(b.ctx, chunk)
+}
+
+// cut returns the length of the complete part of the buffer.
+func (b *Buffer) cut() int {
+	switch b.chunking {
+	case ChunkWords:
+		return bytes.LastIndexAny(b.buf, " \t\n\r") + 1
+	case ChunkSentences:
+		n := bytes.LastIndexByte(b.buf, '\n') + 1
+		for i := len(b.buf) - 1; i > n; i-- {
+			if isSpace(b.buf[i]) && bytes.IndexByte([]byte(".!?"), b.buf[i-1]) >= 0 {
+				return i + 1
+			}
+		}
+		return n
+	case ChunkLines:
+		return bytes.LastIndexByte(b.buf, '\n') + 1
+	case ChunkNone:
+	}
+	return len(b.buf)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
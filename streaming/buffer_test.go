@@ -0,0 +1,72 @@
+package streaming_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/streaming"
+)
+
+func TestBuffer(t *testing.T) {
+	t.Parallel()
+
+	deltas := []string{"He", "llo wo", "rld. How", " are", " you?\nFi", "ne"}
+	tests := []struct {
+		chunking streaming.Chunking
+		want     []string
+	}{
+		{streaming.ChunkNone, deltas},
+		{streaming.ChunkWords, []string{"Hello ", "world. ", "How ", "are you?\n", "Fine"}},
+		{streaming.ChunkSentences, []string{"Hello world. ", "How are you?\n", "Fine"}},
+		{streaming.ChunkLines, []string{"Hello world. How are you?\n", "Fine"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		b := streaming.NewBuffer(func(_ context.Context, chunk []byte) error {
+			got = append(got, string(chunk))
+			return nil
+		}, tc.chunking, 0)
+		for _, delta := range deltas {
+			require.NoError(t, b.Write(context.Background(), []byte(delta)))
+		}
+		require.NoError(t, b.Flush(context.Background()))
+		assert.Equal(t, tc.want, got, tc.chunking)
+	}
+}
+
+func TestBufferMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	chunks := make(chan string, 1)
+	b := streaming.NewBuffer(func(_ context.Context, chunk []byte) error {
+		chunks <- string(chunk)
+		return nil
+	}, streaming.ChunkLines, 10*time.Millisecond)
+	require.NoError(t, b.Write(context.Background(), []byte("loading")))
+	assert.Equal(t, "loading", <-chunks)
+}
+
+func TestHandlerWithChunking(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h := streaming.NewHandler(streaming.NewNDJSONEncoder(&buf), streaming.WithChunking(streaming.ChunkWords, 0))
+	ctx := context.Background()
+	h.HandleStreamingFunc(ctx, []byte("Thin"))
+	h.HandleStreamingFunc(ctx, []byte("king abo"))
+	h.HandleToolStart(ctx, "search", "ut")
+	h.HandleStreamingFunc(ctx, []byte("Do"))
+	require.NoError(t, h.Done(nil))
+
+	assert.Equal(t, []streaming.Event{
+		{Type: streaming.EventToken, Token: "Thinking "},
+		{Type: streaming.EventToken, Seq: 1, Token: "abo"},
+		{Type: streaming.EventToolStart, Seq: 2, Tool: "search", Input: "ut"},
+		{Type: streaming.EventToken, Seq: 3, Token: "Do"},
+		{Type: streaming.EventDone, Seq: 4},
+	}, decodeNDJSON(t, buf.Bytes()))
+}
@@ -0,0 +1,39 @@
+/*
+Package streaming encodes the events of a run as Server-Sent Events or
+newline delimited JSON, so web frontends can follow the tokens, tool calls and
+steps of chains and agents.
+
+A Handler is a callbacks.Handler writing events with an Encoder:
+
+	enc := streaming.NewSSEEncoder(w)
+	h := streaming.NewHandler(enc)
+	executor := agents.NewExecutor(agent, tools, agents.WithCallbacksHandler(h))
+	_, err := chains.Run(ctx, executor, input)
+	h.Done(err)
+
+Each event is a JSON object with the fields of Event. The "type" field is one
+of the EventType values and tells which of the other fields are set:
+
+	token         "token": a chunk of text streamed by the llm.
+	tool_start    "tool", "input": a tool is called.
+	tool_end      "tool", "output": a tool returned.
+	tool_error    "tool", "error": a tool failed.
+	agent_action  "tool", "input", "log": the agent decided to call a tool.
+	agent_finish  "outputs", "log": the agent gave its final answer.
+	error         "error": the run failed. It is the last event.
+	interrupted   the run was canceled. It is the last event.
+	done          the run succeeded. It is the last event.
+
+Every event also holds "seq", its index in the stream, and "run_id" when set
+with WithRunID. With SSE the type is also sent as the event name and the
+sequence number as the event id.
+
+Llms often stream a few characters at a time. WithChunking makes the Handler
+coalesce them into words, sentences or lines with a Buffer, which can also
+wrap any streaming function:
+
+	b := streaming.NewBuffer(render, streaming.ChunkWords, 100*time.Millisecond)
+	_, err := llm.Call(ctx, prompt, llms.WithStreamingFunc(b.Write))
+	b.Flush(ctx)
+*/
+package streaming
@@ -0,0 +1,87 @@
+package streaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	// SSEContentType is the content type of responses made of SSE frames.
+	SSEContentType = "text/event-stream"
+	// NDJSONContentType is the content type of responses made of NDJSON frames.
+	NDJSONContentType = "application/x-ndjson"
+)
+
+// Encoder writes events to a stream.
+type Encoder interface {
+	Encode(e Event) error
+}
+
+// SSEEncoder writes events as Server-Sent Events.
+type SSEEncoder struct {
+	w io.Writer
+}
+
+var _ Encoder = SSEEncoder{}
+
+// NewSSEEncoder creates a new SSEEncoder writing to w. If w is an
+// http.ResponseWriter, the content type and caching headers are set and each
+// event is flushed as soon as it is written.
+func NewSSEEncoder(w io.Writer) SSEEncoder {
+	if rw, ok := w.(http.ResponseWriter); ok {
+		rw.Header().Set("Content-Type", SSEContentType)
+		rw.Header().Set("Cache-Control", "no-cache")
+	}
+	return SSEEncoder{w: w}
+}
+
+// Encode writes the event as an SSE frame.
+func (e SSEEncoder) Encode(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(e.w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data); err != nil {
+		return err
+	}
+	flush(e.w)
+	return nil
+}
+
+// NDJSONEncoder writes events as newline delimited JSON.
+type NDJSONEncoder struct {
+	w io.Writer
+}
+
+var _ Encoder = NDJSONEncoder{}
+
+// NewNDJSONEncoder creates a new NDJSONEncoder writing to w. If w is an
+// http.ResponseWriter, the content type is set and each event is flushed as
+// soon as it is written.
+func NewNDJSONEncoder(w io.Writer) NDJSONEncoder {
+	if rw, ok := w.(http.ResponseWriter); ok {
+		rw.Header().Set("Content-Type", NDJSONContentType)
+	}
+	return NDJSONEncoder{w: w}
+}
+
+// Encode writes the event as a line of JSON.
+func (e NDJSONEncoder) Encode(event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	flush(e.w)
+	return nil
+}
+
+func flush(w io.Writer) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,31 @@
+package streaming
+
+// EventType is the type of an event, see the package documentation for the
+// fields set by each type.
+type EventType string
+
+const (
+	EventToken       EventType = "token"
+	EventToolStart   EventType = "tool_start"
+	EventToolEnd     EventType = "tool_end"
+	EventToolError   EventType = "tool_error"
+	EventAgentAction EventType = "agent_action"
+	EventAgentFinish EventType = "agent_finish"
+	EventError       EventType = "error"
+	EventInterrupted EventType = "interrupted"
+	EventDone        EventType = "done"
+)
+
+// Event is an event of a run.
+type Event struct {
+	Type    EventType      `json:"type"`
+	Seq     int            `json:"seq"`
+	RunID   string         `json:"run_id,omitempty"`
+	Token   string         `json:"token,omitempty"`
+	Tool    string         `json:"tool,omitempty"`
+	Input   string         `json:"input,omitempty"`
+	Output  string         `json:"output,omitempty"`
+	Outputs map[string]any `json:"outputs,omitempty"`
+	Log     string         `json:"log,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
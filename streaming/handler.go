@@ -0,0 +1,130 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Handler is a callbacks.Handler writing the events of a run with an Encoder.
+// It is safe for concurrent use.
+type Handler struct {
+	callbacks.SimpleHandler
+
+	enc    Encoder
+	runID  string
+	tokens *Buffer
+
+	mu  sync.Mutex
+	seq int
+	err error
+}
+
+var _ callbacks.Handler = (*Handler)(nil)
+
+// HandlerOption is a function type that can be used to modify a Handler.
+type HandlerOption func(h *Handler)
+
+// WithRunID is an option for setting the run id of all the events.
+func WithRunID(runID string) HandlerOption {
+	return func(h *Handler) {
+		h.runID = runID
+	}
+}
+
+// WithChunking is an option for coalescing the streamed chunks of text into
+// words, sentences or lines before writing token events, see Buffer.
+func WithChunking(chunking Chunking, maxDelay time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.tokens = NewBuffer(h.emitToken, chunking, maxDelay)
+	}
+}
+
+// NewHandler creates a new Handler writing events with the encoder.
+func NewHandler(enc Encoder, opts ...HandlerOption) *Handler {
+	h := &Handler{enc: enc}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Err returns the first error returned by the encoder. Events are dropped once
+// the encoder failed, for example because the client went away.
+func (h *Handler) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// Done writes the buffered text and the last event of the run: an interrupted
+// event if err is a context.Canceled error, an error event for other errors
+// and a done event otherwise. It returns the first error of the encoder.
+func (h *Handler) Done(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		h.send(Event{Type: EventInterrupted})
+	case err != nil:
+		h.send(Event{Type: EventError, Error: err.Error()})
+	default:
+		h.send(Event{Type: EventDone})
+	}
+	return h.Err()
+}
+
+func (h *Handler) HandleStreamingFunc(ctx context.Context, chunk []byte) {
+	if h.tokens != nil {
+		_ = h.tokens.Write(ctx, chunk)
+		return
+	}
+	_ = h.emitToken(ctx, chunk)
+}
+
+func (h *Handler) HandleToolStart(_ context.Context, tool, input string) {
+	h.send(Event{Type: EventToolStart, Tool: tool, Input: input})
+}
+
+func (h *Handler) HandleToolEnd(_ context.Context, tool, output string) {
+	h.send(Event{Type: EventToolEnd, Tool: tool, Output: output})
+}
+
+func (h *Handler) HandleToolError(_ context.Context, tool string, err error) {
+	h.send(Event{Type: EventToolError, Tool: tool, Error: err.Error()})
+}
+
+func (h *Handler) HandleAgentAction(_ context.Context, action schema.AgentAction) {
+	h.send(Event{Type: EventAgentAction, Tool: action.Tool, Input: action.ToolInput, Log: action.Log})
+}
+
+func (h *Handler) HandleAgentFinish(_ context.Context, finish schema.AgentFinish) {
+	h.send(Event{Type: EventAgentFinish, Outputs: finish.ReturnValues, Log: finish.Log})
+}
+
+// send writes the buffered text, then the event, keeping the events in order.
+func (h *Handler) send(e Event) {
+	if h.tokens != nil {
+		_ = h.tokens.Flush(context.Background())
+	}
+	h.emit(e)
+}
+
+func (h *Handler) emitToken(_ context.Context, chunk []byte) error {
+	h.emit(Event{Type: EventToken, Token: string(chunk)})
+	return h.Err()
+}
+
+func (h *Handler) emit(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err != nil {
+		return
+	}
+	e.Seq = h.seq
+	e.RunID = h.runID
+	h.seq++
+	h.err = h.enc.Encode(e)
+}
@@ -0,0 +1,129 @@
+package streaming_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/streaming"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// scriptedLLM streams its responses word by word, one response per call.
+type scriptedLLM struct {
+	responses []string
+}
+
+var _ llms.LanguageModel = (*scriptedLLM)(nil)
+
+func (l *scriptedLLM) GeneratePrompt(ctx context.Context, _ []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	response := l.responses[0]
+	l.responses = l.responses[1:]
+	if opts.StreamingFunc != nil {
+		for _, word := range strings.SplitAfter(response, " ") {
+			if err := opts.StreamingFunc(ctx, []byte(word)); err != nil {
+				return llms.LLMResult{}, err
+			}
+		}
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: response}}}}, nil
+}
+
+func (l *scriptedLLM) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func decodeNDJSON(t *testing.T, data []byte) []streaming.Event {
+	t.Helper()
+
+	var events []streaming.Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var e streaming.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestHandlerAgentRun(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{responses: []string{
+		"Action: calculator\nAction Input: 1+1",
+		"Final Answer: 2",
+	}}
+	var buf bytes.Buffer
+	h := streaming.NewHandler(streaming.NewNDJSONEncoder(&buf), streaming.WithRunID("run-1"))
+	executor, err := agents.Initialize(llm, []tools.Tool{tools.Calculator{}},
+		agents.ZeroShotReactDescription, agents.WithCallbacksHandler(h))
+	require.NoError(t, err)
+
+	_, err = chains.Run(context.Background(), executor, "what is 1+1?")
+	require.NoError(t, h.Done(err))
+
+	events := decodeNDJSON(t, buf.Bytes())
+	types := make([]streaming.EventType, 0, len(events))
+	for i, e := range events {
+		assert.Equal(t, i, e.Seq)
+		assert.Equal(t, "run-1", e.RunID)
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []streaming.EventType{
+		streaming.EventToken, streaming.EventToken, streaming.EventToken, streaming.EventToken,
+		streaming.EventAgentAction,
+		streaming.EventToolStart,
+		streaming.EventToolEnd,
+		streaming.EventToken, streaming.EventToken, streaming.EventToken,
+		streaming.EventAgentFinish,
+		streaming.EventDone,
+	}, types)
+	assert.Equal(t, "calculator", events[5].Tool)
+	assert.Equal(t, "1+1", events[5].Input)
+	assert.Equal(t, "2", events[6].Output)
+	assert.Equal(t, map[string]any{"output": " 2"}, events[10].Outputs)
+}
+
+func TestSSEEncoder(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	h := streaming.NewHandler(streaming.NewSSEEncoder(rec))
+	h.HandleStreamingFunc(context.Background(), []byte("Hi"))
+	require.NoError(t, h.Done(errors.New("boom")))
+
+	assert.Equal(t, streaming.SSEContentType, rec.Header().Get("Content-Type"))
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, "id: 0\nevent: token\ndata: {\"type\":\"token\",\"seq\":0,\"token\":\"Hi\"}\n\n"+
+		"id: 1\nevent: error\ndata: {\"type\":\"error\",\"seq\":1,\"error\":\"boom\"}\n\n", rec.Body.String())
+}
+
+type failingWriter struct{}
+
+var errWrite = errors.New("client gone")
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestHandlerStopsAfterEncoderError(t *testing.T) {
+	t.Parallel()
+
+	h := streaming.NewHandler(streaming.NewNDJSONEncoder(failingWriter{}))
+	h.HandleToolStart(context.Background(), "calculator", "1+1")
+	require.ErrorIs(t, h.Err(), errWrite)
+	require.ErrorIs(t, h.Done(nil), errWrite)
+}
@@ -0,0 +1,17 @@
+/*
+Package websocket serves chains and agents over WebSocket connections, for
+chat interfaces that need to stream answers and stop them.
+
+Each connection is a session with its own chain, so memory is kept per
+connection. The client sends ClientMessage values as JSON:
+
+	{"type": "message", "content": "What is the weather in Paris?"}
+	{"type": "interrupt"}
+
+A message starts a run of the chain with the content as input, interrupting
+the run in flight if any. An interrupt stops the run in flight. The server
+sends the events of the runs as JSON streaming.Event values, the last event
+of each run being a done, error or interrupted event. The run id of the
+events is the number of the message in the session, starting at 1.
+*/
+package websocket
@@ -0,0 +1,191 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/streaming"
+	xwebsocket "golang.org/x/net/websocket"
+)
+
+// ErrUnknownMessageType is reported to the client when it sends a message of
+// an unknown type.
+var ErrUnknownMessageType = errors.New("unknown message type")
+
+// Types of the messages sent by the client.
+const (
+	MessageTypeMessage   = "message"
+	MessageTypeInterrupt = "interrupt"
+)
+
+// ClientMessage is a message sent by the client.
+type ClientMessage struct {
+	// Type is MessageTypeMessage or MessageTypeInterrupt.
+	Type string `json:"type"`
+	// Content is the input of the chain for messages.
+	Content string `json:"content,omitempty"`
+}
+
+// SessionFactory creates the chain of a new session. The chain must report to
+// the handler to stream its tokens and steps, for example by setting the
+// CallbacksHandler of an LLMChain or with agents.WithCallbacksHandler. The
+// chain must take a single input, see chains.Run.
+type SessionFactory func(handler callbacks.Handler) (chains.Chain, error)
+
+// Handler is an http.Handler upgrading requests to WebSocket connections and
+// serving a session on each of them.
+type Handler struct {
+	newSession SessionFactory
+}
+
+var _ http.Handler = Handler{}
+
+// NewHandler creates a new Handler creating the chain of each session with
+// newSession.
+func NewHandler(newSession SessionFactory) Handler {
+	return Handler{newSession: newSession}
+}
+
+// ServeHTTP upgrades the request and serves the session until the client
+// closes the connection.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	xwebsocket.Handler(func(conn *xwebsocket.Conn) {
+		enc := &connEncoder{conn: conn}
+		chain, err := h.newSession(sessionHandler{})
+		if err != nil {
+			_ = streaming.NewHandler(enc).Done(err)
+			return
+		}
+		s := &session{chain: chain, enc: enc}
+		s.serve(r.Context(), conn)
+	}).ServeHTTP(w, r)
+}
+
+// connEncoder sends events as JSON messages. Events of a run and errors of the
+// session can be sent concurrently, so sends are serialized.
+type connEncoder struct {
+	mu   sync.Mutex
+	conn *xwebsocket.Conn
+}
+
+func (e *connEncoder) Encode(event streaming.Event) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return xwebsocket.JSON.Send(e.conn, event)
+}
+
+// session runs the chain for the messages of a connection, one at a time.
+type session struct {
+	chain chains.Chain
+	enc   *connEncoder
+	runs  int
+
+	// cancel and done belong to the run in flight, if any.
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (s *session) serve(ctx context.Context, conn *xwebsocket.Conn) {
+	defer s.interrupt()
+	for {
+		var msg ClientMessage
+		if err := xwebsocket.JSON.Receive(conn, &msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case MessageTypeMessage:
+			s.interrupt()
+			s.start(ctx, msg.Content)
+		case MessageTypeInterrupt:
+			s.interrupt()
+		default:
+			err := fmt.Errorf("%w: %q", ErrUnknownMessageType, msg.Type)
+			if streaming.NewHandler(s.enc).Done(err) != nil {
+				return
+			}
+		}
+	}
+}
+
+// start runs the chain in the background.
+func (s *session) start(ctx context.Context, input string) {
+	s.runs++
+	h := streaming.NewHandler(s.enc, streaming.WithRunID(strconv.Itoa(s.runs)))
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, runHandlerKey{}, h))
+	done := make(chan struct{})
+	s.cancel, s.done = cancel, done
+
+	go func() {
+		defer close(done)
+		defer cancel()
+		// The streaming function enables streaming, the chunks are given to
+		// the callbacks handler of the chain.
+		_, err := chains.Run(ctx, s.chain, input, chains.WithStreamingFunc(func(context.Context, []byte) error {
+			return ctx.Err()
+		}))
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		_ = h.Done(err)
+	}()
+}
+
+// interrupt cancels the run in flight and waits for it to end.
+func (s *session) interrupt() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.cancel, s.done = nil, nil
+}
+
+type runHandlerKey struct{}
+
+// sessionHandler is the callbacks handler given to the chain of a session. It
+// forwards the events to the streaming handler of the run they belong to,
+// found in their context.
+type sessionHandler struct {
+	callbacks.SimpleHandler
+}
+
+var _ callbacks.Handler = sessionHandler{}
+
+func runHandler(ctx context.Context) callbacks.Handler {
+	if h, ok := ctx.Value(runHandlerKey{}).(*streaming.Handler); ok && ctx.Err() == nil {
+		return h
+	}
+	return callbacks.SimpleHandler{}
+}
+
+func (sessionHandler) HandleStreamingFunc(ctx context.Context, chunk []byte) {
+	runHandler(ctx).HandleStreamingFunc(ctx, chunk)
+}
+
+func (sessionHandler) HandleToolStart(ctx context.Context, tool, input string) {
+	runHandler(ctx).HandleToolStart(ctx, tool, input)
+}
+
+func (sessionHandler) HandleToolEnd(ctx context.Context, tool, output string) {
+	runHandler(ctx).HandleToolEnd(ctx, tool, output)
+}
+
+func (sessionHandler) HandleToolError(ctx context.Context, tool string, err error) {
+	runHandler(ctx).HandleToolError(ctx, tool, err)
+}
+
+func (sessionHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	runHandler(ctx).HandleAgentAction(ctx, action)
+}
+
+func (sessionHandler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	runHandler(ctx).HandleAgentFinish(ctx, finish)
+}
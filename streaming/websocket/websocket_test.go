@@ -0,0 +1,136 @@
+package websocket_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/streaming"
+	"github.com/tmc/langchaingo/streaming/websocket"
+	xwebsocket "golang.org/x/net/websocket"
+)
+
+// echoLLM streams the words of the prompt back. Prompts containing "wait"
+// block until the call is canceled.
+type echoLLM struct{}
+
+var _ llms.LanguageModel = echoLLM{}
+
+func (echoLLM) GeneratePrompt(ctx context.Context, promptValues []schema.PromptValue, options ...llms.CallOption) (llms.LLMResult, error) { //nolint:lll
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	prompt := promptValues[0].String()
+	for _, word := range strings.SplitAfter(prompt, " ") {
+		if err := opts.StreamingFunc(ctx, []byte(word)); err != nil {
+			return llms.LLMResult{}, err
+		}
+	}
+	if strings.Contains(prompt, "wait") {
+		<-ctx.Done()
+		return llms.LLMResult{}, ctx.Err()
+	}
+	return llms.LLMResult{Generations: [][]*llms.Generation{{{Text: prompt}}}}, nil
+}
+
+func (echoLLM) GetNumTokens(text string) int {
+	return len(text)
+}
+
+func dial(t *testing.T) *xwebsocket.Conn {
+	t.Helper()
+
+	srv := httptest.NewServer(websocket.NewHandler(func(h callbacks.Handler) (chains.Chain, error) {
+		chain := chains.NewLLMChain(echoLLM{}, prompts.NewPromptTemplate("{{.input}}", []string{"input"}))
+		chain.CallbacksHandler = h
+		return chain, nil
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, err := xwebsocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func send(t *testing.T, conn *xwebsocket.Conn, msg websocket.ClientMessage) {
+	t.Helper()
+	require.NoError(t, xwebsocket.JSON.Send(conn, msg))
+}
+
+// receiveRun returns the events received until the end of a run.
+func receiveRun(t *testing.T, conn *xwebsocket.Conn) []streaming.Event {
+	t.Helper()
+
+	var events []streaming.Event
+	for {
+		var e streaming.Event
+		require.NoError(t, xwebsocket.JSON.Receive(conn, &e))
+		events = append(events, e)
+		switch e.Type { //nolint:exhaustive
+		case streaming.EventDone, streaming.EventError, streaming.EventInterrupted:
+			return events
+		}
+	}
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	conn := dial(t)
+	send(t, conn, websocket.ClientMessage{Type: websocket.MessageTypeMessage, Content: "hello there"})
+	events := receiveRun(t, conn)
+	require.Len(t, events, 3)
+	assert.Equal(t, streaming.Event{Type: streaming.EventToken, RunID: "1", Token: "hello "}, events[0])
+	assert.Equal(t, streaming.Event{Type: streaming.EventToken, Seq: 1, RunID: "1", Token: "there"}, events[1])
+	assert.Equal(t, streaming.Event{Type: streaming.EventDone, Seq: 2, RunID: "1"}, events[2])
+
+	send(t, conn, websocket.ClientMessage{Type: "shout"})
+	events = receiveRun(t, conn)
+	require.Len(t, events, 1)
+	assert.Equal(t, streaming.EventError, events[0].Type)
+	assert.Contains(t, events[0].Error, "unknown message type")
+}
+
+func TestSessionInterrupt(t *testing.T) {
+	t.Parallel()
+
+	conn := dial(t)
+	send(t, conn, websocket.ClientMessage{Type: websocket.MessageTypeMessage, Content: "wait"})
+	var e streaming.Event
+	require.NoError(t, xwebsocket.JSON.Receive(conn, &e))
+	assert.Equal(t, streaming.EventToken, e.Type)
+
+	send(t, conn, websocket.ClientMessage{Type: websocket.MessageTypeInterrupt})
+	events := receiveRun(t, conn)
+	assert.Equal(t, streaming.Event{Type: streaming.EventInterrupted, Seq: 1, RunID: "1"}, events[len(events)-1])
+}
+
+func TestSessionNewMessageInterrupts(t *testing.T) {
+	t.Parallel()
+
+	conn := dial(t)
+	send(t, conn, websocket.ClientMessage{Type: websocket.MessageTypeMessage, Content: "wait"})
+	var e streaming.Event
+	require.NoError(t, xwebsocket.JSON.Receive(conn, &e))
+
+	send(t, conn, websocket.ClientMessage{Type: websocket.MessageTypeMessage, Content: "hi"})
+	events := receiveRun(t, conn)
+	assert.Equal(t, streaming.EventInterrupted, events[len(events)-1].Type)
+	assert.Equal(t, "1", events[len(events)-1].RunID)
+
+	events = receiveRun(t, conn)
+	assert.Equal(t, []streaming.Event{
+		{Type: streaming.EventToken, RunID: "2", Token: "hi"},
+		{Type: streaming.EventDone, Seq: 1, RunID: "2"},
+	}, events)
+}
@@ -9,6 +9,10 @@ The main components of this package are:
 - TextSplitter interface: a common interface for splitting texts into smaller chunks.
 - RecursiveCharacter: a text splitter that recursively splits texts by different characters (separators)
 combined with chunk size and overlap settings.
+- TokenSplitter: a text splitter that splits texts by tokens, with the encoding of a model
+when created with NewTokenSplitterForModel.
+- MarkdownHeader: a text splitter that splits markdown texts into their sections and keeps
+the headings of each chunk in its metadata.
 - Helper functions: utility functions for creating documents out of split texts and rejoining them if necessary.
 
 Using the TextSplitter interface, developers can implement custom
@@ -0,0 +1,159 @@
+package textsplitter
+
+import (
+	"strings"
+)
+
+// MarkdownHeading is a markdown heading level to split on.
+type MarkdownHeading struct {
+	// Marker is the marker of the heading, such as "##".
+	Marker string
+	// Key is the metadata key of the text of the heading.
+	Key string
+}
+
+// MarkdownHeader is a text splitter that splits markdown texts into their
+// sections, attaching the headings the sections are nested in to the metadata
+// of the chunks.
+type MarkdownHeader struct {
+	Headings []MarkdownHeading
+	// StripHeaders removes the heading lines from the chunks.
+	StripHeaders bool
+	// ChunkSplitter, if set, splits the sections further.
+	ChunkSplitter TextSplitter
+}
+
+var _ MetadataSplitter = MarkdownHeader{}
+
+// NewMarkdownHeader creates a new markdown header splitter with default values.
+// By default the texts are split on the "#", "##" and "###" headings, whose
+// texts are kept as "h1", "h2" and "h3" metadata, and the heading lines are
+// kept in the chunks.
+func NewMarkdownHeader() MarkdownHeader {
+	return MarkdownHeader{
+		Headings: []MarkdownHeading{
+			{Marker: "#", Key: "h1"},
+			{Marker: "##", Key: "h2"},
+			{Marker: "###", Key: "h3"},
+		},
+	}
+}
+
+// SplitText splits a markdown text into its sections.
+func (s MarkdownHeader) SplitText(text string) ([]string, error) {
+	chunks, _, err := s.SplitTextMetadata(text)
+	return chunks, err
+}
+
+// SplitTextMetadata splits a markdown text into its sections, returning the
+// headings of each chunk by metadata key. Headings inside code blocks are
+// ignored.
+func (s MarkdownHeader) SplitTextMetadata(text string) ([]string, []map[string]any, error) {
+	var (
+		chunks    []string
+		metadatas []map[string]any
+		section   []string
+		inCode    string
+		// current are the headings of the current section by level.
+		current = make(map[int]string)
+	)
+
+	flush := func() error {
+		content := strings.TrimSpace(strings.Join(section, "\n"))
+		section = section[:0]
+		if content == "" {
+			return nil
+		}
+		metadata := s.metadata(current)
+		if s.ChunkSplitter == nil {
+			chunks = append(chunks, content)
+			metadatas = append(metadatas, metadata)
+			return nil
+		}
+		split, err := s.ChunkSplitter.SplitText(content)
+		if err != nil {
+			return err
+		}
+		for _, chunk := range split {
+			chunks = append(chunks, chunk)
+			metadatas = append(metadatas, copyMetadata(metadata))
+		}
+		return nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence := codeFence(trimmed); fence != "" {
+			switch {
+			case inCode == "":
+				inCode = fence
+			case strings.HasPrefix(fence, inCode):
+				inCode = ""
+			}
+		}
+
+		if inCode == "" {
+			if level, title, ok := s.heading(trimmed); ok {
+				if err := flush(); err != nil {
+					return nil, nil, err
+				}
+				for l := range current {
+					if l >= level {
+						delete(current, l)
+					}
+				}
+				current[level] = title
+				if !s.StripHeaders {
+					section = append(section, line)
+				}
+				continue
+			}
+		}
+		section = append(section, line)
+	}
+	if err := flush(); err != nil {
+		return nil, nil, err
+	}
+	return chunks, metadatas, nil
+}
+
+// heading returns the level and the text of the line if it is a heading to
+// split on.
+func (s MarkdownHeader) heading(line string) (int, string, bool) {
+	for _, h := range s.Headings {
+		if line == h.Marker || strings.HasPrefix(line, h.Marker+" ") {
+			return len(h.Marker), strings.TrimSpace(strings.TrimPrefix(line, h.Marker)), true
+		}
+	}
+	return 0, "", false
+}
+
+// metadata returns the metadata of the headings by level.
+func (s MarkdownHeader) metadata(current map[int]string) map[string]any {
+	metadata := make(map[string]any, len(current))
+	for _, h := range s.Headings {
+		if title, ok := current[len(h.Marker)]; ok {
+			metadata[h.Key] = title
+		}
+	}
+	return metadata
+}
+
+// codeFence returns the fence opening or closing a code block on the line.
+func codeFence(line string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, marker) {
+			n := len(line) - len(strings.TrimLeft(line, marker[:1]))
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+func copyMetadata(metadata map[string]any) map[string]any {
+	c := make(map[string]any, len(metadata))
+	for key, value := range metadata {
+		c[key] = value
+	}
+	return c
+}
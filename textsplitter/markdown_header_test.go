@@ -0,0 +1,59 @@
+package textsplitter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestMarkdownHeaderSplitter(t *testing.T) {
+	t.Parallel()
+
+	text := `Intro.
+
+# Guide
+
+Welcome.
+
+## Install
+
+Run it.
+
+` + "```sh\n# not a heading\n```" + `
+
+## Use
+
+### Flags
+
+Pass flags.
+
+# Reference
+Details.`
+
+	s := NewMarkdownHeader()
+	s.StripHeaders = true
+	docs, err := CreateDocuments(s, []string{text}, []map[string]any{{"source": "README.md"}})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Document{
+		{PageContent: "Intro.", Metadata: map[string]any{"source": "README.md"}},
+		{PageContent: "Welcome.", Metadata: map[string]any{"source": "README.md", "h1": "Guide"}},
+		{
+			PageContent: "Run it.\n\n```sh\n# not a heading\n```",
+			Metadata:    map[string]any{"source": "README.md", "h1": "Guide", "h2": "Install"},
+		},
+		{
+			PageContent: "Pass flags.",
+			Metadata:    map[string]any{"source": "README.md", "h1": "Guide", "h2": "Use", "h3": "Flags"},
+		},
+		{PageContent: "Details.", Metadata: map[string]any{"source": "README.md", "h1": "Reference"}},
+	}, docs)
+
+	s = NewMarkdownHeader()
+	s.ChunkSplitter = RecursiveCharacter{Separators: []string{"\n", ""}, ChunkSize: 12}
+	chunks, metadatas, err := s.SplitTextMetadata("# Title\nfirst line\nsecond line")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"# Title", "first line", "second line"}, chunks)
+	assert.Equal(t, []map[string]any{{"h1": "Title"}, {"h1": "Title"}, {"h1": "Title"}}, metadatas)
+}
@@ -40,17 +40,22 @@ func CreateDocuments(textSplitter TextSplitter, texts []string, metadatas []map[
 	documents := make([]schema.Document, 0)
 
 	for i := 0; i < len(texts); i++ {
-		chunks, err := textSplitter.SplitText(texts[i])
+		chunks, chunkMetadatas, err := splitText(textSplitter, texts[i])
 		if err != nil {
 			return nil, err
 		}
 
-		for _, chunk := range chunks {
+		for j, chunk := range chunks {
 			// Copy the document metadata
 			curMetadata := make(map[string]any, len(metadatas[i]))
 			for key, value := range metadatas[i] {
 				curMetadata[key] = value
 			}
+			if chunkMetadatas != nil {
+				for key, value := range chunkMetadatas[j] {
+					curMetadata[key] = value
+				}
+			}
 
 			documents = append(documents, schema.Document{
 				PageContent: chunk,
@@ -62,6 +67,16 @@ func CreateDocuments(textSplitter TextSplitter, texts []string, metadatas []map[
 	return documents, nil
 }
 
+// splitText splits the text, returning the metadata of the chunks if the text
+// splitter is a MetadataSplitter.
+func splitText(textSplitter TextSplitter, text string) ([]string, []map[string]any, error) {
+	if s, ok := textSplitter.(MetadataSplitter); ok {
+		return s.SplitTextMetadata(text)
+	}
+	chunks, err := textSplitter.SplitText(text)
+	return chunks, nil, err
+}
+
 // joinDocs comines two documents with the separator used to split them.
 func joinDocs(docs []string, separator string) string {
 	return strings.TrimSpace(strings.Join(docs, separator))
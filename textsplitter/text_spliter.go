@@ -9,3 +9,11 @@ const (
 type TextSplitter interface {
 	SplitText(string) ([]string, error)
 }
+
+// MetadataSplitter is implemented by text splitters giving metadata to the
+// chunks, such as the headings of markdown sections. CreateDocuments adds the
+// metadata to the metadata of the documents.
+type MetadataSplitter interface {
+	TextSplitter
+	SplitTextMetadata(string) ([]string, []map[string]any, error)
+}
@@ -24,6 +24,8 @@ type TokenSplitter struct {
 	DisallowedSpecial []string
 }
 
+// NewTokenSplitter creates a new token splitter using the cl100k_base
+// encoding. The chunk size is set to 512 tokens and the chunk overlap to 100.
 func NewTokenSplitter() TokenSplitter {
 	return TokenSplitter{
 		ChunkSize:         _defaultTokenChunkSize,
@@ -35,6 +37,16 @@ func NewTokenSplitter() TokenSplitter {
 	}
 }
 
+// NewTokenSplitterForModel creates a new token splitter counting tokens with
+// the encoding of the model, like llms.CountTokens, so that chunks fit the
+// token limits of the model.
+func NewTokenSplitterForModel(model string) TokenSplitter {
+	s := NewTokenSplitter()
+	s.ModelName = model
+	s.EncodingName = ""
+	return s
+}
+
 // SplitText splits a text into multiple text.
 func (s TokenSplitter) SplitText(text string) ([]string, error) {
 	// Get the tokenizer
@@ -43,7 +55,11 @@ func (s TokenSplitter) SplitText(text string) ([]string, error) {
 	if s.EncodingName != "" {
 		tk, err = tiktoken.GetEncoding(s.EncodingName)
 	} else {
+		// Fall back to gpt2 like llms.CountTokens for unknown models.
 		tk, err = tiktoken.EncodingForModel(s.ModelName)
+		if err != nil {
+			tk, err = tiktoken.GetEncoding("gpt2")
+		}
 	}
 	if err != nil {
 		return nil, fmt.Errorf("tiktoken.GetEncoding: %w", err)
@@ -0,0 +1,212 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// Record is the audit record of a tool call. The input and output of the call
+// are recorded as their SHA-256 hashes, see Hash.
+type Record struct {
+	Tool       string        `json:"tool"`
+	RunID      string        `json:"run_id,omitempty"`
+	InputHash  string        `json:"input_hash"`
+	OutputHash string        `json:"output_hash,omitempty"`
+	Err        string        `json:"error,omitempty"`
+	Start      time.Time     `json:"start"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Sink stores audit records.
+type Sink interface {
+	Record(ctx context.Context, record Record) error
+}
+
+// Hash returns the hex encoded SHA-256 hash of a tool input or output, to
+// compare with the hashes of the records.
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Filter selects audit records. The zero values of the fields match all the
+// records.
+type Filter struct {
+	RunID string
+	Tool  string
+	Since time.Time
+	Until time.Time
+}
+
+// Match reports whether the record is selected by the filter.
+func (f Filter) Match(r Record) bool {
+	return (f.RunID == "" || r.RunID == f.RunID) &&
+		(f.Tool == "" || r.Tool == f.Tool) &&
+		(f.Since.IsZero() || !r.Start.Before(f.Since)) &&
+		(f.Until.IsZero() || r.Start.Before(f.Until))
+}
+
+// Tools returns the tools wrapped so that their calls are recorded to the sink.
+func Tools(sink Sink, ts []tools.Tool) []tools.Tool {
+	wrapped := make([]tools.Tool, len(ts))
+	for i, t := range ts {
+		wrapped[i] = Tool(sink, t)
+	}
+	return wrapped
+}
+
+// Tool returns the tool wrapped so that its calls are recorded to the sink,
+// with the run id of their context, see callbacks.WithRunID. A call whose
+// record can not be stored fails, so that no unaudited output reaches the
+// agent. Interrupters stay interrupters.
+func Tool(sink Sink, t tools.Tool) tools.Tool { //nolint:ireturn
+	a := auditedTool{Tool: t, sink: sink}
+	if interrupter, ok := t.(tools.Interrupter); ok {
+		return auditedInterrupter{auditedTool: a, Interrupter: interrupter}
+	}
+	return a
+}
+
+type auditedTool struct {
+	tools.Tool
+	sink Sink
+}
+
+var _ tools.Tool = auditedTool{}
+
+func (t auditedTool) Call(ctx context.Context, input string) (string, error) {
+	start := time.Now()
+	output, err := t.Tool.Call(ctx, input)
+	record := Record{
+		Tool:      t.Name(),
+		RunID:     callbacks.RunIDFromContext(ctx),
+		InputHash: Hash(input),
+		Start:     start,
+		Duration:  time.Since(start),
+	}
+	if err != nil {
+		record.Err = err.Error()
+	} else {
+		record.OutputHash = Hash(output)
+	}
+
+	// The call may have been canceled, the record must still be stored.
+	if sinkErr := t.sink.Record(context.Background(), record); sinkErr != nil {
+		return "", fmt.Errorf("audit tool call: %w", sinkErr)
+	}
+	return output, err
+}
+
+type auditedInterrupter struct {
+	auditedTool
+	tools.Interrupter
+}
+
+var (
+	_ tools.Tool        = auditedInterrupter{}
+	_ tools.Interrupter = auditedInterrupter{}
+)
+
+// MemorySink is a sink keeping the records in memory.
+type MemorySink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+var _ Sink = &MemorySink{}
+
+// NewMemorySink creates a new MemorySink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Record stores the record.
+func (s *MemorySink) Record(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// Query returns the records selected by the filter, in the order they were
+// stored.
+func (s *MemorySink) Query(filter Filter) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []Record
+	for _, r := range s.records {
+		if filter.Match(r) {
+			records = append(records, r)
+		}
+	}
+	return records
+}
+
+// JSONLinesSink is a sink writing the records as JSON lines, for example to a
+// log file. The records can be read back with Query.
+type JSONLinesSink struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+var _ Sink = &JSONLinesSink{}
+
+// NewJSONLinesSink creates a new JSONLinesSink writing to w.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{w: w, enc: json.NewEncoder(w)}
+}
+
+// Record writes the record as a JSON line.
+func (s *JSONLinesSink) Record(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(record)
+}
+
+// Flush flushes the writer of the sink if it is buffered, as a *bufio.Writer,
+// and syncs it if it is a file.
+func (s *JSONLinesSink) Flush(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f, ok := s.w.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return err
+		}
+	}
+	if f, ok := s.w.(interface{ Sync() error }); ok {
+		return f.Sync()
+	}
+	return nil
+}
+
+// Query reads the records written by a JSONLinesSink and returns those
+// selected by the filter.
+func Query(r io.Reader, filter Filter) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, err
+		}
+		if filter.Match(record) {
+			records = append(records, record)
+		}
+	}
+	return records, scanner.Err()
+}
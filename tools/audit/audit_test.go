@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/tools"
+)
+
+var errToolFailed = errors.New("tool failed")
+
+type echoTool struct{}
+
+func (echoTool) Name() string        { return "echo" }
+func (echoTool) Description() string { return "echoes the input" }
+
+func (echoTool) Call(_ context.Context, input string) (string, error) {
+	if input == "fail" {
+		return "", errToolFailed
+	}
+	return "echo: " + input, nil
+}
+
+type jobTool struct {
+	echoTool
+	interrupted bool
+}
+
+func (t *jobTool) Interrupt(context.Context) error {
+	t.interrupted = true
+	return nil
+}
+
+type failingSink struct{}
+
+func (failingSink) Record(context.Context, Record) error { return errors.New("disk full") }
+
+func TestTool(t *testing.T) {
+	t.Parallel()
+
+	sink := NewMemorySink()
+	tool := Tool(sink, echoTool{})
+
+	ctx := callbacks.WithRunID(context.Background(), "run-1")
+	output, err := tool.Call(ctx, "hi")
+	require.NoError(t, err)
+	require.Equal(t, "echo: hi", output)
+	_, err = tool.Call(callbacks.WithRunID(context.Background(), "run-2"), "fail")
+	require.ErrorIs(t, err, errToolFailed)
+
+	records := sink.Query(Filter{RunID: "run-1"})
+	require.Len(t, records, 1)
+	require.Equal(t, "echo", records[0].Tool)
+	require.Equal(t, Hash("hi"), records[0].InputHash)
+	require.Equal(t, Hash("echo: hi"), records[0].OutputHash)
+	require.Empty(t, records[0].Err)
+
+	records = sink.Query(Filter{Tool: "echo"})
+	require.Len(t, records, 2)
+	require.Equal(t, "run-2", records[1].RunID)
+	require.Equal(t, errToolFailed.Error(), records[1].Err)
+	require.Empty(t, records[1].OutputHash)
+
+	require.Empty(t, sink.Query(Filter{Since: time.Now().Add(time.Hour)}))
+}
+
+func TestToolFailingSink(t *testing.T) {
+	t.Parallel()
+
+	_, err := Tool(failingSink{}, echoTool{}).Call(context.Background(), "hi")
+	require.ErrorContains(t, err, "disk full")
+}
+
+func TestToolInterrupter(t *testing.T) {
+	t.Parallel()
+
+	job := &jobTool{}
+	wrapped := Tools(NewMemorySink(), []tools.Tool{echoTool{}, job})
+	_, ok := wrapped[0].(tools.Interrupter)
+	require.False(t, ok)
+	interrupter, ok := wrapped[1].(tools.Interrupter)
+	require.True(t, ok)
+	require.NoError(t, interrupter.Interrupt(context.Background()))
+	require.True(t, job.interrupted)
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	tool := Tool(NewJSONLinesSink(&buf), echoTool{})
+	for _, runID := range []string{"a", "b", "a"} {
+		_, err := tool.Call(callbacks.WithRunID(context.Background(), runID), runID)
+		require.NoError(t, err)
+	}
+
+	records, err := Query(&buf, Filter{RunID: "a"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for _, r := range records {
+		require.Equal(t, Hash("a"), r.InputHash)
+		require.Equal(t, Hash("echo: a"), r.OutputHash)
+	}
+}
+
+func TestJSONLinesSinkFlush(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	sink := NewJSONLinesSink(w)
+	require.NoError(t, sink.Record(context.Background(), Record{Tool: "echo"}))
+	require.Zero(t, buf.Len())
+	require.NoError(t, sink.Flush(context.Background()))
+
+	records, err := Query(&buf, Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+}
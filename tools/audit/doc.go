@@ -0,0 +1,10 @@
+// Package audit records the tool calls of agents to an audit sink, with the
+// name of the tool, the hashes of its input and output, the duration of the
+// call and the id of the run making it, so that what an agent did can be
+// reconstructed afterwards:
+//
+//	sink := audit.NewJSONLinesSink(logFile)
+//	executor, err := agents.Initialize(llm, audit.Tools(sink, tools), agents.ZeroShotReactDescription)
+//
+// The records of a run are then selected with Query or MemorySink.Query.
+package audit
@@ -0,0 +1,63 @@
+package coderunner
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Language is a programming language code is written in.
+type Language string
+
+const (
+	// Go code is a main package run with go run.
+	Go Language = "go"
+	// Python code is a script run with python3.
+	Python Language = "python"
+)
+
+// ErrUnsupportedLanguage is returned when running code in a language the
+// sandbox does not support.
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// Limits are the resources code may use when run.
+type Limits struct {
+	// CPUs is the number of CPUs, such as 0.5.
+	CPUs float64
+	// MemoryBytes is the memory, swap included.
+	MemoryBytes int64
+	// Processes is the number of processes and threads.
+	Processes int
+	// Timeout is the time the code may run, build included.
+	Timeout time.Duration
+	// MaxOutputBytes is the size at which the standard output and error are
+	// each cut.
+	MaxOutputBytes int
+}
+
+// DefaultLimits are the limits of sandboxes created without any.
+var DefaultLimits = Limits{ //nolint:gochecknoglobals
+	CPUs:           1,
+	MemoryBytes:    256 << 20,
+	Processes:      64,
+	Timeout:        30 * time.Second,
+	MaxOutputBytes: 16 << 10,
+}
+
+// Result is the outcome of running code.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// TimedOut is whether the code was stopped at the timeout.
+	TimedOut bool
+	// Truncated is whether the standard output or error was cut.
+	Truncated bool
+}
+
+// Sandbox runs untrusted code in isolation.
+type Sandbox interface {
+	// Run runs the code and returns its outcome. A code failing, or timing
+	// out, is a result with an error exit code, not an error.
+	Run(ctx context.Context, language Language, code string) (Result, error)
+}
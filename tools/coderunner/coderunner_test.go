@@ -0,0 +1,107 @@
+package coderunner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDocker writes a docker command recording its arguments in dir and
+// running the code it reads as a shell script.
+func fakeDocker(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docker")
+	script := `#!/bin/sh
+if [ "$1" = "rm" ]; then echo "$@" >> "` + dir + `/removed"; exit 0; fi
+echo "$@" > "` + dir + `/args"
+exec sh -s
+`
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o700)) //nolint:gosec
+	return path, dir
+}
+
+func TestDocker(t *testing.T) {
+	t.Parallel()
+
+	path, dir := fakeDocker(t)
+	d := NewDocker(WithDockerPath(path), WithImage(Python, "python:test"), WithLimits(Limits{
+		CPUs:           0.5,
+		MemoryBytes:    64 << 20,
+		MaxOutputBytes: 10,
+	}))
+
+	result, err := d.Run(context.Background(), Python, "echo hello")
+	require.NoError(t, err)
+	require.Equal(t, Result{Stdout: "hello\n"}, result)
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	for _, arg := range []string{
+		"run --rm -i", "--network none", "--read-only", "--cpus 0.5", "--memory 67108864",
+		"--pids-limit 64", "python:test python3 -",
+	} {
+		require.Contains(t, string(args), arg)
+	}
+
+	result, err = d.Run(context.Background(), Python, "echo oops >&2; exit 3")
+	require.NoError(t, err)
+	require.Equal(t, Result{Stderr: "oops\n", ExitCode: 3}, result)
+
+	result, err = d.Run(context.Background(), Python, "echo 0123456789abcdef")
+	require.NoError(t, err)
+	require.Equal(t, Result{Stdout: "0123456789", Truncated: true}, result)
+
+	_, err = d.Run(context.Background(), "cobol", "")
+	require.ErrorIs(t, err, ErrUnsupportedLanguage)
+}
+
+func TestDockerTimeout(t *testing.T) {
+	t.Parallel()
+
+	path, dir := fakeDocker(t)
+	d := NewDocker(WithDockerPath(path), WithLimits(Limits{Timeout: 100 * time.Millisecond}))
+
+	result, err := d.Run(context.Background(), Go, "exec sleep 10")
+	require.NoError(t, err)
+	require.True(t, result.TimedOut)
+	require.Equal(t, -1, result.ExitCode)
+
+	removed, err := os.ReadFile(filepath.Join(dir, "removed"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(removed), "rm -f langchaingo-coderunner-"))
+}
+
+type fakeSandbox struct {
+	code   string
+	result Result
+}
+
+func (s *fakeSandbox) Run(_ context.Context, _ Language, code string) (Result, error) {
+	s.code = code
+	return s.result, nil
+}
+
+func TestTool(t *testing.T) {
+	t.Parallel()
+
+	sandbox := &fakeSandbox{result: Result{Stdout: "4\n", Stderr: "warning\n", ExitCode: 0}}
+	tool := New(sandbox, Python)
+	require.Equal(t, "Python Runner", tool.Name())
+
+	out, err := tool.Call(context.Background(), "```python\nprint(2+2)\n```")
+	require.NoError(t, err)
+	require.Equal(t, "print(2+2)", sandbox.code)
+	require.Equal(t, "Exit code: 0\nOutput:\n4\nErrors:\nwarning", out)
+
+	sandbox.result = Result{ExitCode: -1, TimedOut: true, Truncated: true}
+	out, err = New(sandbox, Go).Call(context.Background(), "package main")
+	require.NoError(t, err)
+	require.Equal(t, "package main", sandbox.code)
+	require.Equal(t, "The code timed out.\nExit code: -1\n(output truncated)", out)
+}
@@ -0,0 +1,10 @@
+// Package coderunner contains a tool running Go or Python code written by
+// agents in a sandbox, returning its output, so that agents can answer by
+// writing code.
+//
+// Code is run by a Sandbox. Docker runs it in a throwaway container without
+// network access, with a read-only file system and limits on CPU, memory,
+// processes and time, and captures its standard output and error up to a
+// maximum size. Other runtimes, such as WASM ones, can be used by implementing
+// Sandbox.
+package coderunner
@@ -0,0 +1,202 @@
+package coderunner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// _dockerStopTimeout is the time given to remove the container of a run which
+// timed out or was canceled.
+const _dockerStopTimeout = 10 * time.Second
+
+// dockerCommands are the commands running the code, read from the standard
+// input, in the containers of each language.
+var dockerCommands = map[Language][]string{ //nolint:gochecknoglobals
+	Python: {"python3", "-"},
+	Go:     {"sh", "-c", "cat > /tmp/main.go && go run /tmp/main.go"},
+}
+
+// Docker is a sandbox running code in throwaway Docker containers, without
+// network access, with a read-only file system except for /tmp, as an
+// unprivileged user and with the resources of its limits.
+type Docker struct {
+	dockerPath string
+	images     map[Language]string
+	limits     Limits
+}
+
+var _ Sandbox = (*Docker)(nil)
+
+// DockerOption is a function type that can be used to modify the Docker
+// sandbox.
+type DockerOption func(d *Docker)
+
+// WithDockerPath is an option for setting the path of the docker command.
+// Defaults to "docker", looked up in PATH.
+func WithDockerPath(path string) DockerOption {
+	return func(d *Docker) {
+		d.dockerPath = path
+	}
+}
+
+// WithImage is an option for setting the image running the code of a
+// language. Defaults to python:3.12-alpine and golang:1.21-alpine.
+func WithImage(language Language, image string) DockerOption {
+	return func(d *Docker) {
+		d.images[language] = image
+	}
+}
+
+// WithLimits is an option for setting the limits of the runs. Zero limits are
+// the ones of DefaultLimits.
+func WithLimits(limits Limits) DockerOption {
+	return func(d *Docker) {
+		d.limits = limits
+	}
+}
+
+// NewDocker creates a new Docker sandbox.
+func NewDocker(opts ...DockerOption) *Docker {
+	d := &Docker{
+		dockerPath: "docker",
+		images: map[Language]string{
+			Python: "python:3.12-alpine",
+			Go:     "golang:1.21-alpine",
+		},
+		limits: DefaultLimits,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.limits = withDefaults(d.limits)
+	return d
+}
+
+// Run runs the code in a new container, removed once done.
+func (d *Docker) Run(ctx context.Context, language Language, code string) (Result, error) {
+	image, ok := d.images[language]
+	command, known := dockerCommands[language]
+	if !ok || !known {
+		return Result{}, fmt.Errorf("%w: %q", ErrUnsupportedLanguage, language)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, d.limits.Timeout)
+	defer cancel()
+
+	name := "langchaingo-coderunner-" + uuid.NewString()
+	cmd := exec.CommandContext(runCtx, d.dockerPath, append(d.runArgs(name, image), command...)...) //nolint:gosec
+	cmd.Stdin = strings.NewReader(code)
+	stdout := &limitedBuffer{limit: d.limits.MaxOutputBytes}
+	stderr := &limitedBuffer{limit: d.limits.MaxOutputBytes}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	cmd.WaitDelay = _dockerStopTimeout
+
+	err := cmd.Run()
+	if runCtx.Err() != nil {
+		// Killing the docker client does not stop the container.
+		d.remove(name)
+	}
+	if ctx.Err() != nil {
+		return Result{}, ctx.Err()
+	}
+
+	result := Result{
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		TimedOut:  errors.Is(runCtx.Err(), context.DeadlineExceeded),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case result.TimedOut:
+		result.ExitCode = -1
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return Result{}, fmt.Errorf("run docker: %w", err)
+	}
+	return result, nil
+}
+
+// runArgs returns the arguments of docker running a container with the name
+// and image.
+func (d *Docker) runArgs(name, image string) []string {
+	return []string{
+		"run", "--rm", "-i",
+		"--name", name,
+		"--network", "none",
+		"--read-only",
+		"--tmpfs", "/tmp:rw,exec,size=64m",
+		"--user", "65534:65534",
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges",
+		"--cpus", strconv.FormatFloat(d.limits.CPUs, 'f', -1, 64),
+		"--memory", strconv.FormatInt(d.limits.MemoryBytes, 10),
+		"--memory-swap", strconv.FormatInt(d.limits.MemoryBytes, 10),
+		"--pids-limit", strconv.Itoa(d.limits.Processes),
+		"--env", "HOME=/tmp",
+		"--env", "GOCACHE=/tmp/.cache",
+		"--env", "GOPATH=/tmp/go",
+		"--env", "GOPROXY=off",
+		image,
+	}
+}
+
+// remove removes the container, which may still be running.
+func (d *Docker) remove(name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), _dockerStopTimeout)
+	defer cancel()
+	_ = exec.CommandContext(ctx, d.dockerPath, "rm", "-f", name).Run() //nolint:gosec
+}
+
+// withDefaults returns the limits with the zero ones set to the default ones.
+func withDefaults(l Limits) Limits {
+	if l.CPUs <= 0 {
+		l.CPUs = DefaultLimits.CPUs
+	}
+	if l.MemoryBytes <= 0 {
+		l.MemoryBytes = DefaultLimits.MemoryBytes
+	}
+	if l.Processes <= 0 {
+		l.Processes = DefaultLimits.Processes
+	}
+	if l.Timeout <= 0 {
+		l.Timeout = DefaultLimits.Timeout
+	}
+	if l.MaxOutputBytes <= 0 {
+		l.MaxOutputBytes = DefaultLimits.MaxOutputBytes
+	}
+	return l
+}
+
+// limitedBuffer is a buffer keeping the first bytes written up to its limit.
+// The buffer is not embedded, so that io.Copy does not bypass Write with its
+// ReadFrom method.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
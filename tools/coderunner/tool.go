@@ -0,0 +1,78 @@
+package coderunner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+)
+
+// Tool is a tool running the code of agents in a language with a sandbox.
+type Tool struct {
+	sandbox  Sandbox
+	language Language
+}
+
+var _ tools.Tool = Tool{}
+
+// New creates a new tool running code of the language in the sandbox.
+func New(sandbox Sandbox, language Language) Tool {
+	return Tool{sandbox: sandbox, language: language}
+}
+
+// Name returns the name of the tool.
+func (t Tool) Name() string {
+	if t.language == Go {
+		return "Go Runner"
+	}
+	return "Python Runner"
+}
+
+// Description returns a string describing the tool.
+func (t Tool) Description() string {
+	if t.language == Go {
+		return `Runs a Go program and returns what it prints. Useful for computations and data processing. The input should be a complete main package using the standard library only, printing the answer. It has no network access.` //nolint:lll
+	}
+	return `Runs a Python script and returns what it prints. Useful for computations and data processing. The input should be a complete script using the standard library only, printing the answer with print(). It has no network access.` //nolint:lll
+}
+
+// Call runs the code, removing the markdown code fences around it, and
+// returns its output, its errors and its exit code.
+func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	result, err := t.sandbox.Run(ctx, t.language, stripCodeFences(input))
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if result.TimedOut {
+		sb.WriteString("The code timed out.\n")
+	}
+	fmt.Fprintf(&sb, "Exit code: %d\n", result.ExitCode)
+	if result.Stdout != "" {
+		fmt.Fprintf(&sb, "Output:\n%s\n", strings.TrimRight(result.Stdout, "\n"))
+	}
+	if result.Stderr != "" {
+		fmt.Fprintf(&sb, "Errors:\n%s\n", strings.TrimRight(result.Stderr, "\n"))
+	}
+	if result.Truncated {
+		sb.WriteString("(output truncated)\n")
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+// stripCodeFences returns the code inside the markdown code fences of the
+// text, if any.
+func stripCodeFences(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	_, code, found := strings.Cut(text, "\n")
+	if !found {
+		return ""
+	}
+	code = strings.TrimSpace(code)
+	return strings.TrimSpace(strings.TrimSuffix(code, "```"))
+}
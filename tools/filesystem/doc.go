@@ -0,0 +1,12 @@
+// Package filesystem contains tools reading, writing, listing and searching
+// the files of a directory tree, so that coding and document agents can work
+// on a project.
+//
+// The tools share an FS bound to a root directory. Paths are relative to the
+// root, and paths leaving it, through ".." or symbolic links, are refused.
+// Files larger than a maximum size are neither read nor written, and listings
+// and searches stop at a maximum number of results.
+//
+// The tools are structured tools: their input is a JSON object, such as
+// {"path": "docs/intro.md"}.
+package filesystem
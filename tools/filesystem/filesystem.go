@@ -0,0 +1,297 @@
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// _sniffSize is the number of bytes looked at to tell binary files apart.
+const _sniffSize = 512
+
+var (
+	// ErrOutsideRoot is returned when a path leaves the root directory.
+	ErrOutsideRoot = errors.New("path outside the root directory")
+	// ErrFileTooLarge is returned when reading or writing a file larger than
+	// the maximum file size.
+	ErrFileTooLarge = errors.New("file too large")
+	// ErrReadOnly is returned when writing a file in a read-only file system.
+	ErrReadOnly = errors.New("read-only file system")
+	// ErrNotAFile is returned when reading a directory or a special file.
+	ErrNotAFile = errors.New("not a regular file")
+)
+
+// Entry is an entry of a directory.
+type Entry struct {
+	// Path is the path of the entry from the root, with forward slashes.
+	Path  string
+	IsDir bool
+	// Size is the size of files, in bytes.
+	Size int64
+}
+
+// Match is a line of a file matching a search.
+type Match struct {
+	// Path is the path of the file from the root, with forward slashes.
+	Path string
+	// Line is the number of the line, from 1.
+	Line int
+	Text string
+}
+
+// Listing is the content of a directory.
+type Listing struct {
+	Entries []Entry
+	// Truncated is whether the listing stopped at the maximum number of
+	// results.
+	Truncated bool
+}
+
+// SearchResult is the outcome of a search.
+type SearchResult struct {
+	Matches []Match
+	// Truncated is whether the search stopped at the maximum number of
+	// results.
+	Truncated bool
+}
+
+// FS gives access to the files under a root directory.
+type FS struct {
+	root        string
+	maxFileSize int64
+	maxResults  int
+	readOnly    bool
+}
+
+// New creates a new file system with the root directory and options.
+func New(root string, opts ...Option) (*FS, error) {
+	fsys := &FS{
+		maxFileSize: _defaultMaxFileSize,
+		maxResults:  _defaultMaxResults,
+	}
+	for _, opt := range opts {
+		opt(fsys)
+	}
+
+	abs, err := filepath.Abs(root)
+	if err == nil {
+		abs, err = filepath.EvalSymlinks(abs)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("root directory: %w", err)
+	}
+	fsys.root = abs
+	return fsys, nil
+}
+
+// ReadFile returns the content of the file.
+func (f *FS) ReadFile(path string) (string, error) {
+	abs, err := f.resolve(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", f.relError(err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%w: %q", ErrNotAFile, path)
+	}
+	if info.Size() > f.maxFileSize {
+		return "", fmt.Errorf("%w: %q is %d bytes, the maximum is %d",
+			ErrFileTooLarge, path, info.Size(), f.maxFileSize)
+	}
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return "", f.relError(err)
+	}
+	return string(content), nil
+}
+
+// WriteFile writes the content to the file, creating it and its parent
+// directories if needed.
+func (f *FS) WriteFile(path, content string) error {
+	if f.readOnly {
+		return ErrReadOnly
+	}
+	if int64(len(content)) > f.maxFileSize {
+		return fmt.Errorf("%w: %d bytes, the maximum is %d", ErrFileTooLarge, len(content), f.maxFileSize)
+	}
+	abs, err := f.resolve(path)
+	if err != nil {
+		return err
+	}
+	if abs == f.root {
+		return fmt.Errorf("%w: %q", ErrNotAFile, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil { //nolint:gosec
+		return f.relError(err)
+	}
+	return f.relError(os.WriteFile(abs, []byte(content), 0o644)) //nolint:gosec
+}
+
+// ListDirectory returns the entries of the directory, directories first.
+func (f *FS) ListDirectory(path string) (Listing, error) {
+	abs, err := f.resolve(path)
+	if err != nil {
+		return Listing{}, err
+	}
+	dirEntries, err := os.ReadDir(abs)
+	if err != nil {
+		return Listing{}, f.relError(err)
+	}
+
+	entries := make([]Entry, 0, len(dirEntries))
+	for _, d := range dirEntries {
+		entry := Entry{Path: f.rel(filepath.Join(abs, d.Name())), IsDir: d.IsDir()}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].IsDir && !entries[j].IsDir
+	})
+	if len(entries) > f.maxResults {
+		return Listing{Entries: entries[:f.maxResults], Truncated: true}, nil
+	}
+	return Listing{Entries: entries}, nil
+}
+
+// GrepSearch returns the lines matching the regular expression of the text
+// files under the directory. Hidden directories, such as .git, are skipped,
+// as are binary files and files larger than the maximum file size.
+func (f *FS) GrepSearch(ctx context.Context, pattern, path string) (SearchResult, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return SearchResult{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	abs, err := f.resolve(path)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	result := SearchResult{}
+	errDone := errors.New("done")
+	err = filepath.WalkDir(abs, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // Unreadable entries are skipped.
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if p != abs && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		matches, err := f.grepFile(re, p, f.maxResults-len(result.Matches)+1)
+		if err != nil {
+			return nil //nolint:nilerr // Unreadable files are skipped.
+		}
+		result.Matches = append(result.Matches, matches...)
+		if len(result.Matches) > f.maxResults {
+			result.Matches, result.Truncated = result.Matches[:f.maxResults], true
+			return errDone
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDone) {
+		return SearchResult{}, err
+	}
+	return result, nil
+}
+
+// grepFile returns up to limit lines of the file matching the regular
+// expression, or none if the file is binary or too large.
+func (f *FS) grepFile(re *regexp.Regexp, path string, limit int) ([]Match, error) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > f.maxFileSize {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(content[:minInt(len(content), _sniffSize)], 0) >= 0 {
+		return nil, nil
+	}
+
+	var matches []Match
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64<<10), int(f.maxFileSize)+1)
+	for line := 1; scanner.Scan() && len(matches) < limit; line++ {
+		if re.MatchString(scanner.Text()) {
+			matches = append(matches, Match{Path: f.rel(path), Line: line, Text: scanner.Text()})
+		}
+	}
+	return matches, nil
+}
+
+// resolve returns the absolute path of a path relative to the root, with the
+// symbolic links of its existing part resolved, or ErrOutsideRoot if it is
+// not under the root. Absolute paths are taken as relative to the root.
+func (f *FS) resolve(path string) (string, error) {
+	abs := filepath.Join(f.root, filepath.FromSlash(path))
+	abs = resolveSymlinks(abs)
+	if abs != f.root && !strings.HasPrefix(abs, f.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q", ErrOutsideRoot, path)
+	}
+	return abs, nil
+}
+
+// rel returns the path of an absolute path under the root from the root, with
+// forward slashes.
+func (f *FS) rel(abs string) string {
+	rel, err := filepath.Rel(f.root, abs)
+	if err != nil {
+		return abs
+	}
+	return filepath.ToSlash(rel)
+}
+
+// relError replaces the absolute paths of path errors by paths from the root,
+// so that the root is not disclosed to agents.
+func (f *FS) relError(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return &fs.PathError{Op: pathErr.Op, Path: f.rel(pathErr.Path), Err: pathErr.Err}
+	}
+	return err
+}
+
+// resolveSymlinks returns the path with the symbolic links of its longest
+// existing prefix resolved.
+func resolveSymlinks(path string) string {
+	rest := ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest)
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
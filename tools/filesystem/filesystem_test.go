@@ -0,0 +1,149 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFS(t *testing.T, opts ...Option) (*FS, string) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs", ".git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "intro.md"), []byte("# Intro\nmain ideas\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", ".git", "HEAD"), []byte("main\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.png"), []byte("main\x00\x01"), 0o600))
+	require.NoError(t, os.Symlink("/etc", filepath.Join(dir, "etc")))
+
+	fsys, err := New(dir, opts...)
+	require.NoError(t, err)
+	return fsys, dir
+}
+
+func TestReadWriteFile(t *testing.T) {
+	t.Parallel()
+	fsys, dir := newTestFS(t, WithMaxFileSize(64))
+
+	content, err := fsys.ReadFile("docs/intro.md")
+	require.NoError(t, err)
+	require.Equal(t, "# Intro\nmain ideas\n", content)
+
+	require.NoError(t, fsys.WriteFile("/src/new.txt", "hello"))
+	written, err := os.ReadFile(filepath.Join(dir, "src", "new.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(written))
+
+	for path, want := range map[string]error{
+		"../secret":         ErrOutsideRoot,
+		"docs/../../secret": ErrOutsideRoot,
+		"etc/passwd":        ErrOutsideRoot,
+		"docs":              ErrNotAFile,
+	} {
+		_, err := fsys.ReadFile(path)
+		require.ErrorIs(t, err, want, path)
+	}
+	require.ErrorIs(t, fsys.WriteFile("etc/evil", "x"), ErrOutsideRoot)
+	require.ErrorIs(t, fsys.WriteFile("big.txt", strings.Repeat("x", 65)), ErrFileTooLarge)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Repeat("x", 65)), 0o600))
+	_, err = fsys.ReadFile("big.txt")
+	require.ErrorIs(t, err, ErrFileTooLarge)
+
+	_, err = fsys.ReadFile("missing.txt")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.NotContains(t, err.Error(), dir)
+
+	readOnly, _ := newTestFS(t, WithReadOnly(true))
+	require.ErrorIs(t, readOnly.WriteFile("new.txt", "x"), ErrReadOnly)
+}
+
+func TestListDirectory(t *testing.T) {
+	t.Parallel()
+	fsys, _ := newTestFS(t)
+
+	listing, err := fsys.ListDirectory("docs")
+	require.NoError(t, err)
+	require.Equal(t, Listing{Entries: []Entry{
+		{Path: "docs/.git", IsDir: true},
+		{Path: "docs/intro.md", Size: 19},
+	}}, listing)
+
+	_, err = fsys.ListDirectory("etc")
+	require.ErrorIs(t, err, ErrOutsideRoot)
+
+	small, _ := newTestFS(t, WithMaxResults(2))
+	listing, err = small.ListDirectory(".")
+	require.NoError(t, err)
+	require.True(t, listing.Truncated)
+	require.Len(t, listing.Entries, 2)
+}
+
+func TestGrepSearch(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	fsys, _ := newTestFS(t)
+
+	result, err := fsys.GrepSearch(ctx, `\bmain\b`, ".")
+	require.NoError(t, err)
+	require.Equal(t, SearchResult{Matches: []Match{
+		{Path: "docs/intro.md", Line: 2, Text: "main ideas"},
+		{Path: "main.go", Line: 1, Text: "package main"},
+		{Path: "main.go", Line: 3, Text: "func main() {}"},
+	}}, result)
+
+	_, err = fsys.GrepSearch(ctx, "(", ".")
+	require.Error(t, err)
+	_, err = fsys.GrepSearch(ctx, "root", "etc")
+	require.ErrorIs(t, err, ErrOutsideRoot)
+
+	small, _ := newTestFS(t, WithMaxResults(2))
+	result, err = small.GrepSearch(ctx, "main", ".")
+	require.NoError(t, err)
+	require.True(t, result.Truncated)
+	require.Len(t, result.Matches, 2)
+}
+
+func TestTools(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	fsys, _ := newTestFS(t)
+
+	ts := fsys.Tools()
+	names := make([]string, 0, len(ts))
+	for _, tool := range ts {
+		names = append(names, tool.Name())
+	}
+	require.Equal(t, []string{"read_file", "list_directory", "grep_search", "write_file"}, names)
+
+	readOnly, _ := newTestFS(t, WithReadOnly(true))
+	require.Len(t, readOnly.Tools(), 3)
+
+	out, err := WriteFileTool{fs: fsys}.Call(ctx, `{"path": "notes/todo.txt", "content": "ship it"}`)
+	require.NoError(t, err)
+	require.Equal(t, "wrote 7 bytes to notes/todo.txt", out)
+
+	out, err = ReadFileTool{fs: fsys}.Call(ctx, `{"path": "notes/todo.txt"}`)
+	require.NoError(t, err)
+	require.Equal(t, "ship it", out)
+
+	out, err = ListDirectoryTool{fs: fsys}.Call(ctx, `{"path": "docs"}`)
+	require.NoError(t, err)
+	require.Equal(t, "docs/.git/\ndocs/intro.md (19 bytes)", out)
+
+	out, err = GrepSearchTool{fs: fsys}.Call(ctx, `{"pattern": "ideas"}`)
+	require.NoError(t, err)
+	require.Equal(t, "docs/intro.md:2:main ideas", out)
+
+	out, err = ReadFileTool{fs: fsys}.Call(ctx, `{"path": "../../etc/passwd"}`)
+	require.NoError(t, err)
+	require.Equal(t, `error: path outside the root directory: "../../etc/passwd"`, out)
+
+	out, err = ReadFileTool{fs: fsys}.Call(ctx, "main.go")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "error: invalid tool input"), out)
+}
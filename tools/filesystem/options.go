@@ -0,0 +1,33 @@
+package filesystem
+
+const (
+	_defaultMaxFileSize = 256 << 10
+	_defaultMaxResults  = 200
+)
+
+// Option is a function type that can be used to modify the file system.
+type Option func(fs *FS)
+
+// WithMaxFileSize is an option for setting the size of the largest file read,
+// written or searched, in bytes. Defaults to 256 KiB.
+func WithMaxFileSize(size int64) Option {
+	return func(fs *FS) {
+		fs.maxFileSize = size
+	}
+}
+
+// WithMaxResults is an option for setting the number of entries listed and of
+// lines found by searches, beyond which results are cut. Defaults to 200.
+func WithMaxResults(n int) Option {
+	return func(fs *FS) {
+		fs.maxResults = n
+	}
+}
+
+// WithReadOnly is an option for refusing to write files, leaving the write
+// tool out of Tools.
+func WithReadOnly(readOnly bool) Option {
+	return func(fs *FS) {
+		fs.readOnly = readOnly
+	}
+}
@@ -0,0 +1,213 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+)
+
+var (
+	_ tools.StructuredTool = ReadFileTool{}
+	_ tools.StructuredTool = WriteFileTool{}
+	_ tools.StructuredTool = ListDirectoryTool{}
+	_ tools.StructuredTool = GrepSearchTool{}
+)
+
+// Tools returns the tools of the file system: ReadFileTool, ListDirectoryTool,
+// GrepSearchTool and, unless it is read-only, WriteFileTool.
+func (f *FS) Tools() []tools.Tool {
+	ts := []tools.Tool{ReadFileTool{fs: f}, ListDirectoryTool{fs: f}, GrepSearchTool{fs: f}}
+	if !f.readOnly {
+		ts = append(ts, WriteFileTool{fs: f})
+	}
+	return ts
+}
+
+// ReadFileTool is a tool returning the content of a file.
+type ReadFileTool struct {
+	fs *FS
+}
+
+// Name returns the name of the tool.
+func (t ReadFileTool) Name() string {
+	return "read_file"
+}
+
+// Description returns a string describing the tool.
+func (t ReadFileTool) Description() string {
+	return "Returns the content of a text file of the project."
+}
+
+// Parameters returns the parameters of the input of the tool.
+func (t ReadFileTool) Parameters() []tools.Parameter {
+	return []tools.Parameter{_pathParameter}
+}
+
+// Call returns the content of the file. Failures are reported in the result
+// to let the agent try another path.
+func (t ReadFileTool) Call(_ context.Context, input string) (string, error) {
+	values, err := tools.ValidateInput(t, input)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	content, err := t.fs.ReadFile(values["path"].(string))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	return content, nil
+}
+
+// WriteFileTool is a tool writing a file.
+type WriteFileTool struct {
+	fs *FS
+}
+
+// Name returns the name of the tool.
+func (t WriteFileTool) Name() string {
+	return "write_file"
+}
+
+// Description returns a string describing the tool.
+func (t WriteFileTool) Description() string {
+	return "Writes a text file of the project, replacing its content, and creates it with its directories if needed."
+}
+
+// Parameters returns the parameters of the input of the tool.
+func (t WriteFileTool) Parameters() []tools.Parameter {
+	return []tools.Parameter{
+		_pathParameter,
+		{Name: "content", Type: tools.ParameterTypeString, Description: "The new content of the file.", Required: true},
+	}
+}
+
+// Call writes the file. Failures are reported in the result.
+func (t WriteFileTool) Call(_ context.Context, input string) (string, error) {
+	values, err := tools.ValidateInput(t, input)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	path, content := values["path"].(string), values["content"].(string)
+	if err := t.fs.WriteFile(path, content); err != nil {
+		return errorResult(err), nil
+	}
+	return fmt.Sprintf("wrote %d bytes to %s", len(content), path), nil
+}
+
+// ListDirectoryTool is a tool listing the entries of a directory.
+type ListDirectoryTool struct {
+	fs *FS
+}
+
+// Name returns the name of the tool.
+func (t ListDirectoryTool) Name() string {
+	return "list_directory"
+}
+
+// Description returns a string describing the tool.
+func (t ListDirectoryTool) Description() string {
+	return `Lists the files and directories of a directory of the project, directories ending with "/". Use "." for the root of the project.` //nolint:lll
+}
+
+// Parameters returns the parameters of the input of the tool.
+func (t ListDirectoryTool) Parameters() []tools.Parameter {
+	return []tools.Parameter{_pathParameter}
+}
+
+// Call returns the entries of the directory, one per line, with the size of
+// the files. Failures are reported in the result.
+func (t ListDirectoryTool) Call(_ context.Context, input string) (string, error) {
+	values, err := tools.ValidateInput(t, input)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	listing, err := t.fs.ListDirectory(values["path"].(string))
+	if err != nil {
+		return errorResult(err), nil
+	}
+	if len(listing.Entries) == 0 {
+		return "the directory is empty", nil
+	}
+
+	var sb strings.Builder
+	for _, e := range listing.Entries {
+		if e.IsDir {
+			fmt.Fprintf(&sb, "%s/\n", e.Path)
+			continue
+		}
+		fmt.Fprintf(&sb, "%s (%d bytes)\n", e.Path, e.Size)
+	}
+	if listing.Truncated {
+		sb.WriteString("(more entries left out)\n")
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+// GrepSearchTool is a tool searching the lines of files matching a regular
+// expression.
+type GrepSearchTool struct {
+	fs *FS
+}
+
+// Name returns the name of the tool.
+func (t GrepSearchTool) Name() string {
+	return "grep_search"
+}
+
+// Description returns a string describing the tool.
+func (t GrepSearchTool) Description() string {
+	return "Searches the lines matching a regular expression in the text files of a directory of the project, or of a single file, and returns them with their path and line number." //nolint:lll
+}
+
+// Parameters returns the parameters of the input of the tool.
+func (t GrepSearchTool) Parameters() []tools.Parameter {
+	return []tools.Parameter{
+		{Name: "pattern", Type: tools.ParameterTypeString, Description: "The Go regular expression searched.", Required: true},
+		{Name: "path", Type: tools.ParameterTypeString, Description: `The directory or file searched. Defaults to ".".`},
+	}
+}
+
+// Call returns the matching lines as path:line:text, one per line. Failures
+// are reported in the result.
+func (t GrepSearchTool) Call(ctx context.Context, input string) (string, error) {
+	values, err := tools.ValidateInput(t, input)
+	if err != nil {
+		return errorResult(err), nil
+	}
+	path, _ := values["path"].(string)
+	if path == "" {
+		path = "."
+	}
+	result, err := t.fs.GrepSearch(ctx, values["pattern"].(string), path)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return errorResult(err), nil
+	}
+	if len(result.Matches) == 0 {
+		return "no matches found", nil
+	}
+
+	var sb strings.Builder
+	for _, m := range result.Matches {
+		fmt.Fprintf(&sb, "%s:%d:%s\n", m.Path, m.Line, m.Text)
+	}
+	if result.Truncated {
+		sb.WriteString("(more matches left out)\n")
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+// _pathParameter is the path of a file or directory from the root.
+var _pathParameter = tools.Parameter{ //nolint:gochecknoglobals
+	Name:        "path",
+	Type:        tools.ParameterTypeString,
+	Description: "The path from the root of the project, with forward slashes.",
+	Required:    true,
+}
+
+func errorResult(err error) string {
+	return "error: " + err.Error()
+}
@@ -0,0 +1,15 @@
+// Package httprequest contains a tool letting agents call HTTP APIs.
+//
+// Requests are limited to the hosts allowed by the developer, redirects
+// included, and to the GET, POST and PUT methods by default. As with the
+// webfetch tool, the addresses of private networks and cloud metadata
+// endpoints are refused once the hosts are resolved, unless allowed with
+// WithAllowPrivateNetworks. Headers, such as
+// authentication tokens, are configured by the developer for each host and
+// added when sending requests: agents can neither see nor set them.
+//
+// The input of the tool is a JSON object with the method, the url, an
+// optional JSON body and an optional JSONPath expression, such as
+// "$.items[*].name", selecting the part of a JSON response returned to keep
+// observations small.
+package httprequest
@@ -0,0 +1,255 @@
+package httprequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/tools/webfetch"
+)
+
+// _maxRedirects is the number of redirects followed.
+const _maxRedirects = 5
+
+var (
+	// ErrInvalidOptions is returned when creating a client without allowed
+	// hosts.
+	ErrInvalidOptions = errors.New("invalid options")
+	// ErrHostNotAllowed is returned when sending, or being redirected, to a
+	// host that is not allowed or with a scheme other than http and https.
+	ErrHostNotAllowed = errors.New("host not allowed")
+	// ErrMethodNotAllowed is returned when sending a request with a method
+	// that is not allowed.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+	// ErrTooManyRedirects is returned when a request redirects more times
+	// than followed.
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrBlockedAddress is returned when an allowed host resolves to a
+	// loopback, private, link-local or otherwise reserved address, unless
+	// private networks are allowed.
+	ErrBlockedAddress = webfetch.ErrBlockedAddress
+)
+
+// Request is a request sent by a Client.
+type Request struct {
+	Method string
+	URL    string
+	// Body is sent encoded in JSON, if not nil.
+	Body any
+}
+
+// Response is the response to a request.
+type Response struct {
+	StatusCode int
+	// Status is the status line, such as "200 OK".
+	Status      string
+	ContentType string
+	Body        []byte
+	// Truncated is whether the body was cut at the maximum body size.
+	Truncated bool
+}
+
+// header is a header added to the requests sent to the hosts matching a
+// pattern.
+type header struct {
+	host  string
+	name  string
+	value func(ctx context.Context) (string, error)
+}
+
+// Client sends requests given by agents to the hosts allowed by the
+// developer, adding the headers configured for them.
+type Client struct {
+	httpClient     *http.Client
+	allowedHosts   []string
+	allowedMethods []string
+	allowPrivate   bool
+	headers        []header
+	maxBodySize    int64
+	maxChars       int
+	timeout        time.Duration
+	userAgent      string
+}
+
+// NewClient creates a new client. WithAllowedHosts is required.
+func NewClient(opts ...Option) (*Client, error) {
+	c := &Client{
+		allowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut},
+		maxBodySize:    _defaultMaxBodySize,
+		maxChars:       _defaultMaxChars,
+		timeout:        _defaultTimeout,
+		userAgent:      _defaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(c.allowedHosts) == 0 {
+		return nil, fmt.Errorf("%w: no allowed hosts", ErrInvalidOptions)
+	}
+
+	httpClient := &http.Client{}
+	if c.httpClient != nil {
+		copied := *c.httpClient
+		httpClient = &copied
+	}
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = c.newTransport()
+	}
+	// The headers are added by the transport so that redirects to other hosts
+	// do not carry them.
+	httpClient.Transport = headerTransport{base: transport, client: c}
+	httpClient.Timeout = c.timeout
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > _maxRedirects {
+			return ErrTooManyRedirects
+		}
+		return c.checkURL(req.URL)
+	}
+	c.httpClient = httpClient
+	return c, nil
+}
+
+// newTransport returns the transport of the client, refusing to connect to
+// the addresses blocked by webfetch unless private networks are allowed.
+func (c *Client) newTransport() http.RoundTripper {
+	dialer := &net.Dialer{Timeout: c.timeout}
+	if !c.allowPrivate {
+		dialer.Control = webfetch.CheckDial
+	}
+	return &http.Transport{
+		// No proxy: the addresses dialed must be the ones checked.
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: c.timeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// Do sends the request and returns the response, whatever its status.
+func (c *Client) Do(ctx context.Context, r Request) (Response, error) {
+	method := strings.ToUpper(strings.TrimSpace(r.Method))
+	if !containsFold(c.allowedMethods, method) {
+		return Response{}, fmt.Errorf("%w: %q", ErrMethodNotAllowed, r.Method)
+	}
+	u, err := url.Parse(strings.TrimSpace(r.URL))
+	if err != nil {
+		return Response{}, err
+	}
+	if err := c.checkURL(u); err != nil {
+		return Response{}, err
+	}
+
+	var body io.Reader
+	if r.Body != nil {
+		b, err := json.Marshal(r.Body)
+		if err != nil {
+			return Response{}, fmt.Errorf("encoding body: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return Response{}, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json, */*;q=0.5")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, c.maxBodySize+1))
+	if err != nil {
+		return Response{}, err
+	}
+	response := Response{
+		StatusCode:  res.StatusCode,
+		Status:      res.Status,
+		ContentType: res.Header.Get("Content-Type"),
+		Body:        b,
+	}
+	if int64(len(b)) > c.maxBodySize {
+		response.Body, response.Truncated = b[:c.maxBodySize], true
+	}
+	return response, nil
+}
+
+// checkURL returns ErrHostNotAllowed if the scheme of the url is not http or
+// https or if its host is not allowed.
+func (c *Client) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q", ErrHostNotAllowed, u.Scheme)
+	}
+	for _, pattern := range c.allowedHosts {
+		if hostMatches(pattern, u) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrHostNotAllowed, u.Host)
+}
+
+// hostMatches reports whether the host of the url matches the pattern: a
+// host name, a host name and port, or *. followed by a domain matching its
+// subdomains.
+func hostMatches(pattern string, u *url.URL) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if _, _, err := net.SplitHostPort(pattern); err == nil {
+		return pattern == strings.ToLower(u.Host)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if domain, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+domain)
+	}
+	return host == pattern
+}
+
+// headerTransport adds the headers configured for the host of each request,
+// redirects included.
+type headerTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var cloned *http.Request
+	for _, h := range t.client.headers {
+		if !hostMatches(h.host, req.URL) {
+			continue
+		}
+		value, err := h.value(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", h.name, err)
+		}
+		if cloned == nil {
+			cloned = req.Clone(req.Context())
+		}
+		cloned.Header.Set(h.name, value)
+	}
+	if cloned == nil {
+		return t.base.RoundTrip(req)
+	}
+	return t.base.RoundTrip(cloned)
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package httprequest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, string) {
+	t.Helper()
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	return s, u.Host
+}
+
+func TestClientHeadersAndRedirects(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var otherAuth []string
+	other, otherHost := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		otherAuth = append(otherAuth, r.Header.Get("Authorization"))
+	})
+	_, apiHost := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echo":
+			body, _ := io.ReadAll(r.Body)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"method": r.Method,
+				"auth":   r.Header.Get("Authorization"),
+				"key":    r.Header.Get("X-Api-Key"),
+				"type":   r.Header.Get("Content-Type"),
+				"body":   string(body),
+			})
+		case "/other":
+			http.Redirect(w, r, strings.Replace(other.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+		case "/outside":
+			http.Redirect(w, r, "http://example.com/", http.StatusFound)
+		}
+	})
+	otherHost = strings.Replace(otherHost, "127.0.0.1", "localhost", 1)
+
+	c, err := NewClient(
+		WithAllowedHosts(apiHost, otherHost),
+		WithAllowPrivateNetworks(true),
+		WithBearerToken(apiHost, "secret"),
+		WithHeaderFunc(apiHost, "X-Api-Key", func(context.Context) (string, error) { return "key", nil }),
+	)
+	require.NoError(t, err)
+
+	res, err := c.Do(ctx, Request{Method: "post", URL: "http://" + apiHost + "/echo", Body: map[string]any{"a": 1}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.JSONEq(t, `{"method": "POST", "auth": "Bearer secret", "key": "key", "type": "application/json", "body": "{\"a\":1}"}`, string(res.Body)) //nolint:lll
+
+	_, err = c.Do(ctx, Request{Method: "GET", URL: "http://" + apiHost + "/other"})
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, otherAuth)
+
+	for r, want := range map[Request]error{
+		{Method: "GET", URL: "http://" + apiHost + "/outside"}: ErrHostNotAllowed,
+		{Method: "GET", URL: "http://example.com/"}:            ErrHostNotAllowed,
+		{Method: "GET", URL: "file:///etc/passwd"}:             ErrHostNotAllowed,
+		{Method: "DELETE", URL: "http://" + apiHost + "/echo"}: ErrMethodNotAllowed,
+	} {
+		_, err := c.Do(ctx, r)
+		require.ErrorIs(t, err, want, r.URL)
+	}
+
+	_, err = NewClient()
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestClientBlocksPrivateAddresses(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	_, host := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	_, port, err := net.SplitHostPort(host)
+	require.NoError(t, err)
+	localhost := net.JoinHostPort("localhost", port)
+
+	for _, opts := range [][]Option{
+		{WithAllowedHosts(host, localhost)},
+		{WithAllowedHosts(host, localhost), WithHTTPClient(&http.Client{})},
+	} {
+		c, err := NewClient(opts...)
+		require.NoError(t, err)
+		for _, h := range []string{host, localhost} {
+			// localhost is not an ip, it is blocked once resolved.
+			_, err := c.Do(ctx, Request{Method: "GET", URL: "http://" + h + "/"})
+			require.ErrorIs(t, err, ErrBlockedAddress, h)
+		}
+	}
+
+	c, err := NewClient(WithAllowedHosts(host), WithAllowPrivateNetworks(true))
+	require.NoError(t, err)
+	res, err := c.Do(ctx, Request{Method: "GET", URL: "http://" + host + "/"})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestHostMatches(t *testing.T) {
+	t.Parallel()
+
+	for pattern, hosts := range map[string]map[string]bool{
+		"api.example.com":      {"api.example.com": true, "API.example.com.": true, "example.com": false, "api.example.com:8080": true},
+		"*.example.com":        {"api.example.com": true, "a.b.example.com": true, "example.com": false, "evilexample.com": false},
+		"api.example.com:8080": {"api.example.com:8080": true, "api.example.com": false, "api.example.com:9090": false},
+	} {
+		for host, want := range hosts {
+			require.Equal(t, want, hostMatches(pattern, &url.URL{Host: host}), pattern+" "+host)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"items": [{"name": "a", "tags": ["x"]}, {"name": "b"}],
+		"meta": {"total count": 2, "next": null}
+	}`), &doc))
+
+	for path, want := range map[string]any{
+		"$":                        doc,
+		"$.items[0].name":          "a",
+		"items[-1].name":           "b",
+		"$.items[*].name":          []any{"a", "b"},
+		"$['meta']['total count']": float64(2),
+		`$.meta["next"]`:           nil,
+		"$.meta.*":                 []any{nil, float64(2)},
+		"$.items[*].tags[0]":       []any{"x"},
+		"$.missing[*]":             []any{},
+	} {
+		got, err := Extract(doc, path)
+		require.NoError(t, err, path)
+		require.Equal(t, want, got, path)
+	}
+
+	for path, want := range map[string]error{
+		"$.missing":  ErrNoMatch,
+		"$.items[5]": ErrNoMatch,
+		"$..name":    ErrInvalidPath,
+		"$.items[?]": ErrInvalidPath,
+		"$['open":    ErrInvalidPath,
+		"$.items[0":  ErrInvalidPath,
+	} {
+		_, err := Extract(doc, path)
+		require.ErrorIs(t, err, want, path)
+	}
+}
+
+func TestTool(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	_, host := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"id": 1, "title": "Otters"}, {"id": 2, "title": "Seals"}]}`))
+	})
+	tool, err := New(WithAllowedHosts(host), WithAllowPrivateNetworks(true), WithMaxChars(40))
+	require.NoError(t, err)
+	require.Contains(t, tool.Description(), host)
+
+	out, err := tool.Call(ctx, `{"method": "GET", "url": "http://`+host+`/search", "extract": "$.results[*].title"}`)
+	require.NoError(t, err)
+	require.Equal(t, "Status: 200 OK\n[\"Otters\",\"Seals\"]", out)
+
+	out, err = tool.Call(ctx, `{"method": "GET", "url": "http://`+host+`/search"}`)
+	require.NoError(t, err)
+	require.Equal(t, "Status: 200 OK\n{\"results\": [{\"id\": 1, \"t\n(response truncated)", out)
+
+	out, err = tool.Call(ctx, `{"method": "GET", "url": "http://`+host+`/missing", "extract": "$.id"}`)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out, "Status: 404 Not Found\nerror extracting \"$.id\": response is not JSON"), out)
+
+	out, err = tool.Call(ctx, `{"method": "GET", "url": "http://169.254.169.254/latest/meta-data/"}`)
+	require.NoError(t, err)
+	require.Equal(t, `request refused: host not allowed: "169.254.169.254"`, out)
+
+	out, err = tool.Call(ctx, `{"url": "http://`+host+`/search"}`)
+	require.NoError(t, err)
+	require.Equal(t, `invalid request: invalid tool input: missing required parameter "method"`, out)
+}
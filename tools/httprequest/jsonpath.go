@@ -0,0 +1,170 @@
+package httprequest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPath is returned when a JSONPath expression can not be parsed.
+	ErrInvalidPath = errors.New("invalid JSONPath expression")
+	// ErrNoMatch is returned when a JSONPath expression without wildcards
+	// selects no value.
+	ErrNoMatch = errors.New("no match for JSONPath expression")
+)
+
+type segmentKind int
+
+const (
+	segmentName segmentKind = iota
+	segmentIndex
+	segmentWildcard
+)
+
+type segment struct {
+	kind  segmentKind
+	name  string
+	index int
+}
+
+// Extract returns the value of the decoded JSON document selected by the
+// JSONPath expression. The expressions supported are made of the root $,
+// member names as in $.a.b or $['a b'], array indexes as in $.a[0], negative
+// indexes counting from the end, and the wildcards [*] and .* selecting all
+// the elements of an array or the values of an object. Expressions with
+// wildcards select a list of values, possibly empty.
+func Extract(doc any, path string) (any, error) {
+	segments, wildcard, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []any{doc}
+	for _, s := range segments {
+		var next []any
+		for _, n := range nodes {
+			next = append(next, s.apply(n)...)
+		}
+		nodes = next
+	}
+	if wildcard {
+		if nodes == nil {
+			nodes = []any{}
+		}
+		return nodes, nil
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrNoMatch, path)
+	}
+	return nodes[0], nil
+}
+
+// apply returns the values selected by the segment in the value.
+func (s segment) apply(v any) []any {
+	switch s.kind {
+	case segmentName:
+		if m, ok := v.(map[string]any); ok {
+			if child, ok := m[s.name]; ok {
+				return []any{child}
+			}
+		}
+	case segmentIndex:
+		if a, ok := v.([]any); ok {
+			i := s.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				return []any{a[i]}
+			}
+		}
+	case segmentWildcard:
+		switch v := v.(type) {
+		case []any:
+			return v
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			values := make([]any, 0, len(keys))
+			for _, k := range keys {
+				values = append(values, v[k])
+			}
+			return values
+		}
+	}
+	return nil
+}
+
+// parsePath returns the segments of the JSONPath expression and whether it
+// has wildcards. The leading $ may be left out.
+func parsePath(path string) ([]segment, bool, error) {
+	p := strings.TrimSpace(path)
+	switch {
+	case strings.HasPrefix(p, "$"):
+		p = p[1:]
+	case p != "" && p[0] != '.' && p[0] != '[':
+		p = "." + p
+	}
+
+	var segments []segment
+	wildcard := false
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %q: %s", ErrInvalidPath, path, reason)
+	}
+	for p != "" {
+		var s segment
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			name := p[:end]
+			p = p[end:]
+			switch name {
+			case "":
+				return nil, false, invalid("empty member name")
+			case "*":
+				s.kind = segmentWildcard
+			default:
+				s.name = name
+			}
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if len(p) > 1 && (p[1] == '\'' || p[1] == '"') {
+				closing := strings.IndexByte(p[2:], p[1])
+				if closing < 0 || len(p) < closing+4 || p[closing+3] != ']' {
+					return nil, false, invalid("unterminated member name")
+				}
+				s.name, end = p[2:closing+2], closing+3
+			} else {
+				if end < 0 {
+					return nil, false, invalid("missing ]")
+				}
+				selector := strings.TrimSpace(p[1:end])
+				if selector == "*" {
+					s.kind = segmentWildcard
+				} else {
+					index, err := strconv.Atoi(selector)
+					if err != nil {
+						return nil, false, invalid(fmt.Sprintf("unsupported selector %q", selector))
+					}
+					s.kind, s.index = segmentIndex, index
+				}
+			}
+			p = p[end+1:]
+		default:
+			return nil, false, invalid(fmt.Sprintf("unexpected %q", p[0]))
+		}
+		wildcard = wildcard || s.kind == segmentWildcard
+		segments = append(segments, s)
+	}
+	return segments, wildcard, nil
+}
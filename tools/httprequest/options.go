@@ -0,0 +1,109 @@
+package httprequest
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	_defaultMaxBodySize = 1 << 20
+	_defaultMaxChars    = 4000
+	_defaultTimeout     = 30 * time.Second
+	_defaultUserAgent   = "langchaingo-httprequest"
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(c *Client)
+
+// WithAllowedHosts is an option for setting the hosts requests may be sent
+// to. Hosts are names, such as "api.example.com", optionally with a port, or
+// patterns such as "*.example.com" matching the subdomains of a domain. It is
+// required.
+func WithAllowedHosts(hosts ...string) Option {
+	return func(c *Client) {
+		c.allowedHosts = append(c.allowedHosts, hosts...)
+	}
+}
+
+// WithAllowedMethods is an option for setting the methods of the requests.
+// Defaults to GET, POST and PUT.
+func WithAllowedMethods(methods ...string) Option {
+	return func(c *Client) {
+		c.allowedMethods = methods
+	}
+}
+
+// WithHeader is an option for adding a header to the requests sent to the
+// hosts matching the pattern, using the syntax of WithAllowedHosts.
+func WithHeader(host, name, value string) Option {
+	return WithHeaderFunc(host, name, func(context.Context) (string, error) {
+		return value, nil
+	})
+}
+
+// WithBearerToken is an option for authenticating the requests sent to the
+// hosts matching the pattern with a bearer token.
+func WithBearerToken(host, token string) Option {
+	return WithHeader(host, "Authorization", "Bearer "+token)
+}
+
+// WithHeaderFunc is an option for adding a header whose value is computed
+// for each request, such as a token that expires, to the requests sent to the
+// hosts matching the pattern.
+func WithHeaderFunc(host, name string, value func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) {
+		c.headers = append(c.headers, header{host: host, name: name, value: value})
+	}
+}
+
+// WithMaxBodySize is an option for setting the size of the response bodies
+// read, in bytes, beyond which they are truncated. Defaults to 1 MiB.
+func WithMaxBodySize(size int64) Option {
+	return func(c *Client) {
+		c.maxBodySize = size
+	}
+}
+
+// WithMaxChars is an option for setting the number of characters the
+// observations of the tool are truncated to. Defaults to 4000.
+func WithMaxChars(n int) Option {
+	return func(c *Client) {
+		c.maxChars = n
+	}
+}
+
+// WithTimeout is an option for setting the timeout of requests. Defaults to
+// 30 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithAllowPrivateNetworks is an option for allowing the allowed hosts to
+// resolve to addresses of private networks, loopback and link-local ones
+// included, such as an API running on the same machine. They are refused by
+// default, as webfetch does.
+func WithAllowPrivateNetworks(allow bool) Option {
+	return func(c *Client) {
+		c.allowPrivate = allow
+	}
+}
+
+// WithHTTPClient is an option for setting the http client sending the
+// requests. Its redirect policy and timeout are replaced by the ones of the
+// client. Its transport, if set, is used as is: the addresses it connects to
+// are not checked, see WithAllowPrivateNetworks.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithUserAgent is an option for setting the user agent of the requests.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
@@ -0,0 +1,117 @@
+package httprequest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+)
+
+// Tool is a tool letting agents send requests to HTTP APIs with a Client.
+type Tool struct {
+	client *Client
+}
+
+var _ tools.StructuredTool = Tool{}
+
+// New creates a new http request tool. The options are the ones of the
+// client, see NewClient.
+func New(opts ...Option) (Tool, error) {
+	c, err := NewClient(opts...)
+	if err != nil {
+		return Tool{}, err
+	}
+	return Tool{client: c}, nil
+}
+
+// Name returns the name of the tool.
+func (t Tool) Name() string {
+	return "http_request"
+}
+
+// Description returns a string describing the tool, with the hosts it may
+// send requests to.
+func (t Tool) Description() string {
+	return fmt.Sprintf(`Sends an HTTP request to an API and returns the status and body of the response. Authentication is added automatically. Allowed hosts: %s. Use extract to select the part of a JSON response you need.`, //nolint:lll
+		strings.Join(t.client.allowedHosts, ", "))
+}
+
+// Parameters returns the parameters of the input of the tool.
+func (t Tool) Parameters() []tools.Parameter {
+	return []tools.Parameter{
+		{
+			Name:        "method",
+			Type:        tools.ParameterTypeString,
+			Description: "The HTTP method, one of " + strings.Join(t.client.allowedMethods, ", ") + ".",
+			Required:    true,
+		},
+		{Name: "url", Type: tools.ParameterTypeString, Description: "The url of the request.", Required: true},
+		{Name: "body", Type: tools.ParameterTypeObject, Description: "The JSON body of the request, if any."},
+		{
+			Name:        "extract",
+			Type:        tools.ParameterTypeString,
+			Description: `A JSONPath expression selecting the part of the JSON response returned, such as "$.items[*].name".`,
+		},
+	}
+}
+
+// Call sends the request and returns the status and body of the response,
+// or the part of its body selected by the JSONPath expression. Refused and
+// failed requests are reported in the result to let the agent fix them.
+func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	values, err := tools.ValidateInput(t, input)
+	if err != nil {
+		return fmt.Sprintf("invalid request: %s", err.Error()), nil
+	}
+	method, _ := values["method"].(string)
+	rawURL, _ := values["url"].(string)
+	extract, _ := values["extract"].(string)
+
+	res, err := t.client.Do(ctx, Request{Method: method, URL: rawURL, Body: values["body"]})
+	switch {
+	case ctx.Err() != nil:
+		return "", ctx.Err()
+	case errors.Is(err, ErrHostNotAllowed), errors.Is(err, ErrMethodNotAllowed):
+		return fmt.Sprintf("request refused: %s", err.Error()), nil
+	case err != nil:
+		return fmt.Sprintf("request failed: %s", err.Error()), nil
+	}
+
+	body := strings.TrimSpace(string(res.Body))
+	if extract != "" {
+		body, err = extractJSON(res.Body, extract)
+		if err != nil {
+			if res.Truncated {
+				err = fmt.Errorf("%w (the response was truncated)", err)
+			}
+			return fmt.Sprintf("Status: %s\nerror extracting %q: %s", res.Status, extract, err.Error()), nil
+		}
+	}
+
+	out := fmt.Sprintf("Status: %s\n%s", res.Status, body)
+	if runes := []rune(out); t.client.maxChars > 0 && len(runes) > t.client.maxChars {
+		out = string(runes[:t.client.maxChars]) + "\n(response truncated)"
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// extractJSON returns the part of the JSON document selected by the JSONPath
+// expression, encoded in JSON.
+func extractJSON(data []byte, path string) (string, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("response is not JSON: %w", err)
+	}
+	v, err := Extract(doc, path)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
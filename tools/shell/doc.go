@@ -0,0 +1,14 @@
+// Package shell contains a tool running commands on the local machine for
+// agents, such as ops assistants inspecting a system.
+//
+// Commands are not run by a shell: a command line is split into a program and
+// its arguments, and pipes, redirections, substitutions and other shell syntax
+// are refused. Only the programs of an allowlist may run, in a working
+// directory which the path arguments of the commands may not leave, with a
+// timeout and a maximum output size. An approval hook can ask a human to
+// confirm each command before it runs.
+//
+// The working directory jail checks the arguments of commands, not the files
+// the programs open, so the allowlist should only hold programs which do not
+// read paths from other sources, such as ls, cat, grep, df or uptime.
+package shell
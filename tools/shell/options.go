@@ -0,0 +1,62 @@
+package shell
+
+import "time"
+
+const (
+	_defaultTimeout        = 30 * time.Second
+	_defaultMaxOutputBytes = 16 << 10
+)
+
+// Option is a function type that can be used to modify the shell.
+type Option func(s *Shell)
+
+// WithAllowedCommands is an option for adding programs commands may run, by
+// the name they are called with, such as "ls" or "git". Must be set.
+func WithAllowedCommands(names ...string) Option {
+	return func(s *Shell) {
+		for _, name := range names {
+			s.allowed[name] = true
+		}
+	}
+}
+
+// WithWorkDir is an option for setting the directory commands run in, which
+// their path arguments may not leave. Defaults to the current directory.
+func WithWorkDir(dir string) Option {
+	return func(s *Shell) {
+		s.dir = dir
+	}
+}
+
+// WithTimeout is an option for setting the time commands may run. Defaults to
+// 30 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Shell) {
+		s.timeout = timeout
+	}
+}
+
+// WithMaxOutputBytes is an option for setting the size at which the output of
+// commands is cut. Defaults to 16 KiB.
+func WithMaxOutputBytes(n int) Option {
+	return func(s *Shell) {
+		s.maxOutputBytes = n
+	}
+}
+
+// WithApproval is an option for setting a function called before running each
+// command, which runs only if it returns true, such as one asking a human to
+// confirm it.
+func WithApproval(approve ApprovalFunc) Option {
+	return func(s *Shell) {
+		s.approve = approve
+	}
+}
+
+// WithEnv is an option for setting the environment of commands, as key=value
+// strings. Defaults to the environment of the process.
+func WithEnv(env []string) Option {
+	return func(s *Shell) {
+		s.env = env
+	}
+}
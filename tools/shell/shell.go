@@ -0,0 +1,326 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// _waitDelay is the time given to the programs started by a command to close
+// its output once it is killed.
+const _waitDelay = 2 * time.Second
+
+var (
+	// ErrInvalidOptions is returned when the options given are invalid.
+	ErrInvalidOptions = errors.New("invalid options")
+	// ErrEmptyCommand is returned when running an empty command line.
+	ErrEmptyCommand = errors.New("empty command")
+	// ErrUnsupportedSyntax is returned when a command line has shell syntax,
+	// such as pipes, redirections or substitutions, or unbalanced quotes.
+	ErrUnsupportedSyntax = errors.New("unsupported shell syntax")
+	// ErrCommandNotAllowed is returned when running a program which is not in
+	// the allowlist.
+	ErrCommandNotAllowed = errors.New("command not allowed")
+	// ErrOutsideWorkDir is returned when a path argument of a command is
+	// outside the working directory.
+	ErrOutsideWorkDir = errors.New("path outside the working directory")
+	// ErrNotApproved is returned when the approval function refuses a command.
+	ErrNotApproved = errors.New("command not approved")
+)
+
+// Command is a command about to run.
+type Command struct {
+	// Name is the program run.
+	Name string
+	Args []string
+	// Dir is the working directory of the command.
+	Dir string
+}
+
+// String returns the command line of the command.
+func (c Command) String() string {
+	words := make([]string, 0, len(c.Args)+1)
+	for _, w := range append([]string{c.Name}, c.Args...) {
+		if w == "" || strings.ContainsAny(w, " \t\n'\"\\") {
+			w = "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " ")
+}
+
+// ApprovalFunc decides whether a command may run.
+type ApprovalFunc func(ctx context.Context, cmd Command) (bool, error)
+
+// Result is the outcome of running a command.
+type Result struct {
+	// Output is the standard output and error of the command, interleaved.
+	Output   string
+	ExitCode int
+	// TimedOut is whether the command was killed at the timeout.
+	TimedOut bool
+	// Truncated is whether the output was cut.
+	Truncated bool
+}
+
+// Shell runs the commands of an allowlist in a working directory.
+type Shell struct {
+	allowed        map[string]bool
+	dir            string
+	timeout        time.Duration
+	maxOutputBytes int
+	approve        ApprovalFunc
+	env            []string
+}
+
+// NewShell creates a new shell with options. At least one command must be
+// allowed.
+func NewShell(opts ...Option) (*Shell, error) {
+	s := &Shell{
+		allowed:        map[string]bool{},
+		timeout:        _defaultTimeout,
+		maxOutputBytes: _defaultMaxOutputBytes,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if len(s.allowed) == 0 {
+		return nil, fmt.Errorf("%w: no allowed commands", ErrInvalidOptions)
+	}
+	if s.dir == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		s.dir = dir
+	}
+	dir, err := filepath.Abs(s.dir)
+	if err == nil {
+		dir, err = filepath.EvalSymlinks(dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: working directory: %w", ErrInvalidOptions, err)
+	}
+	s.dir = dir
+	return s, nil
+}
+
+// Run parses the command line, checks that its program is allowed and that
+// its path arguments are in the working directory, asks for its approval and
+// runs it. A command failing, or timing out, is a result with an error exit
+// code, not an error.
+func (s *Shell) Run(ctx context.Context, commandLine string) (Result, error) {
+	words, err := splitWords(commandLine)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(words) == 0 {
+		return Result{}, ErrEmptyCommand
+	}
+	cmd := Command{Name: words[0], Args: words[1:], Dir: s.dir}
+	if !s.allowed[cmd.Name] {
+		return Result{}, fmt.Errorf("%w: %q", ErrCommandNotAllowed, cmd.Name)
+	}
+	for _, arg := range cmd.Args {
+		if err := s.checkPath(arg); err != nil {
+			return Result{}, err
+		}
+	}
+	if s.approve != nil {
+		ok, err := s.approve(ctx, cmd)
+		if err != nil {
+			return Result{}, fmt.Errorf("approval: %w", err)
+		}
+		if !ok {
+			return Result{}, fmt.Errorf("%w: %s", ErrNotApproved, cmd)
+		}
+	}
+	return s.run(ctx, cmd)
+}
+
+func (s *Shell) run(ctx context.Context, command Command) (Result, error) {
+	runCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, command.Name, command.Args...) //nolint:gosec
+	cmd.Dir = command.Dir
+	cmd.Env = s.env
+	output := &limitedBuffer{limit: s.maxOutputBytes}
+	cmd.Stdout, cmd.Stderr = output, output
+	cmd.WaitDelay = _waitDelay
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return Result{}, ctx.Err()
+	}
+
+	result := Result{
+		Output:    output.String(),
+		TimedOut:  errors.Is(runCtx.Err(), context.DeadlineExceeded),
+		Truncated: output.truncated,
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case result.TimedOut:
+		result.ExitCode = -1
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return Result{}, fmt.Errorf("run %s: %w", command.Name, err)
+	}
+	return result, nil
+}
+
+// checkPath returns ErrOutsideWorkDir if the argument, the value of a
+// --flag=value argument, or a path attached to the short options of a -flags
+// argument, such as in -f/etc/passwd or -xf../archive.tar, is a path outside
+// the working directory once relative paths and symbolic links are resolved.
+func (s *Shell) checkPath(arg string) error {
+	switch {
+	case strings.HasPrefix(arg, "--"):
+		_, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil
+		}
+		return s.checkPathValue(arg, value)
+	case strings.HasPrefix(arg, "-"):
+		// The options taking a value may be followed by any of the others,
+		// so the path may start after any of them.
+		for i := range arg {
+			if i > 0 {
+				if err := s.checkPathValue(arg, arg[i:]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	default:
+		return s.checkPathValue(arg, arg)
+	}
+}
+
+// checkPathValue returns ErrOutsideWorkDir, reporting the argument, if the
+// value is a path outside the working directory.
+func (s *Shell) checkPathValue(arg, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	path := value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.dir, path)
+	}
+	path = resolveSymlinks(filepath.Clean(path))
+	if rel, err := filepath.Rel(s.dir, path); err != nil || rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %q", ErrOutsideWorkDir, arg)
+	}
+	return nil
+}
+
+// resolveSymlinks returns the path with the symbolic links of its longest
+// existing prefix resolved.
+func resolveSymlinks(path string) string {
+	rest := ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest)
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+// splitWords splits a command line into words like a POSIX shell does, with
+// single and double quotes and backslash escapes, refusing the unquoted
+// characters of other shell syntax.
+func splitWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			case '$', '`':
+				return nil, fmt.Errorf("%w: %q", ErrUnsupportedSyntax, r)
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case strings.ContainsRune("|&;<>()$`*?[]{}\n\r", r):
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedSyntax, r)
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("%w: unterminated quote or escape", ErrUnsupportedSyntax)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// limitedBuffer is a buffer keeping the first bytes written up to its limit.
+// Used as both the standard output and error of a command, it is written by
+// one goroutine at a time.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,148 @@
+package shell
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestShell(t *testing.T, opts ...Option) *Shell {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("otters\n"), 0o600))
+	require.NoError(t, os.Symlink("/etc", filepath.Join(dir, "etc")))
+
+	s, err := NewShell(append([]Option{
+		WithAllowedCommands("echo", "cat", "sleep"),
+		WithWorkDir(dir),
+	}, opts...)...)
+	require.NoError(t, err)
+	return s
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s := newTestShell(t)
+
+	result, err := s.Run(ctx, `echo 'hello   world' "a \"b\"" c\ d`)
+	require.NoError(t, err)
+	require.Equal(t, Result{Output: "hello   world a \"b\" c d\n"}, result)
+
+	result, err = s.Run(ctx, "cat notes.txt ./notes.txt")
+	require.NoError(t, err)
+	require.Equal(t, "otters\notters\n", result.Output)
+
+	result, err = s.Run(ctx, "cat missing.txt")
+	require.NoError(t, err)
+	require.Equal(t, 1, result.ExitCode)
+	require.Contains(t, result.Output, "missing.txt")
+
+	for line, want := range map[string]error{
+		"":                      ErrEmptyCommand,
+		"rm -rf notes.txt":      ErrCommandNotAllowed,
+		"/bin/echo hi":          ErrCommandNotAllowed,
+		"cat notes.txt | wc":    ErrUnsupportedSyntax,
+		"echo $HOME":            ErrUnsupportedSyntax,
+		`echo "$(id)"`:          ErrUnsupportedSyntax,
+		"cat *.txt":             ErrUnsupportedSyntax,
+		"echo 'open":            ErrUnsupportedSyntax,
+		"cat /etc/passwd":       ErrOutsideWorkDir,
+		"cat ../notes.txt":      ErrOutsideWorkDir,
+		"cat etc/passwd":        ErrOutsideWorkDir,
+		"cat --file=/etc/hosts": ErrOutsideWorkDir,
+	} {
+		_, err := s.Run(ctx, line)
+		require.ErrorIs(t, err, want, line)
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+	t.Parallel()
+	s := newTestShell(t)
+
+	cases := []struct {
+		arg     string
+		outside bool
+	}{
+		{"notes.txt", false},
+		{"-la", false},
+		{"-n5", false},
+		{"-fnotes.txt", false},
+		{"--color=auto", false},
+		{"--verbose", false},
+		{"-f/etc/shadow", true},
+		{"-C/etc", true},
+		{"-C/", true},
+		{"-xf/etc/passwd", true},
+		{"-f../notes.txt", true},
+		{"-Ietc", true},
+		{"-o=/tmp/out", true},
+		{"--file=/etc/hosts", true},
+		{"--file=../notes.txt", true},
+	}
+	for _, tc := range cases {
+		err := s.checkPath(tc.arg)
+		if tc.outside {
+			require.ErrorIs(t, err, ErrOutsideWorkDir, tc.arg)
+		} else {
+			require.NoError(t, err, tc.arg)
+		}
+	}
+}
+
+func TestRunLimits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s := newTestShell(t, WithTimeout(100*time.Millisecond), WithMaxOutputBytes(5))
+
+	result, err := s.Run(ctx, "sleep 5")
+	require.NoError(t, err)
+	require.True(t, result.TimedOut)
+	require.Equal(t, -1, result.ExitCode)
+
+	result, err = s.Run(ctx, "echo 0123456789")
+	require.NoError(t, err)
+	require.True(t, result.Truncated)
+	require.Equal(t, "01234", result.Output)
+}
+
+func TestRunApproval(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var asked []string
+	s := newTestShell(t, WithApproval(func(_ context.Context, cmd Command) (bool, error) {
+		asked = append(asked, cmd.String())
+		return cmd.Name == "echo", nil
+	}))
+
+	_, err := s.Run(ctx, "echo 'a b'")
+	require.NoError(t, err)
+	_, err = s.Run(ctx, "cat notes.txt")
+	require.ErrorIs(t, err, ErrNotApproved)
+	require.Equal(t, []string{"echo 'a b'", "cat notes.txt"}, asked)
+
+	_, err = NewShell()
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestTool(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	tool := Tool{shell: newTestShell(t)}
+	require.Contains(t, tool.Description(), "cat, echo, sleep")
+
+	out, err := tool.Call(ctx, "cat notes.txt\n")
+	require.NoError(t, err)
+	require.Equal(t, "Exit code: 0\nOutput:\notters", out)
+
+	out, err = tool.Call(ctx, "rm notes.txt")
+	require.NoError(t, err)
+	require.Equal(t, `command refused: command not allowed: "rm"`, out)
+}
@@ -0,0 +1,73 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+)
+
+// Tool is a tool running commands on the local machine with a Shell.
+type Tool struct {
+	shell *Shell
+}
+
+var _ tools.Tool = Tool{}
+
+// New creates a new shell tool. The options are the ones of the shell, see
+// NewShell.
+func New(opts ...Option) (Tool, error) {
+	s, err := NewShell(opts...)
+	if err != nil {
+		return Tool{}, err
+	}
+	return Tool{shell: s}, nil
+}
+
+// Name returns the name of the tool.
+func (t Tool) Name() string {
+	return "Shell"
+}
+
+// Description returns a string describing the tool, with the commands it may
+// run.
+func (t Tool) Description() string {
+	allowed := make([]string, 0, len(t.shell.allowed))
+	for name := range t.shell.allowed {
+		allowed = append(allowed, name)
+	}
+	sort.Strings(allowed)
+	return fmt.Sprintf(`Runs a command on the local machine and returns its output. Useful for inspecting files and the state of the system. The input should be a single command line using one of these programs: %s. Pipes, redirections, variables and wildcards are not supported. Paths must be inside the working directory.`, //nolint:lll
+		strings.Join(allowed, ", "))
+}
+
+// Call runs the command line and returns its exit code and output. Commands
+// refused by the shell are reported in the result to let the agent try
+// another one.
+func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	result, err := t.shell.Run(ctx, strings.TrimSpace(input))
+	switch {
+	case errors.Is(err, ErrEmptyCommand), errors.Is(err, ErrUnsupportedSyntax),
+		errors.Is(err, ErrCommandNotAllowed), errors.Is(err, ErrOutsideWorkDir),
+		errors.Is(err, ErrNotApproved):
+		return fmt.Sprintf("command refused: %s", err.Error()), nil
+	case err != nil:
+		return "", err
+	}
+
+	var sb strings.Builder
+	if result.TimedOut {
+		sb.WriteString("The command timed out.\n")
+	}
+	fmt.Fprintf(&sb, "Exit code: %d\n", result.ExitCode)
+	if result.Output != "" {
+		fmt.Fprintf(&sb, "Output:\n%s\n", strings.TrimRight(result.Output, "\n"))
+	}
+	if result.Truncated {
+		sb.WriteString("(output truncated)\n")
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
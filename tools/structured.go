@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// ErrInvalidInput is returned when the input of a structured tool does not
+// match its parameters.
+var ErrInvalidInput = errors.New("invalid tool input")
+
+// ParameterType is the JSON schema type of a parameter of a structured tool.
+type ParameterType string
+
+const (
+	ParameterTypeString  ParameterType = "string"
+	ParameterTypeNumber  ParameterType = "number"
+	ParameterTypeInteger ParameterType = "integer"
+	ParameterTypeBoolean ParameterType = "boolean"
+	ParameterTypeArray   ParameterType = "array"
+	ParameterTypeObject  ParameterType = "object"
+)
+
+// Parameter describes a field of the JSON object input of a structured tool.
+type Parameter struct {
+	Name        string
+	Type        ParameterType
+	Description string
+	Required    bool
+}
+
+// StructuredTool is a tool whose input is a JSON object with the parameters.
+// Agents include the schema of the input in their prompts, and executors
+// validate the input before calling the tool.
+type StructuredTool interface {
+	Tool
+	Parameters() []Parameter
+}
+
+// InputJSONSchema returns the JSON schema of the input of the tool.
+func InputJSONSchema(t StructuredTool) map[string]any {
+	params := t.Parameters()
+	properties := make(map[string]any, len(params))
+	required := make([]string, 0, len(params))
+	for _, p := range params {
+		property := map[string]any{"type": string(p.Type)}
+		if p.Description != "" {
+			property["description"] = p.Description
+		}
+		properties[p.Name] = property
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// FunctionDefinition returns the definition of the tool as a function of an
+// llm. Tools that are not structured take a single "input" string.
+func FunctionDefinition(t Tool) llms.FunctionDefinition {
+	parameters := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"input": map[string]any{"type": "string"}},
+		"required":   []string{"input"},
+	}
+	if st, ok := t.(StructuredTool); ok {
+		parameters = InputJSONSchema(st)
+	}
+	return llms.FunctionDefinition{
+		Name:        t.Name(),
+		Description: t.Description(),
+		Parameters:  parameters,
+	}
+}
+
+// ValidateInput parses the input of the tool and checks it against its
+// parameters: the input must be a JSON object with the required parameters,
+// and the parameters given must have the right types. Errors wrap
+// ErrInvalidInput.
+func ValidateInput(t StructuredTool, input string) (map[string]any, error) {
+	var values map[string]any
+	if err := json.Unmarshal([]byte(input), &values); err != nil || values == nil {
+		return nil, fmt.Errorf("%w: input must be a JSON object", ErrInvalidInput)
+	}
+	for _, p := range t.Parameters() {
+		v, ok := values[p.Name]
+		if !ok {
+			if p.Required {
+				return nil, fmt.Errorf("%w: missing required parameter %q", ErrInvalidInput, p.Name)
+			}
+			continue
+		}
+		if !hasType(v, p.Type) {
+			return nil, fmt.Errorf("%w: parameter %q must be of type %s", ErrInvalidInput, p.Name, p.Type)
+		}
+	}
+	return values, nil
+}
+
+// hasType reports whether the value decoded from JSON has the type.
+func hasType(v any, t ParameterType) bool {
+	switch t {
+	case ParameterTypeString:
+		_, ok := v.(string)
+		return ok
+	case ParameterTypeNumber:
+		_, ok := v.(float64)
+		return ok
+	case ParameterTypeInteger:
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case ParameterTypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case ParameterTypeArray:
+		return v != nil && reflect.TypeOf(v).Kind() == reflect.Slice
+	case ParameterTypeObject:
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return true
+}
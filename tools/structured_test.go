@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type searchTool struct{}
+
+func (searchTool) Name() string        { return "search" }
+func (searchTool) Description() string { return "searches the web" }
+
+func (searchTool) Call(_ context.Context, input string) (string, error) {
+	return input, nil
+}
+
+func (searchTool) Parameters() []Parameter {
+	return []Parameter{
+		{Name: "query", Type: ParameterTypeString, Description: "the search query", Required: true},
+		{Name: "limit", Type: ParameterTypeInteger},
+	}
+}
+
+func TestInputJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{"type": "string", "description": "the search query"},
+			"limit": map[string]any{"type": "integer"},
+		},
+		"required": []string{"query"},
+	}, InputJSONSchema(searchTool{}))
+
+	require.Equal(t, InputJSONSchema(searchTool{}), FunctionDefinition(searchTool{}).Parameters)
+	require.Equal(t, "calculator", FunctionDefinition(Calculator{}).Name)
+}
+
+func TestValidateInput(t *testing.T) {
+	t.Parallel()
+
+	values, err := ValidateInput(searchTool{}, `{"query": "otters", "limit": 3}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"query": "otters", "limit": float64(3)}, values)
+
+	for _, input := range []string{
+		`otters`,
+		`null`,
+		`{"limit": 3}`,
+		`{"query": 3}`,
+		`{"query": "otters", "limit": 1.5}`,
+	} {
+		_, err := ValidateInput(searchTool{}, input)
+		require.ErrorIs(t, err, ErrInvalidInput, input)
+	}
+}
@@ -8,3 +8,11 @@ type Tool interface {
 	Description() string
 	Call(context.Context, string) (string, error)
 }
+
+// Interrupter is implemented by tools whose work can be interrupted, such as
+// tools starting jobs on remote servers. The agent executor calls Interrupt
+// when the context of a call to the tool is canceled, for example when the run
+// is stopped with chains.RunManager.Stop.
+type Interrupter interface {
+	Interrupt(ctx context.Context) error
+}
@@ -0,0 +1,9 @@
+// Package transcribe transcribes audio files and streams of any length with a
+// speech to text model, such as the whisper model of the openai package, and
+// contains a tool letting agents transcribe the audio files of a directory.
+//
+// Speech to text APIs limit the size of the audio they are sent, 25 MB for
+// the OpenAI API. Longer WAV and MP3 audio is split in chunks transcribed one
+// after the other, WAV audio at sample boundaries and MP3 audio at frame
+// boundaries. Audio in other formats must fit in a chunk.
+package transcribe
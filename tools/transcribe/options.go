@@ -0,0 +1,32 @@
+package transcribe
+
+// _defaultMaxChunkSize is below the 25 MB limit of the OpenAI API.
+const _defaultMaxChunkSize = 24 << 20
+
+// Option is a function type that can be used to modify the transcription.
+type Option func(o *options)
+
+type options struct {
+	maxChunkSize int
+	translate    bool
+}
+
+func defaultOptions() options {
+	return options{maxChunkSize: _defaultMaxChunkSize}
+}
+
+// WithMaxChunkSize is an option for setting the size of the largest chunk of
+// audio sent to the model, in bytes. Defaults to 24 MiB.
+func WithMaxChunkSize(size int) Option {
+	return func(o *options) {
+		o.maxChunkSize = size
+	}
+}
+
+// WithTranslation is an option for transcribing the audio to English text,
+// whatever the language spoken. The transcriber must implement Translator.
+func WithTranslation(translate bool) Option {
+	return func(o *options) {
+		o.translate = translate
+	}
+}
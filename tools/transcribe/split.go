@@ -0,0 +1,128 @@
+package transcribe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrAudioTooLarge is returned when audio larger than the maximum chunk
+	// size is in a format that can not be split.
+	ErrAudioTooLarge = errors.New("audio too large")
+	// ErrInvalidAudio is returned when WAV audio can not be parsed.
+	ErrInvalidAudio = errors.New("invalid audio")
+)
+
+// _wavHeaderSize is the size of the RIFF header of WAV files.
+const _wavHeaderSize = 12
+
+// Split splits the audio in chunks of at most maxSize bytes, each a valid
+// audio file of the format of the audio. Audio smaller than maxSize is
+// returned as is. WAV audio is split at sample boundaries, each chunk having
+// the header of the audio, and MP3 audio at frame boundaries. Audio in other
+// formats larger than maxSize makes Split fail with ErrAudioTooLarge.
+func Split(audio []byte, maxSize int) ([][]byte, error) {
+	if len(audio) <= maxSize {
+		return [][]byte{audio}, nil
+	}
+	switch {
+	case isWAV(audio):
+		return splitWAV(audio, maxSize)
+	case isMP3(audio):
+		return splitMP3(audio, maxSize), nil
+	}
+	return nil, fmt.Errorf("%w: %d bytes in a format that can not be split, the maximum is %d",
+		ErrAudioTooLarge, len(audio), maxSize)
+}
+
+func isWAV(audio []byte) bool {
+	return len(audio) >= _wavHeaderSize &&
+		bytes.Equal(audio[:4], []byte("RIFF")) && bytes.Equal(audio[8:12], []byte("WAVE"))
+}
+
+func isMP3(audio []byte) bool {
+	return bytes.HasPrefix(audio, []byte("ID3")) || isFrameSync(audio, 0)
+}
+
+// isFrameSync reports whether an MP3 frame starts at i.
+func isFrameSync(audio []byte, i int) bool {
+	return i+1 < len(audio) && audio[i] == 0xFF && audio[i+1]&0xE0 == 0xE0
+}
+
+// splitWAV splits WAV audio in chunks made of the header of the audio, with
+// the sizes of the chunk, and of whole samples.
+func splitWAV(audio []byte, maxSize int) ([][]byte, error) {
+	var (
+		format     []byte
+		samples    []byte
+		blockAlign int
+	)
+	for i := _wavHeaderSize; i+8 <= len(audio); {
+		id := string(audio[i : i+4])
+		size := int(binary.LittleEndian.Uint32(audio[i+4 : i+8]))
+		end := i + 8 + size
+		if end > len(audio) || end < i {
+			end = len(audio)
+		}
+		switch id {
+		case "fmt ":
+			format = audio[i:end]
+			if len(format) >= 8+14 {
+				blockAlign = int(binary.LittleEndian.Uint16(format[8+12:]))
+			}
+		case "data":
+			samples = audio[i+8 : end]
+		}
+		i = end + size%2
+	}
+	if format == nil || samples == nil || blockAlign == 0 {
+		return nil, fmt.Errorf("%w: WAV audio without format or data", ErrInvalidAudio)
+	}
+
+	headerSize := _wavHeaderSize + len(format) + 8
+	chunkSamples := (maxSize - headerSize) / blockAlign * blockAlign
+	if chunkSamples <= 0 {
+		return nil, fmt.Errorf("%w: chunks of %d bytes can not hold a WAV header", ErrAudioTooLarge, maxSize)
+	}
+
+	var chunks [][]byte
+	for start := 0; start < len(samples); start += chunkSamples {
+		data := samples[start:minInt(start+chunkSamples, len(samples))]
+		chunk := make([]byte, 0, headerSize+len(data))
+		chunk = append(chunk, "RIFF"...)
+		chunk = binary.LittleEndian.AppendUint32(chunk, uint32(headerSize-8+len(data)))
+		chunk = append(chunk, "WAVE"...)
+		chunk = append(chunk, format...)
+		chunk = append(chunk, "data"...)
+		chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(data)))
+		chunks = append(chunks, append(chunk, data...))
+	}
+	return chunks, nil
+}
+
+// splitMP3 splits MP3 audio before the last frame starting within maxSize
+// bytes of the start of each chunk.
+func splitMP3(audio []byte, maxSize int) [][]byte {
+	var chunks [][]byte
+	for len(audio) > maxSize {
+		cut := maxSize
+		for i := maxSize - 1; i > 0; i-- {
+			if isFrameSync(audio, i) {
+				cut = i
+				break
+			}
+		}
+		chunks = append(chunks, audio[:cut])
+		audio = audio[cut:]
+	}
+	return append(chunks, audio)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,78 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+)
+
+// ErrOutsideRoot is returned when the tool is given a path leaving its root
+// directory.
+var ErrOutsideRoot = errors.New("path outside the root directory")
+
+// Tool is a tool letting agents transcribe the audio files under a root
+// directory.
+type Tool struct {
+	transcriber Transcriber
+	root        string
+	opts        []Option
+}
+
+var _ tools.Tool = Tool{}
+
+// New creates a new transcription tool transcribing the audio files under the
+// root directory with the transcriber and options.
+func New(t Transcriber, root string, opts ...Option) (Tool, error) {
+	abs, err := filepath.Abs(root)
+	if err == nil {
+		abs, err = filepath.EvalSymlinks(abs)
+	}
+	if err != nil {
+		return Tool{}, fmt.Errorf("root directory: %w", err)
+	}
+	return Tool{transcriber: t, root: abs, opts: opts}, nil
+}
+
+// Name returns the name of the tool.
+func (t Tool) Name() string {
+	return "transcribe_audio"
+}
+
+// Description returns a string describing the tool.
+func (t Tool) Description() string {
+	return `Transcribes speech in an audio file to text. The input should be the path of the audio file, such as "meetings/monday.mp3".` //nolint:lll
+}
+
+// Call transcribes the audio file. Errors, including files outside the root
+// directory, are returned as the output so that the agent can recover.
+func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	path, err := t.resolve(strings.Trim(strings.TrimSpace(input), `"'`))
+	if err == nil {
+		var text string
+		text, err = TranscribeFile(ctx, t.transcriber, path, t.opts...)
+		if err == nil {
+			return text, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return fmt.Sprintf("error transcribing audio: %s", strings.ReplaceAll(err.Error(), t.root, ".")), nil
+}
+
+// resolve returns the absolute path of a path from the root, with its
+// symbolic links resolved, failing if it leaves the root.
+func (t Tool) resolve(path string) (string, error) {
+	abs := filepath.Join(t.root, filepath.FromSlash(path))
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if !strings.HasPrefix(abs, t.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q", ErrOutsideRoot, path)
+	}
+	return abs, nil
+}
@@ -0,0 +1,80 @@
+package transcribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrTranslationNotSupported is returned when translating with a transcriber
+// that is not a Translator.
+var ErrTranslationNotSupported = errors.New("transcriber does not support translation")
+
+// Transcriber transcribes speech to text. The LLM and Chat of the openai
+// package are transcribers.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio []byte) (string, error)
+}
+
+// Translator transcribes speech in any language to English text. The LLM and
+// Chat of the openai package are translators.
+type Translator interface {
+	Translate(ctx context.Context, audio []byte) (string, error)
+}
+
+// Transcribe reads the audio and transcribes it with the transcriber, in
+// chunks if it is larger than the maximum chunk size. The transcripts of the
+// chunks are joined with spaces.
+func Transcribe(ctx context.Context, t Transcriber, r io.Reader, opts ...Option) (string, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	transcribe := t.Transcribe
+	if o.translate {
+		tr, ok := t.(Translator)
+		if !ok {
+			return "", ErrTranslationNotSupported
+		}
+		transcribe = tr.Translate
+	}
+
+	audio, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("read audio: %w", err)
+	}
+	chunks, err := Split(audio, o.maxChunkSize)
+	if err != nil {
+		return "", err
+	}
+
+	transcripts := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		text, err := transcribe(ctx, chunk)
+		if err != nil {
+			if len(chunks) > 1 {
+				return "", fmt.Errorf("chunk %d of %d: %w", i+1, len(chunks), err)
+			}
+			return "", err
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			transcripts = append(transcripts, text)
+		}
+	}
+	return strings.Join(transcripts, " "), nil
+}
+
+// TranscribeFile transcribes the audio file with the transcriber, see
+// Transcribe.
+func TranscribeFile(ctx context.Context, t Transcriber, path string, opts ...Option) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return Transcribe(ctx, t, f, opts...)
+}
@@ -0,0 +1,121 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testTranscriber transcribes audio to its size, and translates it to its
+// size in English.
+type testTranscriber struct {
+	calls int
+}
+
+func (t *testTranscriber) Transcribe(_ context.Context, audio []byte) (string, error) {
+	t.calls++
+	return fmt.Sprintf("%d bytes", len(audio)), nil
+}
+
+func (t *testTranscriber) Translate(_ context.Context, audio []byte) (string, error) {
+	t.calls++
+	return fmt.Sprintf("%d bytes in English", len(audio)), nil
+}
+
+type transcribeFunc func(ctx context.Context, audio []byte) (string, error)
+
+func (f transcribeFunc) Transcribe(ctx context.Context, audio []byte) (string, error) {
+	return f(ctx, audio)
+}
+
+// wav returns WAV audio with a 16 byte fmt chunk, blocks of 4 bytes and the
+// samples.
+func wav(samples []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("RIFF")
+	_ = binary.Write(&b, binary.LittleEndian, uint32(36+len(samples)))
+	b.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(2), uint32(8000), uint32(32000), uint16(4), uint16(16)} {
+		_ = binary.Write(&b, binary.LittleEndian, v)
+	}
+	b.WriteString("data")
+	_ = binary.Write(&b, binary.LittleEndian, uint32(len(samples)))
+	b.Write(samples)
+	return b.Bytes()
+}
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	audio := []byte("small")
+	chunks, err := Split(audio, 10)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{audio}, chunks)
+
+	_, err = Split([]byte("not audio at all"), 10)
+	require.ErrorIs(t, err, ErrAudioTooLarge)
+
+	samples := bytes.Repeat([]byte{1, 2, 3, 4}, 10)
+	chunks, err = Split(wav(samples), 44+16)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{wav(samples[:16]), wav(samples[16:32]), wav(samples[32:])}, chunks)
+
+	_, err = Split(wav(samples)[:36], 20)
+	require.ErrorIs(t, err, ErrInvalidAudio)
+
+	frame := append([]byte{0xFF, 0xFB}, bytes.Repeat([]byte{0}, 8)...)
+	mp3 := append([]byte("ID3\x00\x00"), bytes.Repeat(frame, 3)...)
+	chunks, err = Split(mp3, 22)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{mp3[:15], mp3[15:]}, chunks)
+}
+
+func TestTranscribe(t *testing.T) {
+	t.Parallel()
+
+	tr := &testTranscriber{}
+	samples := bytes.Repeat([]byte{1, 2, 3, 4}, 10)
+	text, err := Transcribe(context.Background(), tr, bytes.NewReader(wav(samples)), WithMaxChunkSize(44+16))
+	require.NoError(t, err)
+	require.Equal(t, "60 bytes 60 bytes 52 bytes", text)
+	require.Equal(t, 3, tr.calls)
+
+	text, err = Transcribe(context.Background(), tr, bytes.NewReader(wav(samples)), WithTranslation(true))
+	require.NoError(t, err)
+	require.Equal(t, "84 bytes in English", text)
+
+	failing := transcribeFunc(func(context.Context, []byte) (string, error) { return "", errors.New("boom") })
+	_, err = Transcribe(context.Background(), failing, bytes.NewReader(wav(samples)), WithTranslation(true))
+	require.ErrorIs(t, err, ErrTranslationNotSupported)
+	_, err = Transcribe(context.Background(), failing, bytes.NewReader(wav(samples)), WithMaxChunkSize(44+16))
+	require.EqualError(t, err, "chunk 1 of 3: boom")
+}
+
+func TestTool(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "memo.wav"), wav(make([]byte, 8)), 0o600))
+
+	tool, err := New(&testTranscriber{}, dir)
+	require.NoError(t, err)
+
+	out, err := tool.Call(context.Background(), `"memo.wav"`)
+	require.NoError(t, err)
+	require.Equal(t, "52 bytes", out)
+
+	out, err = tool.Call(context.Background(), "../secret.wav")
+	require.NoError(t, err)
+	require.Equal(t, `error transcribing audio: path outside the root directory: "../secret.wav"`, out)
+
+	out, err = tool.Call(context.Background(), "missing.wav")
+	require.NoError(t, err)
+	require.Equal(t, "error transcribing audio: open ./missing.wav: no such file or directory", out)
+}
@@ -0,0 +1,10 @@
+// Package webfetch contains a Fetcher getting urls with defenses against
+// server side request forgery, and a tool using it to let agents read web
+// pages.
+//
+// The defenses are enabled by default: urls must be http or https, hosts
+// resolving to loopback, private, link-local or otherwise reserved addresses
+// are blocked, as are cloud metadata endpoints such as 169.254.169.254, the
+// redirects are checked the same way and limited, and response bodies are
+// capped in size.
+package webfetch
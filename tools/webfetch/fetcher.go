@@ -0,0 +1,243 @@
+package webfetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	_defaultMaxBodySize  = 2 << 20
+	_defaultMaxRedirects = 5
+	_defaultTimeout      = 30 * time.Second
+)
+
+var (
+	// ErrSchemeNotAllowed is returned when fetching, or being redirected to, a
+	// url whose scheme is not allowed.
+	ErrSchemeNotAllowed = errors.New("url scheme not allowed")
+	// ErrBlockedAddress is returned when fetching, or being redirected to, a
+	// blocked host or an address of a private network.
+	ErrBlockedAddress = errors.New("address blocked")
+	// ErrTooManyRedirects is returned when the url redirects more times than
+	// allowed.
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrBodyTooLarge is returned when the response body is larger than the
+	// maximum size.
+	ErrBodyTooLarge = errors.New("response body too large")
+	// ErrUnexpectedStatus is returned when the response status is not 2xx.
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+)
+
+// _blockedHosts are the cloud metadata endpoints reachable by name.
+var _blockedHosts = []string{ //nolint:gochecknoglobals
+	"metadata",
+	"metadata.google.internal",
+	"metadata.goog",
+	"instance-data",
+	"instance-data.ec2.internal",
+}
+
+// _blockedNetworks are the networks blocked besides the loopback, private,
+// link-local (which includes the 169.254.169.254 metadata endpoint),
+// multicast and unspecified ones.
+var _blockedNetworks = []*net.IPNet{ //nolint:gochecknoglobals
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+	mustParseCIDR("240.0.0.0/4"),
+	mustParseCIDR("64:ff9b::/96"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// Response is a fetched response.
+type Response struct {
+	// URL is the url of the response, after redirects.
+	URL         string
+	ContentType string
+	Body        []byte
+}
+
+// Fetcher fetches urls given by untrusted sources, such as agents, with
+// defenses against server side request forgery enabled by default: only http
+// and https urls are fetched, addresses of private networks and cloud
+// metadata endpoints are blocked, including when redirected to or resolved
+// by DNS, redirects are limited and response bodies are capped.
+type Fetcher struct {
+	client         *http.Client
+	allowedSchemes []string
+	blockedHosts   []string
+	allowPrivate   bool
+	maxBodySize    int64
+	maxRedirects   int
+	timeout        time.Duration
+	userAgent      string
+}
+
+// NewFetcher creates a new Fetcher.
+func NewFetcher(options ...Option) *Fetcher {
+	f := &Fetcher{
+		allowedSchemes: []string{"http", "https"},
+		blockedHosts:   _blockedHosts,
+		maxBodySize:    _defaultMaxBodySize,
+		maxRedirects:   _defaultMaxRedirects,
+		timeout:        _defaultTimeout,
+	}
+	for _, opt := range options {
+		opt(f)
+	}
+
+	dialer := &net.Dialer{Timeout: f.timeout, Control: f.checkDial}
+	f.client = &http.Client{
+		Timeout: f.timeout,
+		// No proxy: the addresses dialed must be the ones checked.
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: f.timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > f.maxRedirects {
+				return ErrTooManyRedirects
+			}
+			return f.CheckURL(req.URL)
+		},
+	}
+	return f
+}
+
+// Client returns the http client of the fetcher, which applies the defenses
+// of the fetcher except the body size limit.
+func (f *Fetcher) Client() *http.Client {
+	return f.client
+}
+
+// CheckURL returns an error if the scheme or host of the url is not allowed.
+// The addresses the host resolves to are checked when dialing.
+func (f *Fetcher) CheckURL(u *url.URL) error {
+	if !containsFold(f.allowedSchemes, u.Scheme) {
+		return fmt.Errorf("%w: %q", ErrSchemeNotAllowed, u.Scheme)
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return fmt.Errorf("%w: empty host", ErrBlockedAddress)
+	}
+	if containsFold(f.blockedHosts, host) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !f.allowPrivate && IsBlockedIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	return nil
+}
+
+// Fetch gets the url and returns the response, failing if its status is not
+// 2xx.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Response{}, err
+	}
+	if err := f.CheckURL(u); err != nil {
+		return Response{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Response{}, err
+	}
+	if f.userAgent != "" {
+		req.Header.Set("User-Agent", f.userAgent)
+	}
+	res, err := f.client.Do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return Response{}, fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+	}
+	if res.ContentLength > f.maxBodySize {
+		return Response{}, fmt.Errorf("%w: %d bytes", ErrBodyTooLarge, res.ContentLength)
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, f.maxBodySize+1))
+	if err != nil {
+		return Response{}, err
+	}
+	if int64(len(body)) > f.maxBodySize {
+		return Response{}, fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, f.maxBodySize)
+	}
+
+	return Response{
+		URL:         res.Request.URL.String(),
+		ContentType: res.Header.Get("Content-Type"),
+		Body:        body,
+	}, nil
+}
+
+// checkDial checks the resolved address before connecting to it, so that a
+// host can not resolve to a blocked address.
+func (f *Fetcher) checkDial(network, address string, c syscall.RawConn) error {
+	if f.allowPrivate {
+		return nil
+	}
+	return CheckDial(network, address, c)
+}
+
+// CheckDial returns ErrBlockedAddress for the addresses blocked by
+// IsBlockedIP. It is a net.Dialer Control function, checking the addresses
+// once resolved, for the other http clients sending requests to untrusted
+// urls to apply the same policy as the fetcher.
+func CheckDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || IsBlockedIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// IsBlockedIP reports whether the address is a loopback, private, link-local,
+// multicast, unspecified or otherwise reserved address, blocked by default.
+func IsBlockedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, n := range _blockedNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
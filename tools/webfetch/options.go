@@ -0,0 +1,62 @@
+package webfetch
+
+import "time"
+
+// Option is a function type that can be used to modify the fetcher.
+type Option func(f *Fetcher)
+
+// WithAllowedSchemes is an option for setting the allowed url schemes.
+// Defaults to http and https.
+func WithAllowedSchemes(schemes ...string) Option {
+	return func(f *Fetcher) {
+		f.allowedSchemes = schemes
+	}
+}
+
+// WithBlockedHosts is an option for blocking hosts by name, in addition to
+// the cloud metadata endpoints blocked by default.
+func WithBlockedHosts(hosts ...string) Option {
+	return func(f *Fetcher) {
+		f.blockedHosts = append(append([]string{}, f.blockedHosts...), hosts...)
+	}
+}
+
+// WithAllowPrivateNetworks is an option for allowing the addresses of private
+// networks, loopback and link-local ones included. Only use it when the urls
+// fetched are trusted.
+func WithAllowPrivateNetworks(allow bool) Option {
+	return func(f *Fetcher) {
+		f.allowPrivate = allow
+	}
+}
+
+// WithMaxBodySize is an option for setting the maximum size of response
+// bodies in bytes. Defaults to 2 MiB.
+func WithMaxBodySize(size int64) Option {
+	return func(f *Fetcher) {
+		f.maxBodySize = size
+	}
+}
+
+// WithMaxRedirects is an option for setting the maximum number of redirects
+// followed. Defaults to 5.
+func WithMaxRedirects(n int) Option {
+	return func(f *Fetcher) {
+		f.maxRedirects = n
+	}
+}
+
+// WithTimeout is an option for setting the timeout of requests. Defaults to
+// 30 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(f *Fetcher) {
+		f.timeout = timeout
+	}
+}
+
+// WithUserAgent is an option for setting the user agent of requests.
+func WithUserAgent(userAgent string) Option {
+	return func(f *Fetcher) {
+		f.userAgent = userAgent
+	}
+}
@@ -0,0 +1,56 @@
+package webfetch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// Tool is a tool fetching web pages with a Fetcher and returning their text.
+type Tool struct {
+	fetcher *Fetcher
+}
+
+var _ tools.Tool = Tool{}
+
+// New creates a new web fetching tool. The options are the ones of the
+// fetcher, see NewFetcher.
+func New(options ...Option) Tool {
+	return Tool{fetcher: NewFetcher(options...)}
+}
+
+// Name returns the name of the tool.
+func (t Tool) Name() string {
+	return "Web Fetch"
+}
+
+// Description returns a string describing the tool.
+func (t Tool) Description() string {
+	return `Useful for reading a web page. The input should be an http or https url.`
+}
+
+// Call fetches the url and returns the text of the page. Blocked urls and
+// failed requests are reported in the result to let the agent try another url.
+func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	res, err := t.fetcher.Fetch(ctx, strings.TrimSpace(input))
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return fmt.Sprintf("error fetching url: %s", err.Error()), nil
+	}
+
+	if !strings.Contains(res.ContentType, "html") {
+		return string(res.Body), nil
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Body))
+	if err != nil {
+		return "", err
+	}
+	doc.Find("script, style, noscript").Remove()
+	return strings.Join(strings.Fields(doc.Find("body").Text()), " "), nil
+}
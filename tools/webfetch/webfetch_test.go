@@ -0,0 +1,128 @@
+package webfetch
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head><script>var x;</script></head><body><h1>Otters</h1> <p>hold hands</p></body></html>`)) //nolint:lll
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+	})
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestFetcherBlocksByDefault(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	f := NewFetcher()
+	for _, u := range []string{
+		s.URL + "/page",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://metadata.google.internal/computeMetadata/v1/",
+		"http://[::1]/",
+		"http://10.0.0.1/",
+	} {
+		_, err := f.Fetch(context.Background(), u)
+		require.ErrorIs(t, err, ErrBlockedAddress, u)
+	}
+
+	_, err := f.Fetch(context.Background(), "file:///etc/passwd")
+	require.ErrorIs(t, err, ErrSchemeNotAllowed)
+}
+
+func TestFetcherChecksResolvedAddress(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	_, port, err := net.SplitHostPort(s.Listener.Addr().String())
+	require.NoError(t, err)
+
+	// localhost is not an ip, it is blocked once resolved.
+	_, err = NewFetcher().Fetch(context.Background(), "http://localhost:"+port+"/page")
+	require.ErrorIs(t, err, ErrBlockedAddress)
+}
+
+func TestFetcherAllowPrivate(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	f := NewFetcher(WithAllowPrivateNetworks(true), WithMaxBodySize(99), WithMaxRedirects(3))
+
+	res, err := f.Fetch(context.Background(), s.URL+"/page")
+	require.NoError(t, err)
+	require.Equal(t, "text/html", res.ContentType)
+	require.Equal(t, s.URL+"/page", res.URL)
+
+	_, err = f.Fetch(context.Background(), s.URL+"/large")
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+	_, err = f.Fetch(context.Background(), s.URL+"/file")
+	require.ErrorIs(t, err, ErrSchemeNotAllowed)
+	_, err = f.Fetch(context.Background(), s.URL+"/loop")
+	require.ErrorIs(t, err, ErrTooManyRedirects)
+	_, err = f.Fetch(context.Background(), s.URL+"/missing")
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+}
+
+func TestCheckURL(t *testing.T) {
+	t.Parallel()
+
+	f := NewFetcher(WithBlockedHosts("internal.example.com"))
+	for _, tc := range []struct {
+		url string
+		err error
+	}{
+		{"https://example.com/", nil},
+		{"HTTPS://EXAMPLE.COM/", nil},
+		{"ftp://example.com/", ErrSchemeNotAllowed},
+		{"https://internal.example.com./", ErrBlockedAddress},
+		{"http://[::ffff:127.0.0.1]/", ErrBlockedAddress},
+		{"http://100.64.0.1/", ErrBlockedAddress},
+		{"http://0.0.0.0/", ErrBlockedAddress},
+		{"http:///path", ErrBlockedAddress},
+	} {
+		u, err := url.Parse(tc.url)
+		require.NoError(t, err)
+		err = f.CheckURL(u)
+		if tc.err == nil {
+			require.NoError(t, err, tc.url)
+		} else {
+			require.ErrorIs(t, err, tc.err, tc.url)
+		}
+	}
+}
+
+func TestTool(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	out, err := New(WithAllowPrivateNetworks(true)).Call(context.Background(), s.URL+"/page")
+	require.NoError(t, err)
+	require.Equal(t, "Otters hold hands", out)
+
+	out, err = New().Call(context.Background(), s.URL+"/page")
+	require.NoError(t, err)
+	require.Contains(t, out, "error fetching url")
+}
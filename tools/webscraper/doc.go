@@ -0,0 +1,7 @@
+// Package webscraper contains a Scraper reading web pages for agents, and a
+// tool using it. Pages are fetched with a webfetch.Fetcher, so with its
+// timeouts, size caps and defenses against server side request forgery, the
+// robots.txt of their sites is respected, and the main text of the page is
+// extracted, leaving out menus, sidebars and footers, and truncated to a
+// token budget.
+package webscraper
@@ -0,0 +1,75 @@
+package webscraper
+
+import (
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/tools/webfetch"
+)
+
+const (
+	// DefaultUserAgent is the user agent of the requests, also used to find
+	// the rules of robots.txt files.
+	DefaultUserAgent = "langchaingo-webscraper"
+
+	_defaultMaxTokens = 2000
+	// _tokenCountingModel is the model whose tokenizer counts tokens by
+	// default.
+	_tokenCountingModel = "gpt-3.5-turbo"
+)
+
+// Option is a function type that can be used to modify the scraper.
+type Option func(s *Scraper)
+
+// WithFetcherOptions is an option for setting the options of the fetcher,
+// such as webfetch.WithTimeout or webfetch.WithMaxBodySize.
+func WithFetcherOptions(opts ...webfetch.Option) Option {
+	return func(s *Scraper) {
+		s.fetcherOptions = append(s.fetcherOptions, opts...)
+	}
+}
+
+// WithUserAgent is an option for setting the user agent of the requests.
+// Defaults to DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(s *Scraper) {
+		s.userAgent = userAgent
+	}
+}
+
+// WithIgnoreRobots is an option for fetching pages disallowed by robots.txt.
+func WithIgnoreRobots(ignore bool) Option {
+	return func(s *Scraper) {
+		s.ignoreRobots = ignore
+	}
+}
+
+// WithMaxTokens is an option for setting the number of tokens the text of a
+// page is truncated to. Defaults to 2000, 0 disables truncation.
+func WithMaxTokens(maxTokens int) Option {
+	return func(s *Scraper) {
+		s.maxTokens = maxTokens
+	}
+}
+
+// WithTokenCounter is an option for setting the function counting the tokens
+// of texts. Defaults to counting with the tokenizer of gpt-3.5-turbo.
+func WithTokenCounter(countTokens func(text string) int) Option {
+	return func(s *Scraper) {
+		s.countTokens = countTokens
+	}
+}
+
+func applyOptions(opts ...Option) *Scraper {
+	s := &Scraper{
+		userAgent: DefaultUserAgent,
+		maxTokens: _defaultMaxTokens,
+		countTokens: func(text string) int {
+			return llms.CountTokens(_tokenCountingModel, text)
+		},
+		robots: make(map[string]*robotsRules),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.fetcher = webfetch.NewFetcher(append(s.fetcherOptions, webfetch.WithUserAgent(s.userAgent))...)
+	return s
+}
@@ -0,0 +1,160 @@
+package webscraper
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var (
+	// _unlikelyRegexp matches the classes and ids of elements unlikely to be
+	// part of the main text.
+	_unlikelyRegexp = regexp.MustCompile(`(?i)comment|sidebar|footer|header|nav|menu|share|social|related|cookie|banner|popup|promo|sponsor|\bads?\b`) //nolint:lll
+	// _likelyRegexp matches the classes and ids of elements likely to hold the
+	// main text.
+	_likelyRegexp = regexp.MustCompile(`(?i)article|content|main|post|entry|story|text|body`)
+)
+
+// _blockTags are the elements whose text is kept as separate paragraphs.
+var _blockTags = map[string]bool{ //nolint:gochecknoglobals
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"li": true, "pre": true, "blockquote": true, "td": true, "th": true, "dt": true, "dd": true,
+	"figcaption": true,
+}
+
+// extractArticle returns the title and the main text of an HTML page. The
+// elements unlikely to hold the text, such as menus and footers, are removed,
+// and the element scoring best by the length and number of its paragraphs is
+// taken as the main text, in the manner of readability.
+func extractArticle(body []byte) (string, string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	if title == "" {
+		title = strings.TrimSpace(doc.Find("h1").First().Text())
+	}
+
+	doc.Find("script, style, noscript, template, svg, iframe, form, nav, header, footer, aside").Remove()
+	doc.Find("[class], [id]").Each(func(_ int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "body" || goquery.NodeName(s) == "article" {
+			return
+		}
+		attrs := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if _unlikelyRegexp.MatchString(attrs) && !_likelyRegexp.MatchString(attrs) {
+			s.Remove()
+		}
+	})
+
+	main := bestCandidate(doc)
+	if main == nil {
+		main = doc.Find("body")
+	}
+	return title, blockText(main), nil
+}
+
+// bestCandidate returns the element scoring best as the main text: each
+// paragraph adds its score to its parent, and half of it to its grandparent.
+func bestCandidate(doc *goquery.Document) *goquery.Selection {
+	if articles := doc.Find("article"); articles.Length() > 0 {
+		var best *goquery.Selection
+		articles.Each(func(_ int, s *goquery.Selection) {
+			if best == nil || len(s.Text()) > len(best.Text()) {
+				best = s
+			}
+		})
+		return best
+	}
+
+	// Candidates are kept in document order, so that ties are broken the
+	// same way every time.
+	scores := map[*html.Node]float64{}
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if _, ok := scores[n]; !ok {
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+	doc.Find("p, pre, td").Each(func(_ int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		if len(text) < 25 {
+			return
+		}
+		lengthScore := float64(len(text)) / 100
+		if lengthScore > 3 {
+			lengthScore = 3
+		}
+		score := 1 + float64(strings.Count(text, ",")) + lengthScore
+		parent := p.Parent()
+		if parent.Length() == 0 {
+			return
+		}
+		addScore(parent.Get(0), score)
+		if grandparent := parent.Parent(); grandparent.Length() > 0 {
+			addScore(grandparent.Get(0), score/2)
+		}
+	})
+
+	var (
+		best      *html.Node
+		bestScore float64
+	)
+	for _, node := range candidates {
+		score := scores[node]
+		s := goquery.NewDocumentFromNode(node).Selection
+		if _likelyRegexp.MatchString(s.AttrOr("class", "") + " " + s.AttrOr("id", "")) {
+			score *= 1.25
+		}
+		if score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return goquery.NewDocumentFromNode(best).Selection
+}
+
+// blockText returns the text of the selection, with a paragraph for each
+// block element and whitespace collapsed.
+func blockText(s *goquery.Selection) string {
+	var paragraphs []string
+	var inline strings.Builder
+	flush := func() {
+		if text := strings.Join(strings.Fields(inline.String()), " "); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+		inline.Reset()
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			inline.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			inline.WriteString(" ")
+		case n.Type == html.ElementNode && _blockTags[n.Data]:
+			flush()
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+			flush()
+		default:
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+		}
+	}
+	for _, n := range s.Nodes {
+		walk(n)
+	}
+	flush()
+	return strings.Join(paragraphs, "\n\n")
+}
@@ -0,0 +1,127 @@
+package webscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/tools/webfetch"
+)
+
+// ErrDisallowedByRobots is returned when the robots.txt of a site disallows
+// fetching a page.
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// _truncationMarker ends the text of pages truncated to the token budget.
+const _truncationMarker = "\n\n[truncated]"
+
+// Page is a scraped page.
+type Page struct {
+	// URL is the url of the page, after redirects.
+	URL   string
+	Title string
+	// Text is the main text of the page, truncated to the token budget.
+	Text string
+	// Truncated is whether the text was truncated.
+	Truncated bool
+}
+
+// Scraper reads the main text of web pages.
+type Scraper struct {
+	fetcher        *webfetch.Fetcher
+	fetcherOptions []webfetch.Option
+	userAgent      string
+	ignoreRobots   bool
+	maxTokens      int
+	countTokens    func(string) int
+
+	mu sync.Mutex
+	// robots are the robots.txt rules of each site, by scheme and host.
+	robots map[string]*robotsRules
+}
+
+// NewScraper creates a new Scraper.
+func NewScraper(opts ...Option) *Scraper {
+	return applyOptions(opts...)
+}
+
+// Scrape fetches the page at the url, unless its robots.txt disallows it, and
+// returns its main text truncated to the token budget. Pages which are not
+// HTML are returned as they are.
+func (s *Scraper) Scrape(ctx context.Context, rawURL string) (Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Page{}, err
+	}
+	if !s.ignoreRobots {
+		if err := s.checkRobots(ctx, u); err != nil {
+			return Page{}, err
+		}
+	}
+
+	res, err := s.fetcher.Fetch(ctx, u.String())
+	if err != nil {
+		return Page{}, err
+	}
+
+	page := Page{URL: res.URL, Text: string(res.Body)}
+	if strings.Contains(res.ContentType, "html") || res.ContentType == "" {
+		page.Title, page.Text, err = extractArticle(res.Body)
+		if err != nil {
+			return Page{}, err
+		}
+	}
+	page.Text, page.Truncated = s.truncate(page.Text)
+	return page, nil
+}
+
+// checkRobots returns ErrDisallowedByRobots if the robots.txt of the site of
+// the url disallows it. Sites whose robots.txt can not be fetched allow every
+// page.
+func (s *Scraper) checkRobots(ctx context.Context, u *url.URL) error {
+	site := u.Scheme + "://" + u.Host
+
+	s.mu.Lock()
+	rules, ok := s.robots[site]
+	s.mu.Unlock()
+	if !ok {
+		rules = &robotsRules{}
+		if res, err := s.fetcher.Fetch(ctx, site+"/robots.txt"); err == nil {
+			rules = parseRobots(string(res.Body), s.userAgent)
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.mu.Lock()
+		s.robots[site] = rules
+		s.mu.Unlock()
+	}
+
+	if !rules.allowed(u.RequestURI()) {
+		return fmt.Errorf("%w: %s", ErrDisallowedByRobots, u)
+	}
+	return nil
+}
+
+// truncate cuts the text at a word so that it fits in the token budget, and
+// reports whether it did.
+func (s *Scraper) truncate(text string) (string, bool) {
+	if s.maxTokens <= 0 || s.countTokens(text) <= s.maxTokens {
+		return text, false
+	}
+
+	// The longest prefix of words fitting in the budget is searched.
+	words := strings.SplitAfter(text, " ")
+	low, high := 0, len(words)
+	for low < high {
+		mid := (low + high + 1) / 2
+		if s.countTokens(strings.Join(words[:mid], "")+_truncationMarker) <= s.maxTokens {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return strings.TrimSpace(strings.Join(words[:low], "")) + _truncationMarker, true
+}
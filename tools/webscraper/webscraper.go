@@ -0,0 +1,45 @@
+package webscraper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+)
+
+// Tool is a tool reading the main text of web pages with a Scraper.
+type Tool struct {
+	scraper *Scraper
+}
+
+var _ tools.Tool = Tool{}
+
+// New creates a new web scraping tool. The options are the ones of the
+// scraper, see NewScraper.
+func New(opts ...Option) Tool {
+	return Tool{scraper: NewScraper(opts...)}
+}
+
+// Name returns the name of the tool.
+func (t Tool) Name() string {
+	return "Web Page Reader"
+}
+
+// Description returns a string describing the tool.
+func (t Tool) Description() string {
+	return `Useful for reading the main text of a web page, such as an article or a search result. The input should be an http or https url.` //nolint:lll
+}
+
+// Call scrapes the url and returns the title, url and main text of the page.
+// Failures are reported in the result to let the agent try another url.
+func (t Tool) Call(ctx context.Context, input string) (string, error) {
+	page, err := t.scraper.Scrape(ctx, strings.TrimSpace(input))
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return fmt.Sprintf("error reading page: %s", err.Error()), nil
+	}
+	return fmt.Sprintf("Title: %s\nURL: %s\n\n%s", page.Title, page.URL, page.Text), nil
+}
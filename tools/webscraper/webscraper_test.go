@@ -0,0 +1,136 @@
+package webscraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/tools/webfetch"
+)
+
+const _blogPage = `<html><head><title>Otters</title><script>var x;</script></head><body>
+<div id="menu"><a href="/">Home</a> <a href="/blog">Blog</a></div>
+<div class="layout">
+  <div class="post-content">
+    <h2>Why otters hold hands</h2>
+    <p>Sea otters hold hands while they sleep, so that they do not drift apart.</p>
+    <p>They also wrap themselves in kelp, which anchors them to one place, like a blanket.</p>
+  </div>
+  <div class="sidebar"><p>Subscribe to our newsletter, it is great, really, truly.</p></div>
+</div>
+<div class="footer">Copyright</div>
+</body></html>`
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\nAllow: /private/ok$\n\n" +
+			"User-agent: other-bot\nDisallow: /\n"))
+	})
+	mux.HandleFunc("/blog", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(_blogPage))
+	})
+	mux.HandleFunc("/article", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><body><nav>Menu</nav><h1>News</h1>
+<article><p>First line<br>of the story.</p><ul><li>One</li><li>Two</li></ul></article>
+<aside>Ads</aside></body></html>`))
+	})
+	mux.HandleFunc("/private/", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("secret"))
+	})
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestScraper(opts ...Option) *Scraper {
+	return NewScraper(append([]Option{
+		WithFetcherOptions(webfetch.WithAllowPrivateNetworks(true)),
+		WithTokenCounter(func(text string) int { return len(strings.Fields(text)) }),
+	}, opts...)...)
+}
+
+func TestScrape(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	scraper := newTestScraper()
+
+	page, err := scraper.Scrape(context.Background(), s.URL+"/blog")
+	require.NoError(t, err)
+	require.Equal(t, Page{
+		URL:   s.URL + "/blog",
+		Title: "Otters",
+		Text: "Why otters hold hands\n\n" +
+			"Sea otters hold hands while they sleep, so that they do not drift apart.\n\n" +
+			"They also wrap themselves in kelp, which anchors them to one place, like a blanket.",
+	}, page)
+
+	page, err = scraper.Scrape(context.Background(), s.URL+"/article")
+	require.NoError(t, err)
+	require.Equal(t, "News", page.Title)
+	require.Equal(t, "First line of the story.\n\nOne\n\nTwo", page.Text)
+
+	_, err = scraper.Scrape(context.Background(), s.URL+"/private/data")
+	require.ErrorIs(t, err, ErrDisallowedByRobots)
+	page, err = scraper.Scrape(context.Background(), s.URL+"/private/ok")
+	require.NoError(t, err)
+	require.Equal(t, "secret", page.Text)
+
+	_, err = newTestScraper(WithUserAgent("other-bot/1.0")).Scrape(context.Background(), s.URL+"/blog")
+	require.ErrorIs(t, err, ErrDisallowedByRobots)
+	_, err = newTestScraper(WithUserAgent("other-bot/1.0"), WithIgnoreRobots(true)).Scrape(context.Background(), s.URL+"/blog") //nolint:lll
+	require.NoError(t, err)
+}
+
+func TestScrapeTruncates(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	page, err := newTestScraper(WithMaxTokens(8)).Scrape(context.Background(), s.URL+"/blog")
+	require.NoError(t, err)
+	require.True(t, page.Truncated)
+	require.Equal(t, "Why otters hold hands\n\nSea otters hold\n\n[truncated]", page.Text)
+}
+
+func TestParseRobots(t *testing.T) {
+	t.Parallel()
+
+	rules := parseRobots(`# comment
+User-agent: a-bot
+User-agent: b-bot
+Disallow: /tmp/*.json$
+Disallow: /admin
+Allow: /admin/public
+
+User-agent: *
+Disallow:
+`, "B-Bot/2.0")
+	require.True(t, rules.allowed("/"))
+	require.False(t, rules.allowed("/admin/users"))
+	require.True(t, rules.allowed("/admin/public/page"))
+	require.False(t, rules.allowed("/tmp/x/data.json"))
+	require.True(t, rules.allowed("/tmp/x/data.json?v=1"))
+
+	require.True(t, parseRobots("User-agent: *\nDisallow:\n", "bot").allowed("/anything"))
+}
+
+func TestTool(t *testing.T) {
+	t.Parallel()
+
+	s := newServer(t)
+	out, err := Tool{scraper: newTestScraper()}.Call(context.Background(), s.URL+"/article\n")
+	require.NoError(t, err)
+	require.Equal(t, "Title: News\nURL: "+s.URL+"/article\n\nFirst line of the story.\n\nOne\n\nTwo", out)
+
+	out, err = New().Call(context.Background(), s.URL+"/article")
+	require.NoError(t, err)
+	require.Contains(t, out, "error reading page")
+}
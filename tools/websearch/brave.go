@@ -0,0 +1,86 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+const _braveEndpoint = "https://api.search.brave.com/res/v1/web/search"
+
+// Brave is a search tool using the Brave Search API.
+type Brave struct {
+	opts options
+}
+
+var _ SearchTool = Brave{}
+
+// NewBrave creates a new Brave search tool. The API key is read from the
+// BRAVE_API_KEY environment variable if not set with WithAPIKey.
+func NewBrave(opts ...Option) (*Brave, error) {
+	o := applyOptions(_braveEndpoint, append([]Option{WithAPIKey(os.Getenv("BRAVE_API_KEY"))}, opts...)...)
+	if o.apiKey == "" {
+		return nil, fmt.Errorf("%w: set it in the BRAVE_API_KEY environment variable", ErrMissingAPIKey)
+	}
+	return &Brave{opts: o}, nil
+}
+
+// Name returns the name of the tool.
+func (t Brave) Name() string {
+	return "Brave Search"
+}
+
+// Description returns a string describing the tool.
+func (t Brave) Description() string {
+	return `A wrapper around Brave Search returning the title, snippet and url of the best results.
+Useful for when you need to find information on the internet. Input should be a search query.`
+}
+
+// Call searches the web and returns the results as text.
+func (t Brave) Call(ctx context.Context, input string) (string, error) {
+	return callSearch(ctx, t, input)
+}
+
+// Search returns the web results of the query.
+func (t Brave) Search(ctx context.Context, query string) ([]Result, error) {
+	params := url.Values{
+		"q":     {query},
+		"count": {strconv.Itoa(t.opts.maxResults)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.opts.endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating brave request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Subscription-Token", t.opts.apiKey)
+
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	if err := t.opts.getJSON(req, &resp); err != nil {
+		return nil, fmt.Errorf("brave search: %w", err)
+	}
+
+	results := make([]Result, 0, len(resp.Web.Results))
+	for _, r := range resp.Web.Results {
+		if len(results) == t.opts.maxResults {
+			break
+		}
+		results = append(results, Result{
+			Rank:    len(results) + 1,
+			Title:   r.Title,
+			Snippet: stripTags(r.Description),
+			URL:     r.URL,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,5 @@
+// Package websearch contains tools searching the web with SerpAPI, DuckDuckGo
+// or the Brave Search API. They all implement SearchTool, returning ranked
+// results with their titles, snippets and urls, which agents get formatted as
+// text when calling the tools.
+package websearch
@@ -0,0 +1,96 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const _duckDuckGoEndpoint = "https://html.duckduckgo.com/html/"
+
+// DuckDuckGo is a search tool scraping the HTML results of DuckDuckGo, which
+// requires no API key.
+type DuckDuckGo struct {
+	opts options
+}
+
+var _ SearchTool = DuckDuckGo{}
+
+// NewDuckDuckGo creates a new DuckDuckGo search tool.
+func NewDuckDuckGo(opts ...Option) *DuckDuckGo {
+	return &DuckDuckGo{opts: applyOptions(_duckDuckGoEndpoint, opts...)}
+}
+
+// Name returns the name of the tool.
+func (t DuckDuckGo) Name() string {
+	return "DuckDuckGo Search"
+}
+
+// Description returns a string describing the tool.
+func (t DuckDuckGo) Description() string {
+	return `A wrapper around DuckDuckGo Search returning the title, snippet and url of the best results.
+Free search alternative to Google. Input should be a search query.`
+}
+
+// Call searches the web and returns the results as text.
+func (t DuckDuckGo) Call(ctx context.Context, input string) (string, error) {
+	return callSearch(ctx, t, input)
+}
+
+// Search returns the web results of the query, leaving out the ads.
+func (t DuckDuckGo) Search(ctx context.Context, query string) ([]Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.opts.endpoint+"?"+url.Values{"q": {query}}.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating duckduckgo request: %w", err)
+	}
+	resp, err := t.opts.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("duckduckgo search: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("duckduckgo search: %w", err)
+	}
+
+	results := make([]Result, 0, t.opts.maxResults)
+	doc.Find(".result").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		if len(results) == t.opts.maxResults {
+			return false
+		}
+		if s.HasClass("result--ad") {
+			return true
+		}
+		link := s.Find(".result__a").First()
+		href, ok := link.Attr("href")
+		if !ok {
+			return true
+		}
+		results = append(results, Result{
+			Rank:    len(results) + 1,
+			Title:   strings.TrimSpace(link.Text()),
+			Snippet: strings.Join(strings.Fields(s.Find(".result__snippet").Text()), " "),
+			URL:     duckDuckGoTarget(href),
+		})
+		return true
+	})
+	return results, nil
+}
+
+// duckDuckGoTarget returns the url a DuckDuckGo redirect link leads to, or the
+// link itself if it is not a redirect.
+func duckDuckGoTarget(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	if target := u.Query().Get("uddg"); target != "" {
+		return target
+	}
+	return href
+}
@@ -0,0 +1,71 @@
+package websearch
+
+import "net/http"
+
+// DefaultUserAgent is the user agent of the search requests.
+const DefaultUserAgent = "github.com/tmc/langchaingo/tools/websearch"
+
+type options struct {
+	apiKey     string
+	endpoint   string
+	maxResults int
+	userAgent  string
+	httpClient Doer
+}
+
+// Option is a function type that can be used to modify the search tools.
+type Option func(o *options)
+
+// WithAPIKey is an option for setting the API key of the providers requiring
+// one. If not set, it is read from the SERPAPI_API_KEY or BRAVE_API_KEY
+// environment variable.
+func WithAPIKey(apiKey string) Option {
+	return func(o *options) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithMaxResults is an option for setting the maximum number of results of a
+// search. Defaults to 5.
+func WithMaxResults(n int) Option {
+	return func(o *options) {
+		o.maxResults = n
+	}
+}
+
+// WithEndpoint is an option for overriding the url searched, such as the url
+// of a proxy.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithUserAgent is an option for setting the user agent of the requests.
+// Defaults to DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithHTTPClient is an option for setting the client sending the requests.
+// Defaults to http.DefaultClient.
+func WithHTTPClient(client Doer) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+func applyOptions(endpoint string, opts ...Option) options {
+	o := options{
+		endpoint:   endpoint,
+		maxResults: _defaultMaxResults,
+		userAgent:  DefaultUserAgent,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
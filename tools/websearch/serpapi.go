@@ -0,0 +1,83 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+const _serpAPIEndpoint = "https://serpapi.com/search"
+
+// SerpAPI is a search tool searching Google with SerpAPI.
+type SerpAPI struct {
+	opts options
+}
+
+var _ SearchTool = SerpAPI{}
+
+// NewSerpAPI creates a new SerpAPI search tool. The API key is read from the
+// SERPAPI_API_KEY environment variable if not set with WithAPIKey.
+func NewSerpAPI(opts ...Option) (*SerpAPI, error) {
+	o := applyOptions(_serpAPIEndpoint, append([]Option{WithAPIKey(os.Getenv("SERPAPI_API_KEY"))}, opts...)...)
+	if o.apiKey == "" {
+		return nil, fmt.Errorf("%w: set it in the SERPAPI_API_KEY environment variable", ErrMissingAPIKey)
+	}
+	return &SerpAPI{opts: o}, nil
+}
+
+// Name returns the name of the tool.
+func (t SerpAPI) Name() string {
+	return "Google Search"
+}
+
+// Description returns a string describing the tool.
+func (t SerpAPI) Description() string {
+	return `A wrapper around Google Search returning the title, snippet and url of the best results.
+Useful for when you need to answer questions about current events. Input should be a search query.`
+}
+
+// Call searches the web and returns the results as text.
+func (t SerpAPI) Call(ctx context.Context, input string) (string, error) {
+	return callSearch(ctx, t, input)
+}
+
+// Search returns the organic results of the query.
+func (t SerpAPI) Search(ctx context.Context, query string) ([]Result, error) {
+	params := url.Values{
+		"q":       {query},
+		"engine":  {"google"},
+		"num":     {strconv.Itoa(t.opts.maxResults)},
+		"api_key": {t.opts.apiKey},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.opts.endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating serpapi request: %w", err)
+	}
+
+	var resp struct {
+		Error          string `json:"error"`
+		OrganicResults []struct {
+			Title   string `json:"title"`
+			Link    string `json:"link"`
+			Snippet string `json:"snippet"`
+		} `json:"organic_results"`
+	}
+	if err := t.opts.getJSON(req, &resp); err != nil {
+		return nil, fmt.Errorf("serpapi search: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("serpapi search: %w: %s", ErrAPIError, resp.Error)
+	}
+
+	results := make([]Result, 0, len(resp.OrganicResults))
+	for _, r := range resp.OrganicResults {
+		if len(results) == t.opts.maxResults {
+			break
+		}
+		results = append(results, Result{Rank: len(results) + 1, Title: r.Title, Snippet: r.Snippet, URL: r.Link})
+	}
+	return results, nil
+}
@@ -0,0 +1,105 @@
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/tools"
+)
+
+const _defaultMaxResults = 5
+
+// _tagRegexp matches the HTML tags highlighting the query in snippets.
+var _tagRegexp = regexp.MustCompile(`<[^>]*>`)
+
+var (
+	// ErrMissingAPIKey is returned when a provider requiring an API key is
+	// created without one.
+	ErrMissingAPIKey = errors.New("missing the API key")
+	// ErrUnexpectedStatus is returned when a search responds with a status
+	// other than 200 OK.
+	ErrUnexpectedStatus = errors.New("unexpected status code")
+	// ErrAPIError is returned when a search API responds with an error.
+	ErrAPIError = errors.New("search API responded with an error")
+)
+
+// Result is a result of a web search.
+type Result struct {
+	// Rank is the position of the result, starting at 1.
+	Rank    int    `json:"rank"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet"`
+	URL     string `json:"url"`
+}
+
+// SearchTool is a tool searching the web, whose results can also be used
+// directly.
+type SearchTool interface {
+	tools.Tool
+	// Search returns the results of the query, best first.
+	Search(ctx context.Context, query string) ([]Result, error)
+}
+
+// Doer performs HTTP requests, such as *http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// FormatResults returns the results as text, as given to agents calling the
+// search tools.
+func FormatResults(results []Result) string {
+	if len(results) == 0 {
+		return "No good search results were found"
+	}
+	var sb strings.Builder
+	for _, r := range results {
+		fmt.Fprintf(&sb, "%d. %s\n%s\nURL: %s\n\n", r.Rank, r.Title, r.Snippet, r.URL)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// callSearch searches with the tool and formats the results for agents.
+func callSearch(ctx context.Context, t SearchTool, input string) (string, error) {
+	results, err := t.Search(ctx, strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	return FormatResults(results), nil
+}
+
+// getJSON sends the request and decodes its JSON response into v.
+func (o options) getJSON(req *http.Request, v any) error {
+	resp, err := o.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// do sends the request with the user agent, failing if the status is not 200.
+func (o options) do(req *http.Request) (*http.Response, error) {
+	if o.userAgent != "" {
+		req.Header.Set("User-Agent", o.userAgent)
+	}
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// stripTags removes the HTML tags of a snippet and unescapes its entities.
+func stripTags(s string) string {
+	return html.UnescapeString(_tagRegexp.ReplaceAllString(s, ""))
+}
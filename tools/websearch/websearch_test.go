@@ -0,0 +1,115 @@
+package websearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+	return s.URL
+}
+
+func TestSerpAPI(t *testing.T) {
+	t.Parallel()
+
+	endpoint := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "otters", r.URL.Query().Get("q"))
+		require.Equal(t, "key", r.URL.Query().Get("api_key"))
+		_, _ = w.Write([]byte(`{"organic_results": [
+			{"title": "Otter", "link": "https://en.wikipedia.org/wiki/Otter", "snippet": "Otters are mammals."},
+			{"title": "Sea otter", "link": "https://example.com/sea", "snippet": "They hold hands."},
+			{"title": "Third", "link": "https://example.com/3", "snippet": "Left out."}
+		]}`))
+	})
+	tool, err := NewSerpAPI(WithAPIKey("key"), WithEndpoint(endpoint), WithMaxResults(2))
+	require.NoError(t, err)
+
+	results, err := tool.Search(context.Background(), "otters")
+	require.NoError(t, err)
+	require.Equal(t, []Result{
+		{Rank: 1, Title: "Otter", Snippet: "Otters are mammals.", URL: "https://en.wikipedia.org/wiki/Otter"},
+		{Rank: 2, Title: "Sea otter", Snippet: "They hold hands.", URL: "https://example.com/sea"},
+	}, results)
+
+	text, err := tool.Call(context.Background(), " otters\n")
+	require.NoError(t, err)
+	require.Equal(t, "1. Otter\nOtters are mammals.\nURL: https://en.wikipedia.org/wiki/Otter\n\n"+
+		"2. Sea otter\nThey hold hands.\nURL: https://example.com/sea", text)
+
+	endpoint = newServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"error": "Invalid API key."}`))
+	})
+	tool, err = NewSerpAPI(WithAPIKey("key"), WithEndpoint(endpoint))
+	require.NoError(t, err)
+	_, err = tool.Search(context.Background(), "otters")
+	require.ErrorIs(t, err, ErrAPIError)
+}
+
+func TestBrave(t *testing.T) {
+	t.Parallel()
+
+	endpoint := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Subscription-Token") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"web": {"results": [
+			{"title": "Otter", "url": "https://example.com/otter", "description": "<strong>Otters</strong> &amp; rivers."}
+		]}}`))
+	})
+	tool, err := NewBrave(WithAPIKey("key"), WithEndpoint(endpoint))
+	require.NoError(t, err)
+	results, err := tool.Search(context.Background(), "otters")
+	require.NoError(t, err)
+	require.Equal(t, []Result{
+		{Rank: 1, Title: "Otter", Snippet: "Otters & rivers.", URL: "https://example.com/otter"},
+	}, results)
+
+	tool, err = NewBrave(WithAPIKey("wrong"), WithEndpoint(endpoint))
+	require.NoError(t, err)
+	_, err = tool.Search(context.Background(), "otters")
+	require.ErrorIs(t, err, ErrUnexpectedStatus)
+}
+
+func TestDuckDuckGo(t *testing.T) {
+	t.Parallel()
+
+	endpoint := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, DefaultUserAgent, r.UserAgent())
+		_, _ = w.Write([]byte(`<html><body>
+<div class="result results_links result--ad"><a class="result__a" href="https://ads.example.com">Ad</a></div>
+<div class="result results_links web-result">
+  <h2><a class="result__a" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fexample.com%2Fotter&amp;rut=x">Otter</a></h2>
+  <a class="result__snippet">Otters   are <b>mammals</b>.</a>
+</div>
+<div class="result results_links web-result">
+  <h2><a class="result__a" href="https://example.com/sea">Sea otter</a></h2>
+  <a class="result__snippet">They hold hands.</a>
+</div>
+</body></html>`))
+	})
+	results, err := NewDuckDuckGo(WithEndpoint(endpoint)).Search(context.Background(), "otters")
+	require.NoError(t, err)
+	require.Equal(t, []Result{
+		{Rank: 1, Title: "Otter", Snippet: "Otters are mammals.", URL: "https://example.com/otter"},
+		{Rank: 2, Title: "Sea otter", Snippet: "They hold hands.", URL: "https://example.com/sea"},
+	}, results)
+}
+
+func TestMissingAPIKey(t *testing.T) { //nolint:paralleltest
+	t.Setenv("SERPAPI_API_KEY", "")
+	t.Setenv("BRAVE_API_KEY", "")
+
+	_, err := NewSerpAPI()
+	require.ErrorIs(t, err, ErrMissingAPIKey)
+	_, err = NewBrave()
+	require.ErrorIs(t, err, ErrMissingAPIKey)
+	require.Equal(t, "No good search results were found", FormatResults(nil))
+}
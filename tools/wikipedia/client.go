@@ -1,115 +1,163 @@
 package wikipedia
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"time"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 const _baseURL = "https://%s.wikipedia.org/w/api.php"
 
-type searchResponse struct {
+// Page is the summary of a Wikipedia page.
+type Page struct {
+	Title string
+	// Summary is the introduction of the page, as plain text.
+	Summary string
+	URL     string
+	// Description is the short description of the page, from Wikidata.
+	Description string
+	// WikidataID is the id of the Wikidata item of the page, such as "Q42".
+	WikidataID string
+	// Disambiguation is whether the page lists the pages of the meanings of
+	// an ambiguous title.
+	Disambiguation bool
+}
+
+// client calls the MediaWiki API of a Wikipedia.
+type client struct {
+	endpoint   string
+	userAgent  string
+	httpClient *http.Client
+}
+
+type queryResponse struct {
 	Query struct {
-		Search []struct {
-			Ns        int       `json:"ns"`
-			Title     string    `json:"title"`
-			PageID    int       `json:"pageid"`
-			Size      int       `json:"size"`
-			WordCount int       `json:"wordcount"`
-			Snippet   string    `json:"snippet"`
-			Timestamp time.Time `json:"timestamp"`
-		} `json:"search"`
+		Pages map[string]struct {
+			PageID    int    `json:"pageid"`
+			Title     string `json:"title"`
+			Index     int    `json:"index"`
+			Extract   string `json:"extract"`
+			FullURL   string `json:"fullurl"`
+			PageProps struct {
+				Disambiguation *string `json:"disambiguation"`
+				WikibaseItem   string  `json:"wikibase_item"`
+				ShortDesc      string  `json:"wikibase-shortdesc"`
+			} `json:"pageprops"`
+			Links []struct {
+				Title string `json:"title"`
+			} `json:"links"`
+		} `json:"pages"`
 	} `json:"query"`
 }
 
-func search(
-	ctx context.Context,
-	limit int,
-	query,
-	languageCode,
-	userAgent string,
-) (searchResponse, error) {
-	params := make(url.Values)
-	params.Add("format", "json")
-	params.Add("action", "query")
-	params.Add("list", "search")
-	params.Add("srsearch", query)
-	params.Add("srlimit", fmt.Sprintf("%v", limit))
-
-	reqURL := fmt.Sprintf("%s?%s", fmt.Sprintf(_baseURL, languageCode), params.Encode())
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return searchResponse{}, fmt.Errorf("creating request in wikipedia: %w ", err)
+// search returns the summaries of the pages found for the query, in the order
+// of the search results.
+func (c client) search(ctx context.Context, query string, limit int) ([]Page, error) {
+	params := summaryParams()
+	params.Set("generator", "search")
+	params.Set("gsrsearch", query)
+	params.Set("gsrlimit", strconv.Itoa(limit))
+
+	var res queryResponse
+	if err := c.get(ctx, params, &res); err != nil {
+		return nil, err
 	}
-	req.Header.Add("User-Agent", userAgent)
 
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return searchResponse{}, fmt.Errorf("doing response in wikipedia: %w", err)
+	type indexed struct {
+		index int
+		page  Page
 	}
-	defer res.Body.Close()
-
-	buf := new(bytes.Buffer)
-	_, err = io.Copy(buf, res.Body)
-	if err != nil {
-		return searchResponse{}, fmt.Errorf("coping data in wikipedia: %w", err)
+	results := make([]indexed, 0, len(res.Query.Pages))
+	for _, p := range res.Query.Pages {
+		if p.PageID == 0 {
+			continue
+		}
+		results = append(results, indexed{index: p.Index, page: Page{
+			Title:          p.Title,
+			Summary:        strings.TrimSpace(p.Extract),
+			URL:            p.FullURL,
+			Description:    p.PageProps.ShortDesc,
+			WikidataID:     p.PageProps.WikibaseItem,
+			Disambiguation: p.PageProps.Disambiguation != nil,
+		}})
 	}
+	sort.Slice(results, func(i, j int) bool { return results[i].index < results[j].index })
 
-	var result searchResponse
-	err = json.Unmarshal(buf.Bytes(), &result)
-	if err != nil {
-		return searchResponse{}, fmt.Errorf("unmarshal data in wikipedia: %w", err)
+	pages := make([]Page, 0, len(results))
+	for _, r := range results {
+		pages = append(pages, r.page)
 	}
-
-	return result, nil
+	return pages, nil
 }
 
-type pageResult struct {
-	Query struct {
-		Pages map[string]struct {
-			Title   string `json:"title"`
-			Extract string `json:"extract"`
-		} `json:"pages"`
-	} `json:"query"`
+// links returns the titles of the articles a page links to, such as the
+// meanings listed by a disambiguation page.
+func (c client) links(ctx context.Context, title string, limit int) ([]string, error) {
+	params := url.Values{}
+	params.Set("format", "json")
+	params.Set("action", "query")
+	params.Set("prop", "links")
+	params.Set("titles", title)
+	params.Set("plnamespace", "0")
+	params.Set("pllimit", strconv.Itoa(limit))
+
+	var res queryResponse
+	if err := c.get(ctx, params, &res); err != nil {
+		return nil, err
+	}
+	var titles []string
+	for _, p := range res.Query.Pages {
+		for _, l := range p.Links {
+			titles = append(titles, l.Title)
+		}
+	}
+	return titles, nil
 }
 
-func getPage(ctx context.Context, pageID int, languageCode, userAgent string) (pageResult, error) {
-	params := make(url.Values)
-	params.Add("format", "json")
-	params.Add("action", "query")
-	params.Add("prop", "extracts")
-	params.Add("pageids", fmt.Sprintf("%v", (pageID)))
-
-	reqURL := fmt.Sprintf("%s?%s", fmt.Sprintf(_baseURL, languageCode), params.Encode())
+// summaryParams returns the parameters of a query for the plain text
+// introductions, urls and Wikidata properties of pages.
+func summaryParams() url.Values {
+	params := url.Values{}
+	params.Set("format", "json")
+	params.Set("action", "query")
+	params.Set("prop", "extracts|info|pageprops")
+	params.Set("exintro", "1")
+	params.Set("explaintext", "1")
+	params.Set("exlimit", "max")
+	params.Set("inprop", "url")
+	params.Set("ppprop", "disambiguation|wikibase_item|wikibase-shortdesc")
+	params.Set("redirects", "1")
+	return params
+}
 
+func (c client) get(ctx context.Context, params url.Values, v any) error {
+	reqURL := fmt.Sprintf("%s?%s", c.endpoint, params.Encode())
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return pageResult{}, fmt.Errorf("creating request in wikipedia: %w ", err)
+		return fmt.Errorf("creating request in wikipedia: %w", err)
 	}
-	req.Header.Add("User-Agent", userAgent)
+	req.Header.Add("User-Agent", c.userAgent)
 
-	res, err := http.DefaultClient.Do(req)
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	res, err := httpClient.Do(req)
 	if err != nil {
-		return pageResult{}, fmt.Errorf("doing response in wikipedia: %w", err)
+		return fmt.Errorf("doing response in wikipedia: %w", err)
 	}
 	defer res.Body.Close()
 
-	buf := new(bytes.Buffer)
-	_, err = io.Copy(buf, res.Body)
-	if err != nil {
-		return pageResult{}, fmt.Errorf("coping data in wikipedia: %w", err)
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status code %d", ErrUnexpectedAPIResult, res.StatusCode)
 	}
-
-	var result pageResult
-	err = json.Unmarshal(buf.Bytes(), &result)
-	if err != nil {
-		return pageResult{}, fmt.Errorf("unmarshal data in wikipedia: %w", err)
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("unmarshal data in wikipedia: %w", err)
 	}
-
-	return result, nil
+	return nil
 }
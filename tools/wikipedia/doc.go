@@ -1,3 +1,9 @@
 // Package wikipedia contains an implementation of the tool interface with the
 // wikipedia api.
+//
+// The tool searches the Wikipedia of a language and returns the plain text
+// introductions of the articles found, with their short descriptions and the
+// ids of their Wikidata items. Disambiguation pages found are replaced by the
+// next articles and reported with the meanings they list, so that agents can
+// refine ambiguous queries. The API needs no key.
 package wikipedia
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/tmc/langchaingo/tools"
 )
@@ -12,11 +15,20 @@ const (
 	_defaultTopK         = 2
 	_defaultDocMaxChars  = 2000
 	_defaultLanguageCode = "en"
+	// _extraSearchResults is the number of search results fetched on top of
+	// TopK, to replace the disambiguation pages found.
+	_extraSearchResults = 3
+	// _maxMeanings is the number of meanings listed for a disambiguation page.
+	_maxMeanings = 20
 )
 
 // ErrUnexpectedAPIResult is returned if the result form the wikipedia api is unexpected.
 var ErrUnexpectedAPIResult = errors.New("unexpected result from wikipedia api")
 
+// _languagePrefix matches a query starting with a language code, such as
+// "fr: Tour Eiffel".
+var _languagePrefix = regexp.MustCompile(`^([a-z]{2,3}(?:-[a-z]+)?):\s*(\S.*)$`)
+
 // Tool is an implementation of the tool interface that finds information using the wikipedia api.
 type Tool struct {
 	// The number of wikipedia pages to include in the result.
@@ -27,6 +39,11 @@ type Tool struct {
 	LanguageCode string
 	// The user agent sent in the heder. See https://www.mediawiki.org/wiki/API:Etiquette.
 	UserAgent string
+	// BaseURL is the url of the API, with %s for the language code. Defaults
+	// to https://%s.wikipedia.org/w/api.php.
+	BaseURL string
+	// HTTPClient is the client of the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
 }
 
 var _ tools.Tool = Tool{}
@@ -51,39 +68,126 @@ func (t Tool) Description() string {
 	A wrapper around Wikipedia. 
 	Useful for when you need to answer general questions about 
 	people, places, companies, facts, historical events, or other subjects. 
-	Input should be a search query.`
+	Input should be a search query. To search the Wikipedia of another 
+	language, start the query with its code, such as "fr: Tour Eiffel".`
+}
+
+// Disambiguation is a disambiguation page found by a lookup, with the titles
+// of the pages of the meanings it lists.
+type Disambiguation struct {
+	Title    string
+	Meanings []string
+}
+
+// LookupResult is the outcome of a lookup.
+type LookupResult struct {
+	// Pages are the summaries of the articles found, disambiguation pages left
+	// out.
+	Pages []Page
+	// Disambiguations are the disambiguation pages found.
+	Disambiguations []Disambiguation
+}
+
+// Lookup searches the Wikipedia of the language code of the tool and returns
+// the summaries of the TopK first articles found. Disambiguation pages are
+// replaced by the next articles found and returned with the meanings they
+// list, so that a more precise query can be made.
+func (t Tool) Lookup(ctx context.Context, query string) (LookupResult, error) {
+	c := t.client()
+	pages, err := c.search(ctx, query, t.topK()+_extraSearchResults)
+	if err != nil {
+		return LookupResult{}, err
+	}
+
+	result := LookupResult{}
+	for _, page := range pages {
+		if page.Disambiguation {
+			meanings, err := c.links(ctx, page.Title, _maxMeanings)
+			if err != nil {
+				return LookupResult{}, err
+			}
+			result.Disambiguations = append(result.Disambiguations, Disambiguation{
+				Title:    page.Title,
+				Meanings: meanings,
+			})
+			continue
+		}
+		if len(result.Pages) < t.topK() {
+			result.Pages = append(result.Pages, page)
+		}
+	}
+	return result, nil
 }
 
 // Call uses the wikipedia api to find the top search results for the input and returns
-// the first part of the documents combined.
+// the first part of their summaries, with the meanings of the disambiguation
+// pages found. A language code prefix of the input, such as "fr:", selects the
+// Wikipedia searched.
 func (t Tool) Call(ctx context.Context, input string) (string, error) {
-	searchResult, err := search(ctx, t.TopK, input, t.LanguageCode, t.UserAgent)
+	input = strings.TrimSpace(input)
+	if m := _languagePrefix.FindStringSubmatch(input); m != nil {
+		t.LanguageCode, input = m[1], m[2]
+	}
+
+	result, err := t.Lookup(ctx, input)
 	if err != nil {
 		return "", err
 	}
-
-	if len(searchResult.Query.Search) == 0 {
+	if len(result.Pages) == 0 && len(result.Disambiguations) == 0 {
 		return "no wikipedia pages found", nil
 	}
 
-	result := ""
-
-	for _, search := range searchResult.Query.Search {
-		getPageResult, err := getPage(ctx, search.PageID, t.LanguageCode, t.UserAgent)
-		if err != nil {
-			return "", err
+	parts := make([]string, 0, len(result.Pages)+len(result.Disambiguations))
+	for _, page := range result.Pages {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Title: %s\n", page.Title)
+		if page.Description != "" {
+			fmt.Fprintf(&sb, "Description: %s\n", page.Description)
 		}
-
-		page, ok := getPageResult.Query.Pages[fmt.Sprintf("%v", search.PageID)]
-		if !ok {
-			return "", ErrUnexpectedAPIResult
+		if page.WikidataID != "" {
+			fmt.Fprintf(&sb, "Wikidata: %s\n", page.WikidataID)
 		}
-		if len(page.Extract) >= t.DocMaxChars {
-			result += page.Extract[0:t.DocMaxChars]
-			continue
+		if page.URL != "" {
+			fmt.Fprintf(&sb, "URL: %s\n", page.URL)
 		}
-		result += page.Extract
+		sb.WriteString(truncate(page.Summary, t.DocMaxChars))
+		parts = append(parts, strings.TrimRight(sb.String(), "\n"))
 	}
+	for _, d := range result.Disambiguations {
+		parts = append(parts, fmt.Sprintf("%q may refer to: %s", d.Title, strings.Join(d.Meanings, "; ")))
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
 
-	return result, nil
+func (t Tool) client() client {
+	baseURL := t.BaseURL
+	if baseURL == "" {
+		baseURL = _baseURL
+	}
+	languageCode := t.LanguageCode
+	if languageCode == "" {
+		languageCode = _defaultLanguageCode
+	}
+	return client{
+		endpoint:   fmt.Sprintf(baseURL, languageCode),
+		userAgent:  t.UserAgent,
+		httpClient: t.HTTPClient,
+	}
+}
+
+func (t Tool) topK() int {
+	if t.TopK <= 0 {
+		return _defaultTopK
+	}
+	return t.TopK
+}
+
+// truncate returns the first maxChars characters of the text, or the whole
+// text if maxChars is not positive.
+func truncate(text string, maxChars int) string {
+	runes := []rune(text)
+	if maxChars <= 0 || len(runes) <= maxChars {
+		return text
+	}
+	return string(runes[:maxChars])
 }
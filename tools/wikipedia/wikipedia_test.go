@@ -2,9 +2,13 @@ package wikipedia
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const _userAgent = "langchaingo test (https://github.com/tmc/langchaingo)"
@@ -16,3 +20,75 @@ func TestWikipedia(t *testing.T) {
 	_, err := tool.Call(context.Background(), "america")
 	assert.NoError(t, err)
 }
+
+func newTestTool(t *testing.T) Tool {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/fr/api.php" && q.Get("generator") == "search":
+			fmt.Fprint(w, `{"query": {"pages": {"1": {"pageid": 1, "title": "Tour Eiffel", "index": 1,
+				"extract": "La tour Eiffel est une tour.", "fullurl": "https://fr.wikipedia.org/wiki/Tour_Eiffel"}}}}`)
+		case r.URL.Path == "/en/api.php" && q.Get("gsrsearch") == "mercury" && q.Get("gsrlimit") == "4":
+			fmt.Fprint(w, `{"query": {"pages": {
+				"10": {"pageid": 10, "title": "Mercury (planet)", "index": 2, "extract": "Mercury is the first planet.",
+					"fullurl": "https://en.wikipedia.org/wiki/Mercury_(planet)",
+					"pageprops": {"wikibase_item": "Q308", "wikibase-shortdesc": "First planet from the Sun"}},
+				"11": {"pageid": 11, "title": "Mercury", "index": 1, "extract": "Mercury may refer to:",
+					"pageprops": {"disambiguation": ""}},
+				"12": {"pageid": 12, "title": "Mercury (element)", "index": 3, "extract": "Mercury is a chemical element."}
+			}}}`)
+		case q.Get("prop") == "links" && q.Get("titles") == "Mercury":
+			fmt.Fprint(w, `{"query": {"pages": {"11": {"pageid": 11, "title": "Mercury",
+				"links": [{"title": "Mercury (element)"}, {"title": "Mercury (mythology)"}, {"title": "Mercury (planet)"}]}}}}`)
+		default:
+			fmt.Fprint(w, `{"query": {}}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	tool := New(_userAgent)
+	tool.TopK = 1
+	tool.DocMaxChars = 16
+	tool.BaseURL = srv.URL + "/%s/api.php"
+	return tool
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	result, err := newTestTool(t).Lookup(context.Background(), "mercury")
+	require.NoError(t, err)
+	require.Equal(t, LookupResult{
+		Pages: []Page{{
+			Title:       "Mercury (planet)",
+			Summary:     "Mercury is the first planet.",
+			URL:         "https://en.wikipedia.org/wiki/Mercury_(planet)",
+			Description: "First planet from the Sun",
+			WikidataID:  "Q308",
+		}},
+		Disambiguations: []Disambiguation{{
+			Title:    "Mercury",
+			Meanings: []string{"Mercury (element)", "Mercury (mythology)", "Mercury (planet)"},
+		}},
+	}, result)
+}
+
+func TestCall(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t)
+
+	out, err := tool.Call(context.Background(), "mercury")
+	require.NoError(t, err)
+	require.Equal(t, "Title: Mercury (planet)\nDescription: First planet from the Sun\nWikidata: Q308\n"+
+		"URL: https://en.wikipedia.org/wiki/Mercury_(planet)\nMercury is the f\n\n"+
+		`"Mercury" may refer to: Mercury (element); Mercury (mythology); Mercury (planet)`, out)
+
+	out, err = tool.Call(context.Background(), "fr: Tour Eiffel")
+	require.NoError(t, err)
+	require.Equal(t, "Title: Tour Eiffel\nURL: https://fr.wikipedia.org/wiki/Tour_Eiffel\nLa tour Eiffel e", out)
+
+	out, err = tool.Call(context.Background(), "nothing")
+	require.NoError(t, err)
+	require.Equal(t, "no wikipedia pages found", out)
+}
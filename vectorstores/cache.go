@@ -0,0 +1,197 @@
+package vectorstores
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	_defaultCacheSimilarityThreshold = 0.95
+	_defaultCacheMaxEntries          = 1000
+)
+
+// IndexChange is a change of the documents of a name space of a vector store.
+type IndexChange struct {
+	NameSpace string
+	// Upserted and Deleted are the numbers of documents added or updated, and
+	// deleted.
+	Upserted int
+	Deleted  int
+	// Version is the version of the name space after the change, for
+	// versioned stores.
+	Version int
+}
+
+// IndexListener is notified when documents of a vector store are upserted or
+// deleted, by the vector store or by the code managing its index.
+type IndexListener interface {
+	OnIndexChange(ctx context.Context, change IndexChange)
+}
+
+// RetrieverCache caches the documents returned by retrievers for queries. A
+// query hits the cache when the embedding of a previous query of the same name
+// space is similar enough, so that rephrased questions are answered without a
+// search. The documents of a name space are evicted when the name space
+// changes, which the code upserting or deleting documents reports to the cache
+// with Invalidate or, as an IndexListener, with OnIndexChange.
+type RetrieverCache struct {
+	embedder   embeddings.Embedder
+	threshold  float64
+	maxEntries int
+
+	mu sync.Mutex
+	// entries are the cached queries of each name space, the most recently
+	// used last.
+	entries map[string][]retrieverCacheEntry
+	// generations are incremented by the invalidations of each name space, so
+	// that searches started before an invalidation are not cached.
+	generations map[string]uint64
+}
+
+type retrieverCacheEntry struct {
+	vector []float64
+	docs   []schema.Document
+}
+
+var _ IndexListener = (*RetrieverCache)(nil)
+
+// CacheOption is a function that configures a RetrieverCache.
+type CacheOption func(*RetrieverCache)
+
+// WithCacheSimilarityThreshold sets the cosine similarity from which the
+// embeddings of two queries are the same query. Defaults to 0.95.
+func WithCacheSimilarityThreshold(threshold float64) CacheOption {
+	return func(c *RetrieverCache) {
+		c.threshold = threshold
+	}
+}
+
+// WithCacheMaxEntries sets the number of queries cached by name space, the
+// least recently used ones being evicted first. Defaults to 1000.
+func WithCacheMaxEntries(maxEntries int) CacheOption {
+	return func(c *RetrieverCache) {
+		c.maxEntries = maxEntries
+	}
+}
+
+// NewRetrieverCache creates a new empty RetrieverCache comparing the queries
+// embedded with the embedder.
+func NewRetrieverCache(embedder embeddings.Embedder, opts ...CacheOption) *RetrieverCache {
+	c := &RetrieverCache{
+		embedder:    embedder,
+		threshold:   _defaultCacheSimilarityThreshold,
+		maxEntries:  _defaultCacheMaxEntries,
+		entries:     make(map[string][]retrieverCacheEntry),
+		generations: make(map[string]uint64),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Retriever returns a retriever returning the cached documents of the name
+// space for the queries, and those of the retriever otherwise.
+func (c *RetrieverCache) Retriever(retriever schema.Retriever, nameSpace string) CachedRetriever {
+	return CachedRetriever{cache: c, retriever: retriever, nameSpace: nameSpace}
+}
+
+// Invalidate evicts the cached documents of the name space.
+func (c *RetrieverCache) Invalidate(nameSpace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, nameSpace)
+	c.generations[nameSpace]++
+}
+
+// OnIndexChange invalidates the name space of the change.
+func (c *RetrieverCache) OnIndexChange(_ context.Context, change IndexChange) {
+	c.Invalidate(change.NameSpace)
+}
+
+// lookup returns the documents of the most similar cached query of the name
+// space, if similar enough, and the generation of the name space.
+func (c *RetrieverCache) lookup(nameSpace string, vector []float64) ([]schema.Document, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := c.entries[nameSpace]
+	best, bestScore := -1, c.threshold
+	for i, e := range entries {
+		if score := cosine(vector, e.vector); score >= bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return nil, false, c.generations[nameSpace]
+	}
+
+	e := entries[best]
+	entries = append(entries[:best], entries[best+1:]...)
+	c.entries[nameSpace] = append(entries, e)
+	return e.docs, true, c.generations[nameSpace]
+}
+
+// store caches the documents of the query, unless the name space changed
+// since the generation.
+func (c *RetrieverCache) store(nameSpace string, generation uint64, vector []float64, docs []schema.Document) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[nameSpace] != generation || c.maxEntries <= 0 {
+		return
+	}
+	entries := append(c.entries[nameSpace], retrieverCacheEntry{vector: vector, docs: docs})
+	if len(entries) > c.maxEntries {
+		entries = entries[len(entries)-c.maxEntries:]
+	}
+	c.entries[nameSpace] = entries
+}
+
+// CachedRetriever is a retriever caching the documents of another retriever in
+// a RetrieverCache.
+type CachedRetriever struct {
+	cache     *RetrieverCache
+	retriever schema.Retriever
+	nameSpace string
+}
+
+var _ schema.Retriever = CachedRetriever{}
+
+// GetRelevantDocuments returns the cached documents of a similar query, or
+// the documents of the retriever which are then cached.
+func (r CachedRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	vector, err := r.cache.embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, ok, generation := r.cache.lookup(r.nameSpace, vector)
+	if ok {
+		return append([]schema.Document(nil), docs...), nil
+	}
+
+	docs, err = r.retriever.GetRelevantDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.store(r.nameSpace, generation, vector, append([]schema.Document(nil), docs...))
+	return docs, nil
+}
+
+func cosine(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -7,6 +7,8 @@ The main components of this package are:
 - VectorStore interface: a common interface for saving and querying vector embeddings of documents.
 - Options: a set of options for similarity search and document addition.
 - Retriever: a retriever for vector stores that implements the schema.Retriever interface.
+- VersionedStore interface: a vector store keeping versions of its name spaces, with soft-deleted documents, to pin searches to a version and roll back bad re-indexes.
+- RetrieverCache: a cache of retrieved documents for similar queries, invalidated by IndexListener notifications.
 
 The package provides a flexible way to handle different types of vector stores
 by using the VectorStore interface as an abstraction.
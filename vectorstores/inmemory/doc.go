@@ -0,0 +1,5 @@
+// Package inmemory contains an implementation of the vectorStore interface
+// keeping the vectors in memory. The store can be saved to and loaded from a
+// JSON or gob file, which makes it a handy backend for tests and small
+// retrieval chains.
+package inmemory
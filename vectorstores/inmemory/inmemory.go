@@ -0,0 +1,229 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	// ErrUnsupportedFilter is returned when the filters given to SimilaritySearch
+	// are neither a map[string]any nor a func(map[string]any) bool.
+	ErrUnsupportedFilter = errors.New("unsupported filter type")
+)
+
+// nolint:gochecknoglobals
+var similarityFuncs = map[Similarity]func(a, b []float32) float64{
+	Cosine:     cosine,
+	DotProduct: dot,
+	Euclidean:  euclidean,
+}
+
+// entry is a document stored with its vector. Vectors are kept as float32,
+// embedded without going through float64 if the embedder implements
+// embeddings.Float32Embedder, to halve the memory they take.
+type entry struct {
+	Vector   []float32
+	Document schema.Document
+	// Version is the version of the name space the document was added at, and
+	// Deleted the one it was deleted or replaced at, 0 while it is not.
+	Version int `json:",omitempty"`
+	Deleted int `json:",omitempty"`
+}
+
+// visible reports whether the document was in the name space at the version.
+func (e entry) visible(version int) bool {
+	return e.Version <= version && (e.Deleted == 0 || e.Deleted > version)
+}
+
+// Store is a vector store keeping the documents and their vectors in memory.
+// Searches compare the query with every document of the name space. Deleted
+// and replaced documents are kept until the store is compacted, so that
+// searches can be pinned to a previous version.
+type Store struct {
+	embedder   embeddings.Embedder
+	similarity Similarity
+	listeners  []vectorstores.IndexListener
+
+	mu sync.RWMutex
+	// entries are the documents of each name space, and versions their
+	// current versions.
+	entries  map[string][]entry
+	versions map[string]int
+}
+
+var _ vectorstores.VersionedStore = (*Store)(nil)
+
+// New creates a new empty Store with options. The embedder must be set.
+func New(opts ...Option) (*Store, error) {
+	return applyClientOptions(opts...)
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and adds them to a new version of the name space of the options, notifying
+// the index listeners. Documents with the id of a document of the name space,
+// in their vectorstores.IDKey metadata, replace it.
+func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := embeddings.EmbedFloat32(ctx, opts.Embedder, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+
+	s.mu.Lock()
+	version := s.versions[opts.NameSpace] + 1
+	s.versions[opts.NameSpace] = version
+	for i, doc := range docs {
+		if id, ok := doc.Metadata[vectorstores.IDKey]; ok {
+			s.tombstone(opts.NameSpace, version, func(e entry) bool {
+				return e.Document.Metadata[vectorstores.IDKey] == id
+			})
+		}
+		s.entries[opts.NameSpace] = append(s.entries[opts.NameSpace], entry{
+			Vector:   vectors[i],
+			Document: versioned(doc, version),
+			Version:  version,
+		})
+	}
+	s.mu.Unlock()
+
+	s.notify(ctx, vectorstores.IndexChange{NameSpace: opts.NameSpace, Upserted: len(docs), Version: version})
+	return nil
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and returns the numDocuments most similar documents of the name space.
+// Documents with a score below the score threshold are left out. Filters are
+// either a map[string]any of metadata values the documents must have, or a
+// func(map[string]any) bool called with the metadata of each document. Search
+// the documents of a previous version with vectorstores.WithVersion.
+func (s *Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+
+	match, err := matcher(opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := embeddings.EmbedQueryFloat32(ctx, opts.Embedder, query)
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		doc   schema.Document
+		score float64
+	}
+	similarity := similarityFuncs[s.similarity]
+
+	s.mu.RLock()
+	version, err := s.version(opts)
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, err
+	}
+	results := make([]scored, 0, len(s.entries[opts.NameSpace]))
+	for _, e := range s.entries[opts.NameSpace] {
+		if !e.visible(version) || !match(e.Document.Metadata) {
+			continue
+		}
+		score := similarity(vector, e.Vector)
+		if opts.ScoreThreshold != 0 && score < opts.ScoreThreshold {
+			continue
+		}
+		results = append(results, scored{doc: e.Document, score: score})
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+	if numDocuments >= 0 && len(results) > numDocuments {
+		results = results[:numDocuments]
+	}
+
+	docs := make([]schema.Document, 0, len(results))
+	for _, r := range results {
+		docs = append(docs, r.doc)
+	}
+	return docs, nil
+}
+
+func (s *Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Embedder == nil {
+		opts.Embedder = s.embedder
+	}
+	return opts
+}
+
+// matcher returns a function reporting whether metadata matches the filters.
+func matcher(filters any) (func(map[string]any) bool, error) {
+	switch f := filters.(type) {
+	case nil:
+		return func(map[string]any) bool { return true }, nil
+	case func(map[string]any) bool:
+		return f, nil
+	case map[string]any:
+		return func(metadata map[string]any) bool {
+			for key, want := range f {
+				got, ok := metadata[key]
+				if !ok || !reflect.DeepEqual(got, want) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	default:
+		return nil, ErrUnsupportedFilter
+	}
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+func cosine(a, b []float32) float64 {
+	norms := math.Sqrt(dot(a, a) * dot(b, b))
+	if norms == 0 {
+		return 0
+	}
+	return dot(a, b) / norms
+}
+
+func euclidean(a, b []float32) float64 {
+	var sum float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return 1 / (1 + math.Sqrt(sum))
+}
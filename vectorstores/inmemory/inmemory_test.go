@@ -0,0 +1,240 @@
+package inmemory
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeEmbedder embeds texts as the number of times they contain "a", "b" and
+// "c".
+type fakeEmbedder struct{}
+
+var _ embeddings.Embedder = fakeEmbedder{}
+
+func (e fakeEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		v, err := e.EmbedQuery(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+func (fakeEmbedder) EmbedQuery(_ context.Context, text string) ([]float64, error) {
+	return []float64{
+		float64(strings.Count(text, "a")),
+		float64(strings.Count(text, "b")),
+		float64(strings.Count(text, "c")),
+	}, nil
+}
+
+func newTestStore(t *testing.T, opts ...Option) *Store {
+	t.Helper()
+
+	s, err := New(append([]Option{WithEmbedder(fakeEmbedder{})}, opts...)...)
+	require.NoError(t, err)
+	err = s.AddDocuments(context.Background(), []schema.Document{
+		{PageContent: "aaa", Metadata: map[string]any{"letter": "a", "page": 1}},
+		{PageContent: "bbb", Metadata: map[string]any{"letter": "b", "page": 2}},
+		{PageContent: "aab", Metadata: map[string]any{"letter": "a", "page": 3}},
+		{PageContent: "ccc", Metadata: map[string]any{"letter": "c", "page": 4}},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func contents(docs []schema.Document) []string {
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	return texts
+}
+
+func TestNewRequiresEmbedder(t *testing.T) {
+	t.Parallel()
+
+	_, err := New()
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = New(WithEmbedder(fakeEmbedder{}), WithSimilarity("manhattan"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		similarity Similarity
+		query      string
+		want       []string
+	}{
+		{Cosine, "a", []string{"aaa", "aab"}},
+		{DotProduct, "a", []string{"aaa", "aab"}},
+		{Euclidean, "aab", []string{"aab", "aaa"}},
+		{Cosine, "bc", []string{"bbb", "ccc"}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(string(tc.similarity)+"/"+tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTestStore(t, WithSimilarity(tc.similarity))
+			docs, err := s.SimilaritySearch(context.Background(), tc.query, 2)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, contents(docs))
+		})
+	}
+}
+
+func TestSimilaritySearchOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	docs, err := s.SimilaritySearch(ctx, "a", 10, vectorstores.WithScoreThreshold(0.8))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aaa", "aab"}, contents(docs))
+
+	docs, err = s.SimilaritySearch(ctx, "b", 10, vectorstores.WithFilters(map[string]any{"letter": "a"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aab", "aaa"}, contents(docs))
+
+	docs, err = s.SimilaritySearch(ctx, "b", 10, vectorstores.WithFilters(func(metadata map[string]any) bool {
+		return metadata["page"].(int) > 2
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aab", "ccc"}, contents(docs))
+
+	_, err = s.SimilaritySearch(ctx, "b", 10, vectorstores.WithFilters("letter = a"))
+	require.ErrorIs(t, err, ErrUnsupportedFilter)
+
+	docs, err = s.SimilaritySearch(ctx, "a", 10, vectorstores.WithNameSpace("other"))
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"store.json", "store.gob"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, newTestStore(t).SaveFile(path))
+
+			s, err := New(WithEmbedder(fakeEmbedder{}))
+			require.NoError(t, err)
+			require.NoError(t, s.LoadFile(path))
+
+			docs, err := s.SimilaritySearch(context.Background(), "c", 1)
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "ccc", docs[0].PageContent)
+			assert.EqualValues(t, 4, docs[0].Metadata["page"])
+		})
+	}
+}
+
+// countingRetriever counts the searches of a retriever.
+type countingRetriever struct {
+	schema.Retriever
+	searches int
+}
+
+func (r *countingRetriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	r.searches++
+	return r.Retriever.GetRelevantDocuments(ctx, query)
+}
+
+func TestRetrieverCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	cache := vectorstores.NewRetrieverCache(fakeEmbedder{}, vectorstores.WithCacheSimilarityThreshold(0.99))
+	store, err := New(WithEmbedder(fakeEmbedder{}), WithIndexListener(cache))
+	require.NoError(t, err)
+	require.NoError(t, store.AddDocuments(ctx, []schema.Document{{PageContent: "aa"}, {PageContent: "bb"}},
+		vectorstores.WithNameSpace("ns")))
+
+	counting := &countingRetriever{Retriever: vectorstores.ToRetriever(store, 1, vectorstores.WithNameSpace("ns"))}
+	retriever := cache.Retriever(counting, "ns")
+
+	docs, err := retriever.GetRelevantDocuments(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "aa", docs[0].PageContent)
+	docs, err = retriever.GetRelevantDocuments(ctx, "aaa")
+	require.NoError(t, err)
+	assert.Equal(t, "aa", docs[0].PageContent)
+	assert.Equal(t, 1, counting.searches)
+
+	_, err = retriever.GetRelevantDocuments(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, 2, counting.searches)
+
+	require.NoError(t, store.AddDocuments(ctx, []schema.Document{{PageContent: "aaaa c"}}, vectorstores.WithNameSpace("other")))
+	_, err = retriever.GetRelevantDocuments(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 2, counting.searches)
+
+	require.NoError(t, store.AddDocuments(ctx, []schema.Document{{PageContent: "a"}}, vectorstores.WithNameSpace("ns")))
+	_, err = retriever.GetRelevantDocuments(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, counting.searches)
+}
+
+func TestVersioning(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store, err := New(WithEmbedder(fakeEmbedder{}))
+	require.NoError(t, err)
+	search := func(options ...vectorstores.Option) []string {
+		t.Helper()
+		docs, err := store.SimilaritySearch(ctx, "abc", -1, options...)
+		require.NoError(t, err)
+		return contents(docs)
+	}
+
+	require.NoError(t, store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "aa", Metadata: map[string]any{"id": "1"}},
+		{PageContent: "bb", Metadata: map[string]any{"id": "2"}},
+	}))
+	// A bad re-index replacing document 1 and deleting document 2.
+	require.NoError(t, store.AddDocuments(ctx, []schema.Document{{PageContent: "c", Metadata: map[string]any{"id": "1"}}}))
+	require.NoError(t, store.DeleteDocuments(ctx, []string{"2"}))
+
+	version, err := store.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+	assert.Equal(t, []string{"c"}, search())
+	assert.Equal(t, []string{"aa", "bb"}, search(vectorstores.WithVersion(1)))
+	assert.Equal(t, []string{"bb", "c"}, search(vectorstores.WithVersion(2)))
+	_, err = store.SimilaritySearch(ctx, "a", 1, vectorstores.WithVersion(4))
+	require.ErrorIs(t, err, vectorstores.ErrUnknownVersion)
+
+	require.NoError(t, store.Rollback(ctx, 1))
+	docs, err := store.SimilaritySearch(ctx, "abc", -1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aa", "bb"}, contents(docs))
+	assert.Equal(t, 4, docs[0].Metadata[vectorstores.VersionKey])
+	assert.Equal(t, []string{"c"}, search(vectorstores.WithVersion(3)))
+	require.ErrorIs(t, store.Rollback(ctx, 5), vectorstores.ErrUnknownVersion)
+
+	store.Compact(3)
+	assert.Equal(t, []string{"aa", "bb"}, search())
+	assert.Empty(t, search(vectorstores.WithVersion(1)))
+}
@@ -0,0 +1,71 @@
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Similarity is the function used to compare vectors.
+type Similarity string
+
+const (
+	// Cosine compares the angle between vectors. Scores range from -1 to 1.
+	Cosine Similarity = "cosine"
+	// DotProduct compares vectors by their dot product. It is the same as Cosine
+	// for normalized vectors, and faster.
+	DotProduct Similarity = "dot"
+	// Euclidean compares vectors by their distance d, with a score of 1/(1+d)
+	// ranging from 0 to 1.
+	Euclidean Similarity = "euclidean"
+)
+
+// Option is a function type that can be used to modify the store.
+type Option func(s *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(s *Store) {
+		s.embedder = e
+	}
+}
+
+// WithSimilarity is an option for setting the similarity function used to
+// search documents. Defaults to Cosine.
+func WithSimilarity(similarity Similarity) Option {
+	return func(s *Store) {
+		s.similarity = similarity
+	}
+}
+
+// WithIndexListener is an option for adding a listener notified of the
+// documents added to the store, such as a vectorstores.RetrieverCache.
+func WithIndexListener(l vectorstores.IndexListener) Option {
+	return func(s *Store) {
+		s.listeners = append(s.listeners, l)
+	}
+}
+
+func applyClientOptions(opts ...Option) (*Store, error) {
+	s := &Store{
+		similarity: Cosine,
+		entries:    make(map[string][]entry),
+		versions:   make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.embedder == nil {
+		return nil, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if _, ok := similarityFuncs[s.similarity]; !ok {
+		return nil, fmt.Errorf("%w: unknown similarity %q", ErrInvalidOptions, s.similarity)
+	}
+	return s, nil
+}
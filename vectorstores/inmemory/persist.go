@@ -0,0 +1,115 @@
+package inmemory
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrUnknownFormat is returned when saving or loading a store in a format other
+// than JSON and Gob.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Format is the encoding of a saved store.
+type Format string
+
+const (
+	// JSON saves the store as JSON. Numbers in the metadata of the documents
+	// are loaded back as float64.
+	JSON Format = "json"
+	// Gob saves the store with encoding/gob, keeping the types of metadata
+	// values. Values of types other than the basic Go types must be registered
+	// with gob.Register.
+	Gob Format = "gob"
+)
+
+func init() { //nolint:gochecknoinits
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+}
+
+// snapshot is the saved form of a store.
+type snapshot struct {
+	Entries  map[string][]entry `json:"entries"`
+	Versions map[string]int     `json:"versions,omitempty"`
+}
+
+// Save writes the documents and vectors of the store to w.
+func (s *Store) Save(w io.Writer, format Format) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snap := snapshot{Entries: s.entries, Versions: s.versions}
+	switch format {
+	case JSON:
+		return json.NewEncoder(w).Encode(snap)
+	case Gob:
+		return gob.NewEncoder(w).Encode(snap)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// Load replaces the documents and vectors of the store with the ones read
+// from r.
+func (s *Store) Load(r io.Reader, format Format) error {
+	var snap snapshot
+	var err error
+	switch format {
+	case JSON:
+		err = json.NewDecoder(r).Decode(&snap)
+	case Gob:
+		err = gob.NewDecoder(r).Decode(&snap)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return err
+	}
+	if snap.Entries == nil {
+		snap.Entries = make(map[string][]entry)
+	}
+	if snap.Versions == nil {
+		snap.Versions = make(map[string]int)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = snap.Entries
+	s.versions = snap.Versions
+	return nil
+}
+
+// SaveFile saves the store to a file, in the Gob format if the file name ends
+// with ".gob" and in the JSON format otherwise.
+func (s *Store) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := s.Save(f, formatOf(path)); err != nil {
+		return errors.Join(err, f.Close())
+	}
+	return f.Close()
+}
+
+// LoadFile loads the store from a file saved with SaveFile.
+func (s *Store) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return s.Load(f, formatOf(path))
+}
+
+func formatOf(path string) Format {
+	if filepath.Ext(path) == ".gob" {
+		return Gob
+	}
+	return JSON
+}
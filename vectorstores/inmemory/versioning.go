@@ -0,0 +1,140 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// DeleteDocuments soft-deletes the documents of the name space of the options
+// with the ids, in their vectorstores.IDKey metadata, creating a new version
+// of the name space and notifying the index listeners.
+func (s *Store) DeleteDocuments(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	deleted := make(map[any]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+
+	s.mu.Lock()
+	version := s.versions[opts.NameSpace] + 1
+	s.versions[opts.NameSpace] = version
+	n := s.tombstone(opts.NameSpace, version, func(e entry) bool {
+		id, ok := e.Document.Metadata[vectorstores.IDKey].(string)
+		return ok && deleted[id]
+	})
+	s.mu.Unlock()
+
+	s.notify(ctx, vectorstores.IndexChange{NameSpace: opts.NameSpace, Deleted: n, Version: version})
+	return nil
+}
+
+// Version returns the current version of the name space of the options.
+func (s *Store) Version(_ context.Context, options ...vectorstores.Option) (int, error) {
+	opts := s.getOptions(options...)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.versions[opts.NameSpace], nil
+}
+
+// Rollback creates a new version of the name space of the options with the
+// documents it had at the version, notifying the index listeners. The versions
+// in between are kept, so that a rollback can itself be rolled back.
+func (s *Store) Rollback(ctx context.Context, version int, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	s.mu.Lock()
+	current := s.versions[opts.NameSpace]
+	if version < 0 || version > current {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %d, the current version is %d", vectorstores.ErrUnknownVersion, version, current)
+	}
+	next := current + 1
+	s.versions[opts.NameSpace] = next
+
+	entries := s.entries[opts.NameSpace]
+	var upserted, deleted int
+	for i, e := range entries {
+		switch then, now := e.visible(version), e.visible(current); {
+		case now && !then:
+			entries[i].Deleted = next
+			deleted++
+		case then && !now:
+			entries = append(entries, entry{Vector: e.Vector, Document: versioned(e.Document, next), Version: next})
+			upserted++
+		}
+	}
+	s.entries[opts.NameSpace] = entries
+	s.mu.Unlock()
+
+	s.notify(ctx, vectorstores.IndexChange{
+		NameSpace: opts.NameSpace,
+		Upserted:  upserted,
+		Deleted:   deleted,
+		Version:   next,
+	})
+	return nil
+}
+
+// Compact removes the documents of the name space of the options deleted at
+// or before the version, freeing their memory. Searches can no longer be
+// pinned to versions up to the version.
+func (s *Store) Compact(version int, options ...vectorstores.Option) {
+	opts := s.getOptions(options...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.entries[opts.NameSpace][:0]
+	for _, e := range s.entries[opts.NameSpace] {
+		if e.Deleted == 0 || e.Deleted > version {
+			entries = append(entries, e)
+		}
+	}
+	s.entries[opts.NameSpace] = entries
+}
+
+// version returns the version of the name space searched with the options,
+// the current one unless pinned. s.mu must be held.
+func (s *Store) version(opts vectorstores.Options) (int, error) {
+	current := s.versions[opts.NameSpace]
+	if opts.Version == 0 {
+		return current, nil
+	}
+	if opts.Version < 0 || opts.Version > current {
+		return 0, fmt.Errorf("%w: %d, the current version is %d", vectorstores.ErrUnknownVersion, opts.Version, current)
+	}
+	return opts.Version, nil
+}
+
+// tombstone marks the current documents of the name space matching as deleted
+// at the version, returning their number. s.mu must be held for writing.
+func (s *Store) tombstone(nameSpace string, version int, match func(entry) bool) int {
+	n := 0
+	entries := s.entries[nameSpace]
+	for i, e := range entries {
+		if e.Deleted == 0 && match(e) {
+			entries[i].Deleted = version
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Store) notify(ctx context.Context, change vectorstores.IndexChange) {
+	for _, l := range s.listeners {
+		l.OnIndexChange(ctx, change)
+	}
+}
+
+// versioned returns a copy of the document with the version in its metadata.
+func versioned(doc schema.Document, version int) schema.Document {
+	metadata := make(map[string]any, len(doc.Metadata)+1)
+	for key, value := range doc.Metadata {
+		metadata[key] = value
+	}
+	metadata[vectorstores.VersionKey] = version
+	return schema.Document{PageContent: doc.PageContent, Metadata: metadata}
+}
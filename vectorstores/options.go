@@ -11,6 +11,7 @@ type Options struct {
 	ScoreThreshold float64
 	Filters        any
 	Embedder       embeddings.Embedder
+	Version        int
 }
 
 // WithNameSpace returns an Option for setting the name space.
@@ -44,3 +45,11 @@ func WithEmbedder(embedder embeddings.Embedder) Option {
 		o.Embedder = embedder
 	}
 }
+
+// WithVersion returns an Option for searching the documents a VersionedStore
+// had at a version of the name space, rather than its current documents.
+func WithVersion(version int) Option {
+	return func(o *Options) {
+		o.Version = version
+	}
+}
@@ -0,0 +1,186 @@
+package pgvector
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_pgvectorEnvVarName     = "PGVECTOR_CONNECTION_STRING"
+	_defaultCollectionName  = "langchain_pg_embedding"
+	_defaultSubjectKey      = "subject"
+	_defaultMaxOpenConns    = 10
+	_defaultHNSWM           = 16
+	_defaultHNSWEfConstruct = 64
+	_defaultIVFFlatLists    = 100
+)
+
+// ErrInvalidOptions is returned when the options given are invalid.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// Distance is the function used to compare vectors.
+type Distance string
+
+const (
+	// Cosine compares vectors by their cosine distance. Scores range from -1
+	// to 1.
+	Cosine Distance = "cosine"
+	// L2 compares vectors by their euclidean distance d, with a score of
+	// 1/(1+d).
+	L2 Distance = "l2"
+	// InnerProduct compares vectors by their inner product, which is the score.
+	InnerProduct Distance = "inner_product"
+)
+
+// IndexType is the type of the approximate nearest neighbor index of the table.
+type IndexType string
+
+const (
+	// NoIndex does exact nearest neighbor searches.
+	NoIndex IndexType = ""
+	// HNSW builds a hierarchical navigable small world index, see WithHNSWIndex.
+	HNSW IndexType = "hnsw"
+	// IVFFlat builds an inverted file index, see WithIVFFlatIndex.
+	IVFFlat IndexType = "ivfflat"
+)
+
+// Option is a function type that can be used to modify the client.
+type Option func(p *Store)
+
+// WithEmbedder is an option for setting the embedder to use. Must be set.
+func WithEmbedder(e embeddings.Embedder) Option {
+	return func(p *Store) {
+		p.embedder = e
+	}
+}
+
+// WithConnectionURL is an option for specifying the url of the database. If
+// neither the url nor the database are set, the url is read from the
+// PGVECTOR_CONNECTION_STRING environment variable.
+func WithConnectionURL(connectionURL string) Option {
+	return func(p *Store) {
+		p.connectionURL = connectionURL
+	}
+}
+
+// WithDB is an option for using an open database, for example to share its
+// connection pool. The database must use the pgx driver.
+func WithDB(db *sql.DB) Option {
+	return func(p *Store) {
+		p.db = db
+	}
+}
+
+// WithMaxOpenConns is an option for setting the size of the connection pool
+// opened from the connection url. Defaults to 10.
+func WithMaxOpenConns(n int) Option {
+	return func(p *Store) {
+		p.maxOpenConns = n
+	}
+}
+
+// WithCollectionName is an option for setting the name of the table storing
+// the documents. Defaults to "langchain_pg_embedding".
+func WithCollectionName(name string) Option {
+	return func(p *Store) {
+		p.collectionName = name
+	}
+}
+
+// WithVectorDimensions is an option for setting the number of dimensions of the
+// vectors. It is required to build an index.
+func WithVectorDimensions(dimensions int) Option {
+	return func(p *Store) {
+		p.dimensions = dimensions
+	}
+}
+
+// WithDistance is an option for setting the distance used by searches and
+// indexes. Defaults to Cosine.
+func WithDistance(distance Distance) Option {
+	return func(p *Store) {
+		p.distance = distance
+	}
+}
+
+// WithHNSWIndex is an option for building an HNSW index with the given
+// maximum number of connections per layer and size of the candidate list
+// during construction. Zero values use the pgvector defaults, 16 and 64.
+// HNSW indexes require pgvector 0.5.0 or later.
+func WithHNSWIndex(m, efConstruction int) Option {
+	return func(p *Store) {
+		p.index = HNSW
+		p.hnswM = m
+		p.hnswEfConstruction = efConstruction
+	}
+}
+
+// WithIVFFlatIndex is an option for building an IVFFlat index with the given
+// number of lists. A zero value uses 100 lists.
+func WithIVFFlatIndex(lists int) Option {
+	return func(p *Store) {
+		p.index = IVFFlat
+		p.ivfflatLists = lists
+	}
+}
+
+// WithSubjectKey is an option for setting the key in the metadata of the
+// documents that stores the id of the user or session the document belongs to.
+// The subject key is used by Purge. Defaults to "subject".
+func WithSubjectKey(subjectKey string) Option {
+	return func(p *Store) {
+		p.subjectKey = subjectKey
+	}
+}
+
+func applyClientOptions(opts ...Option) (Store, error) {
+	o := &Store{
+		collectionName:     _defaultCollectionName,
+		subjectKey:         _defaultSubjectKey,
+		distance:           Cosine,
+		maxOpenConns:       _defaultMaxOpenConns,
+		hnswM:              _defaultHNSWM,
+		hnswEfConstruction: _defaultHNSWEfConstruct,
+		ivfflatLists:       _defaultIVFFlatLists,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.embedder == nil {
+		return Store{}, fmt.Errorf("%w: missing embedder", ErrInvalidOptions)
+	}
+	if _, ok := distanceOperators[o.distance]; !ok {
+		return Store{}, fmt.Errorf("%w: unknown distance %q", ErrInvalidOptions, o.distance)
+	}
+	if o.index != NoIndex && o.dimensions <= 0 {
+		return Store{}, fmt.Errorf("%w: an index requires the vector dimensions", ErrInvalidOptions)
+	}
+	if o.hnswM <= 0 {
+		o.hnswM = _defaultHNSWM
+	}
+	if o.hnswEfConstruction <= 0 {
+		o.hnswEfConstruction = _defaultHNSWEfConstruct
+	}
+	if o.ivfflatLists <= 0 {
+		o.ivfflatLists = _defaultIVFFlatLists
+	}
+
+	if o.db == nil && o.connectionURL == "" {
+		o.connectionURL = os.Getenv(_pgvectorEnvVarName)
+		if o.connectionURL == "" {
+			return Store{}, fmt.Errorf(
+				"%w: missing connection url. Pass it as an option or set the %s environment variable",
+				ErrInvalidOptions,
+				_pgvectorEnvVarName,
+			)
+		}
+	}
+
+	return *o, nil
+}
@@ -0,0 +1,310 @@
+package pgvector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	_ "github.com/jackc/pgx/v5/stdlib" // postgresql driver
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+var (
+	// ErrEmbedderWrongNumberVectors is returned when if the embedder returns a number
+	// of vectors that is not equal to the number of documents given.
+	ErrEmbedderWrongNumberVectors = errors.New(
+		"number of vectors from embedder does not match number of documents",
+	)
+	// ErrUnsupportedFilter is returned when the filters given to SimilaritySearch
+	// are neither a map[string]any nor a Filter.
+	ErrUnsupportedFilter = errors.New("unsupported filter type")
+)
+
+// distanceOperators are the pgvector operators of each distance, with the
+// expression turning the distance into a score and the operator class of
+// indexes.
+// nolint:gochecknoglobals
+var distanceOperators = map[Distance]struct {
+	operator string
+	score    string
+	opclass  string
+}{
+	Cosine:       {"<=>", "1 - (%s)", "vector_cosine_ops"},
+	L2:           {"<->", "1 / (1 + (%s))", "vector_l2_ops"},
+	InnerProduct: {"<#>", "-(%s)", "vector_ip_ops"},
+}
+
+// Filter is a SQL boolean expression restricting the documents searched, see
+// Where.
+type Filter struct {
+	expr string
+	args []any
+}
+
+// Where returns a Filter from a SQL boolean expression on the columns of the
+// table: "document", "metadata" (jsonb) and "namespace". Arguments are
+// referenced with "?" placeholders, for example
+//
+//	pgvector.Where("(metadata->>'year')::int >= ?", 2020)
+//
+// The jsonb operators containing a question mark can not be used, use their
+// function form such as jsonb_exists instead.
+func Where(expr string, args ...any) Filter {
+	return Filter{expr: expr, args: args}
+}
+
+// Store is a wrapper around a PostgreSQL table with a pgvector column.
+type Store struct {
+	embedder embeddings.Embedder
+	db       *sql.DB
+
+	connectionURL      string
+	maxOpenConns       int
+	collectionName     string
+	subjectKey         string
+	dimensions         int
+	distance           Distance
+	index              IndexType
+	hnswM              int
+	hnswEfConstruction int
+	ivfflatLists       int
+}
+
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ schema.Purger            = Store{}
+)
+
+// New creates a new Store with options, creating the pgvector extension, the
+// table and its index if needed. The embedder must be set.
+func New(ctx context.Context, opts ...Option) (Store, error) {
+	s, err := applyClientOptions(opts...)
+	if err != nil {
+		return Store{}, err
+	}
+
+	if s.db == nil {
+		s.db, err = sql.Open("pgx", s.connectionURL)
+		if err != nil {
+			return Store{}, err
+		}
+		s.db.SetMaxOpenConns(s.maxOpenConns)
+	}
+
+	for _, stmt := range s.schemaStatements() {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return Store{}, err
+		}
+	}
+	return s, nil
+}
+
+// AddDocuments creates vector embeddings from the documents using the embedder
+// and inserts them in the table, in the name space of the options.
+func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	vectors, err := opts.Embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return ErrEmbedderWrongNumberVectors
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	stmt := fmt.Sprintf(
+		"INSERT INTO %s (namespace, document, metadata, embedding) VALUES ($1, $2, $3, $4)",
+		s.table(),
+	)
+	for i, doc := range docs {
+		metadata := doc.Metadata
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		metadataJSON, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, stmt, opts.NameSpace, doc.PageContent, string(metadataJSON),
+			vectorLiteral(vectors[i])); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SimilaritySearch creates a vector embedding from the query using the embedder
+// and returns the numDocuments nearest documents of the name space. Filters are
+// either a map[string]any the metadata of the documents must contain, or a
+// Filter. Documents with a score below the score threshold are left out.
+func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...vectorstores.Option) ([]schema.Document, error) { //nolint:lll
+	opts := s.getOptions(options...)
+
+	vector, err := opts.Embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	q, args, err := s.searchQuery(vectorLiteral(vector), numDocuments, opts)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docs := make([]schema.Document, 0, numDocuments)
+	for rows.Next() {
+		var (
+			doc          schema.Document
+			metadataJSON []byte
+			score        float64
+		)
+		if err := rows.Scan(&doc.PageContent, &metadataJSON, &score); err != nil {
+			return nil, err
+		}
+		if opts.ScoreThreshold != 0 && score < opts.ScoreThreshold {
+			continue
+		}
+		if err := json.Unmarshal(metadataJSON, &doc.Metadata); err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+// Purge deletes all the documents whose metadata value for the subject key
+// equals the subject id, in all name spaces.
+func (s Store) Purge(ctx context.Context, subjectID string) error {
+	if subjectID == "" {
+		return schema.ErrMissingSubjectID
+	}
+	_, err := s.db.ExecContext(ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE metadata->>$1 = $2", s.table()),
+		s.subjectKey, subjectID,
+	)
+	return err
+}
+
+// Close closes the database.
+func (s Store) Close() error {
+	return s.db.Close()
+}
+
+func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.Embedder == nil {
+		opts.Embedder = s.embedder
+	}
+	return opts
+}
+
+func (s Store) table() string {
+	return pgx.Identifier{s.collectionName}.Sanitize()
+}
+
+// schemaStatements returns the statements creating the extension, the table
+// and the index.
+func (s Store) schemaStatements() []string {
+	vectorType := "vector"
+	if s.dimensions > 0 {
+		vectorType = fmt.Sprintf("vector(%d)", s.dimensions)
+	}
+	stmts := []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id bigserial PRIMARY KEY,
+	namespace text NOT NULL DEFAULT '',
+	document text NOT NULL,
+	metadata jsonb NOT NULL DEFAULT '{}',
+	embedding %s NOT NULL
+)`, s.table(), vectorType),
+	}
+
+	index := pgx.Identifier{s.collectionName + "_embedding_idx"}.Sanitize()
+	opclass := distanceOperators[s.distance].opclass
+	switch s.index {
+	case HNSW:
+		stmts = append(stmts, fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s USING hnsw (embedding %s) WITH (m = %d, ef_construction = %d)",
+			index, s.table(), opclass, s.hnswM, s.hnswEfConstruction))
+	case IVFFlat:
+		stmts = append(stmts, fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s USING ivfflat (embedding %s) WITH (lists = %d)",
+			index, s.table(), opclass, s.ivfflatLists))
+	case NoIndex:
+	}
+	return stmts
+}
+
+// searchQuery returns the query searching the nearest documents of the vector.
+func (s Store) searchQuery(vector string, numDocuments int, opts vectorstores.Options) (string, []any, error) {
+	op := distanceOperators[s.distance]
+	args := []any{vector, opts.NameSpace, numDocuments}
+	where := "namespace = $2"
+
+	switch f := opts.Filters.(type) {
+	case nil:
+	case map[string]any:
+		filterJSON, err := json.Marshal(f)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, string(filterJSON))
+		where += fmt.Sprintf(" AND metadata @> $%d::jsonb", len(args))
+	case Filter:
+		expr := f.expr
+		for _, arg := range f.args {
+			args = append(args, arg)
+			expr = strings.Replace(expr, "?", "$"+strconv.Itoa(len(args)), 1)
+		}
+		where += " AND (" + expr + ")"
+	default:
+		return "", nil, ErrUnsupportedFilter
+	}
+
+	distance := "embedding " + op.operator + " $1::vector"
+	q := fmt.Sprintf(
+		"SELECT document, metadata, %s AS score FROM %s WHERE %s ORDER BY %s LIMIT $3",
+		fmt.Sprintf(op.score, distance), s.table(), where, distance,
+	)
+	return q, args, nil
+}
+
+// vectorLiteral formats the vector as a pgvector literal.
+func vectorLiteral(v []float64) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(x, 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package pgvector
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// fakeEmbedder embeds texts as the number of times they contain "a", "b" and
+// "c".
+type fakeEmbedder struct{}
+
+func (e fakeEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float64, error) {
+	vectors := make([][]float64, 0, len(texts))
+	for _, text := range texts {
+		v, _ := e.EmbedQuery(ctx, text)
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+func (fakeEmbedder) EmbedQuery(_ context.Context, text string) ([]float64, error) {
+	return []float64{
+		float64(strings.Count(text, "a")),
+		float64(strings.Count(text, "b")),
+		float64(strings.Count(text, "c")),
+	}, nil
+}
+
+func TestOptions(t *testing.T) {
+	t.Setenv(_pgvectorEnvVarName, "")
+
+	_, err := applyClientOptions(WithEmbedder(fakeEmbedder{}))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = applyClientOptions(WithConnectionURL("postgres://localhost"))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+	_, err = applyClientOptions(WithEmbedder(fakeEmbedder{}), WithConnectionURL("postgres://localhost"),
+		WithHNSWIndex(0, 0))
+	require.ErrorIs(t, err, ErrInvalidOptions)
+
+	s, err := applyClientOptions(WithEmbedder(fakeEmbedder{}), WithConnectionURL("postgres://localhost"),
+		WithVectorDimensions(3), WithHNSWIndex(0, 0), WithDistance(L2), WithCollectionName("docs"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+		`CREATE TABLE IF NOT EXISTS "docs" (
+	id bigserial PRIMARY KEY,
+	namespace text NOT NULL DEFAULT '',
+	document text NOT NULL,
+	metadata jsonb NOT NULL DEFAULT '{}',
+	embedding vector(3) NOT NULL
+)`,
+		`CREATE INDEX IF NOT EXISTS "docs_embedding_idx" ON "docs" USING hnsw (embedding vector_l2_ops) ` +
+			`WITH (m = 16, ef_construction = 64)`,
+	}, s.schemaStatements())
+}
+
+func TestSearchQuery(t *testing.T) {
+	t.Parallel()
+
+	s, err := applyClientOptions(WithEmbedder(fakeEmbedder{}), WithConnectionURL("postgres://localhost"))
+	require.NoError(t, err)
+
+	q, args, err := s.searchQuery("[1,0]", 4, vectorstores.Options{
+		NameSpace: "ns",
+		Filters:   Where("(metadata->>'year')::int >= ? AND metadata->>'lang' = ?", 2020, "en"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT document, metadata, 1 - (embedding <=> $1::vector) AS score `+
+		`FROM "langchain_pg_embedding" WHERE namespace = $2 `+
+		`AND ((metadata->>'year')::int >= $4 AND metadata->>'lang' = $5) `+
+		`ORDER BY embedding <=> $1::vector LIMIT $3`, q)
+	assert.Equal(t, []any{"[1,0]", "ns", 4, 2020, "en"}, args)
+
+	q, args, err = s.searchQuery("[1,0]", 4, vectorstores.Options{Filters: map[string]any{"lang": "en"}})
+	require.NoError(t, err)
+	assert.Contains(t, q, "AND metadata @> $4::jsonb")
+	assert.Equal(t, `{"lang":"en"}`, args[3])
+
+	_, _, err = s.searchQuery("[1,0]", 4, vectorstores.Options{Filters: "lang = 'en'"})
+	require.ErrorIs(t, err, ErrUnsupportedFilter)
+}
+
+func TestVectorLiteral(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "[]", vectorLiteral(nil))
+	assert.Equal(t, "[1,-0.5,0.1]", vectorLiteral([]float64{1, -0.5, 0.1}))
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	connectionURL := os.Getenv(_pgvectorEnvVarName)
+	if connectionURL == "" {
+		t.Skip("Must set PGVECTOR_CONNECTION_STRING to run test")
+	}
+
+	ctx := context.Background()
+	s, err := New(ctx,
+		WithEmbedder(fakeEmbedder{}),
+		WithConnectionURL(connectionURL),
+		WithCollectionName("test_"+strings.ReplaceAll(uuid.NewString(), "-", "")),
+		WithVectorDimensions(3),
+		WithHNSWIndex(0, 0),
+	)
+	require.NoError(t, err)
+	defer func() {
+		_, _ = s.db.ExecContext(ctx, "DROP TABLE "+s.table())
+		s.Close()
+	}()
+
+	err = s.AddDocuments(ctx, []schema.Document{
+		{PageContent: "aaa", Metadata: map[string]any{"letter": "a", "subject": "alice"}},
+		{PageContent: "aab", Metadata: map[string]any{"letter": "a", "subject": "bob"}},
+		{PageContent: "ccc", Metadata: map[string]any{"letter": "c"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(ctx, "a", 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "aaa", docs[0].PageContent)
+	assert.Equal(t, "alice", docs[0].Metadata["subject"])
+
+	docs, err = s.SimilaritySearch(ctx, "c", 3, vectorstores.WithFilters(map[string]any{"letter": "a"}))
+	require.NoError(t, err)
+	assert.Len(t, docs, 2)
+
+	require.NoError(t, s.Purge(ctx, "alice"))
+	docs, err = s.SimilaritySearch(ctx, "a", 3, vectorstores.WithFilters(Where("metadata->>'letter' = ?", "a")))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "aab", docs[0].PageContent)
+}
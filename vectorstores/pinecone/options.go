@@ -11,6 +11,7 @@ import (
 const (
 	_pineconeEnvVrName = "PINECONE_API_KEY"
 	_defaultTextKey    = "text"
+	_defaultSubjectKey = "subject"
 )
 
 // ErrInvalidOptions is returned when the options given are invalid.
@@ -66,6 +67,15 @@ func WithTextKey(textKey string) Option {
 	}
 }
 
+// WithSubjectKey is an option for setting the key in the metadata of the vectors
+// that stores the id of the user or session the document belongs to. The subject
+// key is used by Purge. Defaults to "subject".
+func WithSubjectKey(subjectKey string) Option {
+	return func(p *Store) {
+		p.subjectKey = subjectKey
+	}
+}
+
 // NameSpace is an option for setting the nameSpace to upsert and query the vectors
 // from. Must be set.
 func WithNameSpace(nameSpace string) Option {
@@ -83,7 +93,8 @@ func withGrpc() Option { // nolint: unused
 
 func applyClientOptions(opts ...Option) (Store, error) {
 	o := &Store{
-		textKey: _defaultTextKey,
+		textKey:    _defaultTextKey,
+		subjectKey: _defaultSubjectKey,
 	}
 
 	for _, opt := range opts {
@@ -37,11 +37,18 @@ type Store struct {
 	environment string
 	apiKey      string
 	textKey     string
+	subjectKey  string
 	nameSpace   string
 	useGRPC     bool
+
+	// endpoint overrides the url of the REST API, used in tests.
+	endpoint string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ schema.Purger            = Store{}
+)
 
 // New creates a new Store with options. Options for index name, environment, project name
 // and embedder must be set.
@@ -77,7 +84,7 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 		texts = append(texts, doc.PageContent)
 	}
 
-	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
+	vectors, err := embeddings.EmbedFloat32(ctx, s.embedder, texts)
 	if err != nil {
 		return err
 	}
@@ -118,7 +125,7 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, err
 	}
 
-	vector, err := s.embedder.EmbedQuery(ctx, query)
+	vector, err := embeddings.EmbedQueryFloat32(ctx, s.embedder, query)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +138,21 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		filters)
 }
 
+// Purge deletes all vectors in the name space of the store whose metadata value
+// for the subject key equals the subject id. Deletion by metadata filter is only
+// supported by the rest api, so it is used even if the store uses grpc.
+func (s Store) Purge(ctx context.Context, subjectID string) error {
+	if subjectID == "" {
+		return schema.ErrMissingSubjectID
+	}
+
+	filter := map[string]any{
+		s.subjectKey: map[string]any{"$eq": subjectID},
+	}
+
+	return s.restDeleteByFilter(ctx, filter, s.nameSpace)
+}
+
 // Close closes the grpc connection.
 func (s Store) Close() error {
 	return s.grpcConn.Close()
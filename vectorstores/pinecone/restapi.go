@@ -35,7 +35,7 @@ func (e APIError) Error() string {
 }
 
 type vector struct {
-	Values   []float64      `json:"values"`
+	Values   []float32      `json:"values"`
 	Metadata map[string]any `json:"metadata"`
 	ID       string         `json:"id"`
 }
@@ -47,7 +47,7 @@ type upsertPayload struct {
 
 func (s Store) restUpsert(
 	ctx context.Context,
-	vectors [][]float64,
+	vectors [][]float32,
 	metadatas []map[string]any,
 	nameSpace string,
 ) error {
@@ -68,7 +68,7 @@ func (s Store) restUpsert(
 	body, status, err := doRequest(
 		ctx,
 		payload,
-		getEndpoint(s.indexName, s.projectName, s.environment)+"/vectors/upsert",
+		s.getEndpoint()+"/vectors/upsert",
 		s.apiKey,
 		http.MethodPost,
 	)
@@ -105,7 +105,7 @@ type queriesResponse struct {
 type queryPayload struct {
 	IncludeValues   bool      `json:"includeValues"`
 	IncludeMetadata bool      `json:"includeMetadata"`
-	Vector          []float64 `json:"vector"`
+	Vector          []float32 `json:"vector"`
 	TopK            int       `json:"topK"`
 	Namespace       string    `json:"namespace"`
 	Filter          any       `json:"filter"`
@@ -113,7 +113,7 @@ type queryPayload struct {
 
 func (s Store) restQuery(
 	ctx context.Context,
-	vector []float64,
+	vector []float32,
 	numVectors int,
 	nameSpace string,
 	scoreThreshold float64,
@@ -131,7 +131,7 @@ func (s Store) restQuery(
 	body, statusCode, err := doRequest(
 		ctx,
 		payload,
-		getEndpoint(s.indexName, s.projectName, s.environment)+"/query",
+		s.getEndpoint()+"/query",
 		s.apiKey,
 		http.MethodPost,
 	)
@@ -180,6 +180,36 @@ func (s Store) restQuery(
 	return docs, nil
 }
 
+type deletePayload struct {
+	Filter    any    `json:"filter"`
+	Namespace string `json:"namespace"`
+}
+
+func (s Store) restDeleteByFilter(ctx context.Context, filter any, nameSpace string) error {
+	payload := deletePayload{
+		Filter:    filter,
+		Namespace: nameSpace,
+	}
+
+	body, status, err := doRequest(
+		ctx,
+		payload,
+		s.getEndpoint()+"/vectors/delete",
+		s.apiKey,
+		http.MethodPost,
+	)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if status == http.StatusOK {
+		return nil
+	}
+
+	return newAPIError("deleting vectors", body)
+}
+
 func doRequest(ctx context.Context, payload any, url, apiKey, method string) (io.ReadCloser, int, error) {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -203,6 +233,14 @@ func doRequest(ctx context.Context, payload any, url, apiKey, method string) (io
 	return r.Body, r.StatusCode, err
 }
 
+// getEndpoint returns the url of the REST API of the index.
+func (s Store) getEndpoint() string {
+	if s.endpoint != "" {
+		return s.endpoint
+	}
+	return getEndpoint(s.indexName, s.projectName, s.environment)
+}
+
 func getEndpoint(index, project, environment string) string {
 	urlString := url.QueryEscape(
 		fmt.Sprintf(
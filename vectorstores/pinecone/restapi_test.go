@@ -0,0 +1,56 @@
+package pinecone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestPurge(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path    string
+		apiKey  string
+		payload map[string]any
+	)
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		apiKey = r.Header.Get("Api-Key")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	s := Store{
+		apiKey:     "key",
+		subjectKey: _defaultSubjectKey,
+		nameSpace:  "ns",
+		endpoint:   srv.URL,
+	}
+
+	err := s.Purge(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "/vectors/delete", path)
+	assert.Equal(t, "key", apiKey)
+	assert.Equal(t, map[string]any{
+		"filter":    map[string]any{"subject": map[string]any{"$eq": "user-1"}},
+		"namespace": "ns",
+	}, payload)
+
+	status = http.StatusBadRequest
+	err = s.Purge(context.Background(), "user-1")
+	var apiErr APIError
+	assert.ErrorAs(t, err, &apiErr)
+
+	err = s.Purge(context.Background(), "")
+	assert.ErrorIs(t, err, schema.ErrMissingSubjectID)
+}
@@ -0,0 +1,36 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	// IDKey is the metadata key of the id of documents. Adding a document
+	// with the id of a document of a VersionedStore replaces it.
+	IDKey = "id"
+	// VersionKey is the metadata key of the version of the name space at
+	// which documents were added to a VersionedStore, set by the store.
+	VersionKey = "version"
+)
+
+// ErrUnknownVersion is returned when a version of a name space that does not
+// exist is queried or rolled back to.
+var ErrUnknownVersion = errors.New("unknown index version")
+
+// VersionedStore is a vector store keeping the history of its documents. Each
+// change of a name space creates a new version of it, starting at 1. Deleted
+// and replaced documents are kept as tombstones, so that searches can be
+// pinned to a version with WithVersion, and a bad re-index can be rolled back.
+type VersionedStore interface {
+	VectorStore
+	// DeleteDocuments soft-deletes the documents with the ids, creating a new
+	// version of the name space.
+	DeleteDocuments(ctx context.Context, ids []string, options ...Option) error
+	// Version returns the current version of the name space, 0 if it was
+	// never changed.
+	Version(ctx context.Context, options ...Option) (int, error)
+	// Rollback creates a new version of the name space with the documents it
+	// had at the version.
+	Rollback(ctx context.Context, version int, options ...Option) error
+}
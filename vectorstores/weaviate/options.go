@@ -13,6 +13,7 @@ import (
 const (
 	_defaultNameSpaceKey = "nameSpace"
 	_defaultTextKey      = "text"
+	_defaultSubjectKey   = "subject"
 	_defaultNameSpace    = "default"
 )
 
@@ -45,6 +46,15 @@ func WithNameSpaceKey(nameSpaceKey string) Option {
 	}
 }
 
+// WithSubjectKey is an option for setting the property key in the metadata to the
+// vectors in the index that stores the id of the user or session the document
+// belongs to. The subject key is used by Purge. Defaults to "subject".
+func WithSubjectKey(subjectKey string) Option {
+	return func(p *Store) {
+		p.subjectKey = subjectKey
+	}
+}
+
 // WithIndexName is an option for specifying the index name. Must be set.
 // The index name is the name of the class in weaviate.
 // Multiple words should be concatenated in CamelCase, e.g. ArticleAuthor.
@@ -109,6 +119,7 @@ func applyClientOptions(opts ...Option) (Store, error) {
 	o := &Store{
 		textKey:      _defaultTextKey,
 		nameSpaceKey: _defaultNameSpaceKey,
+		subjectKey:   _defaultSubjectKey,
 		nameSpace:    _defaultNameSpace,
 	}
 
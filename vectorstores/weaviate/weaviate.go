@@ -34,6 +34,9 @@ var (
 	ErrInvalidScoreThreshold = errors.New(
 		"score threshold must be between 0 and 1")
 	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrPurgeIncomplete is returned by Purge if some of the objects of the
+	// subject could not be deleted.
+	ErrPurgeIncomplete = errors.New("purge incomplete")
 )
 
 // Store is a wrapper around the weaviate client.
@@ -43,6 +46,7 @@ type Store struct {
 
 	textKey      string
 	nameSpaceKey string
+	subjectKey   string
 
 	indexName string
 	nameSpace string
@@ -60,7 +64,10 @@ type Store struct {
 	queryAttrs []string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore = Store{}
+	_ schema.Purger            = Store{}
+)
 
 // New creates a new Store with options.
 // When using weaviate,
@@ -94,7 +101,7 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 		texts = append(texts, doc.PageContent)
 	}
 
-	vectors, err := s.embedder.EmbedDocuments(ctx, texts)
+	vectors, err := embeddings.EmbedFloat32(ctx, s.embedder, texts)
 	if err != nil {
 		return err
 	}
@@ -120,7 +127,7 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document, options
 		objects = append(objects, &models.Object{
 			Class:      s.indexName,
 			ID:         strfmt.UUID(uuid.New().String()),
-			Vector:     convertVector(vectors[i]),
+			Vector:     vectors[i],
 			Properties: metadatas[i],
 		})
 	}
@@ -148,7 +155,7 @@ func (s Store) SimilaritySearch(
 		return nil, err
 	}
 
-	vector, err := s.embedder.EmbedQuery(ctx, query)
+	vector, err := embeddings.EmbedQueryFloat32(ctx, s.embedder, query)
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +164,7 @@ func (s Store) SimilaritySearch(
 		Get().
 		WithNearVector(s.client.GraphQL().
 			NearVectorArgBuilder().
-			WithVector(convertVector(vector)).
+			WithVector(vector).
 			WithCertainty(scoreThreshold),
 		).
 		WithWhere(whereBuilder).
@@ -170,6 +177,53 @@ func (s Store) SimilaritySearch(
 	return s.parseDocumentsByGraphQLResponse(res)
 }
 
+// Purge deletes all objects in the name space of the store whose value for the
+// subject key property equals the subject id. Weaviate caps the number of
+// objects deleted by a batch delete, so batches are deleted until no object
+// matches anymore. ErrPurgeIncomplete is returned if any object could not be
+// deleted.
+func (s Store) Purge(ctx context.Context, subjectID string) error {
+	if subjectID == "" {
+		return schema.ErrMissingSubjectID
+	}
+
+	for {
+		resp, err := s.client.Batch().ObjectsBatchDeleter().
+			WithClassName(s.indexName).
+			WithWhere(s.purgeFilter(subjectID)).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		if resp == nil || resp.Results == nil {
+			return ErrInvalidResponse
+		}
+
+		results := resp.Results
+		if results.Failed > 0 {
+			return fmt.Errorf("%w: %d of %d objects could not be deleted",
+				ErrPurgeIncomplete, results.Failed, results.Matches)
+		}
+		if results.Matches <= results.Limit {
+			return nil
+		}
+		// More objects match than can be deleted at once. Stop if the last
+		// batch made no progress, to not loop forever.
+		if results.Successful == 0 {
+			return fmt.Errorf("%w: %d objects left", ErrPurgeIncomplete, results.Matches)
+		}
+	}
+}
+
+// purgeFilter returns the filter matching the objects of the subject in the
+// name space of the store.
+func (s Store) purgeFilter(subjectID string) *filters.WhereBuilder {
+	return filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
+		filters.Where().WithPath([]string{s.nameSpaceKey}).WithOperator(filters.Equal).WithValueString(s.nameSpace),
+		filters.Where().WithPath([]string{s.subjectKey}).WithOperator(filters.Equal).WithValueString(subjectID),
+	})
+}
+
 func (s Store) parseDocumentsByGraphQLResponse(res *models.GraphQLResponse) ([]schema.Document, error) {
 	if len(res.Errors) > 0 {
 		messages := make([]string, 0, len(res.Errors))
@@ -267,11 +321,3 @@ func (s Store) createFields() []graphql.Field {
 	})
 	return fields
 }
-
-func convertVector(v []float64) []float32 {
-	v32 := make([]float32, len(v))
-	for i, f := range v {
-		v32[i] = float32(f)
-	}
-	return v32
-}
@@ -3,8 +3,11 @@ package weaviate
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/uuid"
@@ -537,3 +540,105 @@ func TestWeaviateAsRetrieverWithMetadataFilters(t *testing.T) {
 	require.NotContains(t, result, "orange", "expected not orange in result")
 	require.NotContains(t, result, "yellow", "expected not yellow in result")
 }
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float64, error) {
+	return make([][]float64, len(texts)), nil
+}
+
+func (fakeEmbedder) EmbedQuery(context.Context, string) ([]float64, error) {
+	return nil, nil
+}
+
+func TestPurgeFilter(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(
+		WithScheme("http"),
+		WithHost("localhost"),
+		WithIndexName("Test"),
+		WithEmbedder(fakeEmbedder{}),
+		WithNameSpace("ns"),
+	)
+	require.NoError(t, err)
+
+	where := s.purgeFilter("user-1").Build()
+	require.Equal(t, string(filters.And), where.Operator)
+	require.Len(t, where.Operands, 2)
+	require.Equal(t, []string{_defaultNameSpaceKey}, where.Operands[0].Path)
+	require.Equal(t, "ns", *where.Operands[0].ValueString) //nolint:staticcheck
+	require.Equal(t, []string{_defaultSubjectKey}, where.Operands[1].Path)
+	require.Equal(t, "user-1", *where.Operands[1].ValueString) //nolint:staticcheck
+}
+
+func TestPurge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		responses     []string
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			name:          "single batch",
+			responses:     []string{`{"results":{"matches":3,"limit":10,"successful":3}}`},
+			expectedCalls: 1,
+		},
+		{
+			name: "more matches than limit",
+			responses: []string{
+				`{"results":{"matches":15,"limit":10,"successful":10}}`,
+				`{"results":{"matches":5,"limit":10,"successful":5}}`,
+			},
+			expectedCalls: 2,
+		},
+		{
+			name:          "failed objects",
+			responses:     []string{`{"results":{"matches":3,"limit":10,"successful":2,"failed":1}}`},
+			expectedCalls: 1,
+			expectedErr:   ErrPurgeIncomplete,
+		},
+		{
+			name:          "no progress",
+			responses:     []string{`{"results":{"matches":15,"limit":10,"successful":0}}`},
+			expectedCalls: 1,
+			expectedErr:   ErrPurgeIncomplete,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				i := atomic.AddInt32(&calls, 1) - 1
+				_, _ = w.Write([]byte(tc.responses[int(i)%len(tc.responses)]))
+			}))
+			defer srv.Close()
+
+			s, err := New(
+				WithScheme("http"),
+				WithHost(strings.TrimPrefix(srv.URL, "http://")),
+				WithIndexName("Test"),
+				WithEmbedder(fakeEmbedder{}),
+			)
+			require.NoError(t, err)
+
+			err = s.Purge(context.Background(), "user-1")
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, int32(tc.expectedCalls), atomic.LoadInt32(&calls))
+		})
+	}
+}